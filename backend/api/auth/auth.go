@@ -3,9 +3,16 @@ package auth
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"log/slog"
+	"net"
 	"net/http"
+	"net/netip"
 	"strconv"
 	"strings"
 	"time"
@@ -13,15 +20,19 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 
 	"github.com/golang-jwt/jwt/v4"
 	errs "github.com/pkg/errors"
 
 	"github.com/bytebase/bytebase/backend/common"
+	"github.com/bytebase/bytebase/backend/common/log"
 	"github.com/bytebase/bytebase/backend/component/state"
 	enterprise "github.com/bytebase/bytebase/backend/enterprise/api"
+	api "github.com/bytebase/bytebase/backend/legacyapi"
 	"github.com/bytebase/bytebase/backend/store"
+	v1pb "github.com/bytebase/bytebase/proto/generated-go/v1"
 )
 
 const (
@@ -33,7 +44,18 @@ const (
 	AccessTokenAudienceFmt = "bb.user.access.%s"
 	// MFATempTokenAudienceFmt is the format of the MFA temp token audience.
 	MFATempTokenAudienceFmt = "bb.user.mfa-temp.%s"
-	apiTokenDuration        = 1 * time.Hour
+	// PasswordResetTokenAudienceFmt is the format of the audience of the token issued to the user whose password
+	// is expired, the token can only be used to update the password of the user.
+	PasswordResetTokenAudienceFmt = "bb.user.password-reset.%s"
+	// HardwareKeyEnrollmentTokenAudienceFmt is the format of the audience of the token issued to the user who is
+	// required to use a hardware security key but has none, the token can only be used to register a security key.
+	HardwareKeyEnrollmentTokenAudienceFmt = "bb.user.hardware-key-enrollment.%s"
+	apiTokenDuration                      = 1 * time.Hour
+	restrictedTokenDuration               = 15 * time.Minute
+	// updateUserMethod is the only method allowed for the password reset and the hardware key enrollment tokens.
+	updateUserMethod = "/bytebase.v1.AuthService/UpdateUser"
+	// accessTokenUsageInterval is the minimum interval to record the usage of a personal access token from the same IP.
+	accessTokenUsageInterval = 1 * time.Minute
 	// DefaultTokenDuration is the default token expiration duration.
 	DefaultTokenDuration = 7 * 24 * time.Hour
 
@@ -56,10 +78,11 @@ type APIAuthInterceptor struct {
 	licenseService enterprise.LicenseService
 	stateCfg       *state.State
 	mode           common.ReleaseMode
+	trustedProxies []netip.Prefix
 }
 
 // New returns a new API auth interceptor.
-func New(store *store.Store, secret string, tokenDuration time.Duration, licenseService enterprise.LicenseService, stateCfg *state.State, mode common.ReleaseMode) *APIAuthInterceptor {
+func New(store *store.Store, secret string, tokenDuration time.Duration, licenseService enterprise.LicenseService, stateCfg *state.State, mode common.ReleaseMode, trustedProxies []netip.Prefix) *APIAuthInterceptor {
 	return &APIAuthInterceptor{
 		store:          store,
 		secret:         secret,
@@ -67,6 +90,7 @@ func New(store *store.Store, secret string, tokenDuration time.Duration, license
 		licenseService: licenseService,
 		stateCfg:       stateCfg,
 		mode:           mode,
+		trustedProxies: trustedProxies,
 	}
 }
 
@@ -81,7 +105,7 @@ func (in *APIAuthInterceptor) AuthenticationInterceptor(ctx context.Context, req
 		return nil, status.Errorf(codes.Unauthenticated, err.Error())
 	}
 
-	principalID, err := in.authenticate(ctx, accessTokenStr)
+	principalID, err := in.authenticate(ctx, accessTokenStr, serverInfo.FullMethod, request)
 	if err != nil {
 		if IsAuthenticationAllowed(serverInfo.FullMethod) {
 			return handler(ctx, request)
@@ -106,7 +130,8 @@ func (in *APIAuthInterceptor) AuthenticationStreamInterceptor(request any, ss gr
 		return status.Errorf(codes.Unauthenticated, err.Error())
 	}
 
-	principalID, err := in.authenticate(ctx, accessTokenStr)
+	// The stream requests are not available before the handler, so only the tokens with all scopes are allowed.
+	principalID, err := in.authenticate(ctx, accessTokenStr, serverInfo.FullMethod, nil /* request */)
 	if err != nil {
 		if IsAuthenticationAllowed(serverInfo.FullMethod) {
 			return handler(request, ss)
@@ -129,10 +154,13 @@ func (s overrideStream) Context() context.Context {
 	return s.childCtx
 }
 
-func (in *APIAuthInterceptor) authenticate(ctx context.Context, accessTokenStr string) (int, error) {
+func (in *APIAuthInterceptor) authenticate(ctx context.Context, accessTokenStr string, fullMethod string, request any) (int, error) {
 	if accessTokenStr == "" {
 		return 0, status.Errorf(codes.Unauthenticated, "access token not found")
 	}
+	if strings.HasPrefix(accessTokenStr, api.PersonalAccessTokenPrefix) {
+		return in.authenticatePersonalAccessToken(ctx, accessTokenStr, fullMethod, request)
+	}
 	if _, ok := in.stateCfg.ExpireCache.Get(accessTokenStr); ok {
 		return 0, status.Errorf(codes.Unauthenticated, "access token expired")
 	}
@@ -154,7 +182,9 @@ func (in *APIAuthInterceptor) authenticate(ctx context.Context, accessTokenStr s
 		}
 		return 0, status.Errorf(codes.Unauthenticated, "failed to parse claim")
 	}
-	if !audienceContains(claims.Audience, fmt.Sprintf(AccessTokenAudienceFmt, in.mode)) {
+	passwordReset := audienceContains(claims.Audience, fmt.Sprintf(PasswordResetTokenAudienceFmt, in.mode))
+	hardwareKeyEnrollment := audienceContains(claims.Audience, fmt.Sprintf(HardwareKeyEnrollmentTokenAudienceFmt, in.mode))
+	if !passwordReset && !hardwareKeyEnrollment && !audienceContains(claims.Audience, fmt.Sprintf(AccessTokenAudienceFmt, in.mode)) {
 		return 0, status.Errorf(codes.Unauthenticated,
 			"invalid access token, audience mismatch, got %q, expected %q. you may send request to the wrong environment",
 			claims.Audience,
@@ -176,10 +206,110 @@ func (in *APIAuthInterceptor) authenticate(ctx context.Context, accessTokenStr s
 	if user.MemberDeleted {
 		return 0, status.Errorf(codes.Unauthenticated, "user ID %q has been deactivated by administrators", principalID)
 	}
+	if passwordReset && !isPasswordResetRequest(principalID, fullMethod, request) {
+		return 0, status.Errorf(codes.PermissionDenied, "password expired, please reset the password")
+	}
+	if hardwareKeyEnrollment && !isHardwareKeyEnrollmentRequest(principalID, fullMethod, request) {
+		return 0, status.Errorf(codes.PermissionDenied, "hardware security key is required for your role, please register a security key")
+	}
 
 	return principalID, nil
 }
 
+// isPasswordResetRequest returns true if the request updates the password of the user only.
+func isPasswordResetRequest(principalID int, fullMethod string, request any) bool {
+	if fullMethod != updateUserMethod {
+		return false
+	}
+	updateUser, ok := request.(*v1pb.UpdateUserRequest)
+	if !ok || updateUser.User == nil || updateUser.UpdateMask == nil {
+		return false
+	}
+	userID, err := common.GetUserID(updateUser.User.Name)
+	if err != nil || userID != principalID {
+		return false
+	}
+	paths := updateUser.UpdateMask.Paths
+	return len(paths) == 1 && paths[0] == "password"
+}
+
+// isHardwareKeyEnrollmentRequest returns true if the request only registers a WebAuthn credential of the user.
+func isHardwareKeyEnrollmentRequest(principalID int, fullMethod string, request any) bool {
+	if fullMethod != updateUserMethod {
+		return false
+	}
+	updateUser, ok := request.(*v1pb.UpdateUserRequest)
+	if !ok || updateUser.User == nil || updateUser.UpdateMask == nil {
+		return false
+	}
+	userID, err := common.GetUserID(updateUser.User.Name)
+	if err != nil || userID != principalID {
+		return false
+	}
+	if len(updateUser.UpdateMask.Paths) != 0 {
+		return false
+	}
+	return updateUser.RegenerateWebauthnChallenge || updateUser.WebauthnRegistration != nil
+}
+
+// authenticatePersonalAccessToken authenticates the personal access token and checks its scopes for the method.
+func (in *APIAuthInterceptor) authenticatePersonalAccessToken(ctx context.Context, accessTokenStr string, fullMethod string, request any) (int, error) {
+	tokenHash := HashPersonalAccessToken(accessTokenStr)
+	token, err := in.store.GetAccessToken(ctx, &store.FindAccessTokenMessage{TokenHash: &tokenHash})
+	if err != nil {
+		return 0, status.Errorf(codes.Internal, "failed to find access token, error: %v", err)
+	}
+	if token == nil {
+		return 0, status.Errorf(codes.Unauthenticated, "invalid access token")
+	}
+	now := time.Now()
+	if token.ExpireTs <= now.Unix() {
+		return 0, status.Errorf(codes.Unauthenticated, "access token expired")
+	}
+	user, err := in.store.GetUserByID(ctx, token.PrincipalUID)
+	if err != nil {
+		return 0, status.Errorf(codes.Unauthenticated, "failed to find user ID %q in the access token", token.PrincipalUID)
+	}
+	if user == nil {
+		return 0, status.Errorf(codes.Unauthenticated, "user ID %q not exists in the access token", token.PrincipalUID)
+	}
+	if user.MemberDeleted {
+		return 0, status.Errorf(codes.Unauthenticated, "user ID %q has been deactivated by administrators", token.PrincipalUID)
+	}
+	if err := in.checkAccessTokenScopes(ctx, token.Scopes, fullMethod, request); err != nil {
+		return 0, err
+	}
+
+	// Throttle the usage recording to avoid writing on every request.
+	clientIP := GetClientIP(ctx, in.trustedProxies)
+	if now.Unix()-token.LastUsedTs >= int64(accessTokenUsageInterval.Seconds()) || token.LastUsedIP != clientIP {
+		lastUsedTs := now.Unix()
+		if _, err := in.store.UpdateAccessToken(ctx, &store.UpdateAccessTokenMessage{
+			UID:        token.UID,
+			LastUsedTs: &lastUsedTs,
+			LastUsedIP: &clientIP,
+		}); err != nil {
+			slog.Warn("failed to record the access token usage", log.BBError(err))
+		}
+	}
+	return token.PrincipalUID, nil
+}
+
+// GeneratePersonalAccessToken generates a random personal access token.
+func GeneratePersonalAccessToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return api.PersonalAccessTokenPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// HashPersonalAccessToken returns the hash of the personal access token to store.
+func HashPersonalAccessToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
 // GetUserIDFromMFATempToken returns the user ID from the MFA temp token.
 func GetUserIDFromMFATempToken(token string, mode common.ReleaseMode, secret string) (int, error) {
 	claims := &claimsMessage{}
@@ -229,6 +359,92 @@ func GetTokenFromMetadata(md metadata.MD) (string, error) {
 	return accessToken, nil
 }
 
+// GetClientIP returns the client IP address of the request.
+// The X-Forwarded-For header is set freely by the clients, so it is only trusted if the request comes from the
+// grpc-gateway on the loopback address or the trusted proxies. The gateway appends the remote address of the HTTP
+// request to the header, and the client IP is the right-most address which is not a trusted proxy.
+func GetClientIP(ctx context.Context, trustedProxies []netip.Prefix) string {
+	var peerIP string
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		peerIP = p.Addr.String()
+		if host, _, err := net.SplitHostPort(peerIP); err == nil {
+			peerIP = host
+		}
+	}
+	peerAddr, err := netip.ParseAddr(peerIP)
+	if err != nil || !(peerAddr.IsLoopback() || isTrustedProxy(peerAddr, trustedProxies)) {
+		return peerIP
+	}
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return peerIP
+	}
+	var hops []string
+	for _, v := range md.Get("x-forwarded-for") {
+		hops = append(hops, strings.Split(v, ",")...)
+	}
+	clientIP := peerIP
+	for i := len(hops) - 1; i >= 0; i-- {
+		addr, err := parseHop(hops[i])
+		if err != nil {
+			// The hops on the left of the malformed one are not trustworthy.
+			return clientIP
+		}
+		clientIP = addr.String()
+		if !isTrustedProxy(addr, trustedProxies) {
+			return clientIP
+		}
+	}
+	return clientIP
+}
+
+// ParseTrustedProxies parses the trusted proxies in the form of IP address or CIDR, e.g. "10.0.0.1" and "10.0.0.0/8".
+func ParseTrustedProxies(values []string) ([]netip.Prefix, error) {
+	var prefixes []netip.Prefix
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		if strings.Contains(value, "/") {
+			prefix, err := netip.ParsePrefix(value)
+			if err != nil {
+				return nil, errs.Wrapf(err, "invalid trusted proxy %q", value)
+			}
+			prefixes = append(prefixes, prefix.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(value)
+		if err != nil {
+			return nil, errs.Wrapf(err, "invalid trusted proxy %q", value)
+		}
+		prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+	return prefixes, nil
+}
+
+func parseHop(hop string) (netip.Addr, error) {
+	hop = strings.TrimSpace(hop)
+	if host, _, err := net.SplitHostPort(hop); err == nil {
+		hop = host
+	}
+	addr, err := netip.ParseAddr(hop)
+	if err != nil {
+		return netip.Addr{}, err
+	}
+	return addr.Unmap(), nil
+}
+
+func isTrustedProxy(addr netip.Addr, trustedProxies []netip.Prefix) bool {
+	addr = addr.Unmap()
+	for _, prefix := range trustedProxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
 func audienceContains(audience jwt.ClaimStrings, token string) bool {
 	for _, v := range audience {
 		if v == token {
@@ -261,6 +477,20 @@ func GenerateMFATempToken(userName string, userID int, mode common.ReleaseMode,
 	return generateToken(userName, userID, fmt.Sprintf(MFATempTokenAudienceFmt, mode), expirationTime, []byte(secret))
 }
 
+// GeneratePasswordResetToken generates a short-lived token for the user whose password is expired,
+// which can only be used to update the password.
+func GeneratePasswordResetToken(userName string, userID int, mode common.ReleaseMode, secret string) (string, error) {
+	expirationTime := time.Now().Add(restrictedTokenDuration)
+	return generateToken(userName, userID, fmt.Sprintf(PasswordResetTokenAudienceFmt, mode), expirationTime, []byte(secret))
+}
+
+// GenerateHardwareKeyEnrollmentToken generates a short-lived token for the user who is required to use a hardware
+// security key but has none, which can only be used to register a security key.
+func GenerateHardwareKeyEnrollmentToken(userName string, userID int, mode common.ReleaseMode, secret string) (string, error) {
+	expirationTime := time.Now().Add(restrictedTokenDuration)
+	return generateToken(userName, userID, fmt.Sprintf(HardwareKeyEnrollmentTokenAudienceFmt, mode), expirationTime, []byte(secret))
+}
+
 // Pay attention to this function. It holds the main JWT token generation logic.
 func generateToken(userName string, userID int, aud string, expirationTime time.Time, secret []byte) (string, error) {
 	// Create the JWT claims, which includes the username and expiry time.
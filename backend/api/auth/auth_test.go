@@ -0,0 +1,217 @@
+package auth
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+
+	v1pb "github.com/bytebase/bytebase/proto/generated-go/v1"
+)
+
+func TestGetClientIP(t *testing.T) {
+	trustedProxies, err := ParseTrustedProxies([]string{"10.0.0.0/8", "192.168.1.1"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		peer           string
+		xForwardedFor  []string
+		trustedProxies bool
+		want           string
+	}{
+		{
+			name: "direct request",
+			peer: "203.0.113.7:51234",
+			want: "203.0.113.7",
+		},
+		{
+			name:          "forged header from untrusted peer",
+			peer:          "203.0.113.7:51234",
+			xForwardedFor: []string{"198.51.100.1"},
+			want:          "203.0.113.7",
+		},
+		{
+			name:          "gateway without proxies",
+			peer:          "127.0.0.1:40000",
+			xForwardedFor: []string{"203.0.113.7"},
+			want:          "203.0.113.7",
+		},
+		{
+			name: "client rotates the header through the gateway",
+			peer: "127.0.0.1:40000",
+			// The gateway appends the remote address of the HTTP request.
+			xForwardedFor: []string{"198.51.100.1, 203.0.113.7"},
+			want:          "203.0.113.7",
+		},
+		{
+			name:           "behind trusted proxies",
+			peer:           "127.0.0.1:40000",
+			xForwardedFor:  []string{"198.51.100.1, 203.0.113.7, 10.1.2.3", "192.168.1.1"},
+			trustedProxies: true,
+			want:           "203.0.113.7",
+		},
+		{
+			name:           "direct request from trusted proxy",
+			peer:           "10.1.2.3:443",
+			xForwardedFor:  []string{"203.0.113.7"},
+			trustedProxies: true,
+			want:           "203.0.113.7",
+		},
+		{
+			name:           "malformed hop",
+			peer:           "127.0.0.1:40000",
+			xForwardedFor:  []string{"unknown, 10.1.2.3"},
+			trustedProxies: true,
+			want:           "10.1.2.3",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			addr, err := net.ResolveTCPAddr("tcp", test.peer)
+			require.NoError(t, err)
+			ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: addr})
+			if len(test.xForwardedFor) > 0 {
+				ctx = metadata.NewIncomingContext(ctx, metadata.MD{"x-forwarded-for": test.xForwardedFor})
+			}
+			var proxies = trustedProxies
+			if !test.trustedProxies {
+				proxies = nil
+			}
+			require.Equal(t, test.want, GetClientIP(ctx, proxies))
+		})
+	}
+}
+
+func TestParseTrustedProxies(t *testing.T) {
+	prefixes, err := ParseTrustedProxies([]string{"10.1.2.3/8", " ::1 ", ""})
+	require.NoError(t, err)
+	require.Len(t, prefixes, 2)
+	require.Equal(t, "10.0.0.0/8", prefixes[0].String())
+	require.Equal(t, "::1/128", prefixes[1].String())
+
+	_, err = ParseTrustedProxies([]string{"10.0.0.256"})
+	require.Error(t, err)
+}
+
+func TestIsPasswordResetRequest(t *testing.T) {
+	tests := []struct {
+		name       string
+		fullMethod string
+		request    any
+		want       bool
+	}{
+		{
+			name:       "update password",
+			fullMethod: updateUserMethod,
+			request: &v1pb.UpdateUserRequest{
+				User:       &v1pb.User{Name: "users/101", Password: "new"},
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"password"}},
+			},
+			want: true,
+		},
+		{
+			name:       "update other fields",
+			fullMethod: updateUserMethod,
+			request: &v1pb.UpdateUserRequest{
+				User:       &v1pb.User{Name: "users/101", Password: "new"},
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"password", "role"}},
+			},
+			want: false,
+		},
+		{
+			name:       "update other user",
+			fullMethod: updateUserMethod,
+			request: &v1pb.UpdateUserRequest{
+				User:       &v1pb.User{Name: "users/102", Password: "new"},
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"password"}},
+			},
+			want: false,
+		},
+		{
+			name:       "no update mask",
+			fullMethod: updateUserMethod,
+			request:    &v1pb.UpdateUserRequest{User: &v1pb.User{Name: "users/101"}},
+			want:       false,
+		},
+		{
+			name:       "other method",
+			fullMethod: "/bytebase.v1.AuthService/GetUser",
+			request:    &v1pb.GetUserRequest{Name: "users/101"},
+			want:       false,
+		},
+	}
+
+	for _, test := range tests {
+		require.Equal(t, test.want, isPasswordResetRequest(101, test.fullMethod, test.request), test.name)
+	}
+}
+
+func TestIsHardwareKeyEnrollmentRequest(t *testing.T) {
+	registration := "{}"
+	tests := []struct {
+		name       string
+		fullMethod string
+		request    any
+		want       bool
+	}{
+		{
+			name:       "regenerate challenge",
+			fullMethod: updateUserMethod,
+			request: &v1pb.UpdateUserRequest{
+				User:                        &v1pb.User{Name: "users/101"},
+				UpdateMask:                  &fieldmaskpb.FieldMask{},
+				RegenerateWebauthnChallenge: true,
+			},
+			want: true,
+		},
+		{
+			name:       "register credential",
+			fullMethod: updateUserMethod,
+			request: &v1pb.UpdateUserRequest{
+				User:                 &v1pb.User{Name: "users/101"},
+				UpdateMask:           &fieldmaskpb.FieldMask{},
+				WebauthnRegistration: &registration,
+			},
+			want: true,
+		},
+		{
+			name:       "register credential and update other fields",
+			fullMethod: updateUserMethod,
+			request: &v1pb.UpdateUserRequest{
+				User:                 &v1pb.User{Name: "users/101", Title: "title"},
+				UpdateMask:           &fieldmaskpb.FieldMask{Paths: []string{"title"}},
+				WebauthnRegistration: &registration,
+			},
+			want: false,
+		},
+		{
+			name:       "register credential of other user",
+			fullMethod: updateUserMethod,
+			request: &v1pb.UpdateUserRequest{
+				User:                 &v1pb.User{Name: "users/102"},
+				UpdateMask:           &fieldmaskpb.FieldMask{},
+				WebauthnRegistration: &registration,
+			},
+			want: false,
+		},
+		{
+			name:       "no registration",
+			fullMethod: updateUserMethod,
+			request: &v1pb.UpdateUserRequest{
+				User:       &v1pb.User{Name: "users/101"},
+				UpdateMask: &fieldmaskpb.FieldMask{},
+			},
+			want: false,
+		},
+	}
+
+	for _, test := range tests {
+		require.Equal(t, test.want, isHardwareKeyEnrollmentRequest(101, test.fullMethod, test.request), test.name)
+	}
+}
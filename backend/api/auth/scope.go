@@ -0,0 +1,225 @@
+package auth
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/bytebase/bytebase/backend/common"
+	"github.com/bytebase/bytebase/backend/store"
+	v1pb "github.com/bytebase/bytebase/proto/generated-go/v1"
+)
+
+// The scopes of the personal access tokens.
+// A scope can be restricted to a project by the ":projects/{project}" suffix, e.g. "issue.create:projects/hr".
+const (
+	// ScopeAll allows all methods that the user can access.
+	ScopeAll = "all"
+	// ScopeRead allows the read-only methods in readMethods.
+	ScopeRead = "read"
+	// ScopeSQLQuery allows running read-only queries and exporting the query results.
+	ScopeSQLQuery = "sql.query"
+	// ScopeIssueCreate allows creating issues along with their sheets and plans.
+	ScopeIssueCreate = "issue.create"
+
+	apiPackagePrefix = "/bytebase.v1."
+)
+
+// readMethods are the methods allowed by ScopeRead. The new methods are not allowed until added here.
+// The read methods exposing the secrets or the credentials, e.g. ListSecrets and GetSetting, are excluded,
+// as well as the ones calling the external systems, e.g. SearchExternalVersionControlProjects.
+var readMethods = map[string]bool{
+	"ActuatorService/GetActuatorInfo":                           true,
+	"AnomalyService/SearchAnomalies":                            true,
+	"AuthService/GetUser":                                       true,
+	"AuthService/ListApprovalDelegations":                       true,
+	"AuthService/ListUsers":                                     true,
+	"BookmarkService/ListBookmarks":                             true,
+	"ChangelistService/GetChangelist":                           true,
+	"ChangelistService/ListChangelists":                         true,
+	"DatabaseService/GetBackupSetting":                          true,
+	"DatabaseService/GetChangeHistory":                          true,
+	"DatabaseService/GetDatabase":                               true,
+	"DatabaseService/GetDatabaseMetadata":                       true,
+	"DatabaseService/GetDatabaseSchema":                         true,
+	"DatabaseService/ListBackups":                               true,
+	"DatabaseService/ListChangeHistories":                       true,
+	"DatabaseService/ListDatabaseGrants":                        true,
+	"DatabaseService/ListDatabases":                             true,
+	"DatabaseService/ListSlowQueries":                           true,
+	"DatabaseService/SearchDatabases":                           true,
+	"EnvironmentService/GetEnvironment":                         true,
+	"EnvironmentService/ListEnvironments":                       true,
+	"ExternalVersionControlService/GetExternalVersionControl":   true,
+	"ExternalVersionControlService/ListExternalVersionControls": true,
+	"ExternalVersionControlService/ListProjectGitOpsInfo":       true,
+	"InboxService/GetInboxSummary":                              true,
+	"InboxService/ListInbox":                                    true,
+	"InstanceRoleService/GetInstanceRole":                       true,
+	"InstanceRoleService/ListInstanceRoles":                     true,
+	"InstanceService/GetInstance":                               true,
+	"InstanceService/ListInstances":                             true,
+	"IssueService/GetIssue":                                     true,
+	"IssueService/ListIssues":                                   true,
+	"IssueService/SearchIssues":                                 true,
+	"LoggingService/GetLog":                                     true,
+	"LoggingService/ListLogs":                                   true,
+	"OrgPolicyService/GetPolicy":                                true,
+	"OrgPolicyService/ListPolicies":                             true,
+	"ProjectService/GetDatabaseGroup":                           true,
+	"ProjectService/GetDeploymentConfig":                        true,
+	"ProjectService/GetIamPolicy":                               true,
+	"ProjectService/GetProject":                                 true,
+	"ProjectService/GetProjectGitOpsInfo":                       true,
+	"ProjectService/GetProjectProtectionRules":                  true,
+	"ProjectService/GetSchemaGroup":                             true,
+	"ProjectService/ListDatabaseGroups":                         true,
+	"ProjectService/ListProjects":                               true,
+	"ProjectService/ListSchemaGroups":                           true,
+	"ProjectService/ListWebhookDeliveries":                      true,
+	"ProjectService/SearchProjects":                             true,
+	"RiskService/ListRisks":                                     true,
+	"RoleService/ListRoles":                                     true,
+	"RolloutService/GetPlan":                                    true,
+	"RolloutService/GetRollout":                                 true,
+	"RolloutService/ListPlanCheckRuns":                          true,
+	"RolloutService/ListPlans":                                  true,
+	"RolloutService/ListTaskRuns":                               true,
+	"SchemaDesignService/GetSchemaDesign":                       true,
+	"SchemaDesignService/ListSchemaDesigns":                     true,
+	"SheetService/GetSheet":                                     true,
+	"SheetService/SearchSheets":                                 true,
+	"SubscriptionService/GetFeatureMatrix":                      true,
+	"SubscriptionService/GetSubscription":                       true,
+}
+
+var scopeMethods = map[string]map[string]bool{
+	ScopeRead: readMethods,
+	ScopeSQLQuery: {
+		"SQLService/Pretty": true,
+		"SQLService/Query":  true,
+		"SQLService/Export": true,
+	},
+	ScopeIssueCreate: {
+		"SheetService/CreateSheet":  true,
+		"RolloutService/CreatePlan": true,
+		"IssueService/CreateIssue":  true,
+	},
+}
+
+// ValidateScope validates the scope of the personal access token.
+func ValidateScope(scope string) error {
+	name, project, hasProject := strings.Cut(scope, ":")
+	switch name {
+	case ScopeAll, ScopeRead, ScopeSQLQuery, ScopeIssueCreate:
+	default:
+		return errors.Errorf("invalid scope %q", scope)
+	}
+	if hasProject {
+		if _, err := common.GetProjectID(project); err != nil {
+			return errors.Wrapf(err, "invalid project in scope %q", scope)
+		}
+	}
+	return nil
+}
+
+// isMethodInScope returns true if the method can be called with the scope without the project restriction.
+// The stream methods can only be called with the ScopeAll.
+func isMethodInScope(scope string, fullMethod string) bool {
+	if scope == ScopeAll {
+		return true
+	}
+	if !strings.HasPrefix(fullMethod, apiPackagePrefix) {
+		return false
+	}
+	return scopeMethods[scope][strings.TrimPrefix(fullMethod, apiPackagePrefix)]
+}
+
+// checkAccessTokenScopes returns an error unless one of the scopes allows calling the method with the request.
+// The request is nil for the stream methods.
+func (in *APIAuthInterceptor) checkAccessTokenScopes(ctx context.Context, scopes []string, fullMethod string, request any) error {
+	for _, scope := range scopes {
+		name, project, hasProject := strings.Cut(scope, ":")
+		if !isMethodInScope(name, fullMethod) {
+			continue
+		}
+		if !hasProject {
+			return nil
+		}
+		if request == nil {
+			continue
+		}
+		projectID, err := in.getRequestProjectID(ctx, request)
+		if err != nil {
+			return err
+		}
+		// The requests without a project-scoped resource are denied for the project restricted scopes.
+		if projectID != "" && common.FormatProject(projectID) == project {
+			return nil
+		}
+	}
+	return status.Errorf(codes.PermissionDenied, "the access token does not have the scope for method %q", fullMethod)
+}
+
+// getRequestProjectID returns the project of the resource in the parent or name field of the request,
+// or empty if the resource does not belong to a project.
+func (in *APIAuthInterceptor) getRequestProjectID(ctx context.Context, request any) (string, error) {
+	var resource, databaseName string
+	switch r := request.(type) {
+	case *v1pb.QueryRequest:
+		resource, databaseName = r.Name, r.ConnectionDatabase
+	case *v1pb.ExportRequest:
+		resource, databaseName = r.Name, r.ConnectionDatabase
+	default:
+		message, ok := request.(proto.Message)
+		if !ok {
+			return "", nil
+		}
+		fields := message.ProtoReflect().Descriptor().Fields()
+		for _, name := range []protoreflect.Name{"parent", "name"} {
+			if field := fields.ByName(name); field != nil && field.Kind() == protoreflect.StringKind && !field.IsList() {
+				resource = message.ProtoReflect().Get(field).String()
+				break
+			}
+		}
+	}
+
+	parts := strings.Split(resource, "/")
+	if len(parts) >= 2 && parts[0]+"/" == common.ProjectNamePrefix {
+		return parts[1], nil
+	}
+	if len(parts) < 2 || parts[0]+"/" != common.InstanceNamePrefix {
+		return "", nil
+	}
+	if len(parts) >= 4 && parts[2]+"/" == common.DatabaseIDPrefix {
+		databaseName = parts[3]
+	}
+	if databaseName == "" {
+		return "", nil
+	}
+	instanceID := parts[1]
+	instance, err := in.store.GetInstanceV2(ctx, &store.FindInstanceMessage{ResourceID: &instanceID})
+	if err != nil {
+		return "", status.Errorf(codes.Internal, "failed to get instance %q, error: %v", instanceID, err)
+	}
+	if instance == nil {
+		return "", nil
+	}
+	database, err := in.store.GetDatabaseV2(ctx, &store.FindDatabaseMessage{
+		InstanceID:          &instanceID,
+		DatabaseName:        &databaseName,
+		IgnoreCaseSensitive: store.IgnoreDatabaseAndTableCaseSensitive(instance),
+	})
+	if err != nil {
+		return "", status.Errorf(codes.Internal, "failed to get database %q, error: %v", databaseName, err)
+	}
+	if database == nil {
+		return "", nil
+	}
+	return database.ProjectID, nil
+}
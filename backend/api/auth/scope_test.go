@@ -0,0 +1,145 @@
+package auth
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	v1pb "github.com/bytebase/bytebase/proto/generated-go/v1"
+)
+
+func TestIsMethodInScope(t *testing.T) {
+	tests := []struct {
+		scope      string
+		fullMethod string
+		want       bool
+	}{
+		{ScopeAll, "/bytebase.v1.ProjectService/DeleteProject", true},
+		{ScopeAll, "/bytebase.v1.SQLService/AdminExecute", true},
+		{ScopeRead, "/bytebase.v1.ProjectService/GetProject", true},
+		{ScopeRead, "/bytebase.v1.IssueService/SearchIssues", true},
+		{ScopeRead, "/bytebase.v1.ProjectService/DeleteProject", false},
+		// The read methods exposing the secrets are not allowed.
+		{ScopeRead, "/bytebase.v1.DatabaseService/ListSecrets", false},
+		{ScopeRead, "/bytebase.v1.SettingService/GetSetting", false},
+		{ScopeRead, "/bytebase.v1.AuthService/ListAccessTokens", false},
+		// The methods outside the API package are not allowed.
+		{ScopeRead, "/grpc.health.v1.Health/GetStatus", false},
+		{ScopeSQLQuery, "/bytebase.v1.SQLService/Query", true},
+		{ScopeSQLQuery, "/bytebase.v1.SQLService/Execute", false},
+		{ScopeSQLQuery, "/bytebase.v1.SQLService/AdminExecute", false},
+		{ScopeIssueCreate, "/bytebase.v1.IssueService/CreateIssue", true},
+		{ScopeIssueCreate, "/bytebase.v1.IssueService/GetIssue", false},
+		{"unknown", "/bytebase.v1.ProjectService/GetProject", false},
+	}
+
+	a := require.New(t)
+	for _, test := range tests {
+		a.Equal(test.want, isMethodInScope(test.scope, test.fullMethod), "%s %s", test.scope, test.fullMethod)
+	}
+}
+
+func TestCheckAccessTokenScopes(t *testing.T) {
+	tests := []struct {
+		name       string
+		scopes     []string
+		fullMethod string
+		request    any
+		want       bool
+	}{
+		{
+			name:       "read in project",
+			scopes:     []string{"read:projects/hr"},
+			fullMethod: "/bytebase.v1.ProjectService/GetProject",
+			request:    &v1pb.GetProjectRequest{Name: "projects/hr"},
+			want:       true,
+		},
+		{
+			name:       "read in other project",
+			scopes:     []string{"read:projects/other"},
+			fullMethod: "/bytebase.v1.ProjectService/GetProject",
+			request:    &v1pb.GetProjectRequest{Name: "projects/hr"},
+			want:       false,
+		},
+		{
+			name:       "method not in scope",
+			scopes:     []string{"read:projects/hr"},
+			fullMethod: "/bytebase.v1.ProjectService/DeleteProject",
+			request:    &v1pb.DeleteProjectRequest{Name: "projects/hr"},
+			want:       false,
+		},
+		{
+			name:       "create issue in project",
+			scopes:     []string{"issue.create:projects/hr"},
+			fullMethod: "/bytebase.v1.IssueService/CreateIssue",
+			request:    &v1pb.CreateIssueRequest{Parent: "projects/hr"},
+			want:       true,
+		},
+		{
+			name:       "request without project in restricted scope",
+			scopes:     []string{"read:projects/hr"},
+			fullMethod: "/bytebase.v1.ProjectService/ListProjects",
+			request:    &v1pb.ListProjectsRequest{},
+			want:       false,
+		},
+		{
+			name:       "request without project in unrestricted scope",
+			scopes:     []string{"read"},
+			fullMethod: "/bytebase.v1.ProjectService/ListProjects",
+			request:    &v1pb.ListProjectsRequest{},
+			want:       true,
+		},
+		{
+			name:       "query without database in restricted scope",
+			scopes:     []string{"sql.query:projects/hr"},
+			fullMethod: "/bytebase.v1.SQLService/Query",
+			request:    &v1pb.QueryRequest{Name: "instances/x"},
+			want:       false,
+		},
+		{
+			name:       "stream in restricted scope",
+			scopes:     []string{"all:projects/hr"},
+			fullMethod: "/bytebase.v1.SQLService/AdminExecute",
+			want:       false,
+		},
+		{
+			name:       "stream in all scope",
+			scopes:     []string{"all"},
+			fullMethod: "/bytebase.v1.SQLService/AdminExecute",
+			want:       true,
+		},
+		{
+			name:       "stream in read scope",
+			scopes:     []string{"read"},
+			fullMethod: "/bytebase.v1.SQLService/AdminExecute",
+			want:       false,
+		},
+		{
+			name:       "one of the scopes matches",
+			scopes:     []string{"read:projects/other", "issue.create", "read:projects/hr"},
+			fullMethod: "/bytebase.v1.ProjectService/GetProject",
+			request:    &v1pb.GetProjectRequest{Name: "projects/hr"},
+			want:       true,
+		},
+		{
+			name:       "no scope",
+			fullMethod: "/bytebase.v1.ProjectService/GetProject",
+			request:    &v1pb.GetProjectRequest{Name: "projects/hr"},
+			want:       false,
+		},
+	}
+
+	a := require.New(t)
+	in := &APIAuthInterceptor{}
+	for _, test := range tests {
+		err := in.checkAccessTokenScopes(context.Background(), test.scopes, test.fullMethod, test.request)
+		if test.want {
+			a.NoError(err, test.name)
+		} else {
+			a.Equal(codes.PermissionDenied, status.Code(err), test.name)
+		}
+	}
+}
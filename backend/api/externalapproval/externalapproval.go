@@ -0,0 +1,89 @@
+// Package externalapproval is the package for the callbacks of the external approval systems.
+package externalapproval
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/backend/common/log"
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/runner/relay"
+	"github.com/bytebase/bytebase/backend/store"
+	"github.com/bytebase/bytebase/backend/utils"
+)
+
+// Service is the API endpoint for handling the callbacks of the external approval systems.
+type Service struct {
+	store       *store.Store
+	relayRunner *relay.Runner
+}
+
+// NewService creates an external approval service.
+func NewService(store *store.Store, relayRunner *relay.Runner) *Service {
+	return &Service{
+		store:       store,
+		relayRunner: relayRunner,
+	}
+}
+
+// RegisterWebhookRoutes registers the callback routes for the external approval nodes.
+func (s *Service) RegisterWebhookRoutes(g *echo.Group) {
+	g.POST("/external-approval/:id", func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		body, err := io.ReadAll(c.Request().Body)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Failed to read callback request").SetInternal(err)
+		}
+		nodeID := c.Param("id")
+		node, err := utils.GetExternalApprovalNode(ctx, s.store, nodeID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get external approval node").SetInternal(err)
+		}
+		if node == nil {
+			return echo.NewHTTPError(http.StatusNotFound, "External approval node not found")
+		}
+		provider, err := s.relayRunner.NewProvider(node)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create external approval provider").SetInternal(err)
+		}
+		changeRequest, err := provider.ParseCallback(c.Request().Header, body)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusUnauthorized, "Invalid callback request").SetInternal(err)
+		}
+
+		externalApprovalType := api.ExternalApprovalTypeRelay
+		approvals, err := s.store.ListExternalApprovalV2(ctx, &store.ListExternalApprovalMessage{
+			Type: &externalApprovalType,
+		})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to list external approvals").SetInternal(err)
+		}
+		for _, approval := range approvals {
+			payload := &api.ExternalApprovalPayloadRelay{}
+			if err := json.Unmarshal([]byte(approval.Payload), payload); err != nil {
+				slog.Error("failed to unmarshal external approval payload", slog.Int("id", approval.ID), log.BBError(err))
+				continue
+			}
+			if payload.ExternalApprovalNodeID != node.Id || payload.ID != changeRequest.ID {
+				continue
+			}
+			msg := relay.CheckExternalApprovalChanMessage{
+				ExternalApproval: approval,
+				ChangeRequest:    changeRequest,
+				ErrChan:          make(chan error, 1),
+			}
+			s.relayRunner.CheckExternalApprovalChan <- msg
+			if err := <-msg.ErrChan; err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, "Failed to apply external approval status").SetInternal(errors.Wrapf(err, "issueUID %d", approval.IssueUID))
+			}
+		}
+		// Respond OK even if no pending external approval matches, e.g. the issue is closed, so that the external system stops retrying.
+		return c.String(http.StatusOK, "OK")
+	})
+}
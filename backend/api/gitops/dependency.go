@@ -0,0 +1,337 @@
+package gitops
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/backend/common"
+	"github.com/bytebase/bytebase/backend/common/log"
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/plugin/db"
+	"github.com/bytebase/bytebase/backend/plugin/vcs"
+	"github.com/bytebase/bytebase/backend/store"
+	"github.com/bytebase/bytebase/backend/store/model"
+)
+
+const (
+	// frontMatterPrefix is the prefix of the front-matter comment in the migration file, e.g.
+	//
+	//	-- bytebase: depends-on=db_a@0012,@0010
+	//	-- bytebase: run-after=db_b
+	frontMatterPrefix = "bytebase:"
+	// frontMatterDependsOn and frontMatterRunAfter declare the migration files to run before the current one.
+	frontMatterDependsOn = "depends-on"
+	frontMatterRunAfter  = "run-after"
+)
+
+// migrationDependency is a dependency declared in the front-matter of the migration file.
+type migrationDependency struct {
+	// database is the database name of the dependency. Empty means the same database as the migration file.
+	database string
+	// version is the schema version of the dependency. Empty means all the migration files of the database in the push event.
+	version string
+}
+
+func (d *migrationDependency) String() string {
+	if d.version == "" {
+		return d.database
+	}
+	return fmt.Sprintf("%s@%s", d.database, d.version)
+}
+
+// parseMigrationDependencies parses the dependencies from the front-matter of the migration file.
+// The front-matter is the leading SQL comments of the file, and only the "-- bytebase:" lines are parsed.
+func parseMigrationDependencies(content string) ([]*migrationDependency, error) {
+	var dependencies []*migrationDependency
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if !strings.HasPrefix(line, "--") {
+			break
+		}
+		line = strings.TrimSpace(strings.TrimPrefix(line, "--"))
+		if !strings.HasPrefix(line, frontMatterPrefix) {
+			continue
+		}
+		for _, field := range strings.Fields(strings.TrimPrefix(line, frontMatterPrefix)) {
+			key, value, ok := strings.Cut(field, "=")
+			if !ok {
+				return nil, errors.Errorf("invalid front-matter %q, expect key=value", field)
+			}
+			if key != frontMatterDependsOn && key != frontMatterRunAfter {
+				return nil, errors.Errorf("unknown front-matter key %q, expect %q or %q", key, frontMatterDependsOn, frontMatterRunAfter)
+			}
+			for _, ref := range strings.Split(value, ",") {
+				ref = strings.TrimSpace(ref)
+				if ref == "" {
+					continue
+				}
+				database, version, _ := strings.Cut(ref, "@")
+				if database == "" && version == "" {
+					return nil, errors.Errorf("invalid %s %q", key, ref)
+				}
+				dependencies = append(dependencies, &migrationDependency{
+					database: database,
+					version:  version,
+				})
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to scan the front-matter")
+	}
+	return dependencies, nil
+}
+
+// readMigrationDependencies reads the migration files and parses the dependencies declared in their front-matter.
+// The files with invalid front-matter are ignored. The tenant project is not supported because each migration file
+// is processed in its own issue.
+func (s *Service) readMigrationDependencies(ctx context.Context, oauthContext *common.OauthContext, baseVCSPushEvent vcs.PushEvent, fileInfoList []fileInfo) ([]fileInfo, []*store.ActivityMessage) {
+	var result []fileInfo
+	var activityCreateList []*store.ActivityMessage
+	for _, fileInfo := range fileInfoList {
+		repoInfo := fileInfo.repoInfo
+		if !isDependencyNode(fileInfo) || repoInfo.project.TenantMode == api.TenantModeTenant {
+			result = append(result, fileInfo)
+			continue
+		}
+		pushEvent := baseVCSPushEvent
+		pushEvent.VCSType = repoInfo.vcs.Type
+		pushEvent.BaseDirectory = repoInfo.repository.BaseDirectory
+		content, err := s.readFileContent(ctx, oauthContext, pushEvent, repoInfo, fileInfo.item.FileName)
+		if err != nil {
+			// Leave the error to be reported when the file is processed.
+			slog.Warn("Failed to read file content for the dependencies", slog.String("file", fileInfo.item.FileName), log.BBError(err))
+			result = append(result, fileInfo)
+			continue
+		}
+		dependencies, err := parseMigrationDependencies(content)
+		if err != nil {
+			activityCreateList = append(activityCreateList, getIgnoredFileActivityCreate(repoInfo.project.UID, pushEvent, fileInfo.item.FileName, errors.Wrap(err, "Failed to parse dependencies")))
+			continue
+		}
+		fileInfo.content = &content
+		fileInfo.dependencies = dependencies
+		result = append(result, fileInfo)
+	}
+	return result, activityCreateList
+}
+
+// fileInfoGroup is a group of files processed in one issue.
+type fileInfoGroup struct {
+	fileInfoList []fileInfo
+	// err is set if the dependencies in the group cannot be resolved, e.g. there is a cycle.
+	err error
+}
+
+// groupFileInfoByDependency groups the files in a repository by the database, and merges the groups of
+// the databases depending on each other. The migration files in each group are sorted in the topological
+// order of the dependencies and the implicit order of the schema versions in the same database.
+// The level of the migration file is the number of cross-database dependencies to run before it, which
+// is used to order the steps of the plan.
+func groupFileInfoByDependency(fileInfoList []fileInfo) []*fileInfoGroup {
+	dbName2FileInfoList := groupFileInfoByDatabase(fileInfoList)
+	var databaseList []string
+	for database := range dbName2FileInfoList {
+		databaseList = append(databaseList, database)
+	}
+	sort.Strings(databaseList)
+
+	// The migration files are the nodes of the dependency graph.
+	var nodes []fileInfo
+	var others []fileInfo
+	for _, database := range databaseList {
+		for _, fileInfo := range sortFilesBySchemaVersion(dbName2FileInfoList[database]) {
+			if isDependencyNode(fileInfo) {
+				nodes = append(nodes, fileInfo)
+			} else {
+				others = append(others, fileInfo)
+			}
+		}
+	}
+
+	// edges[i] is the list of nodes that node i depends on.
+	edges := make([][]int, len(nodes))
+	// The union-find of the databases which are connected by the cross-database dependencies.
+	parent := make(map[string]string)
+	for _, database := range databaseList {
+		parent[database] = database
+	}
+	var find func(string) string
+	find = func(database string) string {
+		if parent[database] != database {
+			parent[database] = find(parent[database])
+		}
+		return parent[database]
+	}
+	for i, node := range nodes {
+		// The migration files of the same database run in the order of the schema version.
+		if i > 0 && nodes[i-1].migrationInfo.Database == node.migrationInfo.Database {
+			edges[i] = append(edges[i], i-1)
+		}
+		for _, dependency := range node.dependencies {
+			database := dependency.database
+			if database == "" {
+				database = node.migrationInfo.Database
+			}
+			found := false
+			for j, candidate := range nodes {
+				if i == j || candidate.migrationInfo.Database != database {
+					continue
+				}
+				if dependency.version != "" && candidate.migrationInfo.Version.Version != dependency.version {
+					continue
+				}
+				found = true
+				edges[i] = append(edges[i], j)
+			}
+			if !found {
+				// The dependency on a version should have been applied by a previous push event, which is checked
+				// against the change history before the group is processed.
+				if dependency.version != "" {
+					nodes[i].unresolvedDependencies = append(nodes[i].unresolvedDependencies, dependency)
+				}
+				continue
+			}
+			parent[find(database)] = find(node.migrationInfo.Database)
+		}
+	}
+
+	// Kahn's algorithm, and the ready nodes are picked in the order of the database name and schema version.
+	inDegree := make([]int, len(nodes))
+	dependents := make([][]int, len(nodes))
+	for i := range nodes {
+		for _, j := range edges[i] {
+			inDegree[i]++
+			dependents[j] = append(dependents[j], i)
+		}
+	}
+	levels := make([]int, len(nodes))
+	visited := make([]bool, len(nodes))
+	var sorted []int
+	for {
+		next := -1
+		for i := range nodes {
+			if !visited[i] && inDegree[i] == 0 {
+				next = i
+				break
+			}
+		}
+		if next < 0 {
+			break
+		}
+		visited[next] = true
+		sorted = append(sorted, next)
+		for _, i := range dependents[next] {
+			inDegree[i]--
+			level := levels[next]
+			if nodes[i].migrationInfo.Database != nodes[next].migrationInfo.Database {
+				level++
+			}
+			if level > levels[i] {
+				levels[i] = level
+			}
+		}
+	}
+
+	groupMap := make(map[string]*fileInfoGroup)
+	var groupList []*fileInfoGroup
+	getGroup := func(database string) *fileInfoGroup {
+		root := find(database)
+		group, ok := groupMap[root]
+		if !ok {
+			group = &fileInfoGroup{}
+			groupMap[root] = group
+			groupList = append(groupList, group)
+		}
+		return group
+	}
+	for _, i := range sorted {
+		fileInfo := nodes[i]
+		fileInfo.level = levels[i]
+		group := getGroup(fileInfo.migrationInfo.Database)
+		group.fileInfoList = append(group.fileInfoList, fileInfo)
+	}
+	// The nodes left are in the dependency cycles, and the groups containing them are rejected.
+	cycleFileList := make(map[*fileInfoGroup][]string)
+	for i, fileInfo := range nodes {
+		if visited[i] {
+			continue
+		}
+		group := getGroup(fileInfo.migrationInfo.Database)
+		group.fileInfoList = append(group.fileInfoList, fileInfo)
+		cycleFileList[group] = append(cycleFileList[group], fileInfo.item.FileName)
+	}
+	for group, fileList := range cycleFileList {
+		group.err = errors.Errorf("found dependency cycle among files %s", strings.Join(fileList, ", "))
+	}
+	for _, fileInfo := range others {
+		group := getGroup(fileInfo.migrationInfo.Database)
+		group.fileInfoList = append(group.fileInfoList, fileInfo)
+	}
+	return groupList
+}
+
+// checkUnresolvedDependencies returns an error if any dependency not in the push event has not been applied to
+// the databases, which are found in the same environment as the migration file depending on it.
+func (s *Service) checkUnresolvedDependencies(ctx context.Context, fileInfoList []fileInfo) error {
+	for _, fileInfo := range fileInfoList {
+		for _, dependency := range fileInfo.unresolvedDependencies {
+			database := dependency.database
+			if database == "" {
+				database = fileInfo.migrationInfo.Database
+			}
+			databases, err := s.findProjectDatabases(ctx, fileInfo.repoInfo.project.UID, database, fileInfo.migrationInfo.Environment)
+			if err != nil {
+				return errors.Wrapf(err, "failed to find the database of dependency %s of file %s", dependency, fileInfo.item.FileName)
+			}
+			for _, database := range databases {
+				applied, err := s.isVersionApplied(ctx, database, dependency.version)
+				if err != nil {
+					return err
+				}
+				if !applied {
+					return errors.Errorf("dependency %s of file %s is neither in the push event nor applied to database %q", dependency, fileInfo.item.FileName, database.DatabaseName)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// isVersionApplied returns true if the migration file of the version has been applied to the database successfully.
+func (s *Service) isVersionApplied(ctx context.Context, database *store.DatabaseMessage, version string) (bool, error) {
+	instance, err := s.store.GetInstanceV2(ctx, &store.FindInstanceMessage{ResourceID: &database.InstanceID})
+	if err != nil {
+		return false, errors.Wrap(err, "failed to get instance")
+	}
+	if instance == nil {
+		return false, errors.Errorf("instance %q not found", database.InstanceID)
+	}
+	// The schema version in the change history has the suffix of the migration type.
+	for _, migrationType := range []db.MigrationType{db.Migrate, db.Data, db.MigrateSDL, db.Baseline} {
+		schemaVersion := model.Version{Version: fmt.Sprintf("%s-%s", version, migrationType.GetVersionTypeSuffix())}
+		history, err := s.getAppliedChangeHistory(ctx, instance, database, schemaVersion, false /* showFull */)
+		if err != nil {
+			return false, err
+		}
+		if history != nil {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// isDependencyNode returns true if the file is a migration file to be applied.
+func isDependencyNode(fileInfo fileInfo) bool {
+	return fileInfo.fType == fileTypeMigration && fileInfo.item.ItemType != vcs.FileItemTypeRemoved
+}
@@ -0,0 +1,143 @@
+package gitops
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/backend/plugin/db"
+	"github.com/bytebase/bytebase/backend/plugin/vcs"
+	"github.com/bytebase/bytebase/backend/store/model"
+)
+
+func TestParseMigrationDependencies(t *testing.T) {
+	tests := []struct {
+		content string
+		want    []*migrationDependency
+		wantErr string
+	}{
+		{
+			content: "CREATE TABLE t(id INT);",
+		},
+		{
+			content: "\n-- Create the table for the backfill.\n-- bytebase: depends-on=db_a@0012,@0010\n--bytebase: run-after=db_b\nCREATE TABLE t(id INT);",
+			want: []*migrationDependency{
+				{database: "db_a", version: "0012"},
+				{version: "0010"},
+				{database: "db_b"},
+			},
+		},
+		{
+			// The front-matter ends at the first statement.
+			content: "CREATE TABLE t(id INT);\n-- bytebase: depends-on=db_a@0012",
+		},
+		{
+			content: "-- bytebase: depends-on=db_a@0012 run-after=db_b@v12",
+			want: []*migrationDependency{
+				{database: "db_a", version: "0012"},
+				{database: "db_b", version: "v12"},
+			},
+		},
+		{
+			content: "-- bytebase: depends=db_a",
+			wantErr: `unknown front-matter key "depends", expect "depends-on" or "run-after"`,
+		},
+		{
+			content: "-- bytebase: depends-on",
+			wantErr: `invalid front-matter "depends-on", expect key=value`,
+		},
+		{
+			content: "-- bytebase: depends-on=@",
+			wantErr: `invalid depends-on "@"`,
+		},
+	}
+
+	for _, test := range tests {
+		got, err := parseMigrationDependencies(test.content)
+		if test.wantErr != "" {
+			require.EqualError(t, err, test.wantErr)
+			continue
+		}
+		require.NoError(t, err)
+		assert.Equal(t, test.want, got)
+	}
+}
+
+func TestGroupFileInfoByDependency(t *testing.T) {
+	newFileInfo := func(database, version string, dependencies ...*migrationDependency) fileInfo {
+		return fileInfo{
+			item: vcs.DistinctFileItem{
+				FileName: database + "##" + version + "##ddl.sql",
+				ItemType: vcs.FileItemTypeAdded,
+			},
+			migrationInfo: &db.MigrationInfo{
+				Database: database,
+				Version:  model.Version{Version: version},
+				Type:     db.Migrate,
+			},
+			fType:        fileTypeMigration,
+			dependencies: dependencies,
+		}
+	}
+	getFileList := func(group *fileInfoGroup) ([]string, []int) {
+		var fileList []string
+		var levelList []int
+		for _, fileInfo := range group.fileInfoList {
+			fileList = append(fileList, fileInfo.item.FileName)
+			levelList = append(levelList, fileInfo.level)
+		}
+		return fileList, levelList
+	}
+
+	t.Run("independent databases", func(t *testing.T) {
+		groupList := groupFileInfoByDependency([]fileInfo{
+			newFileInfo("db_b", "0002"),
+			newFileInfo("db_a", "0001"),
+			newFileInfo("db_b", "0001"),
+		})
+		require.Len(t, groupList, 2)
+		fileList, levelList := getFileList(groupList[0])
+		assert.Equal(t, []string{"db_a##0001##ddl.sql"}, fileList)
+		assert.Equal(t, []int{0}, levelList)
+		fileList, levelList = getFileList(groupList[1])
+		assert.Equal(t, []string{"db_b##0001##ddl.sql", "db_b##0002##ddl.sql"}, fileList)
+		assert.Equal(t, []int{0, 0}, levelList)
+	})
+
+	t.Run("cross database dependencies", func(t *testing.T) {
+		groupList := groupFileInfoByDependency([]fileInfo{
+			// Backfill db_a from db_b after the table is created in db_b.
+			newFileInfo("db_a", "0001"),
+			newFileInfo("db_a", "0002", &migrationDependency{database: "db_b", version: "0012"}),
+			newFileInfo("db_b", "0012"),
+			newFileInfo("db_c", "0001", &migrationDependency{database: "db_a"}),
+			newFileInfo("db_d", "0001", &migrationDependency{database: "db_e", version: "0001"}),
+		})
+		require.Len(t, groupList, 2)
+		require.NoError(t, groupList[0].err)
+		fileList, levelList := getFileList(groupList[0])
+		assert.Equal(t, []string{"db_a##0001##ddl.sql", "db_b##0012##ddl.sql", "db_a##0002##ddl.sql", "db_c##0001##ddl.sql"}, fileList)
+		assert.Equal(t, []int{0, 0, 1, 2}, levelList)
+		// The dependency not in the push event is checked against the change history later.
+		fileList, levelList = getFileList(groupList[1])
+		assert.Equal(t, []string{"db_d##0001##ddl.sql"}, fileList)
+		assert.Equal(t, []int{0}, levelList)
+		assert.Equal(t, []*migrationDependency{{database: "db_e", version: "0001"}}, groupList[1].fileInfoList[0].unresolvedDependencies)
+		// The dependency on all the migration files of db_a in the push event is resolved.
+		assert.Empty(t, groupList[0].fileInfoList[3].unresolvedDependencies)
+	})
+
+	t.Run("dependency cycle", func(t *testing.T) {
+		groupList := groupFileInfoByDependency([]fileInfo{
+			newFileInfo("db_a", "0001", &migrationDependency{database: "db_b"}),
+			newFileInfo("db_b", "0001", &migrationDependency{database: "db_a", version: "0001"}),
+			newFileInfo("db_c", "0001"),
+		})
+		require.Len(t, groupList, 2)
+		fileList, _ := getFileList(groupList[0])
+		assert.Equal(t, []string{"db_c##0001##ddl.sql"}, fileList)
+		require.NoError(t, groupList[0].err)
+		require.EqualError(t, groupList[1].err, "found dependency cycle among files db_a##0001##ddl.sql, db_b##0001##ddl.sql")
+	})
+}
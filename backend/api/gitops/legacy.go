@@ -18,6 +18,8 @@ type migrationDetail struct {
 	// schemaVersion is parsed from VCS file name.
 	// It is automatically generated in the UI workflow.
 	schemaVersion model.Version
+	// level is the order of the plan step in the same environment, which is derived from the dependencies between databases.
+	level int
 }
 
 // MigrationFileYAMLDatabase contains the information of a database in a YAML
@@ -16,6 +16,7 @@ import (
 	"path"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -23,6 +24,7 @@ import (
 
 	"github.com/labstack/echo/v4"
 	"github.com/pkg/errors"
+	"google.golang.org/protobuf/encoding/protojson"
 	"gopkg.in/yaml.v3"
 
 	"github.com/bytebase/bytebase/backend/common"
@@ -31,6 +33,8 @@ import (
 	api "github.com/bytebase/bytebase/backend/legacyapi"
 	"github.com/bytebase/bytebase/backend/plugin/advisor"
 	"github.com/bytebase/bytebase/backend/plugin/db"
+	"github.com/bytebase/bytebase/backend/plugin/parser/base"
+	annotationparser "github.com/bytebase/bytebase/backend/plugin/parser/mybatis/annotation"
 	configparser "github.com/bytebase/bytebase/backend/plugin/parser/mybatis/configuration"
 	mapperparser "github.com/bytebase/bytebase/backend/plugin/parser/mybatis/mapper"
 	"github.com/bytebase/bytebase/backend/plugin/parser/mybatis/mapper/ast"
@@ -40,6 +44,8 @@ import (
 	// Register azure plugin.
 	"github.com/bytebase/bytebase/backend/plugin/vcs/azure"
 	"github.com/bytebase/bytebase/backend/plugin/vcs/bitbucket"
+	"github.com/bytebase/bytebase/backend/plugin/vcs/bitbucketdc"
+	"github.com/bytebase/bytebase/backend/plugin/vcs/gitea"
 	"github.com/bytebase/bytebase/backend/plugin/vcs/github"
 	"github.com/bytebase/bytebase/backend/plugin/vcs/gitlab"
 	"github.com/bytebase/bytebase/backend/store"
@@ -51,6 +57,12 @@ import (
 const (
 	// sqlReviewDocs is the URL for SQL review doc.
 	sqlReviewDocs = "https://www.bytebase.com/docs/reference/error-code/advisor"
+	// sqlReviewStatusContext is the name of the commit status for the SQL review.
+	sqlReviewStatusContext = "bytebase/sql-review"
+	// sqlReviewCommentTitle is the title of the SQL review summary comment.
+	sqlReviewCommentTitle = "**Bytebase SQL Review**"
+	// sqlReviewCommentMarker is the hidden mark of the SQL review comments, which finds the comments of the earlier reviews.
+	sqlReviewCommentMarker = "<!-- bytebase-sql-review -->"
 
 	// issueNameTemplate should be consistent with UI issue names generated from the frontend except for the timestamp.
 	// Because we cannot get the correct timezone of the client here.
@@ -72,7 +84,13 @@ func (s *Service) RegisterWebhookRoutes(g *echo.Group) {
 		if err := json.Unmarshal(body, &pushEvent); err != nil {
 			return echo.NewHTTPError(http.StatusBadRequest, "Malformed push event").SetInternal(err)
 		}
-		// This shouldn't happen as we only setup webhook to receive push event, just in case.
+		if pushEvent.ObjectKind == gitlab.WebhookMergeRequest {
+			if err := s.processGitLabMergeRequestEvent(ctx, c.Param("id"), c.Request().Header.Get("X-Gitlab-Token"), body); err != nil {
+				return err
+			}
+			return c.String(http.StatusOK, "OK")
+		}
+		// This shouldn't happen as we only setup webhook to receive push and merge request events, just in case.
 		if pushEvent.ObjectKind != gitlab.WebhookPush {
 			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid webhook event type, got %s, want push", pushEvent.ObjectKind))
 		}
@@ -132,7 +150,6 @@ func (s *Service) RegisterWebhookRoutes(g *echo.Group) {
 	g.POST("/github/:id", func(c echo.Context) error {
 		ctx := c.Request().Context()
 
-		// This shouldn't happen as we only setup webhook to receive push event, just in case.
 		eventType := github.WebhookType(c.Request().Header.Get("X-GitHub-Event"))
 		// https://docs.github.com/en/developers/webhooks-and-events/webhooks/about-webhooks#ping-event
 		// When we create a new webhook, GitHub will send us a simple ping event to let us know we've set up the webhook correctly.
@@ -140,7 +157,8 @@ func (s *Service) RegisterWebhookRoutes(g *echo.Group) {
 		if eventType == github.WebhookPing {
 			return c.String(http.StatusOK, "OK")
 		}
-		if eventType != github.WebhookPush {
+		// This shouldn't happen as we only setup webhook to receive push and pull request events, just in case.
+		if eventType != github.WebhookPush && eventType != github.WebhookPullRequest {
 			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid webhook event type, got %s, want %s", eventType, github.WebhookPush))
 		}
 
@@ -148,6 +166,12 @@ func (s *Service) RegisterWebhookRoutes(g *echo.Group) {
 		if err != nil {
 			return echo.NewHTTPError(http.StatusBadRequest, "Failed to read webhook request").SetInternal(err)
 		}
+		if eventType == github.WebhookPullRequest {
+			if err := s.processGitHubPullRequestEvent(ctx, c.Param("id"), c.Request().Header.Get("X-Hub-Signature-256"), body); err != nil {
+				return err
+			}
+			return c.String(http.StatusOK, "OK")
+		}
 		var pushEvent github.WebhookPushEvent
 		if err := json.Unmarshal(body, &pushEvent); err != nil {
 			return echo.NewHTTPError(http.StatusBadRequest, "Malformed push event").SetInternal(err)
@@ -206,6 +230,79 @@ func (s *Service) RegisterWebhookRoutes(g *echo.Group) {
 		return c.String(http.StatusOK, strings.Join(createdMessages, "\n"))
 	})
 
+	g.POST("/gitea/:id", func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		// This shouldn't happen as we only setup webhook to receive push event, just in case.
+		// Forgejo sends the X-Gitea-* headers as well for compatibility.
+		eventType := gitea.WebhookType(c.Request().Header.Get("X-Gitea-Event"))
+		if eventType != gitea.WebhookPush {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid webhook event type, got %s, want %s", eventType, gitea.WebhookPush))
+		}
+
+		body, err := io.ReadAll(c.Request().Body)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Failed to read webhook request").SetInternal(err)
+		}
+		var pushEvent gitea.WebhookPushEvent
+		if err := json.Unmarshal(body, &pushEvent); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed push event").SetInternal(err)
+		}
+		repositoryID := pushEvent.Repository.FullName
+
+		nonBytebaseCommitList := filterGiteaBytebaseCommit(pushEvent.Commits)
+		if len(nonBytebaseCommitList) == 0 {
+			var commitList []string
+			for _, commit := range pushEvent.Commits {
+				commitList = append(commitList, commit.ID)
+			}
+			slog.Debug("all commits are created by Bytebase",
+				slog.String("repoURL", pushEvent.Repository.HTMLURL),
+				slog.String("repoName", pushEvent.Repository.FullName),
+				slog.String("commits", strings.Join(commitList, ", ")),
+			)
+			return c.String(http.StatusOK, "OK")
+		}
+		pushEvent.Commits = nonBytebaseCommitList
+
+		filter := func(repo *store.RepositoryMessage) (bool, error) {
+			// Gitea signs the payload in the same way as GitHub, but without the "sha256=" prefix.
+			ok, err := validateGitHubWebhookSignature256(c.Request().Header.Get("X-Gitea-Signature"), repo.WebhookSecretToken, body)
+			if err != nil {
+				return false, echo.NewHTTPError(http.StatusInternalServerError, "Failed to validate Gitea webhook signature").SetInternal(err)
+			}
+			if !ok {
+				return false, nil
+			}
+
+			return isWebhookEventBranch(pushEvent.Ref, repo.BranchFilter)
+		}
+		repositoryList, err := s.filterRepository(ctx, c.Param("id"), repositoryID, filter)
+		if err != nil {
+			return err
+		}
+		if len(repositoryList) == 0 {
+			slog.Debug("Empty handle repo list. Ignore this push event.")
+			return c.String(http.StatusOK, "OK")
+		}
+		repo := repositoryList[0]
+		oauthContext := &common.OauthContext{
+			ClientID:     repo.vcs.ApplicationID,
+			ClientSecret: repo.vcs.Secret,
+			AccessToken:  repo.repository.AccessToken,
+			RefreshToken: repo.repository.RefreshToken,
+			Refresher:    utils.RefreshToken(ctx, s.store, repo.repository.WebURL),
+		}
+
+		baseVCSPushEvent := pushEvent.ToVCS()
+
+		createdMessages, err := s.processPushEvent(ctx, oauthContext, repositoryList, baseVCSPushEvent)
+		if err != nil {
+			return err
+		}
+		return c.String(http.StatusOK, strings.Join(createdMessages, "\n"))
+	})
+
 	g.POST("/bitbucket/:id", func(c echo.Context) error {
 		ctx := c.Request().Context()
 
@@ -345,6 +442,36 @@ func (s *Service) RegisterWebhookRoutes(g *echo.Group) {
 		return c.String(http.StatusOK, strings.Join(allCreatedMessages, "\n"))
 	})
 
+	g.POST("/bitbucket-dc/:id", func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		body, err := io.ReadAll(c.Request().Body)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Failed to read webhook request").SetInternal(err)
+		}
+		signature := c.Request().Header.Get("X-Hub-Signature")
+
+		eventType := bitbucketdc.WebhookEventKey(c.Request().Header.Get("X-Event-Key"))
+		switch eventType {
+		case bitbucketdc.WebhookDiagnosticsPing:
+			return c.String(http.StatusOK, "OK")
+		case bitbucketdc.WebhookRefsChanged:
+			createdMessages, err := s.processBitbucketDataCenterPushEvent(ctx, c.Param("id"), signature, body)
+			if err != nil {
+				return err
+			}
+			return c.String(http.StatusOK, strings.Join(createdMessages, "\n"))
+		case bitbucketdc.WebhookPullRequestOpened, bitbucketdc.WebhookPullRequestFromRefUpdated, bitbucketdc.WebhookPullRequestModified:
+			if err := s.processBitbucketDataCenterPullRequestEvent(ctx, c.Param("id"), signature, body); err != nil {
+				return err
+			}
+			return c.String(http.StatusOK, "OK")
+		default:
+			// This shouldn't happen as we only setup webhook to receive push and pull request events, just in case.
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid webhook event type, got %q, want %q", eventType, bitbucketdc.WebhookRefsChanged))
+		}
+	})
+
 	g.POST("/azure/:id", func(c echo.Context) error {
 		ctx := c.Request().Context()
 
@@ -660,57 +787,9 @@ func (s *Service) RegisterWebhookRoutes(g *echo.Group) {
 			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to list pull request file").SetInternal(err)
 		}
 
-		sqlFileName2Advice := s.sqlAdviceForSQLFiles(ctx, oauthContext, repositoryList, prFiles, setting.ExternalUrl)
-
-		if s.licenseService.IsFeatureEnabled(api.FeatureMybatisSQLReview) == nil {
-			// If the commit file list contains the file which extension is xml and the content
-			// contains "https://mybatis.org/dtd/mybatis-3-mapper.dtd", we will try to apply
-			// sql-review to it.
-			// To apply sql-review to it, proceed as follows:
-			// 1. Look in the sibling and parent directories for directories containing similar
-			// <!DOCTYPE configuration
-			//   PUBLIC "-//mybatis.org//DTD Config 3.0//EN"
-			//   "https://mybatis.org/dtd/mybatis-3-config.dtd">
-			// of the xml file
-			// 2. If we can find it, then we will extract the sql from the mapper xml
-			// 3. match the environments in the configuration xml, look for the sql-review policy in the environment and apply it.
-			var isMybatisMapperXMLRegex = regexp.MustCompile(`(?i)http(s)?://mybatis\.org/dtd/mybatis-3-mapper\.dtd`)
-
-			mybatisMapperXMLFiles := make(map[string]string)
-			var commitID string
-			for _, prFile := range prFiles {
-				if !strings.HasSuffix(prFile.Path, ".xml") {
-					continue
-				}
-				fileContent, err := vcs.Get(repo.vcs.Type, vcs.ProviderConfig{}).ReadFileContent(
-					ctx,
-					oauthContext,
-					repo.vcs.InstanceURL,
-					repo.repository.ExternalID,
-					prFile.Path,
-					vcs.RefInfo{
-						RefType: vcs.RefTypeCommit,
-						RefName: prFile.LastCommitID,
-					},
-				)
-				if err != nil {
-					return echo.NewHTTPError(http.StatusInternalServerError, "Failed to read file content").SetInternal(err)
-				}
-				if !isMybatisMapperXMLRegex.MatchString(fileContent) {
-					continue
-				}
-				mybatisMapperXMLFiles[prFile.Path] = fileContent
-				commitID = prFile.LastCommitID
-			}
-			if len(mybatisMapperXMLFiles) > 0 {
-				mapperAdvices, err := s.sqlAdviceForMybatisMapperFiles(ctx, oauthContext, mybatisMapperXMLFiles, commitID, repo)
-				if err != nil {
-					return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get sql advice for mybatis mapper files").SetInternal(err)
-				}
-				for filename, mapperAdvice := range mapperAdvices {
-					sqlFileName2Advice[filename] = mapperAdvice
-				}
-			}
+		sqlFileName2Advice, err := s.sqlAdviceForPullRequestFiles(ctx, oauthContext, repositoryList, prFiles, setting.ExternalUrl)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to take SQL review for pull request files").SetInternal(err)
 		}
 
 		response := &api.VCSSQLReviewResult{}
@@ -735,7 +814,577 @@ func (s *Service) RegisterWebhookRoutes(g *echo.Group) {
 	})
 }
 
-func (s *Service) sqlAdviceForMybatisMapperFiles(ctx context.Context, oauthContext *common.OauthContext, mybatisMapperContent map[string]string, commitID string, repoInfo *repoInfo) (map[string][]advisor.Advice, error) {
+// processBitbucketDataCenterPushEvent processes the repo:refs_changed event of Bitbucket Data Center.
+func (s *Service) processBitbucketDataCenterPushEvent(ctx context.Context, webhookEndpointID, signature string, body []byte) ([]string, error) {
+	var pushEvent bitbucketdc.WebhookRefsChangedEvent
+	if err := json.Unmarshal(body, &pushEvent); err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "Malformed push event").SetInternal(err)
+	}
+	repositoryID := pushEvent.Repository.FullPath()
+	repositoryURL := pushEvent.Repository.WebURL()
+
+	var allCreatedMessages []string
+	for _, change := range pushEvent.Changes {
+		if change.Ref.Type != "BRANCH" || change.Type == "DELETE" {
+			continue
+		}
+		// The push event of the new branch has no base commit, we don't know which commits are new to the repository.
+		if change.Type == "ADD" {
+			slog.Debug("Ignore the push event of the new branch", slog.String("ref", change.RefID), slog.String("repoName", repositoryID))
+			continue
+		}
+
+		filter := func(repo *store.RepositoryMessage) (bool, error) {
+			// Bitbucket Data Center signs the payload in the same way as GitHub.
+			ok, err := validateGitHubWebhookSignature256(signature, repo.WebhookSecretToken, body)
+			if err != nil {
+				return false, echo.NewHTTPError(http.StatusInternalServerError, "Failed to validate Bitbucket Data Center webhook signature").SetInternal(err)
+			}
+			if !ok {
+				return false, nil
+			}
+
+			return isWebhookEventBranch(change.RefID, repo.BranchFilter)
+		}
+		repositoryList, err := s.filterRepository(ctx, webhookEndpointID, repositoryID, filter)
+		if err != nil {
+			return nil, err
+		}
+		if len(repositoryList) == 0 {
+			slog.Debug("Empty handle repo list. Ignore this push event.")
+			continue
+		}
+		repo := repositoryList[0]
+		oauthContext := &common.OauthContext{
+			ClientID:     repo.vcs.ApplicationID,
+			ClientSecret: repo.vcs.Secret,
+			AccessToken:  repo.repository.AccessToken,
+			RefreshToken: repo.repository.RefreshToken,
+			Refresher:    utils.RefreshToken(ctx, s.store, repo.repository.WebURL),
+		}
+
+		commits, err := bitbucketdc.ListCommits(ctx, oauthContext, repo.vcs.InstanceURL, repo.repository.ExternalID, change.FromHash, change.ToHash)
+		if err != nil {
+			return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to list commits for ref %q", change.RefID)).SetInternal(err)
+		}
+		nonBytebaseCommitList := filterBitbucketDataCenterBytebaseCommit(commits)
+		if len(nonBytebaseCommitList) == 0 {
+			var commitList []string
+			for _, commit := range commits {
+				commitList = append(commitList, commit.ID)
+			}
+			slog.Debug("all commits are created by Bytebase",
+				slog.String("repoURL", repositoryURL),
+				slog.String("repoName", repositoryID),
+				slog.String("commits", strings.Join(commitList, ", ")),
+			)
+			continue
+		}
+
+		var commitList []vcs.Commit
+		// The commits are listed from the newest to the oldest.
+		for i := len(nonBytebaseCommitList) - 1; i >= 0; i-- {
+			commit := nonBytebaseCommitList[i]
+			before := strings.Repeat("0", 40)
+			if len(commit.Parents) > 0 {
+				before = commit.Parents[0].ID
+			}
+			fileDiffList, err := vcs.Get(repo.vcs.Type, vcs.ProviderConfig{}).GetDiffFileList(
+				ctx,
+				oauthContext,
+				repo.vcs.InstanceURL,
+				repo.repository.ExternalID,
+				before,
+				commit.ID,
+			)
+			if err != nil {
+				return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Failed to get diff file list for commit %q", commit.ID)).SetInternal(err)
+			}
+
+			var addedList, modifiedList []string
+			for _, f := range fileDiffList {
+				switch f.Type {
+				case vcs.FileDiffTypeAdded:
+					addedList = append(addedList, f.Path)
+				case vcs.FileDiffTypeModified:
+					modifiedList = append(modifiedList, f.Path)
+				}
+			}
+
+			// Per Git convention, the message title and body are separated by two new line characters.
+			messages := strings.SplitN(commit.Message, "\n\n", 2)
+			messageTitle := messages[0]
+
+			commitList = append(commitList,
+				vcs.Commit{
+					ID:           commit.ID,
+					Title:        messageTitle,
+					Message:      commit.Message,
+					CreatedTs:    commit.AuthorTimestamp / 1000,
+					URL:          fmt.Sprintf("%s/commits/%s", repositoryURL, commit.ID),
+					AuthorName:   commit.Author.Name,
+					AuthorEmail:  commit.Author.EmailAddress,
+					AddedList:    addedList,
+					ModifiedList: modifiedList,
+				},
+			)
+		}
+
+		createdMessages, err := s.processPushEvent(
+			ctx,
+			oauthContext,
+			repositoryList,
+			vcs.PushEvent{
+				VCSType:            vcs.BitbucketDataCenter,
+				Ref:                change.RefID,
+				Before:             change.FromHash,
+				After:              change.ToHash,
+				RepositoryID:       repositoryID,
+				RepositoryURL:      repositoryURL,
+				RepositoryFullPath: repositoryID,
+				AuthorName:         pushEvent.Actor.DisplayName,
+				CommitList:         commitList,
+			},
+		)
+		if err != nil {
+			return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to process push event for commit %q", change.ToHash)).SetInternal(err)
+		}
+		allCreatedMessages = append(allCreatedMessages, createdMessages...)
+	}
+	return allCreatedMessages, nil
+}
+
+// processBitbucketDataCenterPullRequestEvent takes the SQL review for the pull request, then reports the result
+// as the build status of the latest commit and the comment of the pull request, which is updated on the new commits.
+// Bitbucket Data Center has no built-in CI, so the SQL review is triggered by the pull request events directly.
+func (s *Service) processBitbucketDataCenterPullRequestEvent(ctx context.Context, webhookEndpointID, signature string, body []byte) error {
+	var prEvent bitbucketdc.WebhookPullRequestEvent
+	if err := json.Unmarshal(body, &prEvent); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Malformed pull request event").SetInternal(err)
+	}
+	pullRequest := prEvent.PullRequest
+	pullRequestID := strconv.Itoa(pullRequest.ID)
+
+	validator := func(repo *store.RepositoryMessage) (bool, error) {
+		ok, err := validateGitHubWebhookSignature256(signature, repo.WebhookSecretToken, body)
+		if err != nil {
+			return false, echo.NewHTTPError(http.StatusInternalServerError, "Failed to validate Bitbucket Data Center webhook signature").SetInternal(err)
+		}
+		return ok, nil
+	}
+	review, err := s.sqlReviewPullRequest(ctx, webhookEndpointID, pullRequest.ToRef.Repository.FullPath(), pullRequest.ToRef.ID, pullRequestID, validator)
+	if err != nil || review == nil {
+		return err
+	}
+	repo := review.repo
+
+	buildStatus := &bitbucketdc.BuildStatus{
+		State:       bitbucketdc.BuildStateSuccessful,
+		Key:         "bytebase-sql-review",
+		Name:        "Bytebase SQL Review",
+		URL:         review.externalURL,
+		Description: getSQLReviewStatusDescription(review.status),
+	}
+	if review.status == advisor.Error {
+		buildStatus.State = bitbucketdc.BuildStateFailed
+	}
+	if err := bitbucketdc.SetBuildStatus(ctx, review.oauthContext, repo.vcs.InstanceURL, pullRequest.FromRef.LatestCommit, buildStatus); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to set build status").SetInternal(err)
+	}
+
+	// Bitbucket Data Center renders the HTML comments as the text, so the earlier review comment is found by its title.
+	comments, err := bitbucketdc.ListPullRequestComments(ctx, review.oauthContext, repo.vcs.InstanceURL, repo.repository.ExternalID, pullRequestID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to list pull request comments").SetInternal(err)
+	}
+	text := formatSQLReviewSummary(len(review.comments), review.comments)
+	for _, comment := range comments {
+		if !strings.HasPrefix(comment.Text, sqlReviewCommentTitle) {
+			continue
+		}
+		comment.Text = text
+		if err := bitbucketdc.UpdatePullRequestComment(ctx, review.oauthContext, repo.vcs.InstanceURL, repo.repository.ExternalID, pullRequestID, comment); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to update pull request comment").SetInternal(err)
+		}
+		return nil
+	}
+	if len(review.comments) > 0 {
+		if err := bitbucketdc.CreatePullRequestComment(ctx, review.oauthContext, repo.vcs.InstanceURL, repo.repository.ExternalID, pullRequestID, text); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create pull request comment").SetInternal(err)
+		}
+	}
+	return nil
+}
+
+// processGitHubPullRequestEvent takes the SQL review for the pull request, then reports the result
+// as the commit status of the head commit, the inline comments and the summary comment of the pull request.
+// The inline comments of the earlier commits are replaced, and the summary comment is updated in place.
+func (s *Service) processGitHubPullRequestEvent(ctx context.Context, webhookEndpointID, signature string, body []byte) error {
+	var prEvent github.WebhookPullRequestEvent
+	if err := json.Unmarshal(body, &prEvent); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Malformed pull request event").SetInternal(err)
+	}
+	switch prEvent.Action {
+	case "opened", "reopened", "synchronize":
+	default:
+		slog.Debug("Ignore the pull request event", slog.String("action", prEvent.Action))
+		return nil
+	}
+	pullRequest := prEvent.PullRequest
+	pullRequestID := strconv.Itoa(pullRequest.Number)
+
+	validator := func(repo *store.RepositoryMessage) (bool, error) {
+		ok, err := validateGitHubWebhookSignature256(signature, repo.WebhookSecretToken, body)
+		if err != nil {
+			return false, echo.NewHTTPError(http.StatusInternalServerError, "Failed to validate GitHub webhook signature").SetInternal(err)
+		}
+		return ok, nil
+	}
+	review, err := s.sqlReviewPullRequest(ctx, webhookEndpointID, prEvent.Repository.FullName, "refs/heads/"+pullRequest.Base.Ref, pullRequestID, validator)
+	if err != nil || review == nil {
+		return err
+	}
+	repo := review.repo
+
+	commitStatus := &github.CommitStatus{
+		State:       github.CommitStateSuccess,
+		TargetURL:   review.externalURL,
+		Description: getSQLReviewStatusDescription(review.status),
+		Context:     sqlReviewStatusContext,
+	}
+	if review.status == advisor.Error {
+		commitStatus.State = github.CommitStateFailure
+	}
+	if err := github.CreateCommitStatus(ctx, review.oauthContext, repo.vcs.InstanceURL, repo.repository.ExternalID, pullRequest.Head.SHA, commitStatus); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create commit status").SetInternal(err)
+	}
+
+	// Remove the inline comments of the earlier reviews, as the advices on the new commits are commented again.
+	previousComments, err := github.ListPullRequestReviewComments(ctx, review.oauthContext, repo.vcs.InstanceURL, repo.repository.ExternalID, pullRequestID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to list pull request review comments").SetInternal(err)
+	}
+	for _, comment := range previousComments {
+		if !strings.Contains(comment.Body, sqlReviewCommentMarker) {
+			continue
+		}
+		if err := github.DeletePullRequestReviewComment(ctx, review.oauthContext, repo.vcs.InstanceURL, repo.repository.ExternalID, comment.ID); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to delete pull request review comment").SetInternal(err)
+		}
+	}
+	// GitHub rejects the inline comment if the advice is on the unchanged lines, collect them into the summary comment.
+	var overviewComments []*sqlReviewComment
+	for _, comment := range review.comments {
+		if err := github.CreatePullRequestReviewComment(ctx, review.oauthContext, repo.vcs.InstanceURL, repo.repository.ExternalID, pullRequestID, &github.PullRequestReviewCommentCreate{
+			CommitID: pullRequest.Head.SHA,
+			Path:     comment.path,
+			Line:     comment.line,
+			Side:     "RIGHT",
+			Body:     comment.body() + "\n\n" + sqlReviewCommentMarker,
+		}); err != nil {
+			slog.Debug("Failed to create pull request review comment",
+				slog.String("file", comment.path),
+				slog.Int("line", comment.line),
+				log.BBError(err),
+			)
+			overviewComments = append(overviewComments, comment)
+		}
+	}
+
+	summary := formatSQLReviewSummary(len(review.comments), overviewComments) + "\n\n" + sqlReviewCommentMarker
+	issueComments, err := github.ListIssueComments(ctx, review.oauthContext, repo.vcs.InstanceURL, repo.repository.ExternalID, pullRequestID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to list pull request comments").SetInternal(err)
+	}
+	for _, comment := range issueComments {
+		if !strings.Contains(comment.Body, sqlReviewCommentMarker) {
+			continue
+		}
+		if err := github.UpdateIssueComment(ctx, review.oauthContext, repo.vcs.InstanceURL, repo.repository.ExternalID, comment.ID, summary); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to update pull request comment").SetInternal(err)
+		}
+		return nil
+	}
+	if len(review.comments) > 0 {
+		if err := github.CreateIssueComment(ctx, review.oauthContext, repo.vcs.InstanceURL, repo.repository.ExternalID, pullRequestID, summary); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create pull request comment").SetInternal(err)
+		}
+	}
+	return nil
+}
+
+// processGitLabMergeRequestEvent takes the SQL review for the merge request, then reports the result
+// as the commit status of the last commit, the diff discussions and the overview discussion of the merge request.
+// The diff discussions of the earlier commits are resolved, and the overview discussion is updated in place.
+func (s *Service) processGitLabMergeRequestEvent(ctx context.Context, webhookEndpointID, token string, body []byte) error {
+	var mrEvent gitlab.WebhookMergeRequestEvent
+	if err := json.Unmarshal(body, &mrEvent); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Malformed merge request event").SetInternal(err)
+	}
+	attributes := mrEvent.ObjectAttributes
+	// The "update" action without the old revision means no new commits, e.g. the title is changed.
+	if attributes.Action != "open" && attributes.Action != "reopen" && (attributes.Action != "update" || attributes.OldRev == "") {
+		slog.Debug("Ignore the merge request event", slog.String("action", attributes.Action))
+		return nil
+	}
+	mergeRequestID := strconv.Itoa(attributes.IID)
+
+	validator := func(repo *store.RepositoryMessage) (bool, error) {
+		return token == repo.WebhookSecretToken, nil
+	}
+	review, err := s.sqlReviewPullRequest(ctx, webhookEndpointID, strconv.Itoa(mrEvent.Project.ID), "refs/heads/"+attributes.TargetBranch, mergeRequestID, validator)
+	if err != nil || review == nil {
+		return err
+	}
+	repo := review.repo
+
+	commitStatus := &gitlab.CommitStatus{
+		State:       gitlab.CommitStateSuccess,
+		Name:        sqlReviewStatusContext,
+		TargetURL:   review.externalURL,
+		Description: getSQLReviewStatusDescription(review.status),
+	}
+	if review.status == advisor.Error {
+		commitStatus.State = gitlab.CommitStateFailed
+	}
+	if err := gitlab.CreateCommitStatus(ctx, review.oauthContext, repo.vcs.InstanceURL, repo.repository.ExternalID, attributes.LastCommit.ID, commitStatus); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create commit status").SetInternal(err)
+	}
+
+	// Resolve the diff discussions of the earlier reviews, as the advices on the new commits are commented again.
+	discussions, err := gitlab.ListMergeRequestDiscussions(ctx, review.oauthContext, repo.vcs.InstanceURL, repo.repository.ExternalID, mergeRequestID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to list merge request discussions").SetInternal(err)
+	}
+	var overviewNote *gitlab.MergeRequestNote
+	for _, discussion := range discussions {
+		if len(discussion.Notes) == 0 || !strings.Contains(discussion.Notes[0].Body, sqlReviewCommentMarker) {
+			continue
+		}
+		note := discussion.Notes[0]
+		if note.Position == nil {
+			overviewNote = note
+			continue
+		}
+		if !note.Resolvable || note.Resolved {
+			continue
+		}
+		if err := gitlab.ResolveMergeRequestDiscussion(ctx, review.oauthContext, repo.vcs.InstanceURL, repo.repository.ExternalID, mergeRequestID, discussion.ID); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to resolve merge request discussion").SetInternal(err)
+		}
+	}
+
+	var overviewComments []*sqlReviewComment
+	if len(review.comments) > 0 {
+		mergeRequest, err := gitlab.GetMergeRequest(ctx, review.oauthContext, repo.vcs.InstanceURL, repo.repository.ExternalID, mergeRequestID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get merge request").SetInternal(err)
+		}
+		// GitLab rejects the diff discussion if the advice is on the unchanged lines, collect them into the overview discussion.
+		for _, comment := range review.comments {
+			if err := gitlab.CreateMergeRequestDiscussion(ctx, review.oauthContext, repo.vcs.InstanceURL, repo.repository.ExternalID, mergeRequestID, &gitlab.MergeRequestDiscussionCreate{
+				Body: comment.body() + "\n\n" + sqlReviewCommentMarker,
+				Position: &gitlab.DiscussionPosition{
+					BaseSHA:      mergeRequest.DiffRefs.BaseSHA,
+					StartSHA:     mergeRequest.DiffRefs.StartSHA,
+					HeadSHA:      mergeRequest.DiffRefs.HeadSHA,
+					PositionType: "text",
+					NewPath:      comment.path,
+					NewLine:      comment.line,
+				},
+			}); err != nil {
+				slog.Debug("Failed to create merge request diff discussion",
+					slog.String("file", comment.path),
+					slog.Int("line", comment.line),
+					log.BBError(err),
+				)
+				overviewComments = append(overviewComments, comment)
+			}
+		}
+	}
+
+	summary := formatSQLReviewSummary(len(review.comments), overviewComments) + "\n\n" + sqlReviewCommentMarker
+	if overviewNote != nil {
+		if err := gitlab.UpdateMergeRequestNote(ctx, review.oauthContext, repo.vcs.InstanceURL, repo.repository.ExternalID, mergeRequestID, overviewNote.ID, summary); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to update merge request note").SetInternal(err)
+		}
+		return nil
+	}
+	if len(review.comments) > 0 {
+		if err := gitlab.CreateMergeRequestDiscussion(ctx, review.oauthContext, repo.vcs.InstanceURL, repo.repository.ExternalID, mergeRequestID, &gitlab.MergeRequestDiscussionCreate{
+			Body: summary,
+		}); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create merge request discussion").SetInternal(err)
+		}
+	}
+	return nil
+}
+
+// pullRequestSQLReview is the SQL review result of the pull request.
+type pullRequestSQLReview struct {
+	repo         *repoInfo
+	oauthContext *common.OauthContext
+	externalURL  string
+	status       advisor.Status
+	comments     []*sqlReviewComment
+}
+
+// sqlReviewPullRequest takes the SQL review for the pull request from the webhook event.
+// It returns nil if no repository with the native SQL review enabled matches the event.
+func (s *Service) sqlReviewPullRequest(ctx context.Context, webhookEndpointID, repositoryID, targetRef, pullRequestID string, validator repositoryFilter) (*pullRequestSQLReview, error) {
+	setting, err := s.store.GetWorkspaceGeneralSetting(ctx)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to find workspace setting").SetInternal(err)
+	}
+
+	filter := func(repo *store.RepositoryMessage) (bool, error) {
+		ok, err := validator(repo)
+		if err != nil || !ok {
+			return false, err
+		}
+		if !repo.EnableNativeSQLReview {
+			slog.Debug("Skip repository as the native SQL review is not enabled.",
+				slog.Int("repository_id", repo.UID),
+				slog.String("repository_external_id", repo.ExternalID),
+			)
+			return false, nil
+		}
+
+		return isWebhookEventBranch(targetRef, repo.BranchFilter)
+	}
+	repositoryList, err := s.filterRepository(ctx, webhookEndpointID, repositoryID, filter)
+	if err != nil {
+		return nil, err
+	}
+	if len(repositoryList) == 0 {
+		slog.Debug("Empty handle repo list. Ignore this pull request event.")
+		return nil, nil
+	}
+	repo := repositoryList[0]
+	oauthContext := &common.OauthContext{
+		ClientID:     repo.vcs.ApplicationID,
+		ClientSecret: repo.vcs.Secret,
+		AccessToken:  repo.repository.AccessToken,
+		RefreshToken: repo.repository.RefreshToken,
+		Refresher:    utils.RefreshToken(ctx, s.store, repo.repository.WebURL),
+		RedirectURL:  fmt.Sprintf("%s/oauth/callback", setting.ExternalUrl),
+	}
+
+	prFiles, err := vcs.Get(repo.vcs.Type, vcs.ProviderConfig{}).ListPullRequestFile(
+		ctx,
+		oauthContext,
+		repo.vcs.InstanceURL,
+		repo.repository.ExternalID,
+		pullRequestID,
+	)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to list pull request file").SetInternal(err)
+	}
+
+	sqlFileName2Advice, err := s.sqlAdviceForPullRequestFiles(ctx, oauthContext, repositoryList, prFiles, setting.ExternalUrl)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to take SQL review for pull request files").SetInternal(err)
+	}
+	status, comments := convertSQLAdviceToReviewComments(sqlFileName2Advice)
+
+	slog.Debug("SQL review finished",
+		slog.String("pull_request", pullRequestID),
+		slog.String("status", string(status)),
+		slog.Int("comments", len(comments)),
+		slog.String("repository_id", repo.repository.ExternalID),
+		slog.String("vcs", string(repo.vcs.Type)),
+	)
+	return &pullRequestSQLReview{
+		repo:         repo,
+		oauthContext: oauthContext,
+		externalURL:  setting.ExternalUrl,
+		status:       status,
+		comments:     comments,
+	}, nil
+}
+
+// sqlAdviceForPullRequestFiles takes the SQL review for the SQL files and the mybatis mapper files changed in the pull request.
+func (s *Service) sqlAdviceForPullRequestFiles(ctx context.Context, oauthContext *common.OauthContext, repositoryList []*repoInfo, prFiles []*vcs.PullRequestFile, externalURL string) (map[string][]advisor.Advice, error) {
+	repo := repositoryList[0]
+	sqlFileName2Advice := s.sqlAdviceForSQLFiles(ctx, oauthContext, repositoryList, prFiles, externalURL)
+
+	if s.licenseService.IsFeatureEnabled(api.FeatureMybatisSQLReview) == nil {
+		// If the commit file list contains the file which extension is xml and the content
+		// contains "https://mybatis.org/dtd/mybatis-3-mapper.dtd", we will try to apply
+		// sql-review to it.
+		// To apply sql-review to it, proceed as follows:
+		// 1. Look in the sibling and parent directories for directories containing similar
+		// <!DOCTYPE configuration
+		//   PUBLIC "-//mybatis.org//DTD Config 3.0//EN"
+		//   "https://mybatis.org/dtd/mybatis-3-config.dtd">
+		// of the xml file
+		// 2. If we can find it, then we will extract the sql from the mapper xml
+		// 3. match the environments in the configuration xml, look for the sql-review policy in the environment and apply it.
+		// The Java mapper interfaces with mybatis statement annotations are converted to the mapper xml, and the
+		// configuration xml is looked up from the resources directory.
+		var isMybatisMapperXMLRegex = regexp.MustCompile(`(?i)http(s)?://mybatis\.org/dtd/mybatis-3-mapper\.dtd`)
+		var isMybatisAnnotationJavaRegex = regexp.MustCompile(`org\.apache\.ibatis\.annotations`)
+
+		mybatisMapperXMLFiles := make(map[string]string)
+		// annotationLineMappings is the map from the Java file path to the line mapping from the converted mapper xml to the Java file.
+		annotationLineMappings := make(map[string]map[int]int)
+		var commitID string
+		for _, prFile := range prFiles {
+			if !strings.HasSuffix(prFile.Path, ".xml") && !strings.HasSuffix(prFile.Path, ".java") {
+				continue
+			}
+			fileContent, err := vcs.Get(repo.vcs.Type, vcs.ProviderConfig{}).ReadFileContent(
+				ctx,
+				oauthContext,
+				repo.vcs.InstanceURL,
+				repo.repository.ExternalID,
+				prFile.Path,
+				vcs.RefInfo{
+					RefType: vcs.RefTypeCommit,
+					RefName: prFile.LastCommitID,
+				},
+			)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to read file content %s", prFile.Path)
+			}
+			if strings.HasSuffix(prFile.Path, ".java") {
+				if !isMybatisAnnotationJavaRegex.MatchString(fileContent) {
+					continue
+				}
+				mapper, err := annotationparser.ParseMapper(fileContent)
+				if err != nil {
+					slog.Warn("Failed to parse mybatis annotations", slog.String("file", prFile.Path), log.BBError(err))
+					continue
+				}
+				if len(mapper.Statements) == 0 {
+					continue
+				}
+				mapperXML, lineMapping := mapper.BuildMapperXML()
+				mybatisMapperXMLFiles[prFile.Path] = mapperXML
+				annotationLineMappings[prFile.Path] = lineMapping
+				commitID = prFile.LastCommitID
+				continue
+			}
+			if !isMybatisMapperXMLRegex.MatchString(fileContent) {
+				continue
+			}
+			mybatisMapperXMLFiles[prFile.Path] = fileContent
+			commitID = prFile.LastCommitID
+		}
+		if len(mybatisMapperXMLFiles) > 0 {
+			mapperAdvices, err := s.sqlAdviceForMybatisMapperFiles(ctx, oauthContext, mybatisMapperXMLFiles, annotationLineMappings, commitID, repo)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to get sql advice for mybatis mapper files")
+			}
+			for filename, mapperAdvice := range mapperAdvices {
+				sqlFileName2Advice[filename] = mapperAdvice
+			}
+		}
+	}
+	return sqlFileName2Advice, nil
+}
+
+func (s *Service) sqlAdviceForMybatisMapperFiles(ctx context.Context, oauthContext *common.OauthContext, mybatisMapperContent map[string]string, annotationLineMappings map[string]map[int]int, commitID string, repoInfo *repoInfo) (map[string][]advisor.Advice, error) {
 	if len(mybatisMapperContent) == 0 {
 		return map[string][]advisor.Advice{}, nil
 	}
@@ -744,12 +1393,16 @@ func (s *Service) sqlAdviceForMybatisMapperFiles(ctx context.Context, oauthConte
 	}
 
 	sqlCheckAdvices := make(map[string][]advisor.Advice)
-	mybatisMapperXMLFileData, err := buildMybatisMapperXMLFileData(ctx, oauthContext, repoInfo, commitID, mybatisMapperContent)
+	mybatisMapperXMLFileData, repositoryMapperFiles, err := buildMybatisMapperXMLFileData(ctx, oauthContext, repoInfo, commitID, mybatisMapperContent)
 	if err != nil {
 		return nil, errors.Wrapf(err, "Failed to build mybatis mapper xml file data")
 	}
+	// The <sql> elements in all the mapper files can be included by the fully qualified id.
+	sqlFragments := extractMybatisSQLFragments(repositoryMapperFiles)
 	var wg sync.WaitGroup
 	for _, mybatisMapperXMLFile := range mybatisMapperXMLFileData {
+		mybatisMapperXMLFile.sqlFragments = sqlFragments
+		mybatisMapperXMLFile.annotationLineMapping = annotationLineMappings[mybatisMapperXMLFile.mapperPath]
 		slog.Debug("Mybatis mapper xml file data",
 			slog.String("mapper file", mybatisMapperXMLFile.mapperPath),
 			slog.String("config file", mybatisMapperXMLFile.configPath),
@@ -829,7 +1482,7 @@ func (s *Service) sqlAdviceForMybatisMapperFile(ctx context.Context, datum *myba
 					return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to get empty catalog").SetInternal(err)
 				}
 
-				mybatisSQLs, lineMapping, err := extractMybatisMapperSQL(datum.mapperContent, engineType)
+				mybatisSQLs, lineMapping, err := extractMybatisMapperSQL(datum.mapperContent, engineType, getMybatisDatabaseID(conf, engineType), datum.sqlFragments)
 				if err != nil {
 					return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to extract mybatis mapper sql").SetInternal(err)
 				}
@@ -849,6 +1502,10 @@ func (s *Service) sqlAdviceForMybatisMapperFile(ctx context.Context, datum *myba
 							break
 						}
 					}
+					// Remap the line number of the converted mapper xml to the annotation in the Java file.
+					if line, ok := datum.annotationLineMapping[advice.Line]; ok {
+						advice.Line = line
+					}
 					result = append(result, advice)
 				}
 			}
@@ -1195,7 +1852,26 @@ func (s *Service) processPushEvent(ctx context.Context, oauthContext *common.Oau
 		return nil, errors.Errorf("empty repository list")
 	}
 
+	repo := repoInfoList[0]
+
 	distinctFileList := baseVCSPushEvent.GetDistinctFileList()
+	// The before commit ID is all zeros when the branch is just created and contains no commits yet.
+	if baseVCSPushEvent.Before != strings.Repeat("0", 40) && len(baseVCSPushEvent.CommitList) > 0 {
+		fileDiffList, err := vcs.Get(repo.vcs.Type, vcs.ProviderConfig{}).GetDiffFileList(
+			ctx,
+			oauthContext,
+			repo.vcs.InstanceURL,
+			repo.repository.ExternalID,
+			baseVCSPushEvent.Before,
+			baseVCSPushEvent.After,
+		)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "failed to get file diff list for repository %s", repo.repository.ExternalID)
+		}
+		distinctFileList = filterFilesByCommitsDiff(distinctFileList, fileDiffList)
+		// The removed files are the migration files deleted or reverted in the push event.
+		distinctFileList = append(distinctFileList, getRemovedFileList(baseVCSPushEvent, fileDiffList)...)
+	}
 	if len(distinctFileList) == 0 {
 		var commitIDs []string
 		for _, c := range baseVCSPushEvent.CommitList {
@@ -1208,33 +1884,38 @@ func (s *Service) processPushEvent(ctx context.Context, oauthContext *common.Oau
 		return nil, nil
 	}
 
-	repo := repoInfoList[0]
-
-	filteredDistinctFileList, err := func() ([]vcs.DistinctFileItem, error) {
-		// The before commit ID is all zeros when the branch is just created and contains no commits yet.
-		if baseVCSPushEvent.Before == strings.Repeat("0", 40) {
-			return distinctFileList, nil
-		}
-		return filterFilesByCommitsDiff(ctx, oauthContext, repo, distinctFileList, baseVCSPushEvent.Before, baseVCSPushEvent.After)
-	}()
-	if err != nil {
-		return nil, errors.Wrapf(err, "failed to filtered distinct files by commits diff")
-	}
-
-	repoID2FileItemList := groupFileInfoByRepo(filteredDistinctFileList, repoInfoList)
+	repoID2FileItemList := groupFileInfoByRepo(distinctFileList, repoInfoList)
 
 	var createdMessageList []string
 	for _, fileInfoListInRepo := range repoID2FileItemList {
+		fileInfoListInRepo, activityCreateList := s.readMigrationDependencies(ctx, oauthContext, baseVCSPushEvent, fileInfoListInRepo)
+		for _, activityCreate := range activityCreateList {
+			if _, err := s.activityManager.CreateActivity(ctx, activityCreate, &activity.Metadata{}); err != nil {
+				slog.Warn("Failed to create project activity for the ignored repository files", log.BBError(err))
+			}
+		}
 		// There are possibly multiple files in the push event.
 		// Each file corresponds to a (database name, schema version) pair.
-		// We want the migration statements are sorted by the file's schema version, and grouped by the database name.
-		dbName2FileInfoList := groupFileInfoByDatabase(fileInfoListInRepo)
-		for _, fileInfoListInDB := range dbName2FileInfoList {
-			fileInfoListSorted := sortFilesBySchemaVersion(fileInfoListInDB)
+		// We want the migration statements are sorted by the file's schema version and the declared dependencies,
+		// and grouped by the database name unless the databases depend on each other.
+		for _, fileInfoGroup := range groupFileInfoByDependency(fileInfoListInRepo) {
+			fileInfoListSorted := fileInfoGroup.fileInfoList
 			repoInfo := fileInfoListSorted[0].repoInfo
 			pushEvent := baseVCSPushEvent
 			pushEvent.VCSType = repoInfo.vcs.Type
 			pushEvent.BaseDirectory = repoInfo.repository.BaseDirectory
+			if fileInfoGroup.err == nil {
+				fileInfoGroup.err = s.checkUnresolvedDependencies(ctx, fileInfoListSorted)
+			}
+			if fileInfoGroup.err != nil {
+				for _, fileInfo := range fileInfoListSorted {
+					activityCreate := getIgnoredFileActivityCreate(repoInfo.project.UID, pushEvent, fileInfo.item.FileName, fileInfoGroup.err)
+					if _, err := s.activityManager.CreateActivity(ctx, activityCreate, &activity.Metadata{}); err != nil {
+						slog.Warn("Failed to create project activity for the ignored repository files", log.BBError(err))
+					}
+				}
+				continue
+			}
 			createdMessage, created, activityCreateList, err := s.processFilesInProject(
 				ctx,
 				oauthContext,
@@ -1273,34 +1954,50 @@ func (s *Service) processPushEvent(ctx context.Context, oauthContext *common.Oau
 // In that case, the commits in the push event contains files which are not added in this PR/MR.
 // We use the compare API to get the file diffs and filter files by the diffs.
 // TODO(dragonly): generate distinct file change list from the commits diff instead of filter.
-func filterFilesByCommitsDiff(
-	ctx context.Context,
-	oauthContext *common.OauthContext,
-	repoInfo *repoInfo,
-	distinctFileList []vcs.DistinctFileItem,
-	beforeCommit, afterCommit string,
-) ([]vcs.DistinctFileItem, error) {
-	fileDiffList, err := vcs.Get(repoInfo.vcs.Type, vcs.ProviderConfig{}).GetDiffFileList(
-		ctx,
-		oauthContext,
-		repoInfo.vcs.InstanceURL,
-		repoInfo.repository.ExternalID,
-		beforeCommit,
-		afterCommit,
-	)
-	if err != nil {
-		return nil, errors.WithMessagef(err, "failed to get file diff list for repository %s", repoInfo.repository.ExternalID)
-	}
+func filterFilesByCommitsDiff(distinctFileList []vcs.DistinctFileItem, fileDiffList []vcs.FileDiff) []vcs.DistinctFileItem {
 	var filteredDistinctFileList []vcs.DistinctFileItem
 	for _, file := range distinctFileList {
 		for _, diff := range fileDiffList {
-			if file.FileName == diff.Path {
+			// The file may be modified and then removed in the push event.
+			if file.FileName == diff.Path && diff.Type != vcs.FileDiffTypeRemoved {
 				filteredDistinctFileList = append(filteredDistinctFileList, file)
 				break
 			}
 		}
 	}
-	return filteredDistinctFileList, nil
+	return filteredDistinctFileList
+}
+
+// getRemovedFileList returns the files removed between the before and after commits of the push event.
+// The push event does not tell which commit removes the file, so we use the head commit.
+func getRemovedFileList(pushEvent vcs.PushEvent, fileDiffList []vcs.FileDiff) []vcs.DistinctFileItem {
+	headCommit := pushEvent.CommitList[len(pushEvent.CommitList)-1]
+	var removedFileList []vcs.DistinctFileItem
+	for _, diff := range fileDiffList {
+		if diff.Type != vcs.FileDiffTypeRemoved {
+			continue
+		}
+		removedFileList = append(removedFileList, vcs.DistinctFileItem{
+			CreatedTs: headCommit.CreatedTs,
+			Commit:    headCommit,
+			FileName:  diff.Path,
+			ItemType:  vcs.FileItemTypeRemoved,
+			IsYAML:    strings.HasSuffix(diff.Path, ".yml"),
+		})
+	}
+	return removedFileList
+}
+
+// revertCommitRegex matches the message generated by "git revert".
+var revertCommitRegex = regexp.MustCompile(`This reverts commit ([0-9a-f]{7,40})`)
+
+// getRevertedCommitID returns the reverted commit ID if the commit is generated by "git revert".
+func getRevertedCommitID(commit vcs.Commit) string {
+	matches := revertCommitRegex.FindStringSubmatch(commit.Message)
+	if len(matches) != 2 {
+		return ""
+	}
+	return matches[1]
 }
 
 type fileInfo struct {
@@ -1308,6 +2005,14 @@ type fileInfo struct {
 	migrationInfo *db.MigrationInfo
 	fType         fileType
 	repoInfo      *repoInfo
+	// content is the file content read for parsing the front-matter, nil if it is not read yet.
+	content *string
+	// dependencies is the dependencies declared in the front-matter of the migration file.
+	dependencies []*migrationDependency
+	// unresolvedDependencies is the dependencies not in the push event, which should have been applied before.
+	unresolvedDependencies []*migrationDependency
+	// level is the order of the plan step for the migration file in the same environment.
+	level int
 }
 
 func groupFileInfoByDatabase(fileInfoList []fileInfo) map[string][]fileInfo {
@@ -1453,7 +2158,30 @@ func (s *Service) processFilesInProject(ctx context.Context, oauthContext *commo
 	var fileNameList []string
 
 	creatorID := s.getIssueCreatorID(ctx, pushEvent.CommitList[0].AuthorEmail)
+	// Roll back the removed migration files before applying the new ones, and the later version is rolled back first.
+	for i := len(fileInfoList) - 1; i >= 0; i-- {
+		fileInfo := fileInfoList[i]
+		if fileInfo.item.ItemType != vcs.FileItemTypeRemoved || fileInfo.fType != fileTypeMigration {
+			continue
+		}
+		migrationDetailListForFile, activityCreateListForFile := s.prepareRollbackFromRemovedFile(ctx, repoInfo, pushEvent, fileInfo)
+		activityCreateList = append(activityCreateList, activityCreateListForFile...)
+		if len(migrationDetailListForFile) == 0 {
+			continue
+		}
+		issueName := fmt.Sprintf(issueNameTemplate, fileInfo.migrationInfo.Database, "Rollback schema", fmt.Sprintf("revert version %s", fileInfo.migrationInfo.Version.Version))
+		issueDescription := getRollbackIssueDescription(pushEvent, strings.TrimPrefix(fileInfo.item.FileName, repoInfo.repository.BaseDirectory+"/"))
+		if err := s.createIssueFromMigrationDetailsV2(ctx, repoInfo.project, issueName, issueDescription, pushEvent, creatorID, migrationDetailListForFile); err != nil {
+			return "", false, activityCreateList, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to create issue %s, error %v", issueName, err)).SetInternal(err)
+		}
+		createdIssueList = append(createdIssueList, issueName)
+	}
+
 	for _, fileInfo := range fileInfoList {
+		if fileInfo.item.ItemType == vcs.FileItemTypeRemoved {
+			slog.Debug("Ignored removed file", slog.String("fileName", fileInfo.item.FileName))
+			continue
+		}
 		if fileInfo.fType == fileTypeSchema {
 			if fileInfo.repoInfo.project.SchemaChangeType == api.ProjectSchemaChangeTypeSDL {
 				// Create one issue per schema file for SDL project.
@@ -1479,6 +2207,9 @@ func (s *Service) processFilesInProject(ctx context.Context, oauthContext *commo
 			// 2) We may have a limitation in SDL implementation.
 			// 3) User just wants to break the glass.
 			migrationDetailListForFile, activityCreateListForFile := s.prepareIssueFromFile(ctx, oauthContext, repoInfo, pushEvent, fileInfo)
+			for _, migrationDetail := range migrationDetailListForFile {
+				migrationDetail.level = fileInfo.level
+			}
 			activityCreateList = append(activityCreateList, activityCreateListForFile...)
 			migrationDetailList = append(migrationDetailList, migrationDetailListForFile...)
 			if len(migrationDetailListForFile) != 0 {
@@ -1488,7 +2219,10 @@ func (s *Service) processFilesInProject(ctx context.Context, oauthContext *commo
 	}
 
 	if len(migrationDetailList) == 0 {
-		return "", len(createdIssueList) != 0, activityCreateList, nil
+		if len(createdIssueList) == 0 {
+			return "", false, activityCreateList, nil
+		}
+		return fmt.Sprintf("Created issue %q from push event", strings.Join(createdIssueList, ",")), true, activityCreateList, nil
 	}
 
 	// Create one issue per push event for DDL project, or non-schema files for SDL project.
@@ -1499,8 +2233,15 @@ func (s *Service) processFilesInProject(ctx context.Context, oauthContext *commo
 			break
 		}
 	}
-	// The files are grouped by database names before calling this function, so they have the same database name here.
-	databaseName := fileInfoList[0].migrationInfo.Database
+	// The files are grouped by database names before calling this function, so they have the same database name here
+	// unless the databases depend on each other.
+	var databaseNameList []string
+	for _, fileInfo := range fileInfoList {
+		if !slices.Contains(databaseNameList, fileInfo.migrationInfo.Database) {
+			databaseNameList = append(databaseNameList, fileInfo.migrationInfo.Database)
+		}
+	}
+	databaseName := strings.Join(databaseNameList, ", ")
 	description := strings.ReplaceAll(fileInfoList[0].migrationInfo.Description, "_", " ")
 	issueName := fmt.Sprintf(issueNameTemplate, databaseName, migrateType, description)
 	issueDescription := fmt.Sprintf("By VCS files:\n\n%s\n", strings.Join(fileNameList, "\n"))
@@ -1519,6 +2260,12 @@ func (s *Service) processFilesInBatchProject(ctx context.Context, oauthContext *
 
 	creatorID := s.getIssueCreatorID(ctx, pushEvent.CommitList[0].AuthorEmail)
 	for _, fileInfo := range fileInfoList {
+		if fileInfo.item.ItemType == vcs.FileItemTypeRemoved {
+			if fileInfo.fType == fileTypeMigration {
+				activityCreateList = append(activityCreateList, getIgnoredFileActivityCreate(repoInfo.project.UID, pushEvent, fileInfo.item.FileName, errors.New("rollback for the removed migration file is not supported in a tenant project")))
+			}
+			continue
+		}
 		if fileInfo.fType == fileTypeSchema {
 			if fileInfo.repoInfo.project.SchemaChangeType == api.ProjectSchemaChangeTypeSDL {
 				// Create one issue per schema file for SDL project.
@@ -1610,7 +2357,14 @@ func (s *Service) createIssueFromMigrationDetailsV2(ctx context.Context, project
 		for i, environment := range environments {
 			orderIndex[environment.Order] = i
 		}
-		allSteps := make([]*v1pb.Plan_Step, len(environments))
+		// The steps are ordered by the environment, and then by the level of the migration in the same environment.
+		levelCount := 1
+		for _, migrationDetail := range migrationDetailList {
+			if migrationDetail.level >= levelCount {
+				levelCount = migrationDetail.level + 1
+			}
+		}
+		allSteps := make([]*v1pb.Plan_Step, len(environments)*levelCount)
 		for _, migrationDetail := range migrationDetailList {
 			if migrationDetail.databaseID == 0 {
 				// TODO(d): should never reach this.
@@ -1631,10 +2385,11 @@ func (s *Service) createIssueFromMigrationDetailsV2(ctx context.Context, project
 				return errors.Errorf("environment %q not found", database.EffectiveEnvironmentID)
 			}
 
-			step := allSteps[orderIndex[environment.Order]]
+			stepIndex := orderIndex[environment.Order]*levelCount + migrationDetail.level
+			step := allSteps[stepIndex]
 			if step == nil {
-				allSteps[orderIndex[environment.Order]] = &v1pb.Plan_Step{}
-				step = allSteps[orderIndex[environment.Order]]
+				allSteps[stepIndex] = &v1pb.Plan_Step{}
+				step = allSteps[stepIndex]
 			}
 			step.Specs = append(step.Specs, &v1pb.Plan_Spec{
 				Config: &v1pb.Plan_Spec_ChangeDatabaseConfig{
@@ -1960,17 +2715,22 @@ func (s *Service) prepareIssueFromFile(
 	pushEvent vcs.PushEvent,
 	fileInfo fileInfo,
 ) ([]*migrationDetail, []*store.ActivityMessage) {
-	content, err := s.readFileContent(ctx, oauthContext, pushEvent, repoInfo, fileInfo.item.FileName)
-	if err != nil {
-		return nil, []*store.ActivityMessage{
-			getIgnoredFileActivityCreate(
-				repoInfo.project.UID,
-				pushEvent,
-				fileInfo.item.FileName,
-				errors.Wrap(err, "Failed to read file content"),
-			),
+	// The content may have been read for parsing the front-matter.
+	if fileInfo.content == nil {
+		content, err := s.readFileContent(ctx, oauthContext, pushEvent, repoInfo, fileInfo.item.FileName)
+		if err != nil {
+			return nil, []*store.ActivityMessage{
+				getIgnoredFileActivityCreate(
+					repoInfo.project.UID,
+					pushEvent,
+					fileInfo.item.FileName,
+					errors.Wrap(err, "Failed to read file content"),
+				),
+			}
 		}
+		fileInfo.content = &content
 	}
+	content := *fileInfo.content
 
 	sheetPayload := &storepb.SheetPayload{
 		VcsPayload: &storepb.SheetPayload_VCSPayload{
@@ -2005,8 +2765,7 @@ func (s *Service) prepareIssueFromFile(
 		}
 
 		var migrationFile MigrationFileYAML
-		err = yaml.Unmarshal([]byte(content), &migrationFile)
-		if err != nil {
+		if err := yaml.Unmarshal([]byte(content), &migrationFile); err != nil {
 			return nil, []*store.ActivityMessage{
 				getIgnoredFileActivityCreate(
 					repoInfo.project.UID,
@@ -2111,6 +2870,158 @@ func (s *Service) prepareIssueFromFile(
 	return nil, nil
 }
 
+// prepareRollbackFromRemovedFile returns the migration details to roll back the databases to the schema before
+// the version of the removed migration file. The rollback statement is the diff from the schema after the version
+// to the schema before the version, both recorded in the change history.
+func (s *Service) prepareRollbackFromRemovedFile(ctx context.Context, repoInfo *repoInfo, pushEvent vcs.PushEvent, fileInfo fileInfo) ([]*migrationDetail, []*store.ActivityMessage) {
+	if fileInfo.migrationInfo.Type != db.Migrate {
+		return nil, []*store.ActivityMessage{
+			getIgnoredFileActivityCreate(
+				repoInfo.project.UID,
+				pushEvent,
+				fileInfo.item.FileName,
+				errors.Errorf("only the schema migration can be rolled back, but the removed file is %s", fileInfo.migrationInfo.Type),
+			),
+		}
+	}
+	databases, err := s.findProjectDatabases(ctx, repoInfo.project.UID, fileInfo.migrationInfo.Database, fileInfo.migrationInfo.Environment)
+	if err != nil {
+		activityCreate := getIgnoredFileActivityCreate(repoInfo.project.UID, pushEvent, fileInfo.item.FileName, errors.Wrap(err, "Failed to find project databases"))
+		return nil, []*store.ActivityMessage{activityCreate}
+	}
+
+	schemaVersion := model.Version{Version: fmt.Sprintf("%s-%s", fileInfo.migrationInfo.Version.Version, fileInfo.migrationInfo.Type.GetVersionTypeSuffix())}
+	rollbackVersion := model.Version{Version: fmt.Sprintf("%s-rollback-%s", schemaVersion.Version, common.DefaultMigrationVersion().Version)}
+	sheetPayload := &storepb.SheetPayload{
+		VcsPayload: &storepb.SheetPayload_VCSPayload{
+			PushEvent: utils.ConvertVcsPushEvent(&pushEvent),
+		},
+	}
+	var migrationDetailList []*migrationDetail
+	var activityCreateList []*store.ActivityMessage
+	for _, database := range databases {
+		statement, err := s.getRollbackStatement(ctx, database, schemaVersion)
+		if err != nil {
+			activityCreateList = append(activityCreateList, getIgnoredFileActivityCreate(
+				repoInfo.project.UID,
+				pushEvent,
+				fileInfo.item.FileName,
+				errors.Wrapf(err, "Failed to roll back database %q", database.DatabaseName),
+			))
+			continue
+		}
+		if statement == "" {
+			slog.Debug("No rollback statement for the removed migration file",
+				slog.String("fileName", fileInfo.item.FileName),
+				slog.String("database", database.DatabaseName),
+			)
+			continue
+		}
+
+		sheet, err := s.store.CreateSheet(ctx, &store.SheetMessage{
+			CreatorID:  api.SystemBotID,
+			ProjectUID: repoInfo.project.UID,
+			Name:       fmt.Sprintf("Rollback %s", fileInfo.item.FileName),
+			Statement:  statement,
+			Visibility: store.ProjectSheet,
+			Source:     store.SheetFromBytebaseArtifact,
+			Type:       store.SheetForSQL,
+			Payload:    sheetPayload,
+		})
+		if err != nil {
+			activityCreate := getIgnoredFileActivityCreate(repoInfo.project.UID, pushEvent, fileInfo.item.FileName, errors.Wrap(err, "Failed to create a sheet"))
+			return nil, append(activityCreateList, activityCreate)
+		}
+		migrationDetailList = append(migrationDetailList,
+			&migrationDetail{
+				migrationType: db.Migrate,
+				databaseID:    database.UID,
+				sheetID:       sheet.UID,
+				schemaVersion: rollbackVersion,
+			},
+		)
+	}
+	return migrationDetailList, activityCreateList
+}
+
+// getRollbackStatement returns the statement to revert the schema change of the given version.
+// It returns an empty statement if the version has not been applied to the database, and an error if there are
+// later schema changes, which may depend on the version and should be rolled back by the users themselves.
+func (s *Service) getRollbackStatement(ctx context.Context, database *store.DatabaseMessage, schemaVersion model.Version) (string, error) {
+	instance, err := s.store.GetInstanceV2(ctx, &store.FindInstanceMessage{ResourceID: &database.InstanceID})
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get instance")
+	}
+	if instance == nil {
+		return "", errors.Errorf("instance %q not found", database.InstanceID)
+	}
+	history, err := s.getAppliedChangeHistory(ctx, instance, database, schemaVersion, true /* showFull */)
+	if err != nil {
+		return "", err
+	}
+	if history == nil {
+		return "", nil
+	}
+	histories, err := s.store.ListInstanceChangeHistory(ctx, &store.FindInstanceChangeHistoryMessage{
+		InstanceID: &instance.UID,
+		DatabaseID: &database.UID,
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "failed to list change history")
+	}
+	if later := findLaterSchemaChange(histories, history); later != nil {
+		return "", errors.Errorf("version %s has been applied after version %s, please roll back the schema manually", later.Version.Version, schemaVersion.Version)
+	}
+	statement, err := base.SchemaDiff(instance.Engine, history.Schema, history.SchemaPrev, store.IgnoreDatabaseAndTableCaseSensitive(instance))
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to compute the schema diff to the schema before version %s", schemaVersion.Version)
+	}
+	return statement, nil
+}
+
+// findLaterSchemaChange returns the schema change applied after the history in the histories ordered by the sequence descendingly.
+func findLaterSchemaChange(histories []*store.InstanceChangeHistoryMessage, history *store.InstanceChangeHistoryMessage) *store.InstanceChangeHistoryMessage {
+	for _, later := range histories {
+		if later.Sequence <= history.Sequence {
+			break
+		}
+		if later.Status == db.Done && later.Type != db.Data {
+			return later
+		}
+	}
+	return nil
+}
+
+// getAppliedChangeHistory returns the change history of the given version if it has been applied to the database successfully.
+func (s *Service) getAppliedChangeHistory(ctx context.Context, instance *store.InstanceMessage, database *store.DatabaseMessage, schemaVersion model.Version, showFull bool) (*store.InstanceChangeHistoryMessage, error) {
+	limit := 1
+	list, err := s.store.ListInstanceChangeHistory(ctx, &store.FindInstanceChangeHistoryMessage{
+		InstanceID: &instance.UID,
+		DatabaseID: &database.UID,
+		Version:    &schemaVersion,
+		Limit:      &limit,
+		ShowFull:   showFull,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list change history of version %s", schemaVersion.Version)
+	}
+	if len(list) == 0 || list[0].Status != db.Done {
+		return nil, nil
+	}
+	return list[0], nil
+}
+
+// getRollbackIssueDescription returns the description for the issue to roll back the removed migration file.
+func getRollbackIssueDescription(pushEvent vcs.PushEvent, fileName string) string {
+	description := fmt.Sprintf("Roll back the schema change by VCS file %s, which is removed", fileName)
+	for _, commit := range pushEvent.CommitList {
+		if revertedCommitID := getRevertedCommitID(commit); revertedCommitID != "" {
+			return fmt.Sprintf("%s by commit %s reverting commit %s.\n", description, commit.ID, revertedCommitID)
+		}
+	}
+	return fmt.Sprintf("%s in commit %s.\n", description, pushEvent.CommitList[len(pushEvent.CommitList)-1].ID)
+}
+
 func (s *Service) tryUpdateTasksFromModifiedFile(ctx context.Context, databases []*store.DatabaseMessage, fileName, schemaVersion, statement string, pushEvent vcs.PushEvent) error {
 	// For modified files, we try to update the existing issue's statement.
 	for _, database := range databases {
@@ -2124,6 +3035,10 @@ func (s *Service) tryUpdateTasksFromModifiedFile(ctx context.Context, databases
 			return err
 		}
 		if len(taskList) == 0 {
+			// The file may be modified after it has been applied, which makes the schema drift from the repository.
+			if err := s.warnModifiedAppliedFile(ctx, database, fileName, schemaVersion, pushEvent); err != nil {
+				slog.Warn("Failed to warn the modified file which has been applied", slog.String("fileName", fileName), slog.Int("databaseID", database.UID), log.BBError(err))
+			}
 			continue
 		}
 		if len(taskList) > 1 {
@@ -2219,6 +3134,57 @@ func (s *Service) tryUpdateTasksFromModifiedFile(ctx context.Context, databases
 	return nil
 }
 
+// warnModifiedAppliedFile leaves a drift warning on the issue which has applied the version of the modified file.
+func (s *Service) warnModifiedAppliedFile(ctx context.Context, database *store.DatabaseMessage, fileName, schemaVersion string, pushEvent vcs.PushEvent) error {
+	instance, err := s.store.GetInstanceV2(ctx, &store.FindInstanceMessage{ResourceID: &database.InstanceID})
+	if err != nil {
+		return errors.Wrap(err, "failed to get instance")
+	}
+	if instance == nil {
+		return errors.Errorf("instance %q not found", database.InstanceID)
+	}
+	history, err := s.getAppliedChangeHistory(ctx, instance, database, model.Version{Version: schemaVersion}, false /* showFull */)
+	if err != nil {
+		return err
+	}
+	if history == nil {
+		return nil
+	}
+	if history.IssueUID == nil {
+		slog.Warn("The modified file has been applied without issue", slog.String("fileName", fileName), slog.String("database", database.DatabaseName), slog.String("schemaVersion", schemaVersion))
+		return nil
+	}
+	issue, err := s.store.GetIssueV2(ctx, &store.FindIssueMessage{UID: history.IssueUID})
+	if err != nil {
+		return errors.Wrapf(err, "failed to get issue %d", *history.IssueUID)
+	}
+	if issue == nil {
+		return errors.Errorf("issue %d not found", *history.IssueUID)
+	}
+
+	activityPayload, err := protojson.Marshal(&storepb.ActivityIssueCommentCreatePayload{
+		IssueName: issue.Title,
+	})
+	if err != nil {
+		return err
+	}
+	commit := pushEvent.CommitList[len(pushEvent.CommitList)-1]
+	if _, err := s.activityManager.CreateActivity(ctx, &store.ActivityMessage{
+		CreatorUID:   api.SystemBotID,
+		ContainerUID: issue.UID,
+		Type:         api.ActivityIssueCommentCreate,
+		Level:        api.ActivityWarn,
+		Comment: fmt.Sprintf(
+			"Schema drift: VCS file %q is modified by commit %s after version %s has been applied to database %q. The modification is not applied, please add a new migration file instead.",
+			fileName, commit.ID, schemaVersion, database.DatabaseName,
+		),
+		Payload: string(activityPayload),
+	}, &activity.Metadata{}); err != nil {
+		return errors.Wrap(err, "failed to create issue comment")
+	}
+	return nil
+}
+
 // patchTask patches the statement for a task.
 func patchTask(ctx context.Context, stores *store.Store, activityManager *activity.Manager, task *store.TaskMessage, taskPatch *api.TaskPatch, issue *store.IssueMessage) error {
 	taskPatched, err := stores.UpdateTaskV2(ctx, taskPatch)
@@ -2376,6 +3342,103 @@ func convertSQLAdviceToGitHubActionResult(adviceMap map[string][]advisor.Advice)
 	}
 }
 
+// sqlReviewComment is the SQL review advice on the line of the file, which is reported to the pull request.
+type sqlReviewComment struct {
+	path   string
+	line   int
+	advice advisor.Advice
+}
+
+// body returns the markdown content of the inline comment.
+func (c *sqlReviewComment) body() string {
+	return fmt.Sprintf("**[%s] %s (%d)**\n\n%s\n\nDoc: %s#%d",
+		c.advice.Status,
+		c.advice.Title,
+		c.advice.Code,
+		c.advice.Content,
+		sqlReviewDocs,
+		c.advice.Code,
+	)
+}
+
+// convertSQLAdviceToReviewComments will convert SQL advice map to the comments on the pull request, and returns the overall status.
+func convertSQLAdviceToReviewComments(adviceMap map[string][]advisor.Advice) (advisor.Status, []*sqlReviewComment) {
+	var comments []*sqlReviewComment
+	status := advisor.Success
+
+	fileList := getSQLAdviceFileList(adviceMap)
+	for _, filePath := range fileList {
+		adviceList := adviceMap[filePath]
+		for _, advice := range adviceList {
+			if advice.Code == 0 || advice.Status == advisor.Success {
+				continue
+			}
+
+			line := advice.Line
+			if line <= 0 {
+				line = 1
+			}
+
+			if advice.Status == advisor.Error {
+				status = advice.Status
+			} else if status != advisor.Error {
+				status = advice.Status
+			}
+
+			comments = append(comments, &sqlReviewComment{
+				path:   filePath,
+				line:   line,
+				advice: advice,
+			})
+		}
+	}
+	return status, comments
+}
+
+// formatSQLReviewComments will format the comments to the markdown list, which is used when the inline comments are not available.
+func formatSQLReviewComments(comments []*sqlReviewComment) string {
+	messageList := []string{sqlReviewCommentTitle, ""}
+	for _, comment := range comments {
+		messageList = append(messageList, fmt.Sprintf(
+			"- **[%s]** `%s#L%d`: %s (%d)\n  %s [Doc](%s#%d)",
+			comment.advice.Status,
+			comment.path,
+			comment.line,
+			comment.advice.Title,
+			comment.advice.Code,
+			comment.advice.Content,
+			sqlReviewDocs,
+			comment.advice.Code,
+		))
+	}
+	return strings.Join(messageList, "\n")
+}
+
+// formatSQLReviewSummary formats the summary comment of the SQL review with the total number of the comments,
+// and lists the comments not commented inline.
+func formatSQLReviewSummary(total int, overviewComments []*sqlReviewComment) string {
+	switch {
+	case total == 0:
+		return sqlReviewCommentTitle + "\n\nNo issues found."
+	case len(overviewComments) == 0:
+		return fmt.Sprintf("%s\n\nFound %d issue(s), see the inline comments.", sqlReviewCommentTitle, total)
+	default:
+		return formatSQLReviewComments(overviewComments)
+	}
+}
+
+// getSQLReviewStatusDescription returns the description of the commit status for the SQL review.
+func getSQLReviewStatusDescription(status advisor.Status) string {
+	switch status {
+	case advisor.Error:
+		return "SQL review failed"
+	case advisor.Warn:
+		return "SQL review passed with warnings"
+	default:
+		return "SQL review passed"
+	}
+}
+
 func getSQLAdviceFileList(adviceMap map[string][]advisor.Advice) []string {
 	fileList := []string{}
 	fileToErrorCount := map[string]int{}
@@ -2434,6 +3497,17 @@ func filterGitLabBytebaseCommit(list []gitlab.WebhookCommit) []gitlab.WebhookCom
 	return result
 }
 
+func filterGiteaBytebaseCommit(list []gitea.WebhookCommit) []gitea.WebhookCommit {
+	var result []gitea.WebhookCommit
+	for _, commit := range list {
+		if commit.Author.Name == vcs.BytebaseAuthorName && commit.Author.Email == vcs.BytebaseAuthorEmail {
+			continue
+		}
+		result = append(result, commit)
+	}
+	return result
+}
+
 func filterBitbucketBytebaseCommit(list []bitbucket.WebhookCommit) []bitbucket.WebhookCommit {
 	bytebaseRaw := fmt.Sprintf("%s <%s>", vcs.BytebaseAuthorName, vcs.BytebaseAuthorEmail)
 	var result []bitbucket.WebhookCommit
@@ -2446,6 +3520,17 @@ func filterBitbucketBytebaseCommit(list []bitbucket.WebhookCommit) []bitbucket.W
 	return result
 }
 
+func filterBitbucketDataCenterBytebaseCommit(list []bitbucketdc.Commit) []bitbucketdc.Commit {
+	var result []bitbucketdc.Commit
+	for _, commit := range list {
+		if commit.Author.Name == vcs.BytebaseAuthorName && commit.Author.EmailAddress == vcs.BytebaseAuthorEmail {
+			continue
+		}
+		result = append(result, commit)
+	}
+	return result
+}
+
 // extractDBTypeFromJDBCConnectionString will extract the DB type from JDBC connection string. Only support MySQL and Postgres for now.
 // It will return UnknownType if the DB type is not supported, and returns error if cannot parse the JDBC connection string.
 func extractDBTypeFromJDBCConnectionString(jdbcURL string) (storepb.Engine, error) {
@@ -2464,13 +3549,52 @@ func extractDBTypeFromJDBCConnectionString(jdbcURL string) (storepb.Engine, erro
 	return storepb.Engine_ENGINE_UNSPECIFIED, nil
 }
 
+// getMybatisDatabaseID returns the database id of the engine, which is declared in the <databaseIdProvider> of
+// the mybatis configuration XML. Otherwise, we use the lower case of the database product name as the convention.
+func getMybatisDatabaseID(conf *configparser.Configuration, engineType storepb.Engine) string {
+	var productName string
+	switch engineType {
+	case storepb.Engine_MYSQL:
+		productName = "MySQL"
+	case storepb.Engine_POSTGRES:
+		productName = "PostgreSQL"
+	default:
+		return ""
+	}
+	if conf != nil && conf.DatabaseIDs != nil {
+		// The statements with databaseId are ignored if the product is not declared in the provider.
+		return conf.DatabaseIDs[productName]
+	}
+	return strings.ToLower(productName)
+}
+
+// extractMybatisSQLFragments extracts the <sql> elements from the mybatis mapper XML files, the key is the fully qualified id.
+func extractMybatisSQLFragments(mapperFiles map[string]string) map[string]*ast.SQLNode {
+	sqlFragments := make(map[string]*ast.SQLNode)
+	for mapperPath, mapperContent := range mapperFiles {
+		mybatisMapperParser := mapperparser.NewParser(mapperContent)
+		if _, err := mybatisMapperParser.Parse(); err != nil {
+			slog.Debug("Failed to parse mybatis mapper xml for sql fragments", slog.String("file", mapperPath), log.BBError(err))
+			continue
+		}
+		for id, node := range mybatisMapperParser.SQLFragments() {
+			sqlFragments[id] = node
+		}
+	}
+	return sqlFragments
+}
+
 // extractMybatisMapperSQL will extract the SQL from mybatis mapper XML.
-func extractMybatisMapperSQL(mapperContent string, engineType storepb.Engine) (string, []*ast.MybatisSQLLineMapping, error) {
+//
+//	databaseID: the database id of the data source to choose the database specific statements, empty means all the statements.
+//	sqlFragments: the <sql> elements in other mapper XML files.
+func extractMybatisMapperSQL(mapperContent string, engineType storepb.Engine, databaseID string, sqlFragments map[string]*ast.SQLNode) (string, []*ast.MybatisSQLLineMapping, error) {
 	mybatisMapperParser := mapperparser.NewParser(mapperContent)
 	mybatisMapperNode, err := mybatisMapperParser.Parse()
 	if err != nil {
 		return "", nil, errors.Wrapf(err, "failed to parse mybatis mapper xml")
 	}
+	mybatisMapperParser.AddSQLFragments(sqlFragments)
 
 	var placeholder string
 	switch engineType {
@@ -2483,7 +3607,7 @@ func extractMybatisMapperSQL(mapperContent string, engineType storepb.Engine) (s
 	}
 
 	var sb strings.Builder
-	lineMapping, err := mybatisMapperNode.RestoreSQLWithLineMapping(mybatisMapperParser.NewRestoreContext().WithRestoreDataNodePlaceholder(placeholder), &sb)
+	lineMapping, err := mybatisMapperNode.RestoreSQLWithLineMapping(mybatisMapperParser.NewRestoreContext().WithRestoreDataNodePlaceholder(placeholder).WithDatabaseID(databaseID), &sb)
 	if err != nil {
 		return "", nil, errors.Wrapf(err, "failed to restore mybatis mapper xml")
 	}
@@ -2503,6 +3627,11 @@ type mybatisMapperXMLFileDatum struct {
 	// configContent is the content of the mybatis configuration XML file,
 	// it is empty if the mybatis configuration XML file is not found.
 	configContent string
+	// sqlFragments is the <sql> elements in the mapper XML files of the repository, the key is the fully qualified id.
+	sqlFragments map[string]*ast.SQLNode
+	// annotationLineMapping is the map from the line of the mapper XML converted from the Java mapper interface
+	// to the line of the annotation, it is nil for the mapper XML file.
+	annotationLineMapping map[int]int
 }
 
 // buildMybatisMapperXMLFileData will build the mybatis mapper XML file data.
@@ -2511,9 +3640,12 @@ type mybatisMapperXMLFileDatum struct {
 //	repo: the repository will be list file tree and get file content from.
 //	commitID: the commitID is the snapshot of the file tree and file content.
 //	mapperFiles: the map of the mybatis mapper XML file path and content.
-func buildMybatisMapperXMLFileData(ctx context.Context, oauthContext *common.OauthContext, repoInfo *repoInfo, commitID string, mapperFiles map[string]string) ([]*mybatisMapperXMLFileDatum, error) {
+//
+// It also returns the mybatis mapper XML files found in the repository along with the mapperFiles, which are used to
+// resolve the <include> elements referring to other mapper XML files.
+func buildMybatisMapperXMLFileData(ctx context.Context, oauthContext *common.OauthContext, repoInfo *repoInfo, commitID string, mapperFiles map[string]string) ([]*mybatisMapperXMLFileDatum, map[string]string, error) {
 	if len(mapperFiles) == 0 {
-		return []*mybatisMapperXMLFileDatum{}, nil
+		return []*mybatisMapperXMLFileDatum{}, map[string]string{}, nil
 	}
 
 	var mybatisMapperXMLFileData []*mybatisMapperXMLFileDatum
@@ -2527,9 +3659,23 @@ func buildMybatisMapperXMLFileData(ctx context.Context, oauthContext *common.Oau
 	// the key is the mybatis configuration XML file ls-tree syntax path, and value is the mybatis configuration XML file content.
 	// each value is configPathCache must be the key of configCache.
 	configCache := make(map[string]string)
+	var isMybatisMapperXMLRegex = regexp.MustCompile(`(?i)http(s)?://mybatis\.org/dtd/mybatis-3-mapper\.dtd`)
+	repositoryMapperFiles := make(map[string]string)
+	for mapperFilePath, mapperFileContent := range mapperFiles {
+		repositoryMapperFiles[mapperFilePath] = mapperFileContent
+	}
 
 	for mapperFilePath, mapperFileContent := range mapperFiles {
 		configPath := mapperFilePath
+		// The configuration XML of the Java mapper interface is in the resources directory by the convention of Maven
+		// and Gradle, e.g. "src/main/java/com/example/UserMapper.java" and "src/main/resources/mybatis-config.xml".
+		if strings.HasSuffix(mapperFilePath, ".java") {
+			if index := strings.LastIndex(mapperFilePath, "/java/"); index >= 0 {
+				configPath = path.Join(mapperFilePath[:index], "resources", path.Base(mapperFilePath))
+			} else if strings.HasPrefix(mapperFilePath, "java/") {
+				configPath = path.Join("resources", path.Base(mapperFilePath))
+			}
+		}
 		datum := &mybatisMapperXMLFileDatum{
 			mapperPath:    mapperFilePath,
 			mapperContent: mapperFileContent,
@@ -2555,7 +3701,11 @@ func buildMybatisMapperXMLFileData(ctx context.Context, oauthContext *common.Oau
 				currentDir,
 			)
 			if err != nil {
-				return nil, errors.Wrapf(err, "failed to fetch repository file list for repository %q commitID %q directory %q", repoInfo.repository.WebURL, commitID, currentDir)
+				// The resources directory of the Java mapper interface may not exist.
+				if common.ErrorCode(err) != common.NotFound {
+					return nil, nil, errors.Wrapf(err, "failed to fetch repository file list for repository %q commitID %q directory %q", repoInfo.repository.WebURL, commitID, currentDir)
+				}
+				slog.Debug("Directory not found", slog.String("directory", currentDir))
 			}
 
 			for _, file := range filesInDir {
@@ -2574,7 +3724,13 @@ func buildMybatisMapperXMLFileData(ctx context.Context, oauthContext *common.Oau
 					},
 				)
 				if err != nil {
-					return nil, errors.Wrapf(err, "failed to read file content for repository %q commitID %q file %q", repoInfo.repository.WebURL, commitID, file.Path)
+					return nil, nil, errors.Wrapf(err, "failed to read file content for repository %q commitID %q file %q", repoInfo.repository.WebURL, commitID, file.Path)
+				}
+				if isMybatisMapperXMLRegex.MatchString(fileContent) {
+					if _, ok := repositoryMapperFiles[file.Path]; !ok {
+						repositoryMapperFiles[file.Path] = fileContent
+					}
+					continue
 				}
 				if !isMybatisConfigXMLRegex.MatchString(fileContent) {
 					continue
@@ -2591,5 +3747,5 @@ func buildMybatisMapperXMLFileData(ctx context.Context, oauthContext *common.Oau
 		}
 		mybatisMapperXMLFileData = append(mybatisMapperXMLFileData, datum)
 	}
-	return mybatisMapperXMLFileData, nil
+	return mybatisMapperXMLFileData, repositoryMapperFiles, nil
 }
@@ -1,6 +1,7 @@
 package gitops
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -187,6 +188,34 @@ func TestVCSSQLReview_ConvertSQLAdviceToGitHubActionResult(t *testing.T) {
 	assert.Equal(t, expect, res.Content)
 }
 
+func TestVCSSQLReview_ConvertSQLAdviceToReviewComments(t *testing.T) {
+	status, comments := convertSQLAdviceToReviewComments(mockSQLAdviceMap)
+	assert.Equal(t, advisor.Error, status)
+	require.Len(t, comments, 4)
+	assert.Equal(t, "file1.sql", comments[0].path)
+	assert.Equal(t, 1, comments[0].line)
+	assert.Equal(t, "**[WARN] column.no-null (402)**\n\nColumn \"id\" in \"public\".\"book\" cannot have NULL value\n\nDoc: https://www.bytebase.com/docs/reference/error-code/advisor#402", comments[0].body())
+	assert.Equal(t, "file2.sql", comments[3].path)
+	assert.Equal(t, 4, comments[3].line)
+
+	expect := strings.Join([]string{
+		"**Bytebase SQL Review**",
+		"",
+		"- **[WARN]** `file1.sql#L1`: column.no-null (402)\n  Column \"id\" in \"public\".\"book\" cannot have NULL value [Doc](https://www.bytebase.com/docs/reference/error-code/advisor#402)",
+		"- **[ERROR]** `file1.sql#L2`: naming.index.idx (303)\n  Index in table \"tech_book\" mismatches the naming convention, expect \"^$|^idx_tech_book_id_name$\" but found \"tech_book_id_name\" [Doc](https://www.bytebase.com/docs/reference/error-code/advisor#303)",
+		"- **[WARN]** `file2.sql#L1`: naming.table (301)\n  \"techBook\" mismatches table naming convention, naming format should be \"^[a-z]+(_[a-z]+)*$\" [Doc](https://www.bytebase.com/docs/reference/error-code/advisor#301)",
+		"- **[ERROR]** `file2.sql#L4`: naming.index.uk (304)\n  Unique key in table \"tech_book\" mismatches the naming convention, expect \"^$|^uk_tech_book_id_name$\" but found \"tech_book_id_name\" [Doc](https://www.bytebase.com/docs/reference/error-code/advisor#304)",
+	}, "\n")
+	assert.Equal(t, expect, formatSQLReviewComments(comments))
+	assert.Equal(t, expect, formatSQLReviewSummary(len(comments), comments))
+	assert.Equal(t, "**Bytebase SQL Review**\n\nFound 4 issue(s), see the inline comments.", formatSQLReviewSummary(len(comments), nil))
+	assert.Equal(t, "**Bytebase SQL Review**\n\nNo issues found.", formatSQLReviewSummary(0, nil))
+
+	status, comments = convertSQLAdviceToReviewComments(map[string][]advisor.Advice{})
+	assert.Equal(t, advisor.Success, status)
+	assert.Empty(t, comments)
+}
+
 func TestGetFileInfo(t *testing.T) {
 	t.Run("a SQL format DDL", func(t *testing.T) {
 		mi, fileType, repoInfo, err := getFileInfo(
@@ -364,6 +393,89 @@ func TestGetFileInfo(t *testing.T) {
 	})
 }
 
+func TestFilterFilesByCommitsDiff(t *testing.T) {
+	pushEvent := vcs.PushEvent{
+		CommitList: []vcs.Commit{
+			{ID: "commit-1", CreatedTs: 1},
+			{ID: "commit-2", CreatedTs: 2},
+		},
+	}
+	distinctFileList := []vcs.DistinctFileItem{
+		{FileName: "prod/db##0002##ddl##add_index.sql", ItemType: vcs.FileItemTypeAdded},
+		{FileName: "prod/db##0003##ddl##add_column.sql", ItemType: vcs.FileItemTypeModified},
+		{FileName: "prod/db##0004##ddl##merged.sql", ItemType: vcs.FileItemTypeAdded},
+	}
+	fileDiffList := []vcs.FileDiff{
+		{Path: "prod/db##0001##ddl##create_table.sql", Type: vcs.FileDiffTypeRemoved},
+		{Path: "prod/db##0002##ddl##add_index.sql", Type: vcs.FileDiffTypeAdded},
+		{Path: "prod/db##0003##ddl##add_column.sql", Type: vcs.FileDiffTypeRemoved},
+	}
+
+	got := filterFilesByCommitsDiff(distinctFileList, fileDiffList)
+	assert.Equal(t, []vcs.DistinctFileItem{distinctFileList[0]}, got)
+
+	got = getRemovedFileList(pushEvent, fileDiffList)
+	assert.Equal(t, []vcs.DistinctFileItem{
+		{
+			CreatedTs: 2,
+			Commit:    pushEvent.CommitList[1],
+			FileName:  "prod/db##0001##ddl##create_table.sql",
+			ItemType:  vcs.FileItemTypeRemoved,
+		},
+		{
+			CreatedTs: 2,
+			Commit:    pushEvent.CommitList[1],
+			FileName:  "prod/db##0003##ddl##add_column.sql",
+			ItemType:  vcs.FileItemTypeRemoved,
+		},
+	}, got)
+}
+
+func TestGetRollbackIssueDescription(t *testing.T) {
+	pushEvent := vcs.PushEvent{
+		CommitList: []vcs.Commit{
+			{ID: "commit-1", Message: "Remove the migration file"},
+		},
+	}
+	assert.Equal(t, "Roll back the schema change by VCS file db##0001##ddl##create_table.sql, which is removed in commit commit-1.\n", getRollbackIssueDescription(pushEvent, "db##0001##ddl##create_table.sql"))
+
+	pushEvent.CommitList = append(pushEvent.CommitList, vcs.Commit{
+		ID:      "commit-2",
+		Message: "Revert \"Create table\"\n\nThis reverts commit 5b3c1a2e9f0d4c6b8a7e1f2d3c4b5a6978812345.\n",
+	})
+	assert.Equal(t, "5b3c1a2e9f0d4c6b8a7e1f2d3c4b5a6978812345", getRevertedCommitID(pushEvent.CommitList[1]))
+	assert.Equal(t, "Roll back the schema change by VCS file db##0001##ddl##create_table.sql, which is removed by commit commit-2 reverting commit 5b3c1a2e9f0d4c6b8a7e1f2d3c4b5a6978812345.\n", getRollbackIssueDescription(pushEvent, "db##0001##ddl##create_table.sql"))
+}
+
+func TestFindLaterSchemaChange(t *testing.T) {
+	newHistory := func(sequence int64, migrationType db.MigrationType, status db.MigrationStatus, version string) *store.InstanceChangeHistoryMessage {
+		return &store.InstanceChangeHistoryMessage{
+			Sequence: sequence,
+			Type:     migrationType,
+			Status:   status,
+			Version:  model.Version{Version: version},
+		}
+	}
+	history := newHistory(2, db.Migrate, db.Done, "0002-ddl")
+	earlier := newHistory(1, db.Migrate, db.Done, "0001-ddl")
+
+	assert.Nil(t, findLaterSchemaChange([]*store.InstanceChangeHistoryMessage{history, earlier}, history))
+	// The data changes and the failed schema changes do not change the schema.
+	assert.Nil(t, findLaterSchemaChange([]*store.InstanceChangeHistoryMessage{
+		newHistory(4, db.Data, db.Done, "0004-dml"),
+		newHistory(3, db.Migrate, db.Failed, "0003-ddl"),
+		history,
+		earlier,
+	}, history))
+	later := newHistory(3, db.Migrate, db.Done, "0003-ddl")
+	assert.Equal(t, later, findLaterSchemaChange([]*store.InstanceChangeHistoryMessage{
+		newHistory(4, db.Data, db.Done, "0004-dml"),
+		later,
+		history,
+		earlier,
+	}, history))
+}
+
 func TestExtractDBTypeFromJDBCConnectionString(t *testing.T) {
 	testCases := []struct {
 		jdbcConnectionString string
@@ -0,0 +1,195 @@
+// Package imapproval is the package for the interactive approvals from the Slack and Teams messages.
+package imapproval
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/coreos/go-oidc"
+	"github.com/gosimple/slug"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	apiv1 "github.com/bytebase/bytebase/backend/api/v1"
+	"github.com/bytebase/bytebase/backend/common"
+	"github.com/bytebase/bytebase/backend/common/log"
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/plugin/webhook"
+	"github.com/bytebase/bytebase/backend/store"
+	v1pb "github.com/bytebase/bytebase/proto/generated-go/v1"
+)
+
+// Service is the API endpoint for handling the Approve/Reject actions from the Slack and Teams messages.
+type Service struct {
+	store        *store.Store
+	issueService *apiv1.IssueService
+	// teamsKeySet is the key set verifying the bearer tokens of the Teams action requests.
+	teamsKeySet oidc.KeySet
+}
+
+// NewService creates an IM approval service.
+func NewService(ctx context.Context, store *store.Store, issueService *apiv1.IssueService) *Service {
+	return &Service{
+		store:        store,
+		issueService: issueService,
+		teamsKeySet:  oidc.NewRemoteKeySet(ctx, webhook.TeamsActionKeysURL),
+	}
+}
+
+// reviewResult is the result of the approval action.
+type reviewResult struct {
+	issue *store.IssueMessage
+	user  *store.UserMessage
+	// message is the reason displayed to the user if the action is not taken, e.g. the user cannot approve the issue.
+	message string
+}
+
+// RegisterWebhookRoutes registers the callback routes for the Slack interactions and the Teams actions.
+func (s *Service) RegisterWebhookRoutes(g *echo.Group) {
+	g.POST("/im-approval/slack", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		body, err := io.ReadAll(c.Request().Body)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Failed to read Slack interaction request").SetInternal(err)
+		}
+		imSetting, err := s.store.GetAppIMApprovalSetting(ctx)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get IM setting").SetInternal(err)
+		}
+		if imSetting == nil || !imSetting.Slack.Enabled {
+			return echo.NewHTTPError(http.StatusNotFound, "Slack approval is not enabled")
+		}
+		interaction, value, err := webhook.ParseSlackInteraction(imSetting.Slack.SigningSecret, c.Request().Header, body, time.Now())
+		if err != nil {
+			return echo.NewHTTPError(http.StatusUnauthorized, "Invalid Slack interaction request").SetInternal(err)
+		}
+		// Acknowledge the other interactions, e.g. the "View in Bytebase" button.
+		if value == nil {
+			return c.NoContent(http.StatusOK)
+		}
+
+		email, err := webhook.GetSlackUserEmail(imSetting.Slack.BotToken, interaction.User.ID)
+		if err != nil {
+			slog.Warn("Failed to get the email of the Slack user", slog.String("user", interaction.User.ID), log.BBError(err))
+			if err := webhook.ReplySlackInteraction(interaction, "Failed to find your email in Slack, please check the scopes of the Slack app."); err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, "Failed to reply Slack interaction").SetInternal(err)
+			}
+			return c.NoContent(http.StatusOK)
+		}
+		result, err := s.review(ctx, email, value)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to review issue").SetInternal(err)
+		}
+		if result.message != "" {
+			if err := webhook.ReplySlackInteraction(interaction, result.message); err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, "Failed to reply Slack interaction").SetInternal(err)
+			}
+			return c.NoContent(http.StatusOK)
+		}
+		outcome := fmt.Sprintf(":white_check_mark: *Approved by %s (%s)*", result.user.Name, result.user.Email)
+		if value.Action == webhook.ApprovalActionReject {
+			outcome = fmt.Sprintf(":x: *Rejected by %s (%s)*", result.user.Name, result.user.Email)
+		}
+		// The issue has been reviewed, so we only log the failure of updating the message.
+		if err := webhook.UpdateSlackApprovalMessage(interaction, outcome); err != nil {
+			slog.Warn("Failed to update the Slack approval message", slog.Int("issue", result.issue.UID), log.BBError(err))
+		}
+		return c.NoContent(http.StatusOK)
+	})
+
+	g.POST("/im-approval/teams", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		body, err := io.ReadAll(c.Request().Body)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Failed to read Teams action request").SetInternal(err)
+		}
+		imSetting, err := s.store.GetAppIMApprovalSetting(ctx)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get IM setting").SetInternal(err)
+		}
+		if imSetting == nil || !imSetting.Teams.Enabled {
+			return echo.NewHTTPError(http.StatusNotFound, "Teams approval is not enabled")
+		}
+		setting, err := s.store.GetWorkspaceGeneralSetting(ctx)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to find workspace setting").SetInternal(err)
+		}
+		email, value, err := webhook.ParseTeamsAction(ctx, s.teamsKeySet, imSetting.Teams.Token, setting.ExternalUrl, c.Request().Header, body)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusUnauthorized, "Invalid Teams action request").SetInternal(err)
+		}
+
+		result, err := s.review(ctx, email, value)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to review issue").SetInternal(err)
+		}
+		if result.message != "" {
+			c.Response().Header().Set(webhook.TeamsActionStatusHeader, result.message)
+			return c.NoContent(http.StatusOK)
+		}
+		title := fmt.Sprintf("Issue approved - %s", result.issue.Title)
+		outcome := fmt.Sprintf("Approved by %s (%s)", result.user.Name, result.user.Email)
+		if value.Action == webhook.ApprovalActionReject {
+			title = fmt.Sprintf("Issue rejected - %s", result.issue.Title)
+			outcome = fmt.Sprintf("Rejected by %s (%s)", result.user.Name, result.user.Email)
+		}
+		link := fmt.Sprintf("%s/issue/%s-%d", setting.ExternalUrl, slug.Make(result.issue.Title), result.issue.UID)
+		card, err := webhook.GetTeamsApprovalOutcomeCard(title, outcome, link)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get Teams card").SetInternal(err)
+		}
+		c.Response().Header().Set(webhook.TeamsCardUpdateHeader, "true")
+		c.Response().Header().Set(webhook.TeamsActionStatusHeader, outcome)
+		return c.JSONBlob(http.StatusOK, card)
+	})
+}
+
+// review approves or rejects the pending approval step of the issue on behalf of the Bytebase user with the email.
+// It goes through the same checks as approving or rejecting the issue in Bytebase.
+func (s *Service) review(ctx context.Context, email string, value *webhook.ApprovalActionValue) (*reviewResult, error) {
+	email = strings.ToLower(email)
+	user, err := s.store.GetUser(ctx, &store.FindUserMessage{Email: &email})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get user %q", email)
+	}
+	if user == nil || user.MemberDeleted || user.Type != api.EndUser {
+		return &reviewResult{message: fmt.Sprintf("No Bytebase user is found with email %s.", email)}, nil
+	}
+	issue, err := s.store.GetIssueV2(ctx, &store.FindIssueMessage{UID: &value.IssueID})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get issue %d", value.IssueID)
+	}
+	if issue == nil {
+		return &reviewResult{message: "The issue is not found."}, nil
+	}
+	if issue.Status != api.IssueOpen {
+		return &reviewResult{message: "The issue is closed."}, nil
+	}
+	if len(issue.Payload.GetApproval().GetApprovers()) != value.Step {
+		return &reviewResult{message: "The approval request is outdated, please review the issue in Bytebase."}, nil
+	}
+
+	childCtx := context.WithValue(ctx, common.PrincipalIDContextKey, user.ID)
+	name := fmt.Sprintf("%s%s/%s%d", common.ProjectNamePrefix, issue.Project.ResourceID, common.IssuePrefix, issue.UID)
+	switch value.Action {
+	case webhook.ApprovalActionApprove:
+		_, err = s.issueService.ApproveIssue(childCtx, &v1pb.ApproveIssueRequest{Name: name})
+	case webhook.ApprovalActionReject:
+		_, err = s.issueService.RejectIssue(childCtx, &v1pb.RejectIssueRequest{Name: name})
+	}
+	if err != nil {
+		switch status.Code(err) {
+		case codes.PermissionDenied, codes.InvalidArgument, codes.FailedPrecondition:
+			return &reviewResult{message: status.Convert(err).Message()}, nil
+		}
+		return nil, err
+	}
+	return &reviewResult{issue: issue, user: user}, nil
+}
@@ -0,0 +1,59 @@
+package scim
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// filterPattern matches the equality filter such as `userName eq "alice@example.com"`,
+// which is the only filter used by the SCIM clients for provisioning.
+var filterPattern = regexp.MustCompile(`(?i)^\s*([a-z0-9_.]+)\s+eq\s+("(?:[^"\\]|\\.)*")\s*$`)
+
+// equalityFilter is the parsed SCIM equality filter.
+type equalityFilter struct {
+	// Attribute is the lower-cased attribute path, e.g. username and emails.value.
+	Attribute string
+	Value     string
+}
+
+// parseFilter parses the SCIM filter. It returns nil if the filter is empty.
+func parseFilter(filter string) (*equalityFilter, error) {
+	if strings.TrimSpace(filter) == "" {
+		return nil, nil
+	}
+	matches := filterPattern.FindStringSubmatch(filter)
+	if matches == nil {
+		return nil, errors.Errorf("unsupported filter %q, only the eq operator is supported", filter)
+	}
+	value, err := strconv.Unquote(matches[2])
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid filter value %s", matches[2])
+	}
+	return &equalityFilter{
+		Attribute: strings.ToLower(matches[1]),
+		Value:     value,
+	}, nil
+}
+
+// parseMemberPath parses the member value from the path such as `members[value eq "101"]`.
+func parseMemberPath(path string) (string, bool, error) {
+	path = strings.TrimSpace(path)
+	if strings.EqualFold(path, "members") {
+		return "", false, nil
+	}
+	lower := strings.ToLower(path)
+	if !strings.HasPrefix(lower, "members[") || !strings.HasSuffix(lower, "]") {
+		return "", false, errors.Errorf("unsupported path %q", path)
+	}
+	filter, err := parseFilter(path[len("members[") : len(path)-1])
+	if err != nil {
+		return "", false, err
+	}
+	if filter == nil || filter.Attribute != "value" {
+		return "", false, errors.Errorf("unsupported path %q", path)
+	}
+	return filter.Value, true, nil
+}
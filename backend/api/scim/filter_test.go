@@ -0,0 +1,89 @@
+package scim
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFilter(t *testing.T) {
+	tests := []struct {
+		filter  string
+		want    *equalityFilter
+		wantErr bool
+	}{
+		{
+			filter: "",
+			want:   nil,
+		},
+		{
+			filter: `userName eq "Alice@example.com"`,
+			want:   &equalityFilter{Attribute: "username", Value: "Alice@example.com"},
+		},
+		{
+			filter: `displayName EQ "Engineering \"Platform\""`,
+			want:   &equalityFilter{Attribute: "displayname", Value: `Engineering "Platform"`},
+		},
+		{
+			filter: `emails.value eq "alice@example.com"`,
+			want:   &equalityFilter{Attribute: "emails.value", Value: "alice@example.com"},
+		},
+		{
+			filter:  `userName sw "alice"`,
+			wantErr: true,
+		},
+		{
+			filter:  `userName eq "alice" and active eq true`,
+			wantErr: true,
+		},
+	}
+
+	a := require.New(t)
+	for _, test := range tests {
+		got, err := parseFilter(test.filter)
+		if test.wantErr {
+			a.Error(err, test.filter)
+			continue
+		}
+		a.NoError(err, test.filter)
+		a.Equal(test.want, got, test.filter)
+	}
+}
+
+func TestParseMemberPath(t *testing.T) {
+	tests := []struct {
+		path      string
+		value     string
+		hasFilter bool
+		wantErr   bool
+	}{
+		{
+			path: "members",
+		},
+		{
+			path:      `members[value eq "101"]`,
+			value:     "101",
+			hasFilter: true,
+		},
+		{
+			path:    `members[display eq "alice"]`,
+			wantErr: true,
+		},
+		{
+			path:    "displayName",
+			wantErr: true,
+		},
+	}
+
+	a := require.New(t)
+	for _, test := range tests {
+		value, hasFilter, err := parseMemberPath(test.path)
+		if test.wantErr {
+			a.Error(err, test.path)
+			continue
+		}
+		a.NoError(err, test.path)
+		a.Equal(test.value, value, test.path)
+		a.Equal(test.hasFilter, hasFilter, test.path)
+	}
+}
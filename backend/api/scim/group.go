@@ -0,0 +1,439 @@
+package scim
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"github.com/bytebase/bytebase/backend/store"
+	"github.com/bytebase/bytebase/backend/utils"
+)
+
+type group struct {
+	Schemas     []string  `json:"schemas"`
+	ID          string    `json:"id,omitempty"`
+	ExternalID  string    `json:"externalId,omitempty"`
+	DisplayName string    `json:"displayName"`
+	Members     []*member `json:"members,omitempty"`
+	Meta        *meta     `json:"meta,omitempty"`
+}
+
+type member struct {
+	// Value is the SCIM id of the user or group.
+	Value   string `json:"value"`
+	Display string `json:"display,omitempty"`
+}
+
+// bindingKey is the project IAM policy binding that the SCIM groups are mapped to.
+type bindingKey struct {
+	project string
+	role    string
+}
+
+func (s *Service) listGroups(c echo.Context) error {
+	ctx := c.Request().Context()
+	filter, err := parseFilter(c.QueryParam("filter"))
+	if err != nil {
+		return newError(http.StatusBadRequest, err.Error())
+	}
+	find := &store.FindSCIMGroupMessage{}
+	var externalID *string
+	if filter != nil {
+		switch filter.Attribute {
+		case "displayname":
+			find.DisplayName = &filter.Value
+		case "externalid":
+			externalID = &filter.Value
+		default:
+			return newError(http.StatusBadRequest, fmt.Sprintf("unsupported filter attribute %q", filter.Attribute))
+		}
+	}
+	groups, err := s.store.ListSCIMGroups(ctx, find)
+	if err != nil {
+		return newError(http.StatusInternalServerError, fmt.Sprintf("failed to list groups: %v", err))
+	}
+	excludeMembers := strings.Contains(strings.ToLower(c.QueryParam("excludedAttributes")), "members")
+	var resources []any
+	for _, g := range groups {
+		if externalID != nil && g.ExternalID != *externalID {
+			continue
+		}
+		resource, err := s.convertToGroup(ctx, g, excludeMembers)
+		if err != nil {
+			return err
+		}
+		resources = append(resources, resource)
+	}
+	return writeJSON(c, http.StatusOK, paginate(c, resources))
+}
+
+func (s *Service) getGroup(c echo.Context) error {
+	ctx := c.Request().Context()
+	g, err := s.getSCIMGroup(ctx, c.Param("id"))
+	if err != nil {
+		return err
+	}
+	excludeMembers := strings.Contains(strings.ToLower(c.QueryParam("excludedAttributes")), "members")
+	resource, err := s.convertToGroup(ctx, g, excludeMembers)
+	if err != nil {
+		return err
+	}
+	return writeJSON(c, http.StatusOK, resource)
+}
+
+func (s *Service) createGroup(c echo.Context) error {
+	ctx := c.Request().Context()
+	request := &group{}
+	if err := bindJSON(c, request); err != nil {
+		return err
+	}
+	if request.DisplayName == "" {
+		return newError(http.StatusBadRequest, "displayName is required")
+	}
+	existingGroup, err := s.store.GetSCIMGroup(ctx, &store.FindSCIMGroupMessage{DisplayName: &request.DisplayName})
+	if err != nil {
+		return newError(http.StatusInternalServerError, fmt.Sprintf("failed to get group: %v", err))
+	}
+	if existingGroup != nil {
+		return newError(http.StatusConflict, fmt.Sprintf("group %q already exists", request.DisplayName))
+	}
+	memberIDs, err := s.getMemberIDs(ctx, request.Members)
+	if err != nil {
+		return err
+	}
+	g, err := s.store.CreateSCIMGroup(ctx, &store.SCIMGroupMessage{
+		ResourceID:  uuid.NewString(),
+		ExternalID:  request.ExternalID,
+		DisplayName: request.DisplayName,
+		MemberIDs:   memberIDs,
+	})
+	if err != nil {
+		return newError(http.StatusInternalServerError, fmt.Sprintf("failed to create group: %v", err))
+	}
+	if err := s.syncProjectBindings(ctx, nil, g); err != nil {
+		return err
+	}
+	resource, err := s.convertToGroup(ctx, g, false /* excludeMembers */)
+	if err != nil {
+		return err
+	}
+	return writeJSON(c, http.StatusCreated, resource)
+}
+
+func (s *Service) replaceGroup(c echo.Context) error {
+	ctx := c.Request().Context()
+	g, err := s.getSCIMGroup(ctx, c.Param("id"))
+	if err != nil {
+		return err
+	}
+	request := &group{}
+	if err := bindJSON(c, request); err != nil {
+		return err
+	}
+	if request.DisplayName == "" {
+		return newError(http.StatusBadRequest, "displayName is required")
+	}
+	memberIDs, err := s.getMemberIDs(ctx, request.Members)
+	if err != nil {
+		return err
+	}
+	updated, err := s.updateGroup(ctx, g, &store.UpdateSCIMGroupMessage{
+		ExternalID:  &request.ExternalID,
+		DisplayName: &request.DisplayName,
+		MemberIDs:   &memberIDs,
+	})
+	if err != nil {
+		return err
+	}
+	resource, err := s.convertToGroup(ctx, updated, false /* excludeMembers */)
+	if err != nil {
+		return err
+	}
+	return writeJSON(c, http.StatusOK, resource)
+}
+
+func (s *Service) patchGroup(c echo.Context) error {
+	ctx := c.Request().Context()
+	g, err := s.getSCIMGroup(ctx, c.Param("id"))
+	if err != nil {
+		return err
+	}
+	request := &patchRequest{}
+	if err := bindJSON(c, request); err != nil {
+		return err
+	}
+
+	patch := &store.UpdateSCIMGroupMessage{}
+	memberIDs := slices.Clone(g.MemberIDs)
+	for _, operation := range request.Operations {
+		op := strings.ToLower(operation.Op)
+		path := operation.Path
+		values := map[string]json.RawMessage{}
+		if path == "" {
+			if op == "remove" {
+				return newError(http.StatusBadRequest, "path is required for the remove operation")
+			}
+			if err := json.Unmarshal(operation.Value, &values); err != nil {
+				return newError(http.StatusBadRequest, fmt.Sprintf("invalid patch value: %v", err))
+			}
+		} else {
+			values[path] = operation.Value
+		}
+
+		for path, value := range values {
+			switch {
+			case strings.EqualFold(path, "displayName"):
+				if op == "remove" {
+					return newError(http.StatusBadRequest, "displayName is required")
+				}
+				var displayName string
+				if err := json.Unmarshal(value, &displayName); err != nil || displayName == "" {
+					return newError(http.StatusBadRequest, fmt.Sprintf("invalid displayName value %s", value))
+				}
+				patch.DisplayName = &displayName
+			case strings.EqualFold(path, "externalId"):
+				var externalID string
+				if op != "remove" {
+					if err := json.Unmarshal(value, &externalID); err != nil {
+						return newError(http.StatusBadRequest, fmt.Sprintf("invalid externalId value %s", value))
+					}
+				}
+				patch.ExternalID = &externalID
+			case strings.HasPrefix(strings.ToLower(path), "members"):
+				memberIDs, err = s.patchMembers(ctx, memberIDs, op, path, value)
+				if err != nil {
+					return err
+				}
+				patch.MemberIDs = &memberIDs
+			default:
+				// The attributes not stored in Bytebase are ignored.
+			}
+		}
+	}
+	if v := patch.DisplayName; v != nil && *v != g.DisplayName {
+		existingGroup, err := s.store.GetSCIMGroup(ctx, &store.FindSCIMGroupMessage{DisplayName: v})
+		if err != nil {
+			return newError(http.StatusInternalServerError, fmt.Sprintf("failed to get group: %v", err))
+		}
+		if existingGroup != nil {
+			return newError(http.StatusConflict, fmt.Sprintf("group %q already exists", *v))
+		}
+	}
+
+	updated, err := s.updateGroup(ctx, g, patch)
+	if err != nil {
+		return err
+	}
+	resource, err := s.convertToGroup(ctx, updated, false /* excludeMembers */)
+	if err != nil {
+		return err
+	}
+	return writeJSON(c, http.StatusOK, resource)
+}
+
+func (s *Service) deleteGroup(c echo.Context) error {
+	ctx := c.Request().Context()
+	g, err := s.getSCIMGroup(ctx, c.Param("id"))
+	if err != nil {
+		return err
+	}
+	if err := s.store.DeleteSCIMGroup(ctx, g.ResourceID); err != nil {
+		return newError(http.StatusInternalServerError, fmt.Sprintf("failed to delete group: %v", err))
+	}
+	if err := s.syncProjectBindings(ctx, g, nil); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// patchMembers applies the patch operation on the members and returns the new member ids.
+func (s *Service) patchMembers(ctx context.Context, memberIDs []int, op, path string, value json.RawMessage) ([]int, error) {
+	memberValue, hasFilter, err := parseMemberPath(path)
+	if err != nil {
+		return nil, newError(http.StatusBadRequest, err.Error())
+	}
+	var members []*member
+	if hasFilter {
+		members = append(members, &member{Value: memberValue})
+	} else if len(value) > 0 && string(value) != "null" {
+		if err := json.Unmarshal(value, &members); err != nil {
+			return nil, newError(http.StatusBadRequest, fmt.Sprintf("invalid members value %s", value))
+		}
+	}
+
+	switch op {
+	case "add":
+		ids, err := s.getMemberIDs(ctx, members)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range ids {
+			if !slices.Contains(memberIDs, id) {
+				memberIDs = append(memberIDs, id)
+			}
+		}
+		return memberIDs, nil
+	case "replace":
+		return s.getMemberIDs(ctx, members)
+	case "remove":
+		// Removing the members without the value removes all members.
+		if !hasFilter && len(members) == 0 {
+			return []int{}, nil
+		}
+		for _, m := range members {
+			id, err := strconv.Atoi(m.Value)
+			if err != nil {
+				continue
+			}
+			memberIDs = slices.DeleteFunc(memberIDs, func(memberID int) bool { return memberID == id })
+		}
+		return memberIDs, nil
+	default:
+		return nil, newError(http.StatusBadRequest, fmt.Sprintf("unsupported patch operation %q", op))
+	}
+}
+
+func (s *Service) updateGroup(ctx context.Context, g *store.SCIMGroupMessage, patch *store.UpdateSCIMGroupMessage) (*store.SCIMGroupMessage, error) {
+	updated, err := s.store.UpdateSCIMGroup(ctx, g.ResourceID, patch)
+	if err != nil {
+		return nil, newError(http.StatusInternalServerError, fmt.Sprintf("failed to update group: %v", err))
+	}
+	if err := s.syncProjectBindings(ctx, g, updated); err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// getMemberIDs gets the principal ids of the members, which must be the end users.
+func (s *Service) getMemberIDs(ctx context.Context, members []*member) ([]int, error) {
+	memberIDs := []int{}
+	for _, m := range members {
+		u, err := s.getEndUser(ctx, m.Value)
+		if err != nil {
+			return nil, newError(http.StatusBadRequest, fmt.Sprintf("member %q not found", m.Value))
+		}
+		if !slices.Contains(memberIDs, u.ID) {
+			memberIDs = append(memberIDs, u.ID)
+		}
+	}
+	return memberIDs, nil
+}
+
+func (s *Service) getSCIMGroup(ctx context.Context, id string) (*store.SCIMGroupMessage, error) {
+	g, err := s.store.GetSCIMGroup(ctx, &store.FindSCIMGroupMessage{ResourceID: &id})
+	if err != nil {
+		return nil, newError(http.StatusInternalServerError, fmt.Sprintf("failed to get group: %v", err))
+	}
+	if g == nil {
+		return nil, newError(http.StatusNotFound, fmt.Sprintf("group %q not found", id))
+	}
+	return g, nil
+}
+
+func (s *Service) convertToGroup(ctx context.Context, g *store.SCIMGroupMessage, excludeMembers bool) (*group, error) {
+	resource := &group{
+		Schemas:     []string{schemaGroup},
+		ID:          g.ResourceID,
+		ExternalID:  g.ExternalID,
+		DisplayName: g.DisplayName,
+		Meta: &meta{
+			ResourceType: "Group",
+			Location:     fmt.Sprintf("/Groups/%s", g.ResourceID),
+		},
+	}
+	if excludeMembers {
+		return resource, nil
+	}
+	for _, memberID := range g.MemberIDs {
+		u, err := s.store.GetUserByID(ctx, memberID)
+		if err != nil {
+			return nil, newError(http.StatusInternalServerError, fmt.Sprintf("failed to get user: %v", err))
+		}
+		if u == nil {
+			continue
+		}
+		resource.Members = append(resource.Members, &member{
+			Value:   strconv.Itoa(u.ID),
+			Display: u.Email,
+		})
+	}
+	return resource, nil
+}
+
+// syncProjectBindings syncs the project IAM policy bindings mapped from the group after the group changes.
+// The before is nil for the created group, and the after is nil for the deleted group.
+// The members removed from the group are removed from the bindings unless they are granted by the other groups,
+// and the bindings not granted by the SCIM groups, e.g. the ones granted manually, are kept.
+func (s *Service) syncProjectBindings(ctx context.Context, before, after *store.SCIMGroupMessage) error {
+	setting, err := s.store.GetSCIMSetting(ctx)
+	if err != nil {
+		return newError(http.StatusInternalServerError, fmt.Sprintf("failed to get SCIM setting: %v", err))
+	}
+	getBindingMembers := func(g *store.SCIMGroupMessage) map[bindingKey][]int {
+		bindingMembers := map[bindingKey][]int{}
+		if g == nil {
+			return bindingMembers
+		}
+		for _, mapping := range setting.GroupMappings {
+			if mapping.Group == g.DisplayName {
+				key := bindingKey{project: mapping.Project, role: mapping.Role}
+				bindingMembers[key] = append(bindingMembers[key], g.MemberIDs...)
+			}
+		}
+		return bindingMembers
+	}
+	grants, revokes := getBindingMembers(after), getBindingMembers(before)
+	for key, memberIDs := range revokes {
+		revokes[key] = slices.DeleteFunc(memberIDs, func(id int) bool { return slices.Contains(grants[key], id) })
+	}
+
+	// Keep the members granted by the other groups.
+	groups, err := s.store.ListSCIMGroups(ctx, &store.FindSCIMGroupMessage{})
+	if err != nil {
+		return newError(http.StatusInternalServerError, fmt.Sprintf("failed to list groups: %v", err))
+	}
+	for _, g := range groups {
+		if (before != nil && g.UID == before.UID) || (after != nil && g.UID == after.UID) {
+			continue
+		}
+		for key, memberIDs := range getBindingMembers(g) {
+			if _, ok := revokes[key]; ok {
+				revokes[key] = slices.DeleteFunc(revokes[key], func(id int) bool { return slices.Contains(memberIDs, id) })
+			}
+		}
+	}
+
+	keys := map[bindingKey]bool{}
+	for key := range grants {
+		keys[key] = true
+	}
+	for key := range revokes {
+		keys[key] = true
+	}
+	for key := range keys {
+		// Only revoke the members granted by the SCIM groups, not the ones granted manually or by the other identity providers.
+		grantedIDs, err := s.store.ListSCIMGroupBindingMemberIDs(ctx, key.project, key.role)
+		if err != nil {
+			return newError(http.StatusInternalServerError, fmt.Sprintf("failed to list SCIM granted members of role %s in project %s: %v", key.role, key.project, err))
+		}
+		revokeIDs := slices.DeleteFunc(revokes[key], func(id int) bool { return !slices.Contains(grantedIDs, id) })
+		addedIDs, err := utils.UpdateProjectRoleMembers(ctx, s.store, key.project, key.role, grants[key], revokeIDs)
+		if err != nil {
+			return newError(http.StatusInternalServerError, fmt.Sprintf("failed to update role %s in project %s: %v", key.role, key.project, err))
+		}
+		if err := s.store.AddSCIMGroupBindingMembers(ctx, key.project, key.role, addedIDs); err != nil {
+			return newError(http.StatusInternalServerError, fmt.Sprintf("failed to record SCIM granted members of role %s in project %s: %v", key.role, key.project, err))
+		}
+		if err := s.store.DeleteSCIMGroupBindingMembers(ctx, key.project, key.role, revokeIDs); err != nil {
+			return newError(http.StatusInternalServerError, fmt.Sprintf("failed to delete SCIM granted members of role %s in project %s: %v", key.role, key.project, err))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,221 @@
+// Package scim implements the SCIM 2.0 provisioning APIs for users and groups.
+// https://datatracker.ietf.org/doc/html/rfc7644
+package scim
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	enterprise "github.com/bytebase/bytebase/backend/enterprise/api"
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/store"
+)
+
+const (
+	schemaUser         = "urn:ietf:params:scim:schemas:core:2.0:User"
+	schemaGroup        = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	schemaListResponse = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	schemaPatchOp      = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	schemaError        = "urn:ietf:params:scim:api:messages:2.0:Error"
+	schemaSPConfig     = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+
+	contentType = "application/scim+json"
+
+	// defaultCount is the default and the max page size of the list responses.
+	defaultCount = 100
+)
+
+// Service is the API endpoint for handling SCIM requests.
+type Service struct {
+	store          *store.Store
+	licenseService enterprise.LicenseService
+}
+
+// NewService creates a SCIM service.
+func NewService(store *store.Store, licenseService enterprise.LicenseService) *Service {
+	return &Service{
+		store:          store,
+		licenseService: licenseService,
+	}
+}
+
+// RegisterRoutes registers the SCIM routes.
+func (s *Service) RegisterRoutes(g *echo.Group) {
+	g.Use(s.authenticate)
+
+	g.GET("/ServiceProviderConfig", s.getServiceProviderConfig)
+
+	g.GET("/Users", s.listUsers)
+	g.POST("/Users", s.createUser)
+	g.GET("/Users/:id", s.getUser)
+	g.PUT("/Users/:id", s.replaceUser)
+	g.PATCH("/Users/:id", s.patchUser)
+	g.DELETE("/Users/:id", s.deleteUser)
+
+	g.GET("/Groups", s.listGroups)
+	g.POST("/Groups", s.createGroup)
+	g.GET("/Groups/:id", s.getGroup)
+	g.PUT("/Groups/:id", s.replaceGroup)
+	g.PATCH("/Groups/:id", s.patchGroup)
+	g.DELETE("/Groups/:id", s.deleteGroup)
+}
+
+// authenticate authenticates the SCIM client with the bearer token in the SCIM setting.
+func (s *Service) authenticate(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if err := s.licenseService.IsFeatureEnabled(api.FeatureSSO); err != nil {
+			return newError(http.StatusForbidden, err.Error())
+		}
+		setting, err := s.store.GetSCIMSetting(c.Request().Context())
+		if err != nil {
+			return newError(http.StatusInternalServerError, fmt.Sprintf("failed to get SCIM setting: %v", err))
+		}
+		if setting.TokenHash == "" {
+			return newError(http.StatusForbidden, "SCIM provisioning is not enabled")
+		}
+		token, ok := strings.CutPrefix(c.Request().Header.Get(echo.HeaderAuthorization), "Bearer ")
+		if !ok || token == "" {
+			return newError(http.StatusUnauthorized, "missing bearer token")
+		}
+		tokenHash := fmt.Sprintf("%x", sha256.Sum256([]byte(token)))
+		if subtle.ConstantTimeCompare([]byte(tokenHash), []byte(setting.TokenHash)) != 1 {
+			return newError(http.StatusUnauthorized, "invalid bearer token")
+		}
+		return next(c)
+	}
+}
+
+func (*Service) getServiceProviderConfig(c echo.Context) error {
+	supported := func(v bool) map[string]any {
+		return map[string]any{"supported": v}
+	}
+	return c.JSON(http.StatusOK, map[string]any{
+		"schemas":        []string{schemaSPConfig},
+		"patch":          supported(true),
+		"bulk":           map[string]any{"supported": false, "maxOperations": 0, "maxPayloadSize": 0},
+		"filter":         map[string]any{"supported": true, "maxResults": defaultCount},
+		"changePassword": supported(false),
+		"sort":           supported(false),
+		"etag":           supported(false),
+		"authenticationSchemes": []map[string]any{
+			{
+				"type":        "oauthbearertoken",
+				"name":        "OAuth Bearer Token",
+				"description": "Authentication with the bearer token configured in the workspace SCIM setting.",
+				"primary":     true,
+			},
+		},
+	})
+}
+
+type meta struct {
+	ResourceType string `json:"resourceType"`
+	Created      string `json:"created,omitempty"`
+	LastModified string `json:"lastModified,omitempty"`
+	Location     string `json:"location,omitempty"`
+}
+
+type listResponse struct {
+	Schemas      []string `json:"schemas"`
+	TotalResults int      `json:"totalResults"`
+	StartIndex   int      `json:"startIndex"`
+	ItemsPerPage int      `json:"itemsPerPage"`
+	Resources    []any    `json:"Resources"`
+}
+
+type patchRequest struct {
+	Schemas    []string          `json:"schemas"`
+	Operations []*patchOperation `json:"Operations"`
+}
+
+type patchOperation struct {
+	// Op is one of add, remove and replace. Some clients like Azure AD capitalize it.
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value"`
+}
+
+type errorResponse struct {
+	Schemas []string `json:"schemas"`
+	Status  string   `json:"status"`
+	Detail  string   `json:"detail"`
+}
+
+// newError returns the echo HTTP error with the SCIM error response.
+func newError(code int, detail string) *echo.HTTPError {
+	return echo.NewHTTPError(code, &errorResponse{
+		Schemas: []string{schemaError},
+		Status:  strconv.Itoa(code),
+		Detail:  detail,
+	})
+}
+
+func writeJSON(c echo.Context, code int, v any) error {
+	c.Response().Header().Set(echo.HeaderContentType, contentType)
+	return c.JSON(code, v)
+}
+
+func bindJSON(c echo.Context, v any) error {
+	body, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return newError(http.StatusBadRequest, fmt.Sprintf("failed to read request body: %v", err))
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return newError(http.StatusBadRequest, fmt.Sprintf("malformed request body: %v", err))
+	}
+	return nil
+}
+
+// getPagination returns the 1-based start index and the count from the query parameters.
+func getPagination(c echo.Context) (int, int) {
+	startIndex, err := strconv.Atoi(c.QueryParam("startIndex"))
+	if err != nil || startIndex < 1 {
+		startIndex = 1
+	}
+	count, err := strconv.Atoi(c.QueryParam("count"))
+	if err != nil {
+		count = defaultCount
+	}
+	// The negative count is interpreted as zero by RFC 7644, and the page size is capped to avoid overflows.
+	count = min(max(count, 0), defaultCount)
+	return startIndex, count
+}
+
+func paginate(c echo.Context, resources []any) *listResponse {
+	startIndex, count := getPagination(c)
+	total := len(resources)
+	begin := min(startIndex-1, total)
+	end := min(begin+count, total)
+	page := resources[begin:end]
+	if page == nil {
+		page = []any{}
+	}
+	return &listResponse{
+		Schemas:      []string{schemaListResponse},
+		TotalResults: total,
+		StartIndex:   startIndex,
+		ItemsPerPage: len(page),
+		Resources:    page,
+	}
+}
+
+// parseBool parses the boolean which may be sent as a string by some clients like Azure AD.
+func parseBool(raw json.RawMessage) (bool, error) {
+	var b bool
+	if err := json.Unmarshal(raw, &b); err == nil {
+		return b, nil
+	}
+	var str string
+	if err := json.Unmarshal(raw, &str); err != nil {
+		return false, err
+	}
+	return strconv.ParseBool(str)
+}
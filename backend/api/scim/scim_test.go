@@ -0,0 +1,56 @@
+package scim
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaginate(t *testing.T) {
+	resources := []any{"a", "b", "c"}
+	tests := []struct {
+		query      string
+		startIndex int
+		want       []any
+	}{
+		{
+			query:      "",
+			startIndex: 1,
+			want:       []any{"a", "b", "c"},
+		},
+		{
+			query:      "?startIndex=2&count=1",
+			startIndex: 2,
+			want:       []any{"b"},
+		},
+		{
+			query:      "?startIndex=0&count=-1",
+			startIndex: 1,
+			want:       []any{},
+		},
+		{
+			query:      "?startIndex=4",
+			startIndex: 4,
+			want:       []any{},
+		},
+		{
+			// The count is capped instead of overflowing.
+			query:      "?startIndex=2&count=9223372036854775807",
+			startIndex: 2,
+			want:       []any{"b", "c"},
+		},
+	}
+
+	a := require.New(t)
+	for _, test := range tests {
+		c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/Users"+test.query, nil), httptest.NewRecorder())
+		got := paginate(c, resources)
+		a.Equal(len(resources), got.TotalResults, test.query)
+		a.Equal(test.startIndex, got.StartIndex, test.query)
+		a.Equal(test.want, got.Resources, test.query)
+		a.Equal(len(test.want), got.ItemsPerPage, test.query)
+	}
+}
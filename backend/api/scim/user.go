@@ -0,0 +1,419 @@
+package scim
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/bytebase/bytebase/backend/common"
+	enterprise "github.com/bytebase/bytebase/backend/enterprise/api"
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/store"
+)
+
+type user struct {
+	Schemas     []string  `json:"schemas"`
+	ID          string    `json:"id,omitempty"`
+	ExternalID  string    `json:"externalId,omitempty"`
+	UserName    string    `json:"userName"`
+	Name        *name     `json:"name,omitempty"`
+	DisplayName string    `json:"displayName,omitempty"`
+	Emails      []*email  `json:"emails,omitempty"`
+	Active      *bool     `json:"active,omitempty"`
+	Groups      []*member `json:"groups,omitempty"`
+	Meta        *meta     `json:"meta,omitempty"`
+}
+
+type name struct {
+	Formatted  string `json:"formatted,omitempty"`
+	GivenName  string `json:"givenName,omitempty"`
+	FamilyName string `json:"familyName,omitempty"`
+}
+
+type email struct {
+	Value   string `json:"value"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+}
+
+// userPatch is the change of the user from the SCIM request.
+type userPatch struct {
+	email  *string
+	name   *string
+	active *bool
+}
+
+func (s *Service) listUsers(c echo.Context) error {
+	ctx := c.Request().Context()
+	filter, err := parseFilter(c.QueryParam("filter"))
+	if err != nil {
+		return newError(http.StatusBadRequest, err.Error())
+	}
+	principalType := api.EndUser
+	find := &store.FindUserMessage{
+		Type:        &principalType,
+		ShowDeleted: true,
+	}
+	if filter != nil {
+		switch filter.Attribute {
+		case "username", "emails.value", "emails":
+			email := strings.ToLower(filter.Value)
+			find.Email = &email
+		default:
+			return newError(http.StatusBadRequest, fmt.Sprintf("unsupported filter attribute %q", filter.Attribute))
+		}
+	}
+	users, err := s.store.ListUsers(ctx, find)
+	if err != nil {
+		return newError(http.StatusInternalServerError, fmt.Sprintf("failed to list users: %v", err))
+	}
+	var resources []any
+	for _, u := range users {
+		resource, err := s.convertToUser(ctx, u)
+		if err != nil {
+			return err
+		}
+		resources = append(resources, resource)
+	}
+	return writeJSON(c, http.StatusOK, paginate(c, resources))
+}
+
+func (s *Service) getUser(c echo.Context) error {
+	ctx := c.Request().Context()
+	u, err := s.getEndUser(ctx, c.Param("id"))
+	if err != nil {
+		return err
+	}
+	resource, err := s.convertToUser(ctx, u)
+	if err != nil {
+		return err
+	}
+	return writeJSON(c, http.StatusOK, resource)
+}
+
+func (s *Service) createUser(c echo.Context) error {
+	ctx := c.Request().Context()
+	request := &user{}
+	if err := bindJSON(c, request); err != nil {
+		return err
+	}
+	email, err := getEmail(request)
+	if err != nil {
+		return newError(http.StatusBadRequest, err.Error())
+	}
+	existingUser, err := s.store.GetUser(ctx, &store.FindUserMessage{
+		Email:       &email,
+		ShowDeleted: true,
+	})
+	if err != nil {
+		return newError(http.StatusInternalServerError, fmt.Sprintf("failed to find user by email: %v", err))
+	}
+	if existingUser != nil {
+		return newError(http.StatusConflict, fmt.Sprintf("user %s already exists", email))
+	}
+	active := request.Active == nil || *request.Active
+	if active {
+		if err := s.userCountGuard(ctx); err != nil {
+			return err
+		}
+	}
+
+	// The users provisioned by SCIM sign in with SSO, so the password is random.
+	password, err := common.RandomString(20)
+	if err != nil {
+		return newError(http.StatusInternalServerError, "failed to generate random password")
+	}
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return newError(http.StatusInternalServerError, "failed to generate password hash")
+	}
+	u, err := s.store.CreateUser(ctx, &store.UserMessage{
+		Email:        email,
+		Name:         getDisplayName(request, email),
+		Type:         api.EndUser,
+		PasswordHash: string(passwordHash),
+	}, api.SystemBotID)
+	if err != nil {
+		return newError(http.StatusInternalServerError, fmt.Sprintf("failed to create user: %v", err))
+	}
+	if !active {
+		deleted := true
+		if u, err = s.store.UpdateUser(ctx, u.ID, &store.UpdateUserMessage{Delete: &deleted}, api.SystemBotID); err != nil {
+			return newError(http.StatusInternalServerError, fmt.Sprintf("failed to deactivate user: %v", err))
+		}
+	}
+
+	resource, err := s.convertToUser(ctx, u)
+	if err != nil {
+		return err
+	}
+	return writeJSON(c, http.StatusCreated, resource)
+}
+
+func (s *Service) replaceUser(c echo.Context) error {
+	ctx := c.Request().Context()
+	u, err := s.getEndUser(ctx, c.Param("id"))
+	if err != nil {
+		return err
+	}
+	request := &user{}
+	if err := bindJSON(c, request); err != nil {
+		return err
+	}
+	email, err := getEmail(request)
+	if err != nil {
+		return newError(http.StatusBadRequest, err.Error())
+	}
+	displayName := getDisplayName(request, email)
+	active := request.Active == nil || *request.Active
+	u, err = s.updateUser(ctx, u, &userPatch{
+		email:  &email,
+		name:   &displayName,
+		active: &active,
+	})
+	if err != nil {
+		return err
+	}
+	resource, err := s.convertToUser(ctx, u)
+	if err != nil {
+		return err
+	}
+	return writeJSON(c, http.StatusOK, resource)
+}
+
+func (s *Service) patchUser(c echo.Context) error {
+	ctx := c.Request().Context()
+	u, err := s.getEndUser(ctx, c.Param("id"))
+	if err != nil {
+		return err
+	}
+	request := &patchRequest{}
+	if err := bindJSON(c, request); err != nil {
+		return err
+	}
+	patch := &userPatch{}
+	for _, operation := range request.Operations {
+		switch strings.ToLower(operation.Op) {
+		case "add", "replace":
+			if err := patch.apply(operation.Path, operation.Value); err != nil {
+				return newError(http.StatusBadRequest, err.Error())
+			}
+		case "remove":
+			// The required attributes can't be removed, and the others are not stored.
+		default:
+			return newError(http.StatusBadRequest, fmt.Sprintf("unsupported patch operation %q", operation.Op))
+		}
+	}
+	u, err = s.updateUser(ctx, u, patch)
+	if err != nil {
+		return err
+	}
+	resource, err := s.convertToUser(ctx, u)
+	if err != nil {
+		return err
+	}
+	return writeJSON(c, http.StatusOK, resource)
+}
+
+// deleteUser deactivates the user, which can be reactivated by setting active to true.
+func (s *Service) deleteUser(c echo.Context) error {
+	ctx := c.Request().Context()
+	u, err := s.getEndUser(ctx, c.Param("id"))
+	if err != nil {
+		return err
+	}
+	active := false
+	if _, err := s.updateUser(ctx, u, &userPatch{active: &active}); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// apply applies the value of the add or replace patch operation at the path.
+// The attributes not stored in Bytebase are ignored.
+func (p *userPatch) apply(path string, value json.RawMessage) error {
+	if path == "" {
+		var values map[string]json.RawMessage
+		if err := json.Unmarshal(value, &values); err != nil {
+			return errors.Errorf("invalid patch value: %v", err)
+		}
+		for k, v := range values {
+			if err := p.apply(k, v); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	switch strings.ToLower(path) {
+	case "active":
+		active, err := parseBool(value)
+		if err != nil {
+			return errors.Errorf("invalid active value %s", value)
+		}
+		p.active = &active
+	case "username":
+		var userName string
+		if err := json.Unmarshal(value, &userName); err != nil {
+			return errors.Errorf("invalid userName value %s", value)
+		}
+		email, err := normalizeEmail(userName)
+		if err != nil {
+			return err
+		}
+		p.email = &email
+	case "displayname", "name.formatted":
+		var displayName string
+		if err := json.Unmarshal(value, &displayName); err != nil {
+			return errors.Errorf("invalid %s value %s", path, value)
+		}
+		if displayName != "" {
+			p.name = &displayName
+		}
+	default:
+	}
+	return nil
+}
+
+func (s *Service) updateUser(ctx context.Context, u *store.UserMessage, patch *userPatch) (*store.UserMessage, error) {
+	update := &store.UpdateUserMessage{}
+	if v := patch.email; v != nil && *v != u.Email {
+		existingUser, err := s.store.GetUser(ctx, &store.FindUserMessage{
+			Email:       v,
+			ShowDeleted: true,
+		})
+		if err != nil {
+			return nil, newError(http.StatusInternalServerError, fmt.Sprintf("failed to find user by email: %v", err))
+		}
+		if existingUser != nil {
+			return nil, newError(http.StatusConflict, fmt.Sprintf("user %s already exists", *v))
+		}
+		update.Email = v
+	}
+	if v := patch.name; v != nil && *v != u.Name {
+		update.Name = v
+	}
+	if v := patch.active; v != nil && *v == u.MemberDeleted {
+		if *v {
+			if err := s.userCountGuard(ctx); err != nil {
+				return nil, err
+			}
+		}
+		deleted := !*v
+		update.Delete = &deleted
+	}
+	if update.Email == nil && update.Name == nil && update.Delete == nil {
+		return u, nil
+	}
+	u, err := s.store.UpdateUser(ctx, u.ID, update, api.SystemBotID)
+	if err != nil {
+		return nil, newError(http.StatusInternalServerError, fmt.Sprintf("failed to update user: %v", err))
+	}
+	return u, nil
+}
+
+// getEndUser gets the end user by the SCIM id, which is the principal id.
+func (s *Service) getEndUser(ctx context.Context, id string) (*store.UserMessage, error) {
+	userID, err := strconv.Atoi(id)
+	if err != nil {
+		return nil, newError(http.StatusNotFound, fmt.Sprintf("user %q not found", id))
+	}
+	u, err := s.store.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, newError(http.StatusInternalServerError, fmt.Sprintf("failed to get user: %v", err))
+	}
+	if u == nil || u.Type != api.EndUser {
+		return nil, newError(http.StatusNotFound, fmt.Sprintf("user %q not found", id))
+	}
+	return u, nil
+}
+
+func (s *Service) userCountGuard(ctx context.Context) error {
+	userLimit := s.licenseService.GetPlanLimitValue(ctx, enterprise.PlanLimitMaximumUser)
+	count, err := s.store.CountActiveUsers(ctx)
+	if err != nil {
+		return newError(http.StatusInternalServerError, fmt.Sprintf("failed to count active users: %v", err))
+	}
+	if int64(count) >= userLimit {
+		return newError(http.StatusForbidden, fmt.Sprintf("reached the maximum user count %d", userLimit))
+	}
+	return nil
+}
+
+func (s *Service) convertToUser(ctx context.Context, u *store.UserMessage) (*user, error) {
+	groups, err := s.store.ListSCIMGroups(ctx, &store.FindSCIMGroupMessage{MemberID: &u.ID})
+	if err != nil {
+		return nil, newError(http.StatusInternalServerError, fmt.Sprintf("failed to list groups: %v", err))
+	}
+	id := strconv.Itoa(u.ID)
+	active := !u.MemberDeleted
+	resource := &user{
+		Schemas:     []string{schemaUser},
+		ID:          id,
+		UserName:    u.Email,
+		Name:        &name{Formatted: u.Name},
+		DisplayName: u.Name,
+		Emails:      []*email{{Value: u.Email, Type: "work", Primary: true}},
+		Active:      &active,
+		Meta: &meta{
+			ResourceType: "User",
+			Location:     fmt.Sprintf("/Users/%s", id),
+		},
+	}
+	for _, group := range groups {
+		resource.Groups = append(resource.Groups, &member{
+			Value:   group.ResourceID,
+			Display: group.DisplayName,
+		})
+	}
+	return resource, nil
+}
+
+// getEmail gets the email from the userName, or the primary email if the userName isn't an email.
+func getEmail(u *user) (string, error) {
+	if email, err := normalizeEmail(u.UserName); err == nil {
+		return email, nil
+	}
+	for _, e := range u.Emails {
+		if e.Primary {
+			return normalizeEmail(e.Value)
+		}
+	}
+	if len(u.Emails) > 0 {
+		return normalizeEmail(u.Emails[0].Value)
+	}
+	return "", errors.Errorf("userName %q is not an email and no email is provided", u.UserName)
+}
+
+func getDisplayName(u *user, email string) string {
+	if u.DisplayName != "" {
+		return u.DisplayName
+	}
+	if u.Name != nil {
+		if u.Name.Formatted != "" {
+			return u.Name.Formatted
+		}
+		if fullName := strings.TrimSpace(fmt.Sprintf("%s %s", u.Name.GivenName, u.Name.FamilyName)); fullName != "" {
+			return fullName
+		}
+	}
+	return email
+}
+
+// normalizeEmail returns the lower-cased email, which is required by the store.
+func normalizeEmail(s string) (string, error) {
+	email := strings.ToLower(strings.TrimSpace(s))
+	address, err := mail.ParseAddress(email)
+	if err != nil || address.Address != email {
+		return "", errors.Errorf("invalid email %q", s)
+	}
+	return email, nil
+}
@@ -33,6 +33,7 @@ var ownerAndDBAMethods = map[string]bool{
 	"RoleService/DeleteRole":                 true,
 	"ActuatorService/UpdateActuatorInfo":     true,
 	"ActuatorService/ListDebugLog":           true,
+	"LoggingService/VerifyLogs":              true,
 }
 
 var projectOwnerMethods = map[string]bool{
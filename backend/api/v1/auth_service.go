@@ -4,8 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"net/mail"
-	"regexp"
 	"strings"
 	"time"
 
@@ -18,21 +18,29 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/emptypb"
 
 	"github.com/bytebase/bytebase/backend/api/auth"
 	"github.com/bytebase/bytebase/backend/common"
+	"github.com/bytebase/bytebase/backend/common/log"
+	"github.com/bytebase/bytebase/backend/component/activity"
 	"github.com/bytebase/bytebase/backend/component/config"
 	"github.com/bytebase/bytebase/backend/component/state"
 	enterprise "github.com/bytebase/bytebase/backend/enterprise/api"
 	api "github.com/bytebase/bytebase/backend/legacyapi"
 	metricapi "github.com/bytebase/bytebase/backend/metric"
+	bbidp "github.com/bytebase/bytebase/backend/plugin/idp"
 	"github.com/bytebase/bytebase/backend/plugin/idp/ldap"
 	"github.com/bytebase/bytebase/backend/plugin/idp/oauth2"
 	"github.com/bytebase/bytebase/backend/plugin/idp/oidc"
 	"github.com/bytebase/bytebase/backend/plugin/metric"
+	"github.com/bytebase/bytebase/backend/plugin/webauthn"
+	webhookplugin "github.com/bytebase/bytebase/backend/plugin/webhook"
+	"github.com/bytebase/bytebase/backend/runner/ldapsync"
 	"github.com/bytebase/bytebase/backend/runner/metricreport"
 	"github.com/bytebase/bytebase/backend/store"
+	"github.com/bytebase/bytebase/backend/utils"
 	storepb "github.com/bytebase/bytebase/proto/generated-go/store"
 	v1pb "github.com/bytebase/bytebase/proto/generated-go/v1"
 )
@@ -46,27 +54,31 @@ var (
 // AuthService implements the auth service.
 type AuthService struct {
 	v1pb.UnimplementedAuthServiceServer
-	store          *store.Store
-	secret         string
-	tokenDuration  time.Duration
-	licenseService enterprise.LicenseService
-	metricReporter *metricreport.Reporter
-	profile        *config.Profile
-	stateCfg       *state.State
-	postCreateUser func(ctx context.Context, user *store.UserMessage, firstEndUser bool) error
+	store           *store.Store
+	secret          string
+	tokenDuration   time.Duration
+	licenseService  enterprise.LicenseService
+	metricReporter  *metricreport.Reporter
+	ldapSyncer      *ldapsync.Syncer
+	profile         *config.Profile
+	stateCfg        *state.State
+	activityManager *activity.Manager
+	postCreateUser  func(ctx context.Context, user *store.UserMessage, firstEndUser bool) error
 }
 
 // NewAuthService creates a new AuthService.
-func NewAuthService(store *store.Store, secret string, tokenDuration time.Duration, licenseService enterprise.LicenseService, metricReporter *metricreport.Reporter, profile *config.Profile, stateCfg *state.State, postCreateUser func(ctx context.Context, user *store.UserMessage, firstEndUser bool) error) (*AuthService, error) {
+func NewAuthService(store *store.Store, secret string, tokenDuration time.Duration, licenseService enterprise.LicenseService, metricReporter *metricreport.Reporter, ldapSyncer *ldapsync.Syncer, profile *config.Profile, stateCfg *state.State, activityManager *activity.Manager, postCreateUser func(ctx context.Context, user *store.UserMessage, firstEndUser bool) error) (*AuthService, error) {
 	return &AuthService{
-		store:          store,
-		secret:         secret,
-		tokenDuration:  tokenDuration,
-		licenseService: licenseService,
-		metricReporter: metricReporter,
-		profile:        profile,
-		stateCfg:       stateCfg,
-		postCreateUser: postCreateUser,
+		store:           store,
+		secret:          secret,
+		tokenDuration:   tokenDuration,
+		licenseService:  licenseService,
+		metricReporter:  metricReporter,
+		ldapSyncer:      ldapSyncer,
+		profile:         profile,
+		stateCfg:        stateCfg,
+		activityManager: activityManager,
+		postCreateUser:  postCreateUser,
 	}, nil
 }
 
@@ -83,7 +95,11 @@ func (s *AuthService) GetUser(ctx context.Context, request *v1pb.GetUserRequest)
 	if user == nil {
 		return nil, status.Errorf(codes.NotFound, "user %d not found", userID)
 	}
-	return convertToUser(user), nil
+	userResponse := convertToUser(user)
+	if userResponse.Locked, err = s.isUserLocked(ctx, user); err != nil {
+		return nil, err
+	}
+	return userResponse, nil
 }
 
 // ListUsers lists all users.
@@ -92,9 +108,15 @@ func (s *AuthService) ListUsers(ctx context.Context, request *v1pb.ListUsersRequ
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to list user, error: %v", err)
 	}
+	lockedEmails, err := s.listLockedEmails(ctx)
+	if err != nil {
+		return nil, err
+	}
 	response := &v1pb.ListUsersResponse{}
 	for _, user := range users {
-		response.Users = append(response.Users, convertToUser(user))
+		userResponse := convertToUser(user)
+		userResponse.Locked = lockedEmails[strings.ToLower(user.Email)]
+		response.Users = append(response.Users, userResponse)
 	}
 	return response, nil
 }
@@ -133,8 +155,17 @@ func (s *AuthService) CreateUser(ctx context.Context, request *v1pb.CreateUserRe
 	if request.User.UserType != v1pb.UserType_SERVICE_ACCOUNT && request.User.UserType != v1pb.UserType_USER {
 		return nil, status.Errorf(codes.InvalidArgument, "support user and service account only")
 	}
-	if request.User.UserType != v1pb.UserType_SERVICE_ACCOUNT && request.User.Password == "" {
-		return nil, status.Errorf(codes.InvalidArgument, "password must be set")
+	if request.User.UserType != v1pb.UserType_SERVICE_ACCOUNT {
+		if request.User.Password == "" {
+			return nil, status.Errorf(codes.InvalidArgument, "password must be set")
+		}
+		passwordPolicy, err := s.store.GetPasswordPolicySetting(ctx)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to get password policy, error: %v", err)
+		}
+		if err := validatePassword(passwordPolicy, request.User.Password); err != nil {
+			return nil, err
+		}
 	}
 
 	count, err := s.store.CountUsers(ctx, api.EndUser)
@@ -200,6 +231,14 @@ func (s *AuthService) CreateUser(ctx context.Context, request *v1pb.CreateUserRe
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to create user, error: %v", err)
 	}
+	if user.Type == api.EndUser {
+		if err := s.store.CreatePasswordHistory(ctx, &store.PasswordHistoryMessage{
+			PrincipalUID: user.ID,
+			PasswordHash: user.PasswordHash,
+		}); err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to create password history, error: %v", err)
+		}
+	}
 
 	if err := s.postCreateUser(ctx, user, firstEndUser); err != nil {
 		return nil, err
@@ -282,6 +321,7 @@ func (s *AuthService) UpdateUser(ctx context.Context, request *v1pb.UpdateUserRe
 	}
 
 	var passwordPatch *string
+	unlock := false
 	patch := &store.UpdateUserMessage{}
 	for _, path := range request.UpdateMask.Paths {
 		switch path {
@@ -306,6 +346,16 @@ func (s *AuthService) UpdateUser(ctx context.Context, request *v1pb.UpdateUserRe
 			if user.Type != api.EndUser {
 				return nil, status.Errorf(codes.InvalidArgument, "password can be mutated for end users only")
 			}
+			passwordPolicy, err := s.store.GetPasswordPolicySetting(ctx)
+			if err != nil {
+				return nil, status.Errorf(codes.Internal, "failed to get password policy, error: %v", err)
+			}
+			if err := validatePassword(passwordPolicy, request.User.Password); err != nil {
+				return nil, err
+			}
+			if err := s.validatePasswordHistory(ctx, passwordPolicy, userID, request.User.Password); err != nil {
+				return nil, err
+			}
 			passwordPatch = &request.User.Password
 		case "service_key":
 			if user.Type != api.ServiceAccount {
@@ -332,8 +382,9 @@ func (s *AuthService) UpdateUser(ctx context.Context, request *v1pb.UpdateUserRe
 					return nil, status.Errorf(codes.InvalidArgument, "MFA is not setup yet")
 				}
 				patch.MFAConfig = &storepb.MFAConfig{
-					OtpSecret:     user.MFAConfig.TempOtpSecret,
-					RecoveryCodes: user.MFAConfig.TempRecoveryCodes,
+					OtpSecret:           user.MFAConfig.TempOtpSecret,
+					RecoveryCodes:       user.MFAConfig.TempRecoveryCodes,
+					WebauthnCredentials: user.MFAConfig.WebauthnCredentials,
 				}
 			} else {
 				setting, err := s.store.GetWorkspaceGeneralSetting(ctx)
@@ -343,7 +394,10 @@ func (s *AuthService) UpdateUser(ctx context.Context, request *v1pb.UpdateUserRe
 				if setting.Require_2Fa {
 					return nil, status.Errorf(codes.InvalidArgument, "2FA is required and cannot be disabled")
 				}
-				patch.MFAConfig = &storepb.MFAConfig{}
+				// The WebAuthn credentials are managed by the webauthn_credentials field.
+				patch.MFAConfig = &storepb.MFAConfig{
+					WebauthnCredentials: user.MFAConfig.WebauthnCredentials,
+				}
 			}
 		case "phone":
 			if request.User.Phone != "" {
@@ -352,10 +406,30 @@ func (s *AuthService) UpdateUser(ctx context.Context, request *v1pb.UpdateUserRe
 				}
 			}
 			patch.Phone = &request.User.Phone
+		case "locked":
+			if role != api.Owner {
+				return nil, status.Errorf(codes.PermissionDenied, "only workspace owner can unlock user")
+			}
+			if request.User.Locked {
+				return nil, status.Errorf(codes.InvalidArgument, "user can only be unlocked")
+			}
+			unlock = true
+		case "webauthn_credentials":
+			if patch.MFAConfig == nil {
+				patch.MFAConfig = proto.Clone(user.MFAConfig).(*storepb.MFAConfig)
+			}
+			if err := updateWebAuthnCredentials(patch.MFAConfig, request.User.WebauthnCredentials); err != nil {
+				return nil, err
+			}
+		case "notification_setting":
+			if user.Type != api.EndUser {
+				return nil, status.Errorf(codes.InvalidArgument, "notification setting can be mutated for end users only")
+			}
+			patch.NotificationSetting = convertNotificationSetting(request.User.NotificationSetting)
 		}
 	}
 	if passwordPatch != nil {
-		passwordHash, err := bcrypt.GenerateFromPassword([]byte(request.User.Password), bcrypt.DefaultCost)
+		passwordHash, err := bcrypt.GenerateFromPassword([]byte(*passwordPatch), bcrypt.DefaultCost)
 		if err != nil {
 			return nil, status.Errorf(codes.Internal, "failed to generate password hash, error: %v", err)
 		}
@@ -388,6 +462,7 @@ func (s *AuthService) UpdateUser(ctx context.Context, request *v1pb.UpdateUserRe
 		if user.MFAConfig != nil {
 			patch.MFAConfig.OtpSecret = user.MFAConfig.OtpSecret
 			patch.MFAConfig.RecoveryCodes = user.MFAConfig.RecoveryCodes
+			patch.MFAConfig.WebauthnCredentials = user.MFAConfig.WebauthnCredentials
 		}
 	}
 	// This flag will update user's recovery codes with temp recovery codes.
@@ -400,20 +475,78 @@ func (s *AuthService) UpdateUser(ctx context.Context, request *v1pb.UpdateUserRe
 			return nil, status.Errorf(codes.InvalidArgument, "No recovery codes to update")
 		}
 		patch.MFAConfig = &storepb.MFAConfig{
-			OtpSecret:     user.MFAConfig.OtpSecret,
-			RecoveryCodes: user.MFAConfig.TempRecoveryCodes,
+			OtpSecret:           user.MFAConfig.OtpSecret,
+			RecoveryCodes:       user.MFAConfig.TempRecoveryCodes,
+			WebauthnCredentials: user.MFAConfig.WebauthnCredentials,
+		}
+	}
+	// The WebAuthn credentials can only be registered by the user itself.
+	if request.RegenerateWebauthnChallenge || request.WebauthnRegistration != nil {
+		if err := s.licenseService.IsFeatureEnabled(api.Feature2FA); err != nil {
+			return nil, status.Errorf(codes.PermissionDenied, err.Error())
+		}
+		if principalID != userID {
+			return nil, status.Errorf(codes.PermissionDenied, "only user itself can register WebAuthn credentials")
+		}
+	}
+	// This flag will add the verified WebAuthn credential to user after the two phase registration.
+	if request.WebauthnRegistration != nil {
+		if patch.MFAConfig == nil {
+			patch.MFAConfig = proto.Clone(user.MFAConfig).(*storepb.MFAConfig)
+		}
+		if err := s.registerWebAuthnCredential(ctx, user, patch.MFAConfig, *request.WebauthnRegistration); err != nil {
+			return nil, err
 		}
 	}
 
+	previousRole := user.Role
 	user, err = s.store.UpdateUser(ctx, userID, patch, principalID)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to update user, error: %v", err)
 	}
+	if patch.Role != nil && *patch.Role != previousRole {
+		s.activityManager.PostEvent(ctx, &activity.Event{
+			Type:       api.ActivityMemberRoleUpdate,
+			Level:      webhookplugin.WebhookInfo,
+			Title:      fmt.Sprintf("Workspace role updated - %s", user.Name),
+			CreatorUID: principalID,
+			Member: &webhookplugin.Member{
+				ID:           user.ID,
+				Name:         user.Name,
+				Email:        user.Email,
+				Role:         string(user.Role),
+				PreviousRole: string(previousRole),
+			},
+		})
+	}
+	if user.Type == api.EndUser && patch.PasswordHash != nil {
+		if err := s.store.CreatePasswordHistory(ctx, &store.PasswordHistoryMessage{
+			PrincipalUID: user.ID,
+			PasswordHash: *patch.PasswordHash,
+		}); err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to create password history, error: %v", err)
+		}
+	}
+	if unlock {
+		if err := s.unlockUser(ctx, principalID, user); err != nil {
+			return nil, err
+		}
+	}
 
 	userResponse := convertToUser(user)
+	if userResponse.Locked, err = s.isUserLocked(ctx, user); err != nil {
+		return nil, err
+	}
 	if request.User.UserType == v1pb.UserType_SERVICE_ACCOUNT && passwordPatch != nil {
 		userResponse.ServiceKey = *passwordPatch
 	}
+	// This flag will generate the WebAuthn creation options with a new challenge.
+	// It will be used when user registers a security key or a passkey.
+	if request.RegenerateWebauthnChallenge {
+		if userResponse.WebauthnCreationOptions, err = s.generateWebAuthnCreationOptions(ctx, user); err != nil {
+			return nil, err
+		}
+	}
 	return userResponse, nil
 }
 
@@ -525,10 +658,39 @@ func convertToUser(user *store.UserMessage) *v1pb.User {
 		convertedUser.MfaEnabled = user.MFAConfig.OtpSecret != ""
 		convertedUser.MfaSecret = user.MFAConfig.TempOtpSecret
 		convertedUser.RecoveryCodes = user.MFAConfig.TempRecoveryCodes
+		convertedUser.WebauthnCredentials = convertToWebAuthnCredentials(user.MFAConfig)
+	}
+	if user.NotificationSetting != nil {
+		convertedUser.NotificationSetting = convertToNotificationSetting(user.NotificationSetting)
 	}
 	return convertedUser
 }
 
+func convertToNotificationSetting(setting *storepb.NotificationSetting) *v1pb.NotificationSetting {
+	converted := &v1pb.NotificationSetting{
+		EmailDisabled: setting.EmailDisabled,
+	}
+	for _, event := range setting.EmailMutedEvents {
+		converted.EmailMutedEvents = append(converted.EmailMutedEvents, v1pb.NotificationSetting_Event(event))
+	}
+	return converted
+}
+
+func convertNotificationSetting(setting *v1pb.NotificationSetting) *storepb.NotificationSetting {
+	converted := &storepb.NotificationSetting{}
+	if setting == nil {
+		return converted
+	}
+	converted.EmailDisabled = setting.EmailDisabled
+	for _, event := range setting.EmailMutedEvents {
+		if event == v1pb.NotificationSetting_EVENT_UNSPECIFIED {
+			continue
+		}
+		converted.EmailMutedEvents = append(converted.EmailMutedEvents, storepb.NotificationSetting_Event(event))
+	}
+	return converted
+}
+
 func convertToPrincipalType(userType v1pb.UserType) (api.PrincipalType, error) {
 	var t api.PrincipalType
 	switch userType {
@@ -563,8 +725,29 @@ func (s *AuthService) Login(ctx context.Context, request *v1pb.LoginRequest) (*v
 	if request.MfaTempToken != nil && *request.MfaTempToken != "" {
 		mfaSecondLogin = true
 	}
+	// The passwordless login identifies the user by the discoverable credential, so there is no email.
+	passwordlessLogin := !mfaSecondLogin && request.WebauthnAssertion != nil && request.Email == "" && request.IdpName == ""
 
-	if !mfaSecondLogin {
+	if passwordlessLogin {
+		if err := s.licenseService.IsFeatureEnabled(api.Feature2FA); err != nil {
+			return nil, status.Errorf(codes.PermissionDenied, err.Error())
+		}
+		// Return the options with a new challenge for the empty assertion.
+		if *request.WebauthnAssertion == "" {
+			options, err := s.generateWebAuthnRequestOptions(ctx, nil)
+			if err != nil {
+				return nil, err
+			}
+			return &v1pb.LoginResponse{
+				WebauthnRequestOptions: &options,
+			}, nil
+		}
+		user, err := s.getUserWithPasskey(ctx, *request.WebauthnAssertion)
+		if err != nil {
+			return nil, err
+		}
+		loginUser = user
+	} else if !mfaSecondLogin {
 		var err error
 		if request.IdpName == "" {
 			loginUser, err = s.getAndVerifyUser(ctx, request)
@@ -587,12 +770,23 @@ func (s *AuthService) Login(ctx context.Context, request *v1pb.LoginRequest) (*v
 			return nil, invalidUserOrPasswordError
 		}
 
-		if request.OtpCode != nil {
-			if err := challengeMFACode(user, *request.OtpCode); err != nil {
+		requirement, err := s.getHardwareKeyRequirement(ctx, user)
+		if err != nil {
+			return nil, err
+		}
+		requireHardwareKey := requirement == hardwareKeyRequired
+		if request.WebauthnAssertion != nil {
+			response, err := webauthn.ParseCredentialResponse(*request.WebauthnAssertion)
+			if err != nil {
+				return nil, status.Errorf(codes.InvalidArgument, "invalid WebAuthn assertion, error: %v", err)
+			}
+			if err := s.challengeWebAuthnAssertion(ctx, user, response, getWebAuthnLoginSubject(user.ID), false /* requireUserVerification */, requireHardwareKey); err != nil {
 				return nil, err
 			}
-		} else if request.RecoveryCode != nil {
-			if err := s.challengeRecoveryCode(ctx, user, *request.RecoveryCode); err != nil {
+		} else if requireHardwareKey {
+			return nil, status.Errorf(codes.Unauthenticated, "hardware security key is required for your role")
+		} else if request.OtpCode != nil || request.RecoveryCode != nil {
+			if err := s.challengeMFACodeWithThrottle(ctx, user, request); err != nil {
 				return nil, err
 			}
 		} else {
@@ -608,15 +802,68 @@ func (s *AuthService) Login(ctx context.Context, request *v1pb.LoginRequest) (*v
 		return nil, status.Errorf(codes.Unauthenticated, "user has been deactivated by administrators")
 	}
 
-	userMFAEnabled := loginUser.MFAConfig != nil && loginUser.MFAConfig.OtpSecret != ""
+	// The password rotation only applies to the users signing in with passwords. The users signing in with
+	// the identity providers have no password histories, so the MFA second login never expires their passwords.
+	requireResetPassword := false
+	if !passwordlessLogin && (mfaSecondLogin || request.IdpName == "") {
+		policy, err := s.store.GetPasswordPolicySetting(ctx)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to get password policy, error: %v", err)
+		}
+		expired, err := s.isPasswordExpired(ctx, policy, loginUser, !mfaSecondLogin)
+		if err != nil {
+			return nil, err
+		}
+		requireResetPassword = expired
+	}
+
+	userMFAEnabled := loginUser.MFAConfig != nil && (loginUser.MFAConfig.OtpSecret != "" || len(loginUser.MFAConfig.WebauthnCredentials) > 0)
 	// We only allow MFA login (2-step) when the feature is enabled and user has enabled MFA.
-	if s.licenseService.IsFeatureEnabled(api.Feature2FA) == nil && !mfaSecondLogin && userMFAEnabled {
+	// The passwordless login has verified the user with the passkey, so it's multi-factor already.
+	if s.licenseService.IsFeatureEnabled(api.Feature2FA) == nil && !mfaSecondLogin && !passwordlessLogin && userMFAEnabled {
 		mfaTempToken, err := auth.GenerateMFATempToken(loginUser.Name, loginUser.ID, s.profile.Mode, s.secret, s.tokenDuration)
 		if err != nil {
 			return nil, status.Errorf(codes.Internal, "failed to generate MFA temp token")
 		}
-		return &v1pb.LoginResponse{
+		response := &v1pb.LoginResponse{
 			MfaTempToken: &mfaTempToken,
+		}
+		if len(loginUser.MFAConfig.WebauthnCredentials) > 0 {
+			options, err := s.generateWebAuthnRequestOptions(ctx, loginUser)
+			if err != nil {
+				return nil, err
+			}
+			response.WebauthnRequestOptions = &options
+		}
+		return response, nil
+	}
+
+	// The user whose password is expired can only reset the password with the restricted token, and has to
+	// sign in again with the new password. The cookies are not set, so the web client stays signed out.
+	if requireResetPassword {
+		token, err := auth.GeneratePasswordResetToken(loginUser.Name, loginUser.ID, s.profile.Mode, s.secret)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to generate password reset token")
+		}
+		return &v1pb.LoginResponse{
+			Token:                token,
+			RequireResetPassword: true,
+		}, nil
+	}
+
+	// The user required to use a hardware security key but has none can only register one with the restricted token.
+	requirement, err := s.getHardwareKeyRequirement(ctx, loginUser)
+	if err != nil {
+		return nil, err
+	}
+	if requirement == hardwareKeyEnrollmentRequired {
+		token, err := auth.GenerateHardwareKeyEnrollmentToken(loginUser.Name, loginUser.ID, s.profile.Mode, s.secret)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to generate hardware key enrollment token")
+		}
+		return &v1pb.LoginResponse{
+			Token:                        token,
+			RequireHardwareKeyEnrollment: true,
 		}, nil
 	}
 
@@ -680,6 +927,17 @@ func (s *AuthService) Logout(ctx context.Context, _ *v1pb.LogoutRequest) (*empty
 }
 
 func (s *AuthService) getAndVerifyUser(ctx context.Context, request *v1pb.LoginRequest) (*store.UserMessage, error) {
+	policy, err := s.store.GetPasswordPolicySetting(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get password policy, error: %v", err)
+	}
+	email := strings.ToLower(request.Email)
+	clientIP := auth.GetClientIP(ctx, s.profile.TrustedProxies)
+	// Reject the locked account or client IP before verifying the password.
+	if err := s.checkLoginThrottle(ctx, email, clientIP); err != nil {
+		return nil, err
+	}
+
 	user, err := s.store.GetUser(ctx, &store.FindUserMessage{
 		Email:       &request.Email,
 		ShowDeleted: true,
@@ -688,13 +946,24 @@ func (s *AuthService) getAndVerifyUser(ctx context.Context, request *v1pb.LoginR
 		return nil, status.Errorf(codes.Internal, "failed to get user by email %q: %v", request.Email, err)
 	}
 	if user == nil {
+		if err := s.recordLoginFailure(ctx, policy, nil, email, clientIP); err != nil {
+			return nil, err
+		}
 		return nil, invalidUserOrPasswordError
 	}
 	// Compare the stored hashed password, with the hashed version of the password that was received.
 	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(request.Password)); err != nil {
+		if err := s.recordLoginFailure(ctx, policy, user, email, clientIP); err != nil {
+			return nil, err
+		}
 		// If the two passwords don't match, return a 401 status.
 		return nil, invalidUserOrPasswordError
 	}
+	// Reset the failed login counter of the account. The counter of the client IP is kept until it expires,
+	// so that signing in with a valid account doesn't reset the counter for guessing other accounts.
+	if err := s.store.DeleteLoginThrottle(ctx, store.LoginThrottleAccount, email); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to reset login throttle, error: %v", err)
+	}
 	return user, nil
 }
 
@@ -719,6 +988,7 @@ func (s *AuthService) getOrCreateUserWithIDP(ctx context.Context, request *v1pb.
 	}
 
 	var userInfo *storepb.IdentityProviderUserInfo
+	var claims map[string]any
 	if idp.Type == storepb.IdentityProviderType_OAUTH2 {
 		oauth2Context := request.IdpContext.GetOauth2Context()
 		if oauth2Context == nil {
@@ -733,7 +1003,7 @@ func (s *AuthService) getOrCreateUserWithIDP(ctx context.Context, request *v1pb.
 		if err != nil {
 			return nil, status.Errorf(codes.Internal, "failed to exchange token: %v", err)
 		}
-		userInfo, err = oauth2IdentityProvider.UserInfo(token)
+		userInfo, claims, err = oauth2IdentityProvider.UserInfo(token)
 		if err != nil {
 			return nil, status.Errorf(codes.Internal, "failed to get user info: %v", err)
 		}
@@ -765,7 +1035,7 @@ func (s *AuthService) getOrCreateUserWithIDP(ctx context.Context, request *v1pb.
 			return nil, status.Errorf(codes.Internal, "failed to exchange token: %v", err)
 		}
 
-		userInfo, err = oidcIDP.UserInfo(ctx, token, "")
+		userInfo, claims, err = oidcIDP.UserInfo(ctx, token, "")
 		if err != nil {
 			return nil, status.Errorf(codes.Internal, "failed to get user info: %v", err)
 		}
@@ -804,7 +1074,7 @@ func (s *AuthService) getOrCreateUserWithIDP(ctx context.Context, request *v1pb.
 	if err := validateEmail(email); err != nil {
 		// If the email is invalid, we will try to use the domain and identifier to construct the email.
 		if idp.Domain != "" {
-			domain := extractDomain(idp.Domain)
+			domain := bbidp.ExtractDomain(idp.Domain)
 			email = strings.ToLower(fmt.Sprintf("%s@%s", userInfo.Identifier, domain))
 		}
 	}
@@ -849,9 +1119,97 @@ func (s *AuthService) getOrCreateUserWithIDP(ctx context.Context, request *v1pb.
 		user = users[0]
 	}
 
+	if idp.Type == storepb.IdentityProviderType_LDAP && !user.MemberDeleted {
+		// The groups are synced on login, so that the granted access takes effect immediately.
+		if err := s.ldapSyncer.SyncUser(ctx, idp, request.Email, user); err != nil {
+			slog.Error("failed to sync LDAP groups", slog.String("user", user.Email), log.BBError(err))
+		}
+	}
+	if claims != nil && !user.MemberDeleted {
+		if err := s.applyClaimMappings(ctx, idp, user, claims); err != nil {
+			slog.Error("failed to apply identity provider claim mappings", slog.String("user", user.Email), log.BBError(err))
+		}
+	}
 	return user, nil
 }
 
+// applyClaimMappings reconciles the workspace role and the mapped project roles of the user with the claims.
+func (s *AuthService) applyClaimMappings(ctx context.Context, idp *store.IdentityProviderMessage, user *store.UserMessage, claims map[string]any) error {
+	if s.licenseService.IsFeatureEnabled(api.FeatureSSO) != nil {
+		return nil
+	}
+	setting, err := s.store.GetIdentityProviderClaimMappingSetting(ctx)
+	if err != nil {
+		return err
+	}
+	var config *api.IdentityProviderClaimMappingConfig
+	for _, c := range setting.IdentityProviders {
+		if c.IdentityProvider == idp.ResourceID {
+			config = c
+		}
+	}
+	if config == nil || len(config.ClaimMappings) == 0 {
+		return nil
+	}
+
+	workspaceRole, bindingKeys, granted, err := matchClaimMappings(config, claims)
+	if err != nil {
+		return err
+	}
+	if workspaceRole != "" {
+		if err := utils.UpdateUserWorkspaceRole(ctx, s.store, user, workspaceRole); err != nil {
+			return errors.Wrapf(err, "failed to update the workspace role")
+		}
+	}
+	for _, key := range bindingKeys {
+		var grants, revokes []int
+		if granted[key] {
+			grants = []int{user.ID}
+		} else {
+			revokes = []int{user.ID}
+		}
+		if _, err := utils.UpdateProjectRoleMembers(ctx, s.store, key.project, key.role, grants, revokes); err != nil {
+			return errors.Wrapf(err, "failed to update role %s in project %s", key.role, key.project)
+		}
+	}
+	return nil
+}
+
+type claimBindingKey struct {
+	project string
+	role    string
+}
+
+// matchClaimMappings returns the workspace role, the mapped project bindings and the granted ones of the claims.
+// The workspace role is the highest mapped role, or the default workspace role of the config if none is mapped,
+// and it's empty if the workspace role should be kept.
+func matchClaimMappings(config *api.IdentityProviderClaimMappingConfig, claims map[string]any) (api.Role, []claimBindingKey, map[claimBindingKey]bool, error) {
+	var workspaceRole api.Role
+	var bindingKeys []claimBindingKey
+	granted := make(map[claimBindingKey]bool)
+	for _, mapping := range config.ClaimMappings {
+		key := claimBindingKey{project: mapping.Project, role: mapping.Role}
+		if mapping.Project != "" && !slices.Contains(bindingKeys, key) {
+			bindingKeys = append(bindingKeys, key)
+		}
+		values, err := bbidp.GetClaimValues(claims, mapping.Claim)
+		if err != nil {
+			return "", nil, nil, err
+		}
+		if !slices.Contains(values, mapping.Value) {
+			continue
+		}
+		workspaceRole = utils.MaxWorkspaceRole(workspaceRole, mapping.WorkspaceRole)
+		if mapping.Project != "" {
+			granted[key] = true
+		}
+	}
+	if workspaceRole == "" {
+		workspaceRole = config.DefaultWorkspaceRole
+	}
+	return workspaceRole, bindingKeys, granted, nil
+}
+
 func challengeMFACode(user *store.UserMessage, mfaCode string) error {
 	if !validateWithCodeAndSecret(mfaCode, user.MFAConfig.OtpSecret) {
 		return status.Errorf(codes.Unauthenticated, "invalid MFA code")
@@ -866,8 +1224,9 @@ func (s *AuthService) challengeRecoveryCode(ctx context.Context, user *store.Use
 			user.MFAConfig.RecoveryCodes = slices.Delete(user.MFAConfig.RecoveryCodes, i, i+1)
 			_, err := s.store.UpdateUser(ctx, user.ID, &store.UpdateUserMessage{
 				MFAConfig: &storepb.MFAConfig{
-					OtpSecret:     user.MFAConfig.OtpSecret,
-					RecoveryCodes: user.MFAConfig.RecoveryCodes,
+					OtpSecret:           user.MFAConfig.OtpSecret,
+					RecoveryCodes:       user.MFAConfig.RecoveryCodes,
+					WebauthnCredentials: user.MFAConfig.WebauthnCredentials,
 				},
 			}, user.ID)
 			if err != nil {
@@ -890,23 +1249,6 @@ func validateEmail(email string) error {
 	return nil
 }
 
-func extractDomain(input string) string {
-	pattern := `[a-zA-Z0-9-]+(\.[a-zA-Z0-9-]+)+`
-	regExp, err := regexp.Compile(pattern)
-	if err != nil {
-		// WHen the pattern is invalid, we just return the input.
-		return input
-	}
-
-	match := regExp.FindString(input)
-	domainParts := strings.Split(match, ".")
-	// If the domain has at least 3 parts, we will remove the first part.
-	if len(domainParts) >= 3 {
-		match = strings.Join(domainParts[1:], ".")
-	}
-	return match
-}
-
 const (
 	// issuerName is the name of the issuer of the OTP token.
 	issuerName = "Bytebase"
@@ -0,0 +1,263 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/bytebase/bytebase/backend/api/auth"
+	"github.com/bytebase/bytebase/backend/common"
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/store"
+	v1pb "github.com/bytebase/bytebase/proto/generated-go/v1"
+)
+
+// ListAccessTokens lists the personal access tokens of the user.
+func (s *AuthService) ListAccessTokens(ctx context.Context, request *v1pb.ListAccessTokensRequest) (*v1pb.ListAccessTokensResponse, error) {
+	userID, err := common.GetUserID(request.Parent)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, err.Error())
+	}
+	user, err := s.getAccessTokenOwner(ctx, userID, false /* create */)
+	if err != nil {
+		return nil, err
+	}
+	tokens, err := s.store.ListAccessTokens(ctx, &store.FindAccessTokenMessage{PrincipalUID: &user.ID})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list access tokens, error: %v", err)
+	}
+	response := &v1pb.ListAccessTokensResponse{}
+	for _, token := range tokens {
+		response.AccessTokens = append(response.AccessTokens, convertToAccessToken(token))
+	}
+	return response, nil
+}
+
+// CreateAccessToken creates a personal access token for the user, and the token is only returned in the response.
+func (s *AuthService) CreateAccessToken(ctx context.Context, request *v1pb.CreateAccessTokenRequest) (*v1pb.AccessToken, error) {
+	principalID, ok := ctx.Value(common.PrincipalIDContextKey).(int)
+	if !ok {
+		return nil, status.Errorf(codes.Internal, "principal ID not found")
+	}
+	userID, err := common.GetUserID(request.Parent)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, err.Error())
+	}
+	if request.AccessToken == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "access token must be set")
+	}
+	if request.AccessToken.Title == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "access token title must be set")
+	}
+	expireTs, err := validateAccessTokenExpireTime(request.AccessToken.ExpireTime)
+	if err != nil {
+		return nil, err
+	}
+	for _, scope := range request.AccessToken.Scopes {
+		if err := auth.ValidateScope(scope); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, err.Error())
+		}
+	}
+	user, err := s.getAccessTokenOwner(ctx, userID, true /* create */)
+	if err != nil {
+		return nil, err
+	}
+
+	tokenValue, err := auth.GeneratePersonalAccessToken()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to generate access token, error: %v", err)
+	}
+	token, err := s.store.CreateAccessToken(ctx, &store.AccessTokenMessage{
+		PrincipalUID: user.ID,
+		Title:        request.AccessToken.Title,
+		TokenHash:    auth.HashPersonalAccessToken(tokenValue),
+		Scopes:       request.AccessToken.Scopes,
+		ExpireTs:     expireTs,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create access token, error: %v", err)
+	}
+	if err := s.createMemberAccessTokenActivity(ctx, principalID, api.ActivityMemberAccessTokenCreate, user, token); err != nil {
+		return nil, err
+	}
+
+	accessToken := convertToAccessToken(token)
+	accessToken.Token = tokenValue
+	return accessToken, nil
+}
+
+// DeleteAccessToken revokes the personal access token.
+func (s *AuthService) DeleteAccessToken(ctx context.Context, request *v1pb.DeleteAccessTokenRequest) (*emptypb.Empty, error) {
+	principalID, ok := ctx.Value(common.PrincipalIDContextKey).(int)
+	if !ok {
+		return nil, status.Errorf(codes.Internal, "principal ID not found")
+	}
+	user, token, err := s.getAccessTokenByName(ctx, request.Name)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.store.DeleteAccessToken(ctx, token.UID); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to delete access token, error: %v", err)
+	}
+	if err := s.createMemberAccessTokenActivity(ctx, principalID, api.ActivityMemberAccessTokenRevoke, user, token); err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// RotateAccessToken replaces the token value of the personal access token, and the new token is only returned in the response.
+func (s *AuthService) RotateAccessToken(ctx context.Context, request *v1pb.RotateAccessTokenRequest) (*v1pb.AccessToken, error) {
+	principalID, ok := ctx.Value(common.PrincipalIDContextKey).(int)
+	if !ok {
+		return nil, status.Errorf(codes.Internal, "principal ID not found")
+	}
+	expireTs, err := validateAccessTokenExpireTime(request.ExpireTime)
+	if err != nil {
+		return nil, err
+	}
+	user, token, err := s.getAccessTokenByName(ctx, request.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	tokenValue, err := auth.GeneratePersonalAccessToken()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to generate access token, error: %v", err)
+	}
+	tokenHash := auth.HashPersonalAccessToken(tokenValue)
+	token, err = s.store.UpdateAccessToken(ctx, &store.UpdateAccessTokenMessage{
+		UID:       token.UID,
+		TokenHash: &tokenHash,
+		ExpireTs:  &expireTs,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to rotate access token, error: %v", err)
+	}
+	if err := s.createMemberAccessTokenActivity(ctx, principalID, api.ActivityMemberAccessTokenRotate, user, token); err != nil {
+		return nil, err
+	}
+
+	accessToken := convertToAccessToken(token)
+	accessToken.Token = tokenValue
+	return accessToken, nil
+}
+
+// getAccessTokenOwner returns the user if the principal can manage the access tokens of the user.
+// The users can manage their own access tokens, and the workspace owner can manage the access tokens of all users.
+// However, the workspace owner can only create the access tokens for the service accounts of others.
+func (s *AuthService) getAccessTokenOwner(ctx context.Context, userID int, create bool) (*store.UserMessage, error) {
+	principalID, ok := ctx.Value(common.PrincipalIDContextKey).(int)
+	if !ok {
+		return nil, status.Errorf(codes.Internal, "principal ID not found")
+	}
+	role, ok := ctx.Value(common.RoleContextKey).(api.Role)
+	if !ok {
+		return nil, status.Errorf(codes.Internal, "role not found")
+	}
+	user, err := s.store.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get user, error: %v", err)
+	}
+	if user == nil {
+		return nil, status.Errorf(codes.NotFound, "user %d not found", userID)
+	}
+	if user.MemberDeleted {
+		return nil, status.Errorf(codes.NotFound, "user %d has been deleted", userID)
+	}
+	if user.Type != api.EndUser && user.Type != api.ServiceAccount {
+		return nil, status.Errorf(codes.InvalidArgument, "user %d cannot have access tokens", userID)
+	}
+	if principalID == userID {
+		return user, nil
+	}
+	if role != api.Owner {
+		return nil, status.Errorf(codes.PermissionDenied, "only workspace owner can manage the access tokens of user %d", userID)
+	}
+	if create && user.Type != api.ServiceAccount {
+		return nil, status.Errorf(codes.PermissionDenied, "workspace owner can only create access tokens for service accounts")
+	}
+	return user, nil
+}
+
+// getAccessTokenByName returns the access token and its owner if the principal can manage the access token.
+func (s *AuthService) getAccessTokenByName(ctx context.Context, name string) (*store.UserMessage, *store.AccessTokenMessage, error) {
+	userID, tokenID, err := common.GetUserIDAccessTokenID(name)
+	if err != nil {
+		return nil, nil, status.Errorf(codes.InvalidArgument, err.Error())
+	}
+	user, err := s.getAccessTokenOwner(ctx, userID, false /* create */)
+	if err != nil {
+		return nil, nil, err
+	}
+	token, err := s.store.GetAccessToken(ctx, &store.FindAccessTokenMessage{UID: &tokenID, PrincipalUID: &user.ID})
+	if err != nil {
+		return nil, nil, status.Errorf(codes.Internal, "failed to get access token, error: %v", err)
+	}
+	if token == nil {
+		return nil, nil, status.Errorf(codes.NotFound, "access token %q not found", name)
+	}
+	return user, token, nil
+}
+
+func (s *AuthService) createMemberAccessTokenActivity(ctx context.Context, creatorID int, activityType api.ActivityType, user *store.UserMessage, token *store.AccessTokenMessage) error {
+	bytes, err := json.Marshal(&api.ActivityMemberAccessTokenPayload{
+		PrincipalID:    user.ID,
+		PrincipalName:  user.Name,
+		PrincipalEmail: user.Email,
+		TokenName:      formatAccessTokenName(token),
+		TokenTitle:     token.Title,
+		Scopes:         token.Scopes,
+		ExpireTs:       token.ExpireTs,
+	})
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to construct activity payload, error: %v", err)
+	}
+	if _, err := s.store.CreateActivityV2(ctx, &store.ActivityMessage{
+		CreatorUID:   creatorID,
+		ContainerUID: user.ID,
+		Type:         activityType,
+		Level:        api.ActivityInfo,
+		Payload:      string(bytes),
+	}); err != nil {
+		return status.Errorf(codes.Internal, "failed to create activity, error: %v", err)
+	}
+	return nil
+}
+
+func validateAccessTokenExpireTime(expireTime *timestamppb.Timestamp) (int64, error) {
+	if expireTime == nil {
+		return 0, status.Errorf(codes.InvalidArgument, "access token expire time must be set")
+	}
+	if err := expireTime.CheckValid(); err != nil {
+		return 0, status.Errorf(codes.InvalidArgument, "invalid expire time, error: %v", err)
+	}
+	if !expireTime.AsTime().After(time.Now()) {
+		return 0, status.Errorf(codes.InvalidArgument, "access token expire time must be in the future")
+	}
+	return expireTime.AsTime().Unix(), nil
+}
+
+func formatAccessTokenName(token *store.AccessTokenMessage) string {
+	return fmt.Sprintf("%s/%s%d", common.FormatUserUID(token.PrincipalUID), common.AccessTokenPrefix, token.UID)
+}
+
+func convertToAccessToken(token *store.AccessTokenMessage) *v1pb.AccessToken {
+	accessToken := &v1pb.AccessToken{
+		Name:       formatAccessTokenName(token),
+		Title:      token.Title,
+		Scopes:     token.Scopes,
+		ExpireTime: timestamppb.New(time.Unix(token.ExpireTs, 0)),
+		LastUsedIp: token.LastUsedIP,
+		CreateTime: timestamppb.New(time.Unix(token.CreatedTs, 0)),
+	}
+	if token.LastUsedTs > 0 {
+		accessToken.LastUsedTime = timestamppb.New(time.Unix(token.LastUsedTs, 0))
+	}
+	return accessToken
+}
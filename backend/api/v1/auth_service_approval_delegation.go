@@ -0,0 +1,174 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/bytebase/bytebase/backend/common"
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/store"
+	v1pb "github.com/bytebase/bytebase/proto/generated-go/v1"
+)
+
+// ListApprovalDelegations lists the approval delegations of the user.
+func (s *AuthService) ListApprovalDelegations(ctx context.Context, request *v1pb.ListApprovalDelegationsRequest) (*v1pb.ListApprovalDelegationsResponse, error) {
+	userID, err := common.GetUserID(request.Parent)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, err.Error())
+	}
+	user, err := s.getApprovalDelegator(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	delegations, err := s.store.ListApprovalDelegations(ctx, &store.FindApprovalDelegationMessage{DelegatorUID: &user.ID})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list approval delegations, error: %v", err)
+	}
+	response := &v1pb.ListApprovalDelegationsResponse{}
+	for _, delegation := range delegations {
+		approvalDelegation, err := s.convertToApprovalDelegation(ctx, delegation)
+		if err != nil {
+			return nil, err
+		}
+		response.ApprovalDelegations = append(response.ApprovalDelegations, approvalDelegation)
+	}
+	return response, nil
+}
+
+// CreateApprovalDelegation creates an approval delegation, and the delegate can review the approvals on behalf of the user during the delegation window.
+func (s *AuthService) CreateApprovalDelegation(ctx context.Context, request *v1pb.CreateApprovalDelegationRequest) (*v1pb.ApprovalDelegation, error) {
+	userID, err := common.GetUserID(request.Parent)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, err.Error())
+	}
+	if request.ApprovalDelegation == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "approval delegation must be set")
+	}
+	startTime, endTime := request.ApprovalDelegation.StartTime, request.ApprovalDelegation.EndTime
+	if startTime == nil || endTime == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "approval delegation start time and end time must be set")
+	}
+	if err := startTime.CheckValid(); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid start time, error: %v", err)
+	}
+	if err := endTime.CheckValid(); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid end time, error: %v", err)
+	}
+	if !endTime.AsTime().After(startTime.AsTime()) {
+		return nil, status.Errorf(codes.InvalidArgument, "approval delegation end time must be after the start time")
+	}
+	if !endTime.AsTime().After(time.Now()) {
+		return nil, status.Errorf(codes.InvalidArgument, "approval delegation end time must be in the future")
+	}
+	user, err := s.getApprovalDelegator(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	delegateEmail, err := common.GetUserEmail(request.ApprovalDelegation.Delegate)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, err.Error())
+	}
+	delegate, err := s.store.GetUser(ctx, &store.FindUserMessage{Email: &delegateEmail})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get user by email %q, error: %v", delegateEmail, err)
+	}
+	if delegate == nil || delegate.MemberDeleted {
+		return nil, status.Errorf(codes.NotFound, "delegate not found for email: %q", delegateEmail)
+	}
+	if delegate.Type != api.EndUser {
+		return nil, status.Errorf(codes.InvalidArgument, "delegate %q must be an end user", delegateEmail)
+	}
+	if delegate.ID == user.ID {
+		return nil, status.Errorf(codes.InvalidArgument, "cannot delegate approvals to oneself")
+	}
+
+	delegation, err := s.store.CreateApprovalDelegation(ctx, &store.ApprovalDelegationMessage{
+		DelegatorUID: user.ID,
+		DelegateUID:  delegate.ID,
+		StartTs:      startTime.AsTime().Unix(),
+		EndTs:        endTime.AsTime().Unix(),
+		Reason:       request.ApprovalDelegation.Reason,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create approval delegation, error: %v", err)
+	}
+	return s.convertToApprovalDelegation(ctx, delegation)
+}
+
+// DeleteApprovalDelegation deletes the approval delegation.
+func (s *AuthService) DeleteApprovalDelegation(ctx context.Context, request *v1pb.DeleteApprovalDelegationRequest) (*emptypb.Empty, error) {
+	userID, delegationID, err := common.GetUserIDApprovalDelegationID(request.Name)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, err.Error())
+	}
+	user, err := s.getApprovalDelegator(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	delegation, err := s.store.GetApprovalDelegation(ctx, &store.FindApprovalDelegationMessage{UID: &delegationID, DelegatorUID: &user.ID})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get approval delegation, error: %v", err)
+	}
+	if delegation == nil {
+		return nil, status.Errorf(codes.NotFound, "approval delegation %q not found", request.Name)
+	}
+	if err := s.store.DeleteApprovalDelegation(ctx, delegation.UID); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to delete approval delegation, error: %v", err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// getApprovalDelegator returns the user if the principal can manage the approval delegations of the user.
+// The users can manage their own approval delegations, and the workspace owner can manage the approval delegations of all users.
+func (s *AuthService) getApprovalDelegator(ctx context.Context, userID int) (*store.UserMessage, error) {
+	principalID, ok := ctx.Value(common.PrincipalIDContextKey).(int)
+	if !ok {
+		return nil, status.Errorf(codes.Internal, "principal ID not found")
+	}
+	role, ok := ctx.Value(common.RoleContextKey).(api.Role)
+	if !ok {
+		return nil, status.Errorf(codes.Internal, "role not found")
+	}
+	user, err := s.store.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get user, error: %v", err)
+	}
+	if user == nil {
+		return nil, status.Errorf(codes.NotFound, "user %d not found", userID)
+	}
+	if user.MemberDeleted {
+		return nil, status.Errorf(codes.NotFound, "user %d has been deleted", userID)
+	}
+	if user.Type != api.EndUser {
+		return nil, status.Errorf(codes.InvalidArgument, "user %d cannot delegate approvals", userID)
+	}
+	if principalID != userID && role != api.Owner {
+		return nil, status.Errorf(codes.PermissionDenied, "only workspace owner can manage the approval delegations of user %d", userID)
+	}
+	return user, nil
+}
+
+func (s *AuthService) convertToApprovalDelegation(ctx context.Context, delegation *store.ApprovalDelegationMessage) (*v1pb.ApprovalDelegation, error) {
+	delegate, err := s.store.GetUserByID(ctx, delegation.DelegateUID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get user, error: %v", err)
+	}
+	if delegate == nil {
+		return nil, status.Errorf(codes.NotFound, "user %d not found", delegation.DelegateUID)
+	}
+	return &v1pb.ApprovalDelegation{
+		Name:       fmt.Sprintf("%s/%s%d", common.FormatUserUID(delegation.DelegatorUID), common.ApprovalDelegationPrefix, delegation.UID),
+		Delegate:   common.FormatUserEmail(delegate.Email),
+		StartTime:  timestamppb.New(time.Unix(delegation.StartTs, 0)),
+		EndTime:    timestamppb.New(time.Unix(delegation.EndTs, 0)),
+		Reason:     delegation.Reason,
+		CreateTime: timestamppb.New(time.Unix(delegation.CreatedTs, 0)),
+	}, nil
+}
@@ -0,0 +1,300 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"time"
+	"unicode"
+
+	"golang.org/x/crypto/bcrypt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/bytebase/bytebase/backend/api/auth"
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/store"
+	v1pb "github.com/bytebase/bytebase/proto/generated-go/v1"
+)
+
+const (
+	// loginDelayFailureThreshold is the number of consecutive failures before delaying the login attempts.
+	loginDelayFailureThreshold = 3
+	// maxLoginDelay is the max delay of the login attempts.
+	maxLoginDelay = 30 * time.Second
+)
+
+// validatePassword validates the password against the complexity rules of the password policy.
+func validatePassword(policy *api.SettingPasswordPolicyValue, password string) error {
+	if len([]rune(password)) < policy.MinLength {
+		return status.Errorf(codes.InvalidArgument, "password should be at least %d characters", policy.MinLength)
+	}
+	var hasUpper, hasLower, hasNumber, hasSpecial bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasNumber = true
+		case !unicode.IsLetter(r):
+			hasSpecial = true
+		}
+	}
+	if policy.RequireUppercase && !hasUpper {
+		return status.Errorf(codes.InvalidArgument, "password should contain at least one uppercase letter")
+	}
+	if policy.RequireLowercase && !hasLower {
+		return status.Errorf(codes.InvalidArgument, "password should contain at least one lowercase letter")
+	}
+	if policy.RequireNumber && !hasNumber {
+		return status.Errorf(codes.InvalidArgument, "password should contain at least one number")
+	}
+	if policy.RequireSpecialCharacter && !hasSpecial {
+		return status.Errorf(codes.InvalidArgument, "password should contain at least one special character")
+	}
+	return nil
+}
+
+// validatePasswordHistory rejects the password if it's one of the previous passwords of the user.
+func (s *AuthService) validatePasswordHistory(ctx context.Context, policy *api.SettingPasswordPolicyValue, userID int, password string) error {
+	if policy.HistoryCount <= 0 {
+		return nil
+	}
+	histories, err := s.store.ListPasswordHistories(ctx, userID, policy.HistoryCount)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to list password histories, error: %v", err)
+	}
+	for _, history := range histories {
+		if err := bcrypt.CompareHashAndPassword([]byte(history.PasswordHash), []byte(password)); err == nil {
+			return status.Errorf(codes.InvalidArgument, "password cannot be the same as the last %d passwords", policy.HistoryCount)
+		}
+	}
+	return nil
+}
+
+// isPasswordExpired returns true if the password of the user is older than the rotation days.
+// The password age starts from the first password login if the password is set before the policy.
+func (s *AuthService) isPasswordExpired(ctx context.Context, policy *api.SettingPasswordPolicyValue, user *store.UserMessage, recordIfMissing bool) (bool, error) {
+	if user.Type != api.EndUser {
+		return false, nil
+	}
+	histories, err := s.store.ListPasswordHistories(ctx, user.ID, 1)
+	if err != nil {
+		return false, status.Errorf(codes.Internal, "failed to list password histories, error: %v", err)
+	}
+	if len(histories) == 0 {
+		if recordIfMissing {
+			if err := s.store.CreatePasswordHistory(ctx, &store.PasswordHistoryMessage{
+				PrincipalUID: user.ID,
+				PasswordHash: user.PasswordHash,
+			}); err != nil {
+				return false, status.Errorf(codes.Internal, "failed to create password history, error: %v", err)
+			}
+		}
+		return false, nil
+	}
+	if policy.RotationDays <= 0 {
+		return false, nil
+	}
+	expireTime := time.Unix(histories[0].CreatedTs, 0).AddDate(0, 0, policy.RotationDays)
+	return time.Now().After(expireTime), nil
+}
+
+// checkLoginThrottle rejects the login attempt if the account or the client IP is locked.
+func (s *AuthService) checkLoginThrottle(ctx context.Context, email, clientIP string) error {
+	subjects := map[store.LoginThrottleType]string{store.LoginThrottleAccount: email}
+	if clientIP != "" {
+		subjects[store.LoginThrottleIP] = clientIP
+	}
+	for throttleType, subject := range subjects {
+		throttle, err := s.store.GetLoginThrottle(ctx, throttleType, subject)
+		if err != nil {
+			return status.Errorf(codes.Internal, "failed to get login throttle, error: %v", err)
+		}
+		if throttle != nil && throttle.LockedUntilTs > time.Now().Unix() {
+			return status.Errorf(codes.ResourceExhausted, "too many failed login attempts, please try again after %s", time.Unix(throttle.LockedUntilTs, 0).UTC().Format(time.RFC3339))
+		}
+	}
+	return nil
+}
+
+// recordLoginFailure increases the failed login counters of the account and the client IP, locks them if the
+// counters exceed the limits, and delays the response progressively. The user is nil if the email does not exist.
+func (s *AuthService) recordLoginFailure(ctx context.Context, policy *api.SettingPasswordPolicyValue, user *store.UserMessage, email, clientIP string) error {
+	now := time.Now()
+	lockout := time.Duration(policy.LockoutMinutes) * time.Minute
+	// The counters restart if there is no failure during the lockout duration.
+	resetBeforeTs := now.Add(-lockout).Unix()
+	lockedUntilTs := now.Add(lockout).Unix()
+
+	accountThrottle, err := s.store.RecordLoginFailure(ctx, store.LoginThrottleAccount, email, now.Unix(), resetBeforeTs)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to record login failure, error: %v", err)
+	}
+	accountLocked := accountThrottle.FailureCount >= policy.MaxAccountFailedAttempts
+	if accountLocked {
+		if err := s.store.LockLoginThrottle(ctx, store.LoginThrottleAccount, email, lockedUntilTs); err != nil {
+			return status.Errorf(codes.Internal, "failed to lock account, error: %v", err)
+		}
+	}
+	failureCount := accountThrottle.FailureCount
+	if clientIP != "" {
+		ipThrottle, err := s.store.RecordLoginFailure(ctx, store.LoginThrottleIP, clientIP, now.Unix(), resetBeforeTs)
+		if err != nil {
+			return status.Errorf(codes.Internal, "failed to record login failure, error: %v", err)
+		}
+		if ipThrottle.FailureCount >= policy.MaxIPFailedAttempts {
+			if err := s.store.LockLoginThrottle(ctx, store.LoginThrottleIP, clientIP, lockedUntilTs); err != nil {
+				return status.Errorf(codes.Internal, "failed to lock client IP, error: %v", err)
+			}
+			slog.Warn("client IP is locked for too many failed login attempts", slog.String("ip", clientIP), slog.Int("failureCount", ipThrottle.FailureCount))
+		}
+		if ipThrottle.FailureCount > failureCount {
+			failureCount = ipThrottle.FailureCount
+		}
+	}
+
+	// Only record the activities for the existing users, or the activities can be flooded with random emails.
+	if user != nil {
+		payload := &api.ActivityMemberLoginPayload{
+			PrincipalID:    user.ID,
+			PrincipalName:  user.Name,
+			PrincipalEmail: user.Email,
+			ClientIP:       clientIP,
+			FailureCount:   accountThrottle.FailureCount,
+		}
+		if err := s.createMemberLoginActivity(ctx, api.SystemBotID, api.ActivityMemberLoginFailed, api.ActivityWarn, payload); err != nil {
+			return err
+		}
+		if accountLocked {
+			payload.LockedUntilTs = lockedUntilTs
+			if err := s.createMemberLoginActivity(ctx, api.SystemBotID, api.ActivityMemberLock, api.ActivityError, payload); err != nil {
+				return err
+			}
+		}
+	}
+
+	if delay := getLoginDelay(failureCount); delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+		case <-timer.C:
+		}
+	}
+	return nil
+}
+
+// challengeMFACodeWithThrottle verifies the OTP or the recovery code of the MFA second login. The failures are counted
+// with the failed logins of the account and the client IP, so the codes can't be guessed with the MFA temp token.
+func (s *AuthService) challengeMFACodeWithThrottle(ctx context.Context, user *store.UserMessage, request *v1pb.LoginRequest) error {
+	policy, err := s.store.GetPasswordPolicySetting(ctx)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to get password policy, error: %v", err)
+	}
+	email := strings.ToLower(user.Email)
+	clientIP := auth.GetClientIP(ctx, s.profile.TrustedProxies)
+	if err := s.checkLoginThrottle(ctx, email, clientIP); err != nil {
+		return err
+	}
+
+	var challengeErr error
+	if request.OtpCode != nil {
+		challengeErr = challengeMFACode(user, *request.OtpCode)
+	} else {
+		challengeErr = s.challengeRecoveryCode(ctx, user, *request.RecoveryCode)
+	}
+	if challengeErr != nil {
+		if status.Code(challengeErr) != codes.Unauthenticated {
+			return challengeErr
+		}
+		if err := s.recordLoginFailure(ctx, policy, user, email, clientIP); err != nil {
+			return err
+		}
+		return challengeErr
+	}
+	if err := s.store.DeleteLoginThrottle(ctx, store.LoginThrottleAccount, email); err != nil {
+		return status.Errorf(codes.Internal, "failed to reset login throttle, error: %v", err)
+	}
+	return nil
+}
+
+// unlockUser resets the failed login counter of the user and records the activity.
+func (s *AuthService) unlockUser(ctx context.Context, principalID int, user *store.UserMessage) error {
+	throttle, err := s.store.GetLoginThrottle(ctx, store.LoginThrottleAccount, strings.ToLower(user.Email))
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to get login throttle, error: %v", err)
+	}
+	if throttle == nil {
+		return nil
+	}
+	if err := s.store.DeleteLoginThrottle(ctx, store.LoginThrottleAccount, throttle.Subject); err != nil {
+		return status.Errorf(codes.Internal, "failed to unlock user, error: %v", err)
+	}
+	if throttle.LockedUntilTs <= time.Now().Unix() {
+		return nil
+	}
+	return s.createMemberLoginActivity(ctx, principalID, api.ActivityMemberUnlock, api.ActivityInfo, &api.ActivityMemberLoginPayload{
+		PrincipalID:    user.ID,
+		PrincipalName:  user.Name,
+		PrincipalEmail: user.Email,
+	})
+}
+
+// isUserLocked returns true if the account of the user is locked.
+func (s *AuthService) isUserLocked(ctx context.Context, user *store.UserMessage) (bool, error) {
+	throttle, err := s.store.GetLoginThrottle(ctx, store.LoginThrottleAccount, strings.ToLower(user.Email))
+	if err != nil {
+		return false, status.Errorf(codes.Internal, "failed to get login throttle, error: %v", err)
+	}
+	return throttle != nil && throttle.LockedUntilTs > time.Now().Unix(), nil
+}
+
+// listLockedEmails returns the emails of the locked accounts.
+func (s *AuthService) listLockedEmails(ctx context.Context) (map[string]bool, error) {
+	accountType := store.LoginThrottleAccount
+	throttles, err := s.store.ListLoginThrottles(ctx, &store.FindLoginThrottleMessage{Type: &accountType, LockedOnly: true})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list login throttles, error: %v", err)
+	}
+	lockedEmails := make(map[string]bool)
+	for _, throttle := range throttles {
+		lockedEmails[throttle.Subject] = true
+	}
+	return lockedEmails, nil
+}
+
+func (s *AuthService) createMemberLoginActivity(ctx context.Context, creatorID int, activityType api.ActivityType, level api.ActivityLevel, payload *api.ActivityMemberLoginPayload) error {
+	bytes, err := json.Marshal(payload)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to construct activity payload, error: %v", err)
+	}
+	if _, err := s.store.CreateActivityV2(ctx, &store.ActivityMessage{
+		CreatorUID:   creatorID,
+		ContainerUID: payload.PrincipalID,
+		Type:         activityType,
+		Level:        level,
+		Payload:      string(bytes),
+	}); err != nil {
+		return status.Errorf(codes.Internal, "failed to create activity, error: %v", err)
+	}
+	return nil
+}
+
+// getLoginDelay returns the progressive delay, which doubles for every failure after the threshold.
+func getLoginDelay(failureCount int) time.Duration {
+	if failureCount < loginDelayFailureThreshold {
+		return 0
+	}
+	delay := time.Second
+	for i := loginDelayFailureThreshold; i < failureCount; i++ {
+		delay *= 2
+		if delay >= maxLoginDelay {
+			return maxLoginDelay
+		}
+	}
+	return delay
+}
@@ -0,0 +1,78 @@
+package v1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+)
+
+func TestValidatePassword(t *testing.T) {
+	policy := &api.SettingPasswordPolicyValue{
+		MinLength:               8,
+		RequireUppercase:        true,
+		RequireLowercase:        true,
+		RequireNumber:           true,
+		RequireSpecialCharacter: true,
+	}
+	tests := []struct {
+		password string
+		wantErr  bool
+	}{
+		{
+			password: "Bytebase1!",
+			wantErr:  false,
+		},
+		{
+			password: "Byte1!",
+			wantErr:  true,
+		},
+		{
+			password: "bytebase1!",
+			wantErr:  true,
+		},
+		{
+			password: "BYTEBASE1!",
+			wantErr:  true,
+		},
+		{
+			password: "Bytebase!!",
+			wantErr:  true,
+		},
+		{
+			password: "Bytebase12",
+			wantErr:  true,
+		},
+	}
+
+	for _, test := range tests {
+		err := validatePassword(policy, test.password)
+		if test.wantErr {
+			require.Error(t, err, test.password)
+		} else {
+			require.NoError(t, err, test.password)
+		}
+	}
+	require.NoError(t, validatePassword(&api.SettingPasswordPolicyValue{}, "a"))
+}
+
+func TestGetLoginDelay(t *testing.T) {
+	tests := []struct {
+		failureCount int
+		want         time.Duration
+	}{
+		{failureCount: 1, want: 0},
+		{failureCount: 2, want: 0},
+		{failureCount: 3, want: time.Second},
+		{failureCount: 4, want: 2 * time.Second},
+		{failureCount: 6, want: 8 * time.Second},
+		{failureCount: 8, want: maxLoginDelay},
+		{failureCount: 100, want: maxLoginDelay},
+	}
+
+	for _, test := range tests {
+		require.Equal(t, test.want, getLoginDelay(test.failureCount), test.failureCount)
+	}
+}
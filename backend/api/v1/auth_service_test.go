@@ -1,34 +1,53 @@
 package v1
 
-import "testing"
+import (
+	"testing"
 
-func TestExtractDomain(t *testing.T) {
+	"github.com/stretchr/testify/require"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+)
+
+func TestMatchClaimMappings(t *testing.T) {
+	dba := &api.IdentityProviderClaimMapping{Claim: "groups", Value: "dba", WorkspaceRole: api.DBA}
+	hr := &api.IdentityProviderClaimMapping{Claim: "groups", Value: "hr", Project: "hr", Role: "roles/DEVELOPER"}
+	hrKey := claimBindingKey{project: "hr", role: "roles/DEVELOPER"}
 	tests := []struct {
-		domain string
-		want   string
+		name          string
+		config        *api.IdentityProviderClaimMappingConfig
+		claims        map[string]any
+		workspaceRole api.Role
+		granted       map[claimBindingKey]bool
 	}{
 		{
-			domain: "www.google.com",
-			want:   "google.com",
-		},
-		{
-			domain: "code.google.com",
-			want:   "google.com",
+			name:          "mapped workspace role and project role",
+			config:        &api.IdentityProviderClaimMappingConfig{ClaimMappings: []*api.IdentityProviderClaimMapping{dba, hr}},
+			claims:        map[string]any{"groups": []any{"dba", "hr"}},
+			workspaceRole: api.DBA,
+			granted:       map[claimBindingKey]bool{hrKey: true},
 		},
 		{
-			domain: "code.google.com.cn",
-			want:   "google.com.cn",
+			name:          "default workspace role",
+			config:        &api.IdentityProviderClaimMappingConfig{ClaimMappings: []*api.IdentityProviderClaimMapping{dba, hr}, DefaultWorkspaceRole: api.Developer},
+			claims:        map[string]any{"groups": []any{"hr"}},
+			workspaceRole: api.Developer,
+			granted:       map[claimBindingKey]bool{hrKey: true},
 		},
 		{
-			domain: "google.com",
-			want:   "google.com",
+			// The workspace role, e.g. the owner, is kept without the default workspace role.
+			name:    "keep workspace role",
+			config:  &api.IdentityProviderClaimMappingConfig{ClaimMappings: []*api.IdentityProviderClaimMapping{dba, hr}},
+			claims:  map[string]any{"groups": []any{"sales"}},
+			granted: map[claimBindingKey]bool{},
 		},
 	}
 
+	a := require.New(t)
 	for _, test := range tests {
-		got := extractDomain(test.domain)
-		if got != test.want {
-			t.Errorf("extractDomain %s, got %s, want %s", test.domain, got, test.want)
-		}
+		workspaceRole, bindingKeys, granted, err := matchClaimMappings(test.config, test.claims)
+		a.NoError(err, test.name)
+		a.Equal(test.workspaceRole, workspaceRole, test.name)
+		a.Equal([]claimBindingKey{hrKey}, bindingKeys, test.name)
+		a.Equal(test.granted, granted, test.name)
 	}
 }
@@ -0,0 +1,368 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/plugin/webauthn"
+	"github.com/bytebase/bytebase/backend/store"
+	storepb "github.com/bytebase/bytebase/proto/generated-go/store"
+	v1pb "github.com/bytebase/bytebase/proto/generated-go/v1"
+)
+
+const (
+	webAuthnRelyingPartyName = "Bytebase"
+	// webAuthnPasswordlessSubject is the challenge subject of the passwordless login, which is not bound to any user
+	// because the user is identified by the user handle of the discoverable credential.
+	webAuthnPasswordlessSubject = "login:"
+)
+
+func getWebAuthnRegisterSubject(userID int) string {
+	return fmt.Sprintf("register:%d", userID)
+}
+
+func getWebAuthnLoginSubject(userID int) string {
+	return fmt.Sprintf("login:%d", userID)
+}
+
+// getWebAuthnUserHandle returns the user handle of the credentials, i.e. the user ID.
+func getWebAuthnUserHandle(userID int) []byte {
+	return []byte(strconv.Itoa(userID))
+}
+
+func (s *AuthService) getRelyingParty(ctx context.Context) (*webauthn.RelyingParty, *api.SettingWebAuthnValue, error) {
+	setting, err := s.store.GetWebAuthnSetting(ctx)
+	if err != nil {
+		return nil, nil, status.Errorf(codes.Internal, "failed to get WebAuthn setting, error: %v", err)
+	}
+	if setting.RPID == "" || len(setting.Origins) == 0 {
+		return nil, nil, status.Errorf(codes.FailedPrecondition, "external URL or WebAuthn setting is required for WebAuthn")
+	}
+	rp, err := webauthn.NewRelyingParty(setting.RPID, webAuthnRelyingPartyName, setting.Origins)
+	if err != nil {
+		return nil, nil, status.Errorf(codes.Internal, "failed to create WebAuthn relying party, error: %v", err)
+	}
+	if len(setting.HardwareKeyAttestationRoots) > 0 {
+		roots, err := webauthn.ParseAttestationRoots(setting.HardwareKeyAttestationRoots)
+		if err != nil {
+			return nil, nil, status.Errorf(codes.Internal, "failed to parse hardware key attestation roots, error: %v", err)
+		}
+		rp.AttestationRoots = roots
+	}
+	return rp, setting, nil
+}
+
+// issueWebAuthnChallenge returns a new challenge of the subject, which is valid until it's consumed or expired.
+func (s *AuthService) issueWebAuthnChallenge(ctx context.Context, subject string) (string, error) {
+	challenge, err := webauthn.NewChallenge()
+	if err != nil {
+		return "", status.Errorf(codes.Internal, "failed to generate WebAuthn challenge, error: %v", err)
+	}
+	if err := s.store.CreateWebAuthnChallenge(ctx, challenge, subject, time.Now().Add(webauthn.ChallengeTimeout).Unix()); err != nil {
+		return "", status.Errorf(codes.Internal, "failed to create WebAuthn challenge, error: %v", err)
+	}
+	return challenge, nil
+}
+
+// consumeWebAuthnChallenge consumes the challenge of the response issued for the subject, so that the response cannot be replayed.
+func (s *AuthService) consumeWebAuthnChallenge(ctx context.Context, subject string, response *webauthn.CredentialResponse) (string, error) {
+	challenge, err := response.Challenge()
+	if err != nil {
+		return "", status.Errorf(codes.InvalidArgument, "invalid WebAuthn challenge, error: %v", err)
+	}
+	ok, err := s.store.ConsumeWebAuthnChallenge(ctx, challenge, subject)
+	if err != nil {
+		return "", status.Errorf(codes.Internal, "failed to consume WebAuthn challenge, error: %v", err)
+	}
+	if !ok {
+		return "", status.Errorf(codes.Unauthenticated, "WebAuthn challenge is invalid, expired or already used")
+	}
+	return challenge, nil
+}
+
+// generateWebAuthnCreationOptions returns the JSON serialized options for registering a new credential of the user.
+func (s *AuthService) generateWebAuthnCreationOptions(ctx context.Context, user *store.UserMessage) (string, error) {
+	rp, _, err := s.getRelyingParty(ctx)
+	if err != nil {
+		return "", err
+	}
+	challenge, err := s.issueWebAuthnChallenge(ctx, getWebAuthnRegisterSubject(user.ID))
+	if err != nil {
+		return "", err
+	}
+	var excludeCredentialIDs [][]byte
+	for _, credential := range user.MFAConfig.GetWebauthnCredentials() {
+		excludeCredentialIDs = append(excludeCredentialIDs, credential.Id)
+	}
+	options := rp.NewCreationOptions(challenge, getWebAuthnUserHandle(user.ID), user.Email, user.Name, excludeCredentialIDs)
+	bytes, err := json.Marshal(options)
+	if err != nil {
+		return "", status.Errorf(codes.Internal, "failed to marshal WebAuthn creation options, error: %v", err)
+	}
+	return string(bytes), nil
+}
+
+// generateWebAuthnRequestOptions returns the JSON serialized options for the assertion of the user,
+// or the options for the passwordless login if the user is nil.
+func (s *AuthService) generateWebAuthnRequestOptions(ctx context.Context, user *store.UserMessage) (string, error) {
+	rp, setting, err := s.getRelyingParty(ctx)
+	if err != nil {
+		return "", err
+	}
+	subject := webAuthnPasswordlessSubject
+	var allowCredentialIDs [][]byte
+	if user != nil {
+		subject = getWebAuthnLoginSubject(user.ID)
+		for _, credential := range user.MFAConfig.GetWebauthnCredentials() {
+			allowCredentialIDs = append(allowCredentialIDs, credential.Id)
+		}
+	} else if !setting.AllowPasswordless {
+		return "", status.Errorf(codes.FailedPrecondition, "passwordless login is not allowed")
+	}
+	challenge, err := s.issueWebAuthnChallenge(ctx, subject)
+	if err != nil {
+		return "", err
+	}
+	options := rp.NewRequestOptions(challenge, allowCredentialIDs, user == nil)
+	bytes, err := json.Marshal(options)
+	if err != nil {
+		return "", status.Errorf(codes.Internal, "failed to marshal WebAuthn request options, error: %v", err)
+	}
+	return string(bytes), nil
+}
+
+// registerWebAuthnCredential verifies the registration response and adds the credential to the MFA config.
+func (s *AuthService) registerWebAuthnCredential(ctx context.Context, user *store.UserMessage, mfaConfig *storepb.MFAConfig, registration string) error {
+	rp, setting, err := s.getRelyingParty(ctx)
+	if err != nil {
+		return err
+	}
+	response, err := webauthn.ParseCredentialResponse(registration)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid WebAuthn registration, error: %v", err)
+	}
+	challenge, err := s.consumeWebAuthnChallenge(ctx, getWebAuthnRegisterSubject(user.ID), response)
+	if err != nil {
+		return err
+	}
+	credential, err := rp.VerifyRegistration(challenge, response)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "failed to verify WebAuthn registration, error: %v", err)
+	}
+	if slices.ContainsFunc(mfaConfig.WebauthnCredentials, func(c *storepb.WebAuthnCredential) bool {
+		return bytes.Equal(c.Id, credential.ID)
+	}) {
+		return status.Errorf(codes.AlreadyExists, "WebAuthn credential is already registered")
+	}
+	newCredential := &storepb.WebAuthnCredential{
+		Id:             credential.ID,
+		PublicKey:      credential.PublicKey,
+		SignCount:      credential.SignCount,
+		BackupEligible: credential.BackupEligible,
+		CreatedTs:      time.Now().Unix(),
+		Attested:       credential.Attested,
+		Aaguid:         credential.AAGUID,
+	}
+	name := "Passkey"
+	if isHardwareBoundCredential(setting, newCredential) {
+		name = "Security key"
+	}
+	newCredential.Name = fmt.Sprintf("%s %d", name, len(mfaConfig.WebauthnCredentials)+1)
+	mfaConfig.WebauthnCredentials = append(mfaConfig.WebauthnCredentials, newCredential)
+	return nil
+}
+
+// isHardwareBoundCredential returns true if the credential is vouched for by its vendor with the attestation,
+// and the authenticator model is allowed. The backup eligible flag is reported by the authenticator itself,
+// so it doesn't prove that the credential cannot be synced or exported.
+func isHardwareBoundCredential(setting *api.SettingWebAuthnValue, credential *storepb.WebAuthnCredential) bool {
+	if !credential.Attested || credential.BackupEligible {
+		return false
+	}
+	if len(setting.HardwareKeyAAGUIDs) == 0 {
+		return true
+	}
+	aaguid, err := uuid.FromBytes(credential.Aaguid)
+	if err != nil {
+		return false
+	}
+	return slices.ContainsFunc(setting.HardwareKeyAAGUIDs, func(s string) bool {
+		allowed, err := uuid.Parse(s)
+		return err == nil && allowed == aaguid
+	})
+}
+
+// updateWebAuthnCredentials renames the credentials and removes the credentials absent from the list.
+func updateWebAuthnCredentials(mfaConfig *storepb.MFAConfig, credentials []*v1pb.WebAuthnCredential) error {
+	names := make(map[string]string)
+	for _, credential := range credentials {
+		names[credential.Id] = credential.Name
+	}
+	var updated []*storepb.WebAuthnCredential
+	for _, credential := range mfaConfig.WebauthnCredentials {
+		name, ok := names[base64.RawURLEncoding.EncodeToString(credential.Id)]
+		if !ok {
+			continue
+		}
+		if name != "" {
+			credential.Name = name
+		}
+		updated = append(updated, credential)
+	}
+	if len(updated) != len(credentials) {
+		return status.Errorf(codes.InvalidArgument, "WebAuthn credentials can only be renamed or removed")
+	}
+	mfaConfig.WebauthnCredentials = updated
+	return nil
+}
+
+// hardwareKeyRequirement is the requirement of the hardware-bound credential for the user to sign in.
+type hardwareKeyRequirement int
+
+const (
+	hardwareKeyNotRequired hardwareKeyRequirement = iota
+	// hardwareKeyRequired means the user must pass the MFA with a registered hardware-bound credential.
+	hardwareKeyRequired
+	// hardwareKeyEnrollmentRequired means the user has no hardware-bound credential after the grace period,
+	// and can only sign in to register one.
+	hardwareKeyEnrollmentRequired
+)
+
+func (s *AuthService) getHardwareKeyRequirement(ctx context.Context, user *store.UserMessage) (hardwareKeyRequirement, error) {
+	if user.Type != api.EndUser {
+		return hardwareKeyNotRequired, nil
+	}
+	setting, err := s.store.GetWebAuthnSetting(ctx)
+	if err != nil {
+		return hardwareKeyNotRequired, status.Errorf(codes.Internal, "failed to get WebAuthn setting, error: %v", err)
+	}
+	return getHardwareKeyRequirement(setting, user, time.Now()), nil
+}
+
+func getHardwareKeyRequirement(setting *api.SettingWebAuthnValue, user *store.UserMessage, now time.Time) hardwareKeyRequirement {
+	if !slices.Contains(setting.RequireHardwareKeyRoles, user.Role) {
+		return hardwareKeyNotRequired
+	}
+	if slices.ContainsFunc(user.MFAConfig.GetWebauthnCredentials(), func(c *storepb.WebAuthnCredential) bool {
+		return isHardwareBoundCredential(setting, c)
+	}) {
+		return hardwareKeyRequired
+	}
+	// The users can still sign in with the other factors during the grace period.
+	if now.Unix() < setting.HardwareKeyGracePeriodEndTs {
+		return hardwareKeyNotRequired
+	}
+	return hardwareKeyEnrollmentRequired
+}
+
+// challengeWebAuthnAssertion verifies the assertion with the credentials of the user, and updates the signature counter.
+func (s *AuthService) challengeWebAuthnAssertion(ctx context.Context, user *store.UserMessage, response *webauthn.CredentialResponse, subject string, requireUserVerification, requireHardwareKey bool) error {
+	rp, setting, err := s.getRelyingParty(ctx)
+	if err != nil {
+		return err
+	}
+	credentialID, err := response.CredentialID()
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid WebAuthn assertion, error: %v", err)
+	}
+	// Consume the challenge before any other check, so that every assertion attempt burns its challenge.
+	challenge, err := s.consumeWebAuthnChallenge(ctx, subject, response)
+	if err != nil {
+		return err
+	}
+	index := slices.IndexFunc(user.MFAConfig.GetWebauthnCredentials(), func(c *storepb.WebAuthnCredential) bool {
+		return bytes.Equal(c.Id, credentialID)
+	})
+	if index < 0 {
+		return status.Errorf(codes.Unauthenticated, "invalid WebAuthn credential")
+	}
+	credential := user.MFAConfig.WebauthnCredentials[index]
+	if requireHardwareKey && !isHardwareBoundCredential(setting, credential) {
+		return status.Errorf(codes.Unauthenticated, "hardware security key is required for your role")
+	}
+	result, err := rp.VerifyAssertion(challenge, &webauthn.Credential{
+		ID:             credential.Id,
+		PublicKey:      credential.PublicKey,
+		SignCount:      credential.SignCount,
+		BackupEligible: credential.BackupEligible,
+	}, response)
+	if err != nil {
+		return status.Errorf(codes.Unauthenticated, "failed to verify WebAuthn assertion, error: %v", err)
+	}
+	if requireUserVerification && !result.UserVerified {
+		return status.Errorf(codes.Unauthenticated, "user verification is required for passwordless login")
+	}
+	credential.SignCount = result.SignCount
+	if _, err := s.store.UpdateUser(ctx, user.ID, &store.UpdateUserMessage{MFAConfig: user.MFAConfig}, user.ID); err != nil {
+		return status.Errorf(codes.Internal, "failed to update user: %v", err)
+	}
+	return nil
+}
+
+// getUserWithPasskey verifies the passwordless assertion and returns the user identified by the user handle.
+func (s *AuthService) getUserWithPasskey(ctx context.Context, assertion string) (*store.UserMessage, error) {
+	_, setting, err := s.getRelyingParty(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !setting.AllowPasswordless {
+		return nil, status.Errorf(codes.FailedPrecondition, "passwordless login is not allowed")
+	}
+	response, err := webauthn.ParseCredentialResponse(assertion)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid WebAuthn assertion, error: %v", err)
+	}
+	userHandle, err := response.UserHandle()
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid WebAuthn user handle, error: %v", err)
+	}
+	userID, err := strconv.Atoi(string(userHandle))
+	if err != nil {
+		return nil, invalidUserOrPasswordError
+	}
+	user, err := s.store.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get user, error: %v", err)
+	}
+	if user == nil || user.Type != api.EndUser {
+		return nil, invalidUserOrPasswordError
+	}
+	locked, err := s.isUserLocked(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	if locked {
+		return nil, status.Errorf(codes.ResourceExhausted, "too many failed login attempts, please try again later")
+	}
+	requirement, err := s.getHardwareKeyRequirement(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.challengeWebAuthnAssertion(ctx, user, response, webAuthnPasswordlessSubject, true /* requireUserVerification */, requirement == hardwareKeyRequired); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func convertToWebAuthnCredentials(mfaConfig *storepb.MFAConfig) []*v1pb.WebAuthnCredential {
+	var credentials []*v1pb.WebAuthnCredential
+	for _, credential := range mfaConfig.GetWebauthnCredentials() {
+		credentials = append(credentials, &v1pb.WebAuthnCredential{
+			Id:            base64.RawURLEncoding.EncodeToString(credential.Id),
+			Name:          credential.Name,
+			HardwareBound: credential.Attested && !credential.BackupEligible,
+		})
+	}
+	return credentials
+}
@@ -0,0 +1,133 @@
+package v1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/store"
+	storepb "github.com/bytebase/bytebase/proto/generated-go/store"
+)
+
+func TestGetHardwareKeyRequirement(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	securityKey := &storepb.WebAuthnCredential{Id: []byte("security-key"), Attested: true}
+	passkey := &storepb.WebAuthnCredential{Id: []byte("passkey"), BackupEligible: true}
+	// The software authenticator can claim the credential is not backup eligible without the attestation.
+	unattestedKey := &storepb.WebAuthnCredential{Id: []byte("unattested-key")}
+	tests := []struct {
+		name             string
+		role             api.Role
+		credentials      []*storepb.WebAuthnCredential
+		gracePeriodEndTs int64
+		want             hardwareKeyRequirement
+	}{
+		{
+			name: "role not required",
+			role: api.Developer,
+			want: hardwareKeyNotRequired,
+		},
+		{
+			name:        "registered security key",
+			role:        api.DBA,
+			credentials: []*storepb.WebAuthnCredential{passkey, securityKey},
+			want:        hardwareKeyRequired,
+		},
+		{
+			name:        "passkey only",
+			role:        api.DBA,
+			credentials: []*storepb.WebAuthnCredential{passkey},
+			want:        hardwareKeyEnrollmentRequired,
+		},
+		{
+			name:        "unattested key only",
+			role:        api.DBA,
+			credentials: []*storepb.WebAuthnCredential{unattestedKey},
+			want:        hardwareKeyEnrollmentRequired,
+		},
+		{
+			name: "no credential",
+			role: api.Owner,
+			want: hardwareKeyEnrollmentRequired,
+		},
+		{
+			name:             "grace period",
+			role:             api.Owner,
+			gracePeriodEndTs: now.Add(time.Hour).Unix(),
+			want:             hardwareKeyNotRequired,
+		},
+		{
+			name:             "grace period ended",
+			role:             api.Owner,
+			gracePeriodEndTs: now.Add(-time.Hour).Unix(),
+			want:             hardwareKeyEnrollmentRequired,
+		},
+		{
+			name:             "registered security key during grace period",
+			role:             api.Owner,
+			credentials:      []*storepb.WebAuthnCredential{securityKey},
+			gracePeriodEndTs: now.Add(time.Hour).Unix(),
+			want:             hardwareKeyRequired,
+		},
+	}
+
+	for _, test := range tests {
+		setting := &api.SettingWebAuthnValue{
+			RequireHardwareKeyRoles:     []api.Role{api.Owner, api.DBA},
+			HardwareKeyGracePeriodEndTs: test.gracePeriodEndTs,
+		}
+		user := &store.UserMessage{
+			Role:      test.role,
+			MFAConfig: &storepb.MFAConfig{WebauthnCredentials: test.credentials},
+		}
+		require.Equal(t, test.want, getHardwareKeyRequirement(setting, user, now), test.name)
+	}
+}
+
+func TestIsHardwareBoundCredential(t *testing.T) {
+	yubiKey := []byte{0xcb, 0x69, 0x48, 0x1e, 0x8f, 0xf7, 0x40, 0x39, 0x93, 0xec, 0x0a, 0x27, 0x29, 0xa1, 0x54, 0xa8}
+	tests := []struct {
+		name       string
+		aaguids    []string
+		credential *storepb.WebAuthnCredential
+		want       bool
+	}{
+		{
+			name:       "attested",
+			credential: &storepb.WebAuthnCredential{Attested: true, Aaguid: yubiKey},
+			want:       true,
+		},
+		{
+			name:       "not attested",
+			credential: &storepb.WebAuthnCredential{Aaguid: yubiKey},
+		},
+		{
+			name:       "attested but backup eligible",
+			credential: &storepb.WebAuthnCredential{Attested: true, BackupEligible: true, Aaguid: yubiKey},
+		},
+		{
+			name:       "allowed AAGUID",
+			aaguids:    []string{"CB69481E-8FF7-4039-93EC-0A2729A154A8"},
+			credential: &storepb.WebAuthnCredential{Attested: true, Aaguid: yubiKey},
+			want:       true,
+		},
+		{
+			name:       "disallowed AAGUID",
+			aaguids:    []string{"00000000-0000-0000-0000-000000000000"},
+			credential: &storepb.WebAuthnCredential{Attested: true, Aaguid: yubiKey},
+		},
+		{
+			name:       "U2F security key",
+			aaguids:    []string{"00000000-0000-0000-0000-000000000000"},
+			credential: &storepb.WebAuthnCredential{Attested: true, Aaguid: make([]byte, 16)},
+			want:       true,
+		},
+	}
+
+	for _, test := range tests {
+		setting := &api.SettingWebAuthnValue{HardwareKeyAAGUIDs: test.aaguids}
+		require.Equal(t, test.want, isHardwareBoundCredential(setting, test.credential), test.name)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"log/slog"
 	"regexp"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -40,6 +41,7 @@ import (
 	"github.com/bytebase/bytebase/backend/runner/schemasync"
 	"github.com/bytebase/bytebase/backend/store"
 	"github.com/bytebase/bytebase/backend/store/model"
+	"github.com/bytebase/bytebase/backend/utils"
 	storepb "github.com/bytebase/bytebase/proto/generated-go/store"
 	v1pb "github.com/bytebase/bytebase/proto/generated-go/v1"
 )
@@ -1034,6 +1036,86 @@ func (s *DatabaseService) GetChangeHistory(ctx context.Context, request *v1pb.Ge
 	return converted, nil
 }
 
+// ListDatabaseGrants lists the active query and export grants of the database.
+func (s *DatabaseService) ListDatabaseGrants(ctx context.Context, request *v1pb.ListDatabaseGrantsRequest) (*v1pb.ListDatabaseGrantsResponse, error) {
+	instanceID, databaseName, err := common.GetInstanceDatabaseID(request.Parent)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, err.Error())
+	}
+	instance, err := s.store.GetInstanceV2(ctx, &store.FindInstanceMessage{
+		ResourceID: &instanceID,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+	if instance == nil {
+		return nil, status.Errorf(codes.NotFound, "instance %q not found", instanceID)
+	}
+	database, err := s.store.GetDatabaseV2(ctx, &store.FindDatabaseMessage{
+		InstanceID:          &instanceID,
+		DatabaseName:        &databaseName,
+		IgnoreCaseSensitive: store.IgnoreDatabaseAndTableCaseSensitive(instance),
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+	if database == nil {
+		return nil, status.Errorf(codes.NotFound, "database %q not found", databaseName)
+	}
+	if err := s.checkDatabasePermission(ctx, database.ProjectID, api.ProjectPermissionManageGeneral); err != nil {
+		return nil, err
+	}
+
+	policy, err := s.store.GetProjectPolicy(ctx, &store.GetProjectPolicyMessage{ProjectID: &database.ProjectID})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get project policy, error: %v", err)
+	}
+	databaseResource := fmt.Sprintf("%s%s/%s%s", common.InstanceNamePrefix, instance.ResourceID, common.DatabaseIDPrefix, database.DatabaseName)
+	now := time.Now()
+	response := &v1pb.ListDatabaseGrantsResponse{}
+	for _, binding := range policy.Bindings {
+		if binding.Role != api.Querier && binding.Role != api.Exporter {
+			continue
+		}
+		var expression string
+		var expireTime time.Time
+		if binding.Condition != nil && binding.Condition.Expression != "" {
+			expression = binding.Condition.Expression
+			factors, err := common.GetQueryExportFactors(expression)
+			if err != nil {
+				slog.Warn("failed to parse the grant condition", slog.String("expression", expression), log.BBError(err))
+				continue
+			}
+			// The grant applies to all databases in the project if no database is specified.
+			if len(factors.DatabaseNames) > 0 && !slices.Contains(factors.DatabaseNames, databaseResource) {
+				continue
+			}
+			expireTime = factors.ExpireTime
+		}
+		// The expired grants are pending to be revoked.
+		if !expireTime.IsZero() && !expireTime.After(now) {
+			continue
+		}
+		var issue string
+		if issueUID := utils.GetGrantIssueUID(binding); issueUID != 0 {
+			issue = fmt.Sprintf("%s%s/%s%d", common.ProjectNamePrefix, database.ProjectID, common.IssuePrefix, issueUID)
+		}
+		for _, member := range binding.Members {
+			grant := &v1pb.DatabaseGrant{
+				Member:     fmt.Sprintf("user:%s", member.Email),
+				Role:       convertToProjectRole(binding.Role),
+				Expression: expression,
+				Issue:      issue,
+			}
+			if !expireTime.IsZero() {
+				grant.ExpireTime = timestamppb.New(expireTime)
+			}
+			response.Grants = append(response.Grants, grant)
+		}
+	}
+	return response, nil
+}
+
 // DiffSchema diff the database schema.
 func (s *DatabaseService) DiffSchema(ctx context.Context, request *v1pb.DiffSchemaRequest) (*v1pb.DiffSchemaResponse, error) {
 	source, err := s.getSourceSchema(ctx, request)
@@ -325,6 +325,10 @@ func convertToExternalVersionControl(externalVersionControl *store.ExternalVersi
 		tp = v1pb.ExternalVersionControl_BITBUCKET
 	case vcs.AzureDevOps:
 		tp = v1pb.ExternalVersionControl_AZURE_DEVOPS
+	case vcs.Gitea:
+		tp = v1pb.ExternalVersionControl_GITEA
+	case vcs.BitbucketDataCenter:
+		tp = v1pb.ExternalVersionControl_BITBUCKET_DATA_CENTER
 	}
 
 	return &v1pb.ExternalVersionControl{
@@ -380,6 +384,10 @@ func convertExternalVersionControlTypeToVCSType(tp v1pb.ExternalVersionControl_T
 		return vcs.Bitbucket, nil
 	case v1pb.ExternalVersionControl_AZURE_DEVOPS:
 		return vcs.AzureDevOps, nil
+	case v1pb.ExternalVersionControl_GITEA:
+		return vcs.Gitea, nil
+	case v1pb.ExternalVersionControl_BITBUCKET_DATA_CENTER:
+		return vcs.BitbucketDataCenter, nil
 	}
 	return "", errors.Errorf("unknown external version control type: %v", tp)
 }
@@ -14,6 +14,7 @@ import (
 
 	"github.com/bytebase/bytebase/backend/common"
 	"github.com/bytebase/bytebase/backend/common/log"
+	"github.com/bytebase/bytebase/backend/component/activity"
 	"github.com/bytebase/bytebase/backend/component/dbfactory"
 	"github.com/bytebase/bytebase/backend/component/secret"
 	"github.com/bytebase/bytebase/backend/component/state"
@@ -22,6 +23,7 @@ import (
 	metricapi "github.com/bytebase/bytebase/backend/metric"
 	"github.com/bytebase/bytebase/backend/plugin/metric"
 	pgparser "github.com/bytebase/bytebase/backend/plugin/parser/pg"
+	webhookplugin "github.com/bytebase/bytebase/backend/plugin/webhook"
 	"github.com/bytebase/bytebase/backend/runner/metricreport"
 	"github.com/bytebase/bytebase/backend/runner/schemasync"
 	"github.com/bytebase/bytebase/backend/store"
@@ -32,25 +34,27 @@ import (
 // InstanceService implements the instance service.
 type InstanceService struct {
 	v1pb.UnimplementedInstanceServiceServer
-	store          *store.Store
-	licenseService enterprise.LicenseService
-	metricReporter *metricreport.Reporter
-	secret         string
-	stateCfg       *state.State
-	dbFactory      *dbfactory.DBFactory
-	schemaSyncer   *schemasync.Syncer
+	store           *store.Store
+	licenseService  enterprise.LicenseService
+	metricReporter  *metricreport.Reporter
+	secret          string
+	stateCfg        *state.State
+	dbFactory       *dbfactory.DBFactory
+	schemaSyncer    *schemasync.Syncer
+	activityManager *activity.Manager
 }
 
 // NewInstanceService creates a new InstanceService.
-func NewInstanceService(store *store.Store, licenseService enterprise.LicenseService, metricReporter *metricreport.Reporter, secret string, stateCfg *state.State, dbFactory *dbfactory.DBFactory, schemaSyncer *schemasync.Syncer) *InstanceService {
+func NewInstanceService(store *store.Store, licenseService enterprise.LicenseService, metricReporter *metricreport.Reporter, secret string, stateCfg *state.State, dbFactory *dbfactory.DBFactory, schemaSyncer *schemasync.Syncer, activityManager *activity.Manager) *InstanceService {
 	return &InstanceService{
-		store:          store,
-		licenseService: licenseService,
-		metricReporter: metricReporter,
-		secret:         secret,
-		stateCfg:       stateCfg,
-		dbFactory:      dbFactory,
-		schemaSyncer:   schemaSyncer,
+		store:           store,
+		licenseService:  licenseService,
+		metricReporter:  metricReporter,
+		secret:          secret,
+		stateCfg:        stateCfg,
+		dbFactory:       dbFactory,
+		schemaSyncer:    schemaSyncer,
+		activityManager: activityManager,
 	}
 }
 
@@ -166,6 +170,13 @@ func (s *InstanceService) CreateInstance(ctx context.Context, request *v1pb.Crea
 			"engine": instance.Engine,
 		},
 	})
+	s.activityManager.PostEvent(ctx, &activity.Event{
+		Type:       api.EventInstanceCreate,
+		Level:      webhookplugin.WebhookInfo,
+		Title:      fmt.Sprintf("Instance added - %s", instance.Title),
+		CreatorUID: principalID,
+		Instance:   instance,
+	})
 
 	return convertToInstance(instance), nil
 }
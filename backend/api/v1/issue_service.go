@@ -783,7 +783,7 @@ func (s *IssueService) ApproveIssue(ctx context.Context, request *v1pb.ApproveIs
 		return nil, status.Errorf(codes.Internal, "failed to get project policy, error: %v", err)
 	}
 
-	canApprove, err := isUserReviewer(step, user, policy)
+	canApprove, delegatorID, err := s.canUserReviewStep(ctx, payload.Approval, step, user, policy)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to check if principal can approve step, error: %v", err)
 	}
@@ -794,6 +794,7 @@ func (s *IssueService) ApproveIssue(ctx context.Context, request *v1pb.ApproveIs
 	payload.Approval.Approvers = append(payload.Approval.Approvers, &storepb.IssuePayloadApproval_Approver{
 		Status:      storepb.IssuePayloadApproval_Approver_APPROVED,
 		PrincipalId: int32(principalID),
+		DelegatorId: int32(delegatorID),
 	})
 
 	approved, err := utils.CheckApprovalApproved(payload.Approval)
@@ -801,7 +802,7 @@ func (s *IssueService) ApproveIssue(ctx context.Context, request *v1pb.ApproveIs
 		return nil, status.Errorf(codes.Internal, "failed to check if the approval is approved, error: %v", err)
 	}
 
-	newApprovers, activityCreates, err := utils.HandleIncomingApprovalSteps(ctx, s.store, s.relayRunner.Client, issue, payload.Approval)
+	newApprovers, activityCreates, err := utils.HandleIncomingApprovalSteps(ctx, s.store, s.relayRunner.NewProvider, issue, payload.Approval)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to handle incoming approval steps, error: %v", err)
 	}
@@ -968,7 +969,7 @@ func (s *IssueService) RejectIssue(ctx context.Context, request *v1pb.RejectIssu
 		return nil, status.Errorf(codes.Internal, "failed to get project policy, error: %v", err)
 	}
 
-	canApprove, err := isUserReviewer(step, user, policy)
+	canApprove, delegatorID, err := s.canUserReviewStep(ctx, payload.Approval, step, user, policy)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to check if principal can reject step, error: %v", err)
 	}
@@ -978,6 +979,7 @@ func (s *IssueService) RejectIssue(ctx context.Context, request *v1pb.RejectIssu
 	payload.Approval.Approvers = append(payload.Approval.Approvers, &storepb.IssuePayloadApproval_Approver{
 		Status:      storepb.IssuePayloadApproval_Approver_REJECTED,
 		PrincipalId: int32(principalID),
+		DelegatorId: int32(delegatorID),
 	})
 
 	issue, err = s.store.UpdateIssueV2(ctx, issue.UID, &store.UpdateIssueMessage{
@@ -1072,8 +1074,12 @@ func (s *IssueService) RequestIssue(ctx context.Context, request *v1pb.RequestIs
 		newApprovers = append(newApprovers, approver)
 	}
 	payload.Approval.Approvers = newApprovers
+	// The rejected step starts over, so its timeout and reminders start over as well.
+	payload.Approval.PendingStepStartTs = 0
+	payload.Approval.PendingStepRemindedTs = 0
+	payload.Approval.PendingStepEscalated = false
 
-	newApprovers, activityCreates, err := utils.HandleIncomingApprovalSteps(ctx, s.store, s.relayRunner.Client, issue, payload.Approval)
+	newApprovers, activityCreates, err := utils.HandleIncomingApprovalSteps(ctx, s.store, s.relayRunner.NewProvider, issue, payload.Approval)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to handle incoming approval steps, error: %v", err)
 	}
@@ -1551,6 +1557,50 @@ func canRequestIssue(issueCreator *store.UserMessage, user *store.UserMessage) b
 	return issueCreator.ID == user.ID
 }
 
+// canUserReviewStep returns true if the user can review the step.
+// Besides the reviewers of the step, the reviewers of the escalation step can review the step after it timed out,
+// and the delegates can review the step on behalf of the reviewers during the delegation windows.
+// The delegator ID is returned if the user reviews on behalf of the delegator.
+func (s *IssueService) canUserReviewStep(ctx context.Context, approval *storepb.IssuePayloadApproval, step *storepb.ApprovalStep, user *store.UserMessage, policy *store.IAMPolicyMessage) (bool, int, error) {
+	isReviewer := func(user *store.UserMessage) (bool, error) {
+		ok, err := isUserReviewer(step, user, policy)
+		if err != nil || ok {
+			return ok, err
+		}
+		if escalationStep := utils.GetEscalationStep(step); escalationStep != nil && utils.IsPendingStepEscalated(approval) {
+			return isUserReviewer(escalationStep, user, policy)
+		}
+		return false, nil
+	}
+
+	ok, err := isReviewer(user)
+	if err != nil || ok {
+		return ok, 0, err
+	}
+	now := time.Now().Unix()
+	delegations, err := s.store.ListApprovalDelegations(ctx, &store.FindApprovalDelegationMessage{DelegateUID: &user.ID, ActiveTs: &now})
+	if err != nil {
+		return false, 0, errors.Wrapf(err, "failed to list approval delegations")
+	}
+	for _, delegation := range delegations {
+		delegator, err := s.store.GetUserByID(ctx, delegation.DelegatorUID)
+		if err != nil {
+			return false, 0, errors.Wrapf(err, "failed to get user %d", delegation.DelegatorUID)
+		}
+		if delegator == nil || delegator.MemberDeleted {
+			continue
+		}
+		ok, err := isReviewer(delegator)
+		if err != nil {
+			return false, 0, err
+		}
+		if ok {
+			return true, delegator.ID, nil
+		}
+	}
+	return false, 0, nil
+}
+
 func isUserReviewer(step *storepb.ApprovalStep, user *store.UserMessage, policy *store.IAMPolicyMessage) (bool, error) {
 	if len(step.Nodes) != 1 {
 		return false, errors.Errorf("expecting one node but got %v", len(step.Nodes))
@@ -1666,6 +1716,13 @@ func convertToIssue(ctx context.Context, s *store.Store, issue *store.IssueMessa
 				return nil, errors.Wrapf(err, "failed to find user by id %v", approver.PrincipalId)
 			}
 			convertedApprover.Principal = fmt.Sprintf("users/%s", user.Email)
+			if approver.DelegatorId != 0 {
+				delegator, err := s.GetUserByID(ctx, int(approver.DelegatorId))
+				if err != nil {
+					return nil, errors.Wrapf(err, "failed to find user by id %v", approver.DelegatorId)
+				}
+				convertedApprover.Delegator = fmt.Sprintf("users/%s", delegator.Email)
+			}
 			issueV1.Approvers = append(issueV1.Approvers, convertedApprover)
 		}
 	}
@@ -1728,7 +1785,10 @@ func convertToApprovalFlow(flow *storepb.ApprovalFlow) *v1pb.ApprovalFlow {
 
 func convertToApprovalStep(step *storepb.ApprovalStep) *v1pb.ApprovalStep {
 	convertedStep := &v1pb.ApprovalStep{
-		Type: v1pb.ApprovalStep_Type(step.Type),
+		Type:             v1pb.ApprovalStep_Type(step.Type),
+		Timeout:          step.Timeout,
+		EscalationRole:   step.EscalationRole,
+		ReminderInterval: step.ReminderInterval,
 	}
 	for _, node := range step.Nodes {
 		convertedStep.Nodes = append(convertedStep.Nodes, convertToApprovalNode(node))
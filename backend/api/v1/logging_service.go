@@ -38,6 +38,12 @@ var defaultResourceActionTypeMap = map[string][]api.ActivityType{
 		api.ActivityMemberRoleUpdate,
 		api.ActivityMemberActivate,
 		api.ActivityMemberDeactivate,
+		api.ActivityMemberLoginFailed,
+		api.ActivityMemberLock,
+		api.ActivityMemberUnlock,
+		api.ActivityMemberAccessTokenCreate,
+		api.ActivityMemberAccessTokenRevoke,
+		api.ActivityMemberAccessTokenRotate,
 	},
 	"instances": {
 		api.ActivitySQLEditorQuery,
@@ -48,6 +54,7 @@ var defaultResourceActionTypeMap = map[string][]api.ActivityType{
 		api.ActivityProjectDatabaseTransfer,
 		api.ActivityProjectMemberCreate,
 		api.ActivityProjectMemberDelete,
+		api.ActivityProjectMemberExpiring,
 		api.ActivityDatabaseRecoveryPITRDone,
 	},
 	"pipelines": {
@@ -356,6 +363,24 @@ func (s *LoggingService) ExportLogs(ctx context.Context, request *v1pb.ExportLog
 	return &v1pb.ExportLogsResponse{Content: content}, nil
 }
 
+// VerifyLogs verifies the hash chain of the logs.
+func (s *LoggingService) VerifyLogs(ctx context.Context, _ *v1pb.VerifyLogsRequest) (*v1pb.VerifyLogsResponse, error) {
+	result, err := s.store.VerifyActivityLedger(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to verify logs: %v", err)
+	}
+	response := &v1pb.VerifyLogsResponse{
+		Verified:    result.Reason == "",
+		RecordCount: result.RecordCount,
+		HeadHash:    result.HeadHash,
+		Reason:      result.Reason,
+	}
+	if result.ActivityUID != 0 {
+		response.Log = fmt.Sprintf("%s%d", common.LogNamePrefix, result.ActivityUID)
+	}
+	return response, nil
+}
+
 func convertToLogEntity(ctx context.Context, db *store.Store, activity *store.ActivityMessage) (*v1pb.LogEntity, error) {
 	resource := ""
 	switch activity.Type {
@@ -363,7 +388,13 @@ func convertToLogEntity(ctx context.Context, db *store.Store, activity *store.Ac
 		api.ActivityMemberCreate,
 		api.ActivityMemberRoleUpdate,
 		api.ActivityMemberActivate,
-		api.ActivityMemberDeactivate:
+		api.ActivityMemberDeactivate,
+		api.ActivityMemberLoginFailed,
+		api.ActivityMemberLock,
+		api.ActivityMemberUnlock,
+		api.ActivityMemberAccessTokenCreate,
+		api.ActivityMemberAccessTokenRevoke,
+		api.ActivityMemberAccessTokenRotate:
 		user, err := db.GetUserByID(ctx, activity.ContainerUID)
 		if err != nil {
 			return nil, err
@@ -392,6 +423,7 @@ func convertToLogEntity(ctx context.Context, db *store.Store, activity *store.Ac
 		api.ActivityProjectDatabaseTransfer,
 		api.ActivityProjectMemberCreate,
 		api.ActivityProjectMemberDelete,
+		api.ActivityProjectMemberExpiring,
 		api.ActivityDatabaseRecoveryPITRDone:
 		project, err := db.GetProjectV2(ctx, &store.FindProjectMessage{
 			UID: &activity.ContainerUID,
@@ -449,6 +481,18 @@ func convertToActivityType(action v1pb.LogEntity_Action) (api.ActivityType, erro
 		return api.ActivityMemberActivate, nil
 	case v1pb.LogEntity_ACTION_MEMBER_DEACTIVE:
 		return api.ActivityMemberDeactivate, nil
+	case v1pb.LogEntity_ACTION_MEMBER_LOGIN_FAILED:
+		return api.ActivityMemberLoginFailed, nil
+	case v1pb.LogEntity_ACTION_MEMBER_LOCK:
+		return api.ActivityMemberLock, nil
+	case v1pb.LogEntity_ACTION_MEMBER_UNLOCK:
+		return api.ActivityMemberUnlock, nil
+	case v1pb.LogEntity_ACTION_MEMBER_ACCESS_TOKEN_CREATE:
+		return api.ActivityMemberAccessTokenCreate, nil
+	case v1pb.LogEntity_ACTION_MEMBER_ACCESS_TOKEN_REVOKE:
+		return api.ActivityMemberAccessTokenRevoke, nil
+	case v1pb.LogEntity_ACTION_MEMBER_ACCESS_TOKEN_ROTATE:
+		return api.ActivityMemberAccessTokenRotate, nil
 
 	case v1pb.LogEntity_ACTION_ISSUE_CREATE:
 		return api.ActivityIssueCreate, nil
@@ -482,6 +526,8 @@ func convertToActivityType(action v1pb.LogEntity_Action) (api.ActivityType, erro
 		return api.ActivityProjectMemberCreate, nil
 	case v1pb.LogEntity_ACTION_PROJECT_MEMBER_DELETE:
 		return api.ActivityProjectMemberDelete, nil
+	case v1pb.LogEntity_ACTION_PROJECT_MEMBER_EXPIRING:
+		return api.ActivityProjectMemberExpiring, nil
 	case v1pb.LogEntity_ACTION_PROJECT_DATABASE_RECOVERY_PITR_DONE:
 		return api.ActivityDatabaseRecoveryPITRDone, nil
 
@@ -504,6 +550,18 @@ func convertToActionType(activityType api.ActivityType) v1pb.LogEntity_Action {
 		return v1pb.LogEntity_ACTION_MEMBER_ACTIVATE
 	case api.ActivityMemberDeactivate:
 		return v1pb.LogEntity_ACTION_MEMBER_DEACTIVE
+	case api.ActivityMemberLoginFailed:
+		return v1pb.LogEntity_ACTION_MEMBER_LOGIN_FAILED
+	case api.ActivityMemberLock:
+		return v1pb.LogEntity_ACTION_MEMBER_LOCK
+	case api.ActivityMemberUnlock:
+		return v1pb.LogEntity_ACTION_MEMBER_UNLOCK
+	case api.ActivityMemberAccessTokenCreate:
+		return v1pb.LogEntity_ACTION_MEMBER_ACCESS_TOKEN_CREATE
+	case api.ActivityMemberAccessTokenRevoke:
+		return v1pb.LogEntity_ACTION_MEMBER_ACCESS_TOKEN_REVOKE
+	case api.ActivityMemberAccessTokenRotate:
+		return v1pb.LogEntity_ACTION_MEMBER_ACCESS_TOKEN_ROTATE
 
 	case api.ActivityIssueCreate:
 		return v1pb.LogEntity_ACTION_ISSUE_CREATE
@@ -537,6 +595,8 @@ func convertToActionType(activityType api.ActivityType) v1pb.LogEntity_Action {
 		return v1pb.LogEntity_ACTION_PROJECT_MEMBER_CREATE
 	case api.ActivityProjectMemberDelete:
 		return v1pb.LogEntity_ACTION_PROJECT_MEMBER_DELETE
+	case api.ActivityProjectMemberExpiring:
+		return v1pb.LogEntity_ACTION_PROJECT_MEMBER_EXPIRING
 	case api.ActivityDatabaseRecoveryPITRDone:
 		return v1pb.LogEntity_ACTION_PROJECT_DATABASE_RECOVERY_PITR_DONE
 
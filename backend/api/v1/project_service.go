@@ -17,6 +17,7 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 	"gopkg.in/yaml.v3"
 
 	"github.com/bytebase/bytebase/backend/common"
@@ -27,6 +28,8 @@ import (
 	vcsplugin "github.com/bytebase/bytebase/backend/plugin/vcs"
 	"github.com/bytebase/bytebase/backend/plugin/vcs/azure"
 	"github.com/bytebase/bytebase/backend/plugin/vcs/bitbucket"
+	"github.com/bytebase/bytebase/backend/plugin/vcs/bitbucketdc"
+	"github.com/bytebase/bytebase/backend/plugin/vcs/gitea"
 	"github.com/bytebase/bytebase/backend/plugin/vcs/github"
 	"github.com/bytebase/bytebase/backend/plugin/vcs/gitlab"
 	webhookplugin "github.com/bytebase/bytebase/backend/plugin/webhook"
@@ -502,6 +505,22 @@ func (s *ProjectService) UpdateProjectGitOpsInfo(ctx context.Context, request *v
 			patch.SheetPathTemplate = &request.ProjectGitopsInfo.SheetPathTemplate
 		case "enable_sql_review_ci":
 			patch.EnableSQLReviewCI = &request.ProjectGitopsInfo.EnableSqlReviewCi
+		case "enable_native_sql_review":
+			if request.ProjectGitopsInfo.EnableNativeSqlReview {
+				vcs, err := s.store.GetExternalVersionControlV2(ctx, repo.VCSUID)
+				if err != nil {
+					return nil, status.Errorf(codes.Internal, "failed to find vcs: %s", err.Error())
+				}
+				if vcs == nil {
+					return nil, status.Errorf(codes.NotFound, "vcs %d not found", repo.VCSUID)
+				}
+				switch vcs.Type {
+				case vcsplugin.GitHub, vcsplugin.GitLab, vcsplugin.BitbucketDataCenter:
+				default:
+					return nil, status.Errorf(codes.InvalidArgument, "native SQL review is not supported for %s", vcs.Type)
+				}
+			}
+			patch.EnableNativeSQLReview = &request.ProjectGitopsInfo.EnableNativeSqlReview
 		}
 	}
 
@@ -586,6 +605,15 @@ func (s *ProjectService) SetupProjectSQLReviewCI(ctx context.Context, request *v
 		return nil, status.Errorf(codes.NotFound, "vcs %d not found", repo.VCSUID)
 	}
 
+	// The native SQL review comments on the pull requests from the webhook events, the CI file would review them twice.
+	if repo.EnableNativeSQLReview {
+		return nil, status.Errorf(codes.FailedPrecondition, "native SQL review is enabled, disable it before setting up the SQL review CI")
+	}
+	// Bitbucket Data Center has no CI, the SQL review is triggered by the pull request webhook events.
+	if vcs.Type == vcsplugin.BitbucketDataCenter {
+		return nil, status.Errorf(codes.InvalidArgument, "SQL review CI is not supported for Bitbucket Data Center, enable the native SQL review instead")
+	}
+
 	pullRequest, err := s.setupVCSSQLReviewCI(ctx, repo, vcs)
 	if err != nil {
 		return nil, err
@@ -679,28 +707,49 @@ func (s *ProjectService) UnsetProjectGitOpsInfo(ctx context.Context, request *v1
 	return &emptypb.Empty{}, nil
 }
 
-// CreateIAMPolicyUpdateActivity creates project IAM policy change activities.
+// CreateIAMPolicyUpdateActivity creates project IAM policy change activities, and posts the role change events to the webhooks.
 func (s *ProjectService) CreateIAMPolicyUpdateActivity(ctx context.Context, remove, add *store.IAMPolicyMessage, project *store.ProjectMessage, creatorUID int) {
 	var activities []*store.ActivityMessage
+	var events []*activity.Event
 	for _, binding := range remove.Bindings {
 		for _, member := range binding.Members {
+			comment := fmt.Sprintf("Revoked %s from %s (%s).", binding.Role, member.Name, member.Email)
 			activities = append(activities, &store.ActivityMessage{
 				CreatorUID:   creatorUID,
 				ContainerUID: project.UID,
 				Type:         api.ActivityProjectMemberDelete,
 				Level:        api.ActivityInfo,
-				Comment:      fmt.Sprintf("Revoked %s from %s (%s).", binding.Role, member.Name, member.Email),
+				Comment:      comment,
+			})
+			events = append(events, &activity.Event{
+				Type:        api.ActivityProjectMemberDelete,
+				Level:       webhookplugin.WebhookInfo,
+				Title:       fmt.Sprintf("Project role revoked - %s", member.Name),
+				Description: comment,
+				CreatorUID:  creatorUID,
+				Project:     project,
+				Member:      &webhookplugin.Member{ID: member.ID, Name: member.Name, Email: member.Email, Role: string(binding.Role)},
 			})
 		}
 	}
 	for _, binding := range add.Bindings {
 		for _, member := range binding.Members {
+			comment := fmt.Sprintf("Granted %s to %s (%s).", member.Name, member.Email, binding.Role)
 			activities = append(activities, &store.ActivityMessage{
 				CreatorUID:   creatorUID,
 				ContainerUID: project.UID,
 				Type:         api.ActivityProjectMemberCreate,
 				Level:        api.ActivityInfo,
-				Comment:      fmt.Sprintf("Granted %s to %s (%s).", member.Name, member.Email, binding.Role),
+				Comment:      comment,
+			})
+			events = append(events, &activity.Event{
+				Type:        api.ActivityProjectMemberCreate,
+				Level:       webhookplugin.WebhookInfo,
+				Title:       fmt.Sprintf("Project role granted - %s", member.Name),
+				Description: comment,
+				CreatorUID:  creatorUID,
+				Project:     project,
+				Member:      &webhookplugin.Member{ID: member.ID, Name: member.Name, Email: member.Email, Role: string(binding.Role)},
 			})
 		}
 	}
@@ -710,6 +759,9 @@ func (s *ProjectService) CreateIAMPolicyUpdateActivity(ctx context.Context, remo
 			slog.Warn("Failed to create project activity", log.BBError(err))
 		}
 	}
+	for _, event := range events {
+		s.activityManager.PostEvent(ctx, event)
+	}
 }
 
 // GetDeploymentConfig returns the deployment config for a project.
@@ -879,6 +931,11 @@ func (s *ProjectService) UpdateWebhook(ctx context.Context, request *v1pb.Update
 				return nil, status.Errorf(codes.InvalidArgument, "notification types should not be empty")
 			}
 			update.ActivityList = types
+		case "signing_secret":
+			// The secret is write only, an empty value keeps the current secret.
+			if request.Webhook.SigningSecret != "" {
+				update.SigningSecret = &request.Webhook.SigningSecret
+			}
 		default:
 			return nil, status.Errorf(codes.InvalidArgument, "invalid field %q", path)
 		}
@@ -985,17 +1042,18 @@ func (s *ProjectService) TestWebhook(ctx context.Context, request *v1pb.TestWebh
 	err = webhookplugin.Post(
 		webhook.Type,
 		webhookplugin.Context{
-			URL:          webhook.URL,
-			Level:        webhookplugin.WebhookInfo,
-			ActivityType: string(api.ActivityIssueCreate),
-			Title:        fmt.Sprintf("Test webhook %q", webhook.Title),
-			Description:  "This is a test",
-			Link:         fmt.Sprintf("%s/project/%s/webhook/%s", setting.ExternalUrl, fmt.Sprintf("%s-%d", slug.Make(project.Title), project.UID), fmt.Sprintf("%s-%d", slug.Make(webhook.Title), webhook.ID)),
-			CreatorID:    api.SystemBotID,
-			CreatorName:  "Bytebase",
-			CreatorEmail: api.SystemBotEmail,
-			CreatedTs:    time.Now().Unix(),
-			Project:      &webhookplugin.Project{Name: project.Title},
+			URL:           webhook.URL,
+			SigningSecret: webhook.SigningSecret,
+			Level:         webhookplugin.WebhookInfo,
+			ActivityType:  string(api.ActivityIssueCreate),
+			Title:         fmt.Sprintf("Test webhook %q", webhook.Title),
+			Description:   "This is a test",
+			Link:          fmt.Sprintf("%s/project/%s/webhook/%s", setting.ExternalUrl, fmt.Sprintf("%s-%d", slug.Make(project.Title), project.UID), fmt.Sprintf("%s-%d", slug.Make(webhook.Title), webhook.ID)),
+			CreatorID:     api.SystemBotID,
+			CreatorName:   "Bytebase",
+			CreatorEmail:  api.SystemBotEmail,
+			CreatedTs:     time.Now().Unix(),
+			Project:       &webhookplugin.Project{Name: project.Title},
 		},
 	)
 	if err != nil {
@@ -1005,6 +1063,139 @@ func (s *ProjectService) TestWebhook(ctx context.Context, request *v1pb.TestWebh
 	return resp, nil
 }
 
+// ListWebhookDeliveries lists the deliveries of a webhook.
+func (s *ProjectService) ListWebhookDeliveries(ctx context.Context, request *v1pb.ListWebhookDeliveriesRequest) (*v1pb.ListWebhookDeliveriesResponse, error) {
+	projectID, webhookID, err := common.GetProjectIDWebhookID(request.Parent)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, err.Error())
+	}
+	webhook, err := s.getProjectWebhook(ctx, projectID, webhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	var pageToken storepb.PageToken
+	if request.PageToken != "" {
+		if err := unmarshalPageToken(request.PageToken, &pageToken); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid page token: %v", err)
+		}
+		if pageToken.Limit != request.PageSize {
+			return nil, status.Errorf(codes.InvalidArgument, "request page size does not match the page token")
+		}
+	} else {
+		pageToken.Limit = request.PageSize
+	}
+
+	limit := int(pageToken.Limit)
+	if limit <= 0 {
+		limit = 50
+	}
+	if limit > 1000 {
+		limit = 1000
+	}
+	limitPlusOne := limit + 1
+	offset := int(pageToken.Offset)
+
+	deliveries, err := s.store.ListWebhookDeliveries(ctx, &store.FindWebhookDeliveryMessage{
+		ProjectWebhookID: &webhook.ID,
+		Limit:            &limitPlusOne,
+		Offset:           &offset,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list webhook deliveries: %v", err)
+	}
+
+	nextPageToken := ""
+	if len(deliveries) == limitPlusOne {
+		deliveries = deliveries[:limit]
+		if nextPageToken, err = marshalPageToken(&storepb.PageToken{
+			Limit:  int32(limit),
+			Offset: int32(limit + offset),
+		}); err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to marshal next page token, error: %v", err)
+		}
+	}
+
+	resp := &v1pb.ListWebhookDeliveriesResponse{
+		NextPageToken: nextPageToken,
+	}
+	for _, delivery := range deliveries {
+		resp.Deliveries = append(resp.Deliveries, convertToWebhookDelivery(projectID, delivery))
+	}
+	return resp, nil
+}
+
+// RedeliverWebhook schedules a webhook delivery for an immediate attempt.
+func (s *ProjectService) RedeliverWebhook(ctx context.Context, request *v1pb.RedeliverWebhookRequest) (*v1pb.WebhookDelivery, error) {
+	projectID, webhookID, deliveryID, err := common.GetProjectIDWebhookIDDeliveryID(request.Name)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, err.Error())
+	}
+	deliveryIDInt, err := strconv.ParseInt(deliveryID, 10, 64)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid delivery id %q", deliveryID)
+	}
+	webhook, err := s.getProjectWebhook(ctx, projectID, webhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	delivery, err := s.store.GetWebhookDelivery(ctx, &store.FindWebhookDeliveryMessage{
+		ID:               &deliveryIDInt,
+		ProjectWebhookID: &webhook.ID,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get webhook delivery: %v", err)
+	}
+	if delivery == nil {
+		return nil, status.Errorf(codes.NotFound, "webhook delivery %q not found", request.Name)
+	}
+
+	// The delivery runner picks up the pending delivery in the next round. The attempts are kept,
+	// so the dead letter delivery will be moved to the dead letter again if the attempt fails.
+	pending := store.WebhookDeliveryPending
+	nextAttemptTs := time.Now().Unix()
+	delivery, err = s.store.UpdateWebhookDelivery(ctx, &store.UpdateWebhookDeliveryMessage{
+		ID:            delivery.ID,
+		Status:        &pending,
+		NextAttemptTs: &nextAttemptTs,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to update webhook delivery: %v", err)
+	}
+	return convertToWebhookDelivery(projectID, delivery), nil
+}
+
+func (s *ProjectService) getProjectWebhook(ctx context.Context, projectID, webhookID string) (*store.ProjectWebhookMessage, error) {
+	webhookIDInt, err := strconv.Atoi(webhookID)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid webhook id %q", webhookID)
+	}
+	project, err := s.store.GetProjectV2(ctx, &store.FindProjectMessage{
+		ResourceID: &projectID,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+	if project == nil {
+		return nil, status.Errorf(codes.NotFound, "project %q not found", projectID)
+	}
+	if project.Deleted {
+		return nil, status.Errorf(codes.NotFound, "project %q has been deleted", projectID)
+	}
+	webhook, err := s.store.GetProjectWebhookV2(ctx, &store.FindProjectWebhookMessage{
+		ProjectID: &project.UID,
+		ID:        &webhookIDInt,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+	if webhook == nil {
+		return nil, status.Errorf(codes.NotFound, "webhook %q not found", webhookID)
+	}
+	return webhook, nil
+}
+
 func (s *ProjectService) findProjectRepository(ctx context.Context, projectName string) (*store.RepositoryMessage, error) {
 	project, err := s.getProjectMessage(ctx, projectName)
 	if err != nil {
@@ -2343,13 +2534,41 @@ func convertToStoreProjectWebhookMessage(webhook *v1pb.Webhook) (*store.ProjectW
 		return nil, err
 	}
 	return &store.ProjectWebhookMessage{
-		Type:         tp,
-		URL:          webhook.Url,
-		Title:        webhook.Title,
-		ActivityList: activityTypes,
+		Type:          tp,
+		URL:           webhook.Url,
+		Title:         webhook.Title,
+		ActivityList:  activityTypes,
+		SigningSecret: webhook.SigningSecret,
 	}, nil
 }
 
+func convertToWebhookDelivery(projectID string, delivery *store.WebhookDeliveryMessage) *v1pb.WebhookDelivery {
+	v1Delivery := &v1pb.WebhookDelivery{
+		Name:         fmt.Sprintf("%s%s/%s%d/%s%d", common.ProjectNamePrefix, projectID, common.WebhookIDPrefix, delivery.ProjectWebhookID, common.WebhookDeliveryIDPrefix, delivery.ID),
+		ActivityType: convertNotificationTypeStrings([]string{string(delivery.ActivityType)})[0],
+		Status:       v1pb.WebhookDelivery_STATUS_UNSPECIFIED,
+		Attempts:     int32(delivery.Attempts),
+		ResponseCode: int32(delivery.ResponseCode),
+		Error:        delivery.Error,
+		CreateTime:   timestamppb.New(time.Unix(delivery.CreatedTs, 0)),
+		UpdateTime:   timestamppb.New(time.Unix(delivery.UpdatedTs, 0)),
+	}
+	switch delivery.Status {
+	case store.WebhookDeliveryPending:
+		v1Delivery.Status = v1pb.WebhookDelivery_PENDING
+		v1Delivery.NextAttemptTime = timestamppb.New(time.Unix(delivery.NextAttemptTs, 0))
+	case store.WebhookDeliveryDelivered:
+		v1Delivery.Status = v1pb.WebhookDelivery_DELIVERED
+	case store.WebhookDeliveryDeadLetter:
+		v1Delivery.Status = v1pb.WebhookDelivery_DEAD_LETTER
+	}
+	webhookCtx := &webhookplugin.Context{}
+	if err := json.Unmarshal([]byte(delivery.Payload), webhookCtx); err == nil {
+		v1Delivery.Title = webhookCtx.Title
+	}
+	return v1Delivery
+}
+
 func convertToActivityTypeStrings(types []v1pb.Activity_Type) ([]string, error) {
 	var result []string
 	for _, tp := range types {
@@ -2394,6 +2613,8 @@ func convertToActivityTypeStrings(types []v1pb.Activity_Type) ([]string, error)
 			result = append(result, string(api.ActivityProjectMemberCreate))
 		case v1pb.Activity_TYPE_PROJECT_MEMBER_DELETE:
 			result = append(result, string(api.ActivityProjectMemberDelete))
+		case v1pb.Activity_TYPE_PROJECT_MEMBER_EXPIRING:
+			result = append(result, string(api.ActivityProjectMemberExpiring))
 		case v1pb.Activity_TYPE_SQL_EDITOR_QUERY:
 			result = append(result, string(api.ActivitySQLEditorQuery))
 		case v1pb.Activity_TYPE_DATABASE_RECOVERY_PITR_DONE:
@@ -2447,6 +2668,8 @@ func convertNotificationTypeStrings(types []string) []v1pb.Activity_Type {
 			result = append(result, v1pb.Activity_TYPE_PROJECT_MEMBER_CREATE)
 		case string(api.ActivityProjectMemberDelete):
 			result = append(result, v1pb.Activity_TYPE_PROJECT_MEMBER_DELETE)
+		case string(api.ActivityProjectMemberExpiring):
+			result = append(result, v1pb.Activity_TYPE_PROJECT_MEMBER_EXPIRING)
 		case string(api.ActivitySQLEditorQuery):
 			result = append(result, v1pb.Activity_TYPE_SQL_EDITOR_QUERY)
 		case string(api.ActivityDatabaseRecoveryPITRDone):
@@ -3012,19 +3235,20 @@ func isProjectMember(userID int, policy *store.IAMPolicyMessage) bool {
 
 func convertToProjectGitOpsInfo(repository *store.RepositoryMessage) *v1pb.ProjectGitOpsInfo {
 	return &v1pb.ProjectGitOpsInfo{
-		Name:               fmt.Sprintf("%s%s/gitOpsInfo", common.ProjectNamePrefix, repository.ProjectResourceID),
-		VcsUid:             fmt.Sprintf("%d", repository.VCSUID),
-		Title:              repository.Title,
-		FullPath:           repository.FullPath,
-		WebUrl:             repository.WebURL,
-		BranchFilter:       repository.BranchFilter,
-		BaseDirectory:      repository.BaseDirectory,
-		FilePathTemplate:   repository.FilePathTemplate,
-		SchemaPathTemplate: repository.SchemaPathTemplate,
-		SheetPathTemplate:  repository.SheetPathTemplate,
-		EnableSqlReviewCi:  repository.EnableSQLReviewCI,
-		WebhookEndpointId:  repository.WebhookEndpointID,
-		ExternalId:         repository.ExternalID,
+		Name:                  fmt.Sprintf("%s%s/gitOpsInfo", common.ProjectNamePrefix, repository.ProjectResourceID),
+		VcsUid:                fmt.Sprintf("%d", repository.VCSUID),
+		Title:                 repository.Title,
+		FullPath:              repository.FullPath,
+		WebUrl:                repository.WebURL,
+		BranchFilter:          repository.BranchFilter,
+		BaseDirectory:         repository.BaseDirectory,
+		FilePathTemplate:      repository.FilePathTemplate,
+		SchemaPathTemplate:    repository.SchemaPathTemplate,
+		SheetPathTemplate:     repository.SheetPathTemplate,
+		EnableSqlReviewCi:     repository.EnableSQLReviewCI,
+		WebhookEndpointId:     repository.WebhookEndpointID,
+		EnableNativeSqlReview: repository.EnableNativeSQLReview,
+		ExternalId:            repository.ExternalID,
 	}
 }
 
@@ -3062,6 +3286,7 @@ func createVCSWebhook(ctx context.Context, vcsType vcsplugin.Type, webhookEndpoi
 			URL:                   fmt.Sprintf("%s/hook/gitlab/%s", gitopsWebhookURL, webhookEndpointID),
 			SecretToken:           secretToken,
 			PushEvents:            true,
+			MergeRequestsEvents:   true,
 			EnableSSLVerification: false, // TODO(tianzhou): This is set to false, be lax to not enable_ssl_verification
 		}
 		webhookCreatePayload, err = json.Marshal(webhookCreate)
@@ -3076,7 +3301,22 @@ func createVCSWebhook(ctx context.Context, vcsType vcsplugin.Type, webhookEndpoi
 				Secret:      secretToken,
 				InsecureSSL: 1, // TODO: Allow user to specify this value through api.RepositoryCreate
 			},
+			Events: []string{"push", "pull_request"},
+		}
+		webhookCreatePayload, err = json.Marshal(webhookPost)
+		if err != nil {
+			return "", errors.Wrap(err, "failed to marshal request body for creating webhook")
+		}
+	case vcsplugin.Gitea:
+		webhookPost := gitea.WebhookCreateOrUpdate{
+			Type: "gitea",
+			Config: gitea.WebhookConfig{
+				URL:         fmt.Sprintf("%s/hook/gitea/%s", gitopsWebhookURL, webhookEndpointID),
+				ContentType: "json",
+				Secret:      secretToken,
+			},
 			Events: []string{"push"},
+			Active: true,
 		}
 		webhookCreatePayload, err = json.Marshal(webhookPost)
 		if err != nil {
@@ -3093,6 +3333,24 @@ func createVCSWebhook(ctx context.Context, vcsType vcsplugin.Type, webhookEndpoi
 		if err != nil {
 			return "", errors.Wrap(err, "failed to marshal request body for creating webhook")
 		}
+	case vcsplugin.BitbucketDataCenter:
+		webhookPost := bitbucketdc.WebhookCreateOrUpdate{
+			Name:   "Bytebase GitOps",
+			URL:    fmt.Sprintf("%s/hook/bitbucket-dc/%s", gitopsWebhookURL, webhookEndpointID),
+			Active: true,
+			Events: []bitbucketdc.WebhookEventKey{
+				bitbucketdc.WebhookRefsChanged,
+				bitbucketdc.WebhookPullRequestOpened,
+				bitbucketdc.WebhookPullRequestFromRefUpdated,
+			},
+			Configuration: bitbucketdc.WebhookConfiguration{
+				Secret: secretToken,
+			},
+		}
+		webhookCreatePayload, err = json.Marshal(webhookPost)
+		if err != nil {
+			return "", errors.Wrap(err, "failed to marshal request body for creating webhook")
+		}
 	case vcsplugin.AzureDevOps:
 		part := strings.Split(externalRepoID, "/")
 		if len(part) != 3 {
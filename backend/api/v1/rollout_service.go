@@ -20,6 +20,7 @@ import (
 	"github.com/bytebase/bytebase/backend/component/activity"
 	"github.com/bytebase/bytebase/backend/component/dbfactory"
 	"github.com/bytebase/bytebase/backend/component/ghost"
+	"github.com/bytebase/bytebase/backend/component/pgosc"
 	"github.com/bytebase/bytebase/backend/component/state"
 	enterprise "github.com/bytebase/bytebase/backend/enterprise/api"
 	api "github.com/bytebase/bytebase/backend/legacyapi"
@@ -837,7 +838,15 @@ func (s *RolloutService) UpdatePlan(ctx context.Context, request *v1pb.UpdatePla
 					return status.Errorf(codes.Internal, "failed to unmarshal task payload: %v", err)
 				}
 				newFlags := spec.GetChangeDatabaseConfig().GetGhostFlags()
-				if _, err := ghost.GetUserFlags(newFlags); err != nil {
+				instance, err := s.store.GetInstanceV2(ctx, &store.FindInstanceMessage{UID: &task.InstanceID})
+				if err != nil {
+					return status.Errorf(codes.Internal, "failed to get instance %d: %v", task.InstanceID, err)
+				}
+				if instance != nil && instance.Engine == storepb.Engine_POSTGRES {
+					if _, err := pgosc.GetUserFlags(newFlags); err != nil {
+						return status.Errorf(codes.InvalidArgument, "invalid online schema change flags %q, error %v", newFlags, err)
+					}
+				} else if _, err := ghost.GetUserFlags(newFlags); err != nil {
 					return status.Errorf(codes.InvalidArgument, "invalid ghost flags %q, error %v", newFlags, err)
 				}
 				oldFlags := payload.Flags
@@ -372,6 +372,8 @@ func convertToPlanCheckRunType(t store.PlanCheckRunType) v1pb.PlanCheckRun_Type
 		return v1pb.PlanCheckRun_DATABASE_GHOST_SYNC
 	case store.PlanCheckDatabasePITRMySQL:
 		return v1pb.PlanCheckRun_DATABASE_PITR_MYSQL
+	case store.PlanCheckDatabaseShadowDryRun:
+		return v1pb.PlanCheckRun_DATABASE_SHADOW_DRY_RUN
 	}
 	return v1pb.PlanCheckRun_TYPE_UNSPECIFIED
 }
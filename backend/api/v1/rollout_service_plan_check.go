@@ -354,6 +354,28 @@ func getPlanCheckRunsFromChangeDatabaseConfigForDatabase(ctx context.Context, s
 			DatabaseGroupUid:   databaseGroupUID,
 		},
 	})
+	if databaseGroupUID == nil && convertToChangeDatabaseType(config.Type) == storepb.PlanCheckRunConfig_DDL {
+		shadowDatabaseSetting, err := s.GetShadowDatabaseSetting(ctx)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get shadow database setting")
+		}
+		if shadowDatabaseSetting.Enabled {
+			planCheckRuns = append(planCheckRuns, &store.PlanCheckRunMessage{
+				CreatorUID: api.SystemBotID,
+				UpdaterUID: api.SystemBotID,
+				PlanUID:    plan.UID,
+				Status:     store.PlanCheckRunStatusRunning,
+				Type:       store.PlanCheckDatabaseShadowDryRun,
+				Config: &storepb.PlanCheckRunConfig{
+					SheetUid:           int32(sheetUID),
+					ChangeDatabaseType: convertToChangeDatabaseType(config.Type),
+					InstanceUid:        int32(instance.UID),
+					DatabaseName:       database.DatabaseName,
+					DatabaseGroupUid:   databaseGroupUID,
+				},
+			})
+		}
+	}
 	if databaseGroupUID == nil && config.Type == storepb.PlanConfig_ChangeDatabaseConfig_MIGRATE_GHOST {
 		planCheckRuns = append(planCheckRuns, &store.PlanCheckRunMessage{
 			CreatorUID: api.SystemBotID,
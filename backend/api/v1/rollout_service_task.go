@@ -14,6 +14,7 @@ import (
 	"github.com/bytebase/bytebase/backend/common/log"
 	"github.com/bytebase/bytebase/backend/component/dbfactory"
 	"github.com/bytebase/bytebase/backend/component/ghost"
+	"github.com/bytebase/bytebase/backend/component/pgosc"
 	enterprise "github.com/bytebase/bytebase/backend/enterprise/api"
 	api "github.com/bytebase/bytebase/backend/legacyapi"
 	"github.com/bytebase/bytebase/backend/store"
@@ -389,7 +390,13 @@ func getTaskCreatesFromChangeDatabaseConfigDatabaseTarget(ctx context.Context, s
 		if err != nil {
 			return nil, nil, errors.Wrapf(err, "failed to get sheet id from sheet %q", c.Sheet)
 		}
-		if _, err := ghost.GetUserFlags(c.GhostFlags); err != nil {
+		migrationTool := "gh-ost"
+		if instance.Engine == storepb.Engine_POSTGRES {
+			migrationTool = "online"
+			if _, err := pgosc.GetUserFlags(c.GhostFlags); err != nil {
+				return nil, nil, errors.Wrapf(err, "invalid online schema change flags %q, error: %v", c.GhostFlags, err)
+			}
+		} else if _, err := ghost.GetUserFlags(c.GhostFlags); err != nil {
 			return nil, nil, errors.Wrapf(err, "invalid ghost flags %q, error: %v", c.GhostFlags, err)
 		}
 		var taskCreateList []*store.TaskMessage
@@ -405,7 +412,7 @@ func getTaskCreatesFromChangeDatabaseConfigDatabaseTarget(ctx context.Context, s
 			return nil, nil, errors.Wrapf(err, "failed to marshal database schema update gh-ost sync payload")
 		}
 		taskCreateList = append(taskCreateList, &store.TaskMessage{
-			Name:              fmt.Sprintf("Update schema gh-ost sync for database %q", database.DatabaseName),
+			Name:              fmt.Sprintf("Update schema %s sync for database %q", migrationTool, database.DatabaseName),
 			InstanceID:        instance.UID,
 			DatabaseID:        &database.UID,
 			Status:            api.TaskPendingApproval,
@@ -423,7 +430,7 @@ func getTaskCreatesFromChangeDatabaseConfigDatabaseTarget(ctx context.Context, s
 			return nil, nil, errors.Wrapf(err, "failed to marshal database schema update ghost cutover payload")
 		}
 		taskCreateList = append(taskCreateList, &store.TaskMessage{
-			Name:              fmt.Sprintf("Update schema gh-ost cutover for database %q", database.DatabaseName),
+			Name:              fmt.Sprintf("Update schema %s cutover for database %q", migrationTool, database.DatabaseName),
 			InstanceID:        instance.UID,
 			DatabaseID:        &database.UID,
 			Status:            api.TaskPendingApproval,
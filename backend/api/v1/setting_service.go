@@ -3,14 +3,18 @@ package v1
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
 	"embed"
 	"encoding/json"
 	"fmt"
+	"net/url"
+	"path/filepath"
 	"regexp"
 	"strings"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
 	"github.com/pkg/errors"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -23,7 +27,10 @@ import (
 	"github.com/bytebase/bytebase/backend/component/state"
 	enterprise "github.com/bytebase/bytebase/backend/enterprise/api"
 	api "github.com/bytebase/bytebase/backend/legacyapi"
+	bbidp "github.com/bytebase/bytebase/backend/plugin/idp"
+	"github.com/bytebase/bytebase/backend/plugin/idp/ldap"
 	"github.com/bytebase/bytebase/backend/plugin/mail"
+	"github.com/bytebase/bytebase/backend/plugin/webauthn"
 	"github.com/bytebase/bytebase/backend/store"
 	storepb "github.com/bytebase/bytebase/proto/generated-go/store"
 	v1pb "github.com/bytebase/bytebase/proto/generated-go/v1"
@@ -69,6 +76,15 @@ var whitelistSettings = []api.SettingName{
 	api.SettingDataClassification,
 	api.SettingSemanticTypes,
 	api.SettingMaskingAlgorithm,
+	api.SettingShadowDatabase,
+	api.SettingDataRollback,
+	api.SettingSCIM,
+	api.SettingLDAPGroupSync,
+	api.SettingIdentityProviderClaimMapping,
+	api.SettingPasswordPolicy,
+	api.SettingWebAuthn,
+	api.SettingAuditLogExport,
+	api.SettingWorkspaceWebhook,
 }
 
 var preservedMaskingAlgorithmIDMatcher = regexp.MustCompile("^[0]{8}-[0]{4}-[0]{4}-[0]{4}-[0]{9}[0-9a-fA-F]{3}$")
@@ -308,21 +324,68 @@ func (s *SettingService) SetSetting(ctx context.Context, request *v1pb.SetSettin
 			AppID:     settingValue.AppId,
 			AppSecret: settingValue.AppSecret,
 			ExternalApproval: api.ExternalApproval{
-				Enabled:              settingValue.ExternalApproval.Enabled,
-				ApprovalDefinitionID: settingValue.ExternalApproval.ApprovalDefinitionId,
+				Enabled:              settingValue.GetExternalApproval().GetEnabled(),
+				ApprovalDefinitionID: settingValue.GetExternalApproval().GetApprovalDefinitionId(),
+			},
+			Slack: api.SlackApproval{
+				Enabled:       settingValue.GetSlack().GetEnabled(),
+				SigningSecret: settingValue.GetSlack().GetSigningSecret(),
+				BotToken:      settingValue.GetSlack().GetBotToken(),
+			},
+			Teams: api.TeamsApproval{
+				Enabled: settingValue.GetTeams().GetEnabled(),
+				Token:   settingValue.GetTeams().GetToken(),
 			},
 		}
-		if payload.IMType != api.IMTypeFeishu {
-			return nil, status.Errorf(codes.InvalidArgument, fmt.Sprintf("unknown IM Type %s", payload.IMType))
+		// The Slack and Teams secrets are input only, keep the old ones if they're not changed.
+		if payload.Slack.SigningSecret == "" || payload.Slack.BotToken == "" || payload.Teams.Token == "" {
+			oldSetting, err := s.store.GetSettingV2(ctx, &store.FindSettingMessage{Name: &apiSettingName})
+			if err != nil {
+				return nil, status.Errorf(codes.Internal, "failed to get setting %s: %v", apiSettingName, err)
+			}
+			if oldSetting != nil && oldSetting.Value != "" {
+				oldValue := new(api.SettingAppIMValue)
+				if err := json.Unmarshal([]byte(oldSetting.Value), oldValue); err != nil {
+					return nil, status.Errorf(codes.Internal, "failed to unmarshal setting value for %s with error: %v", apiSettingName, err)
+				}
+				if payload.Slack.SigningSecret == "" {
+					payload.Slack.SigningSecret = oldValue.Slack.SigningSecret
+				}
+				if payload.Slack.BotToken == "" {
+					payload.Slack.BotToken = oldValue.Slack.BotToken
+				}
+				if payload.Teams.Token == "" {
+					payload.Teams.Token = oldValue.Teams.Token
+				}
+			}
 		}
 		if payload.ExternalApproval.Enabled {
 			if err := s.licenseService.IsFeatureEnabled(api.FeatureIMApproval); err != nil {
 				return nil, status.Errorf(codes.PermissionDenied, err.Error())
 			}
+			if payload.IMType != api.IMTypeFeishu {
+				return nil, status.Errorf(codes.InvalidArgument, fmt.Sprintf("unknown IM Type %s", payload.IMType))
+			}
 			if payload.AppID == "" || payload.AppSecret == "" {
 				return nil, status.Errorf(codes.InvalidArgument, "application ID and secret cannot be empty")
 			}
 		}
+		if payload.Slack.Enabled {
+			if err := s.licenseService.IsFeatureEnabled(api.FeatureIMApproval); err != nil {
+				return nil, status.Errorf(codes.PermissionDenied, err.Error())
+			}
+			if payload.Slack.SigningSecret == "" || payload.Slack.BotToken == "" {
+				return nil, status.Errorf(codes.InvalidArgument, "Slack signing secret and bot token cannot be empty")
+			}
+		}
+		if payload.Teams.Enabled {
+			if err := s.licenseService.IsFeatureEnabled(api.FeatureIMApproval); err != nil {
+				return nil, status.Errorf(codes.PermissionDenied, err.Error())
+			}
+			if payload.Teams.Token == "" {
+				return nil, status.Errorf(codes.InvalidArgument, "Teams token cannot be empty")
+			}
+		}
 
 		s, err := json.Marshal(payload)
 		if err != nil {
@@ -343,12 +406,21 @@ func (s *SettingService) SetSetting(ctx context.Context, request *v1pb.SetSettin
 
 		newNode := make(map[string]*storepb.ExternalApprovalSetting_Node)
 		for _, node := range storeValue.Nodes {
+			if node.Type == storepb.ExternalApprovalSetting_Node_HTTP && node.Endpoint == "" {
+				return nil, status.Errorf(codes.InvalidArgument, "endpoint is required for the HTTP external approval node %q", node.Title)
+			}
 			newNode[node.Id] = node
 		}
 		removed := make(map[string]bool)
 		for _, node := range oldSetting.Nodes {
-			if _, ok := newNode[node.Id]; !ok {
+			v, ok := newNode[node.Id]
+			if !ok {
 				removed[node.Id] = true
+				continue
+			}
+			// The signing secret is input only, keep the old one if it's not changed.
+			if v.SigningSecret == "" {
+				v.SigningSecret = node.SigningSecret
 			}
 		}
 		if len(removed) > 0 {
@@ -453,6 +525,154 @@ func (s *SettingService) SetSetting(ctx context.Context, request *v1pb.SetSettin
 			return nil, status.Errorf(codes.Internal, "failed to marshal setting for %s with error: %v", apiSettingName, err)
 		}
 		storeSettingValue = string(bytes)
+	case api.SettingShadowDatabase:
+		payload := new(api.SettingShadowDatabaseValue)
+		if err := json.Unmarshal([]byte(request.Setting.Value.GetStringValue()), payload); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "failed to unmarshal setting value for %s with error: %v", apiSettingName, err)
+		}
+		for _, instanceID := range payload.SandboxInstanceIDs {
+			instance, err := s.store.GetInstanceV2(ctx, &store.FindInstanceMessage{ResourceID: &instanceID})
+			if err != nil {
+				return nil, status.Errorf(codes.Internal, "failed to get instance %q with error: %v", instanceID, err)
+			}
+			if instance == nil {
+				return nil, status.Errorf(codes.InvalidArgument, "sandbox instance %q not found", instanceID)
+			}
+		}
+		bytes, err := json.Marshal(payload)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to marshal setting for %s with error: %v", apiSettingName, err)
+		}
+		storeSettingValue = string(bytes)
+	case api.SettingDataRollback:
+		payload := new(api.SettingDataRollbackValue)
+		if err := json.Unmarshal([]byte(request.Setting.Value.GetStringValue()), payload); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "failed to unmarshal setting value for %s with error: %v", apiSettingName, err)
+		}
+		if payload.PreImageRowLimit < 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "pre-image row limit must not be negative")
+		}
+		bytes, err := json.Marshal(payload)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to marshal setting for %s with error: %v", apiSettingName, err)
+		}
+		storeSettingValue = string(bytes)
+	case api.SettingSCIM:
+		payload := new(api.SettingSCIMValue)
+		if err := json.Unmarshal([]byte(request.Setting.Value.GetStringValue()), payload); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "failed to unmarshal setting value for %s with error: %v", apiSettingName, err)
+		}
+		// Only the hash of the token is stored.
+		if payload.Token != "" {
+			payload.TokenHash = fmt.Sprintf("%x", sha256.Sum256([]byte(payload.Token)))
+			payload.Token = ""
+		}
+		if err := s.validateSCIMGroupMappings(ctx, payload.GroupMappings); err != nil {
+			return nil, err
+		}
+		bytes, err := json.Marshal(payload)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to marshal setting for %s with error: %v", apiSettingName, err)
+		}
+		storeSettingValue = string(bytes)
+	case api.SettingLDAPGroupSync:
+		payload := new(api.SettingLDAPGroupSyncValue)
+		if err := json.Unmarshal([]byte(request.Setting.Value.GetStringValue()), payload); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "failed to unmarshal setting value for %s with error: %v", apiSettingName, err)
+		}
+		if err := s.validateLDAPGroupSyncConfigs(ctx, payload.IdentityProviders); err != nil {
+			return nil, err
+		}
+		bytes, err := json.Marshal(payload)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to marshal setting for %s with error: %v", apiSettingName, err)
+		}
+		storeSettingValue = string(bytes)
+	case api.SettingIdentityProviderClaimMapping:
+		payload := new(api.SettingIdentityProviderClaimMappingValue)
+		if err := json.Unmarshal([]byte(request.Setting.Value.GetStringValue()), payload); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "failed to unmarshal setting value for %s with error: %v", apiSettingName, err)
+		}
+		if err := s.validateIdentityProviderClaimMappingConfigs(ctx, payload.IdentityProviders); err != nil {
+			return nil, err
+		}
+		bytes, err := json.Marshal(payload)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to marshal setting for %s with error: %v", apiSettingName, err)
+		}
+		storeSettingValue = string(bytes)
+	case api.SettingPasswordPolicy:
+		payload := new(api.SettingPasswordPolicyValue)
+		if err := json.Unmarshal([]byte(request.Setting.Value.GetStringValue()), payload); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "failed to unmarshal setting value for %s with error: %v", apiSettingName, err)
+		}
+		if err := validatePasswordPolicy(payload); err != nil {
+			return nil, err
+		}
+		bytes, err := json.Marshal(payload)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to marshal setting for %s with error: %v", apiSettingName, err)
+		}
+		storeSettingValue = string(bytes)
+	case api.SettingWebAuthn:
+		if err := s.licenseService.IsFeatureEnabled(api.Feature2FA); err != nil {
+			return nil, status.Errorf(codes.PermissionDenied, err.Error())
+		}
+		payload := new(api.SettingWebAuthnValue)
+		if err := json.Unmarshal([]byte(request.Setting.Value.GetStringValue()), payload); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "failed to unmarshal setting value for %s with error: %v", apiSettingName, err)
+		}
+		if err := validateWebAuthnSetting(payload); err != nil {
+			return nil, err
+		}
+		bytes, err := json.Marshal(payload)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to marshal setting for %s with error: %v", apiSettingName, err)
+		}
+		storeSettingValue = string(bytes)
+	case api.SettingAuditLogExport:
+		if err := s.licenseService.IsFeatureEnabled(api.FeatureAuditLog); err != nil {
+			return nil, status.Errorf(codes.PermissionDenied, err.Error())
+		}
+		payload := new(api.SettingAuditLogExportValue)
+		if err := json.Unmarshal([]byte(request.Setting.Value.GetStringValue()), payload); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "failed to unmarshal setting value for %s with error: %v", apiSettingName, err)
+		}
+		if err := validateAuditLogExportSetting(payload); err != nil {
+			return nil, err
+		}
+		bytes, err := json.Marshal(payload)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to marshal setting for %s with error: %v", apiSettingName, err)
+		}
+		storeSettingValue = string(bytes)
+	case api.SettingWorkspaceWebhook:
+		payload := new(api.SettingWorkspaceWebhookValue)
+		if err := json.Unmarshal([]byte(request.Setting.Value.GetStringValue()), payload); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "failed to unmarshal setting value for %s with error: %v", apiSettingName, err)
+		}
+		if err := validateWorkspaceWebhookSetting(payload); err != nil {
+			return nil, err
+		}
+		oldValue, err := s.store.GetWorkspaceWebhookSetting(ctx)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to get workspace webhook setting: %v", err)
+		}
+		// The signing secrets are input only, keep the old ones if they're not changed.
+		oldSecrets := make(map[string]string)
+		for _, hook := range oldValue.Webhooks {
+			oldSecrets[hook.ID] = hook.SigningSecret
+		}
+		for _, hook := range payload.Webhooks {
+			if hook.SigningSecret == "" {
+				hook.SigningSecret = oldSecrets[hook.ID]
+			}
+		}
+		bytes, err := json.Marshal(payload)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to marshal setting for %s with error: %v", apiSettingName, err)
+		}
+		storeSettingValue = string(bytes)
 	default:
 		storeSettingValue = request.Setting.Value.GetStringValue()
 	}
@@ -542,7 +762,7 @@ func (s *SettingService) convertToSettingMessage(ctx context.Context, setting *s
 		if err := json.Unmarshal([]byte(stringValue), apiValue); err != nil {
 			return nil, status.Errorf(codes.Internal, "failed to unmarshal setting value for %s with error: %v", setting.Name, err)
 		}
-		return &v1pb.Setting{
+		return stripSensitiveData(&v1pb.Setting{
 			Name: settingName,
 			Value: &v1pb.Value{
 				Value: &v1pb.Value_AppImSettingValue{
@@ -554,10 +774,19 @@ func (s *SettingService) convertToSettingMessage(ctx context.Context, setting *s
 							Enabled:              apiValue.ExternalApproval.Enabled,
 							ApprovalDefinitionId: apiValue.ExternalApproval.ApprovalDefinitionID,
 						},
+						Slack: &v1pb.AppIMSetting_Slack{
+							Enabled:       apiValue.Slack.Enabled,
+							SigningSecret: apiValue.Slack.SigningSecret,
+							BotToken:      apiValue.Slack.BotToken,
+						},
+						Teams: &v1pb.AppIMSetting_Teams{
+							Enabled: apiValue.Teams.Enabled,
+							Token:   apiValue.Teams.Token,
+						},
 					},
 				},
 			},
-		}, nil
+		})
 	case api.SettingPluginAgent:
 		v1Value := new(v1pb.AgentPluginSetting)
 		if err := protojson.Unmarshal([]byte(setting.Value), v1Value); err != nil {
@@ -679,6 +908,29 @@ func (s *SettingService) convertToSettingMessage(ctx context.Context, setting *s
 				},
 			},
 		}, nil
+	case api.SettingWorkspaceWebhook:
+		value := new(api.SettingWorkspaceWebhookValue)
+		if setting.Value != "" {
+			if err := json.Unmarshal([]byte(setting.Value), value); err != nil {
+				return nil, status.Errorf(codes.Internal, "failed to unmarshal setting value for %s with error: %v", setting.Name, err)
+			}
+		}
+		// The signing secrets can be used to forge the webhook deliveries.
+		for _, hook := range value.Webhooks {
+			hook.SigningSecret = ""
+		}
+		bytes, err := json.Marshal(value)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to marshal setting value for %s with error: %v", setting.Name, err)
+		}
+		return &v1pb.Setting{
+			Name: settingName,
+			Value: &v1pb.Value{
+				Value: &v1pb.Value_StringValue{
+					StringValue: string(bytes),
+				},
+			},
+		}, nil
 
 	default:
 		return &v1pb.Setting{
@@ -774,6 +1026,8 @@ func convertToIMType(imType v1pb.AppIMSetting_IMType) (api.IMType, error) {
 	switch imType {
 	case v1pb.AppIMSetting_FEISHU:
 		resp = api.IMTypeFeishu
+	case v1pb.AppIMSetting_IM_TYPE_UNSPECIFIED:
+		// The IM type is only required by the external approval, the Slack and Teams approvals are configured separately.
 	default:
 		return resp, status.Errorf(codes.InvalidArgument, "unknown im type %v", imType.String())
 	}
@@ -812,6 +1066,17 @@ func validateApprovalTemplate(template *v1pb.ApprovalTemplate) error {
 		if len(step.Nodes) != 1 {
 			return errors.Errorf("expect 1 node in approval step, got: %v", len(step.Nodes))
 		}
+		if step.Timeout != nil && step.Timeout.AsDuration() < 0 {
+			return errors.Errorf("invalid approval step timeout: %v", step.Timeout.AsDuration())
+		}
+		if step.ReminderInterval != nil && step.ReminderInterval.AsDuration() < 0 {
+			return errors.Errorf("invalid approval step reminder interval: %v", step.ReminderInterval.AsDuration())
+		}
+		if step.EscalationRole != "" {
+			if _, err := common.GetRoleID(step.EscalationRole); err != nil {
+				return errors.Wrapf(err, "invalid approval step escalation role %q", step.EscalationRole)
+			}
+		}
 	}
 	return nil
 }
@@ -985,6 +1250,7 @@ func convertToExternalApprovalSettingNode(o *storepb.ExternalApprovalSetting_Nod
 		Id:       o.Id,
 		Title:    o.Title,
 		Endpoint: o.Endpoint,
+		Type:     v1pb.ExternalApprovalSetting_Node_Type(o.Type),
 	}
 }
 
@@ -1004,9 +1270,11 @@ func convertExternalApprovalSettingNodes(nodes []*v1pb.ExternalApprovalSetting_N
 
 func convertExternalApprovalSettingNode(o *v1pb.ExternalApprovalSetting_Node) *storepb.ExternalApprovalSetting_Node {
 	return &storepb.ExternalApprovalSetting_Node{
-		Id:       o.Id,
-		Title:    o.Title,
-		Endpoint: o.Endpoint,
+		Id:            o.Id,
+		Title:         o.Title,
+		Endpoint:      o.Endpoint,
+		Type:          storepb.ExternalApprovalSetting_Node_Type(o.Type),
+		SigningSecret: o.SigningSecret,
 	}
 }
 
@@ -1028,6 +1296,19 @@ func stripSensitiveData(setting *v1pb.Setting) (*v1pb.Setting, error) {
 		mailDeliveryValue.SmtpMailDeliverySettingValue.Cert = nil
 		mailDeliveryValue.SmtpMailDeliverySettingValue.Key = nil
 		setting.Value.Value = mailDeliveryValue
+	case api.SettingAppIM:
+		appIMValue, ok := setting.Value.Value.(*v1pb.Value_AppImSettingValue)
+		if !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid setting value type: %T", setting.Value.Value)
+		}
+		// The secrets can be used to forge the Slack and Teams approval actions.
+		if slack := appIMValue.AppImSettingValue.Slack; slack != nil {
+			slack.SigningSecret = ""
+			slack.BotToken = ""
+		}
+		if teams := appIMValue.AppImSettingValue.Teams; teams != nil {
+			teams.Token = ""
+		}
 	default:
 	}
 	return setting, nil
@@ -1132,3 +1413,254 @@ func validateMaskingAlgorithm(algorithm *v1pb.MaskingAlgorithmSetting_Algorithm)
 
 	return nil
 }
+
+func (s *SettingService) validateSCIMGroupMappings(ctx context.Context, mappings []*api.SCIMGroupMapping) error {
+	roles, err := s.store.ListRoles(ctx)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to list roles with error: %v", err)
+	}
+	roleNames := make(map[string]bool)
+	for _, role := range roles {
+		roleNames[convertToRoleName(role.ResourceID)] = true
+	}
+	for _, mapping := range mappings {
+		if mapping.Group == "" {
+			return status.Errorf(codes.InvalidArgument, "SCIM group mapping group cannot be empty")
+		}
+		if !roleNames[mapping.Role] {
+			return status.Errorf(codes.InvalidArgument, "role %q not found for SCIM group %q", mapping.Role, mapping.Group)
+		}
+		project, err := s.store.GetProjectV2(ctx, &store.FindProjectMessage{ResourceID: &mapping.Project})
+		if err != nil {
+			return status.Errorf(codes.Internal, "failed to get project %q with error: %v", mapping.Project, err)
+		}
+		if project == nil || project.Deleted {
+			return status.Errorf(codes.InvalidArgument, "project %q not found for SCIM group %q", mapping.Project, mapping.Group)
+		}
+	}
+	return nil
+}
+
+func (s *SettingService) validateLDAPGroupSyncConfigs(ctx context.Context, configs []*api.LDAPGroupSyncConfig) error {
+	roleNames, err := s.getRoleNames(ctx)
+	if err != nil {
+		return err
+	}
+	identityProviders := make(map[string]bool)
+	for _, config := range configs {
+		if identityProviders[config.IdentityProvider] {
+			return status.Errorf(codes.InvalidArgument, "duplicate LDAP group sync config for identity provider %q", config.IdentityProvider)
+		}
+		identityProviders[config.IdentityProvider] = true
+		idp, err := s.store.GetIdentityProvider(ctx, &store.FindIdentityProviderMessage{ResourceID: &config.IdentityProvider})
+		if err != nil {
+			return status.Errorf(codes.Internal, "failed to get identity provider %q with error: %v", config.IdentityProvider, err)
+		}
+		if idp == nil || idp.Deleted {
+			return status.Errorf(codes.InvalidArgument, "identity provider %q not found", config.IdentityProvider)
+		}
+		if idp.Type != storepb.IdentityProviderType_LDAP {
+			return status.Errorf(codes.InvalidArgument, "identity provider %q is not an LDAP identity provider", config.IdentityProvider)
+		}
+		switch config.DefaultWorkspaceRole {
+		case "", api.Owner, api.DBA, api.Developer:
+		default:
+			return status.Errorf(codes.InvalidArgument, "invalid default workspace role %q", config.DefaultWorkspaceRole)
+		}
+		for _, mapping := range config.GroupMappings {
+			if err := ldap.ValidateGroupMapping(mapping.GroupDN, mapping.Filter); err != nil {
+				return status.Errorf(codes.InvalidArgument, "invalid LDAP group mapping for identity provider %q: %v", config.IdentityProvider, err)
+			}
+			if err := s.validateRoleMapping(ctx, roleNames, mapping.WorkspaceRole, mapping.Project, mapping.Role); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *SettingService) validateIdentityProviderClaimMappingConfigs(ctx context.Context, configs []*api.IdentityProviderClaimMappingConfig) error {
+	roleNames, err := s.getRoleNames(ctx)
+	if err != nil {
+		return err
+	}
+	identityProviders := make(map[string]bool)
+	for _, config := range configs {
+		if identityProviders[config.IdentityProvider] {
+			return status.Errorf(codes.InvalidArgument, "duplicate claim mapping config for identity provider %q", config.IdentityProvider)
+		}
+		identityProviders[config.IdentityProvider] = true
+		idp, err := s.store.GetIdentityProvider(ctx, &store.FindIdentityProviderMessage{ResourceID: &config.IdentityProvider})
+		if err != nil {
+			return status.Errorf(codes.Internal, "failed to get identity provider %q with error: %v", config.IdentityProvider, err)
+		}
+		if idp == nil || idp.Deleted {
+			return status.Errorf(codes.InvalidArgument, "identity provider %q not found", config.IdentityProvider)
+		}
+		if idp.Type != storepb.IdentityProviderType_OAUTH2 && idp.Type != storepb.IdentityProviderType_OIDC {
+			return status.Errorf(codes.InvalidArgument, "identity provider %q is not an OAuth2 or OIDC identity provider", config.IdentityProvider)
+		}
+		switch config.DefaultWorkspaceRole {
+		case "", api.Owner, api.DBA, api.Developer:
+		default:
+			return status.Errorf(codes.InvalidArgument, "invalid default workspace role %q", config.DefaultWorkspaceRole)
+		}
+		for _, mapping := range config.ClaimMappings {
+			if err := bbidp.ValidateClaimPath(mapping.Claim); err != nil {
+				return status.Errorf(codes.InvalidArgument, "invalid claim mapping for identity provider %q: %v", config.IdentityProvider, err)
+			}
+			if mapping.Value == "" {
+				return status.Errorf(codes.InvalidArgument, "claim mapping value cannot be empty for claim %q", mapping.Claim)
+			}
+			if err := s.validateRoleMapping(ctx, roleNames, mapping.WorkspaceRole, mapping.Project, mapping.Role); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *SettingService) getRoleNames(ctx context.Context) (map[string]bool, error) {
+	roles, err := s.store.ListRoles(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list roles with error: %v", err)
+	}
+	roleNames := make(map[string]bool)
+	for _, role := range roles {
+		roleNames[convertToRoleName(role.ResourceID)] = true
+	}
+	return roleNames, nil
+}
+
+// validateRoleMapping validates the workspace role and the project role granted by the identity provider mappings.
+func (s *SettingService) validateRoleMapping(ctx context.Context, roleNames map[string]bool, workspaceRole api.Role, projectID, role string) error {
+	switch workspaceRole {
+	case "", api.Owner, api.DBA, api.Developer:
+	default:
+		return status.Errorf(codes.InvalidArgument, "invalid workspace role %q", workspaceRole)
+	}
+	if projectID == "" && role == "" {
+		if workspaceRole == "" {
+			return status.Errorf(codes.InvalidArgument, "the mapping should grant either the workspace role or the project role")
+		}
+		return nil
+	}
+	if !roleNames[role] {
+		return status.Errorf(codes.InvalidArgument, "role %q not found", role)
+	}
+	project, err := s.store.GetProjectV2(ctx, &store.FindProjectMessage{ResourceID: &projectID})
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to get project %q with error: %v", projectID, err)
+	}
+	if project == nil || project.Deleted {
+		return status.Errorf(codes.InvalidArgument, "project %q not found", projectID)
+	}
+	return nil
+}
+
+func validatePasswordPolicy(policy *api.SettingPasswordPolicyValue) error {
+	if policy.MinLength < 0 || policy.RotationDays < 0 || policy.MaxAccountFailedAttempts < 0 || policy.MaxIPFailedAttempts < 0 || policy.LockoutMinutes < 0 {
+		return status.Errorf(codes.InvalidArgument, "password policy values cannot be negative")
+	}
+	if policy.HistoryCount < 0 || policy.HistoryCount > api.MaxPasswordHistoryCount {
+		return status.Errorf(codes.InvalidArgument, "password history count should be between 0 and %d", api.MaxPasswordHistoryCount)
+	}
+	return nil
+}
+
+func validateWebAuthnSetting(setting *api.SettingWebAuthnValue) error {
+	for _, origin := range setting.Origins {
+		u, err := url.Parse(origin)
+		if err != nil || u.Host == "" || u.Path != "" || (u.Scheme != "https" && u.Hostname() != "localhost") {
+			return status.Errorf(codes.InvalidArgument, "invalid origin %q, should be like https://bytebase.example.com", origin)
+		}
+		// The RP ID should be the host of the origin or a registrable domain suffix of it.
+		if setting.RPID != "" && u.Hostname() != setting.RPID && !strings.HasSuffix(u.Hostname(), "."+setting.RPID) {
+			return status.Errorf(codes.InvalidArgument, "RP ID %q is not a domain suffix of the origin %q", setting.RPID, origin)
+		}
+	}
+	for _, role := range setting.RequireHardwareKeyRoles {
+		switch role {
+		case api.Owner, api.DBA, api.Developer:
+		default:
+			return status.Errorf(codes.InvalidArgument, "invalid workspace role %q", role)
+		}
+	}
+	// The hardware-bound credentials cannot be told apart from the synced passkeys without the attestation.
+	if len(setting.RequireHardwareKeyRoles) > 0 && len(setting.HardwareKeyAttestationRoots) == 0 {
+		return status.Errorf(codes.InvalidArgument, "hardware key attestation roots are required to require hardware keys")
+	}
+	if _, err := webauthn.ParseAttestationRoots(setting.HardwareKeyAttestationRoots); err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid hardware key attestation roots, error: %v", err)
+	}
+	for _, aaguid := range setting.HardwareKeyAAGUIDs {
+		if _, err := uuid.Parse(aaguid); err != nil {
+			return status.Errorf(codes.InvalidArgument, "invalid hardware key AAGUID %q", aaguid)
+		}
+	}
+	if setting.HardwareKeyGracePeriodEndTs < 0 {
+		return status.Errorf(codes.InvalidArgument, "hardware key grace period end time cannot be negative")
+	}
+	return nil
+}
+
+func validateAuditLogExportSetting(setting *api.SettingAuditLogExportValue) error {
+	ids := make(map[string]bool)
+	for _, destination := range setting.Destinations {
+		if destination.ID == "" {
+			return status.Errorf(codes.InvalidArgument, "audit log destination ID is required")
+		}
+		if ids[destination.ID] {
+			return status.Errorf(codes.InvalidArgument, "duplicate audit log destination ID %q", destination.ID)
+		}
+		ids[destination.ID] = true
+		switch destination.Type {
+		case api.AuditLogDestinationSyslog:
+			if destination.Syslog == nil || destination.Syslog.Address == "" {
+				return status.Errorf(codes.InvalidArgument, "syslog address is required for audit log destination %q", destination.ID)
+			}
+			if destination.Syslog.Network != "udp" && destination.Syslog.Network != "tcp" {
+				return status.Errorf(codes.InvalidArgument, "syslog network should be udp or tcp for audit log destination %q", destination.ID)
+			}
+		case api.AuditLogDestinationHTTP:
+			if destination.HTTP == nil {
+				return status.Errorf(codes.InvalidArgument, "HTTP URL is required for audit log destination %q", destination.ID)
+			}
+			u, err := url.Parse(destination.HTTP.URL)
+			if err != nil || u.Host == "" || (u.Scheme != "https" && u.Scheme != "http") {
+				return status.Errorf(codes.InvalidArgument, "invalid HTTP URL %q for audit log destination %q", destination.HTTP.URL, destination.ID)
+			}
+		case api.AuditLogDestinationFile:
+			if destination.File == nil || !filepath.IsAbs(destination.File.Path) {
+				return status.Errorf(codes.InvalidArgument, "absolute file path is required for audit log destination %q", destination.ID)
+			}
+			if destination.File.MaxSizeMB < 0 || destination.File.MaxBackups < 0 {
+				return status.Errorf(codes.InvalidArgument, "file rotation values cannot be negative for audit log destination %q", destination.ID)
+			}
+		default:
+			return status.Errorf(codes.InvalidArgument, "invalid type %q for audit log destination %q", destination.Type, destination.ID)
+		}
+	}
+	return nil
+}
+
+func validateWorkspaceWebhookSetting(setting *api.SettingWorkspaceWebhookValue) error {
+	ids := make(map[string]bool)
+	for _, hook := range setting.Webhooks {
+		if hook.ID == "" {
+			return status.Errorf(codes.InvalidArgument, "workspace webhook ID is required")
+		}
+		if ids[hook.ID] {
+			return status.Errorf(codes.InvalidArgument, "duplicate workspace webhook ID %q", hook.ID)
+		}
+		ids[hook.ID] = true
+		if convertWebhookTypeString(hook.Type) == v1pb.Webhook_TYPE_UNSPECIFIED {
+			return status.Errorf(codes.InvalidArgument, "invalid type %q for workspace webhook %q", hook.Type, hook.ID)
+		}
+		u, err := url.Parse(hook.URL)
+		if err != nil || u.Host == "" || (u.Scheme != "https" && u.Scheme != "http") {
+			return status.Errorf(codes.InvalidArgument, "invalid URL %q for workspace webhook %q", hook.URL, hook.ID)
+		}
+	}
+	return nil
+}
@@ -38,6 +38,7 @@ import (
 	"github.com/bytebase/bytebase/backend/plugin/parser/base"
 	pgparser "github.com/bytebase/bytebase/backend/plugin/parser/pg"
 	"github.com/bytebase/bytebase/backend/plugin/parser/sql/transform"
+	webhookplugin "github.com/bytebase/bytebase/backend/plugin/webhook"
 	"github.com/bytebase/bytebase/backend/runner/schemasync"
 	"github.com/bytebase/bytebase/backend/store"
 	"github.com/bytebase/bytebase/backend/utils"
@@ -299,6 +300,7 @@ func (s *SQLService) Export(ctx context.Context, request *v1pb.ExportRequest) (*
 	if exportErr != nil {
 		return nil, exportErr
 	}
+	s.postExportEvent(ctx, user, instance, database, request)
 
 	return &v1pb.ExportResponse{
 		Content: bytes,
@@ -782,6 +784,27 @@ func convertValueToStringInXLSX(value *v1pb.RowValue) string {
 	}
 }
 
+// postExportEvent posts the SQL export event to the webhooks, the database is nil if the statement is executed on the instance.
+func (s *SQLService) postExportEvent(ctx context.Context, user *store.UserMessage, instance *store.InstanceMessage, database *store.DatabaseMessage, request *v1pb.ExportRequest) {
+	name := instance.Title
+	if database != nil {
+		name = database.DatabaseName
+	}
+	s.activityManager.PostEvent(ctx, &activity.Event{
+		Type:        api.ActivitySQLExport,
+		Level:       webhookplugin.WebhookInfo,
+		Title:       fmt.Sprintf("SQL exported - %s", name),
+		Description: common.TruncateStringWithDescription(request.Statement),
+		CreatorUID:  user.ID,
+		Instance:    instance,
+		Database:    database,
+		SQLExport: &webhookplugin.SQLExport{
+			Statement: request.Statement,
+			Format:    request.Format.String(),
+		},
+	})
+}
+
 func (s *SQLService) createExportActivity(ctx context.Context, user *store.UserMessage, level api.ActivityLevel, containerID int, payload api.ActivitySQLExportPayload) (*store.ActivityMessage, error) {
 	// TODO: use v1 activity API instead of
 	activityBytes, err := json.Marshal(payload)
@@ -16,6 +16,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 
+	"github.com/bytebase/bytebase/backend/api/auth"
 	"github.com/bytebase/bytebase/backend/common"
 	"github.com/bytebase/bytebase/backend/common/log"
 	"github.com/bytebase/bytebase/backend/server"
@@ -69,6 +70,14 @@ var (
 		readonly bool
 		// saas means the Bytebase is running in SaaS mode, several features is only controlled by us instead of users under this mode.
 		saas bool
+		// ha means running in the high-availability mode, multiple replicas share the same external PostgreSQL metadata database.
+		ha bool
+		// uninstallHATriggers means uninstalling the cache invalidation triggers after leaving the high-availability mode.
+		uninstallHATriggers bool
+		// trustedProxies is the IP addresses or CIDRs of the reverse proxies in front of Bytebase.
+		trustedProxies []string
+		// activityLedgerKeyFile is the file of the key for the activity ledger hash chain.
+		activityLedgerKeyFile string
 		// demoName is the name of the demo and should be one of the subpath name in the ../migrator/demo directory.
 		// empty means no demo.
 		demoName string
@@ -119,6 +128,10 @@ func init() {
 	rootCmd.PersistentFlags().StringVar(&flags.dataDir, "data", ".", "directory where Bytebase stores data. If relative path is supplied, then the path is relative to the directory where Bytebase is under")
 	rootCmd.PersistentFlags().BoolVar(&flags.readonly, "readonly", false, "whether to run in read-only mode")
 	rootCmd.PersistentFlags().BoolVar(&flags.saas, "saas", false, "whether to run in SaaS mode")
+	rootCmd.PersistentFlags().BoolVar(&flags.ha, "ha", false, "whether to run in high-availability mode with multiple replicas. Requires --pg")
+	rootCmd.PersistentFlags().BoolVar(&flags.uninstallHATriggers, "uninstall-ha-triggers", false, "uninstall the cache invalidation triggers of the high-availability mode. Only use it after all replicas stop running in high-availability mode")
+	rootCmd.PersistentFlags().StringVar(&flags.activityLedgerKeyFile, "activity-ledger-key-file", "", "file of the secret key for the activity ledger hash chain, which must be kept outside the metadata database. Default to activity_ledger.key generated in the --data directory")
+	rootCmd.PersistentFlags().StringSliceVar(&flags.trustedProxies, "trusted-proxies", nil, "comma separated IP addresses or CIDRs of the reverse proxies in front of Bytebase, the X-Forwarded-For header is only trusted from them")
 	// Must be one of the subpath name in the ../migrator/demo directory
 	rootCmd.PersistentFlags().StringVar(&flags.demoName, "demo", "", "name of the demo to use. Empty means not running in demo mode.")
 	rootCmd.PersistentFlags().BoolVar(&flags.debug, "debug", false, "whether to enable debug level logging")
@@ -217,7 +230,31 @@ func start() {
 		return
 	}
 
+	// The replicas coordinate through the shared metadata database, which can't be the embedded one.
+	if flags.ha && flags.pgURL == "" {
+		slog.Error("high-availability mode requires storing metadata in external PostgreSQL instance with --pg")
+		return
+	}
+
+	if flags.ha && flags.uninstallHATriggers {
+		slog.Error("--uninstall-ha-triggers cannot be used in high-availability mode")
+		return
+	}
+
+	// The replicas chain the activity ledger with the same key.
+	if flags.ha && flags.activityLedgerKeyFile == "" {
+		slog.Error("high-availability mode requires the activity ledger key shared by the replicas with --activity-ledger-key-file")
+		return
+	}
+
+	trustedProxies, err := auth.ParseTrustedProxies(flags.trustedProxies)
+	if err != nil {
+		slog.Error("invalid --trusted-proxies", log.BBError(err))
+		return
+	}
+
 	profile := activeProfile(flags.dataDir)
+	profile.TrustedProxies = trustedProxies
 
 	// The ideal bootstrap order is:
 	// 1. Connect to the metadb
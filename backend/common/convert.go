@@ -2,6 +2,7 @@ package common
 
 import (
 	"encoding/base64"
+	"time"
 
 	"github.com/google/cel-go/cel"
 	"github.com/pkg/errors"
@@ -239,6 +240,8 @@ type QueryExportFactors struct {
 	DatabaseNames []string
 	ExportRows    int64
 	Statement     string
+	// ExpireTime is the earliest "request.time < timestamp(...)" bound, or zero if the expression never expires.
+	ExpireTime time.Time
 }
 
 // GetQueryExportFactors is used to get risk factors from query and export expressions.
@@ -289,6 +292,19 @@ func findField(callExpr *exprproto.Expr_Call, factors *QueryExportFactors) {
 				}
 				factors.Statement = string(statement)
 			}
+			if idExpr.Name == "request.time" && (callExpr.Function == "_<_" || callExpr.Function == "_<=_") {
+				timestampExpr := callExpr.Args[1].GetCallExpr()
+				if timestampExpr == nil || timestampExpr.Function != "timestamp" || len(timestampExpr.Args) != 1 {
+					return
+				}
+				expireTime, err := time.Parse(time.RFC3339, timestampExpr.Args[0].GetConstExpr().GetStringValue())
+				if err != nil {
+					return
+				}
+				if factors.ExpireTime.IsZero() || expireTime.Before(factors.ExpireTime) {
+					factors.ExpireTime = expireTime
+				}
+			}
 			return
 		}
 	}
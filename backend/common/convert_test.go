@@ -2,6 +2,7 @@ package common
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -17,6 +18,7 @@ func TestGetQueryExportFactors(t *testing.T) {
 			want: QueryExportFactors{
 				DatabaseNames: []string{"instances/postgres-sample/databases/employee"},
 				ExportRows:    1000,
+				ExpireTime:    time.Date(2023, 7, 4, 6, 9, 3, 384000000, time.UTC),
 			},
 		},
 		{
@@ -25,22 +27,27 @@ func TestGetQueryExportFactors(t *testing.T) {
 				DatabaseNames: []string{"instances/postgres-sample/databases/employee"},
 				ExportRows:    1000,
 				Statement:     "select * from employee;",
+				ExpireTime:    time.Date(2023, 7, 4, 7, 40, 5, 658000000, time.UTC),
 			},
 		},
 		{
 			expression: "request.time < timestamp(\"2023-08-02T07:33:45.686Z\") && (resource.database == \"instances/postgres-sample/databases/employee\" && resource.schema == \"public\" && resource.table in [\"dept_emp\",\"department\"])",
 			want: QueryExportFactors{
 				DatabaseNames: []string{"instances/postgres-sample/databases/employee"},
+				ExpireTime:    time.Date(2023, 8, 2, 7, 33, 45, 686000000, time.UTC),
 			},
 		},
 		{
 			expression: "request.time < timestamp(\"2023-07-10T08:14:34.788Z\")",
-			want:       QueryExportFactors{},
+			want: QueryExportFactors{
+				ExpireTime: time.Date(2023, 7, 10, 8, 14, 34, 788000000, time.UTC),
+			},
 		},
 		{
 			expression: "request.time < timestamp(\"2023-07-10T08:15:46.773Z\") && ((resource.database in [\"instances/postgres-sample/databases/blog\"]) || (resource.database == \"instances/postgres-sample/databases/employee\" && resource.schema in [\"public\"]))",
 			want: QueryExportFactors{
 				DatabaseNames: []string{"instances/postgres-sample/databases/blog", "instances/postgres-sample/databases/employee"},
+				ExpireTime:    time.Date(2023, 7, 10, 8, 15, 46, 773000000, time.UTC),
 			},
 		},
 	}
@@ -33,6 +33,7 @@ const (
 	RolePrefix                   = "roles/"
 	SecretNamePrefix             = "secrets/"
 	WebhookIDPrefix              = "webhooks/"
+	WebhookDeliveryIDPrefix      = "deliveries/"
 	SheetIDPrefix                = "sheets/"
 	DatabaseGroupNamePrefix      = "databaseGroups/"
 	SchemaGroupNamePrefix        = "schemaGroups/"
@@ -46,6 +47,8 @@ const (
 	SchemaDesignPrefix           = "schemaDesigns/"
 	DeploymentConfigPrefix       = "deploymentConfigs/"
 	ChangelistsPrefix            = "changelists/"
+	AccessTokenPrefix            = "accessTokens/"
+	ApprovalDelegationPrefix     = "approvalDelegations/"
 
 	BackupSettingSuffix   = "/backupSetting"
 	SchemaSuffix          = "/schema"
@@ -99,6 +102,15 @@ func GetProjectIDWebhookID(name string) (string, string, error) {
 	return tokens[0], tokens[1], nil
 }
 
+// GetProjectIDWebhookIDDeliveryID returns the project ID, webhook ID and webhook delivery ID from a resource name.
+func GetProjectIDWebhookIDDeliveryID(name string) (string, string, string, error) {
+	tokens, err := GetNameParentTokens(name, ProjectNamePrefix, WebhookIDPrefix, WebhookDeliveryIDPrefix)
+	if err != nil {
+		return "", "", "", err
+	}
+	return tokens[0], tokens[1], tokens[2], nil
+}
+
 func GetProjectIDDeploymentConfigID(name string) (string, string, error) {
 	tokens, err := GetNameParentTokens(name, ProjectNamePrefix, DeploymentConfigPrefix)
 	if err != nil {
@@ -219,6 +231,40 @@ func GetUserID(name string) (int, error) {
 	return GetUIDFromName(name, UserNamePrefix)
 }
 
+// GetUserIDAccessTokenID returns the user ID and access token ID from a resource name.
+func GetUserIDAccessTokenID(name string) (int, int, error) {
+	tokens, err := GetNameParentTokens(name, UserNamePrefix, AccessTokenPrefix)
+	if err != nil {
+		return 0, 0, err
+	}
+	userID, err := strconv.Atoi(tokens[0])
+	if err != nil {
+		return 0, 0, errors.Errorf("invalid user ID %q", tokens[0])
+	}
+	tokenID, err := strconv.Atoi(tokens[1])
+	if err != nil {
+		return 0, 0, errors.Errorf("invalid access token ID %q", tokens[1])
+	}
+	return userID, tokenID, nil
+}
+
+// GetUserIDApprovalDelegationID returns the user ID and approval delegation ID from a resource name.
+func GetUserIDApprovalDelegationID(name string) (int, int, error) {
+	tokens, err := GetNameParentTokens(name, UserNamePrefix, ApprovalDelegationPrefix)
+	if err != nil {
+		return 0, 0, err
+	}
+	userID, err := strconv.Atoi(tokens[0])
+	if err != nil {
+		return 0, 0, errors.Errorf("invalid user ID %q", tokens[0])
+	}
+	delegationID, err := strconv.Atoi(tokens[1])
+	if err != nil {
+		return 0, 0, errors.Errorf("invalid approval delegation ID %q", tokens[1])
+	}
+	return userID, delegationID, nil
+}
+
 // GetUserEmail returns the user email from a resource name.
 func GetUserEmail(name string) (string, error) {
 	tokens, err := GetNameParentTokens(name, UserNamePrefix)
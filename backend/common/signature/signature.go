@@ -0,0 +1,52 @@
+// Package signature signs the HTTP requests sent by Bytebase and verifies the signed callbacks, e.g. the
+// custom webhooks and the external approval requests and callbacks.
+package signature
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// Header is the header of the HMAC-SHA256 signature of the request body signed at the timestamp.
+	Header = "X-Bytebase-Signature"
+	// TimestampHeader is the header of the unix timestamp when the request body is signed.
+	TimestampHeader = "X-Bytebase-Timestamp"
+
+	prefix = "sha256="
+	// tolerance is how far the timestamp can be from now, which prevents replaying the signed requests.
+	tolerance = 5 * time.Minute
+)
+
+// Sign returns the signature of the body signed at the timestamp.
+// The signature is "sha256=" followed by the hex encoded HMAC-SHA256 of "{timestamp}.{body}".
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return prefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify verifies the signature of the body, and the timestamp must be within the tolerance of now.
+func Verify(secret, timestamp, signature string, body []byte, now time.Time) error {
+	if timestamp == "" || signature == "" {
+		return errors.Errorf("%s and %s headers are required", TimestampHeader, Header)
+	}
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return errors.Errorf("invalid timestamp %q", timestamp)
+	}
+	if d := now.Sub(time.Unix(ts, 0)); d > tolerance || d < -tolerance {
+		return errors.Errorf("timestamp %q is out of the tolerance", timestamp)
+	}
+	if !hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature)) {
+		return errors.New("signature mismatch")
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package signature
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerify(t *testing.T) {
+	now := time.Now()
+	body := []byte(`{"id":"CHG0001"}`)
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	oldTimestamp := strconv.FormatInt(now.Add(-time.Hour).Unix(), 10)
+
+	tests := []struct {
+		name        string
+		timestamp   string
+		signature   string
+		body        []byte
+		containsErr string
+	}{
+		{
+			name:      "valid",
+			timestamp: timestamp,
+			signature: Sign("secret", timestamp, body),
+			body:      body,
+		},
+		{
+			name:        "missing signature",
+			timestamp:   timestamp,
+			body:        body,
+			containsErr: "headers are required",
+		},
+		{
+			name:        "wrong secret",
+			timestamp:   timestamp,
+			signature:   Sign("other", timestamp, body),
+			body:        body,
+			containsErr: "signature mismatch",
+		},
+		{
+			name:        "tampered body",
+			timestamp:   timestamp,
+			signature:   Sign("secret", timestamp, body),
+			body:        []byte(`{"id":"CHG0002"}`),
+			containsErr: "signature mismatch",
+		},
+		{
+			name:        "replayed",
+			timestamp:   oldTimestamp,
+			signature:   Sign("secret", oldTimestamp, body),
+			body:        body,
+			containsErr: "out of the tolerance",
+		},
+	}
+	for _, test := range tests {
+		err := Verify("secret", test.timestamp, test.signature, test.body, now)
+		if test.containsErr == "" {
+			require.NoError(t, err, test.name)
+			continue
+		}
+		require.ErrorContains(t, err, test.containsErr, test.name)
+	}
+}
@@ -28,6 +28,8 @@ const (
 	MaxSheetSizeForTaskCheck = 10 * 1024 * 1024
 	// MaxSheetSizeForRollback is the maximum size of a sheet for rollback generator to run.
 	MaxSheetSizeForRollback = 8 * 1024 * 1024
+	// DefaultRollbackPreImageRowLimit is the default maximum number of rows captured for generating the PostgreSQL rollback SQL.
+	DefaultRollbackPreImageRowLimit = 1000
 	// MaxSheetSizeForPlanCheckDML is the maximum size of a sheet for plan check to check DML changes.
 	MaxSheetSizeForPlanCheckDML = 512 * 1024
 	// MaxStatementSizeForSQLReview is the maximum size of the statement to do sql review checks in sql service.
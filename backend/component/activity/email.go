@@ -0,0 +1,233 @@
+package activity
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/gosimple/slug"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/store"
+	storepb "github.com/bytebase/bytebase/proto/generated-go/store"
+)
+
+// mentionRegexp matches the users mentioned by email in the comments, e.g. "@alice@example.com".
+var mentionRegexp = regexp.MustCompile(`(?:^|\s)@([A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,})`)
+
+// EmailNotificationPayload is the content of an issue email notification.
+// The notifications of a recipient are sent in one digest email by the issue mail sender.
+type EmailNotificationPayload struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	IssueName   string `json:"issueName"`
+	ProjectName string `json:"projectName"`
+	Link        string `json:"link"`
+	ActorName   string `json:"actorName"`
+}
+
+// createEmailNotifications creates the email notifications of the issue activity for the recipients.
+// It does nothing if the mail delivery is not configured.
+func (m *Manager) createEmailNotifications(ctx context.Context, activity *store.ActivityMessage, issue *store.IssueMessage) error {
+	name := api.SettingWorkspaceMailDelivery
+	mailSetting, err := m.store.GetSettingV2(ctx, &store.FindSettingMessage{Name: &name})
+	if err != nil {
+		return errors.Wrapf(err, "failed to get mail delivery setting")
+	}
+	if mailSetting == nil {
+		return nil
+	}
+
+	event, title, recipients, err := m.getEmailNotificationRecipients(ctx, activity, issue)
+	if err != nil {
+		return err
+	}
+	if event == storepb.NotificationSetting_EVENT_UNSPECIFIED || len(recipients) == 0 {
+		return nil
+	}
+
+	setting, err := m.store.GetWorkspaceGeneralSetting(ctx)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get workspace setting")
+	}
+	actor, err := m.store.GetUserByID(ctx, activity.CreatorUID)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get principal %d", activity.CreatorUID)
+	}
+	if actor == nil {
+		return errors.Errorf("principal %d not found", activity.CreatorUID)
+	}
+	link := fmt.Sprintf("%s/issue/%s-%d", setting.ExternalUrl, slug.Make(issue.Title), issue.UID)
+	if activity.Type == api.ActivityIssueCommentCreate {
+		link += fmt.Sprintf("#activity%d", activity.UID)
+	}
+	payload, err := json.Marshal(&EmailNotificationPayload{
+		Title:       title,
+		Description: activity.Comment,
+		IssueName:   issue.Title,
+		ProjectName: issue.Project.Title,
+		Link:        link,
+		ActorName:   actor.Name,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal email notification payload")
+	}
+
+	var creates []*store.EmailNotificationMessage
+	for _, recipient := range filterEmailNotificationRecipients(recipients, activity.CreatorUID, event) {
+		creates = append(creates, &store.EmailNotificationMessage{
+			RecipientID: recipient.ID,
+			Event:       event,
+			IssueUID:    issue.UID,
+			Payload:     string(payload),
+		})
+	}
+	return m.store.CreateEmailNotifications(ctx, creates)
+}
+
+// getEmailNotificationRecipients returns the notification event, the title and the recipients of the issue activity.
+// The event is unspecified if the activity doesn't notify anyone by email.
+func (m *Manager) getEmailNotificationRecipients(ctx context.Context, activity *store.ActivityMessage, issue *store.IssueMessage) (storepb.NotificationSetting_Event, string, []*store.UserMessage, error) {
+	switch activity.Type {
+	case api.ActivityIssueCreate:
+		recipients := []*store.UserMessage{issue.Assignee}
+		recipients = append(recipients, issue.Subscribers...)
+		return storepb.NotificationSetting_ISSUE_CREATED, "Issue created", recipients, nil
+	case api.ActivityIssueApprovalNotify:
+		payload := &api.ActivityIssueApprovalNotifyPayload{}
+		if err := json.Unmarshal([]byte(activity.Payload), payload); err != nil {
+			return 0, "", nil, errors.Wrapf(err, "failed to unmarshal approval notify payload")
+		}
+		protoPayload := &storepb.ActivityIssueApprovalNotifyPayload{}
+		if err := protojson.Unmarshal([]byte(payload.ProtoPayload), protoPayload); err != nil {
+			return 0, "", nil, errors.Wrapf(err, "failed to unmarshal approval notify proto payload")
+		}
+		var recipients []*store.UserMessage
+		for _, node := range protoPayload.GetApprovalStep().GetNodes() {
+			users, err := listApprovalNodeUsers(ctx, m.store, node, issue.Project.ResourceID)
+			if err != nil {
+				return 0, "", nil, errors.Wrapf(err, "failed to list approval node users")
+			}
+			recipients = append(recipients, users...)
+		}
+		return storepb.NotificationSetting_APPROVAL_REQUESTED, "Approval requested", recipients, nil
+	case api.ActivityIssueCommentCreate:
+		payload := &storepb.ActivityIssueCommentCreatePayload{}
+		if activity.Payload != "" {
+			if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal([]byte(activity.Payload), payload); err != nil {
+				return 0, "", nil, errors.Wrapf(err, "failed to unmarshal comment payload")
+			}
+		}
+		if approvalEvent := payload.GetApprovalEvent(); approvalEvent != nil {
+			switch approvalEvent.Status {
+			case storepb.ActivityIssueCommentCreatePayload_ApprovalEvent_APPROVED:
+				return storepb.NotificationSetting_ISSUE_APPROVED, "Issue approved", []*store.UserMessage{issue.Creator}, nil
+			case storepb.ActivityIssueCommentCreatePayload_ApprovalEvent_REJECTED:
+				return storepb.NotificationSetting_ISSUE_REJECTED, "Issue rejected", []*store.UserMessage{issue.Creator}, nil
+			}
+			return 0, "", nil, nil
+		}
+		if payload.Event != nil {
+			return 0, "", nil, nil
+		}
+		recipients, err := m.listMentionedUsers(ctx, activity.Comment, issue)
+		if err != nil {
+			return 0, "", nil, err
+		}
+		return storepb.NotificationSetting_COMMENT_MENTIONED, "Mentioned in a comment", recipients, nil
+	case api.ActivityPipelineTaskRunStatusUpdate:
+		payload := &api.ActivityPipelineTaskRunStatusUpdatePayload{}
+		if err := json.Unmarshal([]byte(activity.Payload), payload); err != nil {
+			return 0, "", nil, errors.Wrapf(err, "failed to unmarshal task run status update payload")
+		}
+		if payload.NewStatus != api.TaskRunFailed {
+			return 0, "", nil, nil
+		}
+		return storepb.NotificationSetting_TASK_FAILED, "Task failed - " + payload.TaskName, []*store.UserMessage{issue.Creator, issue.Assignee}, nil
+	case api.ActivityIssueStatusUpdate:
+		payload := &api.ActivityIssueStatusUpdatePayload{}
+		if err := json.Unmarshal([]byte(activity.Payload), payload); err != nil {
+			return 0, "", nil, errors.Wrapf(err, "failed to unmarshal issue status update payload")
+		}
+		if payload.NewStatus != api.IssueDone {
+			return 0, "", nil, nil
+		}
+		recipients := []*store.UserMessage{issue.Creator}
+		recipients = append(recipients, issue.Subscribers...)
+		return storepb.NotificationSetting_ROLLOUT_DONE, "Rollout done", recipients, nil
+	}
+	return 0, "", nil, nil
+}
+
+// listMentionedUsers lists the users mentioned in the comment who can view the issue.
+func (m *Manager) listMentionedUsers(ctx context.Context, comment string, issue *store.IssueMessage) ([]*store.UserMessage, error) {
+	matches := mentionRegexp.FindAllStringSubmatch(comment, -1)
+	if len(matches) == 0 {
+		return nil, nil
+	}
+	projectPolicy, err := m.store.GetProjectPolicy(ctx, &store.GetProjectPolicyMessage{ProjectID: &issue.Project.ResourceID})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get project policy")
+	}
+
+	var users []*store.UserMessage
+	for _, match := range matches {
+		email := strings.ToLower(strings.TrimRight(match[1], "."))
+		user, err := m.store.GetUser(ctx, &store.FindUserMessage{Email: &email})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get user %q", email)
+		}
+		if user == nil {
+			continue
+		}
+		if user.Role != api.Owner && user.Role != api.DBA && !isProjectMember(projectPolicy, user.ID) {
+			continue
+		}
+		users = append(users, user)
+	}
+	return users, nil
+}
+
+// filterEmailNotificationRecipients returns the distinct recipients who want the email notifications of the event.
+func filterEmailNotificationRecipients(recipients []*store.UserMessage, actorID int, event storepb.NotificationSetting_Event) []*store.UserMessage {
+	var users []*store.UserMessage
+	seen := map[int]bool{}
+	for _, recipient := range recipients {
+		// Users are not notified of their own activities.
+		if recipient == nil || recipient.ID == actorID || seen[recipient.ID] {
+			continue
+		}
+		seen[recipient.ID] = true
+		if recipient.Type != api.EndUser || recipient.MemberDeleted {
+			continue
+		}
+		if !isEmailNotificationEnabled(recipient.NotificationSetting, event) {
+			continue
+		}
+		users = append(users, recipient)
+	}
+	return users
+}
+
+func isProjectMember(policy *store.IAMPolicyMessage, userID int) bool {
+	for _, binding := range policy.Bindings {
+		for _, member := range binding.Members {
+			if member.ID == userID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isEmailNotificationEnabled(setting *storepb.NotificationSetting, event storepb.NotificationSetting_Event) bool {
+	if setting.GetEmailDisabled() {
+		return false
+	}
+	return !slices.Contains(setting.GetEmailMutedEvents(), event)
+}
@@ -0,0 +1,66 @@
+package activity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/store"
+	storepb "github.com/bytebase/bytebase/proto/generated-go/store"
+)
+
+func TestFilterEmailNotificationRecipients(t *testing.T) {
+	actor := &store.UserMessage{ID: 101, Type: api.EndUser}
+	alice := &store.UserMessage{ID: 102, Type: api.EndUser}
+	bob := &store.UserMessage{ID: 103, Type: api.EndUser, NotificationSetting: &storepb.NotificationSetting{
+		EmailMutedEvents: []storepb.NotificationSetting_Event{storepb.NotificationSetting_TASK_FAILED},
+	}}
+	carol := &store.UserMessage{ID: 104, Type: api.EndUser, NotificationSetting: &storepb.NotificationSetting{
+		EmailDisabled: true,
+	}}
+	deleted := &store.UserMessage{ID: 105, Type: api.EndUser, MemberDeleted: true}
+	serviceAccount := &store.UserMessage{ID: 106, Type: api.ServiceAccount}
+
+	tests := []struct {
+		name       string
+		recipients []*store.UserMessage
+		event      storepb.NotificationSetting_Event
+		want       []*store.UserMessage
+	}{
+		{
+			name:       "skip the actor and the duplicates",
+			recipients: []*store.UserMessage{actor, alice, nil, alice},
+			event:      storepb.NotificationSetting_ISSUE_CREATED,
+			want:       []*store.UserMessage{alice},
+		},
+		{
+			name:       "skip the muted event",
+			recipients: []*store.UserMessage{alice, bob},
+			event:      storepb.NotificationSetting_TASK_FAILED,
+			want:       []*store.UserMessage{alice},
+		},
+		{
+			name:       "notify the events not muted",
+			recipients: []*store.UserMessage{alice, bob},
+			event:      storepb.NotificationSetting_ROLLOUT_DONE,
+			want:       []*store.UserMessage{alice, bob},
+		},
+		{
+			name:       "skip the disabled email notifications",
+			recipients: []*store.UserMessage{carol, alice},
+			event:      storepb.NotificationSetting_ISSUE_CREATED,
+			want:       []*store.UserMessage{alice},
+		},
+		{
+			name:       "skip the deleted members and the non end users",
+			recipients: []*store.UserMessage{deleted, serviceAccount},
+			event:      storepb.NotificationSetting_ISSUE_CREATED,
+		},
+	}
+
+	a := require.New(t)
+	for _, test := range tests {
+		a.Equal(test.want, filterEmailNotificationRecipients(test.recipients, actor.ID, test.event), test.name)
+	}
+}
@@ -17,7 +17,6 @@ import (
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/encoding/protojson"
 
-	"github.com/bytebase/bytebase/backend/common"
 	"github.com/bytebase/bytebase/backend/common/log"
 	api "github.com/bytebase/bytebase/backend/legacyapi"
 	"github.com/bytebase/bytebase/backend/plugin/webhook"
@@ -37,6 +36,24 @@ type Metadata struct {
 	Issue *store.IssueMessage
 }
 
+// Event is the webhook event that is not an issue activity, e.g. anomalies and SQL exports.
+type Event struct {
+	Type        api.ActivityType
+	Level       webhook.Level
+	Title       string
+	Description string
+	CreatorUID  int
+	// Project is the project of the event, it defaults to the project of the database.
+	Project  *store.ProjectMessage
+	Instance *store.InstanceMessage
+	Database *store.DatabaseMessage
+
+	Anomaly   *webhook.Anomaly
+	Backup    *webhook.Backup
+	SQLExport *webhook.SQLExport
+	Member    *webhook.Member
+}
+
 // NewManager creates an activity manager.
 func NewManager(store *store.Store) *Manager {
 	return &Manager{
@@ -79,15 +96,12 @@ func (m *Manager) BatchCreateActivitiesForRunTasks(ctx context.Context, tasks []
 	anyActivity := activityList[0]
 
 	activityType := api.ActivityPipelineTaskRunStatusUpdate
-	webhookList, err := m.store.FindProjectWebhookV2(ctx, &store.FindProjectWebhookMessage{
-		ProjectID:    &issue.Project.UID,
-		ActivityType: &activityType,
-	})
+	deliveries, err := m.listWebhookDeliveries(ctx, &issue.Project.UID, activityType)
 	if err != nil {
-		return errors.Wrapf(err, "failed to find project webhook after changing the issue status: %v", issue.Title)
+		return errors.Wrapf(err, "failed to find webhooks after changing the issue status: %v", issue.Title)
 	}
 
-	if len(webhookList) == 0 {
+	if len(deliveries) == 0 {
 		return nil
 	}
 
@@ -123,10 +137,8 @@ func (m *Manager) BatchCreateActivitiesForRunTasks(ctx context.Context, tasks []
 		CreatorName:  user.Name,
 		CreatorEmail: user.Email,
 	}
-	// Call external webhook endpoint in Go routine to avoid blocking web serving thread.
-	go postWebhookList(ctx, &webhookCtx, webhookList)
-
-	return nil
+	// The webhooks are posted by the delivery runner to avoid blocking web serving thread.
+	return m.postWebhookList(ctx, &webhookCtx, deliveries)
 }
 
 // BatchCreateActivitiesForSkipTasks creates activities for skipping tasks.
@@ -164,14 +176,11 @@ func (m *Manager) BatchCreateActivitiesForSkipTasks(ctx context.Context, tasks [
 	anyActivity := activityList[0]
 
 	activityType := api.ActivityPipelineTaskRunStatusUpdate
-	webhookList, err := m.store.FindProjectWebhookV2(ctx, &store.FindProjectWebhookMessage{
-		ProjectID:    &issue.Project.UID,
-		ActivityType: &activityType,
-	})
+	deliveries, err := m.listWebhookDeliveries(ctx, &issue.Project.UID, activityType)
 	if err != nil {
-		return errors.Wrapf(err, "failed to find project webhook after changing the issue status: %v", issue.Title)
+		return errors.Wrapf(err, "failed to find webhooks after changing the issue status: %v", issue.Title)
 	}
-	if len(webhookList) == 0 {
+	if len(deliveries) == 0 {
 		return nil
 	}
 
@@ -207,10 +216,8 @@ func (m *Manager) BatchCreateActivitiesForSkipTasks(ctx context.Context, tasks [
 		CreatorName:  user.Name,
 		CreatorEmail: user.Email,
 	}
-	// Call external webhook endpoint in Go routine to avoid blocking web serving thread.
-	go postWebhookList(ctx, &webhookCtx, webhookList)
-
-	return nil
+	// The webhooks are posted by the delivery runner to avoid blocking web serving thread.
+	return m.postWebhookList(ctx, &webhookCtx, deliveries)
 }
 
 // BatchCreateActivitiesForCancelTaskRuns creates activities for cancelling task runs.
@@ -248,15 +255,12 @@ func (m *Manager) BatchCreateActivitiesForCancelTaskRuns(ctx context.Context, ta
 	anyActivity := activityList[0]
 
 	activityType := api.ActivityPipelineTaskRunStatusUpdate
-	webhookList, err := m.store.FindProjectWebhookV2(ctx, &store.FindProjectWebhookMessage{
-		ProjectID:    &issue.Project.UID,
-		ActivityType: &activityType,
-	})
+	deliveries, err := m.listWebhookDeliveries(ctx, &issue.Project.UID, activityType)
 	if err != nil {
-		return errors.Wrapf(err, "failed to find project webhook after changing the issue status: %v", issue.Title)
+		return errors.Wrapf(err, "failed to find webhooks after changing the issue status: %v", issue.Title)
 	}
 
-	if len(webhookList) == 0 {
+	if len(deliveries) == 0 {
 		return nil
 	}
 
@@ -292,10 +296,8 @@ func (m *Manager) BatchCreateActivitiesForCancelTaskRuns(ctx context.Context, ta
 		CreatorName:  user.Name,
 		CreatorEmail: user.Email,
 	}
-	// Call external webhook endpoint in Go routine to avoid blocking web serving thread.
-	go postWebhookList(ctx, &webhookCtx, webhookList)
-
-	return nil
+	// The webhooks are posted by the delivery runner to avoid blocking web serving thread.
+	return m.postWebhookList(ctx, &webhookCtx, deliveries)
 }
 
 // BatchCreateTaskStatusUpdateApprovalActivity creates a batch task status update activities for task approvals.
@@ -333,14 +335,11 @@ func (m *Manager) BatchCreateTaskStatusUpdateApprovalActivity(ctx context.Contex
 	anyActivity := activityList[0]
 
 	activityType := api.ActivityPipelineTaskStatusUpdate
-	webhookList, err := m.store.FindProjectWebhookV2(ctx, &store.FindProjectWebhookMessage{
-		ProjectID:    &issue.Project.UID,
-		ActivityType: &activityType,
-	})
+	deliveries, err := m.listWebhookDeliveries(ctx, &issue.Project.UID, activityType)
 	if err != nil {
-		return errors.Wrapf(err, "failed to find project webhook after changing the issue status: %v", issue.Title)
+		return errors.Wrapf(err, "failed to find webhooks after changing the issue status: %v", issue.Title)
 	}
-	if len(webhookList) == 0 {
+	if len(deliveries) == 0 {
 		return nil
 	}
 
@@ -376,10 +375,8 @@ func (m *Manager) BatchCreateTaskStatusUpdateApprovalActivity(ctx context.Contex
 		CreatorName:  user.Name,
 		CreatorEmail: user.Email,
 	}
-	// Call external webhook endpoint in Go routine to avoid blocking web serving thread.
-	go postWebhookList(ctx, &webhookCtx, webhookList)
-
-	return nil
+	// The webhooks are posted by the delivery runner to avoid blocking web serving thread.
+	return m.postWebhookList(ctx, &webhookCtx, deliveries)
 }
 
 // CreateActivity creates an activity.
@@ -389,6 +386,21 @@ func (m *Manager) CreateActivity(ctx context.Context, create *store.ActivityMess
 		return nil, err
 	}
 
+	issue := meta.Issue
+	// The approval comments are created without the issue metadata, and their container is the issue.
+	if issue == nil && create.Type == api.ActivityIssueCommentCreate {
+		if issue, err = m.store.GetIssueV2(ctx, &store.FindIssueMessage{UID: &create.ContainerUID}); err != nil {
+			slog.Warn("Failed to get issue for email notifications", slog.Int("issue_id", create.ContainerUID), log.BBError(err))
+		}
+	}
+	if issue != nil {
+		if err := m.createEmailNotifications(ctx, activity, issue); err != nil {
+			slog.Warn("Failed to create email notifications",
+				slog.String("issue_name", issue.Title),
+				log.BBError(err))
+		}
+	}
+
 	if meta.Issue == nil {
 		return activity, nil
 	}
@@ -402,14 +414,11 @@ func (m *Manager) CreateActivity(ctx context.Context, create *store.ActivityMess
 		}
 	}
 
-	webhookList, err := m.store.FindProjectWebhookV2(ctx, &store.FindProjectWebhookMessage{
-		ProjectID:    &meta.Issue.Project.UID,
-		ActivityType: &create.Type,
-	})
+	deliveries, err := m.listWebhookDeliveries(ctx, &meta.Issue.Project.UID, create.Type)
 	if err != nil {
-		return nil, errors.Wrapf(err, "failed to find project webhook after changing the issue status: %v", meta.Issue.Title)
+		return nil, errors.Wrapf(err, "failed to find webhooks after changing the issue status: %v", meta.Issue.Title)
 	}
-	if len(webhookList) == 0 {
+	if len(deliveries) == 0 {
 		return activity, nil
 	}
 
@@ -428,34 +437,163 @@ func (m *Manager) CreateActivity(ctx context.Context, create *store.ActivityMess
 			log.BBError(err))
 		return activity, nil
 	}
-	// Call external webhook endpoint in Go routine to avoid blocking web serving thread.
-	go postWebhookList(ctx, webhookCtx, webhookList)
+	// The webhooks are posted by the delivery runner to avoid blocking web serving thread.
+	if err := m.postWebhookList(ctx, webhookCtx, deliveries); err != nil {
+		return nil, err
+	}
 
 	return activity, nil
 }
 
-func postWebhookList(ctx context.Context, webhookCtx *webhook.Context, webhookList []*store.ProjectWebhookMessage) {
-	for _, hook := range webhookList {
-		webhookCtx := *webhookCtx
-		webhookCtx.URL = hook.URL
-		webhookCtx.CreatedTs = time.Now().Unix()
-		go func(webhookCtx *webhook.Context, hook *store.ProjectWebhookMessage) {
-			if err := common.Retry(ctx, func() error {
-				return webhook.Post(hook.Type, *webhookCtx)
-			}); err != nil {
-				// The external webhook endpoint might be invalid which is out of our code control, so we just emit a warning
-				slog.Warn("Failed to post webhook event on activity",
-					slog.String("webhook type", hook.Type),
-					slog.String("webhook name", hook.Title),
-					slog.String("activity type", webhookCtx.ActivityType),
-					slog.String("title", webhookCtx.Title),
-					log.BBError(err))
-				return
-			}
-		}(&webhookCtx, hook)
+// PostEvent posts the event that is not an issue activity to the workspace webhooks.
+// The event is also posted to the project webhooks if it belongs to a project.
+func (m *Manager) PostEvent(ctx context.Context, event *Event) {
+	project := event.Project
+	if project == nil && event.Database != nil {
+		p, err := m.store.GetProjectV2(ctx, &store.FindProjectMessage{ResourceID: &event.Database.ProjectID})
+		if err != nil {
+			slog.Warn("Failed to get project for the event",
+				slog.String("activity type", string(event.Type)),
+				slog.String("title", event.Title),
+				log.BBError(err))
+			return
+		}
+		project = p
+	}
+	var projectUID *int
+	if project != nil {
+		projectUID = &project.UID
+	}
+	deliveries, err := m.listWebhookDeliveries(ctx, projectUID, event.Type)
+	if err != nil {
+		slog.Warn("Failed to find webhooks for the event",
+			slog.String("activity type", string(event.Type)),
+			slog.String("title", event.Title),
+			log.BBError(err))
+		return
+	}
+	if len(deliveries) == 0 {
+		return
+	}
+
+	webhookCtx, err := m.getEventWebhookContext(ctx, event, project)
+	if err != nil {
+		slog.Warn("Failed to get webhook context",
+			slog.String("activity type", string(event.Type)),
+			slog.String("title", event.Title),
+			log.BBError(err))
+		return
+	}
+	if err := m.postWebhookList(ctx, webhookCtx, deliveries); err != nil {
+		slog.Warn("Failed to post webhooks for the event",
+			slog.String("activity type", string(event.Type)),
+			slog.String("title", event.Title),
+			log.BBError(err))
 	}
 }
 
+func (m *Manager) getEventWebhookContext(ctx context.Context, event *Event, project *store.ProjectMessage) (*webhook.Context, error) {
+	setting, err := m.store.GetWorkspaceGeneralSetting(ctx)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get workspace setting")
+	}
+	creator, err := m.store.GetUserByID(ctx, event.CreatorUID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get principal %d", event.CreatorUID)
+	}
+	if creator == nil {
+		return nil, errors.Errorf("principal %d not found", event.CreatorUID)
+	}
+
+	webhookCtx := &webhook.Context{
+		Level:        event.Level,
+		ActivityType: string(event.Type),
+		Title:        event.Title,
+		Description:  event.Description,
+		Link:         setting.ExternalUrl,
+		CreatorID:    creator.ID,
+		CreatorName:  creator.Name,
+		CreatorEmail: creator.Email,
+		Anomaly:      event.Anomaly,
+		Backup:       event.Backup,
+		SQLExport:    event.SQLExport,
+		Member:       event.Member,
+	}
+	if project != nil {
+		webhookCtx.Project = &webhook.Project{
+			ID:   project.UID,
+			Name: project.Title,
+		}
+	}
+	if event.Instance != nil {
+		webhookCtx.Instance = &webhook.Instance{
+			ID:          event.Instance.ResourceID,
+			Name:        event.Instance.Title,
+			Engine:      event.Instance.Engine.String(),
+			Environment: event.Instance.EnvironmentID,
+		}
+	}
+	if event.Database != nil {
+		webhookCtx.Database = &webhook.Database{
+			ID:   event.Database.UID,
+			Name: event.Database.DatabaseName,
+		}
+	}
+	return webhookCtx, nil
+}
+
+// listWebhookDeliveries returns the deliveries to the webhooks subscribing to the activity type,
+// including the workspace webhooks and the project webhooks if the project is specified.
+func (m *Manager) listWebhookDeliveries(ctx context.Context, projectUID *int, activityType api.ActivityType) ([]*store.WebhookDeliveryMessage, error) {
+	var deliveries []*store.WebhookDeliveryMessage
+	if projectUID != nil {
+		webhookList, err := m.store.FindProjectWebhookV2(ctx, &store.FindProjectWebhookMessage{
+			ProjectID:    projectUID,
+			ActivityType: &activityType,
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to find project webhooks")
+		}
+		for _, hook := range webhookList {
+			deliveries = append(deliveries, &store.WebhookDeliveryMessage{
+				ProjectWebhookID: hook.ID,
+				ActivityType:     activityType,
+			})
+		}
+	}
+	setting, err := m.store.GetWorkspaceWebhookSetting(ctx)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get workspace webhook setting")
+	}
+	for _, hook := range setting.Webhooks {
+		if !hook.Subscribe(activityType) {
+			continue
+		}
+		deliveries = append(deliveries, &store.WebhookDeliveryMessage{
+			WorkspaceWebhookID: hook.ID,
+			ActivityType:       activityType,
+		})
+	}
+	return deliveries, nil
+}
+
+// postWebhookList enqueues the webhook deliveries of the event.
+// The error is returned because the event is lost if the deliveries are not enqueued.
+func (m *Manager) postWebhookList(ctx context.Context, webhookCtx *webhook.Context, deliveries []*store.WebhookDeliveryMessage) error {
+	webhookCtx.CreatedTs = time.Now().Unix()
+	payload, err := json.Marshal(webhookCtx)
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal webhook context")
+	}
+	for _, delivery := range deliveries {
+		delivery.Payload = string(payload)
+	}
+	if err := m.store.CreateWebhookDeliveries(ctx, deliveries); err != nil {
+		return errors.Wrapf(err, "failed to create webhook deliveries for %q", webhookCtx.Title)
+	}
+	return nil
+}
+
 func (m *Manager) getWebhookContext(ctx context.Context, activity *store.ActivityMessage, meta *Metadata, updater *store.UserMessage) (*webhook.Context, error) {
 	var webhookCtx webhook.Context
 	var webhookTaskResult *webhook.TaskResult
@@ -775,38 +913,7 @@ func (m *Manager) getWebhookContext(ctx context.Context, activity *store.Activit
 			return nil, errors.Errorf("pending step nodes length is not 1, got %v", len(pendingStep.Nodes))
 		}
 
-		node := pendingStep.Nodes[0]
-
-		var usersGetter func(ctx context.Context) ([]*store.UserMessage, error)
-
-		switch val := node.Payload.(type) {
-		case *storepb.ApprovalNode_GroupValue_:
-			switch val.GroupValue {
-			case storepb.ApprovalNode_GROUP_VALUE_UNSPECIFILED:
-				return nil, errors.Errorf("invalid group value")
-			case storepb.ApprovalNode_WORKSPACE_OWNER:
-				usersGetter = getUsersFromWorkspaceRole(m.store, api.Owner)
-			case storepb.ApprovalNode_WORKSPACE_DBA:
-				usersGetter = getUsersFromWorkspaceRole(m.store, api.DBA)
-			case storepb.ApprovalNode_PROJECT_OWNER:
-				usersGetter = getUsersFromProjectRole(m.store, api.Owner, meta.Issue.Project.ResourceID)
-			case storepb.ApprovalNode_PROJECT_MEMBER:
-				usersGetter = getUsersFromProjectRole(m.store, api.Developer, meta.Issue.Project.ResourceID)
-			default:
-				return nil, errors.Errorf("invalid group value")
-			}
-		case *storepb.ApprovalNode_Role:
-			role := api.Role(strings.TrimPrefix(val.Role, "roles/"))
-			usersGetter = getUsersFromProjectRole(m.store, role, meta.Issue.Project.ResourceID)
-		case *storepb.ApprovalNode_ExternalNodeId:
-			usersGetter = func(ctx context.Context) ([]*store.UserMessage, error) {
-				return nil, nil
-			}
-		default:
-			return nil, errors.Errorf("invalid node payload type")
-		}
-
-		users, err := usersGetter(ctx)
+		users, err := listApprovalNodeUsers(ctx, m.store, pendingStep.Nodes[0], meta.Issue.Project.ResourceID)
 		if err != nil {
 			slog.Warn("Failed to post webhook event after changing the issue approval node status, failed to get users",
 				slog.String("issue_name", meta.Issue.Title),
@@ -824,6 +931,19 @@ func (m *Manager) getWebhookContext(ctx context.Context, activity *store.Activit
 			phone := strconv.FormatInt(int64(*phoneNumber.NationalNumber), 10)
 			webhookApproval.MentionUsersByPhone = append(webhookApproval.MentionUsersByPhone, phone)
 		}
+		action, err := m.getApprovalAction(ctx, meta.Issue.UID, setting.ExternalUrl)
+		if err != nil {
+			// The approval request is still posted without the buttons.
+			slog.Warn("Failed to get the interactive approval action",
+				slog.String("issue_name", meta.Issue.Title),
+				log.BBError(err))
+		}
+		webhookApproval.Action = action
+	case api.ActivityProjectMemberExpiring:
+		level = webhook.WebhookWarn
+		title = "Granted role expiring - " + meta.Issue.Title
+	case api.ActivityProjectMemberDelete:
+		title = "Granted role revoked - " + meta.Issue.Title
 	}
 
 	webhookCtx = webhook.Context{
@@ -852,6 +972,38 @@ func (m *Manager) getWebhookContext(ctx context.Context, activity *store.Activit
 	return &webhookCtx, nil
 }
 
+// getApprovalAction returns the Approve/Reject action of the pending approval step of the issue.
+// It returns nil if the interactive approval is enabled for neither Slack nor Teams.
+func (m *Manager) getApprovalAction(ctx context.Context, issueUID int, externalURL string) (*webhook.ApprovalAction, error) {
+	imSetting, err := m.store.GetAppIMApprovalSetting(ctx)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get IM setting")
+	}
+	if imSetting == nil || (!imSetting.Slack.Enabled && !imSetting.Teams.Enabled) {
+		return nil, nil
+	}
+	// Get the latest issue for the approvers, the issue in the metadata might be fetched before the approval.
+	issue, err := m.store.GetIssueV2(ctx, &store.FindIssueMessage{UID: &issueUID})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get issue %d", issueUID)
+	}
+	if issue == nil {
+		return nil, errors.Errorf("issue %d not found", issueUID)
+	}
+	step := len(issue.Payload.GetApproval().GetApprovers())
+	action := &webhook.ApprovalAction{
+		IssueID: issueUID,
+		Step:    step,
+		Slack:   imSetting.Slack.Enabled,
+	}
+	// Teams posts the actions to the target URL in the card, so the external URL is required.
+	if imSetting.Teams.Enabled && externalURL != "" {
+		action.TeamsURL = fmt.Sprintf("%s/hook/im-approval/teams", externalURL)
+		action.TeamsSignature = webhook.SignApprovalAction(imSetting.Teams.Token, issueUID, step)
+	}
+	return action, nil
+}
+
 func (m *Manager) postInboxIssueActivity(ctx context.Context, issue *store.IssueMessage, activityID int) error {
 	if issue.Creator.ID != api.SystemBotID {
 		if _, err := m.store.CreateInbox(ctx, &store.InboxMessage{
@@ -914,6 +1066,40 @@ func shouldPostInbox(activity *store.ActivityMessage, createType api.ActivityTyp
 	return false, nil
 }
 
+// listApprovalNodeUsers lists the users who can approve the approval node.
+func listApprovalNodeUsers(ctx context.Context, s *store.Store, node *storepb.ApprovalNode, projectID string) ([]*store.UserMessage, error) {
+	var usersGetter func(ctx context.Context) ([]*store.UserMessage, error)
+
+	switch val := node.Payload.(type) {
+	case *storepb.ApprovalNode_GroupValue_:
+		switch val.GroupValue {
+		case storepb.ApprovalNode_GROUP_VALUE_UNSPECIFILED:
+			return nil, errors.Errorf("invalid group value")
+		case storepb.ApprovalNode_WORKSPACE_OWNER:
+			usersGetter = getUsersFromWorkspaceRole(s, api.Owner)
+		case storepb.ApprovalNode_WORKSPACE_DBA:
+			usersGetter = getUsersFromWorkspaceRole(s, api.DBA)
+		case storepb.ApprovalNode_PROJECT_OWNER:
+			usersGetter = getUsersFromProjectRole(s, api.Owner, projectID)
+		case storepb.ApprovalNode_PROJECT_MEMBER:
+			usersGetter = getUsersFromProjectRole(s, api.Developer, projectID)
+		default:
+			return nil, errors.Errorf("invalid group value")
+		}
+	case *storepb.ApprovalNode_Role:
+		role := api.Role(strings.TrimPrefix(val.Role, "roles/"))
+		usersGetter = getUsersFromProjectRole(s, role, projectID)
+	case *storepb.ApprovalNode_ExternalNodeId:
+		usersGetter = func(ctx context.Context) ([]*store.UserMessage, error) {
+			return nil, nil
+		}
+	default:
+		return nil, errors.Errorf("invalid node payload type")
+	}
+
+	return usersGetter(ctx)
+}
+
 func getUsersFromWorkspaceRole(s *store.Store, role api.Role) func(context.Context) ([]*store.UserMessage, error) {
 	return func(ctx context.Context) ([]*store.UserMessage, error) {
 		return s.ListUsers(ctx, &store.FindUserMessage{
@@ -2,6 +2,7 @@
 package config
 
 import (
+	"net/netip"
 	"time"
 
 	"github.com/bytebase/bytebase/backend/common"
@@ -32,6 +33,17 @@ type Profile struct {
 	Readonly bool
 	// When we are running in SaaS mode, some features are not allowed to edit by users.
 	SaaS bool
+	// HA is whether to run in the high-availability mode, where multiple replicas share the same external metadata database
+	// and only the elected leader runs the background runners.
+	HA bool
+	// UninstallHATriggers is whether to uninstall the cache invalidation triggers installed by the high-availability mode on startup.
+	UninstallHATriggers bool
+	// TrustedProxies is the addresses of the reverse proxies in front of Bytebase, the X-Forwarded-For header
+	// is only trusted if the request comes from them.
+	TrustedProxies []netip.Prefix
+	// ActivityLedgerKeyFile is the file of the key for the activity ledger hash chain.
+	// The key is generated in the DataDir if it's empty.
+	ActivityLedgerKeyFile string
 	// DataDir is the directory stores the data including Bytebase's own database, backups, etc.
 	DataDir string
 	// ResourceDir is the directory stores the resources including embedded postgres, mysqlutil, mongoutil and etc.
@@ -0,0 +1,169 @@
+// Package pgosc implements the online schema change for PostgreSQL with the shadow table strategy.
+package pgosc
+
+import (
+	"strconv"
+	"time"
+
+	pgquery "github.com/pganalyze/pg_query_go/v4"
+	"github.com/pkg/errors"
+)
+
+var defaultConfig = struct {
+	chunkSize                 int64
+	niceRatio                 float64
+	maxLagMillis              int64
+	cutoverLockTimeoutSeconds int64
+	cutoverRetries            int
+}{
+	chunkSize:                 1000, // chunk-size
+	niceRatio:                 0,    // nice-ratio
+	maxLagMillis:              1500, // max-lag-millis
+	cutoverLockTimeoutSeconds: 3,    // cut-over-lock-timeout-seconds
+	cutoverRetries:            60,
+}
+
+// UserFlags is the user specified flags of the online schema change.
+type UserFlags struct {
+	chunkSize                 *int64
+	niceRatio                 *float64
+	maxLagMillis              *int64
+	cutoverLockTimeoutSeconds *int64
+}
+
+var knownKeys = map[string]bool{
+	"chunk-size":                    true,
+	"nice-ratio":                    true,
+	"max-lag-millis":                true,
+	"cut-over-lock-timeout-seconds": true,
+}
+
+// GetUserFlags parses and validates the user specified flags.
+func GetUserFlags(flags map[string]string) (*UserFlags, error) {
+	f := &UserFlags{}
+	if flags == nil {
+		return f, nil
+	}
+
+	for k := range flags {
+		if !knownKeys[k] {
+			return nil, errors.Errorf("unsupported flag: %s", k)
+		}
+	}
+
+	if v, ok := flags["chunk-size"]; ok {
+		chunkSize, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to convert chunk-size %q to int", v)
+		}
+		if chunkSize <= 0 {
+			return nil, errors.Errorf("chunk-size must be positive, got %d", chunkSize)
+		}
+		f.chunkSize = &chunkSize
+	}
+	if v, ok := flags["nice-ratio"]; ok {
+		niceRatio, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to convert nice-ratio %q to float", v)
+		}
+		if niceRatio < 0 {
+			return nil, errors.Errorf("nice-ratio must not be negative, got %v", niceRatio)
+		}
+		f.niceRatio = &niceRatio
+	}
+	if v, ok := flags["max-lag-millis"]; ok {
+		maxLagMillis, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to convert max-lag-millis %q to int", v)
+		}
+		if maxLagMillis <= 0 {
+			return nil, errors.Errorf("max-lag-millis must be positive, got %d", maxLagMillis)
+		}
+		f.maxLagMillis = &maxLagMillis
+	}
+	if v, ok := flags["cut-over-lock-timeout-seconds"]; ok {
+		cutoverLockTimeoutSeconds, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to convert cut-over-lock-timeout-seconds %q to int", v)
+		}
+		if cutoverLockTimeoutSeconds <= 0 {
+			return nil, errors.Errorf("cut-over-lock-timeout-seconds must be positive, got %d", cutoverLockTimeoutSeconds)
+		}
+		f.cutoverLockTimeoutSeconds = &cutoverLockTimeoutSeconds
+	}
+	return f, nil
+}
+
+type config struct {
+	chunkSize          int64
+	niceRatio          float64
+	maxLag             time.Duration
+	cutoverLockTimeout time.Duration
+	cutoverRetries     int
+}
+
+func newConfig(flags map[string]string) (*config, error) {
+	userFlags, err := GetUserFlags(flags)
+	if err != nil {
+		return nil, err
+	}
+	c := &config{
+		chunkSize:          defaultConfig.chunkSize,
+		niceRatio:          defaultConfig.niceRatio,
+		maxLag:             time.Duration(defaultConfig.maxLagMillis) * time.Millisecond,
+		cutoverLockTimeout: time.Duration(defaultConfig.cutoverLockTimeoutSeconds) * time.Second,
+		cutoverRetries:     defaultConfig.cutoverRetries,
+	}
+	if v := userFlags.chunkSize; v != nil {
+		c.chunkSize = *v
+	}
+	if v := userFlags.niceRatio; v != nil {
+		c.niceRatio = *v
+	}
+	if v := userFlags.maxLagMillis; v != nil {
+		c.maxLag = time.Duration(*v) * time.Millisecond
+	}
+	if v := userFlags.cutoverLockTimeoutSeconds; v != nil {
+		c.cutoverLockTimeout = time.Duration(*v) * time.Second
+	}
+	return c, nil
+}
+
+// GetTableFromStatement returns the schema name and the table name altered by the statement.
+// The schema name is empty if the table is not schema qualified.
+// The statement must be a single ALTER TABLE statement.
+func GetTableFromStatement(statement string) (string, string, error) {
+	alterStmt, err := parseAlterTableStatement(statement)
+	if err != nil {
+		return "", "", err
+	}
+	return alterStmt.Relation.Schemaname, alterStmt.Relation.Relname, nil
+}
+
+func parseAlterTableStatement(statement string) (*pgquery.AlterTableStmt, error) {
+	tree, err := pgquery.Parse(statement)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse statement")
+	}
+	if len(tree.Stmts) != 1 {
+		return nil, errors.Errorf("online schema change requires exactly one statement, got %d", len(tree.Stmts))
+	}
+	alterStmt := tree.Stmts[0].Stmt.GetAlterTableStmt()
+	if alterStmt == nil || alterStmt.Objtype != pgquery.ObjectType_OBJECT_TABLE || alterStmt.Relation == nil {
+		return nil, errors.Errorf("online schema change only supports the ALTER TABLE statement")
+	}
+	return alterStmt, nil
+}
+
+// rewriteAlterTableStatement rewrites the ALTER TABLE statement to alter the given table instead.
+func rewriteAlterTableStatement(statement string, schemaName string, tableName string) (string, error) {
+	alterStmt, err := parseAlterTableStatement(statement)
+	if err != nil {
+		return "", err
+	}
+	alterStmt.Relation.Schemaname = schemaName
+	alterStmt.Relation.Relname = tableName
+	return pgquery.Deparse(&pgquery.ParseResult{
+		Stmts: []*pgquery.RawStmt{{Stmt: &pgquery.Node{Node: &pgquery.Node_AlterTableStmt{AlterTableStmt: alterStmt}}}},
+	})
+}
@@ -0,0 +1,112 @@
+package pgosc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewConfig(t *testing.T) {
+	a := require.New(t)
+
+	c, err := newConfig(nil)
+	a.NoError(err)
+	a.Equal(&config{
+		chunkSize:          1000,
+		niceRatio:          0,
+		maxLag:             1500 * time.Millisecond,
+		cutoverLockTimeout: 3 * time.Second,
+		cutoverRetries:     60,
+	}, c)
+
+	c, err = newConfig(map[string]string{
+		"chunk-size":                    "500",
+		"nice-ratio":                    "0.5",
+		"max-lag-millis":                "200",
+		"cut-over-lock-timeout-seconds": "10",
+	})
+	a.NoError(err)
+	a.Equal(&config{
+		chunkSize:          500,
+		niceRatio:          0.5,
+		maxLag:             200 * time.Millisecond,
+		cutoverLockTimeout: 10 * time.Second,
+		cutoverRetries:     60,
+	}, c)
+}
+
+func TestGetUserFlagsError(t *testing.T) {
+	tests := []map[string]string{
+		{"unknown": "1"},
+		{"chunk-size": "abc"},
+		{"chunk-size": "0"},
+		{"nice-ratio": "abc"},
+		{"nice-ratio": "-1"},
+		{"max-lag-millis": "abc"},
+		{"max-lag-millis": "0"},
+		{"max-lag-millis": "-100"},
+		{"cut-over-lock-timeout-seconds": "abc"},
+		{"cut-over-lock-timeout-seconds": "0"},
+	}
+	a := require.New(t)
+	for _, flags := range tests {
+		_, err := GetUserFlags(flags)
+		a.Error(err, flags)
+	}
+}
+
+func TestGetTableFromStatement(t *testing.T) {
+	tests := []struct {
+		statement string
+		schema    string
+		table     string
+		wantErr   bool
+	}{
+		{statement: "ALTER TABLE t ADD COLUMN c int", table: "t"},
+		{statement: `ALTER TABLE "S"."T" ADD COLUMN c int;`, schema: "S", table: "T"},
+		{statement: "ALTER TABLE t ADD COLUMN c int; ALTER TABLE t ADD COLUMN d int", wantErr: true},
+		{statement: "CREATE TABLE t (id int)", wantErr: true},
+		{statement: "ALTER INDEX i RENAME TO j", wantErr: true},
+		{statement: "ALTER TABLE", wantErr: true},
+	}
+	a := require.New(t)
+	for _, test := range tests {
+		schema, table, err := GetTableFromStatement(test.statement)
+		if test.wantErr {
+			a.Error(err, test.statement)
+			continue
+		}
+		a.NoError(err, test.statement)
+		a.Equal(test.schema, schema, test.statement)
+		a.Equal(test.table, table, test.statement)
+	}
+}
+
+func TestRewriteAlterTableStatement(t *testing.T) {
+	tests := []struct {
+		statement string
+		schema    string
+		table     string
+		want      string
+	}{
+		{
+			statement: "ALTER TABLE t ADD COLUMN c int NOT NULL DEFAULT 0",
+			schema:    "public",
+			table:     "_t_gho",
+			want:      "ALTER TABLE public._t_gho ADD COLUMN c int NOT NULL DEFAULT 0",
+		},
+		{
+			statement: `ALTER TABLE "S"."T" ALTER COLUMN c TYPE bigint, DROP COLUMN d;`,
+			schema:    "S",
+			table:     "_T_gho",
+			want:      `ALTER TABLE "S"."_T_gho" ALTER COLUMN c TYPE bigint, DROP d`,
+		},
+	}
+	a := require.New(t)
+	for _, test := range tests {
+		got, err := rewriteAlterTableStatement(test.statement, test.schema, test.table)
+		a.NoError(err, test.statement)
+		a.Equal(test.want, got, test.statement)
+	}
+}
@@ -0,0 +1,1040 @@
+package pgosc
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/backend/common/log"
+)
+
+const (
+	// maxIdentifierLength is the maximum identifier length of PostgreSQL.
+	maxIdentifierLength = 63
+	// lockNotAvailableCode is the SQLSTATE of failing to acquire the lock within the lock_timeout.
+	lockNotAvailableCode = "55P03"
+)
+
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// Migration is the online schema change of a PostgreSQL table.
+//
+// It works as follows:
+//  1. Create the shadow table like the original table and apply the ALTER TABLE statement to it.
+//  2. Create the triggers on the original table to record the row changes into the changelog table.
+//  3. Copy the rows from the original table to the shadow table in chunks ordered by the primary key,
+//     and replay the recorded row changes between chunks.
+//  4. Keep replaying the row changes until the cutover is requested.
+//  5. Lock the original table, replay the remaining row changes and swap the tables by renaming.
+//     The indexes and the constraints named after the shadow table are renamed after the original table.
+//
+// The progress is persisted in the "_<table>_ghs" state table of the database, and the triggers keep recording
+// the row changes, so a migration of the same statement resumes from where it left off after Bytebase restarts.
+//
+// The original table is kept as the "_<table>_del" table after the cutover, it's up to the user to drop it.
+// The tables with foreign keys, dependent views, user triggers or inheritance are not supported
+// because these objects cannot be moved to the shadow table transparently.
+type Migration struct {
+	db        *sql.DB
+	statement string
+	config    *config
+
+	schemaName string
+	tableName  string
+	// table, shadowTable, changelogTable, changelogFunction and stateTable are quoted and schema qualified.
+	table             string
+	shadowTable       string
+	changelogTable    string
+	changelogFunction string
+	stateTable        string
+	// oldTableName, rowTrigger and truncateTrigger are quoted.
+	oldTableName    string
+	rowTrigger      string
+	truncateTrigger string
+
+	primaryKeys []string
+	// columns are the columns copied from the original table to the shadow table.
+	columns []string
+
+	rowsEstimate int64
+	rowsCopied   int64
+	synced       atomic.Bool
+
+	cutoverOnce sync.Once
+	cutoverCh   chan struct{}
+	doneCh      chan struct{}
+}
+
+// NewMigration creates the online schema change for the ALTER TABLE statement.
+// The db must connect to the database of the altered table.
+func NewMigration(ctx context.Context, db *sql.DB, statement string, flags map[string]string) (*Migration, error) {
+	config, err := newConfig(flags)
+	if err != nil {
+		return nil, err
+	}
+	schemaName, tableName, err := GetTableFromStatement(statement)
+	if err != nil {
+		return nil, err
+	}
+	table := pgx.Identifier{tableName}
+	if schemaName != "" {
+		table = pgx.Identifier{schemaName, tableName}
+	}
+
+	var relkind string
+	var rowsEstimate int64
+	if err := db.QueryRowContext(ctx, `
+		SELECT n.nspname, c.relname, c.relkind, GREATEST(c.reltuples, 0)::bigint
+		FROM pg_class c JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE c.oid = $1::regclass`, table.Sanitize()).Scan(&schemaName, &tableName, &relkind, &rowsEstimate); err != nil {
+		return nil, errors.Wrapf(err, "failed to find table %s", table.Sanitize())
+	}
+	if relkind != "r" {
+		return nil, errors.Errorf("online schema change only supports ordinary tables, %q is not", tableName)
+	}
+	// The suffixes follow gh-ost, "_gho" for the shadow table, "_ghc" for the changelog and "_del" for the old table.
+	if len("_"+tableName+"_ghc_truncate") > maxIdentifierLength {
+		return nil, errors.Errorf("table name %q is too long for online schema change", tableName)
+	}
+
+	return &Migration{
+		db:                db,
+		statement:         statement,
+		config:            config,
+		schemaName:        schemaName,
+		tableName:         tableName,
+		table:             pgx.Identifier{schemaName, tableName}.Sanitize(),
+		shadowTable:       pgx.Identifier{schemaName, "_" + tableName + "_gho"}.Sanitize(),
+		changelogTable:    pgx.Identifier{schemaName, "_" + tableName + "_ghc"}.Sanitize(),
+		changelogFunction: pgx.Identifier{schemaName, "_" + tableName + "_ghc"}.Sanitize(),
+		stateTable:        pgx.Identifier{schemaName, "_" + tableName + "_ghs"}.Sanitize(),
+		oldTableName:      pgx.Identifier{"_" + tableName + "_del"}.Sanitize(),
+		rowTrigger:        pgx.Identifier{"_" + tableName + "_ghc"}.Sanitize(),
+		truncateTrigger:   pgx.Identifier{"_" + tableName + "_ghc_truncate"}.Sanitize(),
+		rowsEstimate:      rowsEstimate,
+		cutoverCh:         make(chan struct{}),
+		doneCh:            make(chan struct{}),
+	}, nil
+}
+
+// GetRowsEstimate returns the estimated row count of the original table.
+func (m *Migration) GetRowsEstimate() int64 {
+	return atomic.LoadInt64(&m.rowsEstimate)
+}
+
+// GetRowsCopied returns the number of rows copied to the shadow table.
+func (m *Migration) GetRowsCopied() int64 {
+	return atomic.LoadInt64(&m.rowsCopied)
+}
+
+// IsSynced returns true if all rows are copied and the row changes are caught up within max-lag-millis.
+func (m *Migration) IsSynced() bool {
+	return m.synced.Load()
+}
+
+// GetMaxLag returns the maximum lag allowed for the cutover.
+func (m *Migration) GetMaxLag() time.Duration {
+	return m.config.maxLag
+}
+
+// GetLag returns the age of the oldest row change not replayed yet.
+func (m *Migration) GetLag(ctx context.Context) (time.Duration, error) {
+	var lagMillis int64
+	if err := m.db.QueryRowContext(ctx, fmt.Sprintf(`
+		SELECT COALESCE((EXTRACT(EPOCH FROM clock_timestamp() - min(created_at)) * 1000)::bigint, 0) FROM %s`, m.changelogTable)).Scan(&lagMillis); err != nil {
+		return 0, errors.Wrap(err, "failed to get the changelog lag")
+	}
+	return time.Duration(lagMillis) * time.Millisecond, nil
+}
+
+// Cutover requests the cutover. The result is returned by Run.
+func (m *Migration) Cutover() {
+	m.cutoverOnce.Do(func() {
+		close(m.cutoverCh)
+	})
+}
+
+// Done returns a channel that's closed when Run returns.
+func (m *Migration) Done() <-chan struct{} {
+	return m.doneCh
+}
+
+// IsResumable returns true if the migration of the statement is persisted in the state table, e.g. it's prepared
+// before Bytebase restarts, so Run resumes it.
+func (m *Migration) IsResumable(ctx context.Context) (bool, error) {
+	state, err := m.loadState(ctx)
+	if err != nil {
+		return false, err
+	}
+	return state != nil && state.statementHash == m.statementHash(), nil
+}
+
+// Run runs the online schema change until the cutover is done, or resumes the one persisted in the state table.
+// The shadow table and the changelog are dropped if it fails before the cutover.
+func (m *Migration) Run(ctx context.Context) (err error) {
+	defer close(m.doneCh)
+
+	state, err := m.loadState(ctx)
+	if err != nil {
+		return err
+	}
+	if state != nil && state.statementHash != m.statementHash() {
+		return errors.Errorf("another online schema change of table %s is in progress", m.table)
+	}
+	if state != nil && state.cutOver {
+		// The cutover was committed before the restart.
+		m.dropChangelog(ctx)
+		return nil
+	}
+	defer func() {
+		if err == nil {
+			return
+		}
+		// Use a new context because ctx may be canceled.
+		if cleanupErr := m.cleanup(context.Background()); cleanupErr != nil {
+			slog.Warn("failed to clean up the online schema change", slog.String("table", m.table), log.BBError(cleanupErr))
+		}
+	}()
+
+	if state == nil {
+		// Drop the objects left by the preparation interrupted before persisting the state.
+		if err := m.cleanup(ctx); err != nil {
+			return errors.Wrapf(err, "failed to clean up the online schema change of table %s", m.table)
+		}
+		if err := m.prepare(ctx); err != nil {
+			return err
+		}
+		state = &migrationState{}
+	} else {
+		slog.Info("resuming the online schema change", slog.String("table", m.table), slog.Int64("rowsCopied", state.rowsCopied))
+		if err := m.load(ctx); err != nil {
+			return err
+		}
+		atomic.StoreInt64(&m.rowsCopied, state.rowsCopied)
+	}
+	if !state.copied {
+		if err := m.copyRows(ctx, state); err != nil {
+			return err
+		}
+	}
+
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		if err := m.replay(ctx, m.db); err != nil {
+			return err
+		}
+		if !m.synced.Load() {
+			lag, err := m.GetLag(ctx)
+			if err != nil {
+				return err
+			}
+			if lag <= m.config.maxLag {
+				m.synced.Store(true)
+			}
+		}
+		select {
+		case <-m.cutoverCh:
+			return m.cutover(ctx)
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// DryRun validates the online schema change without changing the data.
+func (m *Migration) DryRun(ctx context.Context) error {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if err := m.validateTable(ctx, tx); err != nil {
+		return err
+	}
+	return m.createShadowTable(ctx, tx)
+}
+
+func (m *Migration) validateTable(ctx context.Context, q querier) error {
+	primaryKeys, err := getPrimaryKeys(ctx, q, m.table)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get the primary key of table %s", m.table)
+	}
+	if len(primaryKeys) == 0 {
+		return errors.Errorf("online schema change requires table %s to have a primary key", m.table)
+	}
+	m.primaryKeys = primaryKeys
+
+	checks := []struct {
+		query   string
+		message string
+	}{
+		{
+			query:   `SELECT conname FROM pg_constraint WHERE contype = 'f' AND (conrelid = $1::regclass OR confrelid = $1::regclass) LIMIT 1`,
+			message: "foreign key",
+		},
+		{
+			query: `
+				SELECT r.ev_class::regclass::text FROM pg_depend d JOIN pg_rewrite r ON r.oid = d.objid
+				WHERE d.classid = 'pg_rewrite'::regclass AND d.refobjid = $1::regclass AND r.ev_class <> $1::regclass LIMIT 1`,
+			message: "dependent view",
+		},
+		{
+			query:   `SELECT tgname FROM pg_trigger WHERE tgrelid = $1::regclass AND NOT tgisinternal LIMIT 1`,
+			message: "trigger",
+		},
+		{
+			query:   `SELECT inhrelid::regclass::text FROM pg_inherits WHERE inhrelid = $1::regclass OR inhparent = $1::regclass LIMIT 1`,
+			message: "inheritance",
+		},
+	}
+	for _, check := range checks {
+		var name string
+		if err := q.QueryRowContext(ctx, check.query, m.table).Scan(&name); err != nil {
+			if err == sql.ErrNoRows {
+				continue
+			}
+			return errors.Wrapf(err, "failed to check %s of table %s", check.message, m.table)
+		}
+		return errors.Errorf("online schema change does not support table %s with %s %q", m.table, check.message, name)
+	}
+	return nil
+}
+
+func (m *Migration) createShadowTable(ctx context.Context, q querier) error {
+	if _, err := q.ExecContext(ctx, fmt.Sprintf("CREATE TABLE %s (LIKE %s INCLUDING ALL)", m.shadowTable, m.table)); err != nil {
+		return errors.Wrapf(err, "failed to create shadow table %s", m.shadowTable)
+	}
+	alterStatement, err := rewriteAlterTableStatement(m.statement, m.schemaName, "_"+m.tableName+"_gho")
+	if err != nil {
+		return err
+	}
+	if _, err := q.ExecContext(ctx, alterStatement); err != nil {
+		return errors.Wrapf(err, "failed to alter shadow table %s", m.shadowTable)
+	}
+
+	shadowPrimaryKeys, err := getPrimaryKeys(ctx, q, m.shadowTable)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get the primary key of shadow table %s", m.shadowTable)
+	}
+	if strings.Join(shadowPrimaryKeys, ",") != strings.Join(m.primaryKeys, ",") {
+		return errors.Errorf("online schema change does not support changing the primary key")
+	}
+	return m.loadColumns(ctx, q)
+}
+
+// load loads the primary keys and the columns of the migration resumed from the state table.
+func (m *Migration) load(ctx context.Context) error {
+	primaryKeys, err := getPrimaryKeys(ctx, m.db, m.table)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get the primary key of table %s", m.table)
+	}
+	if len(primaryKeys) == 0 {
+		return errors.Errorf("online schema change requires table %s to have a primary key", m.table)
+	}
+	m.primaryKeys = primaryKeys
+	return m.loadColumns(ctx, m.db)
+}
+
+func (m *Migration) loadColumns(ctx context.Context, q querier) error {
+	// Copy the columns existing in both tables, the generated columns are computed by the shadow table.
+	rows, err := q.QueryContext(ctx, `
+		SELECT a.attname FROM pg_attribute a
+		WHERE a.attrelid = $1::regclass AND a.attnum > 0 AND NOT a.attisdropped AND a.attgenerated = ''
+			AND EXISTS (
+				SELECT 1 FROM pg_attribute o
+				WHERE o.attrelid = $2::regclass AND o.attname = a.attname AND o.attnum > 0 AND NOT o.attisdropped AND o.attgenerated = ''
+			)
+		ORDER BY a.attnum`, m.shadowTable, m.table)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get the columns of shadow table %s", m.shadowTable)
+	}
+	defer rows.Close()
+	m.columns = nil
+	for rows.Next() {
+		var column string
+		if err := rows.Scan(&column); err != nil {
+			return err
+		}
+		m.columns = append(m.columns, column)
+	}
+	return rows.Err()
+}
+
+// prepare creates the shadow table and starts recording the row changes of the original table.
+func (m *Migration) prepare(ctx context.Context) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if err := m.validateTable(ctx, conn); err != nil {
+		return err
+	}
+	if err := m.createShadowTable(ctx, conn); err != nil {
+		return err
+	}
+	if err := m.copyPrivileges(ctx, conn); err != nil {
+		return err
+	}
+
+	if _, err := conn.ExecContext(ctx, fmt.Sprintf(`
+		CREATE TABLE %s (
+			id bigserial PRIMARY KEY,
+			op "char" NOT NULL,
+			old_row jsonb,
+			new_row jsonb,
+			created_at timestamptz NOT NULL DEFAULT clock_timestamp()
+		)`, m.changelogTable)); err != nil {
+		return errors.Wrapf(err, "failed to create changelog table %s", m.changelogTable)
+	}
+	if _, err := conn.ExecContext(ctx, fmt.Sprintf(`
+		CREATE FUNCTION %s() RETURNS trigger LANGUAGE plpgsql AS $bb$
+		BEGIN
+			IF TG_OP = 'INSERT' THEN
+				INSERT INTO %s (op, new_row) VALUES ('I', to_jsonb(NEW));
+			ELSIF TG_OP = 'UPDATE' THEN
+				INSERT INTO %s (op, old_row, new_row) VALUES ('U', to_jsonb(OLD), to_jsonb(NEW));
+			ELSIF TG_OP = 'DELETE' THEN
+				INSERT INTO %s (op, old_row) VALUES ('D', to_jsonb(OLD));
+			ELSE
+				INSERT INTO %s (op) VALUES ('T');
+			END IF;
+			RETURN NULL;
+		END
+		$bb$`, m.changelogFunction, m.changelogTable, m.changelogTable, m.changelogTable, m.changelogTable)); err != nil {
+		return errors.Wrapf(err, "failed to create changelog function %s", m.changelogFunction)
+	}
+
+	// Creating triggers waits for the running writes on the original table, so limit the lock waiting time.
+	if _, err := conn.ExecContext(ctx, fmt.Sprintf("SET lock_timeout = %d", m.config.cutoverLockTimeout.Milliseconds())); err != nil {
+		return err
+	}
+	defer func() {
+		_, _ = conn.ExecContext(context.Background(), "RESET lock_timeout")
+	}()
+	if _, err := conn.ExecContext(ctx, fmt.Sprintf(`
+		CREATE TRIGGER %s AFTER INSERT OR UPDATE OR DELETE ON %s FOR EACH ROW EXECUTE PROCEDURE %s();
+		CREATE TRIGGER %s AFTER TRUNCATE ON %s FOR EACH STATEMENT EXECUTE PROCEDURE %s();`,
+		m.rowTrigger, m.table, m.changelogFunction, m.truncateTrigger, m.table, m.changelogFunction)); err != nil {
+		return errors.Wrapf(err, "failed to create changelog triggers on table %s", m.table)
+	}
+
+	// The state is persisted last, so the migration is resumed only if it's fully prepared.
+	// The statements run in one implicit transaction.
+	if _, err := conn.ExecContext(ctx, fmt.Sprintf(`
+		CREATE TABLE %s (
+			statement_hash text NOT NULL,
+			last_key text,
+			rows_copied bigint NOT NULL DEFAULT 0,
+			copied boolean NOT NULL DEFAULT false,
+			cut_over boolean NOT NULL DEFAULT false
+		);
+		INSERT INTO %s (statement_hash) VALUES (%s);`, m.stateTable, m.stateTable, quoteLiteral(m.statementHash()))); err != nil {
+		return errors.Wrapf(err, "failed to create state table %s", m.stateTable)
+	}
+	return nil
+}
+
+// migrationState is the progress of the migration persisted in the state table.
+type migrationState struct {
+	// statementHash is the SHA-256 of the statement, the statement itself is not persisted because it may
+	// contain the rendered secrets.
+	statementHash string
+	lastKey       sql.NullString
+	rowsCopied    int64
+	copied        bool
+	cutOver       bool
+}
+
+// loadState returns the persisted state, or nil if the migration is not prepared.
+func (m *Migration) loadState(ctx context.Context) (*migrationState, error) {
+	var exists bool
+	if err := m.db.QueryRowContext(ctx, `SELECT to_regclass($1) IS NOT NULL`, m.stateTable).Scan(&exists); err != nil {
+		return nil, errors.Wrapf(err, "failed to find state table %s", m.stateTable)
+	}
+	if !exists {
+		return nil, nil
+	}
+	state := &migrationState{}
+	if err := m.db.QueryRowContext(ctx, fmt.Sprintf(`SELECT statement_hash, last_key, rows_copied, copied, cut_over FROM %s`, m.stateTable)).Scan(
+		&state.statementHash,
+		&state.lastKey,
+		&state.rowsCopied,
+		&state.copied,
+		&state.cutOver,
+	); err != nil {
+		return nil, errors.Wrapf(err, "failed to load the state of the online schema change from %s", m.stateTable)
+	}
+	return state, nil
+}
+
+func (m *Migration) statementHash() string {
+	hash := sha256.Sum256([]byte(m.statement))
+	return hex.EncodeToString(hash[:])
+}
+
+// copyPrivileges copies the owner and the privileges of the original table to the shadow table.
+func (m *Migration) copyPrivileges(ctx context.Context, q querier) error {
+	var owner string
+	if err := q.QueryRowContext(ctx, `SELECT pg_get_userbyid(relowner) FROM pg_class WHERE oid = $1::regclass`, m.table).Scan(&owner); err != nil {
+		return errors.Wrapf(err, "failed to get the owner of table %s", m.table)
+	}
+	if _, err := q.ExecContext(ctx, fmt.Sprintf("ALTER TABLE %s OWNER TO %s", m.shadowTable, pgx.Identifier{owner}.Sanitize())); err != nil {
+		return errors.Wrapf(err, "failed to set the owner of shadow table %s", m.shadowTable)
+	}
+
+	rows, err := q.QueryContext(ctx, `
+		SELECT CASE WHEN a.grantee = 0 THEN '' ELSE pg_get_userbyid(a.grantee) END, a.privilege_type, a.is_grantable
+		FROM pg_class c, aclexplode(c.relacl) a
+		WHERE c.oid = $1::regclass`, m.table)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get the privileges of table %s", m.table)
+	}
+	defer rows.Close()
+	var grants []string
+	for rows.Next() {
+		var grantee, privilege string
+		var grantable bool
+		if err := rows.Scan(&grantee, &privilege, &grantable); err != nil {
+			return err
+		}
+		if grantee == owner {
+			continue
+		}
+		role := "PUBLIC"
+		if grantee != "" {
+			role = pgx.Identifier{grantee}.Sanitize()
+		}
+		grant := fmt.Sprintf("GRANT %s ON %s TO %s", privilege, m.shadowTable, role)
+		if grantable {
+			grant += " WITH GRANT OPTION"
+		}
+		grants = append(grants, grant)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	for _, grant := range grants {
+		if _, err := q.ExecContext(ctx, grant); err != nil {
+			return errors.Wrapf(err, "failed to grant privileges on shadow table %s", m.shadowTable)
+		}
+	}
+	return nil
+}
+
+// copyRows copies the rows from the original table to the shadow table in chunks after the persisted last key,
+// and persists the progress after each chunk.
+func (m *Migration) copyRows(ctx context.Context, state *migrationState) error {
+	lastKey := state.lastKey
+	for {
+		start := time.Now()
+		var count int64
+		query := m.copyChunkQuery(lastKey.Valid)
+		var args []any
+		if lastKey.Valid {
+			args = append(args, lastKey.String)
+		}
+		if err := m.db.QueryRowContext(ctx, query, args...).Scan(&count, &lastKey); err != nil {
+			return errors.Wrapf(err, "failed to copy rows to shadow table %s", m.shadowTable)
+		}
+		if count == 0 {
+			if _, err := m.db.ExecContext(ctx, fmt.Sprintf("UPDATE %s SET copied = true", m.stateTable)); err != nil {
+				return errors.Wrapf(err, "failed to save the state to %s", m.stateTable)
+			}
+			return nil
+		}
+		atomic.AddInt64(&m.rowsCopied, count)
+		rowsCopied := atomic.LoadInt64(&m.rowsCopied)
+		if rowsCopied > atomic.LoadInt64(&m.rowsEstimate) {
+			atomic.StoreInt64(&m.rowsEstimate, rowsCopied)
+		}
+		// The chunk is copied again if it's not saved before the restart, which is fine because the copy skips
+		// the existing rows.
+		if _, err := m.db.ExecContext(ctx, fmt.Sprintf("UPDATE %s SET last_key = $1, rows_copied = $2", m.stateTable), lastKey, rowsCopied); err != nil {
+			return errors.Wrapf(err, "failed to save the state to %s", m.stateTable)
+		}
+
+		if err := m.replay(ctx, m.db); err != nil {
+			return err
+		}
+
+		if m.config.niceRatio > 0 {
+			select {
+			case <-time.After(time.Duration(float64(time.Since(start)) * m.config.niceRatio)):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+}
+
+// copyChunkQuery returns the query copying the next chunk of rows after the last primary key,
+// which returns the number of copied rows and the primary key of the last row in JSON.
+func (m *Migration) copyChunkQuery(hasLastKey bool) string {
+	var keys, keysDesc, lastKeys, keyObject []string
+	for _, column := range m.primaryKeys {
+		quoted := pgx.Identifier{column}.Sanitize()
+		keys = append(keys, "b."+quoted)
+		keysDesc = append(keysDesc, "b."+quoted+" DESC")
+		lastKeys = append(lastKeys, "o."+quoted)
+		keyObject = append(keyObject, fmt.Sprintf("%s, b.%s", quoteLiteral(column), quoted))
+	}
+	where := ""
+	if hasLastKey {
+		where = fmt.Sprintf("WHERE (%s) > (SELECT %s FROM jsonb_populate_record(NULL::%s, $1::jsonb) o)", strings.Join(keys, ", "), strings.Join(lastKeys, ", "), m.table)
+	}
+	columns := m.quotedColumns()
+	return fmt.Sprintf(`
+		WITH batch AS (
+			SELECT * FROM %s b %s ORDER BY %s LIMIT %d
+		), copied AS (
+			INSERT INTO %s (%s) OVERRIDING SYSTEM VALUE SELECT %s FROM batch ON CONFLICT (%s) DO NOTHING
+		)
+		SELECT (SELECT count(*) FROM batch), (SELECT jsonb_build_object(%s)::text FROM batch b ORDER BY %s LIMIT 1)`,
+		m.table, where, strings.Join(keys, ", "), m.config.chunkSize,
+		m.shadowTable, columns, columns, m.quotedPrimaryKeys(),
+		strings.Join(keyObject, ", "), strings.Join(keysDesc, ", "),
+	)
+}
+
+type rowChange struct {
+	id     int64
+	op     string
+	oldRow sql.NullString
+	newRow sql.NullString
+}
+
+// replay replays all recorded row changes to the shadow table.
+func (m *Migration) replay(ctx context.Context, q querier) error {
+	for {
+		var count int64
+		var err error
+		if tx, ok := q.(*sql.Tx); ok {
+			count, err = m.replayChunk(ctx, tx)
+		} else {
+			count, err = m.replayChunkInTx(ctx)
+		}
+		if err != nil {
+			return err
+		}
+		if count < m.config.chunkSize {
+			return nil
+		}
+	}
+}
+
+func (m *Migration) replayChunkInTx(ctx context.Context) (int64, error) {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+	count, err := m.replayChunk(ctx, tx)
+	if err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// replayChunk consumes a chunk of the recorded row changes and applies them to the shadow table in order.
+// Each change is applied by deleting the old and the new row by the primary key and inserting the new row,
+// so it's fine to apply a change to a row copied either before or after the change.
+func (m *Migration) replayChunk(ctx context.Context, tx *sql.Tx) (int64, error) {
+	rows, err := tx.QueryContext(ctx, fmt.Sprintf(`
+		DELETE FROM %s WHERE id IN (SELECT id FROM %s ORDER BY id LIMIT %d)
+		RETURNING id, op, old_row::text, new_row::text`, m.changelogTable, m.changelogTable, m.config.chunkSize))
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to fetch the changelog")
+	}
+	defer rows.Close()
+	var changes []*rowChange
+	for rows.Next() {
+		change := &rowChange{}
+		if err := rows.Scan(&change.id, &change.op, &change.oldRow, &change.newRow); err != nil {
+			return 0, err
+		}
+		changes = append(changes, change)
+	}
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].id < changes[j].id
+	})
+
+	var conditions []string
+	for _, column := range m.primaryKeys {
+		quoted := pgx.Identifier{column}.Sanitize()
+		conditions = append(conditions, fmt.Sprintf("t.%s = o.%s", quoted, quoted))
+	}
+	// The rows are recorded from the original table, so populate the records with the original table type.
+	deleteQuery := fmt.Sprintf("DELETE FROM %s t USING jsonb_populate_record(NULL::%s, $1::jsonb) o WHERE %s", m.shadowTable, m.table, strings.Join(conditions, " AND "))
+	columns := m.quotedColumns()
+	insertQuery := fmt.Sprintf("INSERT INTO %s (%s) OVERRIDING SYSTEM VALUE SELECT %s FROM jsonb_populate_record(NULL::%s, $1::jsonb)", m.shadowTable, columns, columns, m.table)
+
+	for _, change := range changes {
+		if change.op == "T" {
+			if _, err := tx.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s", m.shadowTable)); err != nil {
+				return 0, errors.Wrapf(err, "failed to replay truncate to shadow table %s", m.shadowTable)
+			}
+			continue
+		}
+		for _, row := range []sql.NullString{change.oldRow, change.newRow} {
+			if !row.Valid {
+				continue
+			}
+			if _, err := tx.ExecContext(ctx, deleteQuery, row.String); err != nil {
+				return 0, errors.Wrapf(err, "failed to replay row change %d to shadow table %s", change.id, m.shadowTable)
+			}
+		}
+		if change.newRow.Valid {
+			if _, err := tx.ExecContext(ctx, insertQuery, change.newRow.String); err != nil {
+				return 0, errors.Wrapf(err, "failed to replay row change %d to shadow table %s", change.id, m.shadowTable)
+			}
+		}
+	}
+	return int64(len(changes)), nil
+}
+
+// cutover swaps the original table and the shadow table.
+// It retries if failing to acquire the lock within cut-over-lock-timeout-seconds.
+func (m *Migration) cutover(ctx context.Context) error {
+	var err error
+	for i := 0; i < m.config.cutoverRetries; i++ {
+		if err = m.tryCutover(ctx); err == nil {
+			break
+		}
+		if !isLockNotAvailable(err) {
+			return err
+		}
+		slog.Warn("failed to lock the table for cutover, retrying", slog.String("table", m.table), log.BBError(err))
+		// Catch up the row changes made while waiting for the lock.
+		if err := m.replay(ctx, m.db); err != nil {
+			return err
+		}
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to cut over after %d attempts", m.config.cutoverRetries)
+	}
+
+	m.dropChangelog(ctx)
+	return nil
+}
+
+// dropChangelog drops the changelog and the state table after the cutover.
+// The cutover is done, so failing to drop them is not fatal.
+func (m *Migration) dropChangelog(ctx context.Context) {
+	if _, err := m.db.ExecContext(ctx, fmt.Sprintf("DROP TABLE IF EXISTS %s; DROP FUNCTION IF EXISTS %s(); DROP TABLE IF EXISTS %s;", m.changelogTable, m.changelogFunction, m.stateTable)); err != nil {
+		slog.Warn("failed to drop the changelog of the online schema change", slog.String("table", m.table), log.BBError(err))
+	}
+}
+
+func (m *Migration) tryCutover(ctx context.Context) error {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf("SET LOCAL lock_timeout = %d", m.config.cutoverLockTimeout.Milliseconds())); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf("LOCK TABLE %s IN ACCESS EXCLUSIVE MODE", m.table)); err != nil {
+		return errors.Wrapf(err, "failed to lock table %s", m.table)
+	}
+	if err := m.replay(ctx, tx); err != nil {
+		return err
+	}
+	if err := m.moveSequences(ctx, tx); err != nil {
+		return err
+	}
+	if err := m.renameShadowObjects(ctx, tx); err != nil {
+		return err
+	}
+	for _, statement := range []string{
+		fmt.Sprintf("DROP TRIGGER %s ON %s", m.rowTrigger, m.table),
+		fmt.Sprintf("DROP TRIGGER %s ON %s", m.truncateTrigger, m.table),
+		fmt.Sprintf("ALTER TABLE %s RENAME TO %s", m.table, m.oldTableName),
+		fmt.Sprintf("ALTER TABLE %s RENAME TO %s", m.shadowTable, pgx.Identifier{m.tableName}.Sanitize()),
+		// Mark the cutover in the same transaction, so the resumed migration doesn't cut over again.
+		fmt.Sprintf("UPDATE %s SET cut_over = true", m.stateTable),
+	} {
+		if _, err := tx.ExecContext(ctx, statement); err != nil {
+			return errors.Wrapf(err, "failed to swap table %s and shadow table %s", m.table, m.shadowTable)
+		}
+	}
+	return tx.Commit()
+}
+
+// moveSequences moves the sequences owned by the original table to the shadow table,
+// and advances the identity sequences of the shadow table to the ones of the original table.
+func (m *Migration) moveSequences(ctx context.Context, tx *sql.Tx) error {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT s.oid::regclass::text, a.attname
+		FROM pg_depend d
+			JOIN pg_class s ON s.oid = d.objid AND s.relkind = 'S'
+			JOIN pg_attribute a ON a.attrelid = d.refobjid AND a.attnum = d.refobjsubid
+		WHERE d.classid = 'pg_class'::regclass AND d.refclassid = 'pg_class'::regclass AND d.refobjid = $1::regclass AND d.deptype = 'a'`, m.table)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get the sequences owned by table %s", m.table)
+	}
+	defer rows.Close()
+	var statements []string
+	for rows.Next() {
+		var sequence, column string
+		if err := rows.Scan(&sequence, &column); err != nil {
+			return err
+		}
+		if !m.hasColumn(column) {
+			continue
+		}
+		statements = append(statements, fmt.Sprintf("ALTER SEQUENCE %s OWNED BY %s.%s", sequence, m.shadowTable, pgx.Identifier{column}.Sanitize()))
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	identityRows, err := tx.QueryContext(ctx, `
+		SELECT pg_get_serial_sequence($1, a.attname), pg_get_serial_sequence($2, a.attname)
+		FROM pg_attribute a
+		WHERE a.attrelid = $1::regclass AND a.attnum > 0 AND NOT a.attisdropped AND a.attidentity <> ''`, m.shadowTable, m.table)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get the identity sequences of shadow table %s", m.shadowTable)
+	}
+	defer identityRows.Close()
+	for identityRows.Next() {
+		var shadowSequence, sequence sql.NullString
+		if err := identityRows.Scan(&shadowSequence, &sequence); err != nil {
+			return err
+		}
+		if !shadowSequence.Valid || !sequence.Valid {
+			continue
+		}
+		statements = append(statements, fmt.Sprintf("SELECT setval(%s, last_value, is_called) FROM %s", quoteLiteral(shadowSequence.String), sequence.String))
+	}
+	if err := identityRows.Err(); err != nil {
+		return err
+	}
+
+	for _, statement := range statements {
+		if _, err := tx.ExecContext(ctx, statement); err != nil {
+			return errors.Wrapf(err, "failed to move sequences to shadow table %s", m.shadowTable)
+		}
+	}
+	return nil
+}
+
+// tableObject is an index or a constraint of a table.
+type tableObject struct {
+	name string
+	// constraint is true for the constraints, and false for the indexes not backing any constraint.
+	constraint bool
+	// relation is true for the indexes and the constraints backed by an index, whose names are unique in the schema.
+	relation bool
+}
+
+// renameShadowObjects renames the indexes and the constraints named after the shadow table, which are created by
+// CREATE TABLE LIKE and the ALTER TABLE statement, to be named after the original table.
+func (m *Migration) renameShadowObjects(ctx context.Context, tx *sql.Tx) error {
+	shadowObjects, err := listTableObjects(ctx, tx, m.shadowTable)
+	if err != nil {
+		return errors.Wrapf(err, "failed to list the indexes and constraints of shadow table %s", m.shadowTable)
+	}
+	objects, err := listTableObjects(ctx, tx, m.table)
+	if err != nil {
+		return errors.Wrapf(err, "failed to list the indexes and constraints of table %s", m.table)
+	}
+	rows, err := tx.QueryContext(ctx, `
+		SELECT relname FROM pg_class
+		WHERE relnamespace = $1::regnamespace AND (left(relname, length($2)) = $2 OR left(relname, length($3)) = $3)`,
+		pgx.Identifier{m.schemaName}.Sanitize(), m.tableName, "_"+m.tableName+"_del")
+	if err != nil {
+		return errors.Wrapf(err, "failed to list the relations of schema %s", m.schemaName)
+	}
+	defer rows.Close()
+	relations := map[string]bool{}
+	for rows.Next() {
+		var relation string
+		if err := rows.Scan(&relation); err != nil {
+			return err
+		}
+		relations[relation] = true
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, statement := range m.renameShadowObjectStatements(shadowObjects, objects, relations) {
+		if _, err := tx.ExecContext(ctx, statement); err != nil {
+			return errors.Wrapf(err, "failed to rename the indexes and constraints of shadow table %s", m.shadowTable)
+		}
+	}
+	return nil
+}
+
+// renameShadowObjectStatements returns the statements renaming the shadow table objects, e.g. "_tbl_gho_pkey"
+// to "tbl_pkey". The original table objects with the same names are renamed after the old table first, e.g.
+// "tbl_pkey" to "_tbl_del_pkey". The objects are not renamed if the names are taken by other relations.
+func (m *Migration) renameShadowObjectStatements(shadowObjects, objects []*tableObject, relations map[string]bool) []string {
+	shadowPrefix := "_" + m.tableName + "_gho"
+	oldPrefix := "_" + m.tableName + "_del"
+	shadowNames := map[string]bool{}
+	for _, object := range shadowObjects {
+		shadowNames[object.name] = true
+	}
+	originals := map[string]*tableObject{}
+	for _, object := range objects {
+		originals[object.name] = object
+	}
+
+	var statements []string
+	for _, object := range shadowObjects {
+		suffix, ok := strings.CutPrefix(object.name, shadowPrefix)
+		if !ok {
+			continue
+		}
+		name := m.tableName + suffix
+		if shadowNames[name] {
+			continue
+		}
+		if object.relation {
+			original, ok := originals[name]
+			if ok && original.relation {
+				if relations[oldPrefix+suffix] {
+					continue
+				}
+				statements = append(statements, m.renameStatement(m.table, original, oldPrefix+suffix))
+			} else if relations[name] {
+				continue
+			}
+		}
+		statements = append(statements, m.renameStatement(m.shadowTable, object, name))
+	}
+	return statements
+}
+
+func (m *Migration) renameStatement(table string, object *tableObject, name string) string {
+	if object.constraint {
+		return fmt.Sprintf("ALTER TABLE %s RENAME CONSTRAINT %s TO %s", table, pgx.Identifier{object.name}.Sanitize(), pgx.Identifier{name}.Sanitize())
+	}
+	return fmt.Sprintf("ALTER INDEX %s RENAME TO %s", pgx.Identifier{m.schemaName, object.name}.Sanitize(), pgx.Identifier{name}.Sanitize())
+}
+
+func listTableObjects(ctx context.Context, q querier, table string) ([]*tableObject, error) {
+	rows, err := q.QueryContext(ctx, `
+		SELECT conname, true, contype IN ('p', 'u', 'x') FROM pg_constraint WHERE conrelid = $1::regclass
+		UNION ALL
+		SELECT c.relname, false, true FROM pg_index i JOIN pg_class c ON c.oid = i.indexrelid
+		WHERE i.indrelid = $1::regclass
+			AND NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conrelid = $1::regclass AND conindid = i.indexrelid AND contype IN ('p', 'u', 'x'))
+		ORDER BY 1`, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var objects []*tableObject
+	for rows.Next() {
+		object := &tableObject{}
+		if err := rows.Scan(&object.name, &object.constraint, &object.relation); err != nil {
+			return nil, err
+		}
+		objects = append(objects, object)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return objects, nil
+}
+
+// cleanup drops the triggers, the changelog, the state table and the shadow table.
+func (m *Migration) cleanup(ctx context.Context) error {
+	_, err := m.db.ExecContext(ctx, fmt.Sprintf(`
+		DROP TRIGGER IF EXISTS %s ON %s;
+		DROP TRIGGER IF EXISTS %s ON %s;
+		DROP FUNCTION IF EXISTS %s();
+		DROP TABLE IF EXISTS %s;
+		DROP TABLE IF EXISTS %s;
+		DROP TABLE IF EXISTS %s;`,
+		m.rowTrigger, m.table, m.truncateTrigger, m.table, m.changelogFunction, m.stateTable, m.changelogTable, m.shadowTable))
+	return err
+}
+
+func (m *Migration) hasColumn(column string) bool {
+	for _, c := range m.columns {
+		if c == column {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *Migration) quotedColumns() string {
+	var columns []string
+	for _, column := range m.columns {
+		columns = append(columns, pgx.Identifier{column}.Sanitize())
+	}
+	return strings.Join(columns, ", ")
+}
+
+func (m *Migration) quotedPrimaryKeys() string {
+	var columns []string
+	for _, column := range m.primaryKeys {
+		columns = append(columns, pgx.Identifier{column}.Sanitize())
+	}
+	return strings.Join(columns, ", ")
+}
+
+func getPrimaryKeys(ctx context.Context, q querier, table string) ([]string, error) {
+	rows, err := q.QueryContext(ctx, `
+		SELECT a.attname
+		FROM pg_index i JOIN pg_attribute a ON a.attrelid = i.indrelid AND a.attnum = ANY(i.indkey)
+		WHERE i.indrelid = $1::regclass AND i.indisprimary
+		ORDER BY array_position(i.indkey::int2[], a.attnum)`, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var primaryKeys []string
+	for rows.Next() {
+		var column string
+		if err := rows.Scan(&column); err != nil {
+			return nil, err
+		}
+		primaryKeys = append(primaryKeys, column)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return primaryKeys, nil
+}
+
+func isLockNotAvailable(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == lockNotAvailableCode
+}
+
+func quoteLiteral(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
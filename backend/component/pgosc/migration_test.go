@@ -0,0 +1,89 @@
+package pgosc
+
+import (
+	"testing"
+
+	"github.com/jackc/pgconn"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCopyChunkQuery(t *testing.T) {
+	a := require.New(t)
+	m := &Migration{
+		config:      &config{chunkSize: 100},
+		table:       `"public"."t"`,
+		shadowTable: `"public"."_t_gho"`,
+		primaryKeys: []string{"id", "o'k"},
+		columns:     []string{"id", "o'k", "Name"},
+	}
+
+	a.Equal(`
+		WITH batch AS (
+			SELECT * FROM "public"."t" b  ORDER BY b."id", b."o'k" LIMIT 100
+		), copied AS (
+			INSERT INTO "public"."_t_gho" ("id", "o'k", "Name") OVERRIDING SYSTEM VALUE SELECT "id", "o'k", "Name" FROM batch ON CONFLICT ("id", "o'k") DO NOTHING
+		)
+		SELECT (SELECT count(*) FROM batch), (SELECT jsonb_build_object('id', b."id", 'o''k', b."o'k")::text FROM batch b ORDER BY b."id" DESC, b."o'k" DESC LIMIT 1)`,
+		m.copyChunkQuery(false))
+
+	a.Equal(`
+		WITH batch AS (
+			SELECT * FROM "public"."t" b WHERE (b."id", b."o'k") > (SELECT o."id", o."o'k" FROM jsonb_populate_record(NULL::"public"."t", $1::jsonb) o) ORDER BY b."id", b."o'k" LIMIT 100
+		), copied AS (
+			INSERT INTO "public"."_t_gho" ("id", "o'k", "Name") OVERRIDING SYSTEM VALUE SELECT "id", "o'k", "Name" FROM batch ON CONFLICT ("id", "o'k") DO NOTHING
+		)
+		SELECT (SELECT count(*) FROM batch), (SELECT jsonb_build_object('id', b."id", 'o''k', b."o'k")::text FROM batch b ORDER BY b."id" DESC, b."o'k" DESC LIMIT 1)`,
+		m.copyChunkQuery(true))
+}
+
+func TestIsLockNotAvailable(t *testing.T) {
+	a := require.New(t)
+	a.True(isLockNotAvailable(errors.Wrap(&pgconn.PgError{Code: "55P03", Message: "canceling statement due to lock timeout"}, "failed to lock table")))
+	a.False(isLockNotAvailable(&pgconn.PgError{Code: "57014", Message: "canceling statement due to statement timeout"}))
+	a.False(isLockNotAvailable(errors.New("lock timeout")))
+}
+
+func TestRenameShadowObjectStatements(t *testing.T) {
+	a := require.New(t)
+	m := &Migration{
+		schemaName:  "public",
+		tableName:   "t",
+		table:       `"public"."t"`,
+		shadowTable: `"public"."_t_gho"`,
+	}
+	shadowObjects := []*tableObject{
+		{name: "_t_gho_a_idx", relation: true},
+		{name: "_t_gho_b_check", constraint: true},
+		{name: "_t_gho_c_idx", relation: true},
+		{name: "_t_gho_name_key", constraint: true, relation: true},
+		{name: "_t_gho_pkey", constraint: true, relation: true},
+		{name: "_t_gho_x_check", constraint: true},
+		{name: "t_x_check", constraint: true},
+	}
+	objects := []*tableObject{
+		{name: "t_a_idx", relation: true},
+		{name: "t_name_key", constraint: true, relation: true},
+		{name: "t_pkey", constraint: true, relation: true},
+		{name: "t_x_check", constraint: true},
+	}
+	// "t_c_idx" is taken by the index of another table.
+	relations := map[string]bool{"t": true, "t_a_idx": true, "t_c_idx": true, "t_name_key": true, "t_pkey": true}
+
+	a.Equal([]string{
+		`ALTER INDEX "public"."t_a_idx" RENAME TO "_t_del_a_idx"`,
+		`ALTER INDEX "public"."_t_gho_a_idx" RENAME TO "t_a_idx"`,
+		`ALTER TABLE "public"."_t_gho" RENAME CONSTRAINT "_t_gho_b_check" TO "t_b_check"`,
+		`ALTER TABLE "public"."t" RENAME CONSTRAINT "t_name_key" TO "_t_del_name_key"`,
+		`ALTER TABLE "public"."_t_gho" RENAME CONSTRAINT "_t_gho_name_key" TO "t_name_key"`,
+		`ALTER TABLE "public"."t" RENAME CONSTRAINT "t_pkey" TO "_t_del_pkey"`,
+		`ALTER TABLE "public"."_t_gho" RENAME CONSTRAINT "_t_gho_pkey" TO "t_pkey"`,
+	}, m.renameShadowObjectStatements(shadowObjects, objects, relations))
+}
+
+func TestStatementHash(t *testing.T) {
+	a := require.New(t)
+	m := &Migration{statement: "ALTER TABLE t ADD COLUMN a int"}
+	a.Len(m.statementHash(), 64)
+	a.NotEqual(m.statementHash(), (&Migration{statement: "ALTER TABLE t ADD COLUMN b int"}).statementHash())
+}
@@ -31,8 +31,8 @@ type State struct {
 
 	// TaskProgress is the map from task ID to task progress.
 	TaskProgress sync.Map // map[taskID]api.Progress
-	// GhostTaskState is the map from task ID to gh-ost state.
-	GhostTaskState sync.Map // map[taskID]sharedGhostState
+	// GhostTaskState is the map from task ID to gh-ost or PostgreSQL online schema change state.
+	GhostTaskState sync.Map // map[taskID]sharedGhostState or sharedPostgresOnlineState
 
 	// TaskRunExecutionStatuses is the map from task run ID to task run execution status.
 	TaskRunExecutionStatuses sync.Map // map[taskRunID]TaskRunExecutionStatus
@@ -45,6 +45,18 @@ const (
 	ActivityMemberActivate ActivityType = "bb.member.activate"
 	// ActivityMemberDeactivate is the type for deactivating members.
 	ActivityMemberDeactivate ActivityType = "bb.member.deactivate"
+	// ActivityMemberLoginFailed is the type for failed login attempts of members.
+	ActivityMemberLoginFailed ActivityType = "bb.member.login.failed"
+	// ActivityMemberLock is the type for locking members after too many failed login attempts.
+	ActivityMemberLock ActivityType = "bb.member.lock"
+	// ActivityMemberUnlock is the type for unlocking members.
+	ActivityMemberUnlock ActivityType = "bb.member.unlock"
+	// ActivityMemberAccessTokenCreate is the type for creating personal access tokens of members.
+	ActivityMemberAccessTokenCreate ActivityType = "bb.member.access-token.create"
+	// ActivityMemberAccessTokenRevoke is the type for revoking personal access tokens of members.
+	ActivityMemberAccessTokenRevoke ActivityType = "bb.member.access-token.revoke"
+	// ActivityMemberAccessTokenRotate is the type for rotating personal access tokens of members.
+	ActivityMemberAccessTokenRotate ActivityType = "bb.member.access-token.rotate"
 
 	// Project related.
 
@@ -56,6 +68,8 @@ const (
 	ActivityProjectMemberCreate ActivityType = "bb.project.member.create"
 	// ActivityProjectMemberDelete is the type for deleting project members.
 	ActivityProjectMemberDelete ActivityType = "bb.project.member.delete"
+	// ActivityProjectMemberExpiring is the type for notifying project members that the granted roles are about to expire.
+	ActivityProjectMemberExpiring ActivityType = "bb.project.member.expiring"
 
 	// SQL Editor related.
 
@@ -71,6 +85,20 @@ const (
 
 	// ActivityDatabaseRecoveryPITRDone is the type for performing PITR on the database successfully.
 	ActivityDatabaseRecoveryPITRDone ActivityType = "bb.database.recovery.pitr.done"
+
+	// Webhook event related.
+	// The events are only posted to the webhooks, no activity is created for them.
+
+	// EventAnomalyCreate is the event for detecting anomalies, e.g. schema drift, backup missing and connection failure.
+	EventAnomalyCreate ActivityType = "bb.anomaly.create"
+	// EventAnomalyResolve is the event for resolving anomalies.
+	EventAnomalyResolve ActivityType = "bb.anomaly.resolve"
+	// EventDatabaseBackupFailed is the event for failed database backups.
+	EventDatabaseBackupFailed ActivityType = "bb.database.backup.failed"
+	// EventInstanceCreate is the event for adding instances.
+	EventInstanceCreate ActivityType = "bb.instance.create"
+	// EventProjectMemberExpire is the event for revoking the project roles as the grants expire.
+	EventProjectMemberExpire ActivityType = "bb.project.member.expire"
 )
 
 // ActivityLevel is the level of activities.
@@ -230,6 +258,38 @@ type ActivityMemberActivateDeactivatePayload struct {
 	Role           Role   `json:"role"`
 }
 
+// ActivityMemberLoginPayload is the API message payloads for failed login attempts, locking or unlocking members.
+type ActivityMemberLoginPayload struct {
+	PrincipalID    int    `json:"principalId"`
+	PrincipalName  string `json:"principalName"`
+	PrincipalEmail string `json:"principalEmail"`
+	ClientIP       string `json:"clientIp,omitempty"`
+	FailureCount   int    `json:"failureCount,omitempty"`
+	LockedUntilTs  int64  `json:"lockedUntilTs,omitempty"`
+}
+
+// ActivityMemberAccessTokenPayload is the API message payloads for creating, revoking or rotating personal access tokens.
+type ActivityMemberAccessTokenPayload struct {
+	PrincipalID    int      `json:"principalId"`
+	PrincipalName  string   `json:"principalName"`
+	PrincipalEmail string   `json:"principalEmail"`
+	TokenName      string   `json:"tokenName"`
+	TokenTitle     string   `json:"tokenTitle"`
+	Scopes         []string `json:"scopes,omitempty"`
+	ExpireTs       int64    `json:"expireTs,omitempty"`
+}
+
+// ActivityProjectMemberGrantPayload is the API message payloads for expiring or revoking the granted roles of project members.
+type ActivityProjectMemberGrantPayload struct {
+	PrincipalID    int    `json:"principalId"`
+	PrincipalName  string `json:"principalName"`
+	PrincipalEmail string `json:"principalEmail"`
+	Role           Role   `json:"role"`
+	// IssueID is the grant request issue of the role, if any.
+	IssueID  int   `json:"issueId,omitempty"`
+	ExpireTs int64 `json:"expireTs"`
+}
+
 // ActivityProjectRepositoryPushPayload is the API message payloads for pushing repositories.
 type ActivityProjectRepositoryPushPayload struct {
 	VCSPushEvent vcs.PushEvent `json:"pushEvent"`
@@ -6,14 +6,18 @@ type ExternalApprovalType string
 const (
 	// ExternalApprovalTypeFeishu is the ExternalApproval from feishu.
 	ExternalApprovalTypeFeishu ExternalApprovalType = "bb.plugin.app.feishu"
-	// ExternalApprovalTypeRelay is the ExternalApproval from relay.
+	// ExternalApprovalTypeRelay is the ExternalApproval from the external approval nodes.
+	// The provider of the ExternalApproval, e.g. the relay service or a ticketing system, is decided by the node type.
 	ExternalApprovalTypeRelay ExternalApprovalType = "bb.plugin.app.relay"
 )
 
 // ExternalApprovalPayloadRelay is the payload for relay type ExternalApproval.
 type ExternalApprovalPayloadRelay struct {
 	ExternalApprovalNodeID string `json:"externalApprovalNodeId"`
-	ID                     string `json:"id"`
+	// ID is the ID of the change request in the external system, e.g. the ticket ID.
+	ID string `json:"id"`
+	// URL is the link to the change request in the external system.
+	URL string `json:"url,omitempty"`
 }
 
 // ExternalApprovalEventActionType is the type of the action which the user took.
@@ -31,6 +31,8 @@ const (
 
 	// ServiceAccountAccessKeyPrefix is the prefix for service account access key.
 	ServiceAccountAccessKeyPrefix = "bbs_"
+	// PersonalAccessTokenPrefix is the prefix for personal access token.
+	PersonalAccessTokenPrefix = "bbp_"
 )
 
 // PrincipalAuthProvider is the type of an authentication provider.
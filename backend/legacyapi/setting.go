@@ -46,6 +46,24 @@ const (
 	SettingSemanticTypes SettingName = "bb.workspace.semantic-types"
 	// SettingMaskingAlgorithms is the setting name for masking algorithms.
 	SettingMaskingAlgorithm SettingName = "bb.workspace.masking-algorithm"
+	// SettingShadowDatabase is the setting name for shadow database dry run.
+	SettingShadowDatabase SettingName = "bb.workspace.shadow-database"
+	// SettingDataRollback is the setting name for data change rollback.
+	SettingDataRollback SettingName = "bb.workspace.data-rollback"
+	// SettingSCIM is the setting name for SCIM provisioning.
+	SettingSCIM SettingName = "bb.workspace.scim"
+	// SettingLDAPGroupSync is the setting name for LDAP group synchronization.
+	SettingLDAPGroupSync SettingName = "bb.workspace.ldap-group-sync"
+	// SettingIdentityProviderClaimMapping is the setting name for the OAuth2 and OIDC claim based role mapping.
+	SettingIdentityProviderClaimMapping SettingName = "bb.workspace.idp-claim-mapping"
+	// SettingPasswordPolicy is the setting name for the password policy and the login throttling of the built-in accounts.
+	SettingPasswordPolicy SettingName = "bb.workspace.password-policy"
+	// SettingWebAuthn is the setting name for the WebAuthn relying party and the hardware-bound factor enforcement.
+	SettingWebAuthn SettingName = "bb.workspace.webauthn"
+	// SettingAuditLogExport is the setting name for the destinations of the audit log streaming export.
+	SettingAuditLogExport SettingName = "bb.workspace.audit-log-export"
+	// SettingWorkspaceWebhook is the setting name for the workspace webhooks receiving the events across all projects.
+	SettingWorkspaceWebhook SettingName = "bb.workspace.webhook"
 )
 
 // IMType is the type of IM.
@@ -60,12 +78,28 @@ type ExternalApproval struct {
 	ApprovalDefinitionID string `json:"approvalDefinitionID"`
 }
 
+// SlackApproval is the interactive approval setting for Slack.
+type SlackApproval struct {
+	Enabled       bool   `json:"enabled"`
+	SigningSecret string `json:"signingSecret"`
+	BotToken      string `json:"botToken"`
+}
+
+// TeamsApproval is the interactive approval setting for Microsoft Teams.
+type TeamsApproval struct {
+	Enabled bool   `json:"enabled"`
+	Token   string `json:"token"`
+}
+
 // SettingAppIMValue is the setting value of SettingAppIM type setting.
 type SettingAppIMValue struct {
 	IMType           IMType           `json:"imType"`
 	AppID            string           `json:"appId"`
 	AppSecret        string           `json:"appSecret"`
 	ExternalApproval ExternalApproval `json:"externalApproval"`
+	// Slack and Teams are the interactive approvals from the Slack and Teams webhook messages.
+	Slack SlackApproval `json:"slack"`
+	Teams TeamsApproval `json:"teams"`
 }
 
 // SettingWorkspaceMailDeliveryValue is the setting value of SettingMailDelivery type setting.
@@ -79,3 +113,265 @@ type SettingWorkspaceMailDeliveryValue struct {
 	SMTPEncryptionType     storepb.SMTPMailDeliverySetting_Encryption     `json:"smtpEncryptionType"`
 	SMTPTo                 string                                         `json:"sendTo"`
 }
+
+// SettingShadowDatabaseValue is the setting value of SettingShadowDatabase type setting.
+type SettingShadowDatabaseValue struct {
+	// Enabled controls whether the shadow database dry run plan check is created for schema changes.
+	Enabled bool `json:"enabled"`
+	// SandboxInstanceIDs are the resource ids of the instances used to host the shadow databases.
+	// The first sandbox instance with the same engine as the target is used.
+	SandboxInstanceIDs []string `json:"sandboxInstanceIds"`
+	// AllowTargetInstance allows creating the shadow database on the target instance if no sandbox instance matches.
+	// Otherwise, the dry run is skipped with a warning to keep the shadow databases off the production instances.
+	AllowTargetInstance bool `json:"allowTargetInstance"`
+}
+
+// SettingDataRollbackValue is the setting value of SettingDataRollback type setting.
+type SettingDataRollbackValue struct {
+	// PreImageRowLimit is the maximum number of rows captured before executing the data change.
+	// The rollback SQL generation fails if the data change affects more rows.
+	// It is only used for PostgreSQL now.
+	PreImageRowLimit int `json:"preImageRowLimit"`
+}
+
+// SettingSCIMValue is the setting value of SettingSCIM type setting.
+type SettingSCIMValue struct {
+	// Token is the bearer token for the SCIM client. It's only used for updating the setting,
+	// and the SHA-256 hash of the token is stored in TokenHash.
+	Token string `json:"token,omitempty"`
+	// TokenHash is the hex encoded SHA-256 hash of the bearer token. SCIM is disabled if empty.
+	TokenHash string `json:"tokenHash"`
+	// GroupMappings maps the SCIM groups to the project IAM policy bindings.
+	GroupMappings []*SCIMGroupMapping `json:"groupMappings"`
+}
+
+// SCIMGroupMapping grants the members of the SCIM group the role in the project.
+type SCIMGroupMapping struct {
+	// Group is the display name of the SCIM group.
+	Group string `json:"group"`
+	// Project is the resource id of the project.
+	Project string `json:"project"`
+	// Role is the project role, e.g. roles/DEVELOPER.
+	Role string `json:"role"`
+}
+
+// SettingLDAPGroupSyncValue is the setting value of SettingLDAPGroupSync type setting.
+type SettingLDAPGroupSyncValue struct {
+	// IdentityProviders are the group sync configs of the LDAP identity providers.
+	IdentityProviders []*LDAPGroupSyncConfig `json:"identityProviders"`
+}
+
+// LDAPGroupSyncConfig is the group sync config of an LDAP identity provider.
+// The workspace roles and the mapped project roles of the directory users are reconciled
+// on login and periodically, so removing a user from a group or the directory revokes the access.
+type LDAPGroupSyncConfig struct {
+	// IdentityProvider is the resource id of the LDAP identity provider.
+	IdentityProvider string `json:"identityProvider"`
+	// GroupMappings maps the LDAP groups to the workspace roles and the project IAM policy bindings.
+	GroupMappings []*LDAPGroupMapping `json:"groupMappings"`
+	// DefaultWorkspaceRole is the workspace role of the directory users without any mapped workspace role,
+	// including the users removed from the directory. The workspace roles of these users are kept if it's empty.
+	DefaultWorkspaceRole Role `json:"defaultWorkspaceRole"`
+}
+
+// LDAPGroupMapping grants the members of the LDAP group the workspace role and/or the role in the project.
+// Either GroupDN or Filter should be set.
+type LDAPGroupMapping struct {
+	// GroupDN is the DN of the group entry whose "member" or "uniqueMember" attributes list the user DNs,
+	// e.g. cn=dba,ou=groups,dc=example,dc=com.
+	GroupDN string `json:"groupDn"`
+	// Filter is the filter matching the user entries, e.g. (memberOf=cn=dba,ou=groups,dc=example,dc=com).
+	Filter string `json:"filter"`
+	// WorkspaceRole is the workspace role, i.e. OWNER, DBA or DEVELOPER. The highest mapped role wins,
+	// and the directory users without any mapped workspace role are set to the DefaultWorkspaceRole of the config.
+	WorkspaceRole Role `json:"workspaceRole"`
+	// Project is the resource id of the project.
+	Project string `json:"project"`
+	// Role is the project role, e.g. roles/DEVELOPER.
+	Role string `json:"role"`
+}
+
+// SettingIdentityProviderClaimMappingValue is the setting value of SettingIdentityProviderClaimMapping type setting.
+type SettingIdentityProviderClaimMappingValue struct {
+	// IdentityProviders are the claim mapping configs of the OAuth2 and OIDC identity providers.
+	IdentityProviders []*IdentityProviderClaimMappingConfig `json:"identityProviders"`
+}
+
+// IdentityProviderClaimMappingConfig is the claim mapping config of an OAuth2 or OIDC identity provider.
+// The workspace role and the mapped project roles of the user are reconciled on every login.
+type IdentityProviderClaimMappingConfig struct {
+	// IdentityProvider is the resource id of the OAuth2 or OIDC identity provider.
+	IdentityProvider string `json:"identityProvider"`
+	// ClaimMappings maps the claim values to the workspace roles and the project IAM policy bindings.
+	ClaimMappings []*IdentityProviderClaimMapping `json:"claimMappings"`
+	// DefaultWorkspaceRole is the workspace role of the users without any mapped workspace role.
+	// The workspace roles of these users are kept if it's empty.
+	DefaultWorkspaceRole Role `json:"defaultWorkspaceRole"`
+}
+
+// IdentityProviderClaimMapping grants the users whose claim contains the value the workspace role and/or the role in the project.
+type IdentityProviderClaimMapping struct {
+	// Claim is the JSONPath-like selector of the claim in the ID Token or the user info response,
+	// e.g. groups, realm_access.roles or $['https://example.com/roles'].
+	Claim string `json:"claim"`
+	// Value is the claim value to match. Array claims match if any element equals the value.
+	Value string `json:"value"`
+	// WorkspaceRole is the workspace role, i.e. OWNER, DBA or DEVELOPER. The highest mapped role wins,
+	// and the users without any mapped workspace role are set to the DefaultWorkspaceRole of the config.
+	WorkspaceRole Role `json:"workspaceRole"`
+	// Project is the resource id of the project.
+	Project string `json:"project"`
+	// Role is the project role, e.g. roles/DEVELOPER.
+	Role string `json:"role"`
+}
+
+const (
+	// MaxPasswordHistoryCount is the max number of the previous passwords that cannot be reused.
+	MaxPasswordHistoryCount = 24
+	// DefaultMaxAccountFailedAttempts is the default number of consecutive failed login attempts before locking the account.
+	DefaultMaxAccountFailedAttempts = 5
+	// DefaultMaxIPFailedAttempts is the default number of consecutive failed login attempts before locking the client IP.
+	DefaultMaxIPFailedAttempts = 20
+	// DefaultLockoutMinutes is the default duration of the temporary lockout.
+	DefaultLockoutMinutes = 15
+)
+
+// SettingPasswordPolicyValue is the setting value of SettingPasswordPolicy type setting.
+// It only applies to the built-in accounts signing in with email and password.
+type SettingPasswordPolicyValue struct {
+	// MinLength is the minimum length of the password, 0 means no limit.
+	MinLength int `json:"minLength"`
+	// RequireUppercase requires at least one uppercase letter.
+	RequireUppercase bool `json:"requireUppercase"`
+	// RequireLowercase requires at least one lowercase letter.
+	RequireLowercase bool `json:"requireLowercase"`
+	// RequireNumber requires at least one number.
+	RequireNumber bool `json:"requireNumber"`
+	// RequireSpecialCharacter requires at least one character other than letters and numbers.
+	RequireSpecialCharacter bool `json:"requireSpecialCharacter"`
+	// RotationDays is the number of days before the password expires, 0 means the password never expires.
+	RotationDays int `json:"rotationDays"`
+	// HistoryCount is the number of the previous passwords that cannot be reused, up to MaxPasswordHistoryCount.
+	HistoryCount int `json:"historyCount"`
+	// MaxAccountFailedAttempts is the number of consecutive failed login attempts before locking the account.
+	MaxAccountFailedAttempts int `json:"maxAccountFailedAttempts"`
+	// MaxIPFailedAttempts is the number of consecutive failed login attempts before locking the client IP.
+	MaxIPFailedAttempts int `json:"maxIpFailedAttempts"`
+	// LockoutMinutes is the duration of the temporary lockout.
+	LockoutMinutes int `json:"lockoutMinutes"`
+}
+
+// SettingWebAuthnValue is the setting value of SettingWebAuthn type setting.
+type SettingWebAuthnValue struct {
+	// RPID is the WebAuthn relying party ID, i.e. the effective domain of the origins.
+	// It defaults to the host of the workspace external URL.
+	RPID string `json:"rpId"`
+	// Origins are the allowed origins of the WebAuthn ceremonies, e.g. https://bytebase.example.com.
+	// It defaults to the workspace external URL.
+	Origins []string `json:"origins"`
+	// AllowPasswordless allows the users to login with the passkeys without password.
+	AllowPasswordless bool `json:"allowPasswordless"`
+	// RequireHardwareKeyRoles are the workspace roles, i.e. OWNER, DBA or DEVELOPER, that must pass the MFA
+	// with a hardware-bound WebAuthn credential. The TOTP and the recovery codes are rejected for these users.
+	// The users without a hardware-bound credential can only sign in to register one.
+	RequireHardwareKeyRoles []Role `json:"requireHardwareKeyRoles"`
+	// HardwareKeyAttestationRoots are the PEM encoded root certificates of the security key vendors, which are
+	// required by RequireHardwareKeyRoles. A credential is hardware-bound only if its packed or fido-u2f attestation
+	// is signed by a certificate chained to these roots.
+	HardwareKeyAttestationRoots []string `json:"hardwareKeyAttestationRoots"`
+	// HardwareKeyAAGUIDs are the allowed authenticator models of the hardware-bound credentials, e.g.
+	// cb69481e-8ff7-4039-93ec-0a2729a154a8. All attested models are allowed if it's empty. The U2F security keys
+	// have the all-zero AAGUID 00000000-0000-0000-0000-000000000000.
+	HardwareKeyAAGUIDs []string `json:"hardwareKeyAaguids"`
+	// HardwareKeyGracePeriodEndTs is the time before which the users without a hardware-bound credential can
+	// still sign in with the other factors, 0 means no grace period.
+	HardwareKeyGracePeriodEndTs int64 `json:"hardwareKeyGracePeriodEndTs"`
+}
+
+// AuditLogDestinationType is the type of the audit log export destination.
+type AuditLogDestinationType string
+
+const (
+	// AuditLogDestinationSyslog exports the audit logs to a syslog server in RFC 5424 format.
+	AuditLogDestinationSyslog AuditLogDestinationType = "SYSLOG"
+	// AuditLogDestinationHTTP exports the audit logs to an HTTP endpoint.
+	AuditLogDestinationHTTP AuditLogDestinationType = "HTTP"
+	// AuditLogDestinationFile exports the audit logs to a local file with size based rotation.
+	AuditLogDestinationFile AuditLogDestinationType = "FILE"
+)
+
+// AuditLogDestination is the destination of the audit log streaming export.
+type AuditLogDestination struct {
+	// ID is the unique identifier of the destination. The export progress is tracked by the ID.
+	ID   string                  `json:"id"`
+	Type AuditLogDestinationType `json:"type"`
+	// ActivityTypes are the activity types exported to the destination, empty means all types.
+	ActivityTypes []ActivityType `json:"activityTypes"`
+
+	Syslog *AuditLogSyslogDestination `json:"syslog,omitempty"`
+	HTTP   *AuditLogHTTPDestination   `json:"http,omitempty"`
+	File   *AuditLogFileDestination   `json:"file,omitempty"`
+}
+
+// AuditLogSyslogDestination is the syslog destination of the audit log export.
+type AuditLogSyslogDestination struct {
+	// Network is either udp or tcp.
+	Network string `json:"network"`
+	// Address is the host:port of the syslog server.
+	Address string `json:"address"`
+	// AppName is the APP-NAME of the syslog message, it defaults to bytebase.
+	AppName string `json:"appName"`
+}
+
+// AuditLogHTTPDestination is the HTTP destination of the audit log export.
+type AuditLogHTTPDestination struct {
+	URL string `json:"url"`
+	// Headers are the extra headers of the requests, e.g. Authorization.
+	Headers map[string]string `json:"headers"`
+}
+
+// AuditLogFileDestination is the local file destination of the audit log export.
+type AuditLogFileDestination struct {
+	Path string `json:"path"`
+	// MaxSizeMB is the size of the file before it gets rotated, it defaults to 100.
+	MaxSizeMB int `json:"maxSizeMb"`
+	// MaxBackups is the number of the rotated files to keep, 0 means keeping all of them.
+	MaxBackups int `json:"maxBackups"`
+}
+
+// SettingAuditLogExportValue is the setting value of SettingAuditLogExport type setting.
+type SettingAuditLogExportValue struct {
+	Destinations []*AuditLogDestination `json:"destinations"`
+}
+
+// WorkspaceWebhook is the webhook receiving the events across all projects.
+type WorkspaceWebhook struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+	// Type is the type of the receiver, e.g. bb.plugin.webhook.custom.
+	Type string `json:"type"`
+	URL  string `json:"url"`
+	// ActivityTypes are the subscribed events, all events are subscribed if it's empty.
+	ActivityTypes []ActivityType `json:"activityTypes"`
+	// SigningSecret is the secret for signing the request body of the custom webhooks with HMAC-SHA256.
+	// It is input only, the stored secret is kept if it's empty in the update.
+	SigningSecret string `json:"signingSecret"`
+}
+
+// Subscribe returns whether the webhook subscribes to the event.
+func (w *WorkspaceWebhook) Subscribe(activityType ActivityType) bool {
+	if len(w.ActivityTypes) == 0 {
+		return true
+	}
+	for _, t := range w.ActivityTypes {
+		if t == activityType {
+			return true
+		}
+	}
+	return false
+}
+
+// SettingWorkspaceWebhookValue is the setting value of SettingWorkspaceWebhook type setting.
+type SettingWorkspaceWebhookValue struct {
+	Webhooks []*WorkspaceWebhook `json:"webhooks"`
+}
@@ -191,7 +191,7 @@ type TaskDatabaseDataUpdatePayload struct {
 	SheetID       int    `json:"sheetId,omitempty"`
 	SchemaVersion string `json:"schemaVersion,omitempty"`
 
-	// MySQL rollback SQL related.
+	// Rollback SQL related.
 
 	// Build the RollbackSheetID if RollbackEnabled.
 	RollbackEnabled bool `json:"rollbackEnabled,omitempty"`
@@ -0,0 +1,29 @@
+-- notify_cache_invalidation notifies the Bytebase replicas in the high-availability mode to invalidate the cache.
+-- The payload is the table and the JSON array of the trigger argument columns of the changed row, e.g. "environment:["101","prod"]".
+-- Both the old and the new keys are notified if the update changes the key, and the truncation only notifies the table.
+-- The triggers are installed by the replicas in the high-availability mode on startup.
+CREATE OR REPLACE FUNCTION notify_cache_invalidation()
+RETURNS TRIGGER AS $$
+DECLARE
+  old_key JSONB;
+  new_key JSONB;
+BEGIN
+  IF TG_LEVEL = 'STATEMENT' THEN
+    PERFORM pg_notify('bb_cache_invalidation', TG_TABLE_NAME);
+    RETURN NULL;
+  END IF;
+  IF TG_OP IN ('UPDATE', 'DELETE') THEN
+    SELECT COALESCE(jsonb_agg(to_jsonb(OLD) ->> a.arg ORDER BY a.ord), '[]'::JSONB) INTO old_key
+    FROM unnest(TG_ARGV) WITH ORDINALITY AS a(arg, ord);
+    PERFORM pg_notify('bb_cache_invalidation', TG_TABLE_NAME || ':' || old_key::TEXT);
+  END IF;
+  IF TG_OP IN ('INSERT', 'UPDATE') THEN
+    SELECT COALESCE(jsonb_agg(to_jsonb(NEW) ->> a.arg ORDER BY a.ord), '[]'::JSONB) INTO new_key
+    FROM unnest(TG_ARGV) WITH ORDINALITY AS a(arg, ord);
+    IF new_key IS DISTINCT FROM old_key THEN
+      PERFORM pg_notify('bb_cache_invalidation', TG_TABLE_NAME || ':' || new_key::TEXT);
+    END IF;
+  END IF;
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
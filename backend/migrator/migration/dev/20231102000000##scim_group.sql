@@ -0,0 +1,58 @@
+-- scim_group stores the groups provisioned by the SCIM client such as Okta and Azure AD.
+CREATE TABLE scim_group (
+    id SERIAL PRIMARY KEY,
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    resource_id TEXT NOT NULL,
+    external_id TEXT NOT NULL DEFAULT '',
+    display_name TEXT NOT NULL,
+    -- member_ids stores the principal ids of the group members.
+    member_ids INTEGER[] NOT NULL DEFAULT '{}'
+);
+
+CREATE UNIQUE INDEX idx_scim_group_unique_resource_id ON scim_group(resource_id);
+
+CREATE UNIQUE INDEX idx_scim_group_unique_display_name ON scim_group(display_name);
+
+ALTER SEQUENCE scim_group_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_scim_group_updated_ts
+BEFORE
+UPDATE
+    ON scim_group FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
+
+-- scim_group_binding stores the project IAM policy bindings granted by the SCIM group mappings, so that only
+-- these bindings are revoked when the members leave the groups, not the ones granted manually or by the other
+-- identity providers.
+CREATE TABLE scim_group_binding (
+    -- project is the resource id of the project.
+    project TEXT NOT NULL,
+    -- role is the project role, e.g. roles/DEVELOPER.
+    role TEXT NOT NULL,
+    principal_id INTEGER NOT NULL REFERENCES principal (id),
+    PRIMARY KEY (project, role, principal_id)
+);
+
+-- ldap_group_sync_user stores the users synced from the LDAP identity providers,
+-- so that the mapped roles of the users removed from the directory are revoked.
+CREATE TABLE ldap_group_sync_user (
+    -- idp_id is the resource id of the LDAP identity provider.
+    idp_id TEXT NOT NULL,
+    principal_id INTEGER NOT NULL REFERENCES principal (id),
+    PRIMARY KEY (idp_id, principal_id)
+);
+
+-- The directory users without any mapped workspace role were set to DEVELOPER, which is explicit now.
+UPDATE setting
+SET value = (
+    SELECT jsonb_build_object('identityProviders', COALESCE(jsonb_agg(
+        CASE
+            WHEN EXISTS (SELECT 1 FROM jsonb_array_elements(COALESCE(p->'groupMappings', '[]'::jsonb)) m WHERE COALESCE(m->>'workspaceRole', '') <> '')
+            THEN p || '{"defaultWorkspaceRole": "DEVELOPER"}'::jsonb
+            ELSE p
+        END
+    ), '[]'::jsonb))::text
+    FROM jsonb_array_elements(COALESCE(value::jsonb->'identityProviders', '[]'::jsonb)) p
+)
+WHERE name = 'bb.workspace.ldap-group-sync' AND value <> '';
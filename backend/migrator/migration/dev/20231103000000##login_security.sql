@@ -0,0 +1,34 @@
+-- password_history stores the password hashes of the end users for the password history and rotation policies.
+CREATE TABLE password_history (
+    id SERIAL PRIMARY KEY,
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    principal_id INTEGER NOT NULL REFERENCES principal (id),
+    password_hash TEXT NOT NULL
+);
+
+CREATE INDEX idx_password_history_principal_id ON password_history(principal_id);
+
+ALTER SEQUENCE password_history_id_seq RESTART WITH 101;
+
+-- login_throttle stores the failed login counters of the accounts and the client IPs.
+CREATE TABLE login_throttle (
+    id SERIAL PRIMARY KEY,
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    type TEXT NOT NULL CHECK (type IN ('ACCOUNT', 'IP')),
+    -- subject is the lower-case email for ACCOUNT and the client IP for IP.
+    subject TEXT NOT NULL,
+    failure_count INTEGER NOT NULL DEFAULT 0,
+    last_failure_ts BIGINT NOT NULL DEFAULT 0,
+    locked_until_ts BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE UNIQUE INDEX idx_login_throttle_unique_type_subject ON login_throttle(type, subject);
+
+ALTER SEQUENCE login_throttle_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_login_throttle_updated_ts
+BEFORE
+UPDATE
+    ON login_throttle FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
@@ -0,0 +1,27 @@
+-- access_token stores the personal access tokens of the users and service accounts.
+CREATE TABLE access_token (
+    id SERIAL PRIMARY KEY,
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    principal_id INTEGER NOT NULL REFERENCES principal (id),
+    title TEXT NOT NULL,
+    -- token_hash is the hex encoded SHA-256 hash of the token.
+    token_hash TEXT NOT NULL,
+    -- scopes limit the methods that the token can call, e.g. {read, issue.create:projects/hr}.
+    scopes TEXT ARRAY NOT NULL,
+    expire_ts BIGINT NOT NULL,
+    last_used_ts BIGINT NOT NULL DEFAULT 0,
+    last_used_ip TEXT NOT NULL DEFAULT ''
+);
+
+CREATE UNIQUE INDEX idx_access_token_unique_token_hash ON access_token(token_hash);
+
+CREATE INDEX idx_access_token_principal_id ON access_token(principal_id);
+
+ALTER SEQUENCE access_token_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_access_token_updated_ts
+BEFORE
+UPDATE
+    ON access_token FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
@@ -0,0 +1,23 @@
+-- approval_delegation stores the windows in which the delegates review the approvals on behalf of the delegators.
+CREATE TABLE approval_delegation (
+    id SERIAL PRIMARY KEY,
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    delegator_id INTEGER NOT NULL REFERENCES principal (id),
+    delegate_id INTEGER NOT NULL REFERENCES principal (id),
+    start_ts BIGINT NOT NULL,
+    end_ts BIGINT NOT NULL,
+    reason TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX idx_approval_delegation_delegator_id ON approval_delegation(delegator_id);
+
+CREATE INDEX idx_approval_delegation_delegate_id ON approval_delegation(delegate_id);
+
+ALTER SEQUENCE approval_delegation_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_approval_delegation_updated_ts
+BEFORE
+UPDATE
+    ON approval_delegation FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
@@ -0,0 +1,34 @@
+-- activity_ledger is the append-only hash chain of the activity records.
+-- A record is appended every time an activity is created or updated, so modifying or deleting the activity rows
+-- out of band breaks the chain. activity_id doesn't reference activity so that the deleted activities are detected.
+-- The records are appended unsealed along with the activities, and chained by the sealing afterwards.
+CREATE TABLE activity_ledger (
+    id BIGSERIAL PRIMARY KEY,
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    activity_id INTEGER NOT NULL,
+    -- content_hash is the hex encoded SHA-256 hash of the activity record.
+    content_hash TEXT NOT NULL,
+    -- hash is the hex encoded HMAC-SHA256 of the hash of the previous record and the content hash, it's NULL until sealed.
+    hash TEXT,
+    -- seq is the position of the record in the hash chain.
+    seq BIGINT
+);
+
+CREATE INDEX idx_activity_ledger_activity_id ON activity_ledger(activity_id);
+
+CREATE UNIQUE INDEX idx_activity_ledger_unique_seq ON activity_ledger(seq);
+
+CREATE INDEX idx_activity_ledger_unsealed ON activity_ledger(id) WHERE seq IS NULL;
+
+-- audit_log_export_cursor stores the last activity ledger record exported to each audit log destination.
+CREATE TABLE audit_log_export_cursor (
+    destination TEXT PRIMARY KEY,
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    ledger_seq BIGINT NOT NULL
+);
+
+CREATE TRIGGER update_audit_log_export_cursor_updated_ts
+BEFORE
+UPDATE
+    ON audit_log_export_cursor FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
@@ -0,0 +1,36 @@
+-- signing_secret is the secret for signing the request body of the custom webhooks with HMAC-SHA256.
+ALTER TABLE project_webhook ADD COLUMN signing_secret TEXT NOT NULL DEFAULT '';
+
+-- webhook_delivery is the outbox of the project webhook events.
+-- The deliveries are retried with exponential backoff, and moved to the DEAD_LETTER status after the max attempts.
+CREATE TABLE webhook_delivery (
+    id BIGSERIAL PRIMARY KEY,
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    project_webhook_id INTEGER NOT NULL REFERENCES project_webhook (id) ON DELETE CASCADE,
+    activity_type TEXT NOT NULL,
+    -- payload is the webhook context of the event.
+    payload JSONB NOT NULL DEFAULT '{}',
+    status TEXT NOT NULL CHECK (status IN ('PENDING', 'DELIVERED', 'DEAD_LETTER')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    -- response_code is the HTTP status code of the last attempt, 0 if there is no response.
+    response_code INTEGER NOT NULL DEFAULT 0,
+    -- error is the error of the last attempt.
+    error TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX idx_webhook_delivery_project_webhook_id ON webhook_delivery(project_webhook_id);
+
+CREATE INDEX idx_webhook_delivery_pending_next_attempt_ts ON webhook_delivery(next_attempt_ts) WHERE status = 'PENDING';
+
+-- The delivered and dead letter deliveries are pruned by the updated_ts after the retention.
+CREATE INDEX idx_webhook_delivery_done_updated_ts ON webhook_delivery(updated_ts) WHERE status <> 'PENDING';
+
+ALTER SEQUENCE webhook_delivery_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_webhook_delivery_updated_ts
+BEFORE
+UPDATE
+    ON webhook_delivery FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
@@ -0,0 +1,8 @@
+-- The deliveries target either a project webhook or a workspace webhook in the bb.workspace.webhook setting.
+ALTER TABLE webhook_delivery ALTER COLUMN project_webhook_id DROP NOT NULL;
+
+ALTER TABLE webhook_delivery ADD COLUMN workspace_webhook_id TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE webhook_delivery ADD CONSTRAINT webhook_delivery_target_check CHECK ((project_webhook_id IS NULL) <> (workspace_webhook_id = ''));
+
+CREATE INDEX idx_webhook_delivery_workspace_webhook_id ON webhook_delivery(workspace_webhook_id) WHERE workspace_webhook_id != '';
@@ -0,0 +1,31 @@
+ALTER TABLE principal ADD COLUMN notification_setting JSONB NOT NULL DEFAULT '{}';
+
+-- email_notification is the outbox of the issue email notifications, the notifications of a recipient are sent in one digest email.
+CREATE TABLE email_notification (
+    id BIGSERIAL PRIMARY KEY,
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    recipient_id INTEGER NOT NULL REFERENCES principal (id),
+    -- event is the NotificationSetting.Event of the notification.
+    event TEXT NOT NULL,
+    issue_id INTEGER NOT NULL REFERENCES issue (id),
+    -- payload is the content of the notification.
+    payload JSONB NOT NULL DEFAULT '{}',
+    status TEXT NOT NULL CHECK (status IN ('PENDING', 'SENT', 'FAILED')),
+    -- error is the error of the sending.
+    error TEXT NOT NULL DEFAULT '',
+    -- attempts is the number of the sending attempts, the failed notifications are retried until the max attempts.
+    attempts INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX idx_email_notification_pending_recipient_id ON email_notification(recipient_id) WHERE status = 'PENDING';
+
+CREATE INDEX idx_email_notification_issue_id ON email_notification(issue_id);
+
+ALTER SEQUENCE email_notification_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_email_notification_updated_ts
+BEFORE
+UPDATE
+    ON email_notification FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
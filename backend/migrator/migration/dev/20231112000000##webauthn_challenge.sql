@@ -0,0 +1,16 @@
+-- webauthn_challenge stores the issued WebAuthn challenges, each of them can only be consumed once.
+CREATE TABLE webauthn_challenge (
+    id BIGSERIAL PRIMARY KEY,
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    -- challenge is the base64url encoded random challenge.
+    challenge TEXT NOT NULL,
+    -- subject is the ceremony and the user of the challenge, e.g. register:101.
+    subject TEXT NOT NULL,
+    expires_ts BIGINT NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_webauthn_challenge_unique_challenge ON webauthn_challenge(challenge);
+
+CREATE INDEX idx_webauthn_challenge_expires_ts ON webauthn_challenge(expires_ts);
+
+ALTER SEQUENCE webauthn_challenge_id_seq RESTART WITH 101;
@@ -0,0 +1,2 @@
+-- If enable the native SQL review, which reviews the pull requests from the webhook events and comments on them.
+ALTER TABLE repository ADD COLUMN enable_native_sql_review BOOLEAN NOT NULL DEFAULT false;
@@ -10,6 +10,36 @@ BEGIN
 END;
 $$ LANGUAGE plpgsql;
 
+-- notify_cache_invalidation notifies the Bytebase replicas in the high-availability mode to invalidate the cache.
+-- The payload is the table and the JSON array of the trigger argument columns of the changed row, e.g. "environment:["101","prod"]".
+-- Both the old and the new keys are notified if the update changes the key, and the truncation only notifies the table.
+-- The triggers are installed by the replicas in the high-availability mode on startup.
+CREATE OR REPLACE FUNCTION notify_cache_invalidation()
+RETURNS TRIGGER AS $$
+DECLARE
+  old_key JSONB;
+  new_key JSONB;
+BEGIN
+  IF TG_LEVEL = 'STATEMENT' THEN
+    PERFORM pg_notify('bb_cache_invalidation', TG_TABLE_NAME);
+    RETURN NULL;
+  END IF;
+  IF TG_OP IN ('UPDATE', 'DELETE') THEN
+    SELECT COALESCE(jsonb_agg(to_jsonb(OLD) ->> a.arg ORDER BY a.ord), '[]'::JSONB) INTO old_key
+    FROM unnest(TG_ARGV) WITH ORDINALITY AS a(arg, ord);
+    PERFORM pg_notify('bb_cache_invalidation', TG_TABLE_NAME || ':' || old_key::TEXT);
+  END IF;
+  IF TG_OP IN ('INSERT', 'UPDATE') THEN
+    SELECT COALESCE(jsonb_agg(to_jsonb(NEW) ->> a.arg ORDER BY a.ord), '[]'::JSONB) INTO new_key
+    FROM unnest(TG_ARGV) WITH ORDINALITY AS a(arg, ord);
+    IF new_key IS DISTINCT FROM old_key THEN
+      PERFORM pg_notify('bb_cache_invalidation', TG_TABLE_NAME || ':' || new_key::TEXT);
+    END IF;
+  END IF;
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
 -- idp stores generic identity provider.
 CREATE TABLE idp (
   id SERIAL PRIMARY KEY,
@@ -47,7 +77,8 @@ CREATE TABLE principal (
     email TEXT NOT NULL,
     password_hash TEXT NOT NULL,
     phone TEXT NOT NULL DEFAULT '',
-    mfa_config JSONB NOT NULL DEFAULT '{}'
+    mfa_config JSONB NOT NULL DEFAULT '{}',
+    notification_setting JSONB NOT NULL DEFAULT '{}'
 );
 
 CREATE TRIGGER update_principal_updated_ts
@@ -326,7 +357,9 @@ CREATE TABLE project_webhook (
     type TEXT NOT NULL CHECK (type LIKE 'bb.plugin.webhook.%'),
     name TEXT NOT NULL,
     url TEXT NOT NULL,
-    activity_list TEXT ARRAY NOT NULL
+    activity_list TEXT ARRAY NOT NULL,
+    -- signing_secret is the secret for signing the request body of the custom webhooks with HMAC-SHA256.
+    signing_secret TEXT NOT NULL DEFAULT ''
 );
 
 CREATE INDEX idx_project_webhook_project_id ON project_webhook(project_id);
@@ -924,7 +957,7 @@ CREATE TABLE vcs (
     updater_id INTEGER NOT NULL REFERENCES principal (id),
     updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
     name TEXT NOT NULL,
-    type TEXT NOT NULL CHECK (type IN ('GITLAB', 'GITHUB', 'BITBUCKET', 'AZURE_DEVOPS')),
+    type TEXT NOT NULL CHECK (type IN ('GITLAB', 'GITHUB', 'BITBUCKET', 'AZURE_DEVOPS', 'GITEA', 'BITBUCKET_DATA_CENTER')),
     instance_url TEXT NOT NULL CHECK ((instance_url LIKE 'http://%' OR instance_url LIKE 'https://%') AND instance_url = rtrim(instance_url, '/')),
     api_url TEXT NOT NULL CHECK ((api_url LIKE 'http://%' OR api_url LIKE 'https://%') AND api_url = rtrim(api_url, '/')),
     application_id TEXT NOT NULL,
@@ -969,6 +1002,8 @@ CREATE TABLE repository (
     file_path_template TEXT NOT NULL DEFAULT '',
     -- If enable the SQL review CI in VCS repository.
     enable_sql_review_ci BOOLEAN NOT NULL DEFAULT false,
+    -- If enable the native SQL review, which reviews the pull requests from the webhook events and comments on them.
+    enable_native_sql_review BOOLEAN NOT NULL DEFAULT false,
     -- The file path template for storing the latest schema auto-generated by Bytebase after migration.
     -- If empty, then Bytebase won't auto generate it.
     schema_path_template TEXT NOT NULL DEFAULT '',
@@ -1254,4 +1289,257 @@ CREATE TRIGGER update_changelist_updated_ts
 BEFORE
 UPDATE
     ON changelist FOR EACH ROW
-EXECUTE FUNCTION trigger_update_updated_ts();
\ No newline at end of file
+EXECUTE FUNCTION trigger_update_updated_ts();
+
+-- scim_group stores the groups provisioned by the SCIM client such as Okta and Azure AD.
+CREATE TABLE scim_group (
+    id SERIAL PRIMARY KEY,
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    resource_id TEXT NOT NULL,
+    external_id TEXT NOT NULL DEFAULT '',
+    display_name TEXT NOT NULL,
+    -- member_ids stores the principal ids of the group members.
+    member_ids INTEGER[] NOT NULL DEFAULT '{}'
+);
+
+CREATE UNIQUE INDEX idx_scim_group_unique_resource_id ON scim_group(resource_id);
+
+CREATE UNIQUE INDEX idx_scim_group_unique_display_name ON scim_group(display_name);
+
+ALTER SEQUENCE scim_group_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_scim_group_updated_ts
+BEFORE
+UPDATE
+    ON scim_group FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
+
+-- scim_group_binding stores the project IAM policy bindings granted by the SCIM group mappings, so that only
+-- these bindings are revoked when the members leave the groups, not the ones granted manually or by the other
+-- identity providers.
+CREATE TABLE scim_group_binding (
+    -- project is the resource id of the project.
+    project TEXT NOT NULL,
+    -- role is the project role, e.g. roles/DEVELOPER.
+    role TEXT NOT NULL,
+    principal_id INTEGER NOT NULL REFERENCES principal (id),
+    PRIMARY KEY (project, role, principal_id)
+);
+
+-- ldap_group_sync_user stores the users synced from the LDAP identity providers,
+-- so that the mapped roles of the users removed from the directory are revoked.
+CREATE TABLE ldap_group_sync_user (
+    -- idp_id is the resource id of the LDAP identity provider.
+    idp_id TEXT NOT NULL,
+    principal_id INTEGER NOT NULL REFERENCES principal (id),
+    PRIMARY KEY (idp_id, principal_id)
+);
+
+-- password_history stores the password hashes of the end users for the password history and rotation policies.
+CREATE TABLE password_history (
+    id SERIAL PRIMARY KEY,
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    principal_id INTEGER NOT NULL REFERENCES principal (id),
+    password_hash TEXT NOT NULL
+);
+
+CREATE INDEX idx_password_history_principal_id ON password_history(principal_id);
+
+ALTER SEQUENCE password_history_id_seq RESTART WITH 101;
+
+-- login_throttle stores the failed login counters of the accounts and the client IPs.
+CREATE TABLE login_throttle (
+    id SERIAL PRIMARY KEY,
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    type TEXT NOT NULL CHECK (type IN ('ACCOUNT', 'IP')),
+    -- subject is the lower-case email for ACCOUNT and the client IP for IP.
+    subject TEXT NOT NULL,
+    failure_count INTEGER NOT NULL DEFAULT 0,
+    last_failure_ts BIGINT NOT NULL DEFAULT 0,
+    locked_until_ts BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE UNIQUE INDEX idx_login_throttle_unique_type_subject ON login_throttle(type, subject);
+
+ALTER SEQUENCE login_throttle_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_login_throttle_updated_ts
+BEFORE
+UPDATE
+    ON login_throttle FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
+
+-- access_token stores the personal access tokens of the users and service accounts.
+CREATE TABLE access_token (
+    id SERIAL PRIMARY KEY,
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    principal_id INTEGER NOT NULL REFERENCES principal (id),
+    title TEXT NOT NULL,
+    -- token_hash is the hex encoded SHA-256 hash of the token.
+    token_hash TEXT NOT NULL,
+    -- scopes limit the methods that the token can call, e.g. {read, issue.create:projects/hr}.
+    scopes TEXT ARRAY NOT NULL,
+    expire_ts BIGINT NOT NULL,
+    last_used_ts BIGINT NOT NULL DEFAULT 0,
+    last_used_ip TEXT NOT NULL DEFAULT ''
+);
+
+CREATE UNIQUE INDEX idx_access_token_unique_token_hash ON access_token(token_hash);
+
+CREATE INDEX idx_access_token_principal_id ON access_token(principal_id);
+
+ALTER SEQUENCE access_token_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_access_token_updated_ts
+BEFORE
+UPDATE
+    ON access_token FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
+
+-- approval_delegation stores the windows in which the delegates review the approvals on behalf of the delegators.
+CREATE TABLE approval_delegation (
+    id SERIAL PRIMARY KEY,
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    delegator_id INTEGER NOT NULL REFERENCES principal (id),
+    delegate_id INTEGER NOT NULL REFERENCES principal (id),
+    start_ts BIGINT NOT NULL,
+    end_ts BIGINT NOT NULL,
+    reason TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX idx_approval_delegation_delegator_id ON approval_delegation(delegator_id);
+
+CREATE INDEX idx_approval_delegation_delegate_id ON approval_delegation(delegate_id);
+
+ALTER SEQUENCE approval_delegation_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_approval_delegation_updated_ts
+BEFORE
+UPDATE
+    ON approval_delegation FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
+
+-- activity_ledger is the append-only hash chain of the activity records.
+-- A record is appended every time an activity is created or updated, so modifying or deleting the activity rows
+-- out of band breaks the chain. activity_id doesn't reference activity so that the deleted activities are detected.
+-- The records are appended unsealed along with the activities, and chained by the sealing afterwards.
+CREATE TABLE activity_ledger (
+    id BIGSERIAL PRIMARY KEY,
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    activity_id INTEGER NOT NULL,
+    -- content_hash is the hex encoded SHA-256 hash of the activity record.
+    content_hash TEXT NOT NULL,
+    -- hash is the hex encoded HMAC-SHA256 of the hash of the previous record and the content hash, it's NULL until sealed.
+    hash TEXT,
+    -- seq is the position of the record in the hash chain.
+    seq BIGINT
+);
+
+CREATE INDEX idx_activity_ledger_activity_id ON activity_ledger(activity_id);
+
+CREATE UNIQUE INDEX idx_activity_ledger_unique_seq ON activity_ledger(seq);
+
+CREATE INDEX idx_activity_ledger_unsealed ON activity_ledger(id) WHERE seq IS NULL;
+
+-- audit_log_export_cursor stores the last activity ledger record exported to each audit log destination.
+CREATE TABLE audit_log_export_cursor (
+    destination TEXT PRIMARY KEY,
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    ledger_seq BIGINT NOT NULL
+);
+
+CREATE TRIGGER update_audit_log_export_cursor_updated_ts
+BEFORE
+UPDATE
+    ON audit_log_export_cursor FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
+
+-- webhook_delivery is the outbox of the project and workspace webhook events.
+-- The deliveries are retried with exponential backoff, and moved to the DEAD_LETTER status after the max attempts.
+CREATE TABLE webhook_delivery (
+    id BIGSERIAL PRIMARY KEY,
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    project_webhook_id INTEGER REFERENCES project_webhook (id) ON DELETE CASCADE,
+    -- workspace_webhook_id is the ID of the workspace webhook in the bb.workspace.webhook setting.
+    workspace_webhook_id TEXT NOT NULL DEFAULT '',
+    activity_type TEXT NOT NULL,
+    -- payload is the webhook context of the event.
+    payload JSONB NOT NULL DEFAULT '{}',
+    status TEXT NOT NULL CHECK (status IN ('PENDING', 'DELIVERED', 'DEAD_LETTER')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    -- response_code is the HTTP status code of the last attempt, 0 if there is no response.
+    response_code INTEGER NOT NULL DEFAULT 0,
+    -- error is the error of the last attempt.
+    error TEXT NOT NULL DEFAULT '',
+    CONSTRAINT webhook_delivery_target_check CHECK ((project_webhook_id IS NULL) <> (workspace_webhook_id = ''))
+);
+
+CREATE INDEX idx_webhook_delivery_project_webhook_id ON webhook_delivery(project_webhook_id);
+
+CREATE INDEX idx_webhook_delivery_workspace_webhook_id ON webhook_delivery(workspace_webhook_id) WHERE workspace_webhook_id != '';
+
+CREATE INDEX idx_webhook_delivery_pending_next_attempt_ts ON webhook_delivery(next_attempt_ts) WHERE status = 'PENDING';
+
+-- The delivered and dead letter deliveries are pruned by the updated_ts after the retention.
+CREATE INDEX idx_webhook_delivery_done_updated_ts ON webhook_delivery(updated_ts) WHERE status <> 'PENDING';
+
+ALTER SEQUENCE webhook_delivery_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_webhook_delivery_updated_ts
+BEFORE
+UPDATE
+    ON webhook_delivery FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
+
+-- email_notification is the outbox of the issue email notifications, the notifications of a recipient are sent in one digest email.
+CREATE TABLE email_notification (
+    id BIGSERIAL PRIMARY KEY,
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    recipient_id INTEGER NOT NULL REFERENCES principal (id),
+    -- event is the NotificationSetting.Event of the notification.
+    event TEXT NOT NULL,
+    issue_id INTEGER NOT NULL REFERENCES issue (id),
+    -- payload is the content of the notification.
+    payload JSONB NOT NULL DEFAULT '{}',
+    status TEXT NOT NULL CHECK (status IN ('PENDING', 'SENT', 'FAILED')),
+    -- error is the error of the sending.
+    error TEXT NOT NULL DEFAULT '',
+    -- attempts is the number of the sending attempts, the failed notifications are retried until the max attempts.
+    attempts INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX idx_email_notification_pending_recipient_id ON email_notification(recipient_id) WHERE status = 'PENDING';
+
+CREATE INDEX idx_email_notification_issue_id ON email_notification(issue_id);
+
+ALTER SEQUENCE email_notification_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_email_notification_updated_ts
+BEFORE
+UPDATE
+    ON email_notification FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
+
+-- webauthn_challenge stores the issued WebAuthn challenges, each of them can only be consumed once.
+CREATE TABLE webauthn_challenge (
+    id BIGSERIAL PRIMARY KEY,
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    -- challenge is the base64url encoded random challenge.
+    challenge TEXT NOT NULL,
+    -- subject is the ceremony and the user of the challenge, e.g. register:101.
+    subject TEXT NOT NULL,
+    expires_ts BIGINT NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_webauthn_challenge_unique_challenge ON webauthn_challenge(challenge);
+
+CREATE INDEX idx_webauthn_challenge_expires_ts ON webauthn_challenge(expires_ts);
+
+ALTER SEQUENCE webauthn_challenge_id_seq RESTART WITH 101;
@@ -0,0 +1,120 @@
+package externalapproval
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/backend/common/signature"
+)
+
+// httpProvider is the generic HTTP provider for the ticketing systems.
+//
+// The ticketing system, or an adapter in front of it, implements the following APIs:
+//   - POST {endpoint}/change-requests creates a change request with the CreatePayload.
+//   - GET {endpoint}/change-requests/{id} gets the change request.
+//   - POST {endpoint}/change-requests/{id}:cancel cancels the change request.
+//
+// The APIs respond with the ChangeRequest. The requests are signed if the signing secret is set,
+// and the ticketing system posts the ChangeRequest signed with the same secret to the callback URL
+// "{external URL}/hook/external-approval/{node id}" when the change request is approved or rejected.
+type httpProvider struct {
+	client   *http.Client
+	endpoint string
+	secret   string
+}
+
+func newHTTPProvider(endpoint, secret string) *httpProvider {
+	return &httpProvider{
+		client: &http.Client{
+			Timeout: 5 * time.Second,
+		},
+		endpoint: strings.TrimSuffix(endpoint, "/"),
+		secret:   secret,
+	}
+}
+
+// Create creates a change request in the ticketing system.
+func (p *httpProvider) Create(ctx context.Context, payload *CreatePayload) (*ChangeRequest, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	return p.do(ctx, http.MethodPost, fmt.Sprintf("%s/change-requests", p.endpoint), body)
+}
+
+// Get gets the change request from the ticketing system.
+func (p *httpProvider) Get(ctx context.Context, id string) (*ChangeRequest, error) {
+	return p.do(ctx, http.MethodGet, fmt.Sprintf("%s/change-requests/%s", p.endpoint, url.PathEscape(id)), nil)
+}
+
+// Cancel cancels the change request in the ticketing system.
+func (p *httpProvider) Cancel(ctx context.Context, id string) error {
+	_, err := p.do(ctx, http.MethodPost, fmt.Sprintf("%s/change-requests/%s:cancel", p.endpoint, url.PathEscape(id)), nil)
+	return err
+}
+
+// ParseCallback verifies the signature and the timestamp of the callback, and returns the change request in it.
+func (p *httpProvider) ParseCallback(header http.Header, body []byte) (*ChangeRequest, error) {
+	if p.secret == "" {
+		return nil, errors.New("signing secret is required to receive the callbacks")
+	}
+	if err := signature.Verify(p.secret, header.Get(signature.TimestampHeader), header.Get(signature.Header), body, time.Now()); err != nil {
+		return nil, err
+	}
+	changeRequest := &ChangeRequest{}
+	if err := json.Unmarshal(body, changeRequest); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal the change request")
+	}
+	if changeRequest.ID == "" {
+		return nil, errors.New("change request id is required")
+	}
+	switch changeRequest.Status {
+	case StatusPending, StatusApproved, StatusRejected:
+	default:
+		return nil, errors.Errorf("invalid change request status %q", changeRequest.Status)
+	}
+	return changeRequest, nil
+}
+
+func (p *httpProvider) do(ctx context.Context, method, requestURL string, body []byte) (*ChangeRequest, error) {
+	req, err := http.NewRequestWithContext(ctx, method, requestURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if p.secret != "" {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set(signature.TimestampHeader, timestamp)
+		req.Header.Set(signature.Header, signature.Sign(p.secret, timestamp, body))
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, errors.Errorf("failed to %s %s with status %v, body: %s", method, requestURL, resp.Status, respBody)
+	}
+	changeRequest := &ChangeRequest{}
+	if len(respBody) == 0 {
+		return changeRequest, nil
+	}
+	if err := json.Unmarshal(respBody, changeRequest); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal the change request")
+	}
+	return changeRequest, nil
+}
@@ -0,0 +1,144 @@
+package externalapproval
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/backend/common/signature"
+)
+
+func TestHTTPProvider(t *testing.T) {
+	const secret = "test-secret"
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		if err := signature.Verify(secret, r.Header.Get(signature.TimestampHeader), r.Header.Get(signature.Header), body, time.Now()); err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		changeRequest := &ChangeRequest{ID: "CHG0001", Status: StatusPending, URL: "https://tickets.example.com/CHG0001"}
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/change-requests":
+			payload := &CreatePayload{}
+			require.NoError(t, json.Unmarshal(body, payload))
+			require.Equal(t, "101", payload.IssueID)
+		case r.Method == http.MethodGet && r.URL.Path == "/change-requests/CHG0001":
+			changeRequest.Status = StatusApproved
+		case r.Method == http.MethodPost && r.URL.Path == "/change-requests/CHG0001:cancel":
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(changeRequest))
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	provider := newHTTPProvider(server.URL+"/", secret)
+	changeRequest, err := provider.Create(ctx, &CreatePayload{IssueID: "101", Title: "Add column"})
+	require.NoError(t, err)
+	require.Equal(t, &ChangeRequest{ID: "CHG0001", Status: StatusPending, URL: "https://tickets.example.com/CHG0001"}, changeRequest)
+
+	changeRequest, err = provider.Get(ctx, "CHG0001")
+	require.NoError(t, err)
+	require.Equal(t, StatusApproved, changeRequest.Status)
+
+	require.NoError(t, provider.Cancel(ctx, "CHG0001"))
+	require.Equal(t, []string{"POST /change-requests", "GET /change-requests/CHG0001", "POST /change-requests/CHG0001:cancel"}, requests)
+
+	_, err = newHTTPProvider(server.URL, "wrong-secret").Get(ctx, "CHG0001")
+	require.ErrorContains(t, err, "401")
+}
+
+func TestHTTPProviderParseCallback(t *testing.T) {
+	const secret = "test-secret"
+	now := time.Now()
+	body := []byte(`{"id":"CHG0001","status":"REJECTED"}`)
+	newHeader := func(secret string, signedAt time.Time, body []byte) http.Header {
+		timestamp := strconv.FormatInt(signedAt.Unix(), 10)
+		header := http.Header{}
+		header.Set(signature.TimestampHeader, timestamp)
+		header.Set(signature.Header, signature.Sign(secret, timestamp, body))
+		return header
+	}
+
+	tests := []struct {
+		name        string
+		secret      string
+		header      http.Header
+		body        []byte
+		want        *ChangeRequest
+		containsErr string
+	}{
+		{
+			name:   "valid callback",
+			secret: secret,
+			header: newHeader(secret, now, body),
+			body:   body,
+			want:   &ChangeRequest{ID: "CHG0001", Status: StatusRejected},
+		},
+		{
+			name:        "no signing secret",
+			secret:      "",
+			header:      newHeader(secret, now, body),
+			body:        body,
+			containsErr: "signing secret is required",
+		},
+		{
+			name:        "missing headers",
+			secret:      secret,
+			header:      http.Header{},
+			body:        body,
+			containsErr: "headers are required",
+		},
+		{
+			name:        "wrong secret",
+			secret:      secret,
+			header:      newHeader("wrong-secret", now, body),
+			body:        body,
+			containsErr: "signature mismatch",
+		},
+		{
+			name:        "tampered body",
+			secret:      secret,
+			header:      newHeader(secret, now, body),
+			body:        []byte(`{"id":"CHG0001","status":"APPROVED"}`),
+			containsErr: "signature mismatch",
+		},
+		{
+			name:        "replayed callback",
+			secret:      secret,
+			header:      newHeader(secret, now.Add(-time.Hour), body),
+			body:        body,
+			containsErr: "out of the tolerance",
+		},
+		{
+			name:        "invalid status",
+			secret:      secret,
+			header:      newHeader(secret, now, []byte(`{"id":"CHG0001","status":"DONE"}`)),
+			body:        []byte(`{"id":"CHG0001","status":"DONE"}`),
+			containsErr: "invalid change request status",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := newHTTPProvider("https://tickets.example.com", test.secret).ParseCallback(test.header, test.body)
+			if test.containsErr != "" {
+				require.ErrorContains(t, err, test.containsErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.want, got)
+		})
+	}
+}
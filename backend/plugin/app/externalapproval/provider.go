@@ -0,0 +1,74 @@
+// Package externalapproval provides the providers for the external approval nodes.
+// A provider creates a change request in the external system, e.g. the relay service or a ticketing system like ServiceNow and Jira,
+// and reports the approval status of the change request via polling or the signed callbacks.
+package externalapproval
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+
+	storepb "github.com/bytebase/bytebase/proto/generated-go/store"
+)
+
+// Status is the status of the change request.
+type Status string
+
+const (
+	// StatusPending means that the change request is waiting for the approval.
+	StatusPending Status = "PENDING"
+	// StatusApproved means that the change request is approved.
+	StatusApproved Status = "APPROVED"
+	// StatusRejected means that the change request is rejected.
+	StatusRejected Status = "REJECTED"
+)
+
+// CreatePayload is the message to create a change request.
+type CreatePayload struct {
+	IssueID     string    `json:"issueId"`
+	Creator     string    `json:"creator"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	Project     string    `json:"project"`
+	Assignee    string    `json:"assignee"`
+	Statement   string    `json:"statement"`
+	CreateTime  time.Time `json:"createTime"`
+}
+
+// ChangeRequest is the change request in the external system.
+type ChangeRequest struct {
+	// ID is the ID of the change request in the external system, e.g. the ticket ID.
+	ID     string `json:"id"`
+	Status Status `json:"status"`
+	// URL is the link to the change request, it's optional.
+	URL string `json:"url,omitempty"`
+}
+
+// Provider is the interface of the external approval providers.
+type Provider interface {
+	// Create creates a change request for the issue.
+	Create(ctx context.Context, payload *CreatePayload) (*ChangeRequest, error)
+	// Get gets the change request by ID.
+	Get(ctx context.Context, id string) (*ChangeRequest, error)
+	// Cancel cancels the change request, e.g. when the issue is closed.
+	Cancel(ctx context.Context, id string) error
+	// ParseCallback verifies the callback request from the external system, and returns the change request in it.
+	ParseCallback(header http.Header, body []byte) (*ChangeRequest, error)
+}
+
+// Factory creates the provider for the external approval node.
+type Factory func(node *storepb.ExternalApprovalSetting_Node) (Provider, error)
+
+// NewProvider creates the provider by the type of the external approval node.
+func NewProvider(node *storepb.ExternalApprovalSetting_Node) (Provider, error) {
+	switch node.Type {
+	case storepb.ExternalApprovalSetting_Node_TYPE_UNSPECIFIED, storepb.ExternalApprovalSetting_Node_RELAY:
+		return newRelayProvider(node.Endpoint), nil
+	case storepb.ExternalApprovalSetting_Node_HTTP:
+		return newHTTPProvider(node.Endpoint, node.SigningSecret), nil
+	default:
+		return nil, errors.Errorf("unsupported external approval node type %v", node.Type)
+	}
+}
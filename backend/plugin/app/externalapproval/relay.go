@@ -0,0 +1,67 @@
+package externalapproval
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/backend/plugin/app/relay"
+)
+
+// relayProvider is the provider for the relay service, which only supports polling.
+type relayProvider struct {
+	client   *relay.Client
+	endpoint string
+}
+
+func newRelayProvider(endpoint string) *relayProvider {
+	return &relayProvider{
+		client:   relay.NewClient(),
+		endpoint: endpoint,
+	}
+}
+
+// Create creates an external approval in the relay service.
+func (p *relayProvider) Create(_ context.Context, payload *CreatePayload) (*ChangeRequest, error) {
+	id, err := p.client.Create(p.endpoint, &relay.CreatePayload{
+		IssueID:     payload.IssueID,
+		Creator:     payload.Creator,
+		Title:       payload.Title,
+		Description: payload.Description,
+		Project:     payload.Project,
+		Assignee:    payload.Assignee,
+		Statement:   payload.Statement,
+		CreateTime:  payload.CreateTime,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &ChangeRequest{ID: id, Status: StatusPending}, nil
+}
+
+// Get gets the external approval from the relay service.
+func (p *relayProvider) Get(_ context.Context, id string) (*ChangeRequest, error) {
+	resp, err := p.client.GetApproval(p.endpoint, id)
+	if err != nil {
+		return nil, err
+	}
+	status := StatusPending
+	switch resp.Status {
+	case relay.StatusApproved:
+		status = StatusApproved
+	case relay.StatusRejected:
+		status = StatusRejected
+	}
+	return &ChangeRequest{ID: id, Status: status}, nil
+}
+
+// Cancel notifies the relay service that the external approval is no longer needed.
+func (p *relayProvider) Cancel(_ context.Context, id string) error {
+	return p.client.UpdateApproval(p.endpoint, id, &relay.UpdatePayload{})
+}
+
+// ParseCallback is not supported because the relay service is polled.
+func (*relayProvider) ParseCallback(http.Header, []byte) (*ChangeRequest, error) {
+	return nil, errors.New("relay external approval does not support callbacks")
+}
@@ -0,0 +1,69 @@
+// Package auditlog provides the destinations of the audit log streaming export, i.e. syslog, HTTP endpoint and local file.
+package auditlog
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+)
+
+// timeout is the timeout of sending the entries to the remote destinations.
+var timeout = 10 * time.Second
+
+// Entry is the audit log entry exported to the destinations.
+type Entry struct {
+	// LedgerSeq is the position of the record in the hash chain, it increases monotonically.
+	LedgerSeq int64 `json:"ledgerSeq"`
+	// Hash is the hash of the record chained to PrevHash, so that the receiver can verify the continuity of the entries.
+	Hash        string `json:"hash"`
+	PrevHash    string `json:"prevHash"`
+	ContentHash string `json:"contentHash"`
+
+	// Log is the name of the log, e.g. logs/101.
+	Log        string    `json:"log"`
+	CreateTime time.Time `json:"createTime"`
+	UpdateTime time.Time `json:"updateTime"`
+	// Creator is the name of the user, e.g. users/hello@bytebase.com.
+	Creator     string `json:"creator"`
+	Type        string `json:"type"`
+	Level       string `json:"level"`
+	ContainerID int    `json:"containerId"`
+	Comment     string `json:"comment"`
+	// Payload is the JSON payload of the log.
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// Destination is the destination of the audit log export.
+type Destination interface {
+	// Send sends the entries to the destination in order.
+	Send(ctx context.Context, entries []*Entry) error
+	// Close releases the resources held by the destination.
+	Close() error
+}
+
+// NewDestination creates the destination from the setting.
+func NewDestination(destination *api.AuditLogDestination) (Destination, error) {
+	switch destination.Type {
+	case api.AuditLogDestinationSyslog:
+		if destination.Syslog == nil {
+			return nil, errors.Errorf("syslog config is required for destination %q", destination.ID)
+		}
+		return newSyslogDestination(destination.Syslog), nil
+	case api.AuditLogDestinationHTTP:
+		if destination.HTTP == nil {
+			return nil, errors.Errorf("HTTP config is required for destination %q", destination.ID)
+		}
+		return newHTTPDestination(destination.HTTP), nil
+	case api.AuditLogDestinationFile:
+		if destination.File == nil {
+			return nil, errors.Errorf("file config is required for destination %q", destination.ID)
+		}
+		return newFileDestination(destination.File), nil
+	default:
+		return nil, errors.Errorf("unsupported destination type %q", destination.Type)
+	}
+}
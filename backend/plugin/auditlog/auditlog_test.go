@@ -0,0 +1,74 @@
+package auditlog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatSyslogMessage(t *testing.T) {
+	a := require.New(t)
+	entry := &Entry{
+		LedgerSeq:  7,
+		Hash:       "abc",
+		Log:        "logs/101",
+		UpdateTime: time.Date(2023, 11, 6, 8, 30, 0, 0, time.UTC),
+		Type:       "bb.issue.create",
+		Level:      "WARN",
+		Payload:    json.RawMessage(`{"issueName":"hello"}`),
+	}
+	message, err := formatSyslogMessage("bb-host", "bytebase", entry)
+	a.NoError(err)
+
+	prefix := fmt.Sprintf("<36>1 2023-11-06T08:30:00Z bb-host bytebase %d audit - ", os.Getpid())
+	a.True(strings.HasPrefix(message, prefix), message)
+	got := &Entry{}
+	a.NoError(json.Unmarshal([]byte(strings.TrimPrefix(message, prefix)), got))
+	a.Equal(entry.LedgerSeq, got.LedgerSeq)
+	a.Equal(entry.Hash, got.Hash)
+	a.Equal(entry.Log, got.Log)
+	a.JSONEq(string(entry.Payload), string(got.Payload))
+}
+
+func TestFileDestinationRotate(t *testing.T) {
+	a := require.New(t)
+	path := filepath.Join(t.TempDir(), "audit", "audit.log")
+	d := &fileDestination{
+		path:       path,
+		maxSize:    600,
+		maxBackups: 2,
+	}
+	defer d.Close()
+
+	var entries []*Entry
+	for i := 1; i <= 10; i++ {
+		entries = append(entries, &Entry{LedgerSeq: int64(i), Hash: strings.Repeat("0", 64)})
+	}
+	for _, entry := range entries {
+		a.NoError(d.Send(context.Background(), []*Entry{entry}))
+	}
+
+	backups, err := filepath.Glob(path + ".*")
+	a.NoError(err)
+	a.Len(backups, 2)
+
+	content, err := os.ReadFile(path)
+	a.NoError(err)
+	a.NotEmpty(content)
+	info, err := os.Stat(path)
+	a.NoError(err)
+	a.LessOrEqual(info.Size(), d.maxSize)
+
+	// The last entry is in the current file.
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	last := &Entry{}
+	a.NoError(json.Unmarshal([]byte(lines[len(lines)-1]), last))
+	a.Equal(int64(10), last.LedgerSeq)
+}
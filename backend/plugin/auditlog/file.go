@@ -0,0 +1,136 @@
+package auditlog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+)
+
+const (
+	defaultFileMaxSizeMB = 100
+	// rotatedFileTimeFormat is the timestamp suffix of the rotated files, e.g. audit.log.20231106T150405.000000000.
+	rotatedFileTimeFormat = "20060102T150405.000000000"
+)
+
+type fileDestination struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+	file       *os.File
+	size       int64
+}
+
+func newFileDestination(config *api.AuditLogFileDestination) *fileDestination {
+	maxSizeMB := config.MaxSizeMB
+	if maxSizeMB <= 0 {
+		maxSizeMB = defaultFileMaxSizeMB
+	}
+	return &fileDestination{
+		path:       config.Path,
+		maxSize:    int64(maxSizeMB) * 1024 * 1024,
+		maxBackups: config.MaxBackups,
+	}
+}
+
+// Send appends the entries to the file as JSON lines, and rotates the file once it exceeds the max size.
+func (d *fileDestination) Send(_ context.Context, entries []*Entry) error {
+	if err := d.open(); err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		b, err := json.Marshal(entry)
+		if err != nil {
+			return errors.Wrapf(err, "failed to marshal audit log entry %d", entry.LedgerSeq)
+		}
+		b = append(b, '\n')
+		if d.size > 0 && d.size+int64(len(b)) > d.maxSize {
+			if err := d.rotate(); err != nil {
+				return err
+			}
+		}
+		n, err := d.file.Write(b)
+		d.size += int64(n)
+		if err != nil {
+			return errors.Wrapf(err, "failed to write to %s", d.path)
+		}
+	}
+	return d.file.Sync()
+}
+
+// Close closes the file.
+func (d *fileDestination) Close() error {
+	if d.file == nil {
+		return nil
+	}
+	err := d.file.Close()
+	d.file = nil
+	return err
+}
+
+func (d *fileDestination) open() error {
+	if d.file != nil {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(d.path), 0755); err != nil {
+		return errors.Wrapf(err, "failed to create directory for %s", d.path)
+	}
+	file, err := os.OpenFile(d.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return errors.Wrapf(err, "failed to open %s", d.path)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return errors.Wrapf(err, "failed to stat %s", d.path)
+	}
+	d.file = file
+	d.size = info.Size()
+	return nil
+}
+
+// rotate renames the current file with the timestamp suffix, opens a new file and removes the oldest backups.
+func (d *fileDestination) rotate() error {
+	if err := d.Close(); err != nil {
+		return errors.Wrapf(err, "failed to close %s", d.path)
+	}
+	rotated := fmt.Sprintf("%s.%s", d.path, time.Now().UTC().Format(rotatedFileTimeFormat))
+	if err := os.Rename(d.path, rotated); err != nil {
+		return errors.Wrapf(err, "failed to rotate %s", d.path)
+	}
+	if err := d.open(); err != nil {
+		return err
+	}
+	if d.maxBackups <= 0 {
+		return nil
+	}
+	backups, err := filepath.Glob(d.path + ".*")
+	if err != nil {
+		return errors.Wrapf(err, "failed to list backups of %s", d.path)
+	}
+	var matched []string
+	for _, backup := range backups {
+		if _, err := time.Parse(rotatedFileTimeFormat, strings.TrimPrefix(backup, d.path+".")); err == nil {
+			matched = append(matched, backup)
+		}
+	}
+	if len(matched) <= d.maxBackups {
+		return nil
+	}
+	// The timestamp suffix sorts in the time order.
+	sort.Strings(matched)
+	for _, backup := range matched[:len(matched)-d.maxBackups] {
+		if err := os.Remove(backup); err != nil {
+			return errors.Wrapf(err, "failed to remove backup %s", backup)
+		}
+	}
+	return nil
+}
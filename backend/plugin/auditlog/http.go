@@ -0,0 +1,61 @@
+package auditlog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/pkg/errors"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+)
+
+type httpDestination struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+}
+
+func newHTTPDestination(config *api.AuditLogHTTPDestination) *httpDestination {
+	return &httpDestination{
+		url:     config.URL,
+		headers: config.Headers,
+		client:  &http.Client{Timeout: timeout},
+	}
+}
+
+// Send posts the entries as a JSON array.
+func (d *httpDestination) Send(ctx context.Context, entries []*Entry) error {
+	body, err := json.Marshal(entries)
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal audit log entries")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.url, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrapf(err, "failed to construct POST %s", d.url)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range d.headers {
+		req.Header.Set(key, value)
+	}
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "failed to POST %s", d.url)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		b, err := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		if err != nil {
+			return errors.Wrapf(err, "failed to read response body of POST %s", d.url)
+		}
+		return errors.Errorf("failed to POST %s, status code: %d, response body: %s", d.url, resp.StatusCode, b)
+	}
+	return nil
+}
+
+// Close is a no-op for the HTTP destination.
+func (*httpDestination) Close() error {
+	return nil
+}
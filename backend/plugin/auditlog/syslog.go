@@ -0,0 +1,119 @@
+package auditlog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+)
+
+const (
+	defaultSyslogAppName = "bytebase"
+	// syslogFacility is the security/authorization messages (4), i.e. auth.
+	syslogFacility = 4
+	// syslogMessageID is the MSGID of the syslog messages.
+	syslogMessageID = "audit"
+)
+
+type syslogDestination struct {
+	network string
+	address string
+	appName string
+	conn    net.Conn
+}
+
+func newSyslogDestination(config *api.AuditLogSyslogDestination) *syslogDestination {
+	appName := config.AppName
+	if appName == "" {
+		appName = defaultSyslogAppName
+	}
+	return &syslogDestination{
+		network: config.Network,
+		address: config.Address,
+		appName: appName,
+	}
+}
+
+// Send sends each entry as a syslog message in RFC 5424 format.
+func (d *syslogDestination) Send(ctx context.Context, entries []*Entry) error {
+	if d.conn == nil {
+		dialer := &net.Dialer{Timeout: timeout}
+		conn, err := dialer.DialContext(ctx, d.network, d.address)
+		if err != nil {
+			return errors.Wrapf(err, "failed to connect to syslog server %s", d.address)
+		}
+		d.conn = conn
+	}
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "-"
+	}
+	for _, entry := range entries {
+		message, err := formatSyslogMessage(hostname, d.appName, entry)
+		if err != nil {
+			return err
+		}
+		// The messages over TCP are framed with the octet counting in RFC 6587.
+		if d.network == "tcp" {
+			message = fmt.Sprintf("%d %s", len(message), message)
+		}
+		if err := d.conn.SetWriteDeadline(time.Now().Add(timeout)); err != nil {
+			return err
+		}
+		if _, err := d.conn.Write([]byte(message)); err != nil {
+			// Reconnect in the next round.
+			d.conn.Close()
+			d.conn = nil
+			return errors.Wrapf(err, "failed to write to syslog server %s", d.address)
+		}
+	}
+	return nil
+}
+
+// Close closes the connection to the syslog server.
+func (d *syslogDestination) Close() error {
+	if d.conn == nil {
+		return nil
+	}
+	err := d.conn.Close()
+	d.conn = nil
+	return err
+}
+
+// formatSyslogMessage formats the entry as an RFC 5424 syslog message with the JSON entry as MSG.
+func formatSyslogMessage(hostname, appName string, entry *Entry) (string, error) {
+	msg, err := json.Marshal(entry)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to marshal audit log entry %d", entry.LedgerSeq)
+	}
+	pri := syslogFacility*8 + getSyslogSeverity(entry.Level)
+	// <PRI>VERSION TIMESTAMP HOSTNAME APP-NAME PROCID MSGID STRUCTURED-DATA MSG
+	// The ledger ID and the hash are in the JSON entry, so there is no structured data.
+	return fmt.Sprintf("<%d>1 %s %s %s %d %s - %s",
+		pri,
+		entry.UpdateTime.UTC().Format(time.RFC3339Nano),
+		hostname,
+		appName,
+		os.Getpid(),
+		syslogMessageID,
+		msg,
+	), nil
+}
+
+func getSyslogSeverity(level string) int {
+	switch api.ActivityLevel(level) {
+	case api.ActivityError:
+		return 3
+	case api.ActivityWarn:
+		return 4
+	default:
+		// Informational.
+		return 6
+	}
+}
@@ -486,6 +486,9 @@ func Open(ctx context.Context, dbType storepb.Engine, driverConfig DriverConfig,
 
 // ExecuteOptions is the options for execute.
 type ExecuteOptions struct {
-	BeginFunc          func(ctx context.Context, conn *sql.Conn) error
-	EndTransactionFunc func(tx *sql.Tx) error
+	BeginFunc func(ctx context.Context, conn *sql.Conn) error
+	// BeginTransactionFunc is called in the transaction before executing the statement.
+	// It is only supported by PostgreSQL now.
+	BeginTransactionFunc func(tx *sql.Tx) error
+	EndTransactionFunc   func(tx *sql.Tx) error
 }
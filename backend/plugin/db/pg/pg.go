@@ -261,7 +261,7 @@ func (driver *Driver) getPGStatStatementsVersion(ctx context.Context) (string, e
 
 // Execute will execute the statement. For CREATE DATABASE statement, some types of databases such as Postgres
 // will not use transactions to execute the statement but will still use transactions to execute the rest of statements.
-func (driver *Driver) Execute(ctx context.Context, statement string, createDatabase bool, _ db.ExecuteOptions) (int64, error) {
+func (driver *Driver) Execute(ctx context.Context, statement string, createDatabase bool, opts db.ExecuteOptions) (int64, error) {
 	if createDatabase {
 		databases, err := driver.getDatabases(ctx)
 		if err != nil {
@@ -336,6 +336,12 @@ func (driver *Driver) Execute(ctx context.Context, statement string, createDatab
 		}
 		defer tx.Rollback()
 
+		if opts.BeginTransactionFunc != nil {
+			if err := opts.BeginTransactionFunc(tx); err != nil {
+				return 0, errors.Wrapf(err, "failed to execute beginTransactionFunc")
+			}
+		}
+
 		// Set the current transaction role to the database owner so that the owner of created database will be the same as the database owner.
 		if _, err := tx.ExecContext(ctx, fmt.Sprintf("SET LOCAL ROLE '%s'", owner)); err != nil {
 			return 0, err
@@ -0,0 +1,310 @@
+package pg
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v4"
+	pgquery "github.com/pganalyze/pg_query_go/v4"
+	"github.com/pkg/errors"
+)
+
+// ErrExceedRowLimit is the error returned when the data change affects more rows than the pre-image row limit.
+type ErrExceedRowLimit struct {
+	Limit int
+}
+
+// Error implements the error interface.
+func (e ErrExceedRowLimit) Error() string {
+	return fmt.Sprintf("the statements affect more than %d rows", e.Limit)
+}
+
+// IsErrExceedRowLimit checks if the underlying error is ErrExceedRowLimit.
+func IsErrExceedRowLimit(err error) bool {
+	_, ok := errors.Cause(err).(ErrExceedRowLimit)
+	return ok
+}
+
+// rollbackSavepoint is the savepoint isolating the pre-image queries from the migration transaction.
+const rollbackSavepoint = "bb_rollback_pre_image"
+
+// preImage is the snapshot of the rows affected by a single UPDATE or DELETE statement.
+type preImage struct {
+	// table is the quoted table name, which is schema qualified except for the INSERT statement.
+	table string
+	// primaryKeys are the primary key columns of the table.
+	primaryKeys []string
+	// updatedColumns are the columns assigned by the UPDATE statement, it is empty for the DELETE statement.
+	updatedColumns []string
+	isDelete       bool
+	// isInsert is true for the INSERT statement, which is skipped because the inserted rows are unknown before executing it.
+	isInsert bool
+	// rows are the affected rows in the JSON format produced by to_jsonb().
+	rows []string
+}
+
+// GenerateRollbackSQL captures the pre-image of the rows affected by the UPDATE and DELETE statements,
+// and generates the statements restoring them. It must be called in the migration transaction BEFORE
+// executing the statement, and the affected rows are locked by SELECT ... FOR UPDATE until the transaction ends.
+// The pre-images are captured against the data before the whole statement runs, so the affected rows
+// of a statement depending on the changes of the former statements may be inaccurate.
+// The INSERT statements are skipped with a comment in the rollback SQL.
+// It returns ErrExceedRowLimit if the statements affect more than rowLimit rows.
+//
+// The queries run in a savepoint, so the migration transaction can continue if the generation fails.
+func GenerateRollbackSQL(ctx context.Context, tx *sql.Tx, statement string, rowLimit int) (string, error) {
+	tree, err := pgquery.Parse(statement)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to parse statement")
+	}
+
+	if _, err := tx.ExecContext(ctx, "SAVEPOINT "+rollbackSavepoint); err != nil {
+		return "", err
+	}
+	preImages, err := capturePreImages(ctx, tx, tree.Stmts, rowLimit)
+	if err != nil {
+		if _, rollbackErr := tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+rollbackSavepoint); rollbackErr != nil {
+			return "", errors.Wrapf(rollbackErr, "failed to roll back to savepoint after error: %v", err)
+		}
+		return "", err
+	}
+	if _, err := tx.ExecContext(ctx, "RELEASE SAVEPOINT "+rollbackSavepoint); err != nil {
+		return "", err
+	}
+	return generateRollbackSQLFromPreImages(preImages), nil
+}
+
+func capturePreImages(ctx context.Context, tx *sql.Tx, stmts []*pgquery.RawStmt, rowLimit int) ([]*preImage, error) {
+	var preImages []*preImage
+	total := 0
+	for _, stmt := range stmts {
+		var image *preImage
+		var err error
+		switch node := stmt.Stmt.Node.(type) {
+		case *pgquery.Node_UpdateStmt:
+			image, err = capturePreImage(ctx, tx, node.UpdateStmt.Relation, node.UpdateStmt.FromClause, node.UpdateStmt.WhereClause, node.UpdateStmt.WithClause, rowLimit-total)
+			if err != nil {
+				return nil, err
+			}
+			for _, target := range node.UpdateStmt.TargetList {
+				if resTarget := target.GetResTarget(); resTarget != nil {
+					image.updatedColumns = append(image.updatedColumns, resTarget.Name)
+				}
+			}
+			if err := validateUpdatePreImage(image); err != nil {
+				return nil, err
+			}
+		case *pgquery.Node_DeleteStmt:
+			image, err = capturePreImage(ctx, tx, node.DeleteStmt.Relation, node.DeleteStmt.UsingClause, node.DeleteStmt.WhereClause, node.DeleteStmt.WithClause, rowLimit-total)
+			if err != nil {
+				return nil, err
+			}
+			image.isDelete = true
+		case *pgquery.Node_InsertStmt:
+			if node.InsertStmt.Relation == nil {
+				return nil, errors.Errorf("missing target table")
+			}
+			image = &preImage{table: getRelationTable(node.InsertStmt.Relation), isInsert: true}
+		case *pgquery.Node_SelectStmt, *pgquery.Node_VariableSetStmt:
+			continue
+		default:
+			return nil, errors.Errorf("rollback SQL generation only supports INSERT, UPDATE and DELETE statements")
+		}
+		total += len(image.rows)
+		if total > rowLimit {
+			return nil, ErrExceedRowLimit{Limit: rowLimit}
+		}
+		preImages = append(preImages, image)
+	}
+	return preImages, nil
+}
+
+func getRelationTable(relation *pgquery.RangeVar) string {
+	if relation.Schemaname != "" {
+		return pgx.Identifier{relation.Schemaname, relation.Relname}.Sanitize()
+	}
+	return pgx.Identifier{relation.Relname}.Sanitize()
+}
+
+// capturePreImage captures and locks the rows affected by the UPDATE or DELETE statement.
+// It captures at most rowLimit + 1 rows, so the caller can tell if the rows exceed the limit.
+func capturePreImage(ctx context.Context, tx *sql.Tx, relation *pgquery.RangeVar, fromClause []*pgquery.Node, whereClause *pgquery.Node, withClause *pgquery.WithClause, rowLimit int) (*preImage, error) {
+	if relation == nil {
+		return nil, errors.Errorf("missing target table")
+	}
+	table := getRelationTable(relation)
+	schemaName, tableName, primaryKeys, err := getTablePrimaryKeys(ctx, tx, table)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get primary keys of table %s", table)
+	}
+
+	query, err := getPreImageQuery(relation, fromClause, whereClause, withClause, rowLimit+1)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := tx.QueryContext(ctx, query)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to query the pre-image of table %s", table)
+	}
+	defer rows.Close()
+
+	image := &preImage{
+		table:       pgx.Identifier{schemaName, tableName}.Sanitize(),
+		primaryKeys: primaryKeys,
+	}
+	// UPDATE ... FROM and DELETE ... USING may produce the same row multiple times.
+	seen := make(map[string]bool)
+	for rows.Next() {
+		var row string
+		if err := rows.Scan(&row); err != nil {
+			return nil, err
+		}
+		if seen[row] {
+			continue
+		}
+		seen[row] = true
+		image.rows = append(image.rows, row)
+		if len(image.rows) > rowLimit {
+			break
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return image, nil
+}
+
+// getPreImageQuery returns the query locking and selecting the rows affected by the UPDATE or DELETE statement:
+// SELECT to_jsonb(<table>) FROM <table>, <from> WHERE <where> LIMIT <limit> FOR UPDATE OF <table>.
+// The statements with data-modifying WITH queries are rejected, because the pre-image query would run them once more.
+func getPreImageQuery(relation *pgquery.RangeVar, fromClause []*pgquery.Node, whereClause *pgquery.Node, withClause *pgquery.WithClause, limit int) (string, error) {
+	for _, cte := range withClause.GetCtes() {
+		switch cte.GetCommonTableExpr().GetCtequery().GetNode().(type) {
+		case *pgquery.Node_InsertStmt, *pgquery.Node_UpdateStmt, *pgquery.Node_DeleteStmt, *pgquery.Node_MergeStmt:
+			return "", errors.Errorf("rollback SQL generation doesn't support the data-modifying WITH query %q", cte.GetCommonTableExpr().GetCtename())
+		}
+	}
+	reference := relation.Relname
+	if relation.Alias != nil && relation.Alias.Aliasname != "" {
+		reference = relation.Alias.Aliasname
+	}
+	selectStmt := &pgquery.SelectStmt{
+		TargetList: []*pgquery.Node{
+			pgquery.MakeResTargetNodeWithVal(
+				pgquery.MakeFuncCallNode(
+					[]*pgquery.Node{pgquery.MakeStrNode("to_jsonb")},
+					[]*pgquery.Node{pgquery.MakeColumnRefNode([]*pgquery.Node{pgquery.MakeStrNode(reference)}, 0)},
+					0,
+				),
+				0,
+			),
+		},
+		FromClause:  append([]*pgquery.Node{{Node: &pgquery.Node_RangeVar{RangeVar: relation}}}, fromClause...),
+		WhereClause: whereClause,
+		WithClause:  withClause,
+		LimitCount:  pgquery.MakeAConstIntNode(int64(limit), 0),
+		LimitOption: pgquery.LimitOption_LIMIT_OPTION_COUNT,
+		LockingClause: []*pgquery.Node{
+			{Node: &pgquery.Node_LockingClause{LockingClause: &pgquery.LockingClause{
+				LockedRels: []*pgquery.Node{{Node: &pgquery.Node_RangeVar{RangeVar: &pgquery.RangeVar{Relname: reference, Inh: true, Relpersistence: "p"}}}},
+				Strength:   pgquery.LockClauseStrength_LCS_FORUPDATE,
+				WaitPolicy: pgquery.LockWaitPolicy_LockWaitBlock,
+			}}},
+		},
+		Op: pgquery.SetOperation_SETOP_NONE,
+	}
+	query, err := pgquery.Deparse(&pgquery.ParseResult{
+		Stmts: []*pgquery.RawStmt{{Stmt: &pgquery.Node{Node: &pgquery.Node_SelectStmt{SelectStmt: selectStmt}}}},
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "failed to deparse the pre-image query")
+	}
+	return query, nil
+
+}
+
+// getTablePrimaryKeys returns the schema name, table name and the primary key columns of the table.
+func getTablePrimaryKeys(ctx context.Context, tx *sql.Tx, table string) (string, string, []string, error) {
+	var schemaName, tableName string
+	if err := tx.QueryRowContext(ctx, `
+		SELECT n.nspname, c.relname
+		FROM pg_class c JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE c.oid = $1::regclass`, table).Scan(&schemaName, &tableName); err != nil {
+		return "", "", nil, err
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT a.attname
+		FROM pg_index i JOIN pg_attribute a ON a.attrelid = i.indrelid AND a.attnum = ANY(i.indkey)
+		WHERE i.indrelid = $1::regclass AND i.indisprimary
+		ORDER BY a.attnum`, table)
+	if err != nil {
+		return "", "", nil, err
+	}
+	defer rows.Close()
+	var primaryKeys []string
+	for rows.Next() {
+		var column string
+		if err := rows.Scan(&column); err != nil {
+			return "", "", nil, err
+		}
+		primaryKeys = append(primaryKeys, column)
+	}
+	if err := rows.Err(); err != nil {
+		return "", "", nil, err
+	}
+	return schemaName, tableName, primaryKeys, nil
+}
+
+func validateUpdatePreImage(image *preImage) error {
+	if len(image.rows) == 0 {
+		return nil
+	}
+	if len(image.primaryKeys) == 0 {
+		return errors.Errorf("cannot generate rollback SQL for UPDATE on table %s without primary key", image.table)
+	}
+	for _, column := range image.updatedColumns {
+		for _, primaryKey := range image.primaryKeys {
+			if column == primaryKey {
+				return errors.Errorf("cannot generate rollback SQL for UPDATE changing the primary key column %q of table %s", column, image.table)
+			}
+		}
+	}
+	return nil
+}
+
+// generateRollbackSQLFromPreImages generates the rollback SQL statements for the pre-images in the reversed order.
+func generateRollbackSQLFromPreImages(preImages []*preImage) string {
+	var buf strings.Builder
+	for i := len(preImages) - 1; i >= 0; i-- {
+		image := preImages[i]
+		if image.isInsert {
+			_, _ = fmt.Fprintf(&buf, "-- Skipped rolling back the INSERT INTO %s, the inserted rows are not deleted.\n", image.table)
+			continue
+		}
+		for _, row := range image.rows {
+			record := fmt.Sprintf("jsonb_populate_record(NULL::%s, %s)", image.table, quoteLiteral(row))
+			if image.isDelete {
+				_, _ = fmt.Fprintf(&buf, "INSERT INTO %s SELECT * FROM %s;\n", image.table, record)
+				continue
+			}
+			var sets, conditions []string
+			for _, column := range image.updatedColumns {
+				quoted := pgx.Identifier{column}.Sanitize()
+				sets = append(sets, fmt.Sprintf("%s = o.%s", quoted, quoted))
+			}
+			for _, column := range image.primaryKeys {
+				quoted := pgx.Identifier{column}.Sanitize()
+				conditions = append(conditions, fmt.Sprintf("t.%s = o.%s", quoted, quoted))
+			}
+			_, _ = fmt.Fprintf(&buf, "UPDATE %s AS t SET %s FROM %s AS o WHERE %s;\n", image.table, strings.Join(sets, ", "), record, strings.Join(conditions, " AND "))
+		}
+	}
+	return buf.String()
+}
+
+func quoteLiteral(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
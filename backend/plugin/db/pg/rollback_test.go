@@ -0,0 +1,145 @@
+package pg
+
+import (
+	"testing"
+
+	pgquery "github.com/pganalyze/pg_query_go/v4"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateRollbackSQLFromPreImages(t *testing.T) {
+	tests := []struct {
+		name      string
+		preImages []*preImage
+		want      string
+	}{
+		{
+			name:      "empty",
+			preImages: nil,
+			want:      "",
+		},
+		{
+			name: "DELETE",
+			preImages: []*preImage{
+				{
+					table:       `"public"."user"`,
+					primaryKeys: []string{"id"},
+					isDelete:    true,
+					rows:        []string{`{"id": 1, "name": "alice"}`, `{"id": 2, "name": "o'neil"}`},
+				},
+			},
+			want: `INSERT INTO "public"."user" SELECT * FROM jsonb_populate_record(NULL::"public"."user", '{"id": 1, "name": "alice"}');
+INSERT INTO "public"."user" SELECT * FROM jsonb_populate_record(NULL::"public"."user", '{"id": 2, "name": "o''neil"}');
+`,
+		},
+		{
+			name: "UPDATE then DELETE in reversed order",
+			preImages: []*preImage{
+				{
+					table:          `"public"."user"`,
+					primaryKeys:    []string{"id", "Tenant"},
+					updatedColumns: []string{"name", "age"},
+					rows:           []string{`{"id": 1, "Tenant": 1, "name": "alice", "age": 20}`},
+				},
+				{
+					table:       `"public"."order"`,
+					primaryKeys: []string{"id"},
+					isDelete:    true,
+					rows:        []string{`{"id": 3}`},
+				},
+			},
+			want: `INSERT INTO "public"."order" SELECT * FROM jsonb_populate_record(NULL::"public"."order", '{"id": 3}');
+UPDATE "public"."user" AS t SET "name" = o."name", "age" = o."age" FROM jsonb_populate_record(NULL::"public"."user", '{"id": 1, "Tenant": 1, "name": "alice", "age": 20}') AS o WHERE t."id" = o."id" AND t."Tenant" = o."Tenant";
+`,
+		},
+		{
+			name: "INSERT is skipped",
+			preImages: []*preImage{
+				{
+					table:    `"user"`,
+					isInsert: true,
+				},
+				{
+					table:       `"public"."user"`,
+					primaryKeys: []string{"id"},
+					isDelete:    true,
+					rows:        []string{`{"id": 1}`},
+				},
+			},
+			want: `INSERT INTO "public"."user" SELECT * FROM jsonb_populate_record(NULL::"public"."user", '{"id": 1}');
+-- Skipped rolling back the INSERT INTO "user", the inserted rows are not deleted.
+`,
+		},
+	}
+
+	a := require.New(t)
+	for _, test := range tests {
+		got := generateRollbackSQLFromPreImages(test.preImages)
+		a.Equal(test.want, got, test.name)
+	}
+}
+
+func TestValidateUpdatePreImage(t *testing.T) {
+	a := require.New(t)
+	a.NoError(validateUpdatePreImage(&preImage{table: `"public"."t"`}))
+	a.Error(validateUpdatePreImage(&preImage{table: `"public"."t"`, updatedColumns: []string{"a"}, rows: []string{`{}`}}))
+	a.Error(validateUpdatePreImage(&preImage{table: `"public"."t"`, primaryKeys: []string{"id"}, updatedColumns: []string{"id"}, rows: []string{`{}`}}))
+	a.NoError(validateUpdatePreImage(&preImage{table: `"public"."t"`, primaryKeys: []string{"id"}, updatedColumns: []string{"a"}, rows: []string{`{}`}}))
+}
+
+func TestGetPreImageQuery(t *testing.T) {
+	tests := []struct {
+		statement string
+		want      string
+		wantErr   bool
+	}{
+		{
+			statement: "UPDATE t SET a = 1 WHERE id > 10",
+			want:      "SELECT to_jsonb(t) FROM t WHERE id > 10 LIMIT 101 FOR UPDATE OF t",
+		},
+		{
+			statement: "UPDATE public.t AS x SET a = y.a FROM y WHERE x.id = y.id",
+			want:      "SELECT to_jsonb(x) FROM public.t x, y WHERE x.id = y.id LIMIT 101 FOR UPDATE OF x",
+		},
+		{
+			statement: "DELETE FROM t USING y WHERE t.id = y.id",
+			want:      "SELECT to_jsonb(t) FROM t, y WHERE t.id = y.id LIMIT 101 FOR UPDATE OF t",
+		},
+		{
+			statement: "WITH y AS (SELECT id FROM z) DELETE FROM t WHERE t.id IN (SELECT id FROM y)",
+			want:      "WITH y AS (SELECT id FROM z) SELECT to_jsonb(t) FROM t WHERE t.id IN (SELECT id FROM y) LIMIT 101 FOR UPDATE OF t",
+		},
+		{
+			// The data-modifying WITH query would run twice.
+			statement: "WITH d AS (DELETE FROM z RETURNING id) UPDATE t SET a = 1 WHERE id IN (SELECT id FROM d)",
+			wantErr:   true,
+		},
+	}
+
+	a := require.New(t)
+	for _, test := range tests {
+		tree, err := pgquery.Parse(test.statement)
+		a.NoError(err)
+		var got string
+		switch node := tree.Stmts[0].Stmt.Node.(type) {
+		case *pgquery.Node_UpdateStmt:
+			got, err = getPreImageQuery(node.UpdateStmt.Relation, node.UpdateStmt.FromClause, node.UpdateStmt.WhereClause, node.UpdateStmt.WithClause, 101)
+		case *pgquery.Node_DeleteStmt:
+			got, err = getPreImageQuery(node.DeleteStmt.Relation, node.DeleteStmt.UsingClause, node.DeleteStmt.WhereClause, node.DeleteStmt.WithClause, 101)
+		}
+		if test.wantErr {
+			a.Error(err, test.statement)
+			continue
+		}
+		a.NoError(err)
+		a.Equal(test.want, got, test.statement)
+	}
+}
+
+func TestErrExceedRowLimit(t *testing.T) {
+	a := require.New(t)
+	err := errors.Wrap(ErrExceedRowLimit{Limit: 1000}, "failed to generate rollback SQL")
+	a.True(IsErrExceedRowLimit(err))
+	a.Equal("failed to generate rollback SQL: the statements affect more than 1000 rows", err.Error())
+}
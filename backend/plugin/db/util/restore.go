@@ -23,6 +23,14 @@ func GetPITROldDatabaseName(database string, suffixTs int64) string {
 	return GetSafeName(database, suffix)
 }
 
+// GetShadowDatabaseName composes a shadow database name that we use as the target database for dry-running migrations.
+// The random suffix avoids the concurrent dry runs of the same database colliding.
+// For example, GetShadowDatabaseName("dbfoo", 1653018005, "x7k2m9qa") -> "dbfoo_shadow_1653018005_x7k2m9qa".
+func GetShadowDatabaseName(database string, suffixTs int64, random string) string {
+	suffix := fmt.Sprintf("shadow_%d_%s", suffixTs, random)
+	return GetSafeName(database, suffix)
+}
+
 // GetSafeName trims the name according to max allowed database name length.
 func GetSafeName(baseName, suffix string) string {
 	name := fmt.Sprintf("%s_%s", baseName, suffix)
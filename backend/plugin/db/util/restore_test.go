@@ -0,0 +1,17 @@
+package util
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetShadowDatabaseName(t *testing.T) {
+	a := require.New(t)
+	a.Equal("dbfoo_shadow_1653018005_x7k2m9qa", GetShadowDatabaseName("dbfoo", 1653018005, "x7k2m9qa"))
+
+	name := GetShadowDatabaseName(strings.Repeat("a", 64), 1653018005, "x7k2m9qa")
+	a.Len(name, MaxDatabaseNameLength)
+	a.True(strings.HasSuffix(name, "_shadow_1653018005_x7k2m9qa"))
+}
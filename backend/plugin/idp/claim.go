@@ -0,0 +1,129 @@
+package idp
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// claimPathSegment is a segment of the claim path, either a key or an array index.
+// The wildcard matches all the elements of an array or all the values of an object.
+type claimPathSegment struct {
+	key      string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+// ValidateClaimPath validates the JSONPath-like claim selector.
+func ValidateClaimPath(path string) error {
+	_, err := parseClaimPath(path)
+	return err
+}
+
+// parseClaimPath parses the JSONPath-like claim selector, e.g. "groups", "realm_access.roles",
+// "$.resource_access.bytebase.roles[*]" and "$['https://example.com/roles'][0]".
+func parseClaimPath(path string) ([]claimPathSegment, error) {
+	p := strings.TrimSpace(path)
+	p = strings.TrimPrefix(p, "$")
+	p = strings.TrimPrefix(p, ".")
+	if p == "" {
+		return nil, errors.Errorf("empty claim path %q", path)
+	}
+
+	var segments []claimPathSegment
+	for len(p) > 0 {
+		switch p[0] {
+		case '.':
+			p = p[1:]
+			if p == "" || p[0] == '.' || p[0] == '[' {
+				return nil, errors.Errorf("invalid claim path %q", path)
+			}
+		case '[':
+			end := strings.IndexByte(p, ']')
+			if end < 0 {
+				return nil, errors.Errorf("unclosed bracket in claim path %q", path)
+			}
+			inner := strings.TrimSpace(p[1:end])
+			if len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0] {
+				// The quoted key may contain dots and brackets but not the closing bracket itself.
+				segments = append(segments, claimPathSegment{key: inner[1 : len(inner)-1]})
+			} else if inner == "*" {
+				segments = append(segments, claimPathSegment{wildcard: true})
+			} else {
+				index, err := strconv.Atoi(inner)
+				if err != nil || index < 0 {
+					return nil, errors.Errorf("invalid index %q in claim path %q", inner, path)
+				}
+				segments = append(segments, claimPathSegment{index: index, isIndex: true})
+			}
+			p = p[end+1:]
+		default:
+			end := strings.IndexAny(p, ".[")
+			if end < 0 {
+				end = len(p)
+			}
+			if key := p[:end]; key == "*" {
+				segments = append(segments, claimPathSegment{wildcard: true})
+			} else {
+				segments = append(segments, claimPathSegment{key: key})
+			}
+			p = p[end:]
+		}
+	}
+	return segments, nil
+}
+
+// GetClaimValues returns the string values selected by the claim path. The selected arrays
+// are flattened, and the numbers and booleans are formatted as strings.
+func GetClaimValues(claims map[string]any, path string) ([]string, error) {
+	segments, err := parseClaimPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := []any{claims}
+	for _, segment := range segments {
+		var next []any
+		for _, node := range nodes {
+			switch v := node.(type) {
+			case map[string]any:
+				if segment.wildcard {
+					for _, value := range v {
+						next = append(next, value)
+					}
+				} else if value, ok := v[segment.key]; ok && !segment.isIndex {
+					next = append(next, value)
+				}
+			case []any:
+				if segment.wildcard {
+					next = append(next, v...)
+				} else if segment.isIndex && segment.index < len(v) {
+					next = append(next, v[segment.index])
+				}
+			}
+		}
+		nodes = next
+	}
+
+	var values []string
+	var appendValue func(node any)
+	appendValue = func(node any) {
+		switch v := node.(type) {
+		case []any:
+			for _, e := range v {
+				appendValue(e)
+			}
+		case string:
+			values = append(values, v)
+		case float64, bool:
+			values = append(values, fmt.Sprint(v))
+		}
+	}
+	for _, node := range nodes {
+		appendValue(node)
+	}
+	return values, nil
+}
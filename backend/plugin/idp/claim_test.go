@@ -0,0 +1,99 @@
+package idp
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetClaimValues(t *testing.T) {
+	var claims map[string]any
+	err := json.Unmarshal([]byte(`{
+		"sub": "alice",
+		"groups": ["/dba", "/developer"],
+		"realm_access": {"roles": ["offline_access", "dba"]},
+		"resource_access": {
+			"bytebase": {"roles": ["owner"]},
+			"account": {"roles": ["view-profile"]}
+		},
+		"https://example.com/roles": ["admin"],
+		"email_verified": true,
+		"level": 3
+	}`), &claims)
+	require.NoError(t, err)
+
+	tests := []struct {
+		path    string
+		want    []string
+		wantErr bool
+	}{
+		{
+			path: "groups",
+			want: []string{"/dba", "/developer"},
+		},
+		{
+			path: "sub",
+			want: []string{"alice"},
+		},
+		{
+			path: "realm_access.roles",
+			want: []string{"offline_access", "dba"},
+		},
+		{
+			path: "$.resource_access.bytebase.roles[*]",
+			want: []string{"owner"},
+		},
+		{
+			path: "$.groups[1]",
+			want: []string{"/developer"},
+		},
+		{
+			path: "$['https://example.com/roles'][0]",
+			want: []string{"admin"},
+		},
+		{
+			path: "email_verified",
+			want: []string{"true"},
+		},
+		{
+			path: "level",
+			want: []string{"3"},
+		},
+		{
+			path: "missing.roles",
+			want: nil,
+		},
+		{
+			path:    "",
+			wantErr: true,
+		},
+		{
+			path:    "groups[",
+			wantErr: true,
+		},
+		{
+			path:    "groups[-1]",
+			wantErr: true,
+		},
+		{
+			path:    "realm_access..roles",
+			wantErr: true,
+		},
+	}
+
+	a := require.New(t)
+	for _, test := range tests {
+		got, err := GetClaimValues(claims, test.path)
+		if test.wantErr {
+			a.Error(err, test.path)
+			continue
+		}
+		a.NoError(err, test.path)
+		a.Equal(test.want, got, test.path)
+	}
+
+	values, err := GetClaimValues(claims, "resource_access.*.roles")
+	a.NoError(err)
+	a.ElementsMatch([]string{"owner", "view-profile"}, values)
+}
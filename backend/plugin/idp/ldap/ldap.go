@@ -58,6 +58,16 @@ type IdentityProviderConfig struct {
 	FieldMapping *storepb.FieldMapping `json:"fieldMapping"`
 }
 
+// User is a user entry in the LDAP directory.
+type User struct {
+	// DN is the distinguished name of the user entry.
+	DN       string
+	UserInfo *storepb.IdentityProviderUserInfo
+}
+
+// searchPageSize is the page size of the search requests that may return many entries.
+const searchPageSize = 500
+
 // NewIdentityProvider initializes a new LDAP Identity Provider with the given
 // configuration.
 func NewIdentityProvider(config IdentityProviderConfig) (*IdentityProvider, error) {
@@ -177,3 +187,155 @@ func (p *IdentityProvider) Authenticate(username, password string) (*storepb.Ide
 		Email:       entry.GetAttributeValue(p.config.FieldMapping.Email),
 	}, nil
 }
+
+// GetUser returns the user entry with the given username, or nil if not found.
+func (p *IdentityProvider) GetUser(username string) (*User, error) {
+	conn, err := p.Connect()
+	if err != nil {
+		return nil, errors.Errorf("connect: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	users, err := p.searchUsers(conn, strings.ReplaceAll(p.config.UserFilter, "%s", ldap.EscapeFilter(username)))
+	if err != nil {
+		return nil, err
+	}
+	if len(users) == 0 {
+		return nil, nil
+	}
+	if len(users) > 1 {
+		return nil, errors.Errorf("expect 1 user DN but got %d", len(users))
+	}
+	return users[0], nil
+}
+
+// ListUsers returns all the user entries matching the user filter.
+func (p *IdentityProvider) ListUsers() ([]*User, error) {
+	conn, err := p.Connect()
+	if err != nil {
+		return nil, errors.Errorf("connect: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	return p.searchUsers(conn, strings.ReplaceAll(p.config.UserFilter, "%s", "*"))
+}
+
+// ListFilterMemberDNs returns the normalized DNs of the users matching both the user
+// filter and the given filter, e.g. "(memberOf=cn=dba,ou=groups,dc=example,dc=com)".
+func (p *IdentityProvider) ListFilterMemberDNs(filter string) ([]string, error) {
+	conn, err := p.Connect()
+	if err != nil {
+		return nil, errors.Errorf("connect: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	users, err := p.searchUsers(conn, fmt.Sprintf("(&%s%s)", strings.ReplaceAll(p.config.UserFilter, "%s", "*"), filter))
+	if err != nil {
+		return nil, err
+	}
+	var dns []string
+	for _, user := range users {
+		dns = append(dns, NormalizeDN(user.DN))
+	}
+	return dns, nil
+}
+
+// ListGroupMemberDNs returns the normalized member DNs of the group from the
+// "member" and "uniqueMember" attributes of the group entry.
+func (p *IdentityProvider) ListGroupMemberDNs(groupDN string) ([]string, error) {
+	conn, err := p.Connect()
+	if err != nil {
+		return nil, errors.Errorf("connect: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	sr, err := conn.Search(
+		ldap.NewSearchRequest(
+			groupDN,
+			ldap.ScopeBaseObject,
+			ldap.NeverDerefAliases,
+			0,
+			0,
+			false,
+			"(objectClass=*)",
+			[]string{"member", "uniqueMember"},
+			nil,
+		),
+	)
+	if err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultNoSuchObject) {
+			return nil, errors.Errorf("group %q not found", groupDN)
+		}
+		return nil, errors.Errorf("search group: %v", err)
+	}
+	var dns []string
+	for _, entry := range sr.Entries {
+		for _, dn := range append(entry.GetAttributeValues("member"), entry.GetAttributeValues("uniqueMember")...) {
+			dns = append(dns, NormalizeDN(dn))
+		}
+	}
+	return dns, nil
+}
+
+func (p *IdentityProvider) searchUsers(conn *ldap.Conn, filter string) ([]*User, error) {
+	sr, err := conn.SearchWithPaging(
+		ldap.NewSearchRequest(
+			p.config.BaseDN,
+			ldap.ScopeWholeSubtree,
+			ldap.NeverDerefAliases,
+			0,
+			0,
+			false,
+			filter,
+			[]string{"dn", p.config.FieldMapping.Identifier, p.config.FieldMapping.DisplayName, p.config.FieldMapping.Email},
+			nil,
+		),
+		searchPageSize,
+	)
+	if err != nil {
+		return nil, errors.Errorf("search users: %v", err)
+	}
+	var users []*User
+	for _, entry := range sr.Entries {
+		identifier := entry.GetAttributeValue(p.config.FieldMapping.Identifier)
+		if identifier == "" {
+			continue
+		}
+		users = append(users, &User{
+			DN: entry.DN,
+			UserInfo: &storepb.IdentityProviderUserInfo{
+				Identifier:  identifier,
+				DisplayName: entry.GetAttributeValue(p.config.FieldMapping.DisplayName),
+				Email:       entry.GetAttributeValue(p.config.FieldMapping.Email),
+			},
+		})
+	}
+	return users, nil
+}
+
+// NormalizeDN returns the normalized DN for comparison, since the attribute
+// types and values in DNs are case-insensitive in most directories.
+func NormalizeDN(dn string) string {
+	parsed, err := ldap.ParseDN(dn)
+	if err != nil {
+		return strings.ToLower(strings.TrimSpace(dn))
+	}
+	return strings.ToLower(parsed.String())
+}
+
+// ValidateGroupMapping validates the group DN or the filter of a group mapping.
+func ValidateGroupMapping(groupDN, filter string) error {
+	if (groupDN == "") == (filter == "") {
+		return errors.New("either group DN or filter should be set")
+	}
+	if groupDN != "" {
+		if _, err := ldap.ParseDN(groupDN); err != nil {
+			return errors.Errorf("invalid group DN %q: %v", groupDN, err)
+		}
+		return nil
+	}
+	if _, err := ldap.CompileFilter(filter); err != nil {
+		return errors.Errorf("invalid filter %q: %v", filter, err)
+	}
+	return nil
+}
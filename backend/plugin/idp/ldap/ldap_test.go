@@ -272,3 +272,65 @@ func TestIdentityProvider(t *testing.T) {
 	}
 	assert.Equal(t, wantUserInfo, userInfo)
 }
+
+func TestNormalizeDN(t *testing.T) {
+	tests := []struct {
+		dn   string
+		want string
+	}{
+		{
+			dn:   "uid=Alice,ou=Users,dc=example,dc=com",
+			want: "uid=alice,ou=users,dc=example,dc=com",
+		},
+		{
+			dn:   "UID=alice, OU=Users, DC=example, DC=com",
+			want: "uid=alice,ou=users,dc=example,dc=com",
+		},
+	}
+
+	a := require.New(t)
+	for _, test := range tests {
+		a.Equal(test.want, NormalizeDN(test.dn), test.dn)
+	}
+}
+
+func TestValidateGroupMapping(t *testing.T) {
+	tests := []struct {
+		groupDN string
+		filter  string
+		wantErr bool
+	}{
+		{
+			groupDN: "cn=dba,ou=Groups,dc=example,dc=com",
+		},
+		{
+			filter: "(memberOf=cn=dba,ou=Groups,dc=example,dc=com)",
+		},
+		{
+			wantErr: true,
+		},
+		{
+			groupDN: "cn=dba,ou=Groups,dc=example,dc=com",
+			filter:  "(memberOf=cn=dba,ou=Groups,dc=example,dc=com)",
+			wantErr: true,
+		},
+		{
+			groupDN: "cn=dba,ou",
+			wantErr: true,
+		},
+		{
+			filter:  "(memberOf=cn=dba",
+			wantErr: true,
+		},
+	}
+
+	a := require.New(t)
+	for _, test := range tests {
+		err := ValidateGroupMapping(test.groupDN, test.filter)
+		if test.wantErr {
+			a.Error(err, test.groupDN+test.filter)
+		} else {
+			a.NoError(err, test.groupDN+test.filter)
+		}
+	}
+}
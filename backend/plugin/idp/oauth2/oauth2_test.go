@@ -156,7 +156,7 @@ func TestIdentityProvider(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, testAccessToken, oauthToken)
 
-	userInfoResult, err := oauth2.UserInfo(oauthToken)
+	userInfoResult, _, err := oauth2.UserInfo(oauthToken)
 	require.NoError(t, err)
 
 	wantUserInfo := &storepb.IdentityProviderUserInfo{
@@ -232,7 +232,7 @@ func TestIdentityProvider_SelfSigned(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, testAccessToken, oauthToken)
 
-		userInfoResult, err := oauth2.UserInfo(oauthToken)
+		userInfoResult, _, err := oauth2.UserInfo(oauthToken)
 		require.NoError(t, err)
 
 		wantUserInfo := &storepb.IdentityProviderUserInfo{
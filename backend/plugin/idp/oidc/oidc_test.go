@@ -216,7 +216,7 @@ func TestIdentityProvider(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, testAccessToken, oauthToken.AccessToken)
 
-	userInfo, err := oidc.UserInfo(ctx, oauthToken, testNonce)
+	userInfo, _, err := oidc.UserInfo(ctx, oauthToken, testNonce)
 	require.NoError(t, err)
 
 	wantUserInfo := &storepb.IdentityProviderUserInfo{
@@ -288,7 +288,7 @@ func TestIdentityProvider_SelfSigned(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, testAccessToken, oauthToken.AccessToken)
 
-		userInfo, err := oidc.UserInfo(ctx, oauthToken, testNonce)
+		userInfo, _, err := oidc.UserInfo(ctx, oauthToken, testNonce)
 		require.NoError(t, err)
 
 		wantUserInfo := &storepb.IdentityProviderUserInfo{
@@ -1,7 +1,10 @@
 // Package idp is the plugin for Identity Provider.
 package idp
 
-import "strings"
+import (
+	"regexp"
+	"strings"
+)
 
 // GetValueWithKey returns the value of the key in the data.
 func GetValueWithKey(data map[string]any, key string) any {
@@ -17,3 +20,21 @@ func GetValueWithKey(data map[string]any, key string) any {
 
 	return value
 }
+
+// ExtractDomain extracts the domain from the domain setting of the identity provider, e.g. google.com from www.google.com.
+func ExtractDomain(input string) string {
+	pattern := `[a-zA-Z0-9-]+(\.[a-zA-Z0-9-]+)+`
+	regExp, err := regexp.Compile(pattern)
+	if err != nil {
+		// WHen the pattern is invalid, we just return the input.
+		return input
+	}
+
+	match := regExp.FindString(input)
+	domainParts := strings.Split(match, ".")
+	// If the domain has at least 3 parts, we will remove the first part.
+	if len(domainParts) >= 3 {
+		match = strings.Join(domainParts[1:], ".")
+	}
+	return match
+}
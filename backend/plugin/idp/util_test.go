@@ -67,3 +67,34 @@ func TestGetValueWithKeyFromJSONString(t *testing.T) {
 		}
 	}
 }
+
+func TestExtractDomain(t *testing.T) {
+	tests := []struct {
+		domain string
+		want   string
+	}{
+		{
+			domain: "www.google.com",
+			want:   "google.com",
+		},
+		{
+			domain: "code.google.com",
+			want:   "google.com",
+		},
+		{
+			domain: "code.google.com.cn",
+			want:   "google.com.cn",
+		},
+		{
+			domain: "google.com",
+			want:   "google.com",
+		},
+	}
+
+	for _, test := range tests {
+		got := ExtractDomain(test.domain)
+		if got != test.want {
+			t.Errorf("ExtractDomain %s, got %s, want %s", test.domain, got, test.want)
+		}
+	}
+}
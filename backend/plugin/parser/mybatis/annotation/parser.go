@@ -0,0 +1,551 @@
+// Package annotation provides the parser for the SQL statements declared by mybatis annotations in Java mapper interfaces.
+package annotation
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+// statementAnnotations is the map from the mybatis statement annotation to the mapper xml element.
+var statementAnnotations = map[string]string{
+	"Select": "select",
+	"Insert": "insert",
+	"Update": "update",
+	"Delete": "delete",
+}
+
+// Mapper is the Java mapper interface with mybatis statement annotations.
+type Mapper struct {
+	// Namespace is the fully qualified name of the mapper interface, likes "com.example.UserMapper".
+	Namespace string
+	// Statements is the statements declared by the annotations in the order of the source.
+	Statements []*Statement
+}
+
+// Statement is the SQL statement declared by the mybatis annotation, likes @Select("SELECT * FROM t").
+type Statement struct {
+	// ID is the name of the annotated method.
+	ID string
+	// Type is the annotation name, one of "Select", "Insert", "Update" and "Delete".
+	Type string
+	// Line is the line of the annotation in the Java source, starting from 1.
+	Line int
+	// SQL is the value of the annotation. Same as MyBatis, the strings in the array value are joined with a space.
+	SQL string
+	// DatabaseID is the databaseId element of the annotation.
+	DatabaseID string
+}
+
+// ParseMapper parses the mybatis statement annotations in the Java source. The annotations whose values are
+// not constant strings, e.g. referring to the constants in other classes, are ignored.
+func ParseMapper(javaSource string) (*Mapper, error) {
+	tokens, err := tokenize(javaSource)
+	if err != nil {
+		return nil, err
+	}
+
+	mapper := &Mapper{}
+	var packageName, interfaceName string
+	for i := 0; i < len(tokens); i++ {
+		token := tokens[i]
+		switch {
+		case token.is(tokenIdentifier, "package") && packageName == "":
+			packageName, i = parseQualifiedName(tokens, i+1)
+			i--
+		case token.is(tokenIdentifier, "interface") && interfaceName == "" && i+1 < len(tokens) && tokens[i+1].kind == tokenIdentifier:
+			interfaceName = tokens[i+1].text
+			i++
+		case token.is(tokenPunctuation, "@") && i+1 < len(tokens) && tokens[i+1].kind == tokenIdentifier:
+			name, next := parseQualifiedName(tokens, i+1)
+			// The "@interface" declares an annotation type.
+			if name == "interface" {
+				i = next - 1
+				continue
+			}
+			name = name[strings.LastIndex(name, ".")+1:]
+			if _, ok := statementAnnotations[name]; !ok || next >= len(tokens) || !tokens[next].is(tokenPunctuation, "(") {
+				i = next - 1
+				continue
+			}
+			end := findClosing(tokens, next)
+			if end < 0 {
+				return nil, errors.Errorf("unclosed annotation @%s at line %d", name, token.line)
+			}
+			statement, err := parseStatement(tokens[next+1 : end])
+			if err != nil {
+				// Skip the annotation we cannot evaluate, the tokens in it will be scanned as usual.
+				i = next
+				continue
+			}
+			statement.Type = name
+			statement.Line = token.line
+			statement.ID = findMethodName(tokens, end+1)
+			if statement.ID == "" {
+				statement.ID = fmt.Sprintf("statement%d", len(mapper.Statements)+1)
+			}
+			mapper.Statements = append(mapper.Statements, statement)
+			i = end
+		}
+	}
+	mapper.Namespace = interfaceName
+	if packageName != "" && interfaceName != "" {
+		mapper.Namespace = packageName + "." + interfaceName
+	}
+	return mapper, nil
+}
+
+// BuildMapperXML builds the mybatis mapper xml for the statements, and returns the map from the line of the
+// statement element in the mapper xml to the line of the annotation in the Java source. The statements wrapped
+// in <script> are written as the dynamic SQL, and the others are written as the character data.
+func (m *Mapper) BuildMapperXML() (string, map[int]int) {
+	var buf strings.Builder
+	lineMapping := make(map[int]int)
+	buf.WriteString("<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n")
+	buf.WriteString("<!DOCTYPE mapper PUBLIC \"-//mybatis.org//DTD Mapper 3.0//EN\" \"http://mybatis.org/dtd/mybatis-3-mapper.dtd\">\n")
+	buf.WriteString(fmt.Sprintf("<mapper namespace=\"%s\">\n", escapeAttr(m.Namespace)))
+	line := 4
+	for _, statement := range m.Statements {
+		element := statementAnnotations[statement.Type]
+		buf.WriteString(fmt.Sprintf("<%s id=\"%s\"", element, escapeAttr(statement.ID)))
+		if statement.DatabaseID != "" {
+			buf.WriteString(fmt.Sprintf(" databaseId=\"%s\"", escapeAttr(statement.DatabaseID)))
+		}
+		buf.WriteString(">\n")
+		lineMapping[line] = statement.Line
+		line++
+
+		var body string
+		if script, ok := getScript(statement.SQL); ok {
+			body = script
+		} else {
+			body = "<![CDATA[" + strings.ReplaceAll(statement.SQL, "]]>", "]]]]><![CDATA[>") + "]]>"
+		}
+		buf.WriteString(body)
+		buf.WriteString("\n")
+		line += strings.Count(body, "\n") + 1
+
+		buf.WriteString(fmt.Sprintf("</%s>\n", element))
+		line++
+	}
+	buf.WriteString("</mapper>\n")
+	return buf.String(), lineMapping
+}
+
+// getScript returns the content of the <script> element, which is the dynamic SQL in the annotation.
+func getScript(sql string) (string, bool) {
+	trimmed := strings.TrimSpace(sql)
+	if !strings.HasPrefix(trimmed, "<script>") || !strings.HasSuffix(trimmed, "</script>") {
+		return "", false
+	}
+	return strings.TrimSuffix(strings.TrimPrefix(trimmed, "<script>"), "</script>"), true
+}
+
+func escapeAttr(s string) string {
+	var buf strings.Builder
+	// The error is always nil for strings.Builder.
+	_ = xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
+
+// parseStatement parses the elements of the statement annotation, likes
+//
+//	@Select("SELECT * FROM t")
+//	@Select({"SELECT *", "FROM t"})
+//	@Select(value = "SELECT * " + "FROM t", databaseId = "mysql")
+func parseStatement(tokens []*token) (*Statement, error) {
+	statement := &Statement{}
+	if len(tokens) < 2 || tokens[0].kind != tokenIdentifier || !tokens[1].is(tokenPunctuation, "=") {
+		sql, err := parseElementValue(tokens)
+		if err != nil {
+			return nil, err
+		}
+		statement.SQL = sql
+		return statement, nil
+	}
+
+	hasValue := false
+	for _, pair := range splitTopLevel(tokens) {
+		if len(pair) < 2 || pair[0].kind != tokenIdentifier || !pair[1].is(tokenPunctuation, "=") {
+			return nil, errors.New("expected element-value pair")
+		}
+		switch pair[0].text {
+		case "value":
+			sql, err := parseElementValue(pair[2:])
+			if err != nil {
+				return nil, err
+			}
+			statement.SQL = sql
+			hasValue = true
+		case "databaseId":
+			databaseID, err := parseElementValue(pair[2:])
+			if err != nil {
+				return nil, err
+			}
+			statement.DatabaseID = databaseID
+		}
+	}
+	if !hasValue {
+		return nil, errors.New("missing value element")
+	}
+	return statement, nil
+}
+
+// parseElementValue parses the constant string or the array of constant strings.
+func parseElementValue(tokens []*token) (string, error) {
+	if len(tokens) >= 2 && tokens[0].is(tokenPunctuation, "{") && tokens[len(tokens)-1].is(tokenPunctuation, "}") {
+		var values []string
+		for _, element := range splitTopLevel(tokens[1 : len(tokens)-1]) {
+			// The trailing comma is allowed in the array.
+			if len(element) == 0 {
+				continue
+			}
+			value, err := parseStringConcat(element)
+			if err != nil {
+				return "", err
+			}
+			values = append(values, value)
+		}
+		return strings.Join(values, " "), nil
+	}
+	return parseStringConcat(tokens)
+}
+
+// parseStringConcat parses the concatenation of string literals, likes "SELECT * " + "FROM t".
+func parseStringConcat(tokens []*token) (string, error) {
+	var buf strings.Builder
+	for i, token := range tokens {
+		if i%2 == 1 {
+			if !token.is(tokenPunctuation, "+") {
+				return "", errors.Errorf("unexpected %q at line %d", token.text, token.line)
+			}
+			continue
+		}
+		if token.kind != tokenString {
+			return "", errors.Errorf("unsupported non-constant expression %q at line %d", token.text, token.line)
+		}
+		buf.WriteString(token.text)
+	}
+	if len(tokens)%2 == 0 {
+		return "", errors.New("expected string literal")
+	}
+	return buf.String(), nil
+}
+
+// splitTopLevel splits the tokens by the commas not in the parentheses, brackets and braces.
+func splitTopLevel(tokens []*token) [][]*token {
+	var result [][]*token
+	depth := 0
+	start := 0
+	for i, token := range tokens {
+		if token.kind != tokenPunctuation {
+			continue
+		}
+		switch token.text {
+		case "(", "{", "[":
+			depth++
+		case ")", "}", "]":
+			depth--
+		case ",":
+			if depth == 0 {
+				result = append(result, tokens[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(result, tokens[start:])
+}
+
+// findClosing returns the index of the parenthesis closing the one at the start index, or -1 if not found.
+func findClosing(tokens []*token, start int) int {
+	depth := 0
+	for i := start; i < len(tokens); i++ {
+		if tokens[i].is(tokenPunctuation, "(") {
+			depth++
+		} else if tokens[i].is(tokenPunctuation, ")") {
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// findMethodName returns the name of the method declared after the annotations, e.g. "selectUser" in
+//
+//	@Options(useCache = false)
+//	List<User> selectUser(@Param("name") String name);
+func findMethodName(tokens []*token, start int) string {
+	for i := start; i < len(tokens); i++ {
+		token := tokens[i]
+		switch {
+		case token.is(tokenPunctuation, "@"):
+			_, next := parseQualifiedName(tokens, i+1)
+			if next < len(tokens) && tokens[next].is(tokenPunctuation, "(") {
+				end := findClosing(tokens, next)
+				if end < 0 {
+					return ""
+				}
+				next = end + 1
+			}
+			i = next - 1
+		case token.kind == tokenIdentifier && i+1 < len(tokens) && tokens[i+1].is(tokenPunctuation, "("):
+			return token.text
+		case token.is(tokenPunctuation, ";"), token.is(tokenPunctuation, "{"), token.is(tokenPunctuation, "}"):
+			return ""
+		}
+	}
+	return ""
+}
+
+// parseQualifiedName parses the qualified name likes "org.apache.ibatis.annotations.Select" from the start index,
+// and returns the name and the index of the next token.
+func parseQualifiedName(tokens []*token, start int) (string, int) {
+	var parts []string
+	i := start
+	for i < len(tokens) && tokens[i].kind == tokenIdentifier {
+		parts = append(parts, tokens[i].text)
+		i++
+		if i+1 < len(tokens) && tokens[i].is(tokenPunctuation, ".") && tokens[i+1].kind == tokenIdentifier {
+			i++
+			continue
+		}
+		break
+	}
+	return strings.Join(parts, "."), i
+}
+
+type tokenKind int
+
+const (
+	// tokenIdentifier is the identifier, keyword or number literal.
+	tokenIdentifier tokenKind = iota
+	// tokenString is the string literal or text block, the text is the unescaped value.
+	tokenString
+	// tokenChar is the character literal.
+	tokenChar
+	// tokenPunctuation is the single character separator or operator.
+	tokenPunctuation
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	line int
+}
+
+func (t *token) is(kind tokenKind, text string) bool {
+	return t.kind == kind && t.text == text
+}
+
+// tokenize splits the Java source into tokens, the comments and whitespaces are skipped.
+func tokenize(source string) ([]*token, error) {
+	var tokens []*token
+	runes := []rune(source)
+	line := 1
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case r == '\n':
+			line++
+			i++
+		case unicode.IsSpace(r):
+			i++
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '/':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			end := indexOf(runes, i+2, "*/")
+			if end < 0 {
+				return nil, errors.Errorf("unclosed comment at line %d", line)
+			}
+			line += countNewlines(runes[i:end])
+			i = end + 2
+		case r == '"' && hasPrefix(runes, i, `"""`):
+			startLine := line
+			end := i + 3
+			for ; end < len(runes) && !hasPrefix(runes, end, `"""`); end++ {
+				if runes[end] == '\\' {
+					end++
+				}
+			}
+			if end >= len(runes) {
+				return nil, errors.Errorf("unclosed text block at line %d", startLine)
+			}
+			value, err := unescape(stripIndent(string(runes[i+3 : end])))
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid text block at line %d", startLine)
+			}
+			line += countNewlines(runes[i:end])
+			tokens = append(tokens, &token{kind: tokenString, text: value, line: startLine})
+			i = end + 3
+		case r == '"' || r == '\'':
+			end := i + 1
+			for ; end < len(runes) && runes[end] != r && runes[end] != '\n'; end++ {
+				if runes[end] == '\\' {
+					end++
+				}
+			}
+			if end >= len(runes) || runes[end] != r {
+				return nil, errors.Errorf("unclosed literal at line %d", line)
+			}
+			value, err := unescape(string(runes[i+1 : end]))
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid literal at line %d", line)
+			}
+			kind := tokenString
+			if r == '\'' {
+				kind = tokenChar
+			}
+			tokens = append(tokens, &token{kind: kind, text: value, line: line})
+			i = end + 1
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '$':
+			end := i
+			for end < len(runes) && (unicode.IsLetter(runes[end]) || unicode.IsDigit(runes[end]) || runes[end] == '_' || runes[end] == '$') {
+				end++
+			}
+			tokens = append(tokens, &token{kind: tokenIdentifier, text: string(runes[i:end]), line: line})
+			i = end
+		default:
+			tokens = append(tokens, &token{kind: tokenPunctuation, text: string(r), line: line})
+			i++
+		}
+	}
+	return tokens, nil
+}
+
+// stripIndent strips the incidental whitespaces of the text block content, see
+// https://docs.oracle.com/en/java/javase/17/text-blocks/index.html#incidental-white-space.
+func stripIndent(content string) string {
+	// The content begins after the line terminator following the opening delimiter.
+	if index := strings.IndexByte(content, '\n'); index >= 0 {
+		content = content[index+1:]
+	}
+	lines := strings.Split(content, "\n")
+	indent := -1
+	for i, line := range lines {
+		// The last line is significant if it contains the closing delimiter only.
+		if strings.TrimSpace(line) == "" && i != len(lines)-1 {
+			continue
+		}
+		n := len(line) - len(strings.TrimLeftFunc(line, unicode.IsSpace))
+		if indent < 0 || n < indent {
+			indent = n
+		}
+	}
+	for i, line := range lines {
+		if len(line) >= indent {
+			line = line[indent:]
+		} else {
+			line = ""
+		}
+		lines[i] = strings.TrimRightFunc(line, unicode.IsSpace)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// unescape processes the escape sequences in the Java string literal.
+func unescape(s string) (string, error) {
+	if !strings.Contains(s, `\`) {
+		return s, nil
+	}
+	var buf strings.Builder
+	runes := []rune(s)
+	for i := 0; i < len(runes); i++ {
+		if runes[i] != '\\' {
+			buf.WriteRune(runes[i])
+			continue
+		}
+		i++
+		if i >= len(runes) {
+			return "", errors.New("invalid escape sequence at the end")
+		}
+		switch r := runes[i]; r {
+		case 'n':
+			buf.WriteRune('\n')
+		case 't':
+			buf.WriteRune('\t')
+		case 'r':
+			buf.WriteRune('\r')
+		case 'b':
+			buf.WriteRune('\b')
+		case 'f':
+			buf.WriteRune('\f')
+		case 's':
+			buf.WriteRune(' ')
+		case '\n':
+			// The line continuation in the text block.
+		case 'u':
+			for i+1 < len(runes) && runes[i+1] == 'u' {
+				i++
+			}
+			if i+4 >= len(runes) {
+				return "", errors.New("invalid unicode escape sequence")
+			}
+			code, err := strconv.ParseUint(string(runes[i+1:i+5]), 16, 32)
+			if err != nil {
+				return "", errors.Wrap(err, "invalid unicode escape sequence")
+			}
+			buf.WriteRune(rune(code))
+			i += 4
+		default:
+			if r >= '0' && r <= '7' {
+				// The octal escape is at most \377.
+				end := i + 1
+				maxLen := 2
+				if r <= '3' {
+					maxLen = 3
+				}
+				for end < len(runes) && end-i < maxLen && runes[end] >= '0' && runes[end] <= '7' {
+					end++
+				}
+				code, err := strconv.ParseUint(string(runes[i:end]), 8, 32)
+				if err != nil {
+					return "", errors.Wrap(err, "invalid octal escape sequence")
+				}
+				buf.WriteRune(rune(code))
+				i = end - 1
+				continue
+			}
+			// \", \' and \\.
+			buf.WriteRune(r)
+		}
+	}
+	return buf.String(), nil
+}
+
+func indexOf(runes []rune, start int, s string) int {
+	for i := start; i < len(runes); i++ {
+		if hasPrefix(runes, i, s) {
+			return i
+		}
+	}
+	return -1
+}
+
+func hasPrefix(runes []rune, start int, s string) bool {
+	i := start
+	for _, r := range s {
+		if i >= len(runes) || runes[i] != r {
+			return false
+		}
+		i++
+	}
+	return true
+}
+
+func countNewlines(runes []rune) int {
+	n := 0
+	for _, r := range runes {
+		if r == '\n' {
+			n++
+		}
+	}
+	return n
+}
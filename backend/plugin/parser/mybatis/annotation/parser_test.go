@@ -0,0 +1,79 @@
+package annotation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMapper(t *testing.T) {
+	source := `package com.example.mapper;
+
+import java.util.List;
+import org.apache.ibatis.annotations.*;
+
+/**
+ * @Select("SELECT * FROM t_comment") in the comment is ignored.
+ */
+@Mapper
+public interface UserMapper {
+    String TABLE = "t_user";
+
+    // @Delete("DELETE FROM t_comment")
+    @Select("SELECT * FROM t_user WHERE id = #{id}")
+    User selectById(@Param("id") long id);
+
+    @Select({"SELECT *", "FROM t_user", "WHERE name = 'a\"b'",})
+    @Options(useCache = false)
+    List<User> selectByName(String name);
+
+    @org.apache.ibatis.annotations.Update(value = "UPDATE t_user " +
+        "SET name = #{name} WHERE id = #{id}", databaseId = "mysql")
+    int update(User user);
+
+    @Insert("""
+        INSERT INTO t_user (name)
+        VALUES (#{name})
+        """)
+    int insert(User user);
+
+    @Delete("DELETE FROM " + TABLE)
+    int deleteAll();
+}
+`
+	mapper, err := ParseMapper(source)
+	require.NoError(t, err)
+	require.Equal(t, &Mapper{
+		Namespace: "com.example.mapper.UserMapper",
+		Statements: []*Statement{
+			{ID: "selectById", Type: "Select", Line: 14, SQL: "SELECT * FROM t_user WHERE id = #{id}"},
+			{ID: "selectByName", Type: "Select", Line: 17, SQL: `SELECT * FROM t_user WHERE name = 'a"b'`},
+			{ID: "update", Type: "Update", Line: 21, SQL: "UPDATE t_user SET name = #{name} WHERE id = #{id}", DatabaseID: "mysql"},
+			{ID: "insert", Type: "Insert", Line: 25, SQL: "INSERT INTO t_user (name)\nVALUES (#{name})\n"},
+		},
+	}, mapper)
+}
+
+func TestBuildMapperXML(t *testing.T) {
+	mapper := &Mapper{
+		Namespace: "com.example.mapper.UserMapper",
+		Statements: []*Statement{
+			{ID: "selectById", Type: "Select", Line: 14, SQL: "SELECT * FROM t_user WHERE id < #{id} AND note = ']]>'"},
+			{ID: "selectByName", Type: "Select", Line: 20, SQL: "<script>SELECT * FROM t_user\n<where><if test='name != null'>name = #{name}</if></where></script>", DatabaseID: "mysql"},
+		},
+	}
+	xml, lineMapping := mapper.BuildMapperXML()
+	require.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE mapper PUBLIC "-//mybatis.org//DTD Mapper 3.0//EN" "http://mybatis.org/dtd/mybatis-3-mapper.dtd">
+<mapper namespace="com.example.mapper.UserMapper">
+<select id="selectById">
+<![CDATA[SELECT * FROM t_user WHERE id < #{id} AND note = ']]]]><![CDATA[>']]>
+</select>
+<select id="selectByName" databaseId="mysql">
+SELECT * FROM t_user
+<where><if test='name != null'>name = #{name}</if></where>
+</select>
+</mapper>
+`, xml)
+	require.Equal(t, map[int]int{4: 14, 7: 20}, lineMapping)
+}
@@ -12,6 +12,9 @@ import (
 // Configuration is the root element of mybatis configuration xml file.
 type Configuration struct {
 	Environments []Environment
+	// DatabaseIDs is the map from the database product name to the database id declared in the <databaseIdProvider>
+	// element, likes "MySQL" to "mysql". It is nil if the element is absent.
+	DatabaseIDs map[string]string
 }
 
 // Environment is the element of environments in mybatis configuration xml file.
@@ -32,6 +35,9 @@ type Environment struct {
 //	     ...
 //	   </environment>
 //	</environments>
+//	<databaseIdProvider type="DB_VENDOR">
+//	  <property name="MySQL" value="mysql"/>
+//	</databaseIdProvider>
 //
 // </configuration>.
 func ParseConfiguration(configurationXML string) (*Configuration, error) {
@@ -45,13 +51,26 @@ func ParseConfiguration(configurationXML string) (*Configuration, error) {
 		} `xml:"environment"`
 	}
 
+	type DatabaseIDProvider struct {
+		Type       string `xml:"type,attr"`
+		Properties []struct {
+			Name  string `xml:"name,attr"`
+			Value string `xml:"value,attr"`
+		} `xml:"property"`
+	}
+
 	reader := strings.NewReader(configurationXML)
 	d := xml.NewDecoder(reader)
+	var conf *Configuration
+	var databaseIDs map[string]string
 	for {
 		token, err := d.Token()
 		if err != nil {
 			if err == io.EOF {
-				return nil, nil
+				if conf != nil {
+					conf.DatabaseIDs = databaseIDs
+				}
+				return conf, nil
 			}
 			return nil, errors.Wrapf(err, "failed to read token")
 		}
@@ -62,7 +81,7 @@ func ParseConfiguration(configurationXML string) (*Configuration, error) {
 				if err := d.DecodeElement(&environments, &t); err != nil {
 					return nil, errors.Wrapf(err, "failed to decode environments")
 				}
-				var conf Configuration
+				conf = &Configuration{}
 				for _, environment := range environments.Environment {
 					for _, property := range environment.Properties {
 						if property.Name == "url" {
@@ -73,7 +92,19 @@ func ParseConfiguration(configurationXML string) (*Configuration, error) {
 						}
 					}
 				}
-				return &conf, nil
+			} else if t.Name.Local == "databaseIdProvider" {
+				var provider DatabaseIDProvider
+				if err := d.DecodeElement(&provider, &t); err != nil {
+					return nil, errors.Wrapf(err, "failed to decode databaseIdProvider")
+				}
+				// Only the built-in vendor provider is supported, the custom provider is implemented in Java.
+				if provider.Type != "DB_VENDOR" && provider.Type != "VENDOR" {
+					continue
+				}
+				databaseIDs = make(map[string]string)
+				for _, property := range provider.Properties {
+					databaseIDs[property.Name] = property.Value
+				}
 			}
 		default:
 		}
@@ -56,6 +56,40 @@ func TestExtractEnvironmentIDs(t *testing.T) {
 				},
 			},
 		},
+		{
+			configuration: `
+<?xml version="1.0" encoding="UTF-8" ?>
+<!DOCTYPE configuration
+	PUBLIC "-//mybatis.org//DTD Config 3.0//EN"
+	"https://mybatis.org/dtd/mybatis-3-config.dtd">
+<configuration>
+	<environments default="prod">
+	<environment id="prod">
+		<transactionManager type="JDBC"/>
+		<dataSource type="POOLED">
+			<property name="url" value="jdbc:postgresql://localhost:5432/test"/>
+		</dataSource>
+	</environment>
+	</environments>
+	<databaseIdProvider type="DB_VENDOR">
+		<property name="MySQL" value="mysql"/>
+		<property name="PostgreSQL" value="postgres"/>
+	</databaseIdProvider>
+</configuration>
+`,
+			want: &Configuration{
+				Environments: []Environment{
+					{
+						ID:             "prod",
+						JDBCConnString: "jdbc:postgresql://localhost:5432/test",
+					},
+				},
+				DatabaseIDs: map[string]string{
+					"MySQL":      "mysql",
+					"PostgreSQL": "postgres",
+				},
+			},
+		},
 	}
 	for _, tc := range testCases {
 		got, err := ParseConfiguration(tc.configuration)
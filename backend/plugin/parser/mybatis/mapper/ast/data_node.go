@@ -77,6 +77,11 @@ func (v *VariableNode) RestoreSQL(ctx *RestoreContext, w io.Writer) error {
 		if _, err := w.Write([]byte(value)); err != nil {
 			return err
 		}
+	} else if value, ok := ctx.bindings[v.Name]; ok && value.kind == ognlValueString {
+		// The variable bound by <bind> element with the constant value.
+		if _, err := w.Write([]byte(value.str)); err != nil {
+			return err
+		}
 	} else {
 		if _, err := w.Write([]byte(ctx.RestoreDataNodePlaceholder)); err != nil {
 			return err
@@ -25,6 +25,7 @@ var (
 	_ Node = (*SQLNode)(nil)
 	_ Node = (*IncludeNode)(nil)
 	_ Node = (*PropertyNode)(nil)
+	_ Node = (*BindNode)(nil)
 )
 
 // IfNode represents a if node in mybatis mapper xml likes <if test="condition">...</if>.
@@ -44,8 +45,12 @@ func NewIfNode(startElement *xml.StartElement) *IfNode {
 	return node
 }
 
-// RestoreSQL implements Node interface, the if condition will be ignored.
+// RestoreSQL implements Node interface, the children will be ignored only if the condition is always false,
+// because the parameters are unknown.
 func (n *IfNode) RestoreSQL(ctx *RestoreContext, w io.Writer) error {
+	if evaluateTest(ctx, n.Test) == testResultFalse {
+		return nil
+	}
 	if len(n.Children) > 0 {
 		if _, err := w.Write([]byte(" ")); err != nil {
 			return err
@@ -62,7 +67,7 @@ func (n *IfNode) RestoreSQL(ctx *RestoreContext, w io.Writer) error {
 func (*IfNode) isChildAcceptable(child Node) bool {
 	// https://github.com/mybatis/mybatis-3/blob/master/src/main/resources/org/apache/ibatis/builder/xml/mybatis-3-mapper.dtd#L290
 	switch child.(type) {
-	case *DataNode, *IncludeNode, *TrimNode, *WhereNode, *SetNode, *ForEachNode, *ChooseNode, *IfNode:
+	case *DataNode, *IncludeNode, *TrimNode, *WhereNode, *SetNode, *ForEachNode, *ChooseNode, *IfNode, *BindNode:
 		return true
 	default:
 		return false
@@ -87,7 +92,8 @@ func NewChooseNode(_ *xml.StartElement) *ChooseNode {
 	return &ChooseNode{}
 }
 
-// RestoreSQL implements Node interface.
+// RestoreSQL implements Node interface. The <when> elements whose conditions are always false will be ignored,
+// and the ones after the first <when> element whose condition is always true will be ignored.
 func (n *ChooseNode) RestoreSQL(ctx *RestoreContext, w io.Writer) error {
 	if len(n.Children) > 0 {
 		if _, err := w.Write([]byte(" ")); err != nil {
@@ -95,9 +101,19 @@ func (n *ChooseNode) RestoreSQL(ctx *RestoreContext, w io.Writer) error {
 		}
 	}
 	for _, node := range n.Children {
+		result := testResultUnknown
+		if whenNode, ok := node.(*WhenNode); ok {
+			result = evaluateTest(ctx, whenNode.Test)
+		}
+		if result == testResultFalse {
+			continue
+		}
 		if err := node.RestoreSQL(ctx, w); err != nil {
 			return err
 		}
+		if result == testResultTrue {
+			break
+		}
 	}
 	return nil
 }
@@ -159,7 +175,7 @@ func (n *WhenNode) RestoreSQL(ctx *RestoreContext, w io.Writer) error {
 func (*WhenNode) isChildAcceptable(child Node) bool {
 	// https://github.com/mybatis/mybatis-3/blob/master/src/main/resources/org/apache/ibatis/builder/xml/mybatis-3-mapper.dtd#LL284C1-L284C1
 	switch child.(type) {
-	case *DataNode, *IncludeNode, *TrimNode, *WhereNode, *SetNode, *ForEachNode, *ChooseNode, *IfNode:
+	case *DataNode, *IncludeNode, *TrimNode, *WhereNode, *SetNode, *ForEachNode, *ChooseNode, *IfNode, *BindNode:
 		return true
 	default:
 		return false
@@ -202,7 +218,7 @@ func (n *OtherwiseNode) RestoreSQL(ctx *RestoreContext, w io.Writer) error {
 func (*OtherwiseNode) isChildAcceptable(child Node) bool {
 	// https://github.com/mybatis/mybatis-3/blob/master/src/main/resources/org/apache/ibatis/builder/xml/mybatis-3-mapper.dtd#L288
 	switch child.(type) {
-	case *DataNode, *IncludeNode, *TrimNode, *WhereNode, *SetNode, *ForEachNode, *ChooseNode, *IfNode:
+	case *DataNode, *IncludeNode, *TrimNode, *WhereNode, *SetNode, *ForEachNode, *ChooseNode, *IfNode, *BindNode:
 		return true
 	default:
 		return false
@@ -311,7 +327,7 @@ func (n *TrimNode) RestoreSQL(ctx *RestoreContext, w io.Writer) error {
 func (*TrimNode) isChildAcceptable(child Node) bool {
 	// https://github.com/mybatis/mybatis-3/blob/master/src/main/resources/org/apache/ibatis/builder/xml/mybatis-3-mapper.dtd#L262
 	switch child.(type) {
-	case *DataNode, *IncludeNode, *TrimNode, *WhereNode, *SetNode, *ForEachNode, *ChooseNode, *IfNode:
+	case *DataNode, *IncludeNode, *TrimNode, *WhereNode, *SetNode, *ForEachNode, *ChooseNode, *IfNode, *BindNode:
 		return true
 	default:
 		return false
@@ -416,7 +432,7 @@ func NewForeachNode(startElement *xml.StartElement) *ForEachNode {
 func (*ForEachNode) isChildAcceptable(child Node) bool {
 	// https://github.com/mybatis/mybatis-3/blob/master/src/main/resources/org/apache/ibatis/builder/xml/mybatis-3-mapper.dtd#L272
 	switch child.(type) {
-	case *DataNode, *IncludeNode, *TrimNode, *WhereNode, *SetNode, *ForEachNode, *ChooseNode, *IfNode:
+	case *DataNode, *IncludeNode, *TrimNode, *WhereNode, *SetNode, *ForEachNode, *ChooseNode, *IfNode, *BindNode:
 		return true
 	default:
 		return false
@@ -479,8 +495,10 @@ func (n *ForEachNode) RestoreSQL(ctx *RestoreContext, w io.Writer) error {
 
 // SQLNode represents a sql node in mybatis mapper xml likes <sql id="sqlId">...</sql>.
 type SQLNode struct {
-	ID       string
-	Children []Node
+	ID string
+	// Namespace is the namespace of the mapper which the sql node belongs to.
+	Namespace string
+	Children  []Node
 }
 
 // NewSQLNode creates a new sql node.
@@ -500,7 +518,7 @@ func NewSQLNode(startElement *xml.StartElement) *SQLNode {
 func (*SQLNode) isChildAcceptable(child Node) bool {
 	// https://github.com/mybatis/mybatis-3/blob/master/src/main/resources/org/apache/ibatis/builder/xml/mybatis-3-mapper.dtd#L255
 	switch child.(type) {
-	case *DataNode, *IncludeNode, *TrimNode, *WhereNode, *SetNode, *ForEachNode, *IfNode, *ChooseNode:
+	case *DataNode, *IncludeNode, *TrimNode, *WhereNode, *SetNode, *ForEachNode, *IfNode, *ChooseNode, *BindNode:
 		return true
 	default:
 		return false
@@ -583,10 +601,34 @@ func (n *IncludeNode) RestoreSQL(ctx *RestoreContext, w io.Writer) error {
 		return ctx.Variable[name]
 	})
 
-	sqlNode, ok := ctx.SQLMap[refID]
+	// The refID can be the id in the current mapper or the fully qualified id likes "namespace.id" in other mappers.
+	sqlNode, ok := ctx.SQLMap[ctx.Namespace+"."+refID]
+	if !ok {
+		sqlNode, ok = ctx.SQLMap[refID]
+	}
 	if !ok {
 		return errors.Errorf("refID %s not found", n.RefID)
 	}
+	fragmentKey := sqlNode.ID
+	if sqlNode.Namespace != "" {
+		fragmentKey = sqlNode.Namespace + "." + sqlNode.ID
+	}
+	if ctx.includingFragments[fragmentKey] {
+		return errors.Errorf("circular include of sql %s", fragmentKey)
+	}
+	if ctx.includingFragments == nil {
+		ctx.includingFragments = make(map[string]bool)
+	}
+	ctx.includingFragments[fragmentKey] = true
+	defer delete(ctx.includingFragments, fragmentKey)
+	// The nested <include> elements in the sql node are resolved in the namespace of the sql node.
+	if sqlNode.Namespace != "" {
+		namespace := ctx.Namespace
+		ctx.Namespace = sqlNode.Namespace
+		defer func() {
+			ctx.Namespace = namespace
+		}()
+	}
 
 	// Set all the properties.
 	// It is safe we don't check whether the variable exists, because property element can only be child of include element,
@@ -653,3 +695,48 @@ func (*PropertyNode) AddChild(Node) {
 func (*PropertyNode) RestoreSQL(*RestoreContext, io.Writer) error {
 	return nil
 }
+
+// BindNode represents a bind node in mybatis mapper xml likes <bind name="pattern" value="'%' + name + '%'" />.
+type BindNode struct {
+	Name  string
+	Value string
+}
+
+// NewBindNode creates a new bind node.
+func NewBindNode(startElement *xml.StartElement) *BindNode {
+	var name, value string
+	for _, attr := range startElement.Attr {
+		if attr.Name.Local == "name" {
+			name = attr.Value
+		} else if attr.Name.Local == "value" {
+			value = attr.Value
+		}
+	}
+	return &BindNode{
+		Name:  name,
+		Value: value,
+	}
+}
+
+func (*BindNode) isChildAcceptable(Node) bool {
+	return false
+}
+
+// AddChild adds a child to the bind node.
+func (*BindNode) AddChild(Node) {
+}
+
+// RestoreSQL implements Node interface, the bind node binds the evaluated value to the name, and the value
+// can be used in the test expressions and ${} of the following elements in the same statement.
+func (n *BindNode) RestoreSQL(ctx *RestoreContext, _ io.Writer) error {
+	value, err := evaluateOGNL(ctx, n.Value)
+	if err != nil {
+		// The value depends on the expressions we cannot evaluate.
+		value = ognlValue{kind: ognlValueUnknown}
+	}
+	if ctx.bindings == nil {
+		ctx.bindings = make(map[string]ognlValue)
+	}
+	ctx.bindings[n.Name] = value
+	return nil
+}
@@ -18,7 +18,15 @@ type MapperNode struct {
 
 // RestoreSQL implements Node interface.
 func (n *MapperNode) RestoreSQL(ctx *RestoreContext, w io.Writer) error {
+	namespace := ctx.Namespace
+	ctx.Namespace = n.Namespace
+	defer func() {
+		ctx.Namespace = namespace
+	}()
 	for _, node := range n.Children {
+		if queryNode, ok := node.(*QueryNode); ok && !n.isQueryNodeApplicable(ctx, queryNode) {
+			continue
+		}
 		if err := node.RestoreSQL(ctx, w); err != nil {
 			return err
 		}
@@ -26,6 +34,24 @@ func (n *MapperNode) RestoreSQL(ctx *RestoreContext, w io.Writer) error {
 	return nil
 }
 
+// isQueryNodeApplicable returns true if the query node is used for the database id in the context.
+// Same as MyBatis, the statement with the matched databaseId takes precedence over the one without databaseId,
+// and the statement with other databaseId is discarded.
+func (n *MapperNode) isQueryNodeApplicable(ctx *RestoreContext, queryNode *QueryNode) bool {
+	if ctx.DatabaseID == "" {
+		return true
+	}
+	if queryNode.DatabaseID != "" {
+		return queryNode.DatabaseID == ctx.DatabaseID
+	}
+	for _, node := range n.Children {
+		if other, ok := node.(*QueryNode); ok && other.ID == queryNode.ID && other.DatabaseID == ctx.DatabaseID {
+			return false
+		}
+	}
+	return true
+}
+
 // NewMapperNode creates a new mapper node.
 func NewMapperNode(startElement *xml.StartElement) *MapperNode {
 	m := &MapperNode{}
@@ -32,6 +32,18 @@ type RestoreContext struct {
 	// RestoreDataNodePlaceholder is the placeholder for restoring data node, it may be different in different engine.
 	// For example, in MySQL, it is "?", in PostgreSQL, it is "$1".
 	RestoreDataNodePlaceholder string
+
+	// DatabaseID is the database id of the data source, likes "mysql" in <select databaseId="mysql">.
+	// It is used to choose the database specific statements and evaluate the _databaseId in the test expressions.
+	// Empty means restoring all the statements.
+	DatabaseID string
+	// Namespace is the namespace of the mapper being restored, it is used to resolve the refid of <include> element.
+	Namespace string
+	// bindings is the map of the variables bound by <bind> element in the current statement.
+	bindings map[string]ognlValue
+	// includingFragments is the set of the fully qualified ids of the <sql> elements being expanded by the <include> elements.
+	// It is used to detect the circular <include> references, which may span the mapper xml files.
+	includingFragments map[string]bool
 }
 
 // WithRestoreDataNodePlaceholder set the placeholder for restoring data node.
@@ -45,6 +57,12 @@ func (r *RestoreContext) WithRestoreDataNodePlaceholder(placeholder string) *Res
 	return r
 }
 
+// WithDatabaseID set the database id of the data source, likes "mysql" and "postgresql".
+func (r *RestoreContext) WithDatabaseID(databaseID string) *RestoreContext {
+	r.DatabaseID = databaseID
+	return r
+}
+
 var (
 	_ Node = (*RootNode)(nil)
 	_ Node = (*EmptyNode)(nil)
@@ -0,0 +1,470 @@
+// Package ast defines the abstract syntax tree of mybatis mapper xml.
+package ast
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+// testResult is the result of evaluating the test attribute of <if> and <when> elements.
+// The parameters are unknown when restoring the SQL statement, so the result can be unknown.
+type testResult int
+
+const (
+	testResultUnknown testResult = iota
+	testResultTrue
+	testResultFalse
+)
+
+// ognlValueKind is the kind of the value evaluated from the OGNL expression.
+type ognlValueKind int
+
+const (
+	// ognlValueUnknown is the value depending on the parameters.
+	ognlValueUnknown ognlValueKind = iota
+	// ognlValueNotNull is the value depending on the parameters but never null, e.g. "'%' + name + '%'".
+	ognlValueNotNull
+	ognlValueNull
+	ognlValueString
+	ognlValueNumber
+	ognlValueBoolean
+)
+
+type ognlValue struct {
+	kind ognlValueKind
+	str  string
+	num  float64
+	b    bool
+}
+
+func (v ognlValue) isKnown() bool {
+	return v.kind != ognlValueUnknown && v.kind != ognlValueNotNull
+}
+
+// toTestResult converts the value to the test result in the same way as OGNL, that is, null is false,
+// boolean is itself, number is true if it is not zero, and other values are true.
+func (v ognlValue) toTestResult() testResult {
+	switch v.kind {
+	case ognlValueNull:
+		return testResultFalse
+	case ognlValueBoolean:
+		return newTestResult(v.b)
+	case ognlValueNumber:
+		return newTestResult(v.num != 0)
+	case ognlValueString, ognlValueNotNull:
+		return testResultTrue
+	}
+	return testResultUnknown
+}
+
+func newTestResult(b bool) testResult {
+	if b {
+		return testResultTrue
+	}
+	return testResultFalse
+}
+
+// evaluateTest evaluates the test expression with the known bindings, the result is unknown if the expression
+// depends on the parameters or cannot be parsed.
+func evaluateTest(ctx *RestoreContext, expression string) testResult {
+	value, err := evaluateOGNL(ctx, expression)
+	if err != nil {
+		return testResultUnknown
+	}
+	return value.toTestResult()
+}
+
+// evaluateOGNL evaluates the common OGNL expressions used in mybatis mapper xml, likes
+// "name != null and name.length() > 0", "_databaseId == 'mysql'" and "'%' + name + '%'".
+func evaluateOGNL(ctx *RestoreContext, expression string) (ognlValue, error) {
+	tokens, err := tokenizeOGNL(expression)
+	if err != nil {
+		return ognlValue{}, err
+	}
+	e := &ognlEvaluator{ctx: ctx, tokens: tokens}
+	value, err := e.parseOr()
+	if err != nil {
+		return ognlValue{}, err
+	}
+	if e.pos != len(e.tokens) {
+		return ognlValue{}, errors.Errorf("unexpected token %q in expression %q", e.tokens[e.pos].text, expression)
+	}
+	return value, nil
+}
+
+type ognlTokenType int
+
+const (
+	ognlTokenIdentifier ognlTokenType = iota
+	ognlTokenString
+	ognlTokenNumber
+	ognlTokenOperator
+)
+
+type ognlToken struct {
+	tp   ognlTokenType
+	text string
+}
+
+// ognlKeywordOperators is the map from the keyword operator to the symbol operator.
+var ognlKeywordOperators = map[string]string{
+	"and": "&&",
+	"or":  "||",
+	"not": "!",
+	"eq":  "==",
+	"neq": "!=",
+	"lt":  "<",
+	"lte": "<=",
+	"gt":  ">",
+	"gte": ">=",
+}
+
+func tokenizeOGNL(expression string) ([]ognlToken, error) {
+	var tokens []ognlToken
+	runes := []rune(expression)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '\'' || r == '"':
+			var sb strings.Builder
+			j := i + 1
+			for ; j < len(runes) && runes[j] != r; j++ {
+				if runes[j] == '\\' && j+1 < len(runes) {
+					j++
+				}
+				sb.WriteRune(runes[j])
+			}
+			if j >= len(runes) {
+				return nil, errors.Errorf("unclosed string in expression %q", expression)
+			}
+			tokens = append(tokens, ognlToken{tp: ognlTokenString, text: sb.String()})
+			i = j + 1
+		case unicode.IsDigit(r):
+			j := i
+			for j < len(runes) && (unicode.IsDigit(runes[j]) || runes[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, ognlToken{tp: ognlTokenNumber, text: string(runes[i:j])})
+			// Skip the type suffix, e.g. "1L".
+			for j < len(runes) && strings.ContainsRune("lLdDfF", runes[j]) {
+				j++
+			}
+			i = j
+		case unicode.IsLetter(r) || r == '_' || r == '$':
+			j := i
+			for j < len(runes) && (unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j]) || strings.ContainsRune("_$.", runes[j])) {
+				j++
+			}
+			text := string(runes[i:j])
+			// The method call and the index access depend on the parameters, e.g. "list.size()" and "array[0]".
+			for j < len(runes) && (runes[j] == '(' || runes[j] == '[') {
+				depth := 0
+				for ; j < len(runes); j++ {
+					if runes[j] == '(' || runes[j] == '[' {
+						depth++
+					} else if runes[j] == ')' || runes[j] == ']' {
+						depth--
+						if depth == 0 {
+							break
+						}
+					}
+				}
+				if j >= len(runes) {
+					return nil, errors.Errorf("unclosed parenthesis in expression %q", expression)
+				}
+				j++
+				// Consume the chained access, e.g. "list.get(0).name".
+				for j < len(runes) && (unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j]) || strings.ContainsRune("_$.", runes[j])) {
+					j++
+				}
+				text = string(runes[i:j])
+			}
+			if operator, ok := ognlKeywordOperators[text]; ok {
+				tokens = append(tokens, ognlToken{tp: ognlTokenOperator, text: operator})
+			} else {
+				tokens = append(tokens, ognlToken{tp: ognlTokenIdentifier, text: text})
+			}
+			i = j
+		default:
+			operator := ""
+			for _, candidate := range []string{"==", "!=", "<=", ">=", "&&", "||", "<", ">", "!", "+", "(", ")"} {
+				if strings.HasPrefix(string(runes[i:]), candidate) {
+					operator = candidate
+					break
+				}
+			}
+			if operator == "" {
+				return nil, errors.Errorf("unexpected character %q in expression %q", r, expression)
+			}
+			tokens = append(tokens, ognlToken{tp: ognlTokenOperator, text: operator})
+			i += len(operator)
+		}
+	}
+	return tokens, nil
+}
+
+// ognlEvaluator is a recursive descent evaluator for the OGNL expression.
+//
+//	or         := and ("||" and)*
+//	and        := not ("&&" not)*
+//	not        := "!" not | comparison
+//	comparison := concat (("==" | "!=" | "<" | "<=" | ">" | ">=") concat)?
+//	concat     := primary ("+" primary)*
+//	primary    := "(" or ")" | string | number | identifier
+type ognlEvaluator struct {
+	ctx    *RestoreContext
+	tokens []ognlToken
+	pos    int
+}
+
+func (e *ognlEvaluator) peekOperator(operators ...string) (string, bool) {
+	if e.pos >= len(e.tokens) || e.tokens[e.pos].tp != ognlTokenOperator {
+		return "", false
+	}
+	for _, operator := range operators {
+		if e.tokens[e.pos].text == operator {
+			return operator, true
+		}
+	}
+	return "", false
+}
+
+func (e *ognlEvaluator) parseOr() (ognlValue, error) {
+	left, err := e.parseAnd()
+	if err != nil {
+		return ognlValue{}, err
+	}
+	for {
+		if _, ok := e.peekOperator("||"); !ok {
+			return left, nil
+		}
+		e.pos++
+		right, err := e.parseAnd()
+		if err != nil {
+			return ognlValue{}, err
+		}
+		l, r := left.toTestResult(), right.toTestResult()
+		switch {
+		case l == testResultTrue || r == testResultTrue:
+			left = ognlValue{kind: ognlValueBoolean, b: true}
+		case l == testResultFalse && r == testResultFalse:
+			left = ognlValue{kind: ognlValueBoolean, b: false}
+		default:
+			left = ognlValue{kind: ognlValueUnknown}
+		}
+	}
+}
+
+func (e *ognlEvaluator) parseAnd() (ognlValue, error) {
+	left, err := e.parseNot()
+	if err != nil {
+		return ognlValue{}, err
+	}
+	for {
+		if _, ok := e.peekOperator("&&"); !ok {
+			return left, nil
+		}
+		e.pos++
+		right, err := e.parseNot()
+		if err != nil {
+			return ognlValue{}, err
+		}
+		l, r := left.toTestResult(), right.toTestResult()
+		switch {
+		case l == testResultFalse || r == testResultFalse:
+			left = ognlValue{kind: ognlValueBoolean, b: false}
+		case l == testResultTrue && r == testResultTrue:
+			left = ognlValue{kind: ognlValueBoolean, b: true}
+		default:
+			left = ognlValue{kind: ognlValueUnknown}
+		}
+	}
+}
+
+func (e *ognlEvaluator) parseNot() (ognlValue, error) {
+	if _, ok := e.peekOperator("!"); ok {
+		e.pos++
+		value, err := e.parseNot()
+		if err != nil {
+			return ognlValue{}, err
+		}
+		switch value.toTestResult() {
+		case testResultTrue:
+			return ognlValue{kind: ognlValueBoolean, b: false}, nil
+		case testResultFalse:
+			return ognlValue{kind: ognlValueBoolean, b: true}, nil
+		}
+		return ognlValue{kind: ognlValueUnknown}, nil
+	}
+	return e.parseComparison()
+}
+
+func (e *ognlEvaluator) parseComparison() (ognlValue, error) {
+	left, err := e.parseConcat()
+	if err != nil {
+		return ognlValue{}, err
+	}
+	operator, ok := e.peekOperator("==", "!=", "<", "<=", ">", ">=")
+	if !ok {
+		return left, nil
+	}
+	e.pos++
+	right, err := e.parseConcat()
+	if err != nil {
+		return ognlValue{}, err
+	}
+	switch operator {
+	case "==", "!=":
+		equal, known := ognlEqual(left, right)
+		if !known {
+			return ognlValue{kind: ognlValueUnknown}, nil
+		}
+		return ognlValue{kind: ognlValueBoolean, b: equal == (operator == "==")}, nil
+	default:
+		l, lok := ognlNumber(left)
+		r, rok := ognlNumber(right)
+		if !lok || !rok {
+			return ognlValue{kind: ognlValueUnknown}, nil
+		}
+		var b bool
+		switch operator {
+		case "<":
+			b = l < r
+		case "<=":
+			b = l <= r
+		case ">":
+			b = l > r
+		case ">=":
+			b = l >= r
+		}
+		return ognlValue{kind: ognlValueBoolean, b: b}, nil
+	}
+}
+
+func (e *ognlEvaluator) parseConcat() (ognlValue, error) {
+	left, err := e.parsePrimary()
+	if err != nil {
+		return ognlValue{}, err
+	}
+	for {
+		if _, ok := e.peekOperator("+"); !ok {
+			return left, nil
+		}
+		e.pos++
+		right, err := e.parsePrimary()
+		if err != nil {
+			return ognlValue{}, err
+		}
+		switch {
+		case left.kind == ognlValueNumber && right.kind == ognlValueNumber:
+			left = ognlValue{kind: ognlValueNumber, num: left.num + right.num}
+		case left.kind == ognlValueString && right.isKnown():
+			left = ognlValue{kind: ognlValueString, str: left.str + ognlString(right)}
+		case right.kind == ognlValueString && left.isKnown():
+			left = ognlValue{kind: ognlValueString, str: ognlString(left) + right.str}
+		case left.kind == ognlValueString || right.kind == ognlValueString || left.kind == ognlValueNotNull || right.kind == ognlValueNotNull:
+			// The string concatenation is never null.
+			left = ognlValue{kind: ognlValueNotNull}
+		default:
+			left = ognlValue{kind: ognlValueUnknown}
+		}
+	}
+}
+
+func (e *ognlEvaluator) parsePrimary() (ognlValue, error) {
+	if e.pos >= len(e.tokens) {
+		return ognlValue{}, errors.New("unexpected end of expression")
+	}
+	token := e.tokens[e.pos]
+	e.pos++
+	switch token.tp {
+	case ognlTokenString:
+		return ognlValue{kind: ognlValueString, str: token.text}, nil
+	case ognlTokenNumber:
+		num, err := strconv.ParseFloat(token.text, 64)
+		if err != nil {
+			return ognlValue{}, errors.Wrapf(err, "invalid number %q", token.text)
+		}
+		return ognlValue{kind: ognlValueNumber, num: num}, nil
+	case ognlTokenIdentifier:
+		switch token.text {
+		case "null":
+			return ognlValue{kind: ognlValueNull}, nil
+		case "true":
+			return ognlValue{kind: ognlValueBoolean, b: true}, nil
+		case "false":
+			return ognlValue{kind: ognlValueBoolean, b: false}, nil
+		case "_databaseId":
+			if e.ctx.DatabaseID == "" {
+				return ognlValue{kind: ognlValueUnknown}, nil
+			}
+			return ognlValue{kind: ognlValueString, str: e.ctx.DatabaseID}, nil
+		}
+		if value, ok := e.ctx.bindings[token.text]; ok {
+			return value, nil
+		}
+		return ognlValue{kind: ognlValueUnknown}, nil
+	}
+	if token.text == "(" {
+		value, err := e.parseOr()
+		if err != nil {
+			return ognlValue{}, err
+		}
+		if _, ok := e.peekOperator(")"); !ok {
+			return ognlValue{}, errors.New("expected ')'")
+		}
+		e.pos++
+		return value, nil
+	}
+	return ognlValue{}, errors.Errorf("unexpected operator %q", token.text)
+}
+
+// ognlEqual returns whether the two values are equal, and false for known if it depends on the parameters.
+func ognlEqual(left, right ognlValue) (equal bool, known bool) {
+	if left.kind == ognlValueNull || right.kind == ognlValueNull {
+		if left.kind == ognlValueUnknown || right.kind == ognlValueUnknown {
+			return false, false
+		}
+		return left.kind == right.kind, true
+	}
+	if !left.isKnown() || !right.isKnown() {
+		return false, false
+	}
+	if l, ok := ognlNumber(left); ok {
+		if r, ok := ognlNumber(right); ok {
+			return l == r, true
+		}
+	}
+	return ognlString(left) == ognlString(right), true
+}
+
+func ognlNumber(v ognlValue) (float64, bool) {
+	switch v.kind {
+	case ognlValueNumber:
+		return v.num, true
+	case ognlValueString:
+		num, err := strconv.ParseFloat(v.str, 64)
+		if err != nil {
+			return 0, false
+		}
+		return num, true
+	}
+	return 0, false
+}
+
+func ognlString(v ognlValue) string {
+	switch v.kind {
+	case ognlValueString:
+		return v.str
+	case ognlValueNumber:
+		return strconv.FormatFloat(v.num, 'f', -1, 64)
+	case ognlValueBoolean:
+		return strconv.FormatBool(v.b)
+	}
+	return ""
+}
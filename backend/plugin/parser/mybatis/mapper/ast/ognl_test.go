@@ -0,0 +1,51 @@
+package ast
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluateTest(t *testing.T) {
+	tests := []struct {
+		expression string
+		databaseID string
+		bindings   map[string]ognlValue
+		want       testResult
+	}{
+		{expression: "name != null", want: testResultUnknown},
+		{expression: "name != null and name != ''", want: testResultUnknown},
+		{expression: "list.size() > 0", want: testResultUnknown},
+		{expression: "1 == 1", want: testResultTrue},
+		{expression: "1 gt 2 or 'a' eq 'a'", want: testResultTrue},
+		{expression: "not (1 lte 2)", want: testResultFalse},
+		{expression: "name != null && 1 == 2", want: testResultFalse},
+		{expression: "name != null || 1 == 1", want: testResultTrue},
+		{expression: "null", want: testResultFalse},
+		{expression: "_databaseId == 'mysql'", want: testResultUnknown},
+		{expression: "_databaseId == 'mysql'", databaseID: "mysql", want: testResultTrue},
+		{expression: "_databaseId == \"mysql\"", databaseID: "postgresql", want: testResultFalse},
+		{expression: "_databaseId != 'oracle' and enabled", databaseID: "mysql", bindings: map[string]ognlValue{"enabled": {kind: ognlValueBoolean, b: true}}, want: testResultTrue},
+		{expression: "pattern != null", bindings: map[string]ognlValue{"pattern": {kind: ognlValueNotNull}}, want: testResultTrue},
+		{expression: "pattern == 'a%'", bindings: map[string]ognlValue{"pattern": {kind: ognlValueString, str: "a%"}}, want: testResultTrue},
+		// Unsupported expressions are unknown.
+		{expression: "name instanceof String", want: testResultUnknown},
+		{expression: "'unclosed", want: testResultUnknown},
+	}
+
+	for _, test := range tests {
+		ctx := &RestoreContext{DatabaseID: test.databaseID, bindings: test.bindings}
+		require.Equal(t, test.want, evaluateTest(ctx, test.expression), test.expression)
+	}
+}
+
+func TestEvaluateOGNLConcat(t *testing.T) {
+	ctx := &RestoreContext{}
+	value, err := evaluateOGNL(ctx, "'%' + name + '%'")
+	require.NoError(t, err)
+	require.Equal(t, ognlValueNotNull, value.kind)
+
+	value, err = evaluateOGNL(ctx, "'t_' + 2023 + '_log'")
+	require.NoError(t, err)
+	require.Equal(t, ognlValue{kind: ognlValueString, str: "t_2023_log"}, value)
+}
@@ -35,10 +35,18 @@ type QueryNode struct {
 	Children []Node
 	// Line is the line of the <select><update><delete><insert> tag.
 	Line int
+	// DatabaseID is the databaseId attribute of the query node, the statement is only used for the data source
+	// with the same database id. Empty means the statement is used for all the data sources without specific one.
+	DatabaseID string
 }
 
 // RestoreSQL implements Node interface.
 func (n *QueryNode) RestoreSQL(ctx *RestoreContext, w io.Writer) error {
+	// The variables bound by <bind> element are only visible in the current statement.
+	ctx.bindings = make(map[string]ognlValue)
+	defer func() {
+		ctx.bindings = nil
+	}()
 	var sb strings.Builder
 	for _, node := range n.Children {
 		if err := node.RestoreSQL(ctx, &sb); err != nil {
@@ -77,7 +85,7 @@ func (n *QueryNode) AddChild(child Node) {
 func (*QueryNode) isChildAcceptable(child Node) bool {
 	// https://github.com/mybatis/mybatis-3/blob/master/src/main/resources/org/apache/ibatis/builder/xml/mybatis-3-mapper.dtd#L19
 	switch child.(type) {
-	case *DataNode, *IncludeNode, *TrimNode, *WhereNode, *SetNode, *ForEachNode, *ChooseNode, *SQLNode, *IfNode:
+	case *DataNode, *IncludeNode, *TrimNode, *WhereNode, *SetNode, *ForEachNode, *ChooseNode, *SQLNode, *IfNode, *BindNode:
 	default:
 		return false
 	}
@@ -99,8 +107,11 @@ func NewQueryNode(startEle *xml.StartElement, line int) *QueryNode {
 	}
 
 	for _, attr := range startEle.Attr {
-		if attr.Name.Local == "id" {
+		switch attr.Name.Local {
+		case "id":
 			n.ID = attr.Value
+		case "databaseId":
+			n.DatabaseID = attr.Value
 		}
 	}
 	n.Line = line
@@ -17,7 +17,10 @@ type Parser struct {
 	buf         []rune
 	cursor      uint
 	currentLine int
-	sqlMap      map[string]*ast.SQLNode
+	// namespace is the namespace of the <mapper> element being parsed.
+	namespace string
+	// sqlMap is the map of the <sql> elements, the key is the id and the fully qualified id likes "namespace.id".
+	sqlMap map[string]*ast.SQLNode
 }
 
 // NewParser creates a new mybatis mapper xml parser.
@@ -43,6 +46,28 @@ func (p *Parser) NewRestoreContext() *ast.RestoreContext {
 	}
 }
 
+// SQLFragments returns the <sql> elements in the parsed mapper xml, the key is the fully qualified id likes "namespace.id".
+// It is used to resolve the <include> elements referring to the <sql> elements in other mapper xml files.
+func (p *Parser) SQLFragments() map[string]*ast.SQLNode {
+	fragments := make(map[string]*ast.SQLNode)
+	for key, node := range p.sqlMap {
+		if node.Namespace != "" && key == node.Namespace+"."+node.ID {
+			fragments[key] = node
+		}
+	}
+	return fragments
+}
+
+// AddSQLFragments adds the <sql> elements in other mapper xml files, the ones in the current mapper xml take precedence.
+// It should be called after Parse.
+func (p *Parser) AddSQLFragments(fragments map[string]*ast.SQLNode) {
+	for key, node := range fragments {
+		if _, ok := p.sqlMap[key]; !ok {
+			p.sqlMap[key] = node
+		}
+	}
+}
+
 // Parse parses the mybatis mapper xml statements, building AST without recursion, returns the root node of the AST.
 func (p *Parser) Parse() (*ast.RootNode, error) {
 	root := &ast.RootNode{}
@@ -66,12 +91,23 @@ func (p *Parser) Parse() (*ast.RootNode, error) {
 		switch ele := token.(type) {
 		case xml.StartElement:
 			newNode := p.newNodeByStartElement(&ele)
-			if ele.Name.Local == "sql" {
+			switch ele.Name.Local {
+			case "mapper":
+				node, ok := newNode.(*ast.MapperNode)
+				if !ok {
+					return nil, errors.Errorf("failed convert to mapper node")
+				}
+				p.namespace = node.Namespace
+			case "sql":
 				node, ok := newNode.(*ast.SQLNode)
 				if !ok {
 					return nil, errors.Errorf("failed convert to SQL node")
 				}
-				p.sqlMap[newNode.(*ast.SQLNode).ID] = node
+				node.Namespace = p.namespace
+				p.sqlMap[node.ID] = node
+				if p.namespace != "" {
+					p.sqlMap[p.namespace+"."+node.ID] = node
+				}
 			}
 			startElementStack = append(startElementStack, &ele)
 			nodeStack = append(nodeStack, newNode)
@@ -162,6 +198,8 @@ func (p *Parser) newNodeByStartElement(startElement *xml.StartElement) ast.Node
 		return ast.NewIncludeNode(startElement)
 	case "property":
 		return ast.NewPropertyNode(startElement)
+	case "bind":
+		return ast.NewBindNode(startElement)
 	}
 	return ast.NewEmptyNode()
 }
@@ -142,3 +142,141 @@ func TestRestoreWithLineMapping(t *testing.T) {
 		require.Equal(t, tc.lineMapping, lineMapping)
 	}
 }
+
+func TestRestoreWithSQLFragments(t *testing.T) {
+	commonXML := `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE mapper PUBLIC "-//mybatis.org//DTD Mapper 3.0//EN" "http://mybatis.org/dtd/mybatis-3-mapper.dtd">
+<mapper namespace="com.bytebase.common">
+	<sql id="columns">id, name</sql>
+	<sql id="table">
+		<include refid="tableName"/>
+	</sql>
+	<sql id="tableName">${prefix}_user</sql>
+</mapper>
+`
+	xml := `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE mapper PUBLIC "-//mybatis.org//DTD Mapper 3.0//EN" "http://mybatis.org/dtd/mybatis-3-mapper.dtd">
+<mapper namespace="com.bytebase.user">
+	<sql id="tableName">t_user</sql>
+	<select id="selectUser">
+		SELECT <include refid="com.bytebase.common.columns"/> FROM <include refid="tableName"/>
+	</select>
+	<select id="selectCommonUser">
+		SELECT <include refid="com.bytebase.common.columns"/> FROM
+		<include refid="com.bytebase.common.table">
+			<property name="prefix" value="common"/>
+		</include>
+	</select>
+</mapper>
+`
+	commonParser := NewParser(commonXML)
+	_, err := commonParser.Parse()
+	require.NoError(t, err)
+	fragments := commonParser.SQLFragments()
+	require.Len(t, fragments, 3)
+	require.Contains(t, fragments, "com.bytebase.common.columns")
+
+	parser := NewParser(xml)
+	node, err := parser.Parse()
+	require.NoError(t, err)
+	parser.AddSQLFragments(fragments)
+	var sb strings.Builder
+	err = node.RestoreSQL(parser.NewRestoreContext().WithRestoreDataNodePlaceholder("?"), &sb)
+	require.NoError(t, err)
+	// The <include refid="tableName"> in the common mapper refers to the one in the common mapper.
+	require.Equal(t, "SELECT id, name FROM t_user;\nSELECT id, name FROM common_user;\n", sb.String())
+}
+
+func TestRestoreWithCircularInclude(t *testing.T) {
+	aXML := `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE mapper PUBLIC "-//mybatis.org//DTD Mapper 3.0//EN" "http://mybatis.org/dtd/mybatis-3-mapper.dtd">
+<mapper namespace="a">
+	<sql id="x">id, <include refid="b.y"/></sql>
+	<select id="selectX">
+		SELECT <include refid="x"/> FROM t
+	</select>
+</mapper>
+`
+	bXML := `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE mapper PUBLIC "-//mybatis.org//DTD Mapper 3.0//EN" "http://mybatis.org/dtd/mybatis-3-mapper.dtd">
+<mapper namespace="b">
+	<sql id="y">name, <include refid="a.x"/></sql>
+</mapper>
+`
+	bParser := NewParser(bXML)
+	_, err := bParser.Parse()
+	require.NoError(t, err)
+
+	parser := NewParser(aXML)
+	node, err := parser.Parse()
+	require.NoError(t, err)
+	parser.AddSQLFragments(bParser.SQLFragments())
+	var sb strings.Builder
+	err = node.RestoreSQL(parser.NewRestoreContext().WithRestoreDataNodePlaceholder("?"), &sb)
+	require.ErrorContains(t, err, "circular include of sql a.x")
+
+	// Including the same sql element more than once is not circular.
+	xml := `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE mapper PUBLIC "-//mybatis.org//DTD Mapper 3.0//EN" "http://mybatis.org/dtd/mybatis-3-mapper.dtd">
+<mapper namespace="c">
+	<sql id="columns">id</sql>
+	<select id="selectColumns">
+		SELECT <include refid="columns"/> FROM t WHERE <include refid="columns"/> = 1
+	</select>
+</mapper>
+`
+	parser = NewParser(xml)
+	node, err = parser.Parse()
+	require.NoError(t, err)
+	sb.Reset()
+	err = node.RestoreSQL(parser.NewRestoreContext().WithRestoreDataNodePlaceholder("?"), &sb)
+	require.NoError(t, err)
+	require.Equal(t, "SELECT id FROM t WHERE id = 1;\n", sb.String())
+}
+
+func TestRestoreWithDatabaseID(t *testing.T) {
+	xml := `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE mapper PUBLIC "-//mybatis.org//DTD Mapper 3.0//EN" "http://mybatis.org/dtd/mybatis-3-mapper.dtd">
+<mapper namespace="com.bytebase.user">
+	<select id="selectNow">SELECT CURRENT_TIMESTAMP</select>
+	<select id="selectNow" databaseId="mysql">SELECT NOW()</select>
+	<select id="selectNow" databaseId="oracle">SELECT SYSDATE FROM DUAL</select>
+	<select id="selectUser">
+		<bind name="pattern" value="'%' + name + '%'"/>
+		SELECT * FROM t_user
+		<where>
+			<if test="_databaseId == 'mysql'">AND name LIKE CONCAT('%', #{name}, '%')</if>
+			<if test="_databaseId == 'postgresql'">AND name ILIKE #{pattern}</if>
+			<if test="pattern != null">AND deleted = 0</if>
+		</where>
+		<choose>
+			<when test="_databaseId == 'postgresql'">LIMIT 10</when>
+			<when test="_databaseId == 'mysql'">LIMIT 20</when>
+			<otherwise>FETCH FIRST 10 ROWS ONLY</otherwise>
+		</choose>
+	</select>
+</mapper>
+`
+	tests := []struct {
+		databaseID string
+		want       string
+	}{
+		{
+			databaseID: "mysql",
+			want:       "SELECT NOW();\nSELECT * FROM t_user WHERE name LIKE CONCAT('%', ?, '%')  AND deleted = 0    LIMIT 20;\n",
+		},
+		{
+			databaseID: "postgresql",
+			want:       "SELECT CURRENT_TIMESTAMP;\nSELECT * FROM t_user WHERE name ILIKE ?  AND deleted = 0    LIMIT 10;\n",
+		},
+	}
+	for _, test := range tests {
+		parser := NewParser(xml)
+		node, err := parser.Parse()
+		require.NoError(t, err)
+		var sb strings.Builder
+		err = node.RestoreSQL(parser.NewRestoreContext().WithRestoreDataNodePlaceholder("?").WithDatabaseID(test.databaseID), &sb)
+		require.NoError(t, err)
+		require.Equal(t, test.want, sb.String())
+	}
+}
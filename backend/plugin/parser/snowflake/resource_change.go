@@ -0,0 +1,102 @@
+package snowflake
+
+import (
+	"sort"
+
+	"github.com/antlr4-go/antlr/v4"
+	parser "github.com/bytebase/snowsql-parser"
+
+	"github.com/bytebase/bytebase/backend/plugin/parser/base"
+	storepb "github.com/bytebase/bytebase/proto/generated-go/store"
+)
+
+func init() {
+	base.RegisterExtractChangedResourcesFunc(storepb.Engine_SNOWFLAKE, extractChangedResources)
+}
+
+func extractChangedResources(currentDatabase string, currentSchema string, statement string) ([]base.SchemaResource, error) {
+	parseResult, err := ParseSnowSQL(statement)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &snowflakeChangedResourceExtractListener{
+		currentDatabase: currentDatabase,
+		currentSchema:   currentSchema,
+		resourceMap:     make(map[string]base.SchemaResource),
+	}
+
+	var result []base.SchemaResource
+	antlr.ParseTreeWalkerDefault.Walk(l, parseResult.Tree)
+	for _, resource := range l.resourceMap {
+		result = append(result, resource)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].String() < result[j].String()
+	})
+
+	return result, nil
+}
+
+type snowflakeChangedResourceExtractListener struct {
+	*parser.BaseSnowflakeParserListener
+
+	currentDatabase string
+	currentSchema   string
+	resourceMap     map[string]base.SchemaResource
+}
+
+// EnterCreate_table is called when production create_table is entered.
+func (l *snowflakeChangedResourceExtractListener) EnterCreate_table(ctx *parser.Create_tableContext) {
+	l.addTable(ctx.Object_name())
+}
+
+// EnterCreate_table_as_select is called when production create_table_as_select is entered.
+func (l *snowflakeChangedResourceExtractListener) EnterCreate_table_as_select(ctx *parser.Create_table_as_selectContext) {
+	l.addTable(ctx.Object_name())
+}
+
+// EnterAlter_table is called when production alter_table is entered.
+func (l *snowflakeChangedResourceExtractListener) EnterAlter_table(ctx *parser.Alter_tableContext) {
+	// RENAME TO and SWAP WITH change both tables.
+	for _, objectName := range ctx.AllObject_name() {
+		l.addTable(objectName)
+	}
+}
+
+// EnterDrop_table is called when production drop_table is entered.
+func (l *snowflakeChangedResourceExtractListener) EnterDrop_table(ctx *parser.Drop_tableContext) {
+	l.addTable(ctx.Object_name())
+}
+
+// EnterUndrop_table is called when production undrop_table is entered.
+func (l *snowflakeChangedResourceExtractListener) EnterUndrop_table(ctx *parser.Undrop_tableContext) {
+	l.addTable(ctx.Object_name())
+}
+
+// EnterTruncate_table is called when production truncate_table is entered.
+func (l *snowflakeChangedResourceExtractListener) EnterTruncate_table(ctx *parser.Truncate_tableContext) {
+	l.addTable(ctx.Object_name())
+}
+
+func (l *snowflakeChangedResourceExtractListener) addTable(objectName parser.IObject_nameContext) {
+	if objectName == nil {
+		return
+	}
+	resource := base.SchemaResource{
+		Database: l.currentDatabase,
+		Schema:   l.currentSchema,
+		Table:    NormalizeSnowSQLObjectNamePart(objectName.GetO()),
+	}
+	if resource.Table == "" {
+		return
+	}
+	if d := NormalizeSnowSQLObjectNamePart(objectName.GetD()); d != "" {
+		resource.Database = d
+	}
+	if s := NormalizeSnowSQLObjectNamePart(objectName.GetS()); s != "" {
+		resource.Schema = s
+	}
+	l.resourceMap[resource.String()] = resource
+}
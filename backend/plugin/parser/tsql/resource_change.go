@@ -0,0 +1,98 @@
+package tsql
+
+import (
+	"sort"
+
+	"github.com/antlr4-go/antlr/v4"
+	parser "github.com/bytebase/tsql-parser"
+
+	"github.com/bytebase/bytebase/backend/plugin/parser/base"
+	storepb "github.com/bytebase/bytebase/proto/generated-go/store"
+)
+
+func init() {
+	base.RegisterExtractChangedResourcesFunc(storepb.Engine_MSSQL, extractChangedResources)
+}
+
+func extractChangedResources(currentDatabase string, currentSchema string, statement string) ([]base.SchemaResource, error) {
+	parseResult, err := ParseTSQL(statement)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &tsqlChangedResourceExtractListener{
+		currentDatabase: currentDatabase,
+		currentSchema:   currentSchema,
+		resourceMap:     make(map[string]base.SchemaResource),
+	}
+
+	var result []base.SchemaResource
+	antlr.ParseTreeWalkerDefault.Walk(l, parseResult.Tree)
+	for _, resource := range l.resourceMap {
+		result = append(result, resource)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].String() < result[j].String()
+	})
+
+	return result, nil
+}
+
+type tsqlChangedResourceExtractListener struct {
+	*parser.BaseTSqlParserListener
+
+	currentDatabase string
+	currentSchema   string
+	resourceMap     map[string]base.SchemaResource
+}
+
+// EnterCreate_table is called when production create_table is entered.
+func (l *tsqlChangedResourceExtractListener) EnterCreate_table(ctx *parser.Create_tableContext) {
+	l.addTable(ctx.Table_name())
+}
+
+// EnterAlter_table is called when production alter_table is entered.
+func (l *tsqlChangedResourceExtractListener) EnterAlter_table(ctx *parser.Alter_tableContext) {
+	// The first table name is the altered table, the others are referenced by the constraints.
+	l.addTable(ctx.Table_name(0))
+}
+
+// EnterDrop_table is called when production drop_table is entered.
+func (l *tsqlChangedResourceExtractListener) EnterDrop_table(ctx *parser.Drop_tableContext) {
+	for _, tableName := range ctx.AllTable_name() {
+		l.addTable(tableName)
+	}
+}
+
+// EnterTruncate_table is called when production truncate_table is entered.
+func (l *tsqlChangedResourceExtractListener) EnterTruncate_table(ctx *parser.Truncate_tableContext) {
+	l.addTable(ctx.Table_name())
+}
+
+// EnterCreate_index is called when production create_index is entered.
+func (l *tsqlChangedResourceExtractListener) EnterCreate_index(ctx *parser.Create_indexContext) {
+	l.addTable(ctx.Table_name())
+}
+
+func (l *tsqlChangedResourceExtractListener) addTable(tableName parser.ITable_nameContext) {
+	if tableName == nil {
+		return
+	}
+	database, schema, table := splitTableNameIntoNormalizedParts(tableName)
+	if table == "" {
+		return
+	}
+	resource := base.SchemaResource{
+		Database: l.currentDatabase,
+		Schema:   l.currentSchema,
+		Table:    table,
+	}
+	if database != "" {
+		resource.Database = database
+	}
+	if schema != "" {
+		resource.Schema = schema
+	}
+	l.resourceMap[resource.String()] = resource
+}
@@ -0,0 +1,1189 @@
+// Package bitbucketdc is the plugin for Bitbucket Data Center (formerly Bitbucket Server).
+package bitbucketdc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/backend/common"
+	"github.com/bytebase/bytebase/backend/plugin/vcs"
+	"github.com/bytebase/bytebase/backend/plugin/vcs/internal/oauth"
+)
+
+const (
+	// apiPageSize is the default page size when making API requests.
+	apiPageSize = 100
+
+	// emptyCommit is the commit ID of the ref before it is created.
+	emptyCommit = "0000000000000000000000000000000000000000"
+)
+
+func init() {
+	vcs.Register(vcs.BitbucketDataCenter, newProvider)
+}
+
+var _ vcs.Provider = (*Provider)(nil)
+
+// Provider is a Bitbucket Data Center VCS provider.
+//
+// The repository ID is in the form of "PROJECT_KEY/repository-slug", and the
+// requests are authorized with either OAuth tokens or personal/HTTP access
+// tokens. The latter ones have no refresh tokens and cannot be refreshed.
+type Provider struct {
+	client *http.Client
+}
+
+func newProvider(config vcs.ProviderConfig) vcs.Provider {
+	if config.Client == nil {
+		config.Client = &http.Client{}
+	}
+	return &Provider{
+		client: config.Client,
+	}
+}
+
+// APIURL returns the API URL path of Bitbucket Data Center.
+func (*Provider) APIURL(instanceURL string) string {
+	return fmt.Sprintf("%s/rest/api/1.0", instanceURL)
+}
+
+// repositoryURL returns the API URL of the repository with the ID in the form
+// of "PROJECT_KEY/repository-slug".
+func repositoryURL(instanceURL, repositoryID string) (string, error) {
+	projectKey, slug, ok := strings.Cut(repositoryID, "/")
+	if !ok || projectKey == "" || slug == "" {
+		return "", errors.Errorf("invalid repository ID %q, expecting the form of \"PROJECT_KEY/repository-slug\"", repositoryID)
+	}
+	return fmt.Sprintf("%s/rest/api/1.0/projects/%s/repos/%s", instanceURL, url.PathEscape(projectKey), url.PathEscape(slug)), nil
+}
+
+// oauthResponse is a Bitbucket Data Center OAuth response.
+type oauthResponse struct {
+	AccessToken      string `json:"access_token"`
+	RefreshToken     string `json:"refresh_token"`
+	ExpiresIn        int64  `json:"expires_in"`
+	Error            string `json:"error,omitempty"`
+	ErrorDescription string `json:"error_description,omitempty"`
+}
+
+// toVCSOAuthToken converts the response to *vcs.OAuthToken.
+func (o oauthResponse) toVCSOAuthToken(createdAt int64) *vcs.OAuthToken {
+	oauthToken := &vcs.OAuthToken{
+		AccessToken:  o.AccessToken,
+		RefreshToken: o.RefreshToken,
+		ExpiresIn:    o.ExpiresIn,
+		CreatedAt:    createdAt,
+	}
+	if oauthToken.ExpiresIn != 0 {
+		oauthToken.ExpiresTs = oauthToken.CreatedAt + oauthToken.ExpiresIn
+	}
+	return oauthToken
+}
+
+// ExchangeOAuthToken exchanges OAuth content with the provided authorization code.
+//
+// Docs: https://confluence.atlassian.com/bitbucketserver/bitbucket-oauth-2-0-provider-api-1108483661.html
+func (p *Provider) ExchangeOAuthToken(ctx context.Context, instanceURL string, oauthExchange *common.OAuthExchange) (*vcs.OAuthToken, error) {
+	params := &url.Values{}
+	params.Set("client_id", oauthExchange.ClientID)
+	params.Set("client_secret", oauthExchange.ClientSecret)
+	params.Set("code", oauthExchange.Code)
+	params.Set("redirect_uri", oauthExchange.RedirectURL)
+	params.Set("grant_type", "authorization_code")
+	url := fmt.Sprintf("%s/rest/oauth2/latest/token", instanceURL)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, strings.NewReader(params.Encode()))
+	if err != nil {
+		return nil, errors.Wrapf(err, "construct POST %s", url)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	createdAt := time.Now().Unix()
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to exchange OAuth token")
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read OAuth response body, code %v", resp.StatusCode)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	oauthResp := new(oauthResponse)
+	if err := json.Unmarshal(body, oauthResp); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal OAuth response body, code %v", resp.StatusCode)
+	}
+	if oauthResp.Error != "" {
+		return nil, errors.Errorf("failed to exchange OAuth token, error: %v, error_description: %v", oauthResp.Error, oauthResp.ErrorDescription)
+	}
+	return oauthResp.toVCSOAuthToken(createdAt), nil
+}
+
+// Link is the API message for link.
+type Link struct {
+	Href string `json:"href"`
+	Name string `json:"name,omitempty"`
+}
+
+// Links is the API message for links.
+type Links struct {
+	Self []Link `json:"self"`
+}
+
+// Project is the API message for Bitbucket Data Center project.
+type Project struct {
+	Key  string `json:"key"`
+	Name string `json:"name"`
+}
+
+// Repository is the API message for Bitbucket Data Center repository.
+type Repository struct {
+	ID      int     `json:"id"`
+	Slug    string  `json:"slug"`
+	Name    string  `json:"name"`
+	Project Project `json:"project"`
+	Links   Links   `json:"links"`
+}
+
+// FullPath returns the full path of the repository in the form of
+// "PROJECT_KEY/repository-slug", which is used as the repository ID.
+func (r Repository) FullPath() string {
+	return fmt.Sprintf("%s/%s", r.Project.Key, r.Slug)
+}
+
+// WebURL returns the URL to browse the repository.
+func (r Repository) WebURL() string {
+	if len(r.Links.Self) == 0 {
+		return ""
+	}
+	return strings.TrimSuffix(r.Links.Self[0].Href, "/browse")
+}
+
+// User is the API message for Bitbucket Data Center user.
+type User struct {
+	Name         string `json:"name"`
+	EmailAddress string `json:"emailAddress"`
+	DisplayName  string `json:"displayName"`
+	Slug         string `json:"slug"`
+}
+
+// CommitParent is the API message for the parent of a commit.
+type CommitParent struct {
+	ID string `json:"id"`
+}
+
+// Commit is the API message for Bitbucket Data Center commit.
+type Commit struct {
+	ID     string `json:"id"`
+	Author User   `json:"author"`
+	// AuthorTimestamp is the Unix timestamp in milliseconds.
+	AuthorTimestamp int64          `json:"authorTimestamp"`
+	Message         string         `json:"message"`
+	Parents         []CommitParent `json:"parents"`
+}
+
+// page is the API message for the paged responses.
+type page[T any] struct {
+	Values        []T  `json:"values"`
+	IsLastPage    bool `json:"isLastPage"`
+	NextPageStart int  `json:"nextPageStart"`
+}
+
+// fetchPaginated fetches the values of all pages from the given paged API URL.
+func fetchPaginated[T any](ctx context.Context, client *http.Client, oauthCtx *common.OauthContext, instanceURL, apiURL string) ([]T, error) {
+	separator := "?"
+	if strings.Contains(apiURL, "?") {
+		separator = "&"
+	}
+
+	var values []T
+	start := 0
+	for {
+		url := fmt.Sprintf("%s%sstart=%d&limit=%d", apiURL, separator, start, apiPageSize)
+		code, _, body, err := oauth.Get(ctx, client, url, &oauthCtx.AccessToken, tokenRefresher(instanceURL, oauthCtx))
+		if err != nil {
+			return nil, errors.Wrapf(err, "GET %s", url)
+		}
+
+		if code == http.StatusNotFound {
+			return nil, common.Errorf(common.NotFound, "failed to fetch paged list from URL %s", url)
+		} else if code >= 300 {
+			return nil, errors.Errorf("failed to fetch paged list from URL %s, status code: %d, body: %s",
+				url,
+				code,
+				body,
+			)
+		}
+
+		var resp page[T]
+		if err := json.Unmarshal([]byte(body), &resp); err != nil {
+			return nil, errors.Wrap(err, "unmarshal body")
+		}
+		values = append(values, resp.Values...)
+		if resp.IsLastPage || len(resp.Values) == 0 {
+			break
+		}
+		start = resp.NextPageStart
+	}
+	return values, nil
+}
+
+// FetchCommitByID fetches the commit data by its ID from the repository.
+//
+// Docs: https://developer.atlassian.com/server/bitbucket/rest/v811/api-group-repository/#api-api-latest-projects-projectkey-repos-repositoryslug-commits-commitid-get
+func (p *Provider) FetchCommitByID(ctx context.Context, oauthCtx *common.OauthContext, instanceURL, repositoryID, commitID string) (*vcs.Commit, error) {
+	repoURL, err := repositoryURL(instanceURL, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+	url := fmt.Sprintf("%s/commits/%s", repoURL, url.PathEscape(commitID))
+	code, _, body, err := oauth.Get(ctx, p.client, url, &oauthCtx.AccessToken, tokenRefresher(instanceURL, oauthCtx))
+	if err != nil {
+		return nil, errors.Wrapf(err, "GET %s", url)
+	}
+
+	if code == http.StatusNotFound {
+		return nil, common.Errorf(common.NotFound, "failed to fetch commit data from URL %s", url)
+	} else if code >= 300 {
+		return nil, errors.Errorf("failed to fetch commit data from URL %s, status code: %d, body: %s",
+			url,
+			code,
+			body,
+		)
+	}
+
+	commit := &Commit{}
+	if err := json.Unmarshal([]byte(body), commit); err != nil {
+		return nil, errors.Wrap(err, "unmarshal body")
+	}
+
+	return &vcs.Commit{
+		ID:          commit.ID,
+		AuthorName:  commit.Author.Name,
+		AuthorEmail: commit.Author.EmailAddress,
+		CreatedTs:   commit.AuthorTimestamp / 1000,
+	}, nil
+}
+
+// ListCommits lists the commits reachable from the until commit but not from
+// the since commit, from the newest to the oldest. All the commits reachable
+// from the until commit are listed if the since commit is empty.
+//
+// Bitbucket Data Center does not include the commits in the push event, so we
+// need to list them by ourselves.
+//
+// Docs: https://developer.atlassian.com/server/bitbucket/rest/v811/api-group-repository/#api-api-latest-projects-projectkey-repos-repositoryslug-commits-get
+func ListCommits(ctx context.Context, oauthCtx *common.OauthContext, instanceURL, repositoryID, since, until string) ([]Commit, error) {
+	repoURL, err := repositoryURL(instanceURL, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+	params := url.Values{}
+	params.Set("until", until)
+	if since != "" && since != emptyCommit {
+		params.Set("since", since)
+	}
+	url := fmt.Sprintf("%s/commits?%s", repoURL, params.Encode())
+	commits, err := fetchPaginated[Commit](ctx, &http.Client{}, oauthCtx, instanceURL, url)
+	if err != nil {
+		return nil, errors.Wrap(err, "fetch paginated list")
+	}
+	return commits, nil
+}
+
+// ChangePath is the API message for the path of a change.
+type ChangePath struct {
+	ToString string `json:"toString"`
+}
+
+// Change is the API message for a changed file.
+type Change struct {
+	Path ChangePath `json:"path"`
+	// The change type in Bitbucket Data Center.
+	// Available values: "ADD", "COPY", "DELETE", "MODIFY", "MOVE", "UNKNOWN"
+	Type string `json:"type"`
+}
+
+// GetDiffFileList gets the diff files list between two commits.
+//
+// Docs: https://developer.atlassian.com/server/bitbucket/rest/v811/api-group-repository/#api-api-latest-projects-projectkey-repos-repositoryslug-changes-get
+func (p *Provider) GetDiffFileList(ctx context.Context, oauthCtx *common.OauthContext, instanceURL, repositoryID, beforeCommit, afterCommit string) ([]vcs.FileDiff, error) {
+	repoURL, err := repositoryURL(instanceURL, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+	params := url.Values{}
+	params.Set("until", afterCommit)
+	// The changes are compared with the first parent of the after commit if the
+	// since commit is not specified.
+	if beforeCommit != "" && beforeCommit != emptyCommit {
+		params.Set("since", beforeCommit)
+	}
+	url := fmt.Sprintf("%s/changes?%s", repoURL, params.Encode())
+	changes, err := fetchPaginated[Change](ctx, p.client, oauthCtx, instanceURL, url)
+	if err != nil {
+		return nil, errors.Wrap(err, "fetch paginated list")
+	}
+
+	var ret []vcs.FileDiff
+	for _, change := range changes {
+		ret = append(ret, vcs.FileDiff{
+			Path: change.Path.ToString,
+			Type: convertFileDiffType(change.Type),
+		})
+	}
+	return ret, nil
+}
+
+// convertFileDiffType converts the change type in Bitbucket Data Center to
+// vcs.FileDiffType. The moved file is treated as a new file in the new path.
+func convertFileDiffType(changeType string) vcs.FileDiffType {
+	switch changeType {
+	case "ADD", "COPY", "MOVE":
+		return vcs.FileDiffTypeAdded
+	case "MODIFY":
+		return vcs.FileDiffTypeModified
+	case "DELETE":
+		return vcs.FileDiffTypeRemoved
+	}
+	return vcs.FileDiffTypeUnknown
+}
+
+// FetchAllRepositoryList fetches all repositories where the authenticated user
+// has admin permissions, which is required to create webhook in the repository.
+//
+// Docs: https://developer.atlassian.com/server/bitbucket/rest/v811/api-group-repository/#api-api-latest-repos-get
+func (p *Provider) FetchAllRepositoryList(ctx context.Context, oauthCtx *common.OauthContext, instanceURL string) ([]*vcs.Repository, error) {
+	url := fmt.Sprintf("%s/repos?permission=REPO_ADMIN", p.APIURL(instanceURL))
+	repos, err := fetchPaginated[Repository](ctx, p.client, oauthCtx, instanceURL, url)
+	if err != nil {
+		return nil, errors.Wrap(err, "fetch paginated list")
+	}
+
+	var allRepos []*vcs.Repository
+	for _, r := range repos {
+		allRepos = append(allRepos,
+			&vcs.Repository{
+				ID:       r.FullPath(),
+				Name:     r.Name,
+				FullPath: r.FullPath(),
+				WebURL:   r.WebURL(),
+			},
+		)
+	}
+	return allRepos, nil
+}
+
+// FetchRepositoryFileList fetches the all files from the given repository tree
+// recursively.
+//
+// Docs: https://developer.atlassian.com/server/bitbucket/rest/v811/api-group-repository/#api-api-latest-projects-projectkey-repos-repositoryslug-files-path-get
+func (p *Provider) FetchRepositoryFileList(ctx context.Context, oauthCtx *common.OauthContext, instanceURL, repositoryID, ref, filePath string) ([]*vcs.RepositoryTreeNode, error) {
+	repoURL, err := repositoryURL(instanceURL, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+	filePath = strings.Trim(filePath, "/")
+	filesURL := fmt.Sprintf("%s/files", repoURL)
+	if filePath != "" {
+		filesURL += "/" + escapePath(filePath)
+	}
+	url := fmt.Sprintf("%s?at=%s", filesURL, url.QueryEscape(ref))
+	// The file paths are relative to the given path.
+	files, err := fetchPaginated[string](ctx, p.client, oauthCtx, instanceURL, url)
+	if err != nil {
+		return nil, errors.Wrap(err, "fetch paginated list")
+	}
+
+	var allTreeNodes []*vcs.RepositoryTreeNode
+	for _, file := range files {
+		allTreeNodes = append(allTreeNodes,
+			&vcs.RepositoryTreeNode{
+				Path: path.Join(filePath, file),
+				Type: "blob",
+			},
+		)
+	}
+	return allTreeNodes, nil
+}
+
+// CreateFile creates a file at given path in the repository.
+//
+// Docs: https://developer.atlassian.com/server/bitbucket/rest/v811/api-group-repository/#api-api-latest-projects-projectkey-repos-repositoryslug-browse-path-put
+func (p *Provider) CreateFile(ctx context.Context, oauthCtx *common.OauthContext, instanceURL, repositoryID, filePath string, fileCommitCreate vcs.FileCommitCreate) error {
+	return p.commitFile(ctx, oauthCtx, instanceURL, repositoryID, filePath, fileCommitCreate, "")
+}
+
+// OverwriteFile overwrites an existing file at given path in the repository.
+//
+// Docs: https://developer.atlassian.com/server/bitbucket/rest/v811/api-group-repository/#api-api-latest-projects-projectkey-repos-repositoryslug-browse-path-put
+func (p *Provider) OverwriteFile(ctx context.Context, oauthCtx *common.OauthContext, instanceURL, repositoryID, filePath string, fileCommitCreate vcs.FileCommitCreate) error {
+	return p.commitFile(ctx, oauthCtx, instanceURL, repositoryID, filePath, fileCommitCreate, fileCommitCreate.LastCommitID)
+}
+
+// commitFile commits the file at given path in the repository. Updating an
+// existing file requires the commit ID that the file is last changed, which is
+// used to detect conflicting writes.
+func (p *Provider) commitFile(ctx context.Context, oauthCtx *common.OauthContext, instanceURL, repositoryID, filePath string, fileCommitCreate vcs.FileCommitCreate, sourceCommitID string) error {
+	repoURL, err := repositoryURL(instanceURL, repositoryID)
+	if err != nil {
+		return err
+	}
+
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	_ = w.WriteField("content", fileCommitCreate.Content)
+	_ = w.WriteField("message", fileCommitCreate.CommitMessage)
+	_ = w.WriteField("branch", fileCommitCreate.Branch)
+	if sourceCommitID != "" {
+		_ = w.WriteField("sourceCommitId", sourceCommitID)
+	}
+	_ = w.Close()
+
+	url := fmt.Sprintf("%s/browse/%s", repoURL, escapePath(filePath))
+	code, _, resp, err := oauth.PutWithHeader(
+		ctx,
+		p.client,
+		url,
+		&oauthCtx.AccessToken,
+		&body,
+		tokenRefresher(instanceURL, oauthCtx),
+		map[string]string{
+			"Content-Type": w.FormDataContentType(),
+		},
+	)
+	if err != nil {
+		return errors.Wrapf(err, "PUT %s", url)
+	}
+
+	if code == http.StatusNotFound {
+		return common.Errorf(common.NotFound, "failed to create/update file through URL %s", url)
+	} else if code >= 300 {
+		return errors.Errorf("failed to create/update file through URL %s, status code: %d, body: %s",
+			url,
+			code,
+			resp,
+		)
+	}
+	return nil
+}
+
+// ReadFileMeta reads the metadata of the given file in the repository.
+//
+// Docs: https://developer.atlassian.com/server/bitbucket/rest/v811/api-group-repository/#api-api-latest-projects-projectkey-repos-repositoryslug-browse-path-get
+func (p *Provider) ReadFileMeta(ctx context.Context, oauthCtx *common.OauthContext, instanceURL, repositoryID, filePath string, refInfo vcs.RefInfo) (*vcs.FileMeta, error) {
+	repoURL, err := repositoryURL(instanceURL, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+	url := fmt.Sprintf("%s/browse/%s?at=%s&type=true", repoURL, escapePath(filePath), url.QueryEscape(refInfo.RefName))
+	code, _, body, err := oauth.Get(ctx, p.client, url, &oauthCtx.AccessToken, tokenRefresher(instanceURL, oauthCtx))
+	if err != nil {
+		return nil, errors.Wrapf(err, "GET %s", url)
+	}
+
+	if code == http.StatusNotFound {
+		return nil, common.Errorf(common.NotFound, "failed to read file meta from URL %s", url)
+	} else if code >= 300 {
+		return nil,
+			errors.Errorf("failed to read file meta from URL %s, status code: %d, body: %s",
+				url,
+				code,
+				body,
+			)
+	}
+
+	var fileType struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal([]byte(body), &fileType); err != nil {
+		return nil, errors.Wrap(err, "unmarshal body")
+	}
+	if fileType.Type != "FILE" {
+		return nil, errors.Errorf("%q is not a file", filePath)
+	}
+
+	// The last commit changing the file is required to overwrite the file.
+	lastCommit, err := p.fetchLastCommit(ctx, oauthCtx, instanceURL, repoURL, filePath, refInfo.RefName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &vcs.FileMeta{
+		Name:         path.Base(filePath),
+		Path:         filePath,
+		LastCommitID: lastCommit.ID,
+	}, nil
+}
+
+// fetchLastCommit fetches the last commit changing the file from the given ref.
+func (p *Provider) fetchLastCommit(ctx context.Context, oauthCtx *common.OauthContext, instanceURL, repoURL, filePath, ref string) (*Commit, error) {
+	params := url.Values{}
+	params.Set("path", filePath)
+	params.Set("until", ref)
+	params.Set("limit", "1")
+	url := fmt.Sprintf("%s/commits?%s", repoURL, params.Encode())
+	code, _, body, err := oauth.Get(ctx, p.client, url, &oauthCtx.AccessToken, tokenRefresher(instanceURL, oauthCtx))
+	if err != nil {
+		return nil, errors.Wrapf(err, "GET %s", url)
+	}
+
+	if code == http.StatusNotFound {
+		return nil, common.Errorf(common.NotFound, "failed to fetch last commit from URL %s", url)
+	} else if code >= 300 {
+		return nil, errors.Errorf("failed to fetch last commit from URL %s, status code: %d, body: %s",
+			url,
+			code,
+			body,
+		)
+	}
+
+	var resp page[Commit]
+	if err := json.Unmarshal([]byte(body), &resp); err != nil {
+		return nil, errors.Wrap(err, "unmarshal body")
+	}
+	if len(resp.Values) == 0 {
+		return nil, common.Errorf(common.NotFound, "no commit found for file %q", filePath)
+	}
+	return &resp.Values[0], nil
+}
+
+// ReadFileContent reads the content of the given file in the repository.
+//
+// Docs: https://developer.atlassian.com/server/bitbucket/rest/v811/api-group-repository/#api-api-latest-projects-projectkey-repos-repositoryslug-raw-path-get
+func (p *Provider) ReadFileContent(ctx context.Context, oauthCtx *common.OauthContext, instanceURL, repositoryID, filePath string, refInfo vcs.RefInfo) (string, error) {
+	repoURL, err := repositoryURL(instanceURL, repositoryID)
+	if err != nil {
+		return "", err
+	}
+	url := fmt.Sprintf("%s/raw/%s?at=%s", repoURL, escapePath(filePath), url.QueryEscape(refInfo.RefName))
+	code, _, body, err := oauth.Get(ctx, p.client, url, &oauthCtx.AccessToken, tokenRefresher(instanceURL, oauthCtx))
+	if err != nil {
+		return "", errors.Wrapf(err, "GET %s", url)
+	}
+
+	if code == http.StatusNotFound {
+		return "", common.Errorf(common.NotFound, "failed to read file content from URL %s", url)
+	} else if code >= 300 {
+		return "",
+			errors.Errorf("failed to read file content from URL %s, status code: %d, body: %s",
+				url,
+				code,
+				body,
+			)
+	}
+	return body, nil
+}
+
+// Branch is the API message for Bitbucket Data Center branch.
+type Branch struct {
+	// ID is the full ref name, e.g. "refs/heads/main".
+	ID           string `json:"id"`
+	DisplayID    string `json:"displayId"`
+	LatestCommit string `json:"latestCommit"`
+}
+
+// GetBranch gets the given branch in the repository.
+//
+// Docs: https://developer.atlassian.com/server/bitbucket/rest/v811/api-group-repository/#api-api-latest-projects-projectkey-repos-repositoryslug-branches-get
+func (p *Provider) GetBranch(ctx context.Context, oauthCtx *common.OauthContext, instanceURL, repositoryID, branchName string) (*vcs.BranchInfo, error) {
+	repoURL, err := repositoryURL(instanceURL, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+	// There is no API to get a single branch, so we filter the branches by the
+	// name and find the exact match.
+	url := fmt.Sprintf("%s/branches?filterText=%s&boostMatches=true", repoURL, url.QueryEscape(branchName))
+	branches, err := fetchPaginated[Branch](ctx, p.client, oauthCtx, instanceURL, url)
+	if err != nil {
+		return nil, errors.Wrap(err, "fetch paginated list")
+	}
+
+	for _, branch := range branches {
+		if branch.DisplayID == branchName {
+			return &vcs.BranchInfo{
+				Name:         branch.DisplayID,
+				LastCommitID: branch.LatestCommit,
+			}, nil
+		}
+	}
+	return nil, common.Errorf(common.NotFound, "branch %q not found in repository %s", branchName, repositoryID)
+}
+
+// BranchCreate is the API message to create the branch.
+type BranchCreate struct {
+	Name string `json:"name"`
+	// StartPoint is the branch or commit the new branch is created from.
+	StartPoint string `json:"startPoint"`
+}
+
+// CreateBranch creates the branch in the repository.
+//
+// Docs: https://developer.atlassian.com/server/bitbucket/rest/v811/api-group-repository/#api-api-latest-projects-projectkey-repos-repositoryslug-branches-post
+func (p *Provider) CreateBranch(ctx context.Context, oauthCtx *common.OauthContext, instanceURL, repositoryID string, branch *vcs.BranchInfo) error {
+	repoURL, err := repositoryURL(instanceURL, repositoryID)
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(
+		BranchCreate{
+			Name:       branch.Name,
+			StartPoint: branch.LastCommitID,
+		},
+	)
+	if err != nil {
+		return errors.Wrap(err, "marshal branch create")
+	}
+
+	url := fmt.Sprintf("%s/branches", repoURL)
+	code, _, resp, err := oauth.Post(ctx, p.client, url, &oauthCtx.AccessToken, bytes.NewReader(body), tokenRefresher(instanceURL, oauthCtx))
+	if err != nil {
+		return errors.Wrapf(err, "POST %s", url)
+	}
+
+	if code == http.StatusNotFound {
+		return common.Errorf(common.NotFound, "failed to create branch from URL %s", url)
+	} else if code >= 300 {
+		return errors.Errorf("failed to create branch from URL %s, status code: %d, body: %s",
+			url,
+			code,
+			resp,
+		)
+	}
+	return nil
+}
+
+// PullRequestRef is the API message for the source or target ref of the pull
+// request.
+type PullRequestRef struct {
+	// ID is the full ref name, e.g. "refs/heads/main".
+	ID           string     `json:"id"`
+	DisplayID    string     `json:"displayId,omitempty"`
+	LatestCommit string     `json:"latestCommit,omitempty"`
+	Repository   Repository `json:"repository"`
+}
+
+// PullRequest is the API message for Bitbucket Data Center pull request.
+type PullRequest struct {
+	ID      int            `json:"id"`
+	Title   string         `json:"title"`
+	State   string         `json:"state"`
+	FromRef PullRequestRef `json:"fromRef"`
+	ToRef   PullRequestRef `json:"toRef"`
+	Links   Links          `json:"links"`
+}
+
+// ListPullRequestFile lists the changed files in the pull request.
+//
+// Docs: https://developer.atlassian.com/server/bitbucket/rest/v811/api-group-pull-requests/#api-api-latest-projects-projectkey-repos-repositoryslug-pull-requests-pullrequestid-changes-get
+func (p *Provider) ListPullRequestFile(ctx context.Context, oauthCtx *common.OauthContext, instanceURL, repositoryID, pullRequestID string) ([]*vcs.PullRequestFile, error) {
+	repoURL, err := repositoryURL(instanceURL, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+	url := fmt.Sprintf("%s/pull-requests/%s", repoURL, url.PathEscape(pullRequestID))
+	code, _, body, err := oauth.Get(ctx, p.client, url, &oauthCtx.AccessToken, tokenRefresher(instanceURL, oauthCtx))
+	if err != nil {
+		return nil, errors.Wrapf(err, "GET %s", url)
+	}
+
+	if code == http.StatusNotFound {
+		return nil, common.Errorf(common.NotFound, "failed to get pull request from URL %s", url)
+	} else if code >= 300 {
+		return nil, errors.Errorf("failed to get pull request from URL %s, status code: %d, body: %s",
+			url,
+			code,
+			body,
+		)
+	}
+
+	var pullRequest PullRequest
+	if err := json.Unmarshal([]byte(body), &pullRequest); err != nil {
+		return nil, errors.Wrap(err, "unmarshal body")
+	}
+
+	changes, err := fetchPaginated[Change](ctx, p.client, oauthCtx, instanceURL, fmt.Sprintf("%s/changes", url))
+	if err != nil {
+		return nil, errors.Wrap(err, "fetch paginated list")
+	}
+
+	var files []*vcs.PullRequestFile
+	for _, change := range changes {
+		files = append(files, &vcs.PullRequestFile{
+			Path:         change.Path.ToString,
+			LastCommitID: pullRequest.FromRef.LatestCommit,
+			IsDeleted:    change.Type == "DELETE",
+		})
+	}
+	return files, nil
+}
+
+// PullRequestCreate is the API message to create the pull request.
+type PullRequestCreate struct {
+	Title       string         `json:"title"`
+	Description string         `json:"description"`
+	FromRef     PullRequestRef `json:"fromRef"`
+	ToRef       PullRequestRef `json:"toRef"`
+}
+
+// CreatePullRequest creates the pull request in the repository.
+//
+// Docs: https://developer.atlassian.com/server/bitbucket/rest/v811/api-group-pull-requests/#api-api-latest-projects-projectkey-repos-repositoryslug-pull-requests-post
+func (p *Provider) CreatePullRequest(ctx context.Context, oauthCtx *common.OauthContext, instanceURL, repositoryID string, create *vcs.PullRequestCreate) (*vcs.PullRequest, error) {
+	repoURL, err := repositoryURL(instanceURL, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+	projectKey, slug, _ := strings.Cut(repositoryID, "/")
+	repository := Repository{
+		Slug:    slug,
+		Project: Project{Key: projectKey},
+	}
+	payload, err := json.Marshal(
+		PullRequestCreate{
+			Title:       create.Title,
+			Description: create.Body,
+			FromRef: PullRequestRef{
+				ID:         "refs/heads/" + create.Head,
+				Repository: repository,
+			},
+			ToRef: PullRequestRef{
+				ID:         "refs/heads/" + create.Base,
+				Repository: repository,
+			},
+		},
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal pull request create")
+	}
+
+	url := fmt.Sprintf("%s/pull-requests", repoURL)
+	code, _, body, err := oauth.Post(ctx, p.client, url, &oauthCtx.AccessToken, bytes.NewReader(payload), tokenRefresher(instanceURL, oauthCtx))
+	if err != nil {
+		return nil, errors.Wrapf(err, "POST %s", url)
+	}
+
+	if code == http.StatusNotFound {
+		return nil, common.Errorf(common.NotFound, "failed to create pull request from URL %s", url)
+	} else if code >= 300 {
+		return nil, errors.Errorf("failed to create pull request from URL %s, status code: %d, body: %s",
+			url,
+			code,
+			body,
+		)
+	}
+
+	var res PullRequest
+	if err := json.Unmarshal([]byte(body), &res); err != nil {
+		return nil, err
+	}
+
+	var prURL string
+	if len(res.Links.Self) > 0 {
+		prURL = res.Links.Self[0].Href
+	}
+	return &vcs.PullRequest{
+		URL: prURL,
+	}, nil
+}
+
+// UpsertEnvironmentVariable creates or updates the environment variable in the repository.
+//
+// WARNING: This is not supported in Bitbucket Data Center, the SQL review is
+// triggered by the pull request webhook events instead of CI.
+func (*Provider) UpsertEnvironmentVariable(context.Context, *common.OauthContext, string, string, string, string) error {
+	return errors.New("not supported")
+}
+
+// PullRequestComment is the API message to comment on the pull request.
+type PullRequestComment struct {
+	Text string `json:"text"`
+}
+
+// CreatePullRequestComment comments on the pull request, the text is rendered
+// as Markdown.
+//
+// Docs: https://developer.atlassian.com/server/bitbucket/rest/v811/api-group-pull-requests/#api-api-latest-projects-projectkey-repos-repositoryslug-pull-requests-pullrequestid-comments-post
+func CreatePullRequestComment(ctx context.Context, oauthCtx *common.OauthContext, instanceURL, repositoryID, pullRequestID, text string) error {
+	repoURL, err := repositoryURL(instanceURL, repositoryID)
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(PullRequestComment{Text: text})
+	if err != nil {
+		return errors.Wrap(err, "marshal pull request comment")
+	}
+
+	url := fmt.Sprintf("%s/pull-requests/%s/comments", repoURL, url.PathEscape(pullRequestID))
+	code, _, resp, err := oauth.Post(ctx, &http.Client{}, url, &oauthCtx.AccessToken, bytes.NewReader(body), tokenRefresher(instanceURL, oauthCtx))
+	if err != nil {
+		return errors.Wrapf(err, "POST %s", url)
+	}
+
+	if code == http.StatusNotFound {
+		return common.Errorf(common.NotFound, "failed to create pull request comment from URL %s", url)
+	} else if code >= 300 {
+		return errors.Errorf("failed to create pull request comment from URL %s, status code: %d, body: %s",
+			url,
+			code,
+			resp,
+		)
+	}
+	return nil
+}
+
+// PullRequestActivity is the API message for the activity of the pull request.
+type PullRequestActivity struct {
+	// Action is "COMMENTED" for the comment activities.
+	Action        string `json:"action"`
+	CommentAction string `json:"commentAction"`
+	// Comment is set for the comment activities.
+	Comment *PullRequestCommentInfo `json:"comment"`
+}
+
+// PullRequestCommentInfo is the API message for the comment of the pull request.
+type PullRequestCommentInfo struct {
+	ID int `json:"id"`
+	// Version is the version of the comment, which is required to update the comment.
+	Version int    `json:"version"`
+	Text    string `json:"text"`
+}
+
+// ListPullRequestComments lists the comments added on the pull request overview.
+//
+// Docs: https://developer.atlassian.com/server/bitbucket/rest/v811/api-group-pull-requests/#api-api-latest-projects-projectkey-repos-repositoryslug-pull-requests-pullrequestid-activities-get
+func ListPullRequestComments(ctx context.Context, oauthCtx *common.OauthContext, instanceURL, repositoryID, pullRequestID string) ([]*PullRequestCommentInfo, error) {
+	repoURL, err := repositoryURL(instanceURL, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+	url := fmt.Sprintf("%s/pull-requests/%s/activities", repoURL, url.PathEscape(pullRequestID))
+	activities, err := fetchPaginated[PullRequestActivity](ctx, &http.Client{}, oauthCtx, instanceURL, url)
+	if err != nil {
+		return nil, errors.Wrap(err, "list pull request activities")
+	}
+
+	var comments []*PullRequestCommentInfo
+	for _, activity := range activities {
+		if activity.Action == "COMMENTED" && activity.CommentAction == "ADDED" && activity.Comment != nil {
+			comments = append(comments, activity.Comment)
+		}
+	}
+	return comments, nil
+}
+
+// UpdatePullRequestComment updates the text of the comment on the pull request.
+//
+// Docs: https://developer.atlassian.com/server/bitbucket/rest/v811/api-group-pull-requests/#api-api-latest-projects-projectkey-repos-repositoryslug-pull-requests-pullrequestid-comments-commentid-put
+func UpdatePullRequestComment(ctx context.Context, oauthCtx *common.OauthContext, instanceURL, repositoryID, pullRequestID string, comment *PullRequestCommentInfo) error {
+	repoURL, err := repositoryURL(instanceURL, repositoryID)
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(map[string]any{"text": comment.Text, "version": comment.Version})
+	if err != nil {
+		return errors.Wrap(err, "marshal pull request comment")
+	}
+
+	url := fmt.Sprintf("%s/pull-requests/%s/comments/%d", repoURL, url.PathEscape(pullRequestID), comment.ID)
+	code, _, resp, err := oauth.Put(ctx, &http.Client{}, url, &oauthCtx.AccessToken, bytes.NewReader(body), tokenRefresher(instanceURL, oauthCtx))
+	if err != nil {
+		return errors.Wrapf(err, "PUT %s", url)
+	}
+
+	if code == http.StatusNotFound {
+		return common.Errorf(common.NotFound, "failed to update pull request comment from URL %s", url)
+	} else if code >= 300 {
+		return errors.Errorf("failed to update pull request comment from URL %s, status code: %d, body: %s",
+			url,
+			code,
+			resp,
+		)
+	}
+	return nil
+}
+
+// BuildState is the state of the build status.
+type BuildState string
+
+const (
+	// BuildStateSuccessful is the build state for the passed build.
+	BuildStateSuccessful BuildState = "SUCCESSFUL"
+	// BuildStateFailed is the build state for the failed build.
+	BuildStateFailed BuildState = "FAILED"
+)
+
+// BuildStatus is the API message for the build status of a commit.
+type BuildStatus struct {
+	State BuildState `json:"state"`
+	// Key identifies the build, the status with the same key is replaced.
+	Key         string `json:"key"`
+	Name        string `json:"name"`
+	URL         string `json:"url"`
+	Description string `json:"description"`
+}
+
+// SetBuildStatus sets the build status of the commit, which is shown on the
+// pull requests containing the commit.
+//
+// Docs: https://developer.atlassian.com/server/bitbucket/rest/v811/api-group-build-status/#api-build-status-1-0-commits-commitid-post
+func SetBuildStatus(ctx context.Context, oauthCtx *common.OauthContext, instanceURL, commitID string, status *BuildStatus) error {
+	body, err := json.Marshal(status)
+	if err != nil {
+		return errors.Wrap(err, "marshal build status")
+	}
+
+	url := fmt.Sprintf("%s/rest/build-status/1.0/commits/%s", instanceURL, url.PathEscape(commitID))
+	code, _, resp, err := oauth.Post(ctx, &http.Client{}, url, &oauthCtx.AccessToken, bytes.NewReader(body), tokenRefresher(instanceURL, oauthCtx))
+	if err != nil {
+		return errors.Wrapf(err, "POST %s", url)
+	}
+
+	if code == http.StatusNotFound {
+		return common.Errorf(common.NotFound, "failed to set build status from URL %s", url)
+	} else if code >= 300 {
+		return errors.Errorf("failed to set build status from URL %s, status code: %d, body: %s",
+			url,
+			code,
+			resp,
+		)
+	}
+	return nil
+}
+
+// WebhookEventKey is the key of the webhook event, sent in the X-Event-Key
+// header.
+type WebhookEventKey string
+
+const (
+	// WebhookRefsChanged is the event of pushing to the repository.
+	WebhookRefsChanged WebhookEventKey = "repo:refs_changed"
+	// WebhookPullRequestOpened is the event of opening a pull request.
+	WebhookPullRequestOpened WebhookEventKey = "pr:opened"
+	// WebhookPullRequestFromRefUpdated is the event of pushing to the source branch of a pull request.
+	WebhookPullRequestFromRefUpdated WebhookEventKey = "pr:from_ref_updated"
+	// WebhookPullRequestModified is the event of changing the title, description or target branch of a pull request.
+	WebhookPullRequestModified WebhookEventKey = "pr:modified"
+	// WebhookDiagnosticsPing is the event of testing the webhook connection.
+	WebhookDiagnosticsPing WebhookEventKey = "diagnostics:ping"
+)
+
+// WebhookRef is the API message for the ref in the webhook events.
+type WebhookRef struct {
+	ID        string `json:"id"`
+	DisplayID string `json:"displayId"`
+	// Available values: "BRANCH", "TAG"
+	Type string `json:"type"`
+}
+
+// WebhookRefChange is the API message for the changed ref in the push event.
+type WebhookRefChange struct {
+	Ref      WebhookRef `json:"ref"`
+	RefID    string     `json:"refId"`
+	FromHash string     `json:"fromHash"`
+	ToHash   string     `json:"toHash"`
+	// Available values: "ADD", "DELETE", "UPDATE"
+	Type string `json:"type"`
+}
+
+// WebhookRefsChangedEvent is the API message for the push event.
+//
+// Docs: https://confluence.atlassian.com/bitbucketserver/event-payload-938025882.html#Eventpayload-Push
+type WebhookRefsChangedEvent struct {
+	EventKey   WebhookEventKey    `json:"eventKey"`
+	Actor      User               `json:"actor"`
+	Repository Repository         `json:"repository"`
+	Changes    []WebhookRefChange `json:"changes"`
+}
+
+// WebhookPullRequestEvent is the API message for the pull request events.
+//
+// Docs: https://confluence.atlassian.com/bitbucketserver/event-payload-938025882.html#Eventpayload-Pullrequest
+type WebhookPullRequestEvent struct {
+	EventKey    WebhookEventKey `json:"eventKey"`
+	Actor       User            `json:"actor"`
+	PullRequest PullRequest     `json:"pullRequest"`
+}
+
+// WebhookConfiguration is the API message for the webhook configuration.
+type WebhookConfiguration struct {
+	// Secret is used to sign the payload in the X-Hub-Signature header.
+	Secret string `json:"secret"`
+}
+
+// WebhookCreateOrUpdate represents a Bitbucket Data Center API request for
+// creating or updating a webhook.
+type WebhookCreateOrUpdate struct {
+	Name          string               `json:"name"`
+	URL           string               `json:"url"`
+	Active        bool                 `json:"active"`
+	Events        []WebhookEventKey    `json:"events"`
+	Configuration WebhookConfiguration `json:"configuration"`
+}
+
+// Webhook represents a Bitbucket Data Center API response for the webhook
+// information.
+type Webhook struct {
+	ID int `json:"id"`
+}
+
+// CreateWebhook creates a webhook in the repository with given payload.
+//
+// Docs: https://developer.atlassian.com/server/bitbucket/rest/v811/api-group-repository/#api-api-latest-projects-projectkey-repos-repositoryslug-webhooks-post
+func (p *Provider) CreateWebhook(ctx context.Context, oauthCtx *common.OauthContext, instanceURL, repositoryID string, payload []byte) (string, error) {
+	repoURL, err := repositoryURL(instanceURL, repositoryID)
+	if err != nil {
+		return "", err
+	}
+	url := fmt.Sprintf("%s/webhooks", repoURL)
+	code, _, body, err := oauth.Post(ctx, p.client, url, &oauthCtx.AccessToken, bytes.NewReader(payload), tokenRefresher(instanceURL, oauthCtx))
+	if err != nil {
+		return "", errors.Wrapf(err, "POST %s", url)
+	}
+
+	if code == http.StatusNotFound {
+		return "", common.Errorf(common.NotFound, "failed to create webhook through URL %s", url)
+	} else if code >= 300 {
+		return "", errors.Errorf("failed to create webhook through URL %s, status code: %d, body: %s",
+			url,
+			code,
+			body,
+		)
+	}
+
+	var webhook Webhook
+	if err = json.Unmarshal([]byte(body), &webhook); err != nil {
+		return "", errors.Wrap(err, "unmarshal body")
+	}
+	return strconv.Itoa(webhook.ID), nil
+}
+
+// PatchWebhook patches the webhook in the repository with given payload.
+//
+// Docs: https://developer.atlassian.com/server/bitbucket/rest/v811/api-group-repository/#api-api-latest-projects-projectkey-repos-repositoryslug-webhooks-webhookid-put
+func (p *Provider) PatchWebhook(ctx context.Context, oauthCtx *common.OauthContext, instanceURL, repositoryID, webhookID string, payload []byte) error {
+	repoURL, err := repositoryURL(instanceURL, repositoryID)
+	if err != nil {
+		return err
+	}
+	url := fmt.Sprintf("%s/webhooks/%s", repoURL, webhookID)
+	code, _, body, err := oauth.Put(ctx, p.client, url, &oauthCtx.AccessToken, bytes.NewReader(payload), tokenRefresher(instanceURL, oauthCtx))
+	if err != nil {
+		return errors.Wrapf(err, "PUT %s", url)
+	}
+
+	if code == http.StatusNotFound {
+		return common.Errorf(common.NotFound, "failed to patch webhook through URL %s", url)
+	} else if code >= 300 {
+		return errors.Errorf("failed to patch webhook through URL %s, status code: %d, body: %s",
+			url,
+			code,
+			body,
+		)
+	}
+	return nil
+}
+
+// DeleteWebhook deletes the webhook from the repository.
+//
+// Docs: https://developer.atlassian.com/server/bitbucket/rest/v811/api-group-repository/#api-api-latest-projects-projectkey-repos-repositoryslug-webhooks-webhookid-delete
+func (p *Provider) DeleteWebhook(ctx context.Context, oauthCtx *common.OauthContext, instanceURL, repositoryID, webhookID string) error {
+	repoURL, err := repositoryURL(instanceURL, repositoryID)
+	if err != nil {
+		return err
+	}
+	url := fmt.Sprintf("%s/webhooks/%s", repoURL, webhookID)
+	code, _, body, err := oauth.Delete(ctx, p.client, url, &oauthCtx.AccessToken, tokenRefresher(instanceURL, oauthCtx))
+	if err != nil {
+		return errors.Wrapf(err, "DELETE %s", url)
+	}
+
+	if code == http.StatusNotFound {
+		return nil // It is OK if the webhook has already gone
+	} else if code >= 300 {
+		return errors.Errorf("failed to delete webhook through URL %s, status code: %d, body: %s",
+			url,
+			code,
+			body,
+		)
+	}
+	return nil
+}
+
+// escapePath escapes each segment of the slash-separated path.
+func escapePath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// tokenRefresher returns the refresher of the OAuth token in the context.
+//
+// The personal and HTTP access tokens have no refresh tokens, they need to be
+// replaced by the users once expired or revoked.
+func tokenRefresher(instanceURL string, oauthCtx *common.OauthContext) oauth.TokenRefresher {
+	return func(ctx context.Context, client *http.Client, oldToken *string) error {
+		if oauthCtx.RefreshToken == "" {
+			return errors.New("the access token is expired or revoked, and it cannot be refreshed as there is no refresh token, please update the personal or HTTP access token")
+		}
+
+		params := &url.Values{}
+		params.Set("client_id", oauthCtx.ClientID)
+		params.Set("client_secret", oauthCtx.ClientSecret)
+		params.Set("refresh_token", oauthCtx.RefreshToken)
+		params.Set("grant_type", "refresh_token")
+
+		url := fmt.Sprintf("%s/rest/oauth2/latest/token", instanceURL)
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, strings.NewReader(params.Encode()))
+		if err != nil {
+			return errors.Wrapf(err, "construct POST %s", url)
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+		createdAt := time.Now().Unix()
+		resp, err := client.Do(req)
+		if err != nil {
+			return errors.Wrapf(err, "POST %s", url)
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return errors.Wrapf(err, "read body of POST %s", url)
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		if resp.StatusCode != http.StatusOK {
+			return errors.Errorf("non-200 POST %s status code %d with body %q", url, resp.StatusCode, body)
+		}
+
+		var r oauthResponse
+		if err = json.Unmarshal(body, &r); err != nil {
+			return errors.Wrapf(err, "unmarshal body from POST %s", url)
+		}
+
+		// Update the old token to new value for retries.
+		*oldToken = r.AccessToken
+		// Bitbucket Data Center rotates the refresh token, so we keep the new one
+		// for the following refreshes.
+		oauthCtx.RefreshToken = r.RefreshToken
+
+		return oauthCtx.Refresher(r.AccessToken, r.RefreshToken, r.toVCSOAuthToken(createdAt).ExpiresTs)
+	}
+}
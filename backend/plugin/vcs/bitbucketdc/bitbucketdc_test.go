@@ -0,0 +1,497 @@
+package bitbucketdc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/backend/common"
+	"github.com/bytebase/bytebase/backend/plugin/vcs"
+)
+
+const bitbucketURL = "https://bitbucket.example.com"
+
+func TestProvider_ExchangeOAuthToken(t *testing.T) {
+	p := newMockProvider(func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, "/rest/oauth2/latest/token", r.URL.Path)
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "authorization_code", r.PostForm.Get("grant_type"))
+		assert.Equal(t, "test_code", r.PostForm.Get("code"))
+		assert.Equal(t, "test_client_id", r.PostForm.Get("client_id"))
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: io.NopCloser(strings.NewReader(`
+{
+  "access_token": "test_access_token",
+  "token_type": "bearer",
+  "expires_in": 3600,
+  "refresh_token": "test_refresh_token"
+}
+`)),
+		}, nil
+	},
+	)
+
+	ctx := context.Background()
+	got, err := p.ExchangeOAuthToken(ctx, bitbucketURL, &common.OAuthExchange{
+		ClientID:     "test_client_id",
+		ClientSecret: "test_client_secret",
+		Code:         "test_code",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "test_access_token", got.AccessToken)
+	assert.Equal(t, "test_refresh_token", got.RefreshToken)
+	assert.Equal(t, got.CreatedAt+3600, got.ExpiresTs)
+}
+
+func TestProvider_FetchCommitByID(t *testing.T) {
+	p := newMockProvider(func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, "/rest/api/1.0/projects/PRJ/repos/my-repo/commits/def0123abcdef4567abcdef8987abcdef6543abc", r.URL.Path)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: io.NopCloser(strings.NewReader(`
+{
+  "id": "def0123abcdef4567abcdef8987abcdef6543abc",
+  "displayId": "def0123abcd",
+  "author": {"name": "charlie", "emailAddress": "charlie@example.com"},
+  "authorTimestamp": 1699610400000,
+  "message": "Add the init migration",
+  "parents": [{"id": "abcdef0123abcdef4567abcdef8987abcdef6543", "displayId": "abcdef0"}]
+}
+`)),
+		}, nil
+	},
+	)
+
+	ctx := context.Background()
+	got, err := p.FetchCommitByID(ctx, &common.OauthContext{}, bitbucketURL, "PRJ/my-repo", "def0123abcdef4567abcdef8987abcdef6543abc")
+	require.NoError(t, err)
+
+	want := &vcs.Commit{
+		ID:          "def0123abcdef4567abcdef8987abcdef6543abc",
+		AuthorName:  "charlie",
+		AuthorEmail: "charlie@example.com",
+		CreatedTs:   1699610400,
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestProvider_GetDiffFileList(t *testing.T) {
+	p := newMockProvider(func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, "/rest/api/1.0/projects/PRJ/repos/my-repo/changes", r.URL.Path)
+		assert.Equal(t, "a", r.URL.Query().Get("since"))
+		assert.Equal(t, "d", r.URL.Query().Get("until"))
+		if r.URL.Query().Get("start") == "0" {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body: io.NopCloser(strings.NewReader(`
+{
+  "values": [
+    {"path": {"toString": "prod/1__init.sql"}, "type": "ADD"},
+    {"path": {"toString": "prod/2__alter.sql"}, "type": "MODIFY"}
+  ],
+  "isLastPage": false,
+  "nextPageStart": 2
+}
+`)),
+			}, nil
+		}
+		assert.Equal(t, "2", r.URL.Query().Get("start"))
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: io.NopCloser(strings.NewReader(`
+{
+  "values": [
+    {"path": {"toString": "temp.sql"}, "type": "DELETE"},
+    {"path": {"toString": "prod/3__renamed.sql"}, "type": "MOVE"}
+  ],
+  "isLastPage": true
+}
+`)),
+		}, nil
+	},
+	)
+
+	ctx := context.Background()
+	got, err := p.GetDiffFileList(ctx, &common.OauthContext{}, bitbucketURL, "PRJ/my-repo", "a", "d")
+	require.NoError(t, err)
+
+	want := []vcs.FileDiff{
+		{Path: "prod/1__init.sql", Type: vcs.FileDiffTypeAdded},
+		{Path: "prod/2__alter.sql", Type: vcs.FileDiffTypeModified},
+		{Path: "temp.sql", Type: vcs.FileDiffTypeRemoved},
+		{Path: "prod/3__renamed.sql", Type: vcs.FileDiffTypeAdded},
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestProvider_FetchAllRepositoryList(t *testing.T) {
+	p := newMockProvider(func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, "/rest/api/1.0/repos", r.URL.Path)
+		assert.Equal(t, "REPO_ADMIN", r.URL.Query().Get("permission"))
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: io.NopCloser(strings.NewReader(`
+{
+  "values": [
+    {
+      "id": 1,
+      "slug": "my-repo",
+      "name": "My repo",
+      "project": {"key": "PRJ", "name": "My project"},
+      "links": {"self": [{"href": "https://bitbucket.example.com/projects/PRJ/repos/my-repo/browse"}]}
+    }
+  ],
+  "isLastPage": true
+}
+`)),
+		}, nil
+	},
+	)
+
+	ctx := context.Background()
+	got, err := p.FetchAllRepositoryList(ctx, &common.OauthContext{}, bitbucketURL)
+	require.NoError(t, err)
+
+	want := []*vcs.Repository{
+		{
+			ID:       "PRJ/my-repo",
+			Name:     "My repo",
+			FullPath: "PRJ/my-repo",
+			WebURL:   "https://bitbucket.example.com/projects/PRJ/repos/my-repo",
+		},
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestProvider_FetchRepositoryFileList(t *testing.T) {
+	p := newMockProvider(func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, "/rest/api/1.0/projects/PRJ/repos/my-repo/files/prod", r.URL.Path)
+		assert.Equal(t, "main", r.URL.Query().Get("at"))
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: io.NopCloser(strings.NewReader(`
+{
+  "values": ["1__init.sql", "sub/2__alter.sql"],
+  "isLastPage": true
+}
+`)),
+		}, nil
+	},
+	)
+
+	ctx := context.Background()
+	got, err := p.FetchRepositoryFileList(ctx, &common.OauthContext{}, bitbucketURL, "PRJ/my-repo", "main", "prod/")
+	require.NoError(t, err)
+
+	want := []*vcs.RepositoryTreeNode{
+		{Path: "prod/1__init.sql", Type: "blob"},
+		{Path: "prod/sub/2__alter.sql", Type: "blob"},
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestProvider_OverwriteFile(t *testing.T) {
+	p := newMockProvider(func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		assert.Equal(t, "/rest/api/1.0/projects/PRJ/repos/my-repo/browse/prod/1__init.sql", r.URL.Path)
+		require.NoError(t, r.ParseMultipartForm(1<<20))
+		assert.Equal(t, "CREATE TABLE t (id INT);", r.FormValue("content"))
+		assert.Equal(t, "Update schema", r.FormValue("message"))
+		assert.Equal(t, "main", r.FormValue("branch"))
+		assert.Equal(t, "abc", r.FormValue("sourceCommitId"))
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(`{"id": "def"}`)),
+		}, nil
+	},
+	)
+
+	ctx := context.Background()
+	err := p.OverwriteFile(ctx, &common.OauthContext{}, bitbucketURL, "PRJ/my-repo", "prod/1__init.sql", vcs.FileCommitCreate{
+		Branch:        "main",
+		Content:       "CREATE TABLE t (id INT);",
+		CommitMessage: "Update schema",
+		LastCommitID:  "abc",
+	})
+	require.NoError(t, err)
+}
+
+func TestProvider_ReadFileMeta(t *testing.T) {
+	p := newMockProvider(func(r *http.Request) (*http.Response, error) {
+		switch r.URL.Path {
+		case "/rest/api/1.0/projects/PRJ/repos/my-repo/browse/prod/1__init.sql":
+			assert.Equal(t, "main", r.URL.Query().Get("at"))
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(`{"type": "FILE"}`)),
+			}, nil
+		case "/rest/api/1.0/projects/PRJ/repos/my-repo/commits":
+			assert.Equal(t, "prod/1__init.sql", r.URL.Query().Get("path"))
+			assert.Equal(t, "main", r.URL.Query().Get("until"))
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(`{"values": [{"id": "abc"}], "isLastPage": false, "nextPageStart": 1}`)),
+			}, nil
+		}
+		return nil, fmt.Errorf("unexpected request %s", r.URL.Path)
+	},
+	)
+
+	ctx := context.Background()
+	got, err := p.ReadFileMeta(ctx, &common.OauthContext{}, bitbucketURL, "PRJ/my-repo", "prod/1__init.sql", vcs.RefInfo{RefType: vcs.RefTypeBranch, RefName: "main"})
+	require.NoError(t, err)
+
+	want := &vcs.FileMeta{
+		Name:         "1__init.sql",
+		Path:         "prod/1__init.sql",
+		LastCommitID: "abc",
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestProvider_ReadFileContent(t *testing.T) {
+	p := newMockProvider(func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, "/rest/api/1.0/projects/PRJ/repos/my-repo/raw/prod/1__init.sql", r.URL.Path)
+		assert.Equal(t, "abc", r.URL.Query().Get("at"))
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader("CREATE TABLE t (id INT);")),
+		}, nil
+	},
+	)
+
+	ctx := context.Background()
+	got, err := p.ReadFileContent(ctx, &common.OauthContext{}, bitbucketURL, "PRJ/my-repo", "prod/1__init.sql", vcs.RefInfo{RefType: vcs.RefTypeCommit, RefName: "abc"})
+	require.NoError(t, err)
+	assert.Equal(t, "CREATE TABLE t (id INT);", got)
+}
+
+func TestProvider_GetBranch(t *testing.T) {
+	p := newMockProvider(func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, "/rest/api/1.0/projects/PRJ/repos/my-repo/branches", r.URL.Path)
+		assert.Contains(t, r.URL.Query().Get("filterText"), "main")
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: io.NopCloser(strings.NewReader(`
+{
+  "values": [
+    {"id": "refs/heads/main-backup", "displayId": "main-backup", "latestCommit": "def"},
+    {"id": "refs/heads/main", "displayId": "main", "latestCommit": "abc"}
+  ],
+  "isLastPage": true
+}
+`)),
+		}, nil
+	},
+	)
+
+	ctx := context.Background()
+	got, err := p.GetBranch(ctx, &common.OauthContext{}, bitbucketURL, "PRJ/my-repo", "main")
+	require.NoError(t, err)
+	assert.Equal(t, &vcs.BranchInfo{Name: "main", LastCommitID: "abc"}, got)
+
+	_, err = p.GetBranch(ctx, &common.OauthContext{}, bitbucketURL, "PRJ/my-repo", "main-")
+	assert.Equal(t, common.NotFound, common.ErrorCode(err))
+}
+
+func TestProvider_ListPullRequestFile(t *testing.T) {
+	p := newMockProvider(func(r *http.Request) (*http.Response, error) {
+		switch r.URL.Path {
+		case "/rest/api/1.0/projects/PRJ/repos/my-repo/pull-requests/1":
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body: io.NopCloser(strings.NewReader(`
+{
+  "id": 1,
+  "fromRef": {"id": "refs/heads/feature", "latestCommit": "abc"},
+  "toRef": {"id": "refs/heads/main", "latestCommit": "def"}
+}
+`)),
+			}, nil
+		case "/rest/api/1.0/projects/PRJ/repos/my-repo/pull-requests/1/changes":
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body: io.NopCloser(strings.NewReader(`
+{
+  "values": [
+    {"path": {"toString": "prod/2__alter.sql"}, "type": "ADD"},
+    {"path": {"toString": "temp.sql"}, "type": "DELETE"}
+  ],
+  "isLastPage": true
+}
+`)),
+			}, nil
+		}
+		return nil, fmt.Errorf("unexpected request %s", r.URL.Path)
+	},
+	)
+
+	ctx := context.Background()
+	got, err := p.ListPullRequestFile(ctx, &common.OauthContext{}, bitbucketURL, "PRJ/my-repo", "1")
+	require.NoError(t, err)
+
+	want := []*vcs.PullRequestFile{
+		{Path: "prod/2__alter.sql", LastCommitID: "abc"},
+		{Path: "temp.sql", LastCommitID: "abc", IsDeleted: true},
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestProvider_CreatePullRequest(t *testing.T) {
+	p := newMockProvider(func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, "/rest/api/1.0/projects/PRJ/repos/my-repo/pull-requests", r.URL.Path)
+		var create PullRequestCreate
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&create))
+		assert.Equal(t, "refs/heads/feature", create.FromRef.ID)
+		assert.Equal(t, "refs/heads/main", create.ToRef.ID)
+		assert.Equal(t, "PRJ/my-repo", create.ToRef.Repository.FullPath())
+		return &http.Response{
+			StatusCode: http.StatusCreated,
+			Body:       io.NopCloser(strings.NewReader(`{"id": 1, "links": {"self": [{"href": "https://bitbucket.example.com/projects/PRJ/repos/my-repo/pull-requests/1"}]}}`)),
+		}, nil
+	},
+	)
+
+	ctx := context.Background()
+	got, err := p.CreatePullRequest(ctx, &common.OauthContext{}, bitbucketURL, "PRJ/my-repo", &vcs.PullRequestCreate{
+		Title: "Setup SQL review",
+		Head:  "feature",
+		Base:  "main",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "https://bitbucket.example.com/projects/PRJ/repos/my-repo/pull-requests/1", got.URL)
+}
+
+func TestProvider_CreateWebhook(t *testing.T) {
+	p := newMockProvider(func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, "/rest/api/1.0/projects/PRJ/repos/my-repo/webhooks", r.URL.Path)
+		return &http.Response{
+			StatusCode: http.StatusCreated,
+			Body:       io.NopCloser(strings.NewReader(`{"id": 10, "name": "Bytebase GitOps"}`)),
+		}, nil
+	},
+	)
+
+	ctx := context.Background()
+	got, err := p.CreateWebhook(ctx, &common.OauthContext{}, bitbucketURL, "PRJ/my-repo", []byte("{}"))
+	require.NoError(t, err)
+	assert.Equal(t, "10", got)
+
+	_, err = p.CreateWebhook(ctx, &common.OauthContext{}, bitbucketURL, "my-repo", []byte("{}"))
+	assert.ErrorContains(t, err, "invalid repository ID")
+}
+
+func TestProvider_DeleteWebhook(t *testing.T) {
+	p := newMockProvider(func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, http.MethodDelete, r.Method)
+		assert.Equal(t, "/rest/api/1.0/projects/PRJ/repos/my-repo/webhooks/10", r.URL.Path)
+		return &http.Response{
+			StatusCode: http.StatusNotFound,
+			Body:       io.NopCloser(strings.NewReader("")),
+		}, nil
+	},
+	)
+
+	ctx := context.Background()
+	err := p.DeleteWebhook(ctx, &common.OauthContext{}, bitbucketURL, "PRJ/my-repo", "10")
+	require.NoError(t, err)
+}
+
+func TestPullRequestComments(t *testing.T) {
+	var updated map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/rest/api/1.0/projects/PRJ/repos/my-repo/pull-requests/1/activities":
+			if r.URL.Query().Get("start") == "0" {
+				_, _ = w.Write([]byte(`{"values": [{"action": "OPENED"}, {"action": "COMMENTED", "commentAction": "ADDED", "comment": {"id": 10, "version": 2, "text": "Review"}}], "isLastPage": false, "nextPageStart": 2}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"values": [{"action": "COMMENTED", "commentAction": "DELETED", "comment": {"id": 11, "version": 0, "text": "Deleted"}}], "isLastPage": true}`))
+		case r.Method == http.MethodPut && r.URL.Path == "/rest/api/1.0/projects/PRJ/repos/my-repo/pull-requests/1/comments/10":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&updated))
+			_, _ = w.Write([]byte(`{"id": 10, "version": 3}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	a := require.New(t)
+	ctx := context.Background()
+	comments, err := ListPullRequestComments(ctx, &common.OauthContext{}, server.URL, "PRJ/my-repo", "1")
+	a.NoError(err)
+	a.Equal([]*PullRequestCommentInfo{{ID: 10, Version: 2, Text: "Review"}}, comments)
+
+	comments[0].Text = "Updated review"
+	a.NoError(UpdatePullRequestComment(ctx, &common.OauthContext{}, server.URL, "PRJ/my-repo", "1", comments[0]))
+	a.Equal(map[string]any{"text": "Updated review", "version": float64(2)}, updated)
+}
+
+// unauthorizedBody is the response body of Bitbucket Data Center for the expired or revoked tokens.
+const unauthorizedBody = `{"errors":[{"context":null,"message":"Authentication failed. Please check your credentials and try again.","exceptionName":"com.atlassian.bitbucket.auth.IncorrectPasswordAuthenticationException"}]}`
+
+func TestOAuth_RefreshToken(t *testing.T) {
+	p := newMockProvider(func(r *http.Request) (*http.Response, error) {
+		if r.URL.Path == "/rest/oauth2/latest/token" {
+			require.NoError(t, r.ParseForm())
+			assert.Equal(t, "refresh_token", r.PostForm.Get("grant_type"))
+			assert.Equal(t, "old_refresh_token", r.PostForm.Get("refresh_token"))
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(`{"access_token": "new_access_token", "expires_in": 3600, "refresh_token": "new_refresh_token"}`)),
+			}, nil
+		}
+		if r.Header.Get("Authorization") != "Bearer new_access_token" {
+			return &http.Response{
+				StatusCode: http.StatusUnauthorized,
+				Body:       io.NopCloser(strings.NewReader(unauthorizedBody)),
+			}, nil
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader("SELECT 1;")),
+		}, nil
+	},
+	)
+
+	var refreshed string
+	oauthCtx := &common.OauthContext{
+		AccessToken:  "expired",
+		RefreshToken: "old_refresh_token",
+		Refresher: func(token, refreshToken string, expiresTs int64) error {
+			refreshed = fmt.Sprintf("%s/%s", token, refreshToken)
+			assert.NotZero(t, expiresTs)
+			return nil
+		},
+	}
+	ctx := context.Background()
+	_, err := p.ReadFileContent(ctx, oauthCtx, bitbucketURL, "PRJ/my-repo", "prod/1__init.sql", vcs.RefInfo{RefType: vcs.RefTypeBranch, RefName: "main"})
+	require.NoError(t, err)
+	assert.Equal(t, "new_access_token/new_refresh_token", refreshed)
+	assert.Equal(t, "new_access_token", oauthCtx.AccessToken)
+
+	// The personal and HTTP access tokens cannot be refreshed.
+	_, err = p.ReadFileContent(ctx, &common.OauthContext{AccessToken: "revoked"}, bitbucketURL, "PRJ/my-repo", "prod/1__init.sql", vcs.RefInfo{RefType: vcs.RefTypeBranch, RefName: "main"})
+	assert.ErrorContains(t, err, "cannot be refreshed")
+}
+
+func newMockProvider(mockRoundTrip func(r *http.Request) (*http.Response, error)) vcs.Provider {
+	return newProvider(
+		vcs.ProviderConfig{
+			Client: &http.Client{
+				Transport: &common.MockRoundTripper{
+					MockRoundTrip: mockRoundTrip,
+				},
+			},
+		},
+	)
+}
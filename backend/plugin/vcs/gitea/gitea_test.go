@@ -0,0 +1,658 @@
+package gitea
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/backend/common"
+	"github.com/bytebase/bytebase/backend/plugin/vcs"
+)
+
+const giteaURL = "https://gitea.example.com"
+
+func TestProvider_FetchCommitByID(t *testing.T) {
+	p := newMockProvider(func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, "/api/v1/repos/octocat/Hello-World/git/commits/7638417db6d59f3c431d3e1f261cc637155684cd", r.URL.Path)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: io.NopCloser(strings.NewReader(`
+{
+  "url": "https://gitea.example.com/api/v1/repos/octocat/Hello-World/git/commits/7638417db6d59f3c431d3e1f261cc637155684cd",
+  "sha": "7638417db6d59f3c431d3e1f261cc637155684cd",
+  "created": "2014-11-07T22:01:45Z",
+  "html_url": "https://gitea.example.com/octocat/Hello-World/commit/7638417db6d59f3c431d3e1f261cc637155684cd",
+  "commit": {
+    "author": {
+      "name": "Monalisa Octocat",
+      "email": "octocat@gitea.example.com",
+      "date": "2014-11-07T22:01:45Z"
+    },
+    "message": "added readme\n"
+  }
+}
+`)),
+		}, nil
+	},
+	)
+
+	ctx := context.Background()
+	got, err := p.FetchCommitByID(ctx, &common.OauthContext{}, giteaURL, "octocat/Hello-World", "7638417db6d59f3c431d3e1f261cc637155684cd")
+	require.NoError(t, err)
+
+	want := &vcs.Commit{
+		ID:         "7638417db6d59f3c431d3e1f261cc637155684cd",
+		AuthorName: "Monalisa Octocat",
+		CreatedTs:  1415397705,
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestProvider_GetDiffFileList(t *testing.T) {
+	p := newMockProvider(func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, "/api/v1/repos/octocat/Hello-World/compare/a...d", r.URL.Path)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			// The commits are listed from the newest to the oldest.
+			Body: io.NopCloser(strings.NewReader(`
+{
+  "total_commits": 3,
+  "commits": [
+    {
+      "sha": "d",
+      "files": [
+        {"filename": "temp.sql", "status": "deleted"},
+        {"filename": "prod/1__init.sql", "status": "modified"},
+        {"filename": "README.md", "status": "modified"}
+      ]
+    },
+    {
+      "sha": "c",
+      "files": [
+        {"filename": "temp.sql", "status": "added"},
+        {"filename": "prod/2__alter.sql", "status": "deleted"}
+      ]
+    },
+    {
+      "sha": "b",
+      "files": [
+        {"filename": "prod/1__init.sql", "status": "added"}
+      ]
+    }
+  ]
+}
+`)),
+		}, nil
+	},
+	)
+
+	ctx := context.Background()
+	got, err := p.GetDiffFileList(ctx, &common.OauthContext{}, giteaURL, "octocat/Hello-World", "a", "d")
+	require.NoError(t, err)
+
+	want := []vcs.FileDiff{
+		{Path: "prod/1__init.sql", Type: vcs.FileDiffTypeAdded},
+		{Path: "prod/2__alter.sql", Type: vcs.FileDiffTypeRemoved},
+		{Path: "README.md", Type: vcs.FileDiffTypeModified},
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestProvider_ExchangeOAuthToken(t *testing.T) {
+	p := newMockProvider(func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, "/login/oauth/access_token", r.URL.Path)
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "test_client_id", r.PostForm.Get("client_id"))
+		assert.Equal(t, "test_code", r.PostForm.Get("code"))
+		assert.Equal(t, "authorization_code", r.PostForm.Get("grant_type"))
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: io.NopCloser(strings.NewReader(`
+{
+  "access_token": "gta_access_token",
+  "token_type": "bearer",
+  "expires_in": 3600,
+  "refresh_token": "gta_refresh_token"
+}
+`)),
+		}, nil
+	},
+	)
+
+	ctx := context.Background()
+	got, err := p.ExchangeOAuthToken(
+		ctx,
+		giteaURL,
+		&common.OAuthExchange{
+			ClientID:     "test_client_id",
+			ClientSecret: "test_client_secret",
+			Code:         "test_code",
+			RedirectURL:  "http://localhost:3000",
+		},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, "gta_access_token", got.AccessToken)
+	assert.Equal(t, "gta_refresh_token", got.RefreshToken)
+	assert.Equal(t, got.CreatedAt+3600, got.ExpiresTs)
+}
+
+func TestProvider_FetchAllRepositoryList(t *testing.T) {
+	p := newMockProvider(func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, "/api/v1/user/repos", r.URL.Path)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: io.NopCloser(strings.NewReader(`
+[
+  {
+    "id": 1296269,
+    "name": "Hello-World",
+    "full_name": "octocat/Hello-World",
+    "html_url": "https://gitea.example.com/octocat/Hello-World",
+    "permissions": {"admin": true, "push": true, "pull": true}
+  },
+  {
+    "id": 1296270,
+    "name": "Readonly",
+    "full_name": "octocat/Readonly",
+    "html_url": "https://gitea.example.com/octocat/Readonly",
+    "permissions": {"admin": false, "push": false, "pull": true}
+  }
+]
+`)),
+		}, nil
+	},
+	)
+
+	ctx := context.Background()
+	got, err := p.FetchAllRepositoryList(ctx, &common.OauthContext{}, giteaURL)
+	require.NoError(t, err)
+
+	want := []*vcs.Repository{
+		{
+			ID:       "1296269",
+			Name:     "Hello-World",
+			FullPath: "octocat/Hello-World",
+			WebURL:   "https://gitea.example.com/octocat/Hello-World",
+		},
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestProvider_FetchRepositoryFileList(t *testing.T) {
+	p := newMockProvider(func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, "/api/v1/repos/octocat/Hello-World/git/trees/main", r.URL.Path)
+		if r.URL.Query().Get("page") == "1" {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body: io.NopCloser(strings.NewReader(`
+{
+  "sha": "9fb037999f264ba9a7fc6274d15fa3ae2ab98312",
+  "tree": [
+    {"path": "README.md", "type": "blob"},
+    {"path": "prod", "type": "tree"},
+    {"path": "prod/1__init.sql", "type": "blob"}
+  ],
+  "truncated": true,
+  "page": 1,
+  "total_count": 4
+}
+`)),
+			}, nil
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: io.NopCloser(strings.NewReader(`
+{
+  "sha": "9fb037999f264ba9a7fc6274d15fa3ae2ab98312",
+  "tree": [
+    {"path": "prod/2__alter.sql", "type": "blob"}
+  ],
+  "truncated": false,
+  "page": 2,
+  "total_count": 4
+}
+`)),
+		}, nil
+	},
+	)
+
+	ctx := context.Background()
+	got, err := p.FetchRepositoryFileList(ctx, &common.OauthContext{}, giteaURL, "octocat/Hello-World", "main", "prod")
+	require.NoError(t, err)
+
+	want := []*vcs.RepositoryTreeNode{
+		{Path: "prod/1__init.sql", Type: "blob"},
+		{Path: "prod/2__alter.sql", Type: "blob"},
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestProvider_CreateFile(t *testing.T) {
+	p := newMockProvider(func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/api/v1/repos/octocat/Hello-World/contents/prod/1__init.sql", r.URL.Path)
+
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		var fileCommit FileCommit
+		require.NoError(t, json.Unmarshal(body, &fileCommit))
+		assert.Equal(t, FileCommit{
+			Message: "create file",
+			Content: "Q1JFQVRFIFRBQkxFIHQgKGlkIElOVCk7",
+			Branch:  "main",
+			Author: &CommitAuthor{
+				Name:  vcs.BytebaseAuthorName,
+				Email: vcs.BytebaseAuthorEmail,
+			},
+		}, fileCommit)
+		return &http.Response{
+			StatusCode: http.StatusCreated,
+			Body:       io.NopCloser(strings.NewReader("{}")),
+		}, nil
+	},
+	)
+
+	ctx := context.Background()
+	err := p.CreateFile(
+		ctx,
+		&common.OauthContext{},
+		giteaURL,
+		"octocat/Hello-World",
+		"prod/1__init.sql",
+		vcs.FileCommitCreate{
+			Branch:        "main",
+			Content:       "CREATE TABLE t (id INT);",
+			CommitMessage: "create file",
+			AuthorName:    vcs.BytebaseAuthorName,
+			AuthorEmail:   vcs.BytebaseAuthorEmail,
+		},
+	)
+	require.NoError(t, err)
+}
+
+func TestProvider_OverwriteFile(t *testing.T) {
+	p := newMockProvider(func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		assert.Equal(t, "/api/v1/repos/octocat/Hello-World/contents/prod/.LATEST.sql", r.URL.Path)
+
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		var fileCommit FileCommit
+		require.NoError(t, json.Unmarshal(body, &fileCommit))
+		assert.Equal(t, "7638417db6d59f3c431d3e1f261cc637155684cd", fileCommit.SHA)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader("{}")),
+		}, nil
+	},
+	)
+
+	ctx := context.Background()
+	err := p.OverwriteFile(
+		ctx,
+		&common.OauthContext{},
+		giteaURL,
+		"octocat/Hello-World",
+		"prod/.LATEST.sql",
+		vcs.FileCommitCreate{
+			Branch:        "main",
+			Content:       "CREATE TABLE t (id INT);",
+			CommitMessage: "update file",
+			SHA:           "7638417db6d59f3c431d3e1f261cc637155684cd",
+		},
+	)
+	require.NoError(t, err)
+}
+
+func TestProvider_ReadFileMeta(t *testing.T) {
+	p := newMockProvider(func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, "/api/v1/repos/octocat/Hello-World/contents/prod/1__init.sql", r.URL.Path)
+		assert.Equal(t, "feature/init", r.URL.Query().Get("ref"))
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: io.NopCloser(strings.NewReader(`
+{
+  "name": "1__init.sql",
+  "path": "prod/1__init.sql",
+  "sha": "3d21ec53a331a6f037a91c368710b99387d012c1",
+  "last_commit_sha": "7638417db6d59f3c431d3e1f261cc637155684cd",
+  "type": "file",
+  "size": 24,
+  "encoding": "base64",
+  "content": "Q1JFQVRFIFRBQkxFIHQgKGlkIElOVCk7"
+}
+`)),
+		}, nil
+	},
+	)
+
+	ctx := context.Background()
+	got, err := p.ReadFileMeta(ctx, &common.OauthContext{}, giteaURL, "octocat/Hello-World", "prod/1__init.sql", vcs.RefInfo{RefType: vcs.RefTypeBranch, RefName: "feature/init"})
+	require.NoError(t, err)
+
+	want := &vcs.FileMeta{
+		Name:         "1__init.sql",
+		Path:         "prod/1__init.sql",
+		Size:         24,
+		SHA:          "3d21ec53a331a6f037a91c368710b99387d012c1",
+		LastCommitID: "7638417db6d59f3c431d3e1f261cc637155684cd",
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestProvider_ReadFileContent(t *testing.T) {
+	p := newMockProvider(func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, "/api/v1/repos/octocat/Hello-World/raw/prod/1__init.sql", r.URL.Path)
+		assert.Equal(t, "main", r.URL.Query().Get("ref"))
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader("CREATE TABLE t (id INT);")),
+		}, nil
+	},
+	)
+
+	ctx := context.Background()
+	got, err := p.ReadFileContent(ctx, &common.OauthContext{}, giteaURL, "octocat/Hello-World", "prod/1__init.sql", vcs.RefInfo{RefType: vcs.RefTypeBranch, RefName: "main"})
+	require.NoError(t, err)
+	assert.Equal(t, "CREATE TABLE t (id INT);", got)
+}
+
+func TestProvider_GetBranch(t *testing.T) {
+	p := newMockProvider(func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, "/api/v1/repos/octocat/Hello-World/branches/main", r.URL.Path)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: io.NopCloser(strings.NewReader(`
+{
+  "name": "main",
+  "commit": {
+    "id": "7638417db6d59f3c431d3e1f261cc637155684cd",
+    "message": "added readme\n"
+  },
+  "protected": false
+}
+`)),
+		}, nil
+	},
+	)
+
+	ctx := context.Background()
+	got, err := p.GetBranch(ctx, &common.OauthContext{}, giteaURL, "octocat/Hello-World", "main")
+	require.NoError(t, err)
+
+	want := &vcs.BranchInfo{
+		Name:         "main",
+		LastCommitID: "7638417db6d59f3c431d3e1f261cc637155684cd",
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestProvider_CreateBranch(t *testing.T) {
+	p := newMockProvider(func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, "/api/v1/repos/octocat/Hello-World/branches", r.URL.Path)
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"new_branch_name":"bytebase/sql-review","old_ref_name":"7638417db6d59f3c431d3e1f261cc637155684cd"}`, string(body))
+		return &http.Response{
+			StatusCode: http.StatusCreated,
+			Body:       io.NopCloser(strings.NewReader("{}")),
+		}, nil
+	},
+	)
+
+	ctx := context.Background()
+	err := p.CreateBranch(ctx, &common.OauthContext{}, giteaURL, "octocat/Hello-World", &vcs.BranchInfo{
+		Name:         "bytebase/sql-review",
+		LastCommitID: "7638417db6d59f3c431d3e1f261cc637155684cd",
+	})
+	require.NoError(t, err)
+}
+
+func TestProvider_ListPullRequestFile(t *testing.T) {
+	p := newMockProvider(func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, "/api/v1/repos/octocat/Hello-World/pulls/1/files", r.URL.Path)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: io.NopCloser(strings.NewReader(`
+[
+  {
+    "filename": "prod/1__init.sql",
+    "status": "added",
+    "contents_url": "https://gitea.example.com/api/v1/repos/octocat/Hello-World/contents/prod/1__init.sql?ref=7638417db6d59f3c431d3e1f261cc637155684cd"
+  },
+  {
+    "filename": "prod/0__legacy.sql",
+    "status": "deleted",
+    "contents_url": "https://gitea.example.com/api/v1/repos/octocat/Hello-World/contents/prod/0__legacy.sql?ref=7638417db6d59f3c431d3e1f261cc637155684cd"
+  }
+]
+`)),
+		}, nil
+	},
+	)
+
+	ctx := context.Background()
+	got, err := p.ListPullRequestFile(ctx, &common.OauthContext{}, giteaURL, "octocat/Hello-World", "1")
+	require.NoError(t, err)
+
+	want := []*vcs.PullRequestFile{
+		{
+			Path:         "prod/1__init.sql",
+			LastCommitID: "7638417db6d59f3c431d3e1f261cc637155684cd",
+		},
+		{
+			Path:         "prod/0__legacy.sql",
+			LastCommitID: "7638417db6d59f3c431d3e1f261cc637155684cd",
+			IsDeleted:    true,
+		},
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestProvider_CreatePullRequest(t *testing.T) {
+	p := newMockProvider(func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, "/api/v1/repos/octocat/Hello-World/pulls", r.URL.Path)
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"title":"Add SQL review","body":"body","head":"bytebase/sql-review","base":"main"}`, string(body))
+		return &http.Response{
+			StatusCode: http.StatusCreated,
+			Body:       io.NopCloser(strings.NewReader(`{"number": 1, "html_url": "https://gitea.example.com/octocat/Hello-World/pulls/1"}`)),
+		}, nil
+	},
+	)
+
+	ctx := context.Background()
+	got, err := p.CreatePullRequest(ctx, &common.OauthContext{}, giteaURL, "octocat/Hello-World", &vcs.PullRequestCreate{
+		Title: "Add SQL review",
+		Body:  "body",
+		Head:  "bytebase/sql-review",
+		Base:  "main",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &vcs.PullRequest{URL: "https://gitea.example.com/octocat/Hello-World/pulls/1"}, got)
+}
+
+func TestProvider_UpsertEnvironmentVariable(t *testing.T) {
+	p := newMockProvider(func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		assert.Equal(t, "/api/v1/repos/octocat/Hello-World/actions/secrets/SQL_REVIEW_API_SECRET", r.URL.Path)
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"data":"secret"}`, string(body))
+		return &http.Response{
+			StatusCode: http.StatusNoContent,
+			Body:       io.NopCloser(strings.NewReader("")),
+		}, nil
+	},
+	)
+
+	ctx := context.Background()
+	err := p.UpsertEnvironmentVariable(ctx, &common.OauthContext{}, giteaURL, "octocat/Hello-World", vcs.SQLReviewAPISecretName, "secret")
+	require.NoError(t, err)
+}
+
+func TestProvider_CreateWebhook(t *testing.T) {
+	p := newMockProvider(func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, "/api/v1/repos/octocat/Hello-World/hooks", r.URL.Path)
+		return &http.Response{
+			StatusCode: http.StatusCreated,
+			Body:       io.NopCloser(strings.NewReader(`{"id": 12345678, "type": "gitea", "active": true}`)),
+		}, nil
+	},
+	)
+
+	ctx := context.Background()
+	got, err := p.CreateWebhook(ctx, &common.OauthContext{}, giteaURL, "octocat/Hello-World", []byte(""))
+	require.NoError(t, err)
+	assert.Equal(t, "12345678", got)
+}
+
+func TestProvider_PatchWebhook(t *testing.T) {
+	p := newMockProvider(func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, http.MethodPatch, r.Method)
+		assert.Equal(t, "/api/v1/repos/octocat/Hello-World/hooks/1", r.URL.Path)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader("{}")),
+		}, nil
+	},
+	)
+
+	ctx := context.Background()
+	err := p.PatchWebhook(ctx, &common.OauthContext{}, giteaURL, "octocat/Hello-World", "1", []byte(""))
+	require.NoError(t, err)
+}
+
+func TestProvider_DeleteWebhook(t *testing.T) {
+	p := newMockProvider(func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, http.MethodDelete, r.Method)
+		assert.Equal(t, "/api/v1/repos/octocat/Hello-World/hooks/1", r.URL.Path)
+		return &http.Response{
+			StatusCode: http.StatusNoContent,
+			Body:       io.NopCloser(strings.NewReader("")),
+		}, nil
+	},
+	)
+
+	ctx := context.Background()
+	err := p.DeleteWebhook(ctx, &common.OauthContext{}, giteaURL, "octocat/Hello-World", "1")
+	require.NoError(t, err)
+}
+
+func TestOAuth_RefreshExpiredToken(t *testing.T) {
+	expired, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Minute)),
+	}).SignedString([]byte("key"))
+	require.NoError(t, err)
+
+	p := newMockProvider(func(r *http.Request) (*http.Response, error) {
+		if r.URL.Path == "/login/oauth/access_token" {
+			require.NoError(t, r.ParseForm())
+			assert.Equal(t, "refresh_token", r.PostForm.Get("grant_type"))
+			assert.Equal(t, "old_refresh_token", r.PostForm.Get("refresh_token"))
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body: io.NopCloser(strings.NewReader(`
+{
+  "access_token": "new_access_token",
+  "token_type": "bearer",
+  "expires_in": 3600,
+  "refresh_token": "new_refresh_token"
+}
+`)),
+			}, nil
+		}
+		// The request is sent with the refreshed token.
+		assert.Equal(t, "Bearer new_access_token", r.Header.Get("Authorization"))
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(`{"name": "main", "commit": {"id": "7638417db6d59f3c431d3e1f261cc637155684cd"}}`)),
+		}, nil
+	},
+	)
+
+	var refreshed string
+	oauthCtx := &common.OauthContext{
+		AccessToken:  expired,
+		RefreshToken: "old_refresh_token",
+		Refresher: func(token, refreshToken string, expiresTs int64) error {
+			refreshed = fmt.Sprintf("%s/%s", token, refreshToken)
+			assert.NotZero(t, expiresTs)
+			return nil
+		},
+	}
+	ctx := context.Background()
+	_, err = p.GetBranch(ctx, oauthCtx, giteaURL, "octocat/Hello-World", "main")
+	require.NoError(t, err)
+	assert.Equal(t, "new_access_token/new_refresh_token", refreshed)
+	assert.Equal(t, "new_access_token", oauthCtx.AccessToken)
+	assert.Equal(t, "new_refresh_token", oauthCtx.RefreshToken)
+}
+
+func TestWebhookPushEvent_ToVCS(t *testing.T) {
+	var pushEvent WebhookPushEvent
+	err := json.Unmarshal([]byte(`
+{
+  "ref": "refs/heads/main",
+  "before": "a",
+  "after": "c",
+  "commits": [
+    {
+      "id": "c",
+      "message": "Alter table\n\nAdd the name column.\n",
+      "url": "https://gitea.example.com/octocat/Hello-World/commit/c",
+      "author": {"name": "Octocat", "email": "octocat@gitea.example.com", "username": "octocat"},
+      "timestamp": "2023-11-10T10:00:00Z",
+      "added": ["prod/2__alter.sql"],
+      "removed": [],
+      "modified": []
+    },
+    {
+      "id": "b",
+      "message": "Init\n",
+      "url": "https://gitea.example.com/octocat/Hello-World/commit/b",
+      "author": {"name": "Octocat", "email": "octocat@gitea.example.com", "username": "octocat"},
+      "timestamp": "2023-11-10T09:00:00Z",
+      "added": ["prod/1__init.sql"],
+      "removed": [],
+      "modified": []
+    }
+  ],
+  "repository": {"id": 1, "full_name": "octocat/Hello-World", "html_url": "https://gitea.example.com/octocat/Hello-World"},
+  "sender": {"login": "octocat"}
+}
+`), &pushEvent)
+	require.NoError(t, err)
+
+	got := pushEvent.ToVCS()
+	assert.Equal(t, vcs.Gitea, got.VCSType)
+	assert.Equal(t, "octocat/Hello-World", got.RepositoryID)
+	require.Len(t, got.CommitList, 2)
+	// The commits are in the order of commit time.
+	assert.Equal(t, "b", got.CommitList[0].ID)
+	assert.Equal(t, "c", got.CommitList[1].ID)
+	assert.Equal(t, "Alter table", got.CommitList[1].Title)
+	assert.Equal(t, []string{"prod/2__alter.sql"}, got.CommitList[1].AddedList)
+}
+
+func newMockProvider(mockRoundTrip func(r *http.Request) (*http.Response, error)) vcs.Provider {
+	return newProvider(
+		vcs.ProviderConfig{
+			Client: &http.Client{
+				Transport: &common.MockRoundTripper{
+					MockRoundTrip: mockRoundTrip,
+				},
+			},
+		},
+	)
+}
@@ -57,6 +57,10 @@ func newProvider(config vcs.ProviderConfig) vcs.Provider {
 
 // APIURL returns the API URL path of GitHub.
 func (*Provider) APIURL(instanceURL string) string {
+	return getAPIURL(instanceURL)
+}
+
+func getAPIURL(instanceURL string) string {
 	if instanceURL == githubComURL {
 		return "https://api.github.com"
 	}
@@ -124,6 +128,8 @@ const (
 	WebhookPush WebhookType = "push"
 	// WebhookPing is the webhook type for ping.
 	WebhookPing WebhookType = "ping"
+	// WebhookPullRequest is the webhook type for pull request.
+	WebhookPullRequest WebhookType = "pull_request"
 )
 
 // WebhookInfo represents a GitHub API response for the webhook information.
@@ -203,6 +209,29 @@ type WebhookPushEvent struct {
 	Commits    []WebhookCommit   `json:"commits"`
 }
 
+// WebhookPullRequestBranch is the API message for the head or base branch of the pull request in webhook.
+type WebhookPullRequestBranch struct {
+	Ref string `json:"ref"`
+	SHA string `json:"sha"`
+}
+
+// WebhookPullRequestInfo is the API message for the pull request in webhook.
+type WebhookPullRequestInfo struct {
+	Number  int                      `json:"number"`
+	HTMLURL string                   `json:"html_url"`
+	Head    WebhookPullRequestBranch `json:"head"`
+	Base    WebhookPullRequestBranch `json:"base"`
+}
+
+// WebhookPullRequestEvent is the API message for webhook pull request event.
+type WebhookPullRequestEvent struct {
+	// Action is the action of the pull request event.
+	// Available values: "opened", "synchronize", "reopened", "closed", "edited", etc.
+	Action      string                 `json:"action"`
+	PullRequest WebhookPullRequestInfo `json:"pull_request"`
+	Repository  WebhookRepository      `json:"repository"`
+}
+
 // CommitAuthor represents a GitHub API response for a commit author.
 type CommitAuthor struct {
 	// Date expects corresponding JSON value is a string in RFC 3339 format,
@@ -1,10 +1,18 @@
 package github
 
 import (
+	"bytes"
+	"context"
 	_ "embed"
+	"encoding/json"
 	"fmt"
+	"net/http"
 
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/backend/common"
 	"github.com/bytebase/bytebase/backend/plugin/vcs"
+	"github.com/bytebase/bytebase/backend/plugin/vcs/internal/oauth"
 )
 
 // sqlReviewAction is the GitHub action for SQL review in VCS workflow.
@@ -21,3 +29,182 @@ const (
 func SetupSQLReviewCI(endpoint string) string {
 	return fmt.Sprintf(sqlReviewAction, endpoint, vcs.SQLReviewAPISecretName)
 }
+
+// CommitState is the state of the commit status.
+type CommitState string
+
+const (
+	// CommitStateSuccess is the commit state for the passed check.
+	CommitStateSuccess CommitState = "success"
+	// CommitStateFailure is the commit state for the failed check.
+	CommitStateFailure CommitState = "failure"
+)
+
+// CommitStatus is the API message to create the commit status.
+type CommitStatus struct {
+	State       CommitState `json:"state"`
+	TargetURL   string      `json:"target_url,omitempty"`
+	Description string      `json:"description,omitempty"`
+	// Context differentiates this status from the status of other systems.
+	Context string `json:"context"`
+}
+
+// CreateCommitStatus creates the commit status, which is shown as the check in the pull requests containing the commit.
+//
+// Docs: https://docs.github.com/en/rest/commits/statuses#create-a-commit-status
+func CreateCommitStatus(ctx context.Context, oauthCtx *common.OauthContext, instanceURL, repositoryID, commitID string, status *CommitStatus) error {
+	body, err := json.Marshal(status)
+	if err != nil {
+		return errors.Wrap(err, "marshal commit status")
+	}
+
+	url := fmt.Sprintf("%s/repos/%s/statuses/%s", getAPIURL(instanceURL), repositoryID, commitID)
+	_, err = send(ctx, oauthCtx, instanceURL, http.MethodPost, url, body, "create commit status")
+	return err
+}
+
+// PullRequestReviewCommentCreate is the API message to create the inline comment of the pull request.
+type PullRequestReviewCommentCreate struct {
+	CommitID string `json:"commit_id"`
+	Path     string `json:"path"`
+	Line     int    `json:"line"`
+	// Side is the side of the diff, "RIGHT" is for the new file.
+	Side string `json:"side"`
+	Body string `json:"body"`
+}
+
+// PullRequestComment is the API message for the comment of the pull request, which is either the inline comment
+// or the issue comment on the conversation.
+type PullRequestComment struct {
+	ID   int64  `json:"id"`
+	Body string `json:"body"`
+}
+
+// CreatePullRequestReviewComment creates the inline comment in the pull request.
+// GitHub rejects the comment if it's not on the lines of the diff.
+//
+// Docs: https://docs.github.com/en/rest/pulls/comments#create-a-review-comment-for-a-pull-request
+func CreatePullRequestReviewComment(ctx context.Context, oauthCtx *common.OauthContext, instanceURL, repositoryID, pullRequestID string, comment *PullRequestReviewCommentCreate) error {
+	body, err := json.Marshal(comment)
+	if err != nil {
+		return errors.Wrap(err, "marshal pull request review comment")
+	}
+
+	url := fmt.Sprintf("%s/repos/%s/pulls/%s/comments", getAPIURL(instanceURL), repositoryID, pullRequestID)
+	_, err = send(ctx, oauthCtx, instanceURL, http.MethodPost, url, body, "create pull request review comment")
+	return err
+}
+
+// ListPullRequestReviewComments lists the inline comments in the pull request.
+//
+// Docs: https://docs.github.com/en/rest/pulls/comments#list-review-comments-on-a-pull-request
+func ListPullRequestReviewComments(ctx context.Context, oauthCtx *common.OauthContext, instanceURL, repositoryID, pullRequestID string) ([]*PullRequestComment, error) {
+	url := fmt.Sprintf("%s/repos/%s/pulls/%s/comments", getAPIURL(instanceURL), repositoryID, pullRequestID)
+	return listComments(ctx, oauthCtx, instanceURL, url, "list pull request review comments")
+}
+
+// DeletePullRequestReviewComment deletes the inline comment in the pull request.
+//
+// Docs: https://docs.github.com/en/rest/pulls/comments#delete-a-review-comment-for-a-pull-request
+func DeletePullRequestReviewComment(ctx context.Context, oauthCtx *common.OauthContext, instanceURL, repositoryID string, commentID int64) error {
+	url := fmt.Sprintf("%s/repos/%s/pulls/comments/%d", getAPIURL(instanceURL), repositoryID, commentID)
+	_, err := send(ctx, oauthCtx, instanceURL, http.MethodDelete, url, nil, "delete pull request review comment")
+	return err
+}
+
+// ListIssueComments lists the comments on the conversation of the pull request.
+//
+// Docs: https://docs.github.com/en/rest/issues/comments#list-issue-comments
+func ListIssueComments(ctx context.Context, oauthCtx *common.OauthContext, instanceURL, repositoryID, pullRequestID string) ([]*PullRequestComment, error) {
+	url := fmt.Sprintf("%s/repos/%s/issues/%s/comments", getAPIURL(instanceURL), repositoryID, pullRequestID)
+	return listComments(ctx, oauthCtx, instanceURL, url, "list issue comments")
+}
+
+// CreateIssueComment comments on the conversation of the pull request.
+//
+// Docs: https://docs.github.com/en/rest/issues/comments#create-an-issue-comment
+func CreateIssueComment(ctx context.Context, oauthCtx *common.OauthContext, instanceURL, repositoryID, pullRequestID, text string) error {
+	body, err := json.Marshal(map[string]string{"body": text})
+	if err != nil {
+		return errors.Wrap(err, "marshal issue comment")
+	}
+
+	url := fmt.Sprintf("%s/repos/%s/issues/%s/comments", getAPIURL(instanceURL), repositoryID, pullRequestID)
+	_, err = send(ctx, oauthCtx, instanceURL, http.MethodPost, url, body, "create issue comment")
+	return err
+}
+
+// UpdateIssueComment updates the comment on the conversation of the pull request.
+//
+// Docs: https://docs.github.com/en/rest/issues/comments#update-an-issue-comment
+func UpdateIssueComment(ctx context.Context, oauthCtx *common.OauthContext, instanceURL, repositoryID string, commentID int64, text string) error {
+	body, err := json.Marshal(map[string]string{"body": text})
+	if err != nil {
+		return errors.Wrap(err, "marshal issue comment")
+	}
+
+	url := fmt.Sprintf("%s/repos/%s/issues/comments/%d", getAPIURL(instanceURL), repositoryID, commentID)
+	_, err = send(ctx, oauthCtx, instanceURL, http.MethodPatch, url, body, "update issue comment")
+	return err
+}
+
+// listComments lists the comments of all the pages.
+func listComments(ctx context.Context, oauthCtx *common.OauthContext, instanceURL, url, action string) ([]*PullRequestComment, error) {
+	var comments []*PullRequestComment
+	for page := 1; ; page++ {
+		resp, err := send(ctx, oauthCtx, instanceURL, http.MethodGet, fmt.Sprintf("%s?per_page=%d&page=%d", url, apiPageSize, page), nil, action)
+		if err != nil {
+			return nil, err
+		}
+		var pageComments []*PullRequestComment
+		if err := json.Unmarshal([]byte(resp), &pageComments); err != nil {
+			return nil, errors.Wrap(err, "unmarshal comments")
+		}
+		comments = append(comments, pageComments...)
+		if len(pageComments) < apiPageSize {
+			return comments, nil
+		}
+	}
+}
+
+func send(ctx context.Context, oauthCtx *common.OauthContext, instanceURL, method, url string, body []byte, action string) (string, error) {
+	refresher := tokenRefresher(
+		instanceURL,
+		oauthContext{
+			ClientID:     oauthCtx.ClientID,
+			ClientSecret: oauthCtx.ClientSecret,
+			RefreshToken: oauthCtx.RefreshToken,
+		},
+		oauthCtx.Refresher,
+	)
+	var code int
+	var resp string
+	var err error
+	switch method {
+	case http.MethodGet:
+		code, _, resp, err = oauth.Get(ctx, &http.Client{}, url, &oauthCtx.AccessToken, refresher)
+	case http.MethodPost:
+		code, _, resp, err = oauth.Post(ctx, &http.Client{}, url, &oauthCtx.AccessToken, bytes.NewReader(body), refresher)
+	case http.MethodPatch:
+		code, _, resp, err = oauth.Patch(ctx, &http.Client{}, url, &oauthCtx.AccessToken, bytes.NewReader(body), refresher)
+	case http.MethodDelete:
+		code, _, resp, err = oauth.Delete(ctx, &http.Client{}, url, &oauthCtx.AccessToken, refresher)
+	default:
+		return "", errors.Errorf("unsupported method %s", method)
+	}
+	if err != nil {
+		return "", errors.Wrapf(err, "%s %s", method, url)
+	}
+
+	if code == http.StatusNotFound {
+		return "", common.Errorf(common.NotFound, "failed to %s from URL %s", action, url)
+	} else if code >= 300 {
+		return "", errors.Errorf("failed to %s from URL %s, status code: %d, body: %s",
+			action,
+			url,
+			code,
+			resp,
+		)
+	}
+	return resp, nil
+}
@@ -0,0 +1,125 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/backend/common"
+)
+
+func TestCreateCommitStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/api/v3/repos/octocat/Hello-World/statuses/6dcb09b5b57875f334f61aebed695e2e4193db5e", r.URL.Path)
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		var status CommitStatus
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&status))
+		assert.Equal(t, CommitStatus{
+			State:       CommitStateFailure,
+			TargetURL:   "https://bytebase.example.com",
+			Description: "SQL review failed",
+			Context:     "bytebase/sql-review",
+		}, status)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id": 1}`))
+	}))
+	defer server.Close()
+
+	err := CreateCommitStatus(context.Background(), &common.OauthContext{AccessToken: "token"}, server.URL, "octocat/Hello-World", "6dcb09b5b57875f334f61aebed695e2e4193db5e", &CommitStatus{
+		State:       CommitStateFailure,
+		TargetURL:   "https://bytebase.example.com",
+		Description: "SQL review failed",
+		Context:     "bytebase/sql-review",
+	})
+	require.NoError(t, err)
+}
+
+func TestCreatePullRequestReviewComment(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/api/v3/repos/octocat/Hello-World/pulls/1/comments", r.URL.Path)
+		var comment PullRequestReviewCommentCreate
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&comment))
+		if comment.Line > 5 {
+			// Example response taken from https://docs.github.com/en/rest/pulls/comments#create-a-review-comment-for-a-pull-request
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			_, _ = w.Write([]byte(`{"message": "Validation Failed", "errors": [{"resource": "PullRequestReviewComment", "code": "custom", "field": "pull_request_review_thread.line", "message": "could not be resolved"}]}`))
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id": 10}`))
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	oauthCtx := &common.OauthContext{AccessToken: "token"}
+	err := CreatePullRequestReviewComment(ctx, oauthCtx, server.URL, "octocat/Hello-World", "1", &PullRequestReviewCommentCreate{
+		CommitID: "6dcb09b5b57875f334f61aebed695e2e4193db5e",
+		Path:     "prod/1__init.sql",
+		Line:     10,
+		Side:     "RIGHT",
+		Body:     "Unresolved",
+	})
+	assert.ErrorContains(t, err, "status code: 422")
+
+	err = CreatePullRequestReviewComment(ctx, oauthCtx, server.URL, "octocat/Hello-World", "1", &PullRequestReviewCommentCreate{
+		CommitID: "6dcb09b5b57875f334f61aebed695e2e4193db5e",
+		Path:     "prod/1__init.sql",
+		Line:     1,
+		Side:     "RIGHT",
+		Body:     "Resolved",
+	})
+	require.NoError(t, err)
+}
+
+func TestPullRequestComments(t *testing.T) {
+	var deleted []string
+	var updated map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v3/repos/octocat/Hello-World/pulls/1/comments":
+			assert.Equal(t, "100", r.URL.Query().Get("per_page"))
+			if r.URL.Query().Get("page") != "1" {
+				_, _ = w.Write([]byte(`[]`))
+				return
+			}
+			comments := make([]PullRequestComment, 100)
+			for i := range comments {
+				comments[i] = PullRequestComment{ID: int64(i + 1), Body: "Comment"}
+			}
+			require.NoError(t, json.NewEncoder(w).Encode(comments))
+		case r.Method == http.MethodDelete:
+			deleted = append(deleted, r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v3/repos/octocat/Hello-World/issues/1/comments":
+			_, _ = w.Write([]byte(`[{"id": 1, "body": "Me too"}, {"id": 2, "body": "Review"}]`))
+		case r.Method == http.MethodPatch && r.URL.Path == "/api/v3/repos/octocat/Hello-World/issues/comments/2":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&updated))
+			_, _ = w.Write([]byte(`{"id": 2}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	a := require.New(t)
+	ctx := context.Background()
+	oauthCtx := &common.OauthContext{AccessToken: "token"}
+	comments, err := ListPullRequestReviewComments(ctx, oauthCtx, server.URL, "octocat/Hello-World", "1")
+	a.NoError(err)
+	a.Len(comments, 100)
+	a.NoError(DeletePullRequestReviewComment(ctx, oauthCtx, server.URL, "octocat/Hello-World", comments[0].ID))
+	a.Equal([]string{"/api/v3/repos/octocat/Hello-World/pulls/comments/1"}, deleted)
+
+	comments, err = ListIssueComments(ctx, oauthCtx, server.URL, "octocat/Hello-World", "1")
+	a.NoError(err)
+	a.Equal([]*PullRequestComment{{ID: 1, Body: "Me too"}, {ID: 2, Body: "Review"}}, comments)
+	a.NoError(UpdateIssueComment(ctx, oauthCtx, server.URL, "octocat/Hello-World", comments[1].ID, "Updated review"))
+	a.Equal(map[string]string{"body": "Updated review"}, updated)
+}
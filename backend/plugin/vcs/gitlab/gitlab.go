@@ -38,6 +38,8 @@ type WebhookType string
 const (
 	// WebhookPush is the webhook type for push.
 	WebhookPush WebhookType = "push"
+	// WebhookMergeRequest is the webhook type for merge request.
+	WebhookMergeRequest WebhookType = "merge_request"
 )
 
 // WebhookInfo represents a GitLab API response for the webhook information.
@@ -51,12 +53,12 @@ type WebhookCreate struct {
 	SecretToken string `json:"token"`
 	// This is set to true
 	PushEvents bool `json:"push_events"`
+	// MergeRequestsEvents is used to take the SQL review for the merge requests.
 	// For now, there is no native dry run DDL support in mysql/postgres. One may wonder if we could wrap the DDL
 	// in a transaction and just not commit at the end, unfortunately there are side effects which are hard to control.
 	// See https://www.postgresql.org/message-id/CAMsr%2BYGiYQ7PYvYR2Voio37YdCpp79j5S%2BcmgVJMOLM2LnRQcA%40mail.gmail.com
-	// So we can't possibly display useful info when reviewing a MR, thus we don't enable this event.
-	// Saying that, delivering a souding dry run solution would be great and hopefully we can achieve that one day.
-	// MergeRequestsEvents  bool   `json:"merge_requests_events"`
+	// So we only report the SQL review results statically on the merge requests.
+	MergeRequestsEvents   bool `json:"merge_requests_events"`
 	EnableSSLVerification bool `json:"enable_ssl_verification"`
 }
 
@@ -96,6 +98,32 @@ type WebhookPushEvent struct {
 	CommitList []WebhookCommit `json:"commits"`
 }
 
+// WebhookMergeRequestLastCommit is the API message for the last commit of the merge request in webhook.
+type WebhookMergeRequestLastCommit struct {
+	ID string `json:"id"`
+}
+
+// WebhookMergeRequestAttributes is the API message for the attributes of the merge request in webhook.
+type WebhookMergeRequestAttributes struct {
+	IID int `json:"iid"`
+	// Action is the action of the merge request event.
+	// Available values: "open", "close", "reopen", "update", "approved", "unapproved", "approval", "unapproval", "merge".
+	Action string `json:"action"`
+	// OldRev is only set on the "update" action when there are new commits pushed to the merge request.
+	OldRev       string                        `json:"oldrev"`
+	SourceBranch string                        `json:"source_branch"`
+	TargetBranch string                        `json:"target_branch"`
+	URL          string                        `json:"url"`
+	LastCommit   WebhookMergeRequestLastCommit `json:"last_commit"`
+}
+
+// WebhookMergeRequestEvent is the API message for webhook merge request event.
+type WebhookMergeRequestEvent struct {
+	ObjectKind       WebhookType                   `json:"object_kind"`
+	Project          WebhookProject                `json:"project"`
+	ObjectAttributes WebhookMergeRequestAttributes `json:"object_attributes"`
+}
+
 // Commit is the API message for commit.
 type Commit struct {
 	ID         string `json:"id"`
@@ -796,9 +824,17 @@ func (p *Provider) CreateBranch(ctx context.Context, oauthCtx *common.OauthConte
 	return nil
 }
 
+// MergeRequestDiffRefs is the API message for the SHAs of the merge request diff.
+type MergeRequestDiffRefs struct {
+	BaseSHA  string `json:"base_sha"`
+	HeadSHA  string `json:"head_sha"`
+	StartSHA string `json:"start_sha"`
+}
+
 // MergeRequest is the API message for GitLab merge request.
 type MergeRequest struct {
-	WebURL string `json:"web_url"`
+	WebURL   string               `json:"web_url"`
+	DiffRefs MergeRequestDiffRefs `json:"diff_refs"`
 }
 
 // CreatePullRequest creates the pull request in the repository.
@@ -1,12 +1,19 @@
 package gitlab
 
 import (
+	"bytes"
+	"context"
 	_ "embed"
+	"encoding/json"
 	"fmt"
+	"net/http"
 
+	"github.com/pkg/errors"
 	"gopkg.in/yaml.v3"
 
+	"github.com/bytebase/bytebase/backend/common"
 	"github.com/bytebase/bytebase/backend/plugin/vcs"
+	"github.com/bytebase/bytebase/backend/plugin/vcs/internal/oauth"
 )
 
 const (
@@ -67,3 +74,192 @@ func findSQLReviewCI(include []any) (map[string]any, bool) {
 
 	return nil, false
 }
+
+// CommitState is the state of the commit status.
+type CommitState string
+
+const (
+	// CommitStateSuccess is the commit state for the passed check.
+	CommitStateSuccess CommitState = "success"
+	// CommitStateFailed is the commit state for the failed check.
+	CommitStateFailed CommitState = "failed"
+)
+
+// CommitStatus is the API message to create the commit status.
+type CommitStatus struct {
+	State CommitState `json:"state"`
+	// Name differentiates this status from the status of other systems.
+	Name        string `json:"name"`
+	TargetURL   string `json:"target_url,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// CreateCommitStatus creates the commit status, which is shown as the external pipeline in the merge requests containing the commit.
+//
+// Docs: https://docs.gitlab.com/ee/api/commits.html#set-the-pipeline-status-of-a-commit
+func CreateCommitStatus(ctx context.Context, oauthCtx *common.OauthContext, instanceURL, repositoryID, commitID string, status *CommitStatus) error {
+	body, err := json.Marshal(status)
+	if err != nil {
+		return errors.Wrap(err, "marshal commit status")
+	}
+
+	url := fmt.Sprintf("%s/%s/projects/%s/statuses/%s", instanceURL, apiPath, repositoryID, commitID)
+	_, err = send(ctx, oauthCtx, instanceURL, http.MethodPost, url, body, "create commit status")
+	return err
+}
+
+// GetMergeRequest gets the merge request.
+//
+// Docs: https://docs.gitlab.com/ee/api/merge_requests.html#get-single-mr
+func GetMergeRequest(ctx context.Context, oauthCtx *common.OauthContext, instanceURL, repositoryID, mergeRequestID string) (*MergeRequest, error) {
+	url := fmt.Sprintf("%s/%s/projects/%s/merge_requests/%s", instanceURL, apiPath, repositoryID, mergeRequestID)
+	resp, err := send(ctx, oauthCtx, instanceURL, http.MethodGet, url, nil, "get merge request")
+	if err != nil {
+		return nil, err
+	}
+
+	mergeRequest := new(MergeRequest)
+	if err := json.Unmarshal([]byte(resp), mergeRequest); err != nil {
+		return nil, errors.Wrap(err, "unmarshal merge request")
+	}
+	return mergeRequest, nil
+}
+
+// DiscussionPosition is the API message for the position of the diff discussion.
+type DiscussionPosition struct {
+	BaseSHA  string `json:"base_sha"`
+	StartSHA string `json:"start_sha"`
+	HeadSHA  string `json:"head_sha"`
+	// PositionType is the type of the position, "text" is for the text diff.
+	PositionType string `json:"position_type"`
+	NewPath      string `json:"new_path"`
+	NewLine      int    `json:"new_line"`
+}
+
+// MergeRequestDiscussionCreate is the API message to create the merge request discussion.
+type MergeRequestDiscussionCreate struct {
+	Body string `json:"body"`
+	// Position is nil for the discussion on the merge request overview.
+	Position *DiscussionPosition `json:"position,omitempty"`
+}
+
+// CreateMergeRequestDiscussion creates the discussion in the merge request.
+// GitLab rejects the diff discussion if the position is not on the lines of the diff.
+//
+// Docs: https://docs.gitlab.com/ee/api/discussions.html#create-new-merge-request-thread
+func CreateMergeRequestDiscussion(ctx context.Context, oauthCtx *common.OauthContext, instanceURL, repositoryID, mergeRequestID string, discussion *MergeRequestDiscussionCreate) error {
+	body, err := json.Marshal(discussion)
+	if err != nil {
+		return errors.Wrap(err, "marshal merge request discussion")
+	}
+
+	url := fmt.Sprintf("%s/%s/projects/%s/merge_requests/%s/discussions", instanceURL, apiPath, repositoryID, mergeRequestID)
+	_, err = send(ctx, oauthCtx, instanceURL, http.MethodPost, url, body, "create merge request discussion")
+	return err
+}
+
+// MergeRequestNote is the API message for the note of the merge request discussion.
+type MergeRequestNote struct {
+	ID         int    `json:"id"`
+	Body       string `json:"body"`
+	Resolvable bool   `json:"resolvable"`
+	Resolved   bool   `json:"resolved"`
+	// Position is nil for the note on the merge request overview.
+	Position *DiscussionPosition `json:"position"`
+}
+
+// MergeRequestDiscussion is the API message for the merge request discussion.
+type MergeRequestDiscussion struct {
+	ID    string              `json:"id"`
+	Notes []*MergeRequestNote `json:"notes"`
+}
+
+// ListMergeRequestDiscussions lists the discussions in the merge request.
+//
+// Docs: https://docs.gitlab.com/ee/api/discussions.html#list-project-merge-request-discussion-items
+func ListMergeRequestDiscussions(ctx context.Context, oauthCtx *common.OauthContext, instanceURL, repositoryID, mergeRequestID string) ([]*MergeRequestDiscussion, error) {
+	var discussions []*MergeRequestDiscussion
+	for page := 1; ; page++ {
+		url := fmt.Sprintf("%s/%s/projects/%s/merge_requests/%s/discussions?per_page=%d&page=%d", instanceURL, apiPath, repositoryID, mergeRequestID, apiPageSize, page)
+		resp, err := send(ctx, oauthCtx, instanceURL, http.MethodGet, url, nil, "list merge request discussions")
+		if err != nil {
+			return nil, err
+		}
+		var pageDiscussions []*MergeRequestDiscussion
+		if err := json.Unmarshal([]byte(resp), &pageDiscussions); err != nil {
+			return nil, errors.Wrap(err, "unmarshal merge request discussions")
+		}
+		discussions = append(discussions, pageDiscussions...)
+		if len(pageDiscussions) < apiPageSize {
+			return discussions, nil
+		}
+	}
+}
+
+// ResolveMergeRequestDiscussion resolves the discussion in the merge request.
+//
+// Docs: https://docs.gitlab.com/ee/api/discussions.html#resolve-a-merge-request-thread
+func ResolveMergeRequestDiscussion(ctx context.Context, oauthCtx *common.OauthContext, instanceURL, repositoryID, mergeRequestID, discussionID string) error {
+	body, err := json.Marshal(map[string]bool{"resolved": true})
+	if err != nil {
+		return errors.Wrap(err, "marshal merge request discussion")
+	}
+
+	url := fmt.Sprintf("%s/%s/projects/%s/merge_requests/%s/discussions/%s", instanceURL, apiPath, repositoryID, mergeRequestID, discussionID)
+	_, err = send(ctx, oauthCtx, instanceURL, http.MethodPut, url, body, "resolve merge request discussion")
+	return err
+}
+
+// UpdateMergeRequestNote updates the body of the note in the merge request.
+//
+// Docs: https://docs.gitlab.com/ee/api/notes.html#modify-existing-merge-request-note
+func UpdateMergeRequestNote(ctx context.Context, oauthCtx *common.OauthContext, instanceURL, repositoryID, mergeRequestID string, noteID int, text string) error {
+	body, err := json.Marshal(map[string]string{"body": text})
+	if err != nil {
+		return errors.Wrap(err, "marshal merge request note")
+	}
+
+	url := fmt.Sprintf("%s/%s/projects/%s/merge_requests/%s/notes/%d", instanceURL, apiPath, repositoryID, mergeRequestID, noteID)
+	_, err = send(ctx, oauthCtx, instanceURL, http.MethodPut, url, body, "update merge request note")
+	return err
+}
+
+func send(ctx context.Context, oauthCtx *common.OauthContext, instanceURL, method, url string, body []byte, action string) (string, error) {
+	refresher := tokenRefresher(
+		instanceURL,
+		oauthContext{
+			ClientID:     oauthCtx.ClientID,
+			ClientSecret: oauthCtx.ClientSecret,
+			RefreshToken: oauthCtx.RefreshToken,
+		},
+		oauthCtx.Refresher,
+	)
+	var code int
+	var resp string
+	var err error
+	switch method {
+	case http.MethodGet:
+		code, _, resp, err = oauth.Get(ctx, &http.Client{}, url, &oauthCtx.AccessToken, refresher)
+	case http.MethodPost:
+		code, _, resp, err = oauth.Post(ctx, &http.Client{}, url, &oauthCtx.AccessToken, bytes.NewReader(body), refresher)
+	case http.MethodPut:
+		code, _, resp, err = oauth.Put(ctx, &http.Client{}, url, &oauthCtx.AccessToken, bytes.NewReader(body), refresher)
+	default:
+		return "", errors.Errorf("unsupported method %s", method)
+	}
+	if err != nil {
+		return "", errors.Wrapf(err, "%s %s", method, url)
+	}
+
+	if code == http.StatusNotFound {
+		return "", common.Errorf(common.NotFound, "failed to %s from URL %s", action, url)
+	} else if code >= 300 {
+		return "", errors.Errorf("failed to %s from URL %s, status code: %d, body: %s",
+			action,
+			url,
+			code,
+			resp,
+		)
+	}
+	return resp, nil
+}
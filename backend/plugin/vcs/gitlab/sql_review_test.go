@@ -1,11 +1,17 @@
 package gitlab
 
 import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"gopkg.in/yaml.v3"
+
+	"github.com/bytebase/bytebase/backend/common"
 )
 
 const mockGitLabCIContentYAMLStr = `
@@ -44,3 +50,144 @@ func Test_SetupGitLabCI(t *testing.T) {
 	assert.Equal(t, ok, true)
 	assert.NotNil(t, sqlReviewCI)
 }
+
+func TestGetMergeRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "/api/v4/projects/1/merge_requests/2", r.URL.Path)
+		_, _ = w.Write([]byte(`
+{
+  "iid": 2,
+  "web_url": "https://gitlab.example.com/my-group/my-project/merge_requests/2",
+  "diff_refs": {
+    "base_sha": "c380d3acebd181f13629a25d2e2acca46ffe1e00",
+    "head_sha": "2be7ddb704c7b6b83732fdd5b9f09d5a397b5f8f",
+    "start_sha": "c380d3acebd181f13629a25d2e2acca46ffe1e00"
+  }
+}`))
+	}))
+	defer server.Close()
+
+	got, err := GetMergeRequest(context.Background(), &common.OauthContext{AccessToken: "token"}, server.URL, "1", "2")
+	require.NoError(t, err)
+	want := &MergeRequest{
+		WebURL: "https://gitlab.example.com/my-group/my-project/merge_requests/2",
+		DiffRefs: MergeRequestDiffRefs{
+			BaseSHA:  "c380d3acebd181f13629a25d2e2acca46ffe1e00",
+			HeadSHA:  "2be7ddb704c7b6b83732fdd5b9f09d5a397b5f8f",
+			StartSHA: "c380d3acebd181f13629a25d2e2acca46ffe1e00",
+		},
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestCreateMergeRequestDiscussion(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/api/v4/projects/1/merge_requests/2/discussions", r.URL.Path)
+		var discussion MergeRequestDiscussionCreate
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&discussion))
+		if discussion.Position != nil && discussion.Position.NewLine > 5 {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"message": "400 Bad request - Note {:line_code=>[\"can't be blank\", \"must be a valid line code\"]}"}`))
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id": "6a9c1750b37d513a43987b574953fceb50b03ce7"}`))
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	oauthCtx := &common.OauthContext{AccessToken: "token"}
+	position := &DiscussionPosition{
+		BaseSHA:      "c380d3acebd181f13629a25d2e2acca46ffe1e00",
+		StartSHA:     "c380d3acebd181f13629a25d2e2acca46ffe1e00",
+		HeadSHA:      "2be7ddb704c7b6b83732fdd5b9f09d5a397b5f8f",
+		PositionType: "text",
+		NewPath:      "prod/1__init.sql",
+		NewLine:      1,
+	}
+	err := CreateMergeRequestDiscussion(ctx, oauthCtx, server.URL, "1", "2", &MergeRequestDiscussionCreate{Body: "Inline", Position: position})
+	require.NoError(t, err)
+
+	position.NewLine = 10
+	err = CreateMergeRequestDiscussion(ctx, oauthCtx, server.URL, "1", "2", &MergeRequestDiscussionCreate{Body: "Inline", Position: position})
+	assert.ErrorContains(t, err, "status code: 400")
+
+	err = CreateMergeRequestDiscussion(ctx, oauthCtx, server.URL, "1", "2", &MergeRequestDiscussionCreate{Body: "Overview"})
+	require.NoError(t, err)
+}
+
+func TestMergeRequestDiscussions(t *testing.T) {
+	var resolved map[string]bool
+	var updated map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v4/projects/1/merge_requests/2/discussions":
+			assert.Equal(t, "1", r.URL.Query().Get("page"))
+			// Example response taken from https://docs.gitlab.com/ee/api/discussions.html#list-project-merge-request-discussion-items
+			_, _ = w.Write([]byte(`
+[
+  {
+    "id": "6a9c1750b37d513a43987b574953fceb50b03ce7",
+    "individual_note": false,
+    "notes": [
+      {
+        "id": 1126,
+        "type": "DiffNote",
+        "body": "Inline",
+        "resolvable": true,
+        "resolved": false,
+        "position": {
+          "base_sha": "c380d3acebd181f13629a25d2e2acca46ffe1e00",
+          "start_sha": "c380d3acebd181f13629a25d2e2acca46ffe1e00",
+          "head_sha": "2be7ddb704c7b6b83732fdd5b9f09d5a397b5f8f",
+          "position_type": "text",
+          "new_path": "prod/1__init.sql",
+          "new_line": 1
+        }
+      }
+    ]
+  },
+  {
+    "id": "87805b7c09016a7058e91bdbe7b29d1f284a39e6",
+    "individual_note": true,
+    "notes": [
+      {
+        "id": 1128,
+        "type": null,
+        "body": "Overview",
+        "resolvable": false,
+        "resolved": false
+      }
+    ]
+  }
+]`))
+		case r.Method == http.MethodPut && r.URL.Path == "/api/v4/projects/1/merge_requests/2/discussions/6a9c1750b37d513a43987b574953fceb50b03ce7":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&resolved))
+			_, _ = w.Write([]byte(`{"id": "6a9c1750b37d513a43987b574953fceb50b03ce7"}`))
+		case r.Method == http.MethodPut && r.URL.Path == "/api/v4/projects/1/merge_requests/2/notes/1128":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&updated))
+			_, _ = w.Write([]byte(`{"id": 1128}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	a := require.New(t)
+	ctx := context.Background()
+	oauthCtx := &common.OauthContext{AccessToken: "token"}
+	discussions, err := ListMergeRequestDiscussions(ctx, oauthCtx, server.URL, "1", "2")
+	a.NoError(err)
+	a.Len(discussions, 2)
+	a.Equal("Inline", discussions[0].Notes[0].Body)
+	a.True(discussions[0].Notes[0].Resolvable)
+	a.Equal("prod/1__init.sql", discussions[0].Notes[0].Position.NewPath)
+	a.Nil(discussions[1].Notes[0].Position)
+
+	a.NoError(ResolveMergeRequestDiscussion(ctx, oauthCtx, server.URL, "1", "2", discussions[0].ID))
+	a.Equal(map[string]bool{"resolved": true}, resolved)
+	a.NoError(UpdateMergeRequestNote(ctx, oauthCtx, server.URL, "1", "2", discussions[1].Notes[0].ID, "Updated overview"))
+	a.Equal(map[string]string{"body": "Updated overview"}, updated)
+}
@@ -94,6 +94,14 @@ func Put(ctx context.Context, client *http.Client, url string, token *string, bo
 	return retry(ctx, client, token, tokenRefresher, requester(ctx, client, http.MethodPut, url, token, body))
 }
 
+// PutWithHeader makes a HTTP PUT request to the given URL using the token and
+// additional header. It refreshes token and retries the request in the case of
+// the token has expired.
+func PutWithHeader(ctx context.Context, client *http.Client, url string, token *string, body io.Reader, tokenRefresher TokenRefresher, header map[string]string) (code int, _ http.Header, respBody string, err error) {
+	//nolint:bodyclose
+	return retry(ctx, client, token, tokenRefresher, requesterWithHeader(ctx, client, http.MethodPut, url, token, body, header))
+}
+
 // Patch makes a HTTP PATCH request to the given URL using the token. It
 // refreshes token and retries the request in the case of the token has expired.
 func Patch(ctx context.Context, client *http.Client, url string, token *string, body io.Reader, tokenRefresher TokenRefresher) (code int, header http.Header, respBody string, err error) {
@@ -178,6 +186,12 @@ func getOAuthErrorDetails(code int, body []byte) error {
 		return &oauthError{}
 	}
 
+	// Special case for Bitbucket Data Center OAuth error, it responds to the
+	// expired tokens with the authentication failure.
+	if code == http.StatusUnauthorized && bytes.Contains(body, []byte("com.atlassian.bitbucket")) {
+		return &oauthError{}
+	}
+
 	var oe oauthError
 	if err := json.Unmarshal(body, &oe); err != nil {
 		// If we failed to unmarshal body with oauth error, it's not oauthError and we should return nil.
@@ -26,6 +26,8 @@ type FileItemType string
 const (
 	FileItemTypeAdded    FileItemType = "added"
 	FileItemTypeModified FileItemType = "modified"
+	// FileItemTypeRemoved is only derived from the commits diff, because the commit list in push events does not carry removed files.
+	FileItemTypeRemoved FileItemType = "removed"
 )
 
 // DistinctFileItem is an item for distinct file in push event commits.
@@ -26,6 +26,10 @@ const (
 	Bitbucket Type = "BITBUCKET"
 	// AzureDevOps is the VCS type for Azure DevOps.
 	AzureDevOps Type = "AZURE_DEVOPS"
+	// Gitea is the VCS type for Gitea and its fork Forgejo.
+	Gitea Type = "GITEA"
+	// BitbucketDataCenter is the VCS type for self-hosted Bitbucket Data Center (formerly Bitbucket Server).
+	BitbucketDataCenter Type = "BITBUCKET_DATA_CENTER"
 
 	// SQLReviewAPISecretName is the api secret name used in GitHub action or GitLab CI workflow.
 	SQLReviewAPISecretName = "SQL_REVIEW_API_SECRET"
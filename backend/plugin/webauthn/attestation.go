@@ -0,0 +1,173 @@
+package webauthn
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/pem"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	attestationFormatPacked  = "packed"
+	attestationFormatFIDOU2F = "fido-u2f"
+)
+
+// oidFIDOGenCEAAGUID is the certificate extension of the packed attestation with the AAGUID of the authenticator model.
+var oidFIDOGenCEAAGUID = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 45724, 1, 1, 4}
+
+// ParseAttestationRoots parses the PEM encoded root certificates of the authenticator vendors.
+func ParseAttestationRoots(pemCerts []string) (*x509.CertPool, error) {
+	pool := x509.NewCertPool()
+	for _, pemCert := range pemCerts {
+		rest := []byte(pemCert)
+		count := 0
+		for {
+			var block *pem.Block
+			block, rest = pem.Decode(rest)
+			if block == nil {
+				break
+			}
+			if block.Type != "CERTIFICATE" {
+				continue
+			}
+			cert, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to parse attestation root certificate")
+			}
+			pool.AddCert(cert)
+			count++
+		}
+		if count == 0 {
+			return nil, errors.New("attestation root certificate not found in PEM")
+		}
+	}
+	return pool, nil
+}
+
+// verifyAttestation verifies the attestation statement, and returns true if it's signed by a certificate chained to
+// the attestation roots. The invalid statements are rejected, while the self attestation, the unsupported formats
+// and the certificates of the unknown vendors are accepted but not trusted.
+func (rp *RelyingParty) verifyAttestation(format string, statement map[any]any, rawAuthData []byte, authData *authenticatorData, clientDataHash []byte) (bool, error) {
+	if rp.AttestationRoots == nil {
+		return false, nil
+	}
+	var certs []*x509.Certificate
+	switch format {
+	case attestationFormatPacked:
+		x5c, err := parseX5C(statement)
+		if err != nil {
+			return false, err
+		}
+		// The self attestation is signed by the credential itself, which doesn't vouch for the authenticator model.
+		if len(x5c) == 0 {
+			return false, nil
+		}
+		alg, _ := statement["alg"].(int64)
+		signature, _ := statement["sig"].([]byte)
+		var signatureAlgorithm x509.SignatureAlgorithm
+		switch alg {
+		case AlgES256:
+			signatureAlgorithm = x509.ECDSAWithSHA256
+		case AlgRS256:
+			signatureAlgorithm = x509.SHA256WithRSA
+		default:
+			return false, errors.Errorf("unsupported attestation algorithm %d", alg)
+		}
+		if err := x5c[0].CheckSignature(signatureAlgorithm, append(append([]byte{}, rawAuthData...), clientDataHash...), signature); err != nil {
+			return false, errors.Wrapf(err, "invalid attestation signature")
+		}
+		if x5c[0].IsCA {
+			return false, errors.New("attestation certificate cannot be a CA")
+		}
+		for _, extension := range x5c[0].Extensions {
+			if !extension.Id.Equal(oidFIDOGenCEAAGUID) {
+				continue
+			}
+			var aaguid []byte
+			if _, err := asn1.Unmarshal(extension.Value, &aaguid); err != nil || !bytes.Equal(aaguid, authData.aaguid) {
+				return false, errors.New("AAGUID mismatch between attestation certificate and authenticator data")
+			}
+		}
+		certs = x5c
+	case attestationFormatFIDOU2F:
+		x5c, err := parseX5C(statement)
+		if err != nil {
+			return false, err
+		}
+		if len(x5c) != 1 {
+			return false, errors.New("fido-u2f attestation must have exactly one certificate")
+		}
+		if key, ok := x5c[0].PublicKey.(*ecdsa.PublicKey); !ok || key.Curve != elliptic.P256() {
+			return false, errors.New("fido-u2f attestation certificate must have a P-256 public key")
+		}
+		_, publicKey, err := parsePublicKey(authData.publicKey)
+		if err != nil {
+			return false, err
+		}
+		credentialKey, ok := publicKey.(*ecdsa.PublicKey)
+		if !ok {
+			return false, errors.New("fido-u2f credential must have an ES256 public key")
+		}
+		// The U2F registration data is 0x00, the RP ID hash, the client data hash, the credential ID and the
+		// uncompressed public key.
+		data := []byte{0x00}
+		data = append(data, authData.rpIDHash...)
+		data = append(data, clientDataHash...)
+		data = append(data, authData.credentialID...)
+		x := make([]byte, 32)
+		y := make([]byte, 32)
+		credentialKey.X.FillBytes(x)
+		credentialKey.Y.FillBytes(y)
+		data = append(data, 0x04)
+		data = append(data, x...)
+		data = append(data, y...)
+		signature, _ := statement["sig"].([]byte)
+		if err := x5c[0].CheckSignature(x509.ECDSAWithSHA256, data, signature); err != nil {
+			return false, errors.Wrapf(err, "invalid attestation signature")
+		}
+		certs = x5c
+	default:
+		return false, nil
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+	if _, err := certs[0].Verify(x509.VerifyOptions{
+		Roots:         rp.AttestationRoots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	}); err != nil {
+		return false, nil
+	}
+	return true, nil
+}
+
+func parseX5C(statement map[any]any) ([]*x509.Certificate, error) {
+	v, ok := statement["x5c"]
+	if !ok {
+		return nil, nil
+	}
+	array, ok := v.([]any)
+	if !ok || len(array) == 0 {
+		return nil, errors.New("invalid attestation certificates")
+	}
+	var certs []*x509.Certificate
+	for _, item := range array {
+		der, ok := item.([]byte)
+		if !ok {
+			return nil, errors.New("invalid attestation certificate")
+		}
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse attestation certificate")
+		}
+		certs = append(certs, cert)
+	}
+	return certs, nil
+}
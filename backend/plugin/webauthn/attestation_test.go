@@ -0,0 +1,205 @@
+package webauthn
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func newTestCA(t *testing.T) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test Attestation Root"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return &testCA{cert: cert, key: key}
+}
+
+// issue returns the DER encoded attestation certificate and its private key, with the AAGUID extension if it's set.
+func (ca *testCA) issue(t *testing.T, aaguid []byte) ([]byte, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(2),
+		Subject:               pkix.Name{CommonName: "Test Authenticator Attestation"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		BasicConstraintsValid: true,
+	}
+	if aaguid != nil {
+		value, err := asn1.Marshal(aaguid)
+		require.NoError(t, err)
+		template.ExtraExtensions = []pkix.Extension{{Id: oidFIDOGenCEAAGUID, Value: value}}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+	return der, key
+}
+
+func (ca *testCA) pem() string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.cert.Raw}))
+}
+
+// packedAttestation returns the packed attestation statement {"alg": -7, "sig": sig, "x5c": [cert]}.
+func packedAttestation(t *testing.T, cert []byte, key *ecdsa.PrivateKey) func(authData, clientDataHash []byte) (string, []byte) {
+	return func(authData, clientDataHash []byte) (string, []byte) {
+		digest := sha256.Sum256(append(append([]byte{}, authData...), clientDataHash...))
+		signature, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+		require.NoError(t, err)
+		statement := []byte{0xa3}
+		statement = append(statement, encodeTestCBOR(3, []byte("alg"))...)
+		statement = append(statement, 0x26)
+		statement = append(statement, encodeTestCBOR(3, []byte("sig"))...)
+		statement = append(statement, encodeTestCBOR(2, signature)...)
+		statement = append(statement, encodeTestCBOR(3, []byte("x5c"))...)
+		statement = append(statement, 0x81)
+		statement = append(statement, encodeTestCBOR(2, cert)...)
+		return attestationFormatPacked, statement
+	}
+}
+
+// u2fAttestation returns the fido-u2f attestation statement {"sig": sig, "x5c": [cert]}.
+func u2fAttestation(t *testing.T, authenticator *testAuthenticator, cert []byte, key *ecdsa.PrivateKey) func(authData, clientDataHash []byte) (string, []byte) {
+	return func(authData, clientDataHash []byte) (string, []byte) {
+		x := make([]byte, 32)
+		y := make([]byte, 32)
+		authenticator.key.X.FillBytes(x)
+		authenticator.key.Y.FillBytes(y)
+		data := []byte{0x00}
+		data = append(data, authData[:32]...)
+		data = append(data, clientDataHash...)
+		data = append(data, authenticator.credentialID...)
+		data = append(data, 0x04)
+		data = append(data, x...)
+		data = append(data, y...)
+		digest := sha256.Sum256(data)
+		signature, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+		require.NoError(t, err)
+		statement := []byte{0xa2}
+		statement = append(statement, encodeTestCBOR(3, []byte("sig"))...)
+		statement = append(statement, encodeTestCBOR(2, signature)...)
+		statement = append(statement, encodeTestCBOR(3, []byte("x5c"))...)
+		statement = append(statement, 0x81)
+		statement = append(statement, encodeTestCBOR(2, cert)...)
+		return attestationFormatFIDOU2F, statement
+	}
+}
+
+func TestVerifyAttestation(t *testing.T) {
+	a := require.New(t)
+	origin := "https://bytebase.example.com"
+	ca := newTestCA(t)
+	roots, err := ParseAttestationRoots([]string{ca.pem()})
+	a.NoError(err)
+	rp, err := NewRelyingParty("bytebase.example.com", "Bytebase", []string{origin})
+	a.NoError(err)
+	rp.AttestationRoots = roots
+	a.Equal("direct", rp.NewCreationOptions("challenge", []byte("101"), "bob@example.com", "Bob", nil).Attestation)
+	authenticator := newTestAuthenticator(t, rp.ID)
+	aaguid := []byte("0123456789abcdef")
+	cert, key := ca.issue(t, aaguid)
+	unknownCert, unknownKey := newTestCA(t).issue(t, aaguid)
+	u2fCert, u2fKey := ca.issue(t, nil)
+
+	tests := []struct {
+		name         string
+		aaguid       []byte
+		attest       func(authData, clientDataHash []byte) (string, []byte)
+		wantAttested bool
+		wantErr      bool
+	}{
+		{
+			name:   "none",
+			aaguid: aaguid,
+		},
+		{
+			name:         "packed",
+			aaguid:       aaguid,
+			attest:       packedAttestation(t, cert, key),
+			wantAttested: true,
+		},
+		{
+			name:   "packed by an unknown vendor",
+			aaguid: aaguid,
+			attest: packedAttestation(t, unknownCert, unknownKey),
+		},
+		{
+			name:    "packed with an invalid signature",
+			aaguid:  aaguid,
+			attest:  packedAttestation(t, cert, unknownKey),
+			wantErr: true,
+		},
+		{
+			name:    "packed with a mismatched AAGUID",
+			aaguid:  []byte("fedcba9876543210"),
+			attest:  packedAttestation(t, cert, key),
+			wantErr: true,
+		},
+		{
+			name:         "fido-u2f",
+			aaguid:       make([]byte, 16),
+			attest:       u2fAttestation(t, authenticator, u2fCert, u2fKey),
+			wantAttested: true,
+		},
+		{
+			name:    "fido-u2f with an invalid signature",
+			aaguid:  make([]byte, 16),
+			attest:  u2fAttestation(t, authenticator, u2fCert, key),
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		challenge, err := NewChallenge()
+		a.NoError(err)
+		credential, err := rp.VerifyRegistration(challenge, authenticator.createWithAttestation(t, origin, challenge, test.aaguid, test.attest))
+		if test.wantErr {
+			a.Error(err, test.name)
+			continue
+		}
+		a.NoError(err, test.name)
+		a.Equal(test.aaguid, credential.AAGUID, test.name)
+		a.Equal(test.wantAttested, credential.Attested, test.name)
+	}
+
+	// The attestation is not verified without the attestation roots.
+	rp.AttestationRoots = nil
+	a.Equal("none", rp.NewCreationOptions("challenge", []byte("101"), "bob@example.com", "Bob", nil).Attestation)
+	challenge, err := NewChallenge()
+	a.NoError(err)
+	credential, err := rp.VerifyRegistration(challenge, authenticator.createWithAttestation(t, origin, challenge, aaguid, packedAttestation(t, cert, key)))
+	a.NoError(err)
+	a.False(credential.Attested)
+}
+
+func TestParseAttestationRoots(t *testing.T) {
+	a := require.New(t)
+	_, err := ParseAttestationRoots([]string{newTestCA(t).pem() + newTestCA(t).pem()})
+	a.NoError(err)
+	_, err = ParseAttestationRoots([]string{"not a certificate"})
+	a.Error(err)
+}
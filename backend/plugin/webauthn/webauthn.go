@@ -0,0 +1,528 @@
+// Package webauthn is the plugin for the WebAuthn relying party, which registers and verifies
+// the security keys and the passkeys. The credential is bound to the origin and the user. The authenticator
+// model is only trusted if the packed or fido-u2f attestation statement is signed by a certificate chained to
+// the attestation roots of the relying party, the other attestation formats are accepted but not trusted.
+//
+// The challenges are random nonces. The caller must store the issued challenges and consume each
+// of them once before the verification, because the passkeys don't have the signature counters
+// to detect the replayed assertions.
+package webauthn
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"math/big"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// ChallengeTimeout is the duration that the challenge is valid for.
+	ChallengeTimeout = 5 * time.Minute
+
+	challengeNonceSize = 32
+
+	credentialTypePublicKey = "public-key"
+	clientDataTypeCreate    = "webauthn.create"
+	clientDataTypeGet       = "webauthn.get"
+
+	// The flags of the authenticator data.
+	flagUserPresent    = 0x01
+	flagUserVerified   = 0x04
+	flagBackupEligible = 0x08
+	flagAttestedData   = 0x40
+
+	// The COSE key parameters.
+	coseKeyType    = 1
+	coseKeyAlg     = 3
+	coseKeyCurve   = -1
+	coseKeyX       = -2
+	coseKeyY       = -3
+	coseKeyRSAN    = -1
+	coseKeyRSAE    = -2
+	coseKeyTypeOKP = 1
+	coseKeyTypeEC2 = 2
+	coseKeyTypeRSA = 3
+	coseCurveP256  = 1
+	coseCurveEd255 = 6
+
+	// AlgES256 is ECDSA with SHA-256 on the P-256 curve.
+	AlgES256 = -7
+	// AlgEdDSA is EdDSA on the Ed25519 curve.
+	AlgEdDSA = -8
+	// AlgRS256 is RSASSA-PKCS1-v1_5 with SHA-256.
+	AlgRS256 = -257
+)
+
+// RelyingParty is the WebAuthn relying party.
+type RelyingParty struct {
+	// ID is the RP ID, i.e. the effective domain of the origins.
+	ID string
+	// Name is the human-palatable name of the relying party.
+	Name string
+	// Origins are the allowed origins of the client data, e.g. https://bytebase.example.com.
+	Origins []string
+	// AttestationRoots are the trusted root certificates of the authenticator vendors. The attestation
+	// is requested and verified only if it's set.
+	AttestationRoots *x509.CertPool
+}
+
+// NewRelyingParty creates a new relying party.
+func NewRelyingParty(id, name string, origins []string) (*RelyingParty, error) {
+	if id == "" {
+		return nil, errors.New("RP ID cannot be empty")
+	}
+	if len(origins) == 0 {
+		return nil, errors.New("origins cannot be empty")
+	}
+	return &RelyingParty{
+		ID:      id,
+		Name:    name,
+		Origins: origins,
+	}, nil
+}
+
+// Credential is the public key credential registered by the user.
+type Credential struct {
+	ID []byte
+	// PublicKey is the COSE encoded public key.
+	PublicKey []byte
+	SignCount uint32
+	// BackupEligible is true if the credential can be synced across devices, e.g. the synced passkeys.
+	BackupEligible bool
+	UserVerified   bool
+	// AAGUID is the authenticator model ID, which is all zero for the U2F security keys.
+	AAGUID []byte
+	// Attested is true if the attestation statement is signed by a certificate chained to the attestation roots,
+	// i.e. the authenticator model is vouched for by its vendor.
+	Attested bool
+}
+
+// AssertionResult is the result of the verified assertion.
+type AssertionResult struct {
+	SignCount    uint32
+	UserVerified bool
+}
+
+// CredentialDescriptor is the JSON serialization of PublicKeyCredentialDescriptor.
+type CredentialDescriptor struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+}
+
+// CreationOptions is the JSON serialization of PublicKeyCredentialCreationOptions,
+// which can be parsed by PublicKeyCredential.parseCreationOptionsFromJSON().
+type CreationOptions struct {
+	RP struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"rp"`
+	User struct {
+		ID          string `json:"id"`
+		Name        string `json:"name"`
+		DisplayName string `json:"displayName"`
+	} `json:"user"`
+	Challenge        string `json:"challenge"`
+	PubKeyCredParams []struct {
+		Type string `json:"type"`
+		Alg  int    `json:"alg"`
+	} `json:"pubKeyCredParams"`
+	Timeout                int64                  `json:"timeout"`
+	ExcludeCredentials     []CredentialDescriptor `json:"excludeCredentials"`
+	AuthenticatorSelection struct {
+		ResidentKey      string `json:"residentKey"`
+		UserVerification string `json:"userVerification"`
+	} `json:"authenticatorSelection"`
+	Attestation string `json:"attestation"`
+}
+
+// RequestOptions is the JSON serialization of PublicKeyCredentialRequestOptions,
+// which can be parsed by PublicKeyCredential.parseRequestOptionsFromJSON().
+type RequestOptions struct {
+	Challenge        string                 `json:"challenge"`
+	Timeout          int64                  `json:"timeout"`
+	RPID             string                 `json:"rpId"`
+	AllowCredentials []CredentialDescriptor `json:"allowCredentials"`
+	UserVerification string                 `json:"userVerification"`
+}
+
+// CredentialResponse is the JSON serialization of PublicKeyCredential, i.e. the result of PublicKeyCredential.toJSON().
+type CredentialResponse struct {
+	ID       string `json:"id"`
+	RawID    string `json:"rawId"`
+	Type     string `json:"type"`
+	Response struct {
+		ClientDataJSON    string `json:"clientDataJSON"`
+		AttestationObject string `json:"attestationObject"`
+		AuthenticatorData string `json:"authenticatorData"`
+		Signature         string `json:"signature"`
+		UserHandle        string `json:"userHandle"`
+	} `json:"response"`
+}
+
+type clientData struct {
+	Type      string `json:"type"`
+	Challenge string `json:"challenge"`
+	Origin    string `json:"origin"`
+}
+
+type authenticatorData struct {
+	rpIDHash     []byte
+	flags        byte
+	signCount    uint32
+	aaguid       []byte
+	credentialID []byte
+	publicKey    []byte
+}
+
+// ParseCredentialResponse parses the JSON serialization of PublicKeyCredential.
+func ParseCredentialResponse(s string) (*CredentialResponse, error) {
+	response := &CredentialResponse{}
+	if err := json.Unmarshal([]byte(s), response); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal credential")
+	}
+	if response.Type != credentialTypePublicKey {
+		return nil, errors.Errorf("invalid credential type %q", response.Type)
+	}
+	return response, nil
+}
+
+// CredentialID returns the raw ID of the credential.
+func (r *CredentialResponse) CredentialID() ([]byte, error) {
+	id, err := decodeBase64URL(r.RawID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid credential ID")
+	}
+	if len(id) == 0 {
+		return nil, errors.New("credential ID cannot be empty")
+	}
+	return id, nil
+}
+
+// UserHandle returns the user handle of the assertion, which is only set for the discoverable credentials.
+func (r *CredentialResponse) UserHandle() ([]byte, error) {
+	return decodeBase64URL(r.Response.UserHandle)
+}
+
+// Challenge returns the challenge in the client data, which should be consumed before the verification.
+func (r *CredentialResponse) Challenge() (string, error) {
+	data, _, err := parseClientData(r.Response.ClientDataJSON)
+	if err != nil {
+		return "", err
+	}
+	if data.Challenge == "" {
+		return "", errors.New("challenge cannot be empty")
+	}
+	return data.Challenge, nil
+}
+
+// NewChallenge returns a random challenge.
+func NewChallenge() (string, error) {
+	nonce := make([]byte, challengeNonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", errors.Wrapf(err, "failed to generate nonce")
+	}
+	return base64.RawURLEncoding.EncodeToString(nonce), nil
+}
+
+// NewCreationOptions returns the options for registering a new credential of the user.
+// The existing credentials are excluded to avoid registering the same authenticator twice.
+// The attestation is only requested if the attestation roots are set, because it may identify the authenticator model.
+func (rp *RelyingParty) NewCreationOptions(challenge string, userHandle []byte, userName, displayName string, excludeCredentialIDs [][]byte) *CreationOptions {
+	options := &CreationOptions{
+		Challenge:          challenge,
+		Timeout:            ChallengeTimeout.Milliseconds(),
+		ExcludeCredentials: newCredentialDescriptors(excludeCredentialIDs),
+		Attestation:        "none",
+	}
+	if rp.AttestationRoots != nil {
+		options.Attestation = "direct"
+	}
+	options.RP.ID = rp.ID
+	options.RP.Name = rp.Name
+	options.User.ID = base64.RawURLEncoding.EncodeToString(userHandle)
+	options.User.Name = userName
+	options.User.DisplayName = displayName
+	for _, alg := range []int{AlgES256, AlgEdDSA, AlgRS256} {
+		options.PubKeyCredParams = append(options.PubKeyCredParams, struct {
+			Type string `json:"type"`
+			Alg  int    `json:"alg"`
+		}{Type: credentialTypePublicKey, Alg: alg})
+	}
+	// The discoverable credentials are preferred for the passwordless login.
+	options.AuthenticatorSelection.ResidentKey = "preferred"
+	options.AuthenticatorSelection.UserVerification = "preferred"
+	return options
+}
+
+// NewRequestOptions returns the options for the assertion. The allowed credentials are empty for the
+// discoverable credentials, and the user verification is required for the passwordless login.
+func (rp *RelyingParty) NewRequestOptions(challenge string, allowCredentialIDs [][]byte, requireUserVerification bool) *RequestOptions {
+	userVerification := "discouraged"
+	if requireUserVerification {
+		userVerification = "required"
+	}
+	return &RequestOptions{
+		Challenge:        challenge,
+		Timeout:          ChallengeTimeout.Milliseconds(),
+		RPID:             rp.ID,
+		AllowCredentials: newCredentialDescriptors(allowCredentialIDs),
+		UserVerification: userVerification,
+	}
+}
+
+// VerifyRegistration verifies the attestation response of navigator.credentials.create() for the consumed challenge
+// and returns the new credential.
+func (rp *RelyingParty) VerifyRegistration(challenge string, response *CredentialResponse) (*Credential, error) {
+	clientDataJSON, err := rp.verifyClientData(challenge, clientDataTypeCreate, response.Response.ClientDataJSON)
+	if err != nil {
+		return nil, err
+	}
+	attestationObject, err := decodeBase64URL(response.Response.AttestationObject)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid attestation object")
+	}
+	v, _, err := decodeCBOR(attestationObject)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to decode attestation object")
+	}
+	attestation, ok := v.(map[any]any)
+	if !ok {
+		return nil, errors.New("invalid attestation object")
+	}
+	rawAuthData, ok := attestation["authData"].([]byte)
+	if !ok {
+		return nil, errors.New("authenticator data not found in attestation object")
+	}
+	authData, err := rp.parseAuthenticatorData(rawAuthData)
+	if err != nil {
+		return nil, err
+	}
+	if authData.flags&flagAttestedData == 0 {
+		return nil, errors.New("attested credential data not found")
+	}
+	credentialID, err := response.CredentialID()
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(credentialID, authData.credentialID) {
+		return nil, errors.New("credential ID mismatch")
+	}
+	// Validate the public key is supported before saving the credential.
+	if _, _, err := parsePublicKey(authData.publicKey); err != nil {
+		return nil, err
+	}
+	format, _ := attestation["fmt"].(string)
+	statement, _ := attestation["attStmt"].(map[any]any)
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	attested, err := rp.verifyAttestation(format, statement, rawAuthData, authData, clientDataHash[:])
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to verify %s attestation", format)
+	}
+	return &Credential{
+		ID:             authData.credentialID,
+		PublicKey:      authData.publicKey,
+		SignCount:      authData.signCount,
+		BackupEligible: authData.flags&flagBackupEligible != 0,
+		UserVerified:   authData.flags&flagUserVerified != 0,
+		AAGUID:         authData.aaguid,
+		Attested:       attested,
+	}, nil
+}
+
+// VerifyAssertion verifies the assertion response of navigator.credentials.get() for the consumed challenge
+// with the registered credential.
+func (rp *RelyingParty) VerifyAssertion(challenge string, credential *Credential, response *CredentialResponse) (*AssertionResult, error) {
+	clientDataJSON, err := rp.verifyClientData(challenge, clientDataTypeGet, response.Response.ClientDataJSON)
+	if err != nil {
+		return nil, err
+	}
+	rawAuthData, err := decodeBase64URL(response.Response.AuthenticatorData)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid authenticator data")
+	}
+	authData, err := rp.parseAuthenticatorData(rawAuthData)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := decodeBase64URL(response.Response.Signature)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid signature")
+	}
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	if err := verifySignature(credential.PublicKey, append(slices.Clip(rawAuthData), clientDataHash[:]...), signature); err != nil {
+		return nil, err
+	}
+	// The authenticators without the signature counter always return zero.
+	if (authData.signCount != 0 || credential.SignCount != 0) && authData.signCount <= credential.SignCount {
+		return nil, errors.New("signature counter did not increase, the authenticator may be cloned")
+	}
+	return &AssertionResult{
+		SignCount:    authData.signCount,
+		UserVerified: authData.flags&flagUserVerified != 0,
+	}, nil
+}
+
+func parseClientData(encoded string) (*clientData, []byte, error) {
+	clientDataJSON, err := decodeBase64URL(encoded)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "invalid client data")
+	}
+	data := &clientData{}
+	if err := json.Unmarshal(clientDataJSON, data); err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to unmarshal client data")
+	}
+	return data, clientDataJSON, nil
+}
+
+func (rp *RelyingParty) verifyClientData(challenge, clientDataType, encoded string) ([]byte, error) {
+	data, clientDataJSON, err := parseClientData(encoded)
+	if err != nil {
+		return nil, err
+	}
+	if data.Type != clientDataType {
+		return nil, errors.Errorf("invalid client data type %q, expect %q", data.Type, clientDataType)
+	}
+	if !slices.Contains(rp.Origins, data.Origin) {
+		return nil, errors.Errorf("origin %q is not allowed", data.Origin)
+	}
+	if challenge == "" || subtle.ConstantTimeCompare([]byte(data.Challenge), []byte(challenge)) != 1 {
+		return nil, errors.New("challenge mismatch")
+	}
+	return clientDataJSON, nil
+}
+
+func (rp *RelyingParty) parseAuthenticatorData(data []byte) (*authenticatorData, error) {
+	if len(data) < 37 {
+		return nil, errors.New("authenticator data is too short")
+	}
+	authData := &authenticatorData{
+		rpIDHash:  data[:32],
+		flags:     data[32],
+		signCount: binary.BigEndian.Uint32(data[33:37]),
+	}
+	rpIDHash := sha256.Sum256([]byte(rp.ID))
+	if subtle.ConstantTimeCompare(authData.rpIDHash, rpIDHash[:]) != 1 {
+		return nil, errors.New("RP ID hash mismatch")
+	}
+	if authData.flags&flagUserPresent == 0 {
+		return nil, errors.New("user is not present")
+	}
+	if authData.flags&flagAttestedData == 0 {
+		return authData, nil
+	}
+	// The attested credential data is the 16-byte AAGUID, the 2-byte credential ID length,
+	// the credential ID and the COSE encoded public key.
+	rest := data[37:]
+	if len(rest) < 18 {
+		return nil, errors.New("attested credential data is too short")
+	}
+	authData.aaguid = append([]byte{}, rest[:16]...)
+	idLength := int(binary.BigEndian.Uint16(rest[16:18]))
+	rest = rest[18:]
+	if len(rest) < idLength {
+		return nil, errors.New("credential ID is too short")
+	}
+	authData.credentialID = append([]byte{}, rest[:idLength]...)
+	_, n, err := decodeCBOR(rest[idLength:])
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to decode credential public key")
+	}
+	authData.publicKey = append([]byte{}, rest[idLength:idLength+n]...)
+	return authData, nil
+}
+
+// parsePublicKey parses the COSE encoded public key, and returns the algorithm and the public key.
+func parsePublicKey(coseKey []byte) (int64, crypto.PublicKey, error) {
+	v, _, err := decodeCBOR(coseKey)
+	if err != nil {
+		return 0, nil, errors.Wrapf(err, "failed to decode public key")
+	}
+	key, ok := v.(map[any]any)
+	if !ok {
+		return 0, nil, errors.New("invalid public key")
+	}
+	keyType, _ := key[int64(coseKeyType)].(int64)
+	alg, _ := key[int64(coseKeyAlg)].(int64)
+	switch {
+	case keyType == coseKeyTypeEC2 && alg == AlgES256:
+		curve, _ := key[int64(coseKeyCurve)].(int64)
+		x, _ := key[int64(coseKeyX)].([]byte)
+		y, _ := key[int64(coseKeyY)].([]byte)
+		if curve != coseCurveP256 || len(x) != 32 || len(y) != 32 {
+			return 0, nil, errors.New("invalid ES256 public key")
+		}
+		publicKey := &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !publicKey.Curve.IsOnCurve(publicKey.X, publicKey.Y) {
+			return 0, nil, errors.New("invalid ES256 public key")
+		}
+		return alg, publicKey, nil
+	case keyType == coseKeyTypeOKP && alg == AlgEdDSA:
+		curve, _ := key[int64(coseKeyCurve)].(int64)
+		x, _ := key[int64(coseKeyX)].([]byte)
+		if curve != coseCurveEd255 || len(x) != ed25519.PublicKeySize {
+			return 0, nil, errors.New("invalid EdDSA public key")
+		}
+		return alg, ed25519.PublicKey(x), nil
+	case keyType == coseKeyTypeRSA && alg == AlgRS256:
+		n, _ := key[int64(coseKeyRSAN)].([]byte)
+		e, _ := key[int64(coseKeyRSAE)].([]byte)
+		if len(n) < 256 || len(e) == 0 || len(e) > 4 {
+			return 0, nil, errors.New("invalid RS256 public key")
+		}
+		return alg, &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	}
+	return 0, nil, errors.Errorf("unsupported public key type %d with algorithm %d", keyType, alg)
+}
+
+// verifySignature verifies the signature with the COSE encoded public key.
+func verifySignature(coseKey, data, signature []byte) error {
+	alg, publicKey, err := parsePublicKey(coseKey)
+	if err != nil {
+		return err
+	}
+	digest := sha256.Sum256(data)
+	var valid bool
+	switch alg {
+	case AlgES256:
+		valid = ecdsa.VerifyASN1(publicKey.(*ecdsa.PublicKey), digest[:], signature)
+	case AlgEdDSA:
+		valid = ed25519.Verify(publicKey.(ed25519.PublicKey), data, signature)
+	case AlgRS256:
+		valid = rsa.VerifyPKCS1v15(publicKey.(*rsa.PublicKey), crypto.SHA256, digest[:], signature) == nil
+	}
+	if !valid {
+		return errors.New("invalid signature")
+	}
+	return nil
+}
+
+func newCredentialDescriptors(ids [][]byte) []CredentialDescriptor {
+	descriptors := []CredentialDescriptor{}
+	for _, id := range ids {
+		descriptors = append(descriptors, CredentialDescriptor{
+			Type: credentialTypePublicKey,
+			ID:   base64.RawURLEncoding.EncodeToString(id),
+		})
+	}
+	return descriptors
+}
+
+// decodeBase64URL decodes the base64url string with or without the padding.
+func decodeBase64URL(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+}
@@ -0,0 +1,211 @@
+package webauthn
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeCBOR(t *testing.T) {
+	tests := []struct {
+		data []byte
+		want any
+	}{
+		{data: []byte{0x0a}, want: int64(10)},
+		{data: []byte{0x18, 0x64}, want: int64(100)},
+		{data: []byte{0x26}, want: int64(-7)},
+		{data: []byte{0x39, 0x01, 0x00}, want: int64(-257)},
+		{data: []byte{0x43, 0x01, 0x02, 0x03}, want: []byte{1, 2, 3}},
+		{data: []byte{0x64, 'n', 'o', 'n', 'e'}, want: "none"},
+		{data: []byte{0x82, 0x01, 0xf5}, want: []any{int64(1), true}},
+		{data: []byte{0xa2, 0x01, 0x02, 0x61, 'a', 0xf6}, want: map[any]any{int64(1): int64(2), "a": nil}},
+	}
+	a := require.New(t)
+	for _, test := range tests {
+		got, n, err := decodeCBOR(test.data)
+		a.NoError(err)
+		a.Equal(len(test.data), n)
+		a.Equal(test.want, got)
+	}
+
+	for _, data := range [][]byte{
+		{},
+		{0x43, 0x01},
+		{0x9b, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+		{0x5f},
+	} {
+		_, _, err := decodeCBOR(data)
+		a.Error(err)
+	}
+}
+
+func TestRegistrationAndAssertion(t *testing.T) {
+	a := require.New(t)
+	origin := "https://bytebase.example.com"
+	rp, err := NewRelyingParty("bytebase.example.com", "Bytebase", []string{origin})
+	a.NoError(err)
+	authenticator := newTestAuthenticator(t, rp.ID)
+
+	challenge, err := NewChallenge()
+	a.NoError(err)
+	response := authenticator.create(t, origin, challenge)
+	got, err := response.Challenge()
+	a.NoError(err)
+	a.Equal(challenge, got)
+	credential, err := rp.VerifyRegistration(challenge, response)
+	a.NoError(err)
+	a.Equal(authenticator.credentialID, credential.ID)
+	a.False(credential.BackupEligible)
+	a.True(credential.UserVerified)
+
+	// The response must be signed for the expected challenge.
+	otherChallenge, err := NewChallenge()
+	a.NoError(err)
+	_, err = rp.VerifyRegistration(otherChallenge, response)
+	a.Error(err)
+	_, err = rp.VerifyRegistration("", response)
+	a.Error(err)
+
+	challenge, err = NewChallenge()
+	a.NoError(err)
+	result, err := rp.VerifyAssertion(challenge, credential, authenticator.get(t, origin, challenge, 1))
+	a.NoError(err)
+	a.Equal(uint32(1), result.SignCount)
+	credential.SignCount = result.SignCount
+
+	// The signature counter must increase.
+	_, err = rp.VerifyAssertion(challenge, credential, authenticator.get(t, origin, challenge, 1))
+	a.Error(err)
+
+	// The origin must be allowed.
+	_, err = rp.VerifyAssertion(challenge, credential, authenticator.get(t, "https://evil.example.com", challenge, 2))
+	a.Error(err)
+
+	// The signature must be signed by the credential.
+	response = authenticator.get(t, origin, challenge, 2)
+	response.Response.Signature = base64.RawURLEncoding.EncodeToString([]byte("invalid"))
+	_, err = rp.VerifyAssertion(challenge, credential, response)
+	a.Error(err)
+}
+
+type testAuthenticator struct {
+	rpID         string
+	credentialID []byte
+	key          *ecdsa.PrivateKey
+}
+
+func newTestAuthenticator(t *testing.T, rpID string) *testAuthenticator {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	return &testAuthenticator{
+		rpID:         rpID,
+		credentialID: []byte("test-credential"),
+		key:          key,
+	}
+}
+
+func (a *testAuthenticator) create(t *testing.T, origin, challenge string) *CredentialResponse {
+	return a.createWithAttestation(t, origin, challenge, make([]byte, 16), nil)
+}
+
+// createWithAttestation returns the registration response with the attestation statement returned by attest,
+// which is the CBOR encoded attStmt of the format. The "none" attestation is used if attest is nil.
+func (a *testAuthenticator) createWithAttestation(t *testing.T, origin, challenge string, aaguid []byte, attest func(authData, clientDataHash []byte) (string, []byte)) *CredentialResponse {
+	x := make([]byte, 32)
+	y := make([]byte, 32)
+	a.key.X.FillBytes(x)
+	a.key.Y.FillBytes(y)
+	// The COSE key {1: 2, 3: -7, -1: 1, -2: x, -3: y}.
+	publicKey := []byte{0xa5, 0x01, 0x02, 0x03, 0x26, 0x20, 0x01, 0x21, 0x58, 0x20}
+	publicKey = append(publicKey, x...)
+	publicKey = append(publicKey, 0x22, 0x58, 0x20)
+	publicKey = append(publicKey, y...)
+
+	authData := a.authData(flagUserPresent|flagUserVerified|flagAttestedData, 0)
+	authData = append(authData, aaguid...)
+	authData = binary.BigEndian.AppendUint16(authData, uint16(len(a.credentialID)))
+	authData = append(authData, a.credentialID...)
+	authData = append(authData, publicKey...)
+
+	clientDataJSON := a.clientData(t, clientDataTypeCreate, origin, challenge)
+	rawClientData, err := base64.RawURLEncoding.DecodeString(clientDataJSON)
+	require.NoError(t, err)
+	clientDataHash := sha256.Sum256(rawClientData)
+	format, statement := "none", []byte{0xa0}
+	if attest != nil {
+		format, statement = attest(authData, clientDataHash[:])
+	}
+
+	// The attestation object {"fmt": format, "attStmt": statement, "authData": authData}.
+	attestationObject := []byte{0xa3}
+	attestationObject = append(attestationObject, encodeTestCBOR(3, []byte("fmt"))...)
+	attestationObject = append(attestationObject, encodeTestCBOR(3, []byte(format))...)
+	attestationObject = append(attestationObject, encodeTestCBOR(3, []byte("attStmt"))...)
+	attestationObject = append(attestationObject, statement...)
+	attestationObject = append(attestationObject, encodeTestCBOR(3, []byte("authData"))...)
+	attestationObject = append(attestationObject, encodeTestCBOR(2, authData)...)
+
+	response := &CredentialResponse{
+		ID:    base64.RawURLEncoding.EncodeToString(a.credentialID),
+		RawID: base64.RawURLEncoding.EncodeToString(a.credentialID),
+		Type:  credentialTypePublicKey,
+	}
+	response.Response.ClientDataJSON = clientDataJSON
+	response.Response.AttestationObject = base64.RawURLEncoding.EncodeToString(attestationObject)
+	return response
+}
+
+func (a *testAuthenticator) get(t *testing.T, origin, challenge string, signCount uint32) *CredentialResponse {
+	authData := a.authData(flagUserPresent|flagUserVerified, signCount)
+	clientDataJSON := a.clientData(t, clientDataTypeGet, origin, challenge)
+	rawClientData, err := base64.RawURLEncoding.DecodeString(clientDataJSON)
+	require.NoError(t, err)
+	clientDataHash := sha256.Sum256(rawClientData)
+	digest := sha256.Sum256(append(authData, clientDataHash[:]...))
+	signature, err := ecdsa.SignASN1(rand.Reader, a.key, digest[:])
+	require.NoError(t, err)
+
+	response := &CredentialResponse{
+		ID:    base64.RawURLEncoding.EncodeToString(a.credentialID),
+		RawID: base64.RawURLEncoding.EncodeToString(a.credentialID),
+		Type:  credentialTypePublicKey,
+	}
+	response.Response.ClientDataJSON = clientDataJSON
+	response.Response.AuthenticatorData = base64.RawURLEncoding.EncodeToString(authData)
+	response.Response.Signature = base64.RawURLEncoding.EncodeToString(signature)
+	return response
+}
+
+func (a *testAuthenticator) authData(flags byte, signCount uint32) []byte {
+	rpIDHash := sha256.Sum256([]byte(a.rpID))
+	authData := append([]byte{}, rpIDHash[:]...)
+	authData = append(authData, flags)
+	return binary.BigEndian.AppendUint32(authData, signCount)
+}
+
+func (*testAuthenticator) clientData(t *testing.T, clientDataType, origin, challenge string) string {
+	b, err := json.Marshal(&clientData{Type: clientDataType, Challenge: challenge, Origin: origin})
+	require.NoError(t, err)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// encodeTestCBOR encodes the byte string (major type 2) or the text string (major type 3).
+func encodeTestCBOR(major byte, b []byte) []byte {
+	var data []byte
+	switch {
+	case len(b) < 24:
+		data = []byte{major<<5 | byte(len(b))}
+	case len(b) < 256:
+		data = []byte{major<<5 | 24, byte(len(b))}
+	default:
+		data = binary.BigEndian.AppendUint16([]byte{major<<5 | 25}, uint16(len(b)))
+	}
+	return append(data, b...)
+}
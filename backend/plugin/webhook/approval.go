@@ -0,0 +1,86 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// ApprovalActionType is the type of the interactive approval action.
+type ApprovalActionType string
+
+const (
+	// ApprovalActionApprove approves the pending approval step.
+	ApprovalActionApprove ApprovalActionType = "APPROVE"
+	// ApprovalActionReject rejects the pending approval step.
+	ApprovalActionReject ApprovalActionType = "REJECT"
+)
+
+// ApprovalAction is the Approve/Reject action of the pending approval step in the Slack and Teams messages.
+type ApprovalAction struct {
+	IssueID int
+	// Step is the number of the issue approvers when the approval is requested.
+	// The actions of the outdated messages are rejected by comparing it with the current number of approvers.
+	Step int
+	// Slack is true if the interactive approval is enabled for Slack.
+	Slack bool
+	// TeamsURL is the callback URL of the Teams message card actions, it is empty if the interactive approval is not enabled for Teams.
+	TeamsURL string
+	// TeamsSignature is the signature of the Teams actions, signed with the Teams token.
+	TeamsSignature string
+}
+
+// ApprovalActionValue is the value sent back by the Approve/Reject buttons.
+type ApprovalActionValue struct {
+	Action  ApprovalActionType `json:"action"`
+	IssueID int                `json:"issueId"`
+	Step    int                `json:"step"`
+	// Signature is only present in the Teams actions, the Slack requests are signed by Slack.
+	Signature string `json:"signature,omitempty"`
+}
+
+// SignApprovalAction returns the signature of the approval action of the issue approval step.
+func SignApprovalAction(token string, issueID int, step int) string {
+	mac := hmac.New(sha256.New, []byte(token))
+	mac.Write([]byte(fmt.Sprintf("%d:%d", issueID, step)))
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// verify verifies the signature of the approval action value.
+func (v *ApprovalActionValue) verify(token string) error {
+	if token == "" {
+		return errors.New("token is not configured")
+	}
+	if !hmac.Equal([]byte(v.Signature), []byte(SignApprovalAction(token, v.IssueID, v.Step))) {
+		return errors.New("signature mismatch")
+	}
+	return nil
+}
+
+func (a *ApprovalAction) value(action ApprovalActionType, signature string) (string, error) {
+	b, err := json.Marshal(&ApprovalActionValue{
+		Action:    action,
+		IssueID:   a.IssueID,
+		Step:      a.Step,
+		Signature: signature,
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to marshal approval action value")
+	}
+	return string(b), nil
+}
+
+func parseApprovalActionValue(value string) (*ApprovalActionValue, error) {
+	v := &ApprovalActionValue{}
+	if err := json.Unmarshal([]byte(value), v); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal approval action value")
+	}
+	if v.Action != ApprovalActionApprove && v.Action != ApprovalActionReject {
+		return nil, errors.Errorf("invalid approval action %q", v.Action)
+	}
+	return v, nil
+}
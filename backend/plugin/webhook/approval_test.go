@@ -0,0 +1,192 @@
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlackReceiverApprovalButtons(t *testing.T) {
+	a := require.New(t)
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	err := Post("bb.plugin.webhook.slack", Context{
+		URL:      server.URL,
+		Title:    "Issue approval needed",
+		Approval: &Approval{Action: &ApprovalAction{IssueID: 101, Step: 1, Slack: true}},
+	})
+	a.NoError(err)
+	post := SlackWebhook{}
+	a.NoError(json.Unmarshal(body, &post))
+	block := post.BlockList[len(post.BlockList)-1]
+	a.Equal(slackApprovalBlockID, block.BlockID)
+	a.Len(block.ElementList, 2)
+	value, err := parseApprovalActionValue(block.ElementList[1].Value)
+	a.NoError(err)
+	a.Equal(&ApprovalActionValue{Action: ApprovalActionReject, IssueID: 101, Step: 1}, value)
+
+	// The buttons are not posted if the Slack approval is not enabled.
+	err = Post("bb.plugin.webhook.slack", Context{
+		URL:      server.URL,
+		Approval: &Approval{Action: &ApprovalAction{IssueID: 101, TeamsURL: "https://bytebase.example.com/hook/im-approval/teams"}},
+	})
+	a.NoError(err)
+	a.NotContains(string(body), slackApprovalBlockID)
+}
+
+func signSlackRequest(secret string, timestamp string, body []byte) http.Header {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(fmt.Sprintf("v0:%s:%s", timestamp, body)))
+	header := http.Header{}
+	header.Set(slackTimestampHeader, timestamp)
+	header.Set(slackSignatureHeader, "v0="+hex.EncodeToString(mac.Sum(nil)))
+	return header
+}
+
+func TestParseSlackInteraction(t *testing.T) {
+	a := require.New(t)
+	now := time.Unix(1700000000, 0)
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	value := `{"action":"APPROVE","issueId":101,"step":0}`
+	payload := fmt.Sprintf(`{"type":"block_actions","user":{"id":"U1"},"response_url":"https://hooks.slack.com/actions/1","actions":[{"action_id":"bb_approve","block_id":"bb_approval","value":%q}]}`, value)
+	body := []byte("payload=" + url.QueryEscape(payload))
+
+	interaction, action, err := ParseSlackInteraction("secret", signSlackRequest("secret", timestamp, body), body, now)
+	a.NoError(err)
+	a.Equal("U1", interaction.User.ID)
+	a.Equal(&ApprovalActionValue{Action: ApprovalActionApprove, IssueID: 101}, action)
+
+	_, _, err = ParseSlackInteraction("another secret", signSlackRequest("secret", timestamp, body), body, now)
+	a.ErrorContains(err, "signature mismatch")
+	_, _, err = ParseSlackInteraction("secret", signSlackRequest("secret", timestamp, body), body, now.Add(10*time.Minute))
+	a.ErrorContains(err, "out of range")
+
+	// The other buttons have no approval action.
+	payload = `{"type":"block_actions","user":{"id":"U1"},"actions":[{"action_id":"view","block_id":"x"}]}`
+	body = []byte("payload=" + url.QueryEscape(payload))
+	_, action, err = ParseSlackInteraction("secret", signSlackRequest("secret", timestamp, body), body, now)
+	a.NoError(err)
+	a.Nil(action)
+}
+
+func TestUpdateSlackApprovalMessage(t *testing.T) {
+	a := require.New(t)
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	err := UpdateSlackApprovalMessage(&SlackInteraction{
+		ResponseURL: server.URL,
+		Message: SlackInteractionMessage{
+			Text: "Issue approval needed",
+			Blocks: []json.RawMessage{
+				json.RawMessage(`{"type":"section","block_id":"a","text":{"type":"mrkdwn","text":"title"}}`),
+				json.RawMessage(`{"type":"actions","block_id":"bb_approval","elements":[]}`),
+			},
+		},
+	}, "Approved")
+	a.NoError(err)
+	got := struct {
+		ReplaceOriginal bool                `json:"replace_original"`
+		Blocks          []SlackWebhookBlock `json:"blocks"`
+	}{}
+	a.NoError(json.Unmarshal(body, &got))
+	a.True(got.ReplaceOriginal)
+	a.Len(got.Blocks, 2)
+	a.Equal("a", got.Blocks[0].BlockID)
+	a.Equal("Approved", got.Blocks[1].Text.Text)
+}
+
+func TestGetSlackUserEmail(t *testing.T) {
+	a := require.New(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer xoxb-token" {
+			_, _ = w.Write([]byte(`{"ok":false,"error":"invalid_auth"}`))
+			return
+		}
+		_, _ = w.Write([]byte(fmt.Sprintf(`{"ok":true,"user":{"id":%q,"profile":{"email":"alice@example.com"}}}`, r.URL.Query().Get("user"))))
+	}))
+	defer server.Close()
+	originalURL := slackAPIURL
+	slackAPIURL = server.URL
+	defer func() { slackAPIURL = originalURL }()
+
+	email, err := GetSlackUserEmail("xoxb-token", "U1")
+	a.NoError(err)
+	a.Equal("alice@example.com", email)
+	_, err = GetSlackUserEmail("another-token", "U1")
+	a.ErrorContains(err, "invalid_auth")
+}
+
+// fakeKeySet trusts the signatures of all tokens.
+type fakeKeySet struct{}
+
+func (fakeKeySet) VerifySignature(_ context.Context, jwt string) ([]byte, error) {
+	parts := strings.Split(jwt, ".")
+	return base64.RawURLEncoding.DecodeString(parts[1])
+}
+
+func fakeTeamsToken(audience string, subject string) string {
+	encode := func(s string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(s))
+	}
+	claims := fmt.Sprintf(`{"iss":%q,"aud":%q,"sub":%q,"exp":%d}`, teamsActionIssuer, audience, subject, time.Now().Add(time.Hour).Unix())
+	return encode(`{"alg":"RS256","typ":"JWT"}`) + "." + encode(claims) + "." + encode("signature")
+}
+
+func TestParseTeamsAction(t *testing.T) {
+	a := require.New(t)
+	ctx := context.Background()
+	action := &ApprovalAction{
+		IssueID:        101,
+		Step:           2,
+		TeamsURL:       "https://bytebase.example.com/hook/im-approval/teams",
+		TeamsSignature: SignApprovalAction("token", 101, 2),
+	}
+	actionList, err := getTeamsApprovalActionList(action)
+	a.NoError(err)
+	a.Len(actionList, 2)
+	body := []byte(actionList[0].Body)
+
+	header := http.Header{}
+	header.Set("Authorization", "Bearer "+fakeTeamsToken("https://bytebase.example.com", "alice@example.com"))
+	email, value, err := ParseTeamsAction(ctx, fakeKeySet{}, "token", "https://bytebase.example.com", header, body)
+	a.NoError(err)
+	a.Equal("alice@example.com", email)
+	a.Equal(ApprovalActionApprove, value.Action)
+	a.Equal(101, value.IssueID)
+	a.Equal(2, value.Step)
+
+	_, _, err = ParseTeamsAction(ctx, fakeKeySet{}, "another token", "https://bytebase.example.com", header, body)
+	a.ErrorContains(err, "signature mismatch")
+	_, _, err = ParseTeamsAction(ctx, fakeKeySet{}, "token", "https://another.example.com", header, body)
+	a.ErrorContains(err, "failed to verify bearer token")
+	// The signature is bound to the issue and the approval step.
+	tampered := []byte(strings.Replace(string(body), `"issueId":101`, `"issueId":102`, 1))
+	_, _, err = ParseTeamsAction(ctx, fakeKeySet{}, "token", "https://bytebase.example.com", header, tampered)
+	a.ErrorContains(err, "signature mismatch")
+	_, _, err = ParseTeamsAction(ctx, fakeKeySet{}, "token", "https://bytebase.example.com", http.Header{}, body)
+	a.ErrorContains(err, "bearer token not found")
+}
@@ -5,8 +5,12 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/backend/common/signature"
 )
 
 // CustomWebhookResponse is the API message for Custom webhook response.
@@ -15,8 +19,16 @@ type CustomWebhookResponse struct {
 	Message string `json:"message"`
 }
 
+// CustomWebhookVersion is the version of the custom webhook request envelope.
+// The fields are only added within the version, receivers should ignore the unknown fields.
+const CustomWebhookVersion = "v1"
+
 // CustomWebhookRequest is the API message for Custom webhook request.
+// The activity_type is the event type, and the objects of the event are present according to it,
+// e.g. the anomaly, instance and database for bb.anomaly.create.
 type CustomWebhookRequest struct {
+	Version      string   `json:"version"`
+	DeliveryID   string   `json:"delivery_id"`
 	Level        Level    `json:"level"`
 	ActivityType string   `json:"activity_type"`
 	Title        string   `json:"title"`
@@ -27,6 +39,13 @@ type CustomWebhookRequest struct {
 	CreatedTS    int64    `json:"created_ts"`
 	Issue        *Issue   `json:"issue"`
 	Project      *Project `json:"project"`
+
+	Instance  *Instance  `json:"instance,omitempty"`
+	Database  *Database  `json:"database,omitempty"`
+	Anomaly   *Anomaly   `json:"anomaly,omitempty"`
+	Backup    *Backup    `json:"backup,omitempty"`
+	SQLExport *SQLExport `json:"sql_export,omitempty"`
+	Member    *Member    `json:"member,omitempty"`
 }
 
 func init() {
@@ -39,6 +58,8 @@ type CustomReceiver struct{}
 func (*CustomReceiver) post(context Context) error {
 	// TODO(p0ny): handle context.Task
 	payload := CustomWebhookRequest{
+		Version:      CustomWebhookVersion,
+		DeliveryID:   context.DeliveryID,
 		Level:        context.Level,
 		ActivityType: context.ActivityType,
 		Title:        context.Title,
@@ -49,6 +70,12 @@ func (*CustomReceiver) post(context Context) error {
 		CreatedTS:    context.CreatedTs,
 		Issue:        context.Issue,
 		Project:      context.Project,
+		Instance:     context.Instance,
+		Database:     context.Database,
+		Anomaly:      context.Anomaly,
+		Backup:       context.Backup,
+		SQLExport:    context.SQLExport,
+		Member:       context.Member,
 	}
 
 	body, err := json.Marshal(&payload)
@@ -62,6 +89,14 @@ func (*CustomReceiver) post(context Context) error {
 	}
 
 	req.Header.Set("Content-Type", "application/json")
+	if context.DeliveryID != "" {
+		req.Header.Set(DeliveryHeader, context.DeliveryID)
+	}
+	if context.SigningSecret != "" {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set(TimestampHeader, timestamp)
+		req.Header.Set(SignatureHeader, signature.Sign(context.SigningSecret, timestamp, body))
+	}
 	client := &http.Client{
 		Timeout: timeout,
 	}
@@ -77,7 +112,7 @@ func (*CustomReceiver) post(context Context) error {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return errors.Errorf("failed to POST webhook %s, status code: %d, response body: %s", context.URL, resp.StatusCode, b)
+		return &ResponseError{URL: context.URL, StatusCode: resp.StatusCode, Body: b}
 	}
 
 	webhookResponse := &CustomWebhookResponse{}
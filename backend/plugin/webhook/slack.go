@@ -24,14 +24,18 @@ type SlackWebhookElementButton struct {
 
 // SlackWebhookElement is the API message for Slack webhook element.
 type SlackWebhookElement struct {
-	Type   string                    `json:"type"`
-	Button SlackWebhookElementButton `json:"text,omitempty"`
-	URL    string                    `json:"url,omitempty"`
+	Type     string                    `json:"type"`
+	Button   SlackWebhookElementButton `json:"text,omitempty"`
+	URL      string                    `json:"url,omitempty"`
+	Style    string                    `json:"style,omitempty"`
+	ActionID string                    `json:"action_id,omitempty"`
+	Value    string                    `json:"value,omitempty"`
 }
 
 // SlackWebhookBlock is the API message for Slack webhook block.
 type SlackWebhookBlock struct {
 	Type        string                     `json:"type"`
+	BlockID     string                     `json:"block_id,omitempty"`
 	Text        *SlackWebhookBlockMarkdown `json:"text,omitempty"`
 	ElementList []SlackWebhookElement      `json:"elements,omitempty"`
 }
@@ -112,6 +116,14 @@ func (*SlackReceiver) post(context Context) error {
 		},
 	})
 
+	if context.Approval != nil && context.Approval.Action != nil && context.Approval.Action.Slack {
+		block, err := getSlackApprovalBlock(context.Approval.Action)
+		if err != nil {
+			return err
+		}
+		blockList = append(blockList, *block)
+	}
+
 	post := SlackWebhook{
 		Text:      context.Title,
 		BlockList: blockList,
@@ -142,7 +154,7 @@ func (*SlackReceiver) post(context Context) error {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return errors.Errorf("failed to POST webhook to %s, status code: %d, response body: %s", context.URL, resp.StatusCode, b)
+		return &ResponseError{URL: context.URL, StatusCode: resp.StatusCode, Body: b}
 	}
 
 	if string(b) != "ok" {
@@ -0,0 +1,251 @@
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// slackApprovalBlockID is the block ID of the Approve/Reject buttons, the block is replaced by the outcome after the action.
+	slackApprovalBlockID = "bb_approval"
+	slackSignatureHeader = "X-Slack-Signature"
+	slackTimestampHeader = "X-Slack-Request-Timestamp"
+	// slackRequestMaxAge is the max age of the Slack requests, the older requests are rejected to prevent replay attacks.
+	slackRequestMaxAge = 5 * time.Minute
+)
+
+// slackAPIURL is the base URL of the Slack Web API.
+var slackAPIURL = "https://slack.com/api"
+
+// SlackInteractionUser is the Slack user who takes the action.
+type SlackInteractionUser struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+}
+
+// SlackInteractionAction is the action of the Slack interaction.
+type SlackInteractionAction struct {
+	ActionID string `json:"action_id"`
+	BlockID  string `json:"block_id"`
+	Value    string `json:"value"`
+}
+
+// SlackInteractionMessage is the message containing the buttons.
+type SlackInteractionMessage struct {
+	Text   string            `json:"text"`
+	Blocks []json.RawMessage `json:"blocks"`
+}
+
+// SlackInteraction is the payload of the Slack block actions interaction.
+type SlackInteraction struct {
+	Type        string                   `json:"type"`
+	User        SlackInteractionUser     `json:"user"`
+	Actions     []SlackInteractionAction `json:"actions"`
+	ResponseURL string                   `json:"response_url"`
+	Message     SlackInteractionMessage  `json:"message"`
+}
+
+func getSlackApprovalBlock(action *ApprovalAction) (*SlackWebhookBlock, error) {
+	approve, err := action.value(ApprovalActionApprove, "")
+	if err != nil {
+		return nil, err
+	}
+	reject, err := action.value(ApprovalActionReject, "")
+	if err != nil {
+		return nil, err
+	}
+	return &SlackWebhookBlock{
+		Type:    "actions",
+		BlockID: slackApprovalBlockID,
+		ElementList: []SlackWebhookElement{
+			{
+				Type: "button",
+				Button: SlackWebhookElementButton{
+					Type: "plain_text",
+					Text: "Approve",
+				},
+				Style:    "primary",
+				ActionID: "bb_approve",
+				Value:    approve,
+			},
+			{
+				Type: "button",
+				Button: SlackWebhookElementButton{
+					Type: "plain_text",
+					Text: "Reject",
+				},
+				Style:    "danger",
+				ActionID: "bb_reject",
+				Value:    reject,
+			},
+		},
+	}, nil
+}
+
+// ParseSlackInteraction verifies the signature of the Slack interaction request, and parses the approval action.
+// The approval action is nil if the interaction is not from the Approve/Reject buttons, e.g. the "View in Bytebase" button.
+func ParseSlackInteraction(signingSecret string, header http.Header, body []byte, now time.Time) (*SlackInteraction, *ApprovalActionValue, error) {
+	if signingSecret == "" {
+		return nil, nil, errors.New("signing secret is not configured")
+	}
+	timestamp := header.Get(slackTimestampHeader)
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return nil, nil, errors.Errorf("invalid request timestamp %q", timestamp)
+	}
+	if age := now.Sub(time.Unix(ts, 0)); age > slackRequestMaxAge || age < -slackRequestMaxAge {
+		return nil, nil, errors.Errorf("request timestamp %q is out of range", timestamp)
+	}
+	mac := hmac.New(sha256.New, []byte(signingSecret))
+	fmt.Fprintf(mac, "v0:%s:", timestamp)
+	mac.Write(body)
+	signature := "v0=" + hex.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(signature), []byte(header.Get(slackSignatureHeader))) {
+		return nil, nil, errors.New("signature mismatch")
+	}
+
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to parse interaction request")
+	}
+	interaction := &SlackInteraction{}
+	if err := json.Unmarshal([]byte(form.Get("payload")), interaction); err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to unmarshal interaction payload")
+	}
+	if interaction.Type != "block_actions" {
+		return interaction, nil, nil
+	}
+	for _, action := range interaction.Actions {
+		if action.BlockID != slackApprovalBlockID {
+			continue
+		}
+		value, err := parseApprovalActionValue(action.Value)
+		if err != nil {
+			return nil, nil, err
+		}
+		return interaction, value, nil
+	}
+	return interaction, nil, nil
+}
+
+// GetSlackUserEmail returns the email of the Slack user with the bot token.
+func GetSlackUserEmail(botToken string, userID string) (string, error) {
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s/users.info?user=%s", slackAPIURL, url.QueryEscape(userID)), nil)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to construct Slack users.info request")
+	}
+	req.Header.Set("Authorization", "Bearer "+botToken)
+	client := &http.Client{
+		Timeout: timeout,
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get Slack user %s", userID)
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to read Slack users.info response")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", &ResponseError{URL: req.URL.String(), StatusCode: resp.StatusCode, Body: b}
+	}
+
+	var result struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+		User  struct {
+			Deleted bool `json:"deleted"`
+			Profile struct {
+				Email string `json:"email"`
+			} `json:"profile"`
+		} `json:"user"`
+	}
+	if err := json.Unmarshal(b, &result); err != nil {
+		return "", errors.Wrapf(err, "failed to unmarshal Slack users.info response")
+	}
+	if !result.OK {
+		return "", errors.Errorf("failed to get Slack user %s, error: %s", userID, result.Error)
+	}
+	if result.User.Deleted || result.User.Profile.Email == "" {
+		return "", errors.Errorf("email of Slack user %s not found", userID)
+	}
+	return result.User.Profile.Email, nil
+}
+
+// UpdateSlackApprovalMessage replaces the Approve/Reject buttons of the original message with the outcome.
+func UpdateSlackApprovalMessage(interaction *SlackInteraction, outcome string) error {
+	var blocks []any
+	for _, block := range interaction.Message.Blocks {
+		var b struct {
+			BlockID string `json:"block_id"`
+		}
+		if err := json.Unmarshal(block, &b); err != nil {
+			return errors.Wrapf(err, "failed to unmarshal message block")
+		}
+		if b.BlockID == slackApprovalBlockID {
+			continue
+		}
+		blocks = append(blocks, block)
+	}
+	blocks = append(blocks, SlackWebhookBlock{
+		Type: "section",
+		Text: &SlackWebhookBlockMarkdown{
+			Type: "mrkdwn",
+			Text: outcome,
+		},
+	})
+	return postSlackResponse(interaction.ResponseURL, map[string]any{
+		"replace_original": true,
+		"text":             interaction.Message.Text,
+		"blocks":           blocks,
+	})
+}
+
+// ReplySlackInteraction replies to the user who takes the action, the message is only visible to the user.
+func ReplySlackInteraction(interaction *SlackInteraction, text string) error {
+	return postSlackResponse(interaction.ResponseURL, map[string]any{
+		"response_type":    "ephemeral",
+		"replace_original": false,
+		"text":             text,
+	})
+}
+
+func postSlackResponse(responseURL string, v any) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal Slack response")
+	}
+	req, err := http.NewRequest("POST", responseURL, bytes.NewBuffer(body))
+	if err != nil {
+		return errors.Wrapf(err, "failed to construct Slack response request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	client := &http.Client{
+		Timeout: timeout,
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "failed to POST Slack response")
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read Slack response")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return &ResponseError{URL: responseURL, StatusCode: resp.StatusCode, Body: b}
+	}
+	return nil
+}
@@ -22,7 +22,11 @@ type TeamsWebhookActionTarget struct {
 type TeamsWebhookAction struct {
 	Type       string                     `json:"@type"`
 	Name       string                     `json:"name"`
-	TargetList []TeamsWebhookActionTarget `json:"targets"`
+	TargetList []TeamsWebhookActionTarget `json:"targets,omitempty"`
+	// Target, Body and BodyContentType are the fields of the HttpPOST actions.
+	Target          string `json:"target,omitempty"`
+	Body            string `json:"body,omitempty"`
+	BodyContentType string `json:"bodyContentType,omitempty"`
 }
 
 // TeamsWebhookSectionFact is the API message for Teams webhook section fact.
@@ -90,6 +94,13 @@ func (*TeamsReceiver) post(context Context) error {
 			},
 		},
 	}
+	if context.Approval != nil && context.Approval.Action != nil && context.Approval.Action.TeamsURL != "" {
+		actionList, err := getTeamsApprovalActionList(context.Approval.Action)
+		if err != nil {
+			return err
+		}
+		post.ActionList = append(post.ActionList, actionList...)
+	}
 	body, err := json.Marshal(post)
 	if err != nil {
 		return errors.Wrapf(err, "failed to marshal webhook POST request to %s", context.URL)
@@ -116,7 +127,7 @@ func (*TeamsReceiver) post(context Context) error {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return errors.Errorf("failed to POST webhook %s, status code: %d, response body: %s", context.URL, resp.StatusCode, b)
+		return &ResponseError{URL: context.URL, StatusCode: resp.StatusCode, Body: b}
 	}
 
 	if string(b) != "1" {
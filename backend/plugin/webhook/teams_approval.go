@@ -0,0 +1,116 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/coreos/go-oidc"
+	"github.com/pkg/errors"
+)
+
+const (
+	// teamsActionIssuer is the issuer of the bearer tokens of the Teams actionable message requests.
+	teamsActionIssuer = "https://substrate.office.com/sts/"
+	// TeamsActionKeysURL is the URL of the keys signing the bearer tokens of the Teams actionable message requests.
+	TeamsActionKeysURL = "https://substrate.office.com/sts/common/discovery/keys"
+	// TeamsCardUpdateHeader is the response header to replace the original card with the card in the response body.
+	TeamsCardUpdateHeader = "CARD-UPDATE-IN-BODY"
+	// TeamsActionStatusHeader is the response header of the status message displayed to the user who takes the action.
+	TeamsActionStatusHeader = "CARD-ACTION-STATUS"
+)
+
+func getTeamsApprovalActionList(action *ApprovalAction) ([]TeamsWebhookAction, error) {
+	approve, err := action.value(ApprovalActionApprove, action.TeamsSignature)
+	if err != nil {
+		return nil, err
+	}
+	reject, err := action.value(ApprovalActionReject, action.TeamsSignature)
+	if err != nil {
+		return nil, err
+	}
+	return []TeamsWebhookAction{
+		{
+			Type:            "HttpPOST",
+			Name:            "Approve",
+			Target:          action.TeamsURL,
+			Body:            approve,
+			BodyContentType: "application/json",
+		},
+		{
+			Type:            "HttpPOST",
+			Name:            "Reject",
+			Target:          action.TeamsURL,
+			Body:            reject,
+			BodyContentType: "application/json",
+		},
+	}, nil
+}
+
+// ParseTeamsAction verifies the bearer token and the signature of the Teams action request, and parses the approval action.
+// It returns the email of the user who takes the action.
+// The bearer token is issued by Microsoft for the target URL, and the action is signed with the Teams token when the card is posted.
+func ParseTeamsAction(ctx context.Context, keySet oidc.KeySet, token string, externalURL string, header http.Header, body []byte) (string, *ApprovalActionValue, error) {
+	bearer, ok := strings.CutPrefix(header.Get("Authorization"), "Bearer ")
+	if !ok || bearer == "" {
+		return "", nil, errors.New("bearer token not found")
+	}
+	u, err := url.Parse(externalURL)
+	if err != nil {
+		return "", nil, errors.Wrapf(err, "failed to parse external URL %q", externalURL)
+	}
+	// The audience of the bearer token is the base URL of the target URL, which is under the external URL.
+	verifier := oidc.NewVerifier(teamsActionIssuer, keySet, &oidc.Config{ClientID: u.Scheme + "://" + u.Host})
+	idToken, err := verifier.Verify(ctx, bearer)
+	if err != nil {
+		return "", nil, errors.Wrapf(err, "failed to verify bearer token")
+	}
+	// The subject is the email of the user who takes the action.
+	if idToken.Subject == "" {
+		return "", nil, errors.New("subject of bearer token is empty")
+	}
+
+	value, err := parseApprovalActionValue(string(body))
+	if err != nil {
+		return "", nil, err
+	}
+	if err := value.verify(token); err != nil {
+		return "", nil, errors.Wrapf(err, "failed to verify approval action")
+	}
+	return idToken.Subject, value, nil
+}
+
+// GetTeamsApprovalOutcomeCard returns the card replacing the original card with the Approve/Reject buttons.
+func GetTeamsApprovalOutcomeCard(title string, outcome string, link string) ([]byte, error) {
+	card := TeamsWebhook{
+		Type:       "MessageCard",
+		Context:    "https://schema.org/extensions",
+		Summary:    title,
+		ThemeColor: themeColor,
+		Title:      title,
+		SectionList: []TeamsWebhookSection{
+			{
+				Text: outcome,
+			},
+		},
+		ActionList: []TeamsWebhookAction{
+			{
+				Type: "OpenUri",
+				Name: "View in Bytebase",
+				TargetList: []TeamsWebhookActionTarget{
+					{
+						OS:  "default",
+						URI: link,
+					},
+				},
+			},
+		},
+	}
+	b, err := json.Marshal(card)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal Teams card")
+	}
+	return b, nil
+}
@@ -2,19 +2,32 @@
 package webhook
 
 import (
+	"fmt"
 	"sync"
 	"time"
 
 	"github.com/pkg/errors"
 
 	"github.com/bytebase/bytebase/backend/common"
+	"github.com/bytebase/bytebase/backend/common/signature"
 )
 
 var (
 	receiverMu sync.RWMutex
 	receivers  = make(map[string]Receiver)
-	// Based on the local test, Teams sometimes cannot finish the request in 1 second, so use 3s.
-	timeout = 3 * time.Second
+	// The webhooks are posted by the delivery runner in the background and retried on failure,
+	// so the timeout is generous for the slow receivers.
+	timeout = 10 * time.Second
+)
+
+const (
+	// SignatureHeader is the header of the HMAC-SHA256 signature of the request body signed at the timestamp.
+	// The custom webhooks are signed the same as the external approval requests, see signature.Sign.
+	SignatureHeader = signature.Header
+	// TimestampHeader is the header of the unix timestamp when the request body is signed.
+	TimestampHeader = signature.TimestampHeader
+	// DeliveryHeader is the header of the delivery ID, the redeliveries have the same ID.
+	DeliveryHeader = "X-Bytebase-Delivery"
 )
 
 // meta is the webhook metadata.
@@ -65,6 +78,53 @@ type Project struct {
 // Approval object of issue approval.
 type Approval struct {
 	MentionUsersByPhone []string
+	// Action is the Approve/Reject action of the pending approval step.
+	// It is only present if the interactive approval is enabled for Slack or Teams.
+	Action *ApprovalAction
+}
+
+// Instance object of instance.
+type Instance struct {
+	// ID is the resource ID of the instance.
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Engine      string `json:"engine"`
+	Environment string `json:"environment"`
+}
+
+// Database object of database.
+type Database struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// Anomaly object of anomaly.
+type Anomaly struct {
+	// Type is the anomaly type, e.g. bb.anomaly.database.schema.drift.
+	Type string `json:"type"`
+}
+
+// Backup object of database backup.
+type Backup struct {
+	Name  string `json:"name"`
+	Error string `json:"error"`
+}
+
+// SQLExport object of SQL export.
+type SQLExport struct {
+	Statement string `json:"statement"`
+	Format    string `json:"format"`
+}
+
+// Member object of workspace or project member.
+// The PreviousRole is only present for the role changes, and the ExpireTs is only present for the expired grants.
+type Member struct {
+	ID           int    `json:"id"`
+	Name         string `json:"name"`
+	Email        string `json:"email"`
+	Role         string `json:"role"`
+	PreviousRole string `json:"previous_role,omitempty"`
+	ExpireTs     int64  `json:"expire_ts,omitempty"`
 }
 
 // Context is the context of webhook.
@@ -79,10 +139,32 @@ type Context struct {
 	CreatorName  string
 	CreatorEmail string
 	CreatedTs    int64
-	Issue        *Issue
-	Project      *Project
-	TaskResult   *TaskResult
-	Approval     *Approval
+	// DeliveryID is the ID of the webhook delivery.
+	DeliveryID string
+	// SigningSecret is the secret for signing the request body of the custom webhooks.
+	SigningSecret string
+	Issue         *Issue
+	Project       *Project
+	TaskResult    *TaskResult
+	Approval      *Approval
+	// The objects of the events that are not issue activities, e.g. anomalies and SQL exports.
+	Instance  *Instance
+	Database  *Database
+	Anomaly   *Anomaly
+	Backup    *Backup
+	SQLExport *SQLExport
+	Member    *Member
+}
+
+// ResponseError is the error of the receiver responding with an unexpected status code.
+type ResponseError struct {
+	URL        string
+	StatusCode int
+	Body       []byte
+}
+
+func (e *ResponseError) Error() string {
+	return fmt.Sprintf("failed to POST webhook to %s, status code: %d, response body: %s", e.URL, e.StatusCode, e.Body)
 }
 
 // Receiver is the webhook receiver.
@@ -115,6 +197,31 @@ func (c *Context) getMetaList() []meta {
 		})
 	}
 
+	if c.Instance != nil {
+		m = append(m, meta{
+			Name:  "Instance",
+			Value: c.Instance.Name,
+		})
+	}
+
+	if c.Database != nil {
+		m = append(m, meta{
+			Name:  "Database",
+			Value: c.Database.Name,
+		})
+	}
+
+	if c.Member != nil {
+		m = append(m, meta{
+			Name:  "Member",
+			Value: fmt.Sprintf("%s (%s)", c.Member.Name, c.Member.Email),
+		})
+		m = append(m, meta{
+			Name:  "Role",
+			Value: c.Member.Role,
+		})
+	}
+
 	if c.TaskResult != nil {
 		m = append(m, meta{
 			Name:  "Task",
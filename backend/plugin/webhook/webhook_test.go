@@ -2,11 +2,17 @@ package webhook
 
 import (
 	"crypto/rand"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
 	"github.com/bytebase/bytebase/backend/common"
+	"github.com/bytebase/bytebase/backend/common/signature"
 )
 
 func TestContext_getMetaList(t *testing.T) {
@@ -122,3 +128,84 @@ func TestContext_getMetaList(t *testing.T) {
 		a.Equal(want, context.getMetaList())
 	})
 }
+
+func TestCustomReceiverSignature(t *testing.T) {
+	a := require.New(t)
+	var sig, timestamp, deliveryID string
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sig = r.Header.Get(SignatureHeader)
+		timestamp = r.Header.Get(TimestampHeader)
+		deliveryID = r.Header.Get(DeliveryHeader)
+		body, _ = io.ReadAll(r.Body)
+		_, _ = w.Write([]byte(`{"code":0}`))
+	}))
+	defer server.Close()
+
+	err := Post("bb.plugin.webhook.custom", Context{
+		URL:           server.URL,
+		Title:         "Issue created",
+		DeliveryID:    "101",
+		SigningSecret: "secret",
+	})
+	a.NoError(err)
+	a.Equal("101", deliveryID)
+	// The custom webhooks are verified the same as the external approval callbacks.
+	a.NoError(signature.Verify("secret", timestamp, sig, body, time.Now()))
+	a.Error(signature.Verify("other", timestamp, sig, body, time.Now()))
+
+	err = Post("bb.plugin.webhook.custom", Context{URL: server.URL})
+	a.NoError(err)
+	a.Empty(sig)
+	a.Empty(timestamp)
+}
+
+func TestCustomReceiverEnvelope(t *testing.T) {
+	a := require.New(t)
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		_, _ = w.Write([]byte(`{"code":0}`))
+	}))
+	defer server.Close()
+
+	err := Post("bb.plugin.webhook.custom", Context{
+		URL:          server.URL,
+		ActivityType: "bb.anomaly.create",
+		Title:        "Schema drift detected",
+		DeliveryID:   "101",
+		Instance:     &Instance{ID: "prod-mysql", Name: "Prod MySQL", Engine: "MYSQL", Environment: "prod"},
+		Database:     &Database{ID: 102, Name: "db"},
+		Anomaly:      &Anomaly{Type: "bb.anomaly.database.schema.drift"},
+	})
+	a.NoError(err)
+
+	got := map[string]any{}
+	a.NoError(json.Unmarshal(body, &got))
+	a.Equal(CustomWebhookVersion, got["version"])
+	a.Equal("101", got["delivery_id"])
+	a.Equal("bb.anomaly.create", got["activity_type"])
+	a.Equal(map[string]any{"type": "bb.anomaly.database.schema.drift"}, got["anomaly"])
+	a.Equal(map[string]any{"id": float64(102), "name": "db"}, got["database"])
+	a.Equal("prod-mysql", got["instance"].(map[string]any)["id"])
+	// The objects of the other events are omitted.
+	a.NotContains(got, "backup")
+	a.NotContains(got, "sql_export")
+	a.NotContains(got, "member")
+	// The issue and project are always present for compatibility.
+	a.Contains(got, "issue")
+	a.Contains(got, "project")
+}
+
+func TestResponseError(t *testing.T) {
+	a := require.New(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	err := Post("bb.plugin.webhook.custom", Context{URL: server.URL})
+	var responseErr *ResponseError
+	a.ErrorAs(err, &responseErr)
+	a.Equal(http.StatusBadGateway, responseErr.StatusCode)
+}
@@ -0,0 +1,256 @@
+// Package approvaltimeout is a runner that sends the approval reminders, and escalates or rejects the approval steps after the timeouts.
+package approvaltimeout
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/bytebase/bytebase/backend/common/log"
+	"github.com/bytebase/bytebase/backend/component/activity"
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/store"
+	"github.com/bytebase/bytebase/backend/utils"
+	storepb "github.com/bytebase/bytebase/proto/generated-go/store"
+)
+
+const (
+	approvalTimeoutCheckInterval = 1 * time.Minute
+)
+
+// NewRunner creates a new approval timeout runner.
+func NewRunner(store *store.Store, activityManager *activity.Manager) *Runner {
+	return &Runner{
+		store:           store,
+		activityManager: activityManager,
+	}
+}
+
+// Runner is the approval timeout runner.
+type Runner struct {
+	store           *store.Store
+	activityManager *activity.Manager
+}
+
+// Run will run the approval timeout runner.
+func (r *Runner) Run(ctx context.Context, wg *sync.WaitGroup) {
+	ticker := time.NewTicker(approvalTimeoutCheckInterval)
+	defer ticker.Stop()
+	defer wg.Done()
+	slog.Debug(fmt.Sprintf("Approval timeout runner started and will run every %s", approvalTimeoutCheckInterval.String()))
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Debug("Approval timeout runner received context cancellation")
+			return
+		case <-ticker.C:
+			slog.Debug("Approval timeout runner received tick")
+			r.checkAll(ctx)
+		}
+	}
+}
+
+func (r *Runner) checkAll(ctx context.Context) {
+	defer func() {
+		if r := recover(); r != nil {
+			err, ok := r.(error)
+			if !ok {
+				err = errors.Errorf("%v", r)
+			}
+			slog.Error("Approval timeout runner PANIC RECOVER", log.BBError(err), log.BBStack("panic-stack"))
+		}
+	}()
+
+	issues, err := r.store.ListIssueV2(ctx, &store.FindIssueMessage{
+		StatusList: []api.IssueStatus{api.IssueOpen},
+	})
+	if err != nil {
+		slog.Error("failed to list issues", log.BBError(err))
+		return
+	}
+	for _, issue := range issues {
+		if err := r.checkIssue(ctx, issue); err != nil {
+			slog.Error("failed to check the approval timeout", slog.Int("issue", issue.UID), log.BBError(err))
+		}
+	}
+}
+
+// approvalAction is the action to take on the pending approval step.
+type approvalAction int
+
+const (
+	approvalActionNone approvalAction = iota
+	// approvalActionStart starts tracking the new pending step.
+	approvalActionStart
+	// approvalActionReject rejects the step after the timeout without the escalation.
+	approvalActionReject
+	// approvalActionEscalate escalates the step after the timeout.
+	approvalActionEscalate
+	// approvalActionRemind reminds the approvers of the step.
+	approvalActionRemind
+)
+
+// checkIssue tracks when the pending approval step starts, sends the reminders of the step,
+// and escalates or rejects the step after the timeout.
+func (r *Runner) checkIssue(ctx context.Context, issue *store.IssueMessage) error {
+	approval := issue.Payload.GetApproval()
+	if approval == nil {
+		return nil
+	}
+	now := time.Now()
+	// The payload is shared with the issue cache, so check a copy of it before locking the issue.
+	if action, _ := checkApproval(proto.Clone(approval).(*storepb.IssuePayloadApproval), now); action == approvalActionNone {
+		return nil
+	}
+
+	// The approval may be updated after the issue is listed, e.g. approved by the approvers,
+	// so check the latest approval under the row lock.
+	var action approvalAction
+	var step *storepb.ApprovalStep
+	if _, err := r.store.UpdateIssueApproval(ctx, issue.UID, func(approval *storepb.IssuePayloadApproval) bool {
+		action, step = checkApproval(approval, now)
+		return action != approvalActionNone
+	}, api.SystemBotID); err != nil {
+		return errors.Wrapf(err, "failed to update issue approval")
+	}
+
+	switch action {
+	case approvalActionReject:
+		if err := r.createCommentActivity(ctx, issue, &storepb.ActivityIssueCommentCreatePayload_ApprovalEvent_{
+			ApprovalEvent: &storepb.ActivityIssueCommentCreatePayload_ApprovalEvent{
+				Status: storepb.ActivityIssueCommentCreatePayload_ApprovalEvent_REJECTED,
+			},
+		}, fmt.Sprintf("Rejected automatically as the approval step timed out after %s.", step.Timeout.AsDuration())); err != nil {
+			slog.Error("failed to create activity after rejecting the timed out approval step", log.BBError(err))
+		}
+	case approvalActionEscalate:
+		if err := r.createCommentActivity(ctx, issue, nil, fmt.Sprintf("Escalated to %s as the approval step timed out after %s.", step.EscalationRole, step.Timeout.AsDuration())); err != nil {
+			slog.Error("failed to create activity after escalating the timed out approval step", log.BBError(err))
+		}
+		if err := r.createApprovalNotifyActivity(ctx, issue, utils.GetEscalationStep(step)); err != nil {
+			slog.Error("failed to create approval step pending activity after escalating the approval step", log.BBError(err))
+		}
+	case approvalActionRemind:
+		if err := r.createApprovalNotifyActivity(ctx, issue, step); err != nil {
+			slog.Error("failed to create approval step reminder activity", log.BBError(err))
+		}
+	}
+	return nil
+}
+
+// checkApproval updates the approval in place with the action to take on the pending step at now.
+// The returned step is the pending step, or the escalation step to remind after the escalation.
+func checkApproval(approval *storepb.IssuePayloadApproval, now time.Time) (approvalAction, *storepb.ApprovalStep) {
+	if !approval.ApprovalFindingDone || approval.ApprovalFindingError != "" {
+		return approvalActionNone, nil
+	}
+	if len(approval.ApprovalTemplates) != 1 {
+		return approvalActionNone, nil
+	}
+	rejectedStep := utils.FindRejectedStep(approval.ApprovalTemplates[0], approval.Approvers)
+	if rejectedStep != nil {
+		return approvalActionNone, nil
+	}
+	step := utils.FindNextPendingStep(approval.ApprovalTemplates[0], approval.Approvers)
+	if step == nil {
+		return approvalActionNone, nil
+	}
+
+	if approval.PendingStepStartTs == 0 || int(approval.PendingStepIndex) != len(approval.Approvers) {
+		// A new step becomes pending.
+		approval.PendingStepIndex = int32(len(approval.Approvers))
+		approval.PendingStepStartTs = now.Unix()
+		approval.PendingStepRemindedTs = 0
+		approval.PendingStepEscalated = false
+		return approvalActionStart, step
+	}
+
+	startTime := time.Unix(approval.PendingStepStartTs, 0)
+	if timeout := step.Timeout.AsDuration(); step.Timeout != nil && timeout > 0 && !approval.PendingStepEscalated && !now.Before(startTime.Add(timeout)) {
+		if utils.GetEscalationStep(step) == nil {
+			approval.Approvers = append(approval.Approvers, &storepb.IssuePayloadApproval_Approver{
+				Status:      storepb.IssuePayloadApproval_Approver_REJECTED,
+				PrincipalId: api.SystemBotID,
+			})
+			return approvalActionReject, step
+		}
+		approval.PendingStepEscalated = true
+		approval.PendingStepRemindedTs = now.Unix()
+		return approvalActionEscalate, step
+	}
+
+	if interval := step.ReminderInterval.AsDuration(); step.ReminderInterval != nil && interval > 0 {
+		lastNotifiedTime := startTime
+		if approval.PendingStepRemindedTs > approval.PendingStepStartTs {
+			lastNotifiedTime = time.Unix(approval.PendingStepRemindedTs, 0)
+		}
+		if now.Before(lastNotifiedTime.Add(interval)) {
+			return approvalActionNone, nil
+		}
+		approval.PendingStepRemindedTs = now.Unix()
+		if approval.PendingStepEscalated {
+			return approvalActionRemind, utils.GetEscalationStep(step)
+		}
+		return approvalActionRemind, step
+	}
+	return approvalActionNone, nil
+}
+
+func (r *Runner) createCommentActivity(ctx context.Context, issue *store.IssueMessage, event *storepb.ActivityIssueCommentCreatePayload_ApprovalEvent_, comment string) error {
+	payload := &storepb.ActivityIssueCommentCreatePayload{
+		IssueName: issue.Title,
+	}
+	if event != nil {
+		payload.Event = event
+	}
+	activityPayload, err := protojson.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	create := &store.ActivityMessage{
+		CreatorUID:   api.SystemBotID,
+		ContainerUID: issue.UID,
+		Type:         api.ActivityIssueCommentCreate,
+		Level:        api.ActivityInfo,
+		Comment:      comment,
+		Payload:      string(activityPayload),
+	}
+	if _, err := r.activityManager.CreateActivity(ctx, create, &activity.Metadata{}); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *Runner) createApprovalNotifyActivity(ctx context.Context, issue *store.IssueMessage, step *storepb.ApprovalStep) error {
+	protoPayload, err := protojson.Marshal(&storepb.ActivityIssueApprovalNotifyPayload{
+		ApprovalStep: step,
+	})
+	if err != nil {
+		return err
+	}
+	activityPayload, err := json.Marshal(api.ActivityIssueApprovalNotifyPayload{
+		ProtoPayload: string(protoPayload),
+	})
+	if err != nil {
+		return err
+	}
+	create := &store.ActivityMessage{
+		CreatorUID:   api.SystemBotID,
+		ContainerUID: issue.UID,
+		Type:         api.ActivityIssueApprovalNotify,
+		Level:        api.ActivityInfo,
+		Comment:      "",
+		Payload:      string(activityPayload),
+	}
+	if _, err := r.activityManager.CreateActivity(ctx, create, &activity.Metadata{Issue: issue}); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,204 @@
+package approvaltimeout
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	storepb "github.com/bytebase/bytebase/proto/generated-go/store"
+)
+
+func TestCheckApproval(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	newApproval := func(step *storepb.ApprovalStep, approvers ...*storepb.IssuePayloadApproval_Approver) *storepb.IssuePayloadApproval {
+		return &storepb.IssuePayloadApproval{
+			ApprovalFindingDone: true,
+			ApprovalTemplates: []*storepb.ApprovalTemplate{
+				{
+					Flow: &storepb.ApprovalFlow{
+						Steps: []*storepb.ApprovalStep{
+							{Type: storepb.ApprovalStep_ANY},
+							step,
+						},
+					},
+				},
+			},
+			Approvers: approvers,
+		}
+	}
+	approved := &storepb.IssuePayloadApproval_Approver{Status: storepb.IssuePayloadApproval_Approver_APPROVED, PrincipalId: 101}
+	timeoutStep := &storepb.ApprovalStep{
+		Type:             storepb.ApprovalStep_ANY,
+		Timeout:          durationpb.New(time.Hour),
+		ReminderInterval: durationpb.New(10 * time.Minute),
+	}
+	escalationStep := &storepb.ApprovalStep{
+		Type:             storepb.ApprovalStep_ANY,
+		Timeout:          durationpb.New(time.Hour),
+		ReminderInterval: durationpb.New(10 * time.Minute),
+		EscalationRole:   "roles/DBA",
+	}
+
+	tests := []struct {
+		name     string
+		approval *storepb.IssuePayloadApproval
+		action   approvalAction
+		// want is the approval after the check, which is unchanged if nil.
+		want *storepb.IssuePayloadApproval
+	}{
+		{
+			name:     "finding not done",
+			approval: &storepb.IssuePayloadApproval{},
+			action:   approvalActionNone,
+		},
+		{
+			name:     "start pending step",
+			approval: newApproval(timeoutStep, approved),
+			action:   approvalActionStart,
+			want: func() *storepb.IssuePayloadApproval {
+				a := newApproval(timeoutStep, approved)
+				a.PendingStepIndex = 1
+				a.PendingStepStartTs = now.Unix()
+				return a
+			}(),
+		},
+		{
+			name: "restart after the approver of the step changes",
+			approval: func() *storepb.IssuePayloadApproval {
+				a := newApproval(timeoutStep, approved)
+				a.PendingStepIndex = 0
+				a.PendingStepStartTs = now.Add(-2 * time.Hour).Unix()
+				a.PendingStepEscalated = true
+				return a
+			}(),
+			action: approvalActionStart,
+			want: func() *storepb.IssuePayloadApproval {
+				a := newApproval(timeoutStep, approved)
+				a.PendingStepIndex = 1
+				a.PendingStepStartTs = now.Unix()
+				return a
+			}(),
+		},
+		{
+			name: "not due",
+			approval: func() *storepb.IssuePayloadApproval {
+				a := newApproval(timeoutStep, approved)
+				a.PendingStepIndex = 1
+				a.PendingStepStartTs = now.Add(-5 * time.Minute).Unix()
+				return a
+			}(),
+			action: approvalActionNone,
+		},
+		{
+			name: "remind",
+			approval: func() *storepb.IssuePayloadApproval {
+				a := newApproval(timeoutStep, approved)
+				a.PendingStepIndex = 1
+				a.PendingStepStartTs = now.Add(-30 * time.Minute).Unix()
+				a.PendingStepRemindedTs = now.Add(-15 * time.Minute).Unix()
+				return a
+			}(),
+			action: approvalActionRemind,
+			want: func() *storepb.IssuePayloadApproval {
+				a := newApproval(timeoutStep, approved)
+				a.PendingStepIndex = 1
+				a.PendingStepStartTs = now.Add(-30 * time.Minute).Unix()
+				a.PendingStepRemindedTs = now.Unix()
+				return a
+			}(),
+		},
+		{
+			name: "reject after timeout",
+			approval: func() *storepb.IssuePayloadApproval {
+				a := newApproval(timeoutStep, approved)
+				a.PendingStepIndex = 1
+				a.PendingStepStartTs = now.Add(-time.Hour).Unix()
+				return a
+			}(),
+			action: approvalActionReject,
+			want: func() *storepb.IssuePayloadApproval {
+				a := newApproval(timeoutStep, approved, &storepb.IssuePayloadApproval_Approver{
+					Status:      storepb.IssuePayloadApproval_Approver_REJECTED,
+					PrincipalId: api.SystemBotID,
+				})
+				a.PendingStepIndex = 1
+				a.PendingStepStartTs = now.Add(-time.Hour).Unix()
+				return a
+			}(),
+		},
+		{
+			name: "escalate after timeout",
+			approval: func() *storepb.IssuePayloadApproval {
+				a := newApproval(escalationStep, approved)
+				a.PendingStepIndex = 1
+				a.PendingStepStartTs = now.Add(-2 * time.Hour).Unix()
+				return a
+			}(),
+			action: approvalActionEscalate,
+			want: func() *storepb.IssuePayloadApproval {
+				a := newApproval(escalationStep, approved)
+				a.PendingStepIndex = 1
+				a.PendingStepStartTs = now.Add(-2 * time.Hour).Unix()
+				a.PendingStepRemindedTs = now.Unix()
+				a.PendingStepEscalated = true
+				return a
+			}(),
+		},
+		{
+			name: "already rejected",
+			approval: newApproval(timeoutStep, &storepb.IssuePayloadApproval_Approver{
+				Status:      storepb.IssuePayloadApproval_Approver_REJECTED,
+				PrincipalId: 101,
+			}),
+			action: approvalActionNone,
+		},
+		{
+			name:     "all approved",
+			approval: newApproval(timeoutStep, approved, approved),
+			action:   approvalActionNone,
+		},
+	}
+
+	for _, test := range tests {
+		a := require.New(t)
+		want := test.want
+		if want == nil {
+			want = proto.Clone(test.approval).(*storepb.IssuePayloadApproval)
+		}
+		action, _ := checkApproval(test.approval, now)
+		a.Equal(test.action, action, test.name)
+		a.Empty(cmp.Diff(want, test.approval, protocmp.Transform()), test.name)
+	}
+}
+
+func TestCheckApprovalEscalatedReminder(t *testing.T) {
+	a := require.New(t)
+	now := time.Unix(1_700_000_000, 0)
+	step := &storepb.ApprovalStep{
+		Type:             storepb.ApprovalStep_ANY,
+		Timeout:          durationpb.New(time.Hour),
+		ReminderInterval: durationpb.New(10 * time.Minute),
+		EscalationRole:   "roles/DBA",
+	}
+	approval := &storepb.IssuePayloadApproval{
+		ApprovalFindingDone: true,
+		ApprovalTemplates: []*storepb.ApprovalTemplate{
+			{Flow: &storepb.ApprovalFlow{Steps: []*storepb.ApprovalStep{step}}},
+		},
+		PendingStepStartTs:    now.Add(-2 * time.Hour).Unix(),
+		PendingStepRemindedTs: now.Add(-time.Hour).Unix(),
+		PendingStepEscalated:  true,
+	}
+
+	// The escalation role is reminded after the escalation.
+	action, reminderStep := checkApproval(approval, now)
+	a.Equal(approvalActionRemind, action)
+	a.Len(reminderStep.Nodes, 1)
+	a.Equal("roles/DBA", reminderStep.Nodes[0].GetRole())
+}
@@ -0,0 +1,230 @@
+// Package auditexport is a runner that streams the audit logs in the activity ledger to the configured destinations.
+package auditexport
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/backend/common"
+	"github.com/bytebase/bytebase/backend/common/log"
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/plugin/auditlog"
+	"github.com/bytebase/bytebase/backend/store"
+)
+
+const (
+	auditExportInterval = 10 * time.Second
+	// auditExportBatchSize is the number of the ledger records sent to a destination in one batch.
+	auditExportBatchSize = 100
+)
+
+// NewRunner creates a new audit log export runner.
+func NewRunner(store *store.Store) *Runner {
+	return &Runner{
+		store:        store,
+		destinations: make(map[string]*destination),
+	}
+}
+
+// Runner is the audit log export runner.
+type Runner struct {
+	store *store.Store
+	// destinations is the map from the destination ID to the opened destination.
+	// The connections and the files are kept open across the rounds.
+	destinations map[string]*destination
+}
+
+type destination struct {
+	// config is the JSON of the destination setting, the destination is reopened if it changes.
+	config string
+	auditlog.Destination
+}
+
+// Run will run the audit log export runner.
+func (r *Runner) Run(ctx context.Context, wg *sync.WaitGroup) {
+	ticker := time.NewTicker(auditExportInterval)
+	defer ticker.Stop()
+	defer wg.Done()
+	defer r.closeAll()
+	slog.Debug(fmt.Sprintf("Audit log export runner started and will run every %s", auditExportInterval.String()))
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Debug("Audit log export runner received context cancellation")
+			return
+		case <-ticker.C:
+			r.exportAll(ctx)
+		}
+	}
+}
+
+func (r *Runner) exportAll(ctx context.Context) {
+	defer func() {
+		if r := recover(); r != nil {
+			err, ok := r.(error)
+			if !ok {
+				err = errors.Errorf("%v", r)
+			}
+			slog.Error("Audit log export runner PANIC RECOVER", log.BBError(err), log.BBStack("panic-stack"))
+		}
+	}()
+
+	setting, err := r.store.GetAuditLogExportSetting(ctx)
+	if err != nil {
+		slog.Error("failed to get audit log export setting", log.BBError(err))
+		return
+	}
+	configured := make(map[string]bool)
+	for _, config := range setting.Destinations {
+		configured[config.ID] = true
+		if err := r.export(ctx, config); err != nil {
+			slog.Error("failed to export audit logs", slog.String("destination", config.ID), log.BBError(err))
+		}
+	}
+	for id, d := range r.destinations {
+		if configured[id] {
+			continue
+		}
+		if err := d.Close(); err != nil {
+			slog.Warn("failed to close audit log destination", slog.String("destination", id), log.BBError(err))
+		}
+		delete(r.destinations, id)
+	}
+}
+
+// export sends the ledger records after the cursor of the destination, and advances the cursor after each batch is sent.
+func (r *Runner) export(ctx context.Context, config *api.AuditLogDestination) error {
+	d, err := r.getDestination(config)
+	if err != nil {
+		return err
+	}
+	cursor, err := r.store.GetAuditLogExportCursor(ctx, config.ID)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get export cursor")
+	}
+	for {
+		records, err := r.store.ListActivityLedger(ctx, &store.FindActivityLedgerMessage{
+			SeqAfter: cursor,
+			Limit:    auditExportBatchSize,
+		})
+		if err != nil {
+			return errors.Wrapf(err, "failed to list activity ledger")
+		}
+		if len(records) == 0 {
+			return nil
+		}
+		var entries []*auditlog.Entry
+		for _, record := range records {
+			entry, err := r.getEntry(ctx, record, config.ActivityTypes)
+			if err != nil {
+				return err
+			}
+			if entry != nil {
+				entries = append(entries, entry)
+			}
+		}
+		if len(entries) > 0 {
+			if err := d.Send(ctx, entries); err != nil {
+				return err
+			}
+		}
+		cursor = records[len(records)-1].Seq
+		if err := r.store.UpsertAuditLogExportCursor(ctx, config.ID, cursor); err != nil {
+			return err
+		}
+		if len(records) < auditExportBatchSize {
+			return nil
+		}
+	}
+}
+
+// getEntry converts the ledger record to the audit log entry.
+// It returns nil if the activity type isn't exported or the activity no longer exists.
+func (r *Runner) getEntry(ctx context.Context, record *store.ActivityLedgerMessage, activityTypes []api.ActivityType) (*auditlog.Entry, error) {
+	activity, err := r.store.GetActivityV2(ctx, record.ActivityUID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get activity %d", record.ActivityUID)
+	}
+	if activity == nil {
+		slog.Warn("activity in the ledger is not found", slog.Int("activity", record.ActivityUID))
+		return nil, nil
+	}
+	if !isExported(activityTypes, activity.Type) {
+		return nil, nil
+	}
+	entry := &auditlog.Entry{
+		LedgerSeq:   record.Seq,
+		Hash:        record.Hash,
+		PrevHash:    record.PrevHash,
+		ContentHash: record.ContentHash,
+		Log:         fmt.Sprintf("%s%d", common.LogNamePrefix, activity.UID),
+		CreateTime:  time.Unix(activity.CreatedTs, 0),
+		UpdateTime:  time.Unix(activity.UpdatedTs, 0),
+		Creator:     common.FormatUserUID(activity.CreatorUID),
+		Type:        string(activity.Type),
+		Level:       string(activity.Level),
+		ContainerID: activity.ContainerUID,
+		Comment:     activity.Comment,
+	}
+	if json.Valid([]byte(activity.Payload)) {
+		entry.Payload = json.RawMessage(activity.Payload)
+	}
+	user, err := r.store.GetUserByID(ctx, activity.CreatorUID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get user %d", activity.CreatorUID)
+	}
+	if user != nil {
+		entry.Creator = common.FormatUserEmail(user.Email)
+	}
+	return entry, nil
+}
+
+func (r *Runner) getDestination(config *api.AuditLogDestination) (auditlog.Destination, error) {
+	b, err := json.Marshal(config)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal destination config")
+	}
+	if d, ok := r.destinations[config.ID]; ok {
+		if d.config == string(b) {
+			return d, nil
+		}
+		if err := d.Close(); err != nil {
+			slog.Warn("failed to close audit log destination", slog.String("destination", config.ID), log.BBError(err))
+		}
+		delete(r.destinations, config.ID)
+	}
+	newDestination, err := auditlog.NewDestination(config)
+	if err != nil {
+		return nil, err
+	}
+	d := &destination{config: string(b), Destination: newDestination}
+	r.destinations[config.ID] = d
+	return d, nil
+}
+
+func (r *Runner) closeAll() {
+	for id, d := range r.destinations {
+		if err := d.Close(); err != nil {
+			slog.Warn("failed to close audit log destination", slog.String("destination", id), log.BBError(err))
+		}
+	}
+	r.destinations = make(map[string]*destination)
+}
+
+func isExported(activityTypes []api.ActivityType, activityType api.ActivityType) bool {
+	if len(activityTypes) == 0 {
+		return true
+	}
+	for _, t := range activityTypes {
+		if t == activityType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,267 @@
+// Package grantexpiry is a runner that revokes the expired query and export grants from the project IAM policies.
+package grantexpiry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/backend/common"
+	"github.com/bytebase/bytebase/backend/common/log"
+	"github.com/bytebase/bytebase/backend/component/activity"
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/plugin/webhook"
+	"github.com/bytebase/bytebase/backend/store"
+	"github.com/bytebase/bytebase/backend/utils"
+)
+
+const (
+	grantExpiryCheckInterval = 5 * time.Minute
+	// grantExpiringNoticePeriod is how long before the expiration the grantees are notified.
+	grantExpiringNoticePeriod = 24 * time.Hour
+)
+
+// NewRunner creates a new grant expiry runner.
+func NewRunner(store *store.Store, activityManager *activity.Manager) *Runner {
+	return &Runner{
+		store:           store,
+		activityManager: activityManager,
+	}
+}
+
+// Runner is the grant expiry runner.
+type Runner struct {
+	store           *store.Store
+	activityManager *activity.Manager
+}
+
+// grant is a member of an expiring project IAM policy binding.
+type grant struct {
+	binding    *store.PolicyBinding
+	member     *store.UserMessage
+	expireTime time.Time
+}
+
+// grantKey identifies the grant in the expiring notice activities.
+type grantKey struct {
+	principalID int
+	role        api.Role
+	expireTs    int64
+}
+
+// Run will run the grant expiry runner.
+func (r *Runner) Run(ctx context.Context, wg *sync.WaitGroup) {
+	ticker := time.NewTicker(grantExpiryCheckInterval)
+	defer ticker.Stop()
+	defer wg.Done()
+	slog.Debug(fmt.Sprintf("Grant expiry runner started and will run every %s", grantExpiryCheckInterval.String()))
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Debug("Grant expiry runner received context cancellation")
+			return
+		case <-ticker.C:
+			slog.Debug("Grant expiry runner received tick")
+			r.checkAll(ctx)
+		}
+	}
+}
+
+func (r *Runner) checkAll(ctx context.Context) {
+	defer func() {
+		if r := recover(); r != nil {
+			err, ok := r.(error)
+			if !ok {
+				err = errors.Errorf("%v", r)
+			}
+			slog.Error("Grant expiry runner PANIC RECOVER", log.BBError(err), log.BBStack("panic-stack"))
+		}
+	}()
+
+	projects, err := r.store.ListProjectV2(ctx, &store.FindProjectMessage{})
+	if err != nil {
+		slog.Error("failed to list projects", log.BBError(err))
+		return
+	}
+	for _, project := range projects {
+		if err := r.checkProject(ctx, project); err != nil {
+			slog.Error("failed to check the expired grants", slog.String("project", project.ResourceID), log.BBError(err))
+		}
+	}
+}
+
+// checkProject revokes the expired grants in the project, and notifies the grantees of the grants that are about to expire.
+func (r *Runner) checkProject(ctx context.Context, project *store.ProjectMessage) error {
+	policy, err := r.store.GetProjectPolicy(ctx, &store.GetProjectPolicyMessage{UID: &project.UID})
+	if err != nil {
+		return errors.Wrapf(err, "failed to get project policy")
+	}
+
+	now := time.Now()
+	expiredPolicy, expired, expiring := getExpiredGrants(policy, now)
+	if len(expiredPolicy.Bindings) > 0 {
+		// Only the expired bindings are deleted, so the grants added or renewed concurrently are kept.
+		if err := r.store.DeleteProjectIAMPolicyBindings(ctx, expiredPolicy, project.UID); err != nil {
+			return errors.Wrapf(err, "failed to revoke expired grants")
+		}
+	}
+	for _, g := range expired {
+		comment := fmt.Sprintf("Revoked %s from %s (%s) as the grant expired at %s.", g.binding.Role, g.member.Name, g.member.Email, g.expireTime.UTC().Format(time.RFC3339))
+		if err := r.createGrantActivity(ctx, project, api.ActivityProjectMemberDelete, api.ActivityInfo, comment, g); err != nil {
+			slog.Warn("Failed to create project activity", log.BBError(err))
+		}
+		r.activityManager.PostEvent(ctx, &activity.Event{
+			Type:        api.EventProjectMemberExpire,
+			Level:       webhook.WebhookInfo,
+			Title:       fmt.Sprintf("Granted role expired - %s", g.member.Name),
+			Description: comment,
+			CreatorUID:  api.SystemBotID,
+			Project:     project,
+			Member: &webhook.Member{
+				ID:       g.member.ID,
+				Name:     g.member.Name,
+				Email:    g.member.Email,
+				Role:     string(g.binding.Role),
+				ExpireTs: g.expireTime.Unix(),
+			},
+		})
+	}
+
+	if len(expiring) == 0 {
+		return nil
+	}
+	notified, err := r.listNotifiedGrants(ctx, project, now)
+	if err != nil {
+		return err
+	}
+	for _, g := range getUnnotifiedGrants(expiring, notified) {
+		comment := fmt.Sprintf("%s of %s (%s) expires at %s.", g.binding.Role, g.member.Name, g.member.Email, g.expireTime.UTC().Format(time.RFC3339))
+		if err := r.createGrantActivity(ctx, project, api.ActivityProjectMemberExpiring, api.ActivityWarn, comment, g); err != nil {
+			slog.Warn("Failed to create project activity", log.BBError(err))
+		}
+	}
+	return nil
+}
+
+// getExpiredGrants returns the policy of the expired bindings, the expired grants,
+// and the grants expiring within the notice period.
+func getExpiredGrants(policy *store.IAMPolicyMessage, now time.Time) (*store.IAMPolicyMessage, []*grant, []*grant) {
+	expiredPolicy := &store.IAMPolicyMessage{}
+	var expired, expiring []*grant
+	for _, binding := range policy.Bindings {
+		expireTime := getGrantExpireTime(binding)
+		if expireTime.IsZero() || expireTime.After(now.Add(grantExpiringNoticePeriod)) {
+			continue
+		}
+		if !expireTime.After(now) {
+			expiredPolicy.Bindings = append(expiredPolicy.Bindings, binding)
+		}
+		for _, member := range binding.Members {
+			g := &grant{binding: binding, member: member, expireTime: expireTime}
+			if expireTime.After(now) {
+				expiring = append(expiring, g)
+			} else {
+				expired = append(expired, g)
+			}
+		}
+	}
+	return expiredPolicy, expired, expiring
+}
+
+// getUnnotifiedGrants returns the expiring grants that the grantees haven't been notified of.
+func getUnnotifiedGrants(expiring []*grant, notified map[grantKey]bool) []*grant {
+	var grants []*grant
+	for _, g := range expiring {
+		if notified[grantKey{principalID: g.member.ID, role: g.binding.Role, expireTs: g.expireTime.Unix()}] {
+			continue
+		}
+		grants = append(grants, g)
+	}
+	return grants
+}
+
+// listNotifiedGrants returns the grants that the grantees have been notified of within the notice period.
+func (r *Runner) listNotifiedGrants(ctx context.Context, project *store.ProjectMessage, now time.Time) (map[grantKey]bool, error) {
+	createdTsAfter := now.Add(-grantExpiringNoticePeriod).Unix()
+	activities, err := r.store.ListActivityV2(ctx, &store.FindActivityMessage{
+		TypeList:       []api.ActivityType{api.ActivityProjectMemberExpiring},
+		ContainerUID:   &project.UID,
+		CreatedTsAfter: &createdTsAfter,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list expiring grant activities")
+	}
+	notified := map[grantKey]bool{}
+	for _, a := range activities {
+		payload := &api.ActivityProjectMemberGrantPayload{}
+		if err := json.Unmarshal([]byte(a.Payload), payload); err != nil {
+			continue
+		}
+		notified[grantKey{principalID: payload.PrincipalID, role: payload.Role, expireTs: payload.ExpireTs}] = true
+	}
+	return notified, nil
+}
+
+// createGrantActivity creates the project activity of the grant, and posts it to the inbox of the grantee.
+// The activity is posted to the project webhooks if the grant is requested by an issue.
+func (r *Runner) createGrantActivity(ctx context.Context, project *store.ProjectMessage, activityType api.ActivityType, level api.ActivityLevel, comment string, g *grant) error {
+	issueUID := utils.GetGrantIssueUID(g.binding)
+	payload, err := json.Marshal(&api.ActivityProjectMemberGrantPayload{
+		PrincipalID:    g.member.ID,
+		PrincipalName:  g.member.Name,
+		PrincipalEmail: g.member.Email,
+		Role:           g.binding.Role,
+		IssueID:        issueUID,
+		ExpireTs:       g.expireTime.Unix(),
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal activity payload")
+	}
+	meta := &activity.Metadata{}
+	if issueUID != 0 {
+		issue, err := r.store.GetIssueV2(ctx, &store.FindIssueMessage{UID: &issueUID})
+		if err != nil {
+			return errors.Wrapf(err, "failed to get issue %d", issueUID)
+		}
+		meta.Issue = issue
+	}
+	a, err := r.activityManager.CreateActivity(ctx, &store.ActivityMessage{
+		CreatorUID:   api.SystemBotID,
+		ContainerUID: project.UID,
+		Type:         activityType,
+		Level:        level,
+		Comment:      comment,
+		Payload:      string(payload),
+	}, meta)
+	if err != nil {
+		return err
+	}
+	if _, err := r.store.CreateInbox(ctx, &store.InboxMessage{
+		ReceiverUID: g.member.ID,
+		ActivityUID: a.UID,
+	}); err != nil {
+		return errors.Wrapf(err, "failed to post activity to grantee inbox: %d", g.member.ID)
+	}
+	return nil
+}
+
+// getGrantExpireTime returns the expiration time of the query and export grant, or zero if the binding never expires.
+func getGrantExpireTime(binding *store.PolicyBinding) time.Time {
+	if binding.Role != api.Querier && binding.Role != api.Exporter {
+		return time.Time{}
+	}
+	if binding.Condition == nil || binding.Condition.Expression == "" {
+		return time.Time{}
+	}
+	factors, err := common.GetQueryExportFactors(binding.Condition.Expression)
+	if err != nil {
+		slog.Debug("failed to parse the grant condition", slog.String("expression", binding.Condition.Expression), log.BBError(err))
+		return time.Time{}
+	}
+	return factors.ExpireTime
+}
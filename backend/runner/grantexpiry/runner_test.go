@@ -0,0 +1,111 @@
+package grantexpiry
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/type/expr"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/store"
+)
+
+func newCondition(expireTime time.Time) *expr.Expr {
+	return &expr.Expr{
+		Expression: fmt.Sprintf(`resource.database in ["instances/i/databases/db"] && request.time < timestamp("%s")`, expireTime.UTC().Format(time.RFC3339)),
+	}
+}
+
+func TestGetGrantExpireTime(t *testing.T) {
+	expireTime := time.Date(2023, 7, 10, 8, 14, 34, 0, time.UTC)
+	tests := []struct {
+		name    string
+		binding *store.PolicyBinding
+		want    time.Time
+	}{
+		{
+			name:    "querier",
+			binding: &store.PolicyBinding{Role: api.Querier, Condition: newCondition(expireTime)},
+			want:    expireTime,
+		},
+		{
+			name:    "exporter",
+			binding: &store.PolicyBinding{Role: api.Exporter, Condition: newCondition(expireTime)},
+			want:    expireTime,
+		},
+		{
+			name:    "other roles never expire",
+			binding: &store.PolicyBinding{Role: api.Developer, Condition: newCondition(expireTime)},
+		},
+		{
+			name:    "without condition",
+			binding: &store.PolicyBinding{Role: api.Querier},
+		},
+		{
+			name:    "without expiration",
+			binding: &store.PolicyBinding{Role: api.Querier, Condition: &expr.Expr{Expression: `resource.database in ["instances/i/databases/db"]`}},
+		},
+	}
+
+	a := require.New(t)
+	for _, test := range tests {
+		a.True(test.want.Equal(getGrantExpireTime(test.binding)), test.name)
+	}
+}
+
+func TestGetExpiredGrants(t *testing.T) {
+	now := time.Date(2023, 7, 10, 8, 0, 0, 0, time.UTC)
+	alice := &store.UserMessage{ID: 101}
+	bob := &store.UserMessage{ID: 102}
+	expiredQuerier := &store.PolicyBinding{Role: api.Querier, Members: []*store.UserMessage{alice, bob}, Condition: newCondition(now.Add(-time.Hour))}
+	expiringExporter := &store.PolicyBinding{Role: api.Exporter, Members: []*store.UserMessage{alice}, Condition: newCondition(now.Add(time.Hour))}
+	activeQuerier := &store.PolicyBinding{Role: api.Querier, Members: []*store.UserMessage{bob}, Condition: newCondition(now.Add(48 * time.Hour))}
+	owner := &store.PolicyBinding{Role: api.Role("OWNER"), Members: []*store.UserMessage{alice}}
+	policy := &store.IAMPolicyMessage{Bindings: []*store.PolicyBinding{owner, expiredQuerier, expiringExporter, activeQuerier}}
+
+	a := require.New(t)
+	expiredPolicy, expired, expiring := getExpiredGrants(policy, now)
+	// Only the expired bindings are revoked.
+	a.Equal([]*store.PolicyBinding{expiredQuerier}, expiredPolicy.Bindings)
+	a.Len(expired, 2)
+	for i, member := range []*store.UserMessage{alice, bob} {
+		a.Equal(expiredQuerier, expired[i].binding)
+		a.Equal(member, expired[i].member)
+		a.True(now.Add(-time.Hour).Equal(expired[i].expireTime))
+	}
+	a.Len(expiring, 1)
+	a.Equal(expiringExporter, expiring[0].binding)
+	a.Equal(alice, expiring[0].member)
+	a.True(now.Add(time.Hour).Equal(expiring[0].expireTime))
+
+	// The policy without expired or expiring bindings is left alone.
+	expiredPolicy, expired, expiring = getExpiredGrants(&store.IAMPolicyMessage{Bindings: []*store.PolicyBinding{owner, activeQuerier}}, now)
+	a.Empty(expiredPolicy.Bindings)
+	a.Empty(expired)
+	a.Empty(expiring)
+}
+
+func TestGetUnnotifiedGrants(t *testing.T) {
+	expireTime := time.Date(2023, 7, 10, 8, 0, 0, 0, time.UTC)
+	alice := &store.UserMessage{ID: 101}
+	bob := &store.UserMessage{ID: 102}
+	querier := &store.PolicyBinding{Role: api.Querier}
+	exporter := &store.PolicyBinding{Role: api.Exporter}
+	aliceQuerier := &grant{binding: querier, member: alice, expireTime: expireTime}
+	aliceExporter := &grant{binding: exporter, member: alice, expireTime: expireTime}
+	bobQuerier := &grant{binding: querier, member: bob, expireTime: expireTime}
+	renewedBobQuerier := &grant{binding: querier, member: bob, expireTime: expireTime.Add(time.Hour)}
+	notified := map[grantKey]bool{
+		{principalID: alice.ID, role: api.Querier, expireTs: expireTime.Unix()}: true,
+		{principalID: bob.ID, role: api.Querier, expireTs: expireTime.Unix()}:   true,
+	}
+
+	a := require.New(t)
+	a.Equal(
+		[]*grant{aliceExporter, renewedBobQuerier},
+		getUnnotifiedGrants([]*grant{aliceQuerier, aliceExporter, bobQuerier, renewedBobQuerier}, notified),
+	)
+	a.Empty(getUnnotifiedGrants([]*grant{aliceQuerier}, notified))
+}
@@ -0,0 +1,358 @@
+// Package ldapsync is a runner that synchronizes the LDAP groups into the workspace roles and the project IAM policies.
+package ldapsync
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/mail"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/backend/common/log"
+	enterprise "github.com/bytebase/bytebase/backend/enterprise/api"
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	bbidp "github.com/bytebase/bytebase/backend/plugin/idp"
+	"github.com/bytebase/bytebase/backend/plugin/idp/ldap"
+	"github.com/bytebase/bytebase/backend/store"
+	"github.com/bytebase/bytebase/backend/utils"
+	storepb "github.com/bytebase/bytebase/proto/generated-go/store"
+)
+
+const ldapGroupSyncInterval = 15 * time.Minute
+
+// NewSyncer creates a new LDAP group syncer.
+func NewSyncer(store *store.Store, licenseService enterprise.LicenseService) *Syncer {
+	return &Syncer{
+		store:          store,
+		licenseService: licenseService,
+	}
+}
+
+// Syncer is the LDAP group syncer.
+type Syncer struct {
+	store          *store.Store
+	licenseService enterprise.LicenseService
+}
+
+// bindingKey is the project IAM policy binding that the LDAP groups are mapped to.
+type bindingKey struct {
+	project string
+	role    string
+}
+
+// Run will run the LDAP group syncer.
+func (s *Syncer) Run(ctx context.Context, wg *sync.WaitGroup) {
+	ticker := time.NewTicker(ldapGroupSyncInterval)
+	defer ticker.Stop()
+	defer wg.Done()
+	slog.Debug(fmt.Sprintf("LDAP group syncer started and will run every %s", ldapGroupSyncInterval.String()))
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Debug("LDAP group syncer received context cancellation")
+			return
+		case <-ticker.C:
+			slog.Debug("LDAP group syncer received tick")
+			s.syncAll(ctx)
+		}
+	}
+}
+
+func (s *Syncer) syncAll(ctx context.Context) {
+	defer func() {
+		if r := recover(); r != nil {
+			err, ok := r.(error)
+			if !ok {
+				err = errors.Errorf("%v", r)
+			}
+			slog.Error("LDAP group syncer PANIC RECOVER", log.BBError(err), log.BBStack("panic-stack"))
+		}
+	}()
+
+	if s.licenseService.IsFeatureEnabled(api.FeatureSSO) != nil {
+		return
+	}
+	setting, err := s.store.GetLDAPGroupSyncSetting(ctx)
+	if err != nil {
+		slog.Error("failed to get LDAP group sync setting", log.BBError(err))
+		return
+	}
+	for _, config := range setting.IdentityProviders {
+		if err := s.syncIdentityProvider(ctx, config); err != nil {
+			slog.Error("failed to sync LDAP groups", slog.String("identityProvider", config.IdentityProvider), log.BBError(err))
+		}
+	}
+}
+
+// SyncUser synchronizes the groups of the user logged in with the LDAP identity provider.
+func (s *Syncer) SyncUser(ctx context.Context, idp *store.IdentityProviderMessage, username string, user *store.UserMessage) error {
+	if s.licenseService.IsFeatureEnabled(api.FeatureSSO) != nil {
+		return nil
+	}
+	config, err := s.getConfig(ctx, idp.ResourceID)
+	if err != nil {
+		return err
+	}
+	if config == nil {
+		return nil
+	}
+	provider, err := newIdentityProvider(idp.Config.GetLdapConfig())
+	if err != nil {
+		return err
+	}
+	ldapUser, err := provider.GetUser(username)
+	if err != nil {
+		return err
+	}
+	if ldapUser == nil {
+		return nil
+	}
+	if err := s.sync(ctx, provider, config, map[string]*store.UserMessage{ldap.NormalizeDN(ldapUser.DN): user}, nil); err != nil {
+		return err
+	}
+	return s.store.AddLDAPGroupSyncUsers(ctx, config.IdentityProvider, []int{user.ID})
+}
+
+func (s *Syncer) getConfig(ctx context.Context, idpID string) (*api.LDAPGroupSyncConfig, error) {
+	setting, err := s.store.GetLDAPGroupSyncSetting(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, config := range setting.IdentityProviders {
+		if config.IdentityProvider == idpID {
+			return config, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *Syncer) syncIdentityProvider(ctx context.Context, config *api.LDAPGroupSyncConfig) error {
+	idp, err := s.store.GetIdentityProvider(ctx, &store.FindIdentityProviderMessage{ResourceID: &config.IdentityProvider})
+	if err != nil {
+		return errors.Wrapf(err, "failed to get identity provider")
+	}
+	if idp == nil || idp.Deleted || idp.Type != storepb.IdentityProviderType_LDAP {
+		slog.Warn("LDAP identity provider of the group sync config not found", slog.String("identityProvider", config.IdentityProvider))
+		return nil
+	}
+	provider, err := newIdentityProvider(idp.Config.GetLdapConfig())
+	if err != nil {
+		return err
+	}
+	ldapUsers, err := provider.ListUsers()
+	if err != nil {
+		return err
+	}
+	if len(ldapUsers) == 0 {
+		// Guard against revoking the access of all users if the directory is misconfigured.
+		slog.Warn("no LDAP users found, skip the group sync", slog.String("identityProvider", config.IdentityProvider))
+		return nil
+	}
+	// Only the users who have signed in or have been provisioned are synced.
+	users := make(map[string]*store.UserMessage)
+	userIDs := make(map[int]bool)
+	for _, ldapUser := range ldapUsers {
+		email := getEmail(idp, ldapUser.UserInfo)
+		if email == "" {
+			continue
+		}
+		user, err := s.store.GetUser(ctx, &store.FindUserMessage{Email: &email})
+		if err != nil {
+			return errors.Wrapf(err, "failed to get user %q", email)
+		}
+		if user == nil || user.Type != api.EndUser {
+			continue
+		}
+		users[ldap.NormalizeDN(ldapUser.DN)] = user
+		userIDs[user.ID] = true
+	}
+
+	// The users synced before but absent from the directory now are reconciled as the members of no group.
+	syncedUserIDs, err := s.store.ListLDAPGroupSyncUserIDs(ctx, config.IdentityProvider)
+	if err != nil {
+		return errors.Wrapf(err, "failed to list the synced users")
+	}
+	var absentUsers []*store.UserMessage
+	var absentUserIDs []int
+	for _, id := range syncedUserIDs {
+		if userIDs[id] {
+			continue
+		}
+		absentUserIDs = append(absentUserIDs, id)
+		user, err := s.store.GetUserByID(ctx, id)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get user %d", id)
+		}
+		if user != nil {
+			absentUsers = append(absentUsers, user)
+		}
+	}
+
+	if err := s.sync(ctx, provider, config, users, absentUsers); err != nil {
+		return err
+	}
+	var presentUserIDs []int
+	for id := range userIDs {
+		presentUserIDs = append(presentUserIDs, id)
+	}
+	if err := s.store.AddLDAPGroupSyncUsers(ctx, config.IdentityProvider, presentUserIDs); err != nil {
+		return errors.Wrapf(err, "failed to record the synced users")
+	}
+	return s.store.DeleteLDAPGroupSyncUsers(ctx, config.IdentityProvider, absentUserIDs)
+}
+
+// syncResult is the reconciled workspace roles and mapped project roles of the users.
+type syncResult struct {
+	// workspaceRoles are the workspace roles of the users keyed by the user ids, the users keeping their roles are absent.
+	workspaceRoles map[int]api.Role
+	bindingKeys    []bindingKey
+	// grants and revokes are the user ids granted and revoked for the bindings.
+	grants  map[bindingKey][]int
+	revokes map[bindingKey][]int
+}
+
+// sync reconciles the workspace roles and the mapped project roles of the users keyed by the normalized DNs,
+// and the absent users who are removed from the directory.
+func (s *Syncer) sync(ctx context.Context, provider *ldap.IdentityProvider, config *api.LDAPGroupSyncConfig, users map[string]*store.UserMessage, absentUsers []*store.UserMessage) error {
+	if len(users) == 0 && len(absentUsers) == 0 {
+		return nil
+	}
+	memberDNs := make([]map[string]bool, len(config.GroupMappings))
+	for i, mapping := range config.GroupMappings {
+		var dns []string
+		var err error
+		if mapping.GroupDN != "" {
+			dns, err = provider.ListGroupMemberDNs(mapping.GroupDN)
+		} else {
+			dns, err = provider.ListFilterMemberDNs(mapping.Filter)
+		}
+		if err != nil {
+			return errors.Wrapf(err, "failed to list the members of group mapping %d", i)
+		}
+		memberDNs[i] = make(map[string]bool)
+		for _, dn := range dns {
+			memberDNs[i][dn] = true
+		}
+	}
+
+	result := getSyncResult(config, memberDNs, users, absentUsers)
+	for _, user := range users {
+		if err := s.updateWorkspaceRole(ctx, user, result); err != nil {
+			return err
+		}
+	}
+	for _, user := range absentUsers {
+		if err := s.updateWorkspaceRole(ctx, user, result); err != nil {
+			return err
+		}
+	}
+	for _, key := range result.bindingKeys {
+		if _, err := utils.UpdateProjectRoleMembers(ctx, s.store, key.project, key.role, result.grants[key], result.revokes[key]); err != nil {
+			return errors.Wrapf(err, "failed to update role %s in project %s", key.role, key.project)
+		}
+	}
+	return nil
+}
+
+func (s *Syncer) updateWorkspaceRole(ctx context.Context, user *store.UserMessage, result *syncResult) error {
+	workspaceRole, ok := result.workspaceRoles[user.ID]
+	if !ok {
+		return nil
+	}
+	if err := utils.UpdateUserWorkspaceRole(ctx, s.store, user, workspaceRole); err != nil {
+		return errors.Wrapf(err, "failed to update the workspace role of user %q", user.Email)
+	}
+	return nil
+}
+
+// getSyncResult reconciles the roles of the users with the members of the group mappings.
+// The highest mapped workspace role wins, and the users without any mapped workspace role get the default workspace role.
+func getSyncResult(config *api.LDAPGroupSyncConfig, memberDNs []map[string]bool, users map[string]*store.UserMessage, absentUsers []*store.UserMessage) *syncResult {
+	result := &syncResult{
+		workspaceRoles: map[int]api.Role{},
+		grants:         map[bindingKey][]int{},
+		revokes:        map[bindingKey][]int{},
+	}
+	for _, mapping := range config.GroupMappings {
+		if mapping.Project != "" {
+			if key := (bindingKey{project: mapping.Project, role: mapping.Role}); !slices.Contains(result.bindingKeys, key) {
+				result.bindingKeys = append(result.bindingKeys, key)
+			}
+		}
+	}
+
+	reconcile := func(user *store.UserMessage, dn string) {
+		var workspaceRole api.Role
+		granted := map[bindingKey]bool{}
+		for i, mapping := range config.GroupMappings {
+			if dn == "" || !memberDNs[i][dn] {
+				continue
+			}
+			workspaceRole = utils.MaxWorkspaceRole(workspaceRole, mapping.WorkspaceRole)
+			if mapping.Project != "" {
+				granted[bindingKey{project: mapping.Project, role: mapping.Role}] = true
+			}
+		}
+		if workspaceRole == "" {
+			workspaceRole = config.DefaultWorkspaceRole
+		}
+		if workspaceRole != "" && workspaceRole != user.Role {
+			result.workspaceRoles[user.ID] = workspaceRole
+		}
+		for _, key := range result.bindingKeys {
+			if granted[key] {
+				result.grants[key] = append(result.grants[key], user.ID)
+			} else {
+				result.revokes[key] = append(result.revokes[key], user.ID)
+			}
+		}
+	}
+	for dn, user := range users {
+		reconcile(user, dn)
+	}
+	for _, user := range absentUsers {
+		reconcile(user, "")
+	}
+	return result
+}
+
+func newIdentityProvider(config *storepb.LDAPIdentityProviderConfig) (*ldap.IdentityProvider, error) {
+	provider, err := ldap.NewIdentityProvider(
+		ldap.IdentityProviderConfig{
+			Host:             config.GetHost(),
+			Port:             int(config.GetPort()),
+			SkipTLSVerify:    config.GetSkipTlsVerify(),
+			BindDN:           config.GetBindDn(),
+			BindPassword:     config.GetBindPassword(),
+			BaseDN:           config.GetBaseDn(),
+			UserFilter:       config.GetUserFilter(),
+			SecurityProtocol: ldap.SecurityProtocol(config.GetSecurityProtocol()),
+			FieldMapping:     config.GetFieldMapping(),
+		},
+	)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create new LDAP identity provider")
+	}
+	return provider, nil
+}
+
+// getEmail returns the email of the user in the same way as signing in with the identity provider.
+func getEmail(idp *store.IdentityProviderMessage, userInfo *storepb.IdentityProviderUserInfo) string {
+	email := strings.ToLower(userInfo.Identifier)
+	if _, err := mail.ParseAddress(email); err == nil {
+		return email
+	}
+	if idp.Domain == "" {
+		return ""
+	}
+	email = strings.ToLower(fmt.Sprintf("%s@%s", userInfo.Identifier, bbidp.ExtractDomain(idp.Domain)))
+	if _, err := mail.ParseAddress(email); err != nil {
+		return ""
+	}
+	return email
+}
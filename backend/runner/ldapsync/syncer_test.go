@@ -0,0 +1,90 @@
+package ldapsync
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/store"
+)
+
+func TestGetSyncResult(t *testing.T) {
+	dba := &api.LDAPGroupMapping{GroupDN: "cn=dba,dc=example,dc=com", WorkspaceRole: api.DBA}
+	hr := &api.LDAPGroupMapping{GroupDN: "cn=hr,dc=example,dc=com", Project: "hr", Role: "roles/DEVELOPER"}
+	groupMemberDNs := map[string]map[string]bool{
+		dba.GroupDN: {"uid=alice,dc=example,dc=com": true},
+		hr.GroupDN:  {"uid=alice,dc=example,dc=com": true, "uid=bob,dc=example,dc=com": true},
+	}
+	alice := &store.UserMessage{ID: 101, Role: api.Developer}
+	bob := &store.UserMessage{ID: 102, Role: api.DBA}
+	carol := &store.UserMessage{ID: 103, Role: api.DBA}
+	users := map[string]*store.UserMessage{
+		"uid=alice,dc=example,dc=com": alice,
+		"uid=bob,dc=example,dc=com":   bob,
+	}
+	hrKey := bindingKey{project: "hr", role: "roles/DEVELOPER"}
+
+	tests := []struct {
+		name           string
+		config         *api.LDAPGroupSyncConfig
+		absentUsers    []*store.UserMessage
+		workspaceRoles map[int]api.Role
+		grants         []int
+		revokes        []int
+	}{
+		{
+			name:   "keep the workspace roles without the default workspace role",
+			config: &api.LDAPGroupSyncConfig{GroupMappings: []*api.LDAPGroupMapping{dba, hr}},
+			workspaceRoles: map[int]api.Role{
+				101: api.DBA,
+			},
+			grants: []int{101, 102},
+		},
+		{
+			name:   "set the default workspace role",
+			config: &api.LDAPGroupSyncConfig{GroupMappings: []*api.LDAPGroupMapping{dba, hr}, DefaultWorkspaceRole: api.Developer},
+			workspaceRoles: map[int]api.Role{
+				101: api.DBA,
+				102: api.Developer,
+			},
+			grants: []int{101, 102},
+		},
+		{
+			name:        "revoke the absent users",
+			config:      &api.LDAPGroupSyncConfig{GroupMappings: []*api.LDAPGroupMapping{dba, hr}, DefaultWorkspaceRole: api.Developer},
+			absentUsers: []*store.UserMessage{carol},
+			workspaceRoles: map[int]api.Role{
+				101: api.DBA,
+				102: api.Developer,
+				103: api.Developer,
+			},
+			grants:  []int{101, 102},
+			revokes: []int{103},
+		},
+		{
+			name:        "revoke the absent users without the default workspace role",
+			config:      &api.LDAPGroupSyncConfig{GroupMappings: []*api.LDAPGroupMapping{hr}},
+			absentUsers: []*store.UserMessage{carol},
+			grants:      []int{101, 102},
+			revokes:     []int{103},
+		},
+	}
+
+	a := require.New(t)
+	for _, test := range tests {
+		var memberDNs []map[string]bool
+		for _, mapping := range test.config.GroupMappings {
+			memberDNs = append(memberDNs, groupMemberDNs[mapping.GroupDN])
+		}
+		result := getSyncResult(test.config, memberDNs, users, test.absentUsers)
+		workspaceRoles := test.workspaceRoles
+		if workspaceRoles == nil {
+			workspaceRoles = map[int]api.Role{}
+		}
+		a.Equal(workspaceRoles, result.workspaceRoles, test.name)
+		a.Equal([]bindingKey{hrKey}, result.bindingKeys, test.name)
+		a.ElementsMatch(test.grants, result.grants[hrKey], test.name)
+		a.ElementsMatch(test.revokes, result.revokes[hrKey], test.name)
+	}
+}
@@ -0,0 +1,260 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/bytebase/bytebase/backend/common/log"
+	"github.com/bytebase/bytebase/backend/component/activity"
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/store"
+	storepb "github.com/bytebase/bytebase/proto/generated-go/store"
+)
+
+const (
+	issueMailInterval = 30 * time.Second
+	// issueDigestQuietPeriod is the period without new notifications before a recipient's digest is sent,
+	// so that the notifications of a burst, e.g. the task failures of a rollout to many databases, are sent in one email.
+	issueDigestQuietPeriod = 2 * time.Minute
+	// issueDigestMaxDelay is the max delay of a notification, the digest is sent even if new notifications keep coming.
+	issueDigestMaxDelay = 15 * time.Minute
+	// issueMailBatchSize is the max number of the recipients handled in one round.
+	// All pending notifications of a recipient are handled together, so that a burst is never split into several emails.
+	issueMailBatchSize = 100
+	// issueMailMaxAttempts is the max number of the sending attempts before the notifications are failed.
+	issueMailMaxAttempts = 3
+	// issueMailRetryInterval is the delay before retrying a failed digest, multiplied by the number of the attempts.
+	issueMailRetryInterval = 5 * time.Minute
+)
+
+var (
+	//go:embed templates/issue/digest.html
+	issueDigestTemplateContent string
+	issueDigestTemplate        = template.Must(template.New("digest").Parse(issueDigestTemplateContent))
+)
+
+// NewIssueMailSender creates a new issue mail sender.
+func NewIssueMailSender(store *store.Store) *IssueMailSender {
+	return &IssueMailSender{
+		store: store,
+	}
+}
+
+// IssueMailSender sends the pending issue email notifications of each recipient in one digest email.
+type IssueMailSender struct {
+	store *store.Store
+}
+
+// Run will run the issue mail sender.
+func (s *IssueMailSender) Run(ctx context.Context, wg *sync.WaitGroup) {
+	ticker := time.NewTicker(issueMailInterval)
+	defer ticker.Stop()
+	defer wg.Done()
+	slog.Debug(fmt.Sprintf("Issue mail sender started and will run every %s", issueMailInterval.String()))
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Debug("Issue mail sender received context cancellation")
+			return
+		case <-ticker.C:
+			s.sendDigests(ctx, time.Now())
+		}
+	}
+}
+
+func (s *IssueMailSender) sendDigests(ctx context.Context, now time.Time) {
+	defer func() {
+		if r := recover(); r != nil {
+			err, ok := r.(error)
+			if !ok {
+				err = errors.Errorf("%v", r)
+			}
+			slog.Error("Issue mail sender PANIC RECOVER", log.BBError(err), log.BBStack("panic-stack"))
+		}
+	}()
+
+	recipientIDs, err := s.store.ListPendingEmailNotificationRecipientIDs(ctx, issueMailBatchSize)
+	if err != nil {
+		slog.Error("Failed to list the recipients of pending email notifications", log.BBError(err))
+		return
+	}
+
+	var mailSetting *api.SettingWorkspaceMailDeliveryValue
+	for _, recipientID := range recipientIDs {
+		status := store.EmailNotificationPending
+		notifications, err := s.store.ListEmailNotifications(ctx, &store.FindEmailNotificationMessage{
+			RecipientID: &recipientID,
+			Status:      &status,
+		})
+		if err != nil {
+			slog.Error("Failed to list pending email notifications", slog.Int("recipient_id", recipientID), log.BBError(err))
+			continue
+		}
+		if !isIssueDigestDue(notifications, now) {
+			continue
+		}
+
+		if mailSetting == nil {
+			if mailSetting, err = s.getMailDeliverySetting(ctx); err != nil {
+				slog.Error("Failed to get mail delivery setting", log.BBError(err))
+				return
+			}
+		}
+		if err := s.sendDigest(ctx, mailSetting, recipientID, notifications); err != nil {
+			slog.Warn("Failed to send issue digest email", slog.Int("recipient_id", recipientID), log.BBError(err))
+			errorMessage := err.Error()
+			retryIDs, failedIDs := getFailedNotificationIDs(notifications)
+			if err := s.store.BatchUpdateEmailNotificationStatus(ctx, retryIDs, store.EmailNotificationPending, errorMessage); err != nil {
+				slog.Error("Failed to update email notification status", slog.Int("recipient_id", recipientID), log.BBError(err))
+			}
+			if err := s.store.BatchUpdateEmailNotificationStatus(ctx, failedIDs, store.EmailNotificationFailed, errorMessage); err != nil {
+				slog.Error("Failed to update email notification status", slog.Int("recipient_id", recipientID), log.BBError(err))
+			}
+			continue
+		}
+		var ids []int64
+		for _, notification := range notifications {
+			ids = append(ids, notification.ID)
+		}
+		if err := s.store.BatchUpdateEmailNotificationStatus(ctx, ids, store.EmailNotificationSent, ""); err != nil {
+			slog.Error("Failed to update email notification status", slog.Int("recipient_id", recipientID), log.BBError(err))
+		}
+	}
+}
+
+// isIssueDigestDue returns true if the digest of the pending notifications of a recipient should be sent now.
+// The notifications are in the ascending order of the ID.
+func isIssueDigestDue(notifications []*store.EmailNotificationMessage, now time.Time) bool {
+	if len(notifications) == 0 {
+		return false
+	}
+	oldest, newest := notifications[0], notifications[len(notifications)-1]
+	if now.Sub(time.Unix(newest.CreatedTs, 0)) < issueDigestQuietPeriod && now.Sub(time.Unix(oldest.CreatedTs, 0)) < issueDigestMaxDelay {
+		return false
+	}
+	for _, notification := range notifications {
+		// Back off after the failed attempts.
+		if notification.Attempts > 0 && now.Sub(time.Unix(notification.UpdatedTs, 0)) < time.Duration(notification.Attempts)*issueMailRetryInterval {
+			return false
+		}
+	}
+	return true
+}
+
+// getFailedNotificationIDs returns the IDs of the notifications to retry and the ones failed after the max attempts.
+func getFailedNotificationIDs(notifications []*store.EmailNotificationMessage) ([]int64, []int64) {
+	var retryIDs, failedIDs []int64
+	for _, notification := range notifications {
+		if notification.Attempts+1 < issueMailMaxAttempts {
+			retryIDs = append(retryIDs, notification.ID)
+		} else {
+			failedIDs = append(failedIDs, notification.ID)
+		}
+	}
+	return retryIDs, failedIDs
+}
+
+func (s *IssueMailSender) sendDigest(ctx context.Context, mailSetting *api.SettingWorkspaceMailDeliveryValue, recipientID int, notifications []*store.EmailNotificationMessage) error {
+	if mailSetting.SMTPServerHost == "" {
+		return errors.New("mail delivery is not configured")
+	}
+	recipient, err := s.store.GetUserByID(ctx, recipientID)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get recipient")
+	}
+	if recipient == nil || recipient.MemberDeleted {
+		return errors.Errorf("recipient %d not found", recipientID)
+	}
+	subject, body, err := renderIssueDigest(recipient.Name, notifications)
+	if err != nil {
+		return err
+	}
+	setting := *mailSetting
+	setting.SMTPTo = recipient.Email
+	return send(&setting, subject, body)
+}
+
+func (s *IssueMailSender) getMailDeliverySetting(ctx context.Context) (*api.SettingWorkspaceMailDeliveryValue, error) {
+	name := api.SettingWorkspaceMailDelivery
+	mailSetting, err := s.store.GetSettingV2(ctx, &store.FindSettingMessage{Name: &name})
+	if err != nil {
+		return nil, err
+	}
+	// The notifications are failed by sendDigest if the mail delivery is removed after they are created.
+	if mailSetting == nil {
+		return &api.SettingWorkspaceMailDeliveryValue{}, nil
+	}
+	var storeValue storepb.SMTPMailDeliverySetting
+	if err := protojson.Unmarshal([]byte(mailSetting.Value), &storeValue); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal setting value")
+	}
+	return convertStorepbToAPIMailDeliveryValue(&storeValue), nil
+}
+
+type issueDigest struct {
+	RecipientName string
+	Count         int
+	Issues        []*issueDigestIssue
+}
+
+type issueDigestIssue struct {
+	Name        string
+	ProjectName string
+	Link        string
+	Items       []*activity.EmailNotificationPayload
+}
+
+// renderIssueDigest renders the subject and the HTML body of the digest email, the notifications are grouped by the issue.
+func renderIssueDigest(recipientName string, notifications []*store.EmailNotificationMessage) (string, string, error) {
+	digest := &issueDigest{
+		RecipientName: recipientName,
+		Count:         len(notifications),
+	}
+	issueMap := map[int]*issueDigestIssue{}
+	for _, notification := range notifications {
+		payload := &activity.EmailNotificationPayload{}
+		if err := json.Unmarshal([]byte(notification.Payload), payload); err != nil {
+			return "", "", errors.Wrapf(err, "failed to unmarshal email notification %d", notification.ID)
+		}
+		issue, ok := issueMap[notification.IssueUID]
+		if !ok {
+			// Link to the issue instead of the comment.
+			link, _, _ := strings.Cut(payload.Link, "#")
+			issue = &issueDigestIssue{
+				Name:        payload.IssueName,
+				ProjectName: payload.ProjectName,
+				Link:        link,
+			}
+			issueMap[notification.IssueUID] = issue
+			digest.Issues = append(digest.Issues, issue)
+		}
+		issue.Items = append(issue.Items, payload)
+	}
+
+	var subject string
+	switch {
+	case digest.Count == 1:
+		subject = fmt.Sprintf("[Bytebase] %s - %s", digest.Issues[0].Items[0].Title, digest.Issues[0].Name)
+	case len(digest.Issues) == 1:
+		subject = fmt.Sprintf("[Bytebase] %d notifications on issue %s", digest.Count, digest.Issues[0].Name)
+	default:
+		subject = fmt.Sprintf("[Bytebase] %d notifications on %d issues", digest.Count, len(digest.Issues))
+	}
+
+	var body bytes.Buffer
+	if err := issueDigestTemplate.Execute(&body, digest); err != nil {
+		return "", "", errors.Wrapf(err, "failed to render the digest email")
+	}
+	return subject, body.String(), nil
+}
@@ -0,0 +1,158 @@
+package mail
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/backend/component/activity"
+	"github.com/bytebase/bytebase/backend/store"
+)
+
+func newEmailNotification(t *testing.T, id int64, issueUID int, payload *activity.EmailNotificationPayload) *store.EmailNotificationMessage {
+	payloadBytes, err := json.Marshal(payload)
+	require.NoError(t, err)
+	return &store.EmailNotificationMessage{
+		ID:       id,
+		IssueUID: issueUID,
+		Payload:  string(payloadBytes),
+	}
+}
+
+func TestRenderIssueDigest(t *testing.T) {
+	created := newEmailNotification(t, 101, 1, &activity.EmailNotificationPayload{
+		Title:       "Issue created",
+		IssueName:   "Add column",
+		ProjectName: "HR",
+		Link:        "https://bb.example.com/issue/add-column-1",
+		ActorName:   "Alice",
+	})
+	mentioned := newEmailNotification(t, 102, 1, &activity.EmailNotificationPayload{
+		Title:       "Mentioned in a comment",
+		Description: "@bob@example.com <b>please review</b>",
+		IssueName:   "Add column",
+		ProjectName: "HR",
+		Link:        "https://bb.example.com/issue/add-column-1#activity7",
+		ActorName:   "Alice",
+	})
+	failed := newEmailNotification(t, 103, 2, &activity.EmailNotificationPayload{
+		Title:       "Task failed - db1",
+		IssueName:   "Drop table",
+		ProjectName: "Sales",
+		Link:        "https://bb.example.com/issue/drop-table-2",
+		ActorName:   "Carol",
+	})
+
+	tests := []struct {
+		notifications []*store.EmailNotificationMessage
+		subject       string
+		contains      []string
+	}{
+		{
+			notifications: []*store.EmailNotificationMessage{created},
+			subject:       "[Bytebase] Issue created - Add column",
+			contains:      []string{"1 new notification in Bytebase", "Hi Bob", "Project HR"},
+		},
+		{
+			notifications: []*store.EmailNotificationMessage{created, mentioned},
+			subject:       "[Bytebase] 2 notifications on issue Add column",
+			contains: []string{
+				"2 new notifications in Bytebase",
+				// The issue links to the issue instead of the comment.
+				`<a href="https://bb.example.com/issue/add-column-1">Add column</a>`,
+				`<a href="https://bb.example.com/issue/add-column-1#activity7">Mentioned in a comment</a>`,
+				// The comments are escaped.
+				"@bob@example.com &lt;b&gt;please review&lt;/b&gt;",
+			},
+		},
+		{
+			notifications: []*store.EmailNotificationMessage{created, failed, mentioned},
+			subject:       "[Bytebase] 3 notifications on 2 issues",
+			contains:      []string{"Project HR", "Project Sales", "Task failed - db1", "by Carol"},
+		},
+	}
+
+	a := require.New(t)
+	for _, test := range tests {
+		subject, body, err := renderIssueDigest("Bob", test.notifications)
+		a.NoError(err)
+		a.Equal(test.subject, subject)
+		for _, s := range test.contains {
+			a.Contains(body, s, test.subject)
+		}
+	}
+
+	_, _, err := renderIssueDigest("Bob", []*store.EmailNotificationMessage{{ID: 104, Payload: "{"}})
+	a.Error(err)
+}
+
+func TestIsIssueDigestDue(t *testing.T) {
+	now := time.Date(2023, 11, 16, 8, 0, 0, 0, time.UTC)
+	ago := func(d time.Duration) int64 {
+		return now.Add(-d).Unix()
+	}
+	tests := []struct {
+		name          string
+		notifications []*store.EmailNotificationMessage
+		want          bool
+	}{
+		{
+			name: "no notification",
+		},
+		{
+			name: "within the quiet period",
+			notifications: []*store.EmailNotificationMessage{
+				{ID: 1, CreatedTs: ago(5 * time.Minute)},
+				{ID: 2, CreatedTs: ago(time.Minute)},
+			},
+		},
+		{
+			name: "after the quiet period",
+			notifications: []*store.EmailNotificationMessage{
+				{ID: 1, CreatedTs: ago(5 * time.Minute)},
+				{ID: 2, CreatedTs: ago(3 * time.Minute)},
+			},
+			want: true,
+		},
+		{
+			name: "after the max delay",
+			notifications: []*store.EmailNotificationMessage{
+				{ID: 1, CreatedTs: ago(20 * time.Minute)},
+				{ID: 2, CreatedTs: ago(time.Minute)},
+			},
+			want: true,
+		},
+		{
+			name: "within the retry interval",
+			notifications: []*store.EmailNotificationMessage{
+				{ID: 1, CreatedTs: ago(20 * time.Minute), UpdatedTs: ago(7 * time.Minute), Attempts: 2},
+			},
+		},
+		{
+			name: "after the retry interval",
+			notifications: []*store.EmailNotificationMessage{
+				{ID: 1, CreatedTs: ago(20 * time.Minute), UpdatedTs: ago(11 * time.Minute), Attempts: 2},
+				{ID: 2, CreatedTs: ago(3 * time.Minute)},
+			},
+			want: true,
+		},
+	}
+
+	a := require.New(t)
+	for _, test := range tests {
+		a.Equal(test.want, isIssueDigestDue(test.notifications, now), test.name)
+	}
+}
+
+func TestGetFailedNotificationIDs(t *testing.T) {
+	a := require.New(t)
+	retryIDs, failedIDs := getFailedNotificationIDs([]*store.EmailNotificationMessage{
+		{ID: 1, Attempts: issueMailMaxAttempts - 1},
+		{ID: 2},
+		{ID: 3, Attempts: issueMailMaxAttempts - 2},
+	})
+	a.Equal([]int64{2, 3}, retryIDs)
+	a.Equal([]int64{1}, failedIDs)
+}
@@ -1,4 +1,4 @@
-// Package mail contains the slow query weekly mail sender.
+// Package mail contains the slow query weekly mail sender and the issue mail sender.
 package mail
 
 import (
@@ -435,7 +435,7 @@ func send(mailSetting *api.SettingWorkspaceMailDeliveryValue, subject string, bo
 	if err := client.SendMail(email); err != nil {
 		return err
 	}
-	slog.Debug("Successfully sent need configure slow query policy email", slog.String("to", mailSetting.SMTPTo), slog.String("subject", subject))
+	slog.Debug("Successfully sent email", slog.String("to", mailSetting.SMTPTo), slog.String("subject", subject))
 	return nil
 }
 
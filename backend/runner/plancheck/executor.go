@@ -51,7 +51,16 @@ func isStatementAdviseSupported(dbType storepb.Engine) bool {
 
 func isStatementReportSupported(dbType storepb.Engine) bool {
 	switch dbType {
-	case storepb.Engine_POSTGRES, storepb.Engine_MYSQL, storepb.Engine_OCEANBASE, storepb.Engine_ORACLE, storepb.Engine_OCEANBASE_ORACLE:
+	case storepb.Engine_POSTGRES, storepb.Engine_MYSQL, storepb.Engine_OCEANBASE, storepb.Engine_ORACLE, storepb.Engine_OCEANBASE_ORACLE, storepb.Engine_TIDB, storepb.Engine_MSSQL, storepb.Engine_SNOWFLAKE:
+		return true
+	default:
+		return false
+	}
+}
+
+func isShadowDryRunSupported(dbType storepb.Engine) bool {
+	switch dbType {
+	case storepb.Engine_POSTGRES, storepb.Engine_MYSQL, storepb.Engine_MARIADB, storepb.Engine_TIDB:
 		return true
 	default:
 		return false
@@ -8,7 +8,9 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/bytebase/bytebase/backend/common"
+	"github.com/bytebase/bytebase/backend/component/dbfactory"
 	"github.com/bytebase/bytebase/backend/component/ghost"
+	"github.com/bytebase/bytebase/backend/component/pgosc"
 	api "github.com/bytebase/bytebase/backend/legacyapi"
 	"github.com/bytebase/bytebase/backend/store"
 	"github.com/bytebase/bytebase/backend/utils"
@@ -16,17 +18,19 @@ import (
 )
 
 // NewGhostSyncExecutor creates a gh-ost sync check executor.
-func NewGhostSyncExecutor(store *store.Store, secret string) Executor {
+func NewGhostSyncExecutor(store *store.Store, dbFactory *dbfactory.DBFactory, secret string) Executor {
 	return &GhostSyncExecutor{
-		store:  store,
-		secret: secret,
+		store:     store,
+		dbFactory: dbFactory,
+		secret:    secret,
 	}
 }
 
 // GhostSyncExecutor is the gh-ost sync check executor.
 type GhostSyncExecutor struct {
-	store  *store.Store
-	secret string
+	store     *store.Store
+	dbFactory *dbfactory.DBFactory
+	secret    string
 }
 
 // Run runs the gh-ost sync check executor.
@@ -97,6 +101,10 @@ func (e *GhostSyncExecutor) Run(ctx context.Context, config *storepb.PlanCheckRu
 	// To avoid leaking the rendered statement, the error message should use the original statement and not the rendered statement.
 	renderedStatement := utils.RenderStatement(statement, materials)
 
+	if instance.Engine == storepb.Engine_POSTGRES {
+		return e.runPostgresDryRun(ctx, instance, database, renderedStatement, config.GhostFlags)
+	}
+
 	tableName, err := ghost.GetTableNameFromStatement(renderedStatement)
 	if err != nil {
 		return nil, common.Wrapf(err, common.Internal, "failed to parse table name from statement, statement: %v", statement)
@@ -131,3 +139,38 @@ func (e *GhostSyncExecutor) Run(ctx context.Context, config *storepb.PlanCheckRu
 		},
 	}, nil
 }
+
+// runPostgresDryRun validates the PostgreSQL online schema change by altering the shadow table in a rolled back transaction.
+func (e *GhostSyncExecutor) runPostgresDryRun(ctx context.Context, instance *store.InstanceMessage, database *store.DatabaseMessage, statement string, flags map[string]string) ([]*storepb.PlanCheckRunResult_Result, error) {
+	driver, err := e.dbFactory.GetAdminDatabaseDriver(ctx, instance, database)
+	if err != nil {
+		return nil, err
+	}
+	defer driver.Close(ctx)
+
+	migration, err := pgosc.NewMigration(ctx, driver.GetDB(), statement, flags)
+	if err == nil {
+		err = migration.DryRun(ctx)
+	}
+	if err != nil {
+		return []*storepb.PlanCheckRunResult_Result{
+			{
+				Status:  storepb.PlanCheckRunResult_Result_ERROR,
+				Title:   "Online schema change dry run failed",
+				Content: err.Error(),
+				Code:    common.Internal.Int32(),
+				Report:  nil,
+			},
+		}, nil
+	}
+
+	return []*storepb.PlanCheckRunResult_Result{
+		{
+			Status:  storepb.PlanCheckRunResult_Result_SUCCESS,
+			Title:   "OK",
+			Content: "Online schema change dry run succeeded",
+			Code:    common.Ok.Int32(),
+			Report:  nil,
+		},
+	}, nil
+}
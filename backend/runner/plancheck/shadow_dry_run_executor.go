@@ -0,0 +1,287 @@
+package plancheck
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/backend/common"
+	"github.com/bytebase/bytebase/backend/common/log"
+	"github.com/bytebase/bytebase/backend/component/dbfactory"
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/plugin/db"
+	"github.com/bytebase/bytebase/backend/plugin/db/util"
+	"github.com/bytebase/bytebase/backend/plugin/parser/base"
+	"github.com/bytebase/bytebase/backend/store"
+	"github.com/bytebase/bytebase/backend/utils"
+	storepb "github.com/bytebase/bytebase/proto/generated-go/store"
+)
+
+// NewShadowDryRunExecutor creates a shadow database dry run executor.
+func NewShadowDryRunExecutor(store *store.Store, dbFactory *dbfactory.DBFactory) Executor {
+	return &ShadowDryRunExecutor{
+		store:     store,
+		dbFactory: dbFactory,
+	}
+}
+
+// ShadowDryRunExecutor is the shadow database dry run executor.
+// It clones the schema of the target database into an ephemeral shadow database,
+// executes the statement there and drops the shadow database afterwards.
+type ShadowDryRunExecutor struct {
+	store     *store.Store
+	dbFactory *dbfactory.DBFactory
+}
+
+// Run runs the shadow database dry run executor.
+func (e *ShadowDryRunExecutor) Run(ctx context.Context, config *storepb.PlanCheckRunConfig) ([]*storepb.PlanCheckRunResult_Result, error) {
+	if config.DatabaseGroupUid != nil {
+		return nil, errors.Errorf("database group is not supported")
+	}
+
+	instanceUID := int(config.InstanceUid)
+	instance, err := e.store.GetInstanceV2(ctx, &store.FindInstanceMessage{UID: &instanceUID})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get instance UID %v", instanceUID)
+	}
+	if instance == nil {
+		return nil, errors.Errorf("instance not found UID %v", instanceUID)
+	}
+	if !isShadowDryRunSupported(instance.Engine) {
+		return []*storepb.PlanCheckRunResult_Result{
+			{
+				Status:  storepb.PlanCheckRunResult_Result_SUCCESS,
+				Code:    common.Ok.Int32(),
+				Title:   fmt.Sprintf("Shadow dry run is not supported for %s", instance.Engine),
+				Content: "",
+			},
+		}, nil
+	}
+
+	database, err := e.store.GetDatabaseV2(ctx, &store.FindDatabaseMessage{InstanceID: &instance.ResourceID, DatabaseName: &config.DatabaseName})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get database %q", config.DatabaseName)
+	}
+	if database == nil {
+		return nil, errors.Errorf("database not found %q", config.DatabaseName)
+	}
+
+	sheetUID := int(config.SheetUid)
+	sheet, err := e.store.GetSheet(ctx, &store.FindSheetMessage{UID: &sheetUID}, api.SystemBotID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get sheet %d", sheetUID)
+	}
+	if sheet == nil {
+		return nil, errors.Errorf("sheet %d not found", sheetUID)
+	}
+	if sheet.Size > common.MaxSheetSizeForTaskCheck {
+		return []*storepb.PlanCheckRunResult_Result{
+			{
+				Status:  storepb.PlanCheckRunResult_Result_SUCCESS,
+				Code:    common.Ok.Int32(),
+				Title:   "Shadow dry run for large SQL is not supported",
+				Content: "",
+			},
+		}, nil
+	}
+	statement, err := e.store.GetSheetStatementByID(ctx, sheetUID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get sheet statement %d", sheetUID)
+	}
+	materials := utils.GetSecretMapFromDatabaseMessage(database)
+	renderedStatement := utils.RenderStatement(statement, materials)
+
+	sandboxInstance, err := e.getSandboxInstance(ctx, instance)
+	if err != nil {
+		return nil, err
+	}
+	if sandboxInstance == nil {
+		return []*storepb.PlanCheckRunResult_Result{
+			{
+				Status:  storepb.PlanCheckRunResult_Result_WARNING,
+				Code:    common.Ok.Int32(),
+				Title:   fmt.Sprintf("No sandbox instance for %s", instance.Engine),
+				Content: "Shadow dry run is skipped. Configure a sandbox instance with the same engine, or allow the target instance in the shadow database setting.",
+			},
+		}, nil
+	}
+
+	schema, err := e.dumpSchema(ctx, instance, database)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to dump schema of database %q", database.DatabaseName)
+	}
+
+	random, err := common.RandomString(8)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to generate shadow database name")
+	}
+	// Lower the case for the instances with case-insensitive database names.
+	shadowDatabaseName := util.GetShadowDatabaseName(database.DatabaseName, time.Now().Unix(), strings.ToLower(random))
+	if err := e.createShadowDatabase(ctx, sandboxInstance, shadowDatabaseName); err != nil {
+		return nil, errors.Wrapf(err, "failed to create shadow database %q", shadowDatabaseName)
+	}
+	defer func() {
+		if err := e.dropShadowDatabase(ctx, sandboxInstance, shadowDatabaseName); err != nil {
+			slog.Error("failed to drop shadow database", slog.String("instance", sandboxInstance.ResourceID), slog.String("database", shadowDatabaseName), log.BBError(err))
+		}
+	}()
+
+	shadowDatabase := &store.DatabaseMessage{
+		InstanceID:   sandboxInstance.ResourceID,
+		DatabaseName: shadowDatabaseName,
+	}
+	dryRunResult, err := e.dryRun(ctx, sandboxInstance, shadowDatabase, schema, renderedStatement)
+	if err != nil {
+		return nil, err
+	}
+	if dryRunResult.executeErr != nil {
+		return []*storepb.PlanCheckRunResult_Result{
+			{
+				Status:  storepb.PlanCheckRunResult_Result_ERROR,
+				Code:    common.DbExecutionError.Int32(),
+				Title:   "Shadow dry run failed",
+				Content: fmt.Sprintf("Failed after %v on shadow database %q of instance %q: %v", dryRunResult.duration, shadowDatabaseName, sandboxInstance.Title, dryRunResult.executeErr),
+			},
+		}, nil
+	}
+
+	content := fmt.Sprintf("Executed in %v on shadow database %q of instance %q.", dryRunResult.duration, shadowDatabaseName, sandboxInstance.Title)
+	diff, err := base.SchemaDiff(instance.Engine, schema, dryRunResult.schema, store.IgnoreDatabaseAndTableCaseSensitive(instance))
+	if err != nil {
+		slog.Warn("failed to compute shadow database schema diff", slog.String("database", database.DatabaseName), log.BBError(err))
+	} else if diff != "" {
+		content = fmt.Sprintf("%s\n\nSchema diff:\n%s", content, diff)
+	}
+	return []*storepb.PlanCheckRunResult_Result{
+		{
+			Status:  storepb.PlanCheckRunResult_Result_SUCCESS,
+			Code:    common.Ok.Int32(),
+			Title:   "OK",
+			Content: content,
+		},
+	}, nil
+}
+
+// getSandboxInstance returns the instance hosting the shadow database, or nil if there is none.
+func (e *ShadowDryRunExecutor) getSandboxInstance(ctx context.Context, instance *store.InstanceMessage) (*store.InstanceMessage, error) {
+	setting, err := e.store.GetShadowDatabaseSetting(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var sandboxInstances []*store.InstanceMessage
+	for _, instanceID := range setting.SandboxInstanceIDs {
+		sandboxInstance, err := e.store.GetInstanceV2(ctx, &store.FindInstanceMessage{ResourceID: &instanceID})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get sandbox instance %q", instanceID)
+		}
+		if sandboxInstance != nil {
+			sandboxInstances = append(sandboxInstances, sandboxInstance)
+		}
+	}
+	return pickSandboxInstance(instance, sandboxInstances, setting.AllowTargetInstance), nil
+}
+
+// pickSandboxInstance returns the first sandbox instance with the same engine as the target instance.
+// It falls back to the target instance only if allowTargetInstance is true.
+func pickSandboxInstance(instance *store.InstanceMessage, sandboxInstances []*store.InstanceMessage, allowTargetInstance bool) *store.InstanceMessage {
+	for _, sandboxInstance := range sandboxInstances {
+		if sandboxInstance.Engine == instance.Engine {
+			return sandboxInstance
+		}
+	}
+	if allowTargetInstance {
+		return instance
+	}
+	return nil
+}
+
+func (e *ShadowDryRunExecutor) dumpSchema(ctx context.Context, instance *store.InstanceMessage, database *store.DatabaseMessage) (string, error) {
+	driver, err := e.dbFactory.GetAdminDatabaseDriver(ctx, instance, database)
+	if err != nil {
+		return "", err
+	}
+	defer driver.Close(ctx)
+
+	var buf bytes.Buffer
+	if _, err := driver.Dump(ctx, &buf, true /* schemaOnly */); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+type shadowDryRunResult struct {
+	// executeErr is the error returned by the database when executing the statement.
+	executeErr error
+	duration   time.Duration
+	// schema is the schema of the shadow database after executing the statement.
+	schema string
+}
+
+// dryRun restores the schema into the shadow database and executes the statement.
+func (e *ShadowDryRunExecutor) dryRun(ctx context.Context, instance *store.InstanceMessage, shadowDatabase *store.DatabaseMessage, schema, statement string) (*shadowDryRunResult, error) {
+	driver, err := e.dbFactory.GetAdminDatabaseDriver(ctx, instance, shadowDatabase)
+	if err != nil {
+		return nil, err
+	}
+	defer driver.Close(ctx)
+
+	if err := driver.Restore(ctx, bytes.NewBufferString(schema)); err != nil {
+		return nil, errors.Wrapf(err, "failed to restore schema to shadow database %q", shadowDatabase.DatabaseName)
+	}
+
+	start := time.Now()
+	if _, err := driver.Execute(ctx, statement, false /* createDatabase */, db.ExecuteOptions{}); err != nil {
+		return &shadowDryRunResult{executeErr: err, duration: time.Since(start)}, nil
+	}
+	duration := time.Since(start)
+
+	var buf bytes.Buffer
+	if _, err := driver.Dump(ctx, &buf, true /* schemaOnly */); err != nil {
+		return nil, errors.Wrapf(err, "failed to dump schema of shadow database %q", shadowDatabase.DatabaseName)
+	}
+	return &shadowDryRunResult{duration: duration, schema: buf.String()}, nil
+}
+
+func (e *ShadowDryRunExecutor) createShadowDatabase(ctx context.Context, instance *store.InstanceMessage, databaseName string) error {
+	driver, err := e.dbFactory.GetAdminDatabaseDriver(ctx, instance, nil /* database */)
+	if err != nil {
+		return err
+	}
+	defer driver.Close(ctx)
+
+	quotedName := quoteShadowDatabaseName(instance.Engine, databaseName)
+	// Clean up the dirty shadow database left by a former dry run with the same name.
+	if _, err := driver.GetDB().ExecContext(ctx, fmt.Sprintf("DROP DATABASE IF EXISTS %s;", quotedName)); err != nil {
+		return err
+	}
+	if _, err := driver.GetDB().ExecContext(ctx, fmt.Sprintf("CREATE DATABASE %s;", quotedName)); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (e *ShadowDryRunExecutor) dropShadowDatabase(ctx context.Context, instance *store.InstanceMessage, databaseName string) error {
+	driver, err := e.dbFactory.GetAdminDatabaseDriver(ctx, instance, nil /* database */)
+	if err != nil {
+		return err
+	}
+	defer driver.Close(ctx)
+
+	if _, err := driver.GetDB().ExecContext(ctx, fmt.Sprintf("DROP DATABASE IF EXISTS %s;", quoteShadowDatabaseName(instance.Engine, databaseName))); err != nil {
+		return err
+	}
+	return nil
+}
+
+func quoteShadowDatabaseName(engine storepb.Engine, databaseName string) string {
+	if engine == storepb.Engine_POSTGRES {
+		return pgx.Identifier{databaseName}.Sanitize()
+	}
+	return "`" + strings.ReplaceAll(databaseName, "`", "``") + "`"
+}
@@ -0,0 +1,42 @@
+package plancheck
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/backend/store"
+	storepb "github.com/bytebase/bytebase/proto/generated-go/store"
+)
+
+func TestQuoteShadowDatabaseName(t *testing.T) {
+	tests := []struct {
+		engine storepb.Engine
+		name   string
+		want   string
+	}{
+		{storepb.Engine_POSTGRES, "db_shadow_1", `"db_shadow_1"`},
+		{storepb.Engine_POSTGRES, `a"b`, `"a""b"`},
+		{storepb.Engine_MYSQL, "db_shadow_1", "`db_shadow_1`"},
+		{storepb.Engine_MYSQL, "a`b", "`a``b`"},
+		{storepb.Engine_TIDB, "a`b", "`a``b`"},
+	}
+	a := require.New(t)
+	for _, test := range tests {
+		a.Equal(test.want, quoteShadowDatabaseName(test.engine, test.name))
+	}
+}
+
+func TestPickSandboxInstance(t *testing.T) {
+	a := require.New(t)
+	target := &store.InstanceMessage{ResourceID: "prod", Engine: storepb.Engine_MYSQL}
+	pgSandbox := &store.InstanceMessage{ResourceID: "pg-sandbox", Engine: storepb.Engine_POSTGRES}
+	mysqlSandbox := &store.InstanceMessage{ResourceID: "mysql-sandbox", Engine: storepb.Engine_MYSQL}
+
+	a.Equal(mysqlSandbox, pickSandboxInstance(target, []*store.InstanceMessage{pgSandbox, mysqlSandbox}, false))
+	a.Equal(mysqlSandbox, pickSandboxInstance(target, []*store.InstanceMessage{pgSandbox, mysqlSandbox}, true))
+	// The target instance is only used if allowed.
+	a.Nil(pickSandboxInstance(target, []*store.InstanceMessage{pgSandbox}, false))
+	a.Nil(pickSandboxInstance(target, nil, false))
+	a.Equal(target, pickSandboxInstance(target, []*store.InstanceMessage{pgSandbox}, true))
+}
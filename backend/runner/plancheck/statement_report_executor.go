@@ -8,20 +8,26 @@ import (
 
 	"log/slog"
 
+	"github.com/antlr4-go/antlr/v4"
 	pgquery "github.com/pganalyze/pg_query_go/v4"
 	tidbparser "github.com/pingcap/tidb/parser"
 	tidbast "github.com/pingcap/tidb/parser/ast"
 	"github.com/pkg/errors"
 
+	snowsql "github.com/bytebase/snowsql-parser"
+	tsql "github.com/bytebase/tsql-parser"
+
 	"github.com/bytebase/bytebase/backend/common"
 	"github.com/bytebase/bytebase/backend/common/log"
 	"github.com/bytebase/bytebase/backend/component/dbfactory"
 	api "github.com/bytebase/bytebase/backend/legacyapi"
 	"github.com/bytebase/bytebase/backend/plugin/advisor"
 	"github.com/bytebase/bytebase/backend/plugin/parser/base"
+	snowparser "github.com/bytebase/bytebase/backend/plugin/parser/snowflake"
 	"github.com/bytebase/bytebase/backend/plugin/parser/sql/ast"
 	pgrawparser "github.com/bytebase/bytebase/backend/plugin/parser/sql/engine/pg"
 	"github.com/bytebase/bytebase/backend/plugin/parser/tidb"
+	tsqlparser "github.com/bytebase/bytebase/backend/plugin/parser/tsql"
 	"github.com/bytebase/bytebase/backend/store"
 	"github.com/bytebase/bytebase/backend/utils"
 	storepb "github.com/bytebase/bytebase/proto/generated-go/store"
@@ -127,7 +133,7 @@ func (e *StatementReportExecutor) runForDatabaseTarget(ctx context.Context, conf
 		sqlDB := driver.GetDB()
 
 		return reportForPostgres(ctx, sqlDB, database.DatabaseName, renderedStatement, dbSchema.GetMetadata())
-	case storepb.Engine_MYSQL, storepb.Engine_OCEANBASE:
+	case storepb.Engine_MYSQL, storepb.Engine_OCEANBASE, storepb.Engine_TIDB:
 		driver, err := e.dbFactory.GetAdminDatabaseDriver(ctx, instance, database)
 		if err != nil {
 			return nil, err
@@ -136,6 +142,24 @@ func (e *StatementReportExecutor) runForDatabaseTarget(ctx context.Context, conf
 		sqlDB := driver.GetDB()
 
 		return reportForMySQL(ctx, sqlDB, instance.Engine, database.DatabaseName, renderedStatement, dbSchema.GetMetadata())
+	case storepb.Engine_MSSQL:
+		driver, err := e.dbFactory.GetAdminDatabaseDriver(ctx, instance, database)
+		if err != nil {
+			return nil, err
+		}
+		defer driver.Close(ctx)
+		sqlDB := driver.GetDB()
+
+		return reportForMSSQL(ctx, sqlDB, database.DatabaseName, renderedStatement)
+	case storepb.Engine_SNOWFLAKE:
+		driver, err := e.dbFactory.GetAdminDatabaseDriver(ctx, instance, database)
+		if err != nil {
+			return nil, err
+		}
+		defer driver.Close(ctx)
+		sqlDB := driver.GetDB()
+
+		return reportForSnowflake(ctx, sqlDB, database.DatabaseName, renderedStatement, dbSchema.GetMetadata())
 	case storepb.Engine_ORACLE, storepb.Engine_DM, storepb.Engine_OCEANBASE_ORACLE:
 		schema := ""
 		if instance.Options == nil || !instance.Options.SchemaTenantMode {
@@ -284,7 +308,7 @@ func (e *StatementReportExecutor) runForDatabaseGroupTarget(ctx context.Context,
 					sqlDB := driver.GetDB()
 
 					return reportForPostgres(ctx, sqlDB, database.DatabaseName, renderedStatement, dbSchema.GetMetadata())
-				case storepb.Engine_MYSQL, storepb.Engine_MARIADB, storepb.Engine_OCEANBASE:
+				case storepb.Engine_MYSQL, storepb.Engine_MARIADB, storepb.Engine_OCEANBASE, storepb.Engine_TIDB:
 					driver, err := e.dbFactory.GetAdminDatabaseDriver(ctx, instance, database)
 					if err != nil {
 						return nil, err
@@ -293,6 +317,24 @@ func (e *StatementReportExecutor) runForDatabaseGroupTarget(ctx context.Context,
 					sqlDB := driver.GetDB()
 
 					return reportForMySQL(ctx, sqlDB, instance.Engine, database.DatabaseName, renderedStatement, dbSchema.GetMetadata())
+				case storepb.Engine_MSSQL:
+					driver, err := e.dbFactory.GetAdminDatabaseDriver(ctx, instance, database)
+					if err != nil {
+						return nil, err
+					}
+					defer driver.Close(ctx)
+					sqlDB := driver.GetDB()
+
+					return reportForMSSQL(ctx, sqlDB, database.DatabaseName, renderedStatement)
+				case storepb.Engine_SNOWFLAKE:
+					driver, err := e.dbFactory.GetAdminDatabaseDriver(ctx, instance, database)
+					if err != nil {
+						return nil, err
+					}
+					defer driver.Close(ctx)
+					sqlDB := driver.GetDB()
+
+					return reportForSnowflake(ctx, sqlDB, database.DatabaseName, renderedStatement, dbSchema.GetMetadata())
 				case storepb.Engine_ORACLE, storepb.Engine_DM, storepb.Engine_OCEANBASE_ORACLE:
 					schema := ""
 					if instance.Options == nil || !instance.Options.SchemaTenantMode {
@@ -384,6 +426,187 @@ func reportForOracle(databaseName string, schemaName string, statement string) (
 	}, nil
 }
 
+// getDefaultSchema returns the default schema of the connection, or the fallback schema if the connection has no default schema.
+func getDefaultSchema(ctx context.Context, sqlDB *sql.DB, query string, fallback string) (string, error) {
+	var schema sql.NullString
+	if err := sqlDB.QueryRowContext(ctx, query).Scan(&schema); err != nil {
+		return "", errors.Wrapf(err, "failed to get the default schema")
+	}
+	if !schema.Valid || schema.String == "" {
+		return fallback, nil
+	}
+	return schema.String, nil
+}
+
+func reportForMSSQL(ctx context.Context, sqlDB *sql.DB, databaseName string, statement string) ([]*storepb.PlanCheckRunResult_Result, error) {
+	singleSQLs, err := base.SplitMultiSQL(storepb.Engine_MSSQL, statement)
+	if err != nil {
+		// nolint:nilerr
+		return []*storepb.PlanCheckRunResult_Result{
+			{
+				Status:  storepb.PlanCheckRunResult_Result_ERROR,
+				Title:   "Syntax error",
+				Content: err.Error(),
+				Code:    0,
+				Report: &storepb.PlanCheckRunResult_Result_SqlSummaryReport_{
+					SqlSummaryReport: &storepb.PlanCheckRunResult_Result_SqlSummaryReport{
+						Code: advisor.StatementSyntaxError.Int32(),
+					},
+				},
+			},
+		}, nil
+	}
+
+	// The unqualified objects are resolved in the default schema of the user, which is not always dbo.
+	schemaName, err := getDefaultSchema(ctx, sqlDB, "SELECT SCHEMA_NAME()", "dbo")
+	if err != nil {
+		return nil, err
+	}
+
+	sqlTypeSet := map[string]struct{}{}
+	var totalAffectedRows int64
+	var changedResources []base.SchemaResource
+
+	for _, stmt := range singleSQLs {
+		if stmt.Empty || stmt.Text == "" {
+			continue
+		}
+		parseResult, err := tsqlparser.ParseTSQL(stmt.Text)
+		if err != nil {
+			slog.Error("failed to parse statement", slog.String("statement", stmt.Text), log.BBError(err))
+			continue
+		}
+
+		hasDML, hasDDL := false, false
+		for _, sqlType := range getStatementTypesFromTSQLTree(parseResult.Tree) {
+			sqlTypeSet[sqlType] = struct{}{}
+			if isDML(sqlType) {
+				hasDML = true
+			} else {
+				hasDDL = true
+			}
+		}
+		if hasDDL {
+			resources, err := base.ExtractChangedResources(storepb.Engine_MSSQL, databaseName, schemaName, stmt.Text)
+			if err != nil {
+				slog.Error("failed to get statement changed resources", log.BBError(err))
+			} else {
+				changedResources = append(changedResources, resources...)
+			}
+		}
+		if hasDML {
+			affectedRows, err := getAffectedRowsForMSSQL(ctx, sqlDB, stmt.Text)
+			if err != nil {
+				slog.Error("failed to get affected rows for mssql", slog.String("database", databaseName), log.BBError(err))
+			} else {
+				totalAffectedRows += affectedRows
+			}
+		}
+	}
+
+	var sqlTypes []string
+	for sqlType := range sqlTypeSet {
+		sqlTypes = append(sqlTypes, sqlType)
+	}
+
+	return []*storepb.PlanCheckRunResult_Result{
+		{
+			Status: storepb.PlanCheckRunResult_Result_SUCCESS,
+			Code:   common.Ok.Int32(),
+			Title:  "OK",
+			Report: &storepb.PlanCheckRunResult_Result_SqlSummaryReport_{
+				SqlSummaryReport: &storepb.PlanCheckRunResult_Result_SqlSummaryReport{
+					StatementTypes:   sqlTypes,
+					AffectedRows:     int32(totalAffectedRows),
+					ChangedResources: convertToChangedResources(changedResources),
+				},
+			},
+		},
+	}, nil
+}
+
+func reportForSnowflake(ctx context.Context, sqlDB *sql.DB, databaseName string, statement string, dbMetadata *storepb.DatabaseSchemaMetadata) ([]*storepb.PlanCheckRunResult_Result, error) {
+	parseResult, err := snowparser.ParseSnowSQL(statement)
+	if err != nil {
+		// nolint:nilerr
+		return []*storepb.PlanCheckRunResult_Result{
+			{
+				Status:  storepb.PlanCheckRunResult_Result_ERROR,
+				Title:   "Syntax error",
+				Content: err.Error(),
+				Code:    0,
+				Report: &storepb.PlanCheckRunResult_Result_SqlSummaryReport_{
+					SqlSummaryReport: &storepb.PlanCheckRunResult_Result_SqlSummaryReport{
+						Code: advisor.StatementSyntaxError.Int32(),
+					},
+				},
+			},
+		}, nil
+	}
+	file, ok := parseResult.Tree.(*snowsql.Snowflake_fileContext)
+	if !ok {
+		return nil, errors.Errorf("failed to convert to Snowflake_fileContext")
+	}
+	// The current schema is PUBLIC by default, unless the connection or the user specifies another one.
+	schemaName, err := getDefaultSchema(ctx, sqlDB, "SELECT CURRENT_SCHEMA()", "PUBLIC")
+	if err != nil {
+		return nil, err
+	}
+
+	sqlTypeSet := map[string]struct{}{}
+	var totalAffectedRows int64
+	var changedResources []base.SchemaResource
+
+	for _, batch := range file.AllBatch() {
+		command := batch.Sql_command()
+		if command == nil {
+			continue
+		}
+		sqlType := getStatementTypeFromSnowSQLCommand(command)
+		if sqlType == "" {
+			continue
+		}
+		sqlTypeSet[sqlType] = struct{}{}
+		text := parseResult.Tokens.GetTextFromRuleContext(command)
+		if !isDML(sqlType) {
+			resources, err := base.ExtractChangedResources(storepb.Engine_SNOWFLAKE, databaseName, schemaName, text)
+			if err != nil {
+				slog.Error("failed to get statement changed resources", log.BBError(err))
+			} else {
+				changedResources = append(changedResources, resources...)
+			}
+			continue
+		}
+
+		affectedRows, err := getAffectedRowsForSnowflake(ctx, sqlDB, dbMetadata, databaseName, schemaName, command.Dml_command(), text)
+		if err != nil {
+			slog.Error("failed to get affected rows for snowflake", slog.String("database", databaseName), log.BBError(err))
+		} else {
+			totalAffectedRows += affectedRows
+		}
+	}
+
+	var sqlTypes []string
+	for sqlType := range sqlTypeSet {
+		sqlTypes = append(sqlTypes, sqlType)
+	}
+
+	return []*storepb.PlanCheckRunResult_Result{
+		{
+			Status: storepb.PlanCheckRunResult_Result_SUCCESS,
+			Code:   common.Ok.Int32(),
+			Title:  "OK",
+			Report: &storepb.PlanCheckRunResult_Result_SqlSummaryReport_{
+				SqlSummaryReport: &storepb.PlanCheckRunResult_Result_SqlSummaryReport{
+					StatementTypes:   sqlTypes,
+					AffectedRows:     int32(totalAffectedRows),
+					ChangedResources: convertToChangedResources(changedResources),
+				},
+			},
+		},
+	}, nil
+}
+
 func reportForMySQL(ctx context.Context, sqlDB *sql.DB, engine storepb.Engine, databaseName string, statement string, dbMetadata *storepb.DatabaseSchemaMetadata) ([]*storepb.PlanCheckRunResult_Result, error) {
 	charset := dbMetadata.CharacterSet
 	collation := dbMetadata.Collation
@@ -476,7 +699,7 @@ func reportForMySQL(ctx context.Context, sqlDB *sql.DB, engine storepb.Engine, d
 
 func isDML(tp string) bool {
 	switch tp {
-	case "REPLACE", "INSERT", "UPDATE", "DELETE":
+	case "REPLACE", "INSERT", "UPDATE", "DELETE", "MERGE":
 		return true
 	default:
 		return false
@@ -971,3 +1194,148 @@ func getStatementTypeAndResourcesFromAstNode(database, schema string, node ast.N
 
 	return "UNKNOWN", result
 }
+
+// getStatementTypesFromTSQLTree returns the statement types of the top level statements in the tree.
+func getStatementTypesFromTSQLTree(tree antlr.Tree) []string {
+	file, ok := tree.(*tsql.Tsql_fileContext)
+	if !ok {
+		return nil
+	}
+	var result []string
+	for _, batch := range file.AllBatch() {
+		if batchLevelStatement := batch.Batch_level_statement(); batchLevelStatement != nil {
+			switch batchLevelStatement.GetChild(0).(type) {
+			case *tsql.Create_or_alter_functionContext:
+				result = append(result, "CREATE_FUNCTION")
+			case *tsql.Create_or_alter_procedureContext:
+				result = append(result, "CREATE_PROCEDURE")
+			case *tsql.Create_or_alter_triggerContext:
+				result = append(result, "CREATE_TRIGGER")
+			case *tsql.Create_viewContext:
+				result = append(result, "CREATE_VIEW")
+			}
+			continue
+		}
+		for _, clause := range batch.AllSql_clauses() {
+			if dml := clause.Dml_clause(); dml != nil {
+				switch dml.GetChild(0).(type) {
+				case *tsql.Insert_statementContext:
+					result = append(result, "INSERT")
+				case *tsql.Update_statementContext:
+					result = append(result, "UPDATE")
+				case *tsql.Delete_statementContext:
+					result = append(result, "DELETE")
+				case *tsql.Merge_statementContext:
+					result = append(result, "MERGE")
+				}
+				continue
+			}
+			if ddl := clause.Ddl_clause(); ddl != nil {
+				switch ddl.GetChild(0).(type) {
+				case *tsql.Create_databaseContext:
+					result = append(result, "CREATE_DATABASE")
+				case *tsql.Create_schemaContext:
+					result = append(result, "CREATE_SCHEMA")
+				case *tsql.Create_tableContext:
+					result = append(result, "CREATE_TABLE")
+				case *tsql.Create_indexContext:
+					result = append(result, "CREATE_INDEX")
+				case *tsql.Create_sequenceContext:
+					result = append(result, "CREATE_SEQUENCE")
+				case *tsql.Alter_tableContext:
+					result = append(result, "ALTER_TABLE")
+				case *tsql.Alter_indexContext:
+					result = append(result, "ALTER_INDEX")
+				case *tsql.Drop_databaseContext:
+					result = append(result, "DROP_DATABASE")
+				case *tsql.Drop_schemaContext:
+					result = append(result, "DROP_SCHEMA")
+				case *tsql.Drop_tableContext:
+					result = append(result, "DROP_TABLE")
+				case *tsql.Drop_indexContext:
+					result = append(result, "DROP_INDEX")
+				case *tsql.Drop_viewContext:
+					result = append(result, "DROP_VIEW")
+				case *tsql.Drop_procedureContext:
+					result = append(result, "DROP_PROCEDURE")
+				case *tsql.Drop_functionContext:
+					result = append(result, "DROP_FUNCTION")
+				case *tsql.Truncate_tableContext:
+					result = append(result, "TRUNCATE")
+				default:
+					result = append(result, "UNKNOWN")
+				}
+			}
+		}
+	}
+	return result
+}
+
+// getStatementTypeFromSnowSQLCommand returns the statement type of the command, or empty string for the commands that do not change anything.
+func getStatementTypeFromSnowSQLCommand(command snowsql.ISql_commandContext) string {
+	if ddl := command.Ddl_command(); ddl != nil {
+		if alter := ddl.Alter_command(); alter != nil {
+			switch {
+			case alter.Alter_database() != nil:
+				return "ALTER_DATABASE"
+			case alter.Alter_schema() != nil:
+				return "ALTER_SCHEMA"
+			case alter.Alter_table() != nil:
+				return "ALTER_TABLE"
+			case alter.Alter_view() != nil:
+				return "ALTER_VIEW"
+			case alter.Alter_sequence() != nil:
+				return "ALTER_SEQUENCE"
+			}
+		}
+		if create := ddl.Create_command(); create != nil {
+			switch {
+			case create.Create_database() != nil:
+				return "CREATE_DATABASE"
+			case create.Create_schema() != nil:
+				return "CREATE_SCHEMA"
+			case create.Create_table() != nil, create.Create_table_as_select() != nil:
+				return "CREATE_TABLE"
+			case create.Create_view() != nil:
+				return "CREATE_VIEW"
+			case create.Create_sequence() != nil:
+				return "CREATE_SEQUENCE"
+			}
+		}
+		if drop := ddl.Drop_command(); drop != nil {
+			switch {
+			case drop.Drop_database() != nil:
+				return "DROP_DATABASE"
+			case drop.Drop_schema() != nil:
+				return "DROP_SCHEMA"
+			case drop.Drop_table() != nil:
+				return "DROP_TABLE"
+			case drop.Drop_view() != nil:
+				return "DROP_VIEW"
+			case drop.Drop_sequence() != nil:
+				return "DROP_SEQUENCE"
+			}
+		}
+		if ddl.Undrop_command() != nil {
+			return "UNDROP"
+		}
+		return "UNKNOWN"
+	}
+	if dml := command.Dml_command(); dml != nil {
+		switch {
+		case dml.Insert_statement() != nil, dml.Insert_multi_table_statement() != nil:
+			return "INSERT"
+		case dml.Update_statement() != nil:
+			return "UPDATE"
+		case dml.Delete_statement() != nil:
+			return "DELETE"
+		case dml.Merge_statement() != nil:
+			return "MERGE"
+		}
+		return ""
+	}
+	if other := command.Other_command(); other != nil && other.Truncate_table() != nil {
+		return "TRUNCATE"
+	}
+	return ""
+}
@@ -4,7 +4,11 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"encoding/xml"
 	"fmt"
+	"io"
+	"log/slog"
+	"math"
 	"regexp"
 	"strconv"
 	"strings"
@@ -12,6 +16,10 @@ import (
 	tidbast "github.com/pingcap/tidb/parser/ast"
 	"github.com/pkg/errors"
 
+	snowsql "github.com/bytebase/snowsql-parser"
+
+	"github.com/bytebase/bytebase/backend/common/log"
+	snowparser "github.com/bytebase/bytebase/backend/plugin/parser/snowflake"
 	"github.com/bytebase/bytebase/backend/plugin/parser/sql/ast"
 	storepb "github.com/bytebase/bytebase/proto/generated-go/store"
 )
@@ -229,6 +237,9 @@ func getAffectedRowsForMysql(ctx context.Context, engine storepb.Engine, sqlDB *
 		if engine == storepb.Engine_OCEANBASE {
 			return getAffectedRowsCount(ctx, sqlDB, fmt.Sprintf("EXPLAIN FORMAT=JSON %s", node.Text()), getAffectedRowsCountForOceanBase)
 		}
+		if engine == storepb.Engine_TIDB {
+			return getAffectedRowsCount(ctx, sqlDB, fmt.Sprintf("EXPLAIN %s", node.Text()), getAffectedRowsCountForTiDB)
+		}
 		return getAffectedRowsCount(ctx, sqlDB, fmt.Sprintf("EXPLAIN %s", node.Text()), getAffectedRowsCountForMysql)
 
 	case *tidbast.AlterTableStmt:
@@ -384,3 +395,171 @@ func getAffectedRowsCountForMysql(res []any) (int64, error) {
 
 	return 0, errors.Errorf("failed to extract rows from query plan")
 }
+
+func getAffectedRowsCountForTiDB(res []any) (int64, error) {
+	// the res struct is []any{columnName, columnTable, rowDataList}
+	if len(res) != 3 {
+		return 0, errors.Errorf("expected 3 but got %d", len(res))
+	}
+	rowList, ok := res[2].([]any)
+	if !ok {
+		return 0, errors.Errorf("expected []any but got %t", res[2])
+	}
+	if len(rowList) < 1 {
+		return 0, errors.Errorf("not found any data")
+	}
+
+	// TiDB EXPLAIN statement result has 5 columns.
+	// the column 1 is the data 'estRows'.
+	// the first valid value of column 1 is the affected rows count.
+	//
+	// mysql> explain delete from t where a > 1;
+	// +---------------------------+---------+-----------+---------------+--------------------------------+
+	// | id                        | estRows | task      | access object | operator info                  |
+	// +---------------------------+---------+-----------+---------------+--------------------------------+
+	// | Delete_4                  | N/A     | root      |               | N/A                            |
+	// | └─TableReader_8           | 3333.33 | root      |               | data:Selection_7               |
+	// |   └─Selection_7           | 3333.33 | cop[tikv] |               | gt(test.t.a, 1)                |
+	// |     └─TableFullScan_6     | 10000.00| cop[tikv] | table:t       | keep order:false, stats:pseudo |
+	// +---------------------------+---------+-----------+---------------+--------------------------------+
+
+	for _, rowAny := range rowList {
+		row, ok := rowAny.([]any)
+		if !ok {
+			return 0, errors.Errorf("expected []any but got %t", row)
+		}
+		if len(row) != 5 {
+			return 0, errors.Errorf("expected 5 but got %d", len(row))
+		}
+		col, ok := row[1].(string)
+		if !ok {
+			continue
+		}
+		v, err := strconv.ParseFloat(col, 64)
+		if err != nil {
+			continue
+		}
+		return int64(math.Round(v)), nil
+	}
+
+	return 0, errors.Errorf("failed to extract estRows from query plan")
+}
+
+// mssqlShowPlanXML is the subset of the SQL Server showplan XML we care about.
+// https://schemas.microsoft.com/sqlserver/2004/07/showplan/
+type mssqlShowPlanXML struct {
+	XMLName       xml.Name `xml:"ShowPlanXML"`
+	BatchSequence struct {
+		Batch []struct {
+			Statements []struct {
+				StmtSimple []struct {
+					StatementType    string  `xml:"StatementType,attr"`
+					StatementEstRows float64 `xml:"StatementEstRows,attr"`
+				} `xml:"StmtSimple"`
+			} `xml:"Statements"`
+		} `xml:"Batch"`
+	} `xml:"BatchSequence"`
+}
+
+// getAffectedRowsForMSSQL returns the estimated rows of the statement from the SHOWPLAN_XML output.
+// SET SHOWPLAN_XML must be the only statement in the batch and only takes effect on the current session,
+// so we use a dedicated connection here.
+func getAffectedRowsForMSSQL(ctx context.Context, sqlDB *sql.DB, statement string) (int64, error) {
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "SET SHOWPLAN_XML ON"); err != nil {
+		return 0, errors.Wrapf(err, "failed to set showplan_xml on")
+	}
+	defer func() {
+		if _, err := conn.ExecContext(ctx, "SET SHOWPLAN_XML OFF"); err != nil {
+			slog.Warn("failed to set showplan_xml off", log.BBError(err))
+		}
+	}()
+
+	var plan string
+	if err := conn.QueryRowContext(ctx, statement).Scan(&plan); err != nil {
+		return 0, errors.Wrapf(err, "failed to get showplan_xml")
+	}
+	return getAffectedRowsCountForMSSQL(plan)
+}
+
+func getAffectedRowsCountForMSSQL(plan string) (int64, error) {
+	var showPlan mssqlShowPlanXML
+	decoder := xml.NewDecoder(strings.NewReader(plan))
+	// The plan may declare the utf-16 encoding, but it has been decoded into a UTF-8 string by the driver.
+	decoder.CharsetReader = func(_ string, input io.Reader) (io.Reader, error) {
+		return input, nil
+	}
+	if err := decoder.Decode(&showPlan); err != nil {
+		return 0, errors.Wrapf(err, "failed to parse showplan_xml")
+	}
+	var total float64
+	for _, batch := range showPlan.BatchSequence.Batch {
+		for _, statements := range batch.Statements {
+			for _, stmt := range statements.StmtSimple {
+				total += stmt.StatementEstRows
+			}
+		}
+	}
+	return int64(math.Round(total)), nil
+}
+
+// snowflakeExplainJSON is the subset of the Snowflake EXPLAIN USING JSON output we care about.
+type snowflakeExplainJSON struct {
+	GlobalStats struct {
+		PartitionsTotal    int64 `json:"partitionsTotal"`
+		PartitionsAssigned int64 `json:"partitionsAssigned"`
+	} `json:"GlobalStats"`
+}
+
+// getAffectedRowsForSnowflake estimates the affected rows of the DML statement.
+// Snowflake EXPLAIN does not report the estimated rows, so we scale the row count of the target table
+// by the ratio of the assigned partitions after pruning.
+func getAffectedRowsForSnowflake(ctx context.Context, sqlDB *sql.DB, metadata *storepb.DatabaseSchemaMetadata, databaseName, schemaName string, dml snowsql.IDml_commandContext, statement string) (int64, error) {
+	var objectName snowsql.IObject_nameContext
+	switch {
+	case dml.Insert_statement() != nil:
+		if values := dml.Insert_statement().Values_builder(); values != nil {
+			return int64(len(values.AllExpr_list())), nil
+		}
+		return 0, nil
+	case dml.Update_statement() != nil:
+		objectName = dml.Update_statement().Object_name()
+	case dml.Delete_statement() != nil:
+		objectName = dml.Delete_statement().Object_name()
+	case dml.Merge_statement() != nil:
+		objectName = dml.Merge_statement().Object_name()
+	default:
+		return 0, nil
+	}
+
+	parts := strings.Split(snowparser.NormalizeSnowSQLObjectName(objectName, databaseName, schemaName), ".")
+	if len(parts) != 3 || parts[0] != databaseName {
+		return 0, nil
+	}
+	tableRows := getTableDataSize(metadata, parts[1], parts[2])
+	if tableRows == 0 {
+		return 0, nil
+	}
+
+	var plan string
+	if err := sqlDB.QueryRowContext(ctx, fmt.Sprintf("EXPLAIN USING JSON %s", statement)).Scan(&plan); err != nil {
+		return 0, err
+	}
+	return getAffectedRowsCountForSnowflake(plan, tableRows)
+}
+
+func getAffectedRowsCountForSnowflake(plan string, tableRows int64) (int64, error) {
+	var explain snowflakeExplainJSON
+	if err := json.Unmarshal([]byte(plan), &explain); err != nil {
+		return 0, errors.Wrapf(err, "failed to parse query plan %q", plan)
+	}
+	if explain.GlobalStats.PartitionsTotal == 0 {
+		return 0, nil
+	}
+	return tableRows * explain.GlobalStats.PartitionsAssigned / explain.GlobalStats.PartitionsTotal, nil
+}
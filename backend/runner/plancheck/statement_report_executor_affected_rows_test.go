@@ -0,0 +1,154 @@
+package plancheck
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetAffectedRowsCountForMSSQL(t *testing.T) {
+	tests := []struct {
+		plan    string
+		want    int64
+		wantErr bool
+	}{
+		{
+			plan: `<?xml version="1.0" encoding="utf-16"?>
+<ShowPlanXML xmlns="http://schemas.microsoft.com/sqlserver/2004/07/showplan" Version="1.564" Build="16.0.1000.6">
+  <BatchSequence>
+    <Batch>
+      <Statements>
+        <StmtSimple StatementText="DELETE FROM t WHERE a &gt; 1" StatementId="1" StatementCompId="1" StatementType="DELETE" StatementSubTreeCost="0.0132842" StatementEstRows="42.4" StatementOptmLevel="TRIVIAL">
+          <QueryPlan CachedPlanSize="24" CompileTime="1" CompileCPU="1" CompileMemory="184">
+            <RelOp NodeId="0" PhysicalOp="Clustered Index Delete" LogicalOp="Delete" EstimateRows="42.4" />
+          </QueryPlan>
+        </StmtSimple>
+      </Statements>
+    </Batch>
+  </BatchSequence>
+</ShowPlanXML>`,
+			want: 42,
+		},
+		{
+			// The estimated rows of all statements are summed up.
+			plan: `<ShowPlanXML xmlns="http://schemas.microsoft.com/sqlserver/2004/07/showplan">
+  <BatchSequence>
+    <Batch>
+      <Statements>
+        <StmtSimple StatementType="UPDATE" StatementEstRows="10" />
+        <StmtSimple StatementType="DELETE" StatementEstRows="2.6" />
+      </Statements>
+    </Batch>
+  </BatchSequence>
+</ShowPlanXML>`,
+			want: 13,
+		},
+		{
+			plan:    "not xml",
+			wantErr: true,
+		},
+	}
+
+	a := require.New(t)
+	for _, test := range tests {
+		got, err := getAffectedRowsCountForMSSQL(test.plan)
+		if test.wantErr {
+			a.Error(err)
+			continue
+		}
+		a.NoError(err)
+		a.Equal(test.want, got)
+	}
+}
+
+func TestGetAffectedRowsCountForSnowflake(t *testing.T) {
+	tests := []struct {
+		plan      string
+		tableRows int64
+		want      int64
+		wantErr   bool
+	}{
+		{
+			plan: `{
+  "GlobalStats": {"partitionsTotal": 8, "partitionsAssigned": 2, "bytesAssigned": 1024},
+  "Operations": [[
+    {"id": 0, "operation": "Result", "expressions": ["number of rows deleted"]},
+    {"id": 1, "parentOperators": [0], "operation": "DeleteCommand", "objects": ["DB.PUBLIC.T"]},
+    {"id": 2, "parentOperators": [1], "operation": "TableScan", "objects": ["DB.PUBLIC.T"], "partitionsAssigned": 2, "partitionsTotal": 8}
+  ]]
+}`,
+			tableRows: 1000,
+			want:      250,
+		},
+		{
+			plan:      `{"GlobalStats": {"partitionsTotal": 0, "partitionsAssigned": 0}}`,
+			tableRows: 1000,
+			want:      0,
+		},
+		{
+			plan:      "not json",
+			tableRows: 1000,
+			wantErr:   true,
+		},
+	}
+
+	a := require.New(t)
+	for _, test := range tests {
+		got, err := getAffectedRowsCountForSnowflake(test.plan, test.tableRows)
+		if test.wantErr {
+			a.Error(err)
+			continue
+		}
+		a.NoError(err)
+		a.Equal(test.want, got)
+	}
+}
+
+func TestGetAffectedRowsCountForTiDB(t *testing.T) {
+	columns := []string{"id", "estRows", "task", "access object", "operator info"}
+	columnTypes := []string{"VARCHAR", "VARCHAR", "VARCHAR", "VARCHAR", "VARCHAR"}
+	tests := []struct {
+		rows    []any
+		want    int64
+		wantErr bool
+	}{
+		{
+			rows: []any{
+				[]any{"Delete_4", "N/A", "root", "", "N/A"},
+				[]any{"└─TableReader_8", "3333.33", "root", "", "data:Selection_7"},
+				[]any{"  └─Selection_7", "3333.33", "cop[tikv]", "", "gt(test.t.a, 1)"},
+				[]any{"    └─TableFullScan_6", "10000.00", "cop[tikv]", "table:t", "keep order:false, stats:pseudo"},
+			},
+			want: 3333,
+		},
+		{
+			rows: []any{
+				[]any{"Update_4", "N/A", "root", "", "N/A"},
+				[]any{"└─Point_Get_6", "1.00", "root", "table:t", "handle:1, lock"},
+			},
+			want: 1,
+		},
+		{
+			rows:    []any{[]any{"Delete_4", "N/A", "root", "", "N/A"}},
+			wantErr: true,
+		},
+		{
+			rows:    []any{[]any{"Delete_4", "N/A"}},
+			wantErr: true,
+		},
+		{
+			wantErr: true,
+		},
+	}
+
+	a := require.New(t)
+	for _, test := range tests {
+		got, err := getAffectedRowsCountForTiDB([]any{columns, columnTypes, test.rows})
+		if test.wantErr {
+			a.Error(err)
+			continue
+		}
+		a.NoError(err)
+		a.Equal(test.want, got)
+	}
+}
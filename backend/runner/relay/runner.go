@@ -21,7 +21,7 @@ import (
 	"github.com/bytebase/bytebase/backend/utils"
 
 	api "github.com/bytebase/bytebase/backend/legacyapi"
-	relayplugin "github.com/bytebase/bytebase/backend/plugin/app/relay"
+	"github.com/bytebase/bytebase/backend/plugin/app/externalapproval"
 	"github.com/bytebase/bytebase/backend/store"
 	storepb "github.com/bytebase/bytebase/proto/generated-go/store"
 )
@@ -32,7 +32,7 @@ func NewRunner(store *store.Store, activityManager *activity.Manager, stateCfg *
 		store:                     store,
 		activityManager:           activityManager,
 		stateCfg:                  stateCfg,
-		Client:                    relayplugin.NewClient(),
+		NewProvider:               externalapproval.NewProvider,
 		CheckExternalApprovalChan: make(chan CheckExternalApprovalChanMessage, 100),
 	}
 }
@@ -40,6 +40,9 @@ func NewRunner(store *store.Store, activityManager *activity.Manager, stateCfg *
 // CheckExternalApprovalChanMessage is the message to check external approval status.
 type CheckExternalApprovalChanMessage struct {
 	ExternalApproval *store.ExternalApprovalMessage
+	// ChangeRequest is the change request received from the callback of the external system.
+	// If it's nil, the runner gets the change request from the external system.
+	ChangeRequest *externalapproval.ChangeRequest
 	// ErrChan is used to send back the error message.
 	// The channel must be buffered to avoid blocking.
 	ErrChan chan error
@@ -51,7 +54,8 @@ type Runner struct {
 	activityManager *activity.Manager
 	stateCfg        *state.State
 
-	Client *relayplugin.Client
+	// NewProvider creates the provider for the external approval nodes.
+	NewProvider externalapproval.Factory
 
 	CheckExternalApprovalChan chan CheckExternalApprovalChanMessage
 }
@@ -68,7 +72,7 @@ func (r *Runner) Run(ctx context.Context, wg *sync.WaitGroup) {
 	wg.Add(1)
 	go r.listenIssueExternalApprovalRelayCancelChan(ctx, wg)
 	wg.Add(1)
-	go r.listenCheckExternalApprovalChan(ctx, wg)
+	go r.ListenCheckExternalApprovalChan(ctx, wg)
 
 	for {
 		select {
@@ -105,12 +109,14 @@ func (r *Runner) Run(ctx context.Context, wg *sync.WaitGroup) {
 	}
 }
 
-func (r *Runner) listenCheckExternalApprovalChan(ctx context.Context, wg *sync.WaitGroup) {
+// ListenCheckExternalApprovalChan checks the external approvals sent to CheckExternalApprovalChan.
+// It's also run by the non-leader replicas in the high-availability mode, where the API server waits for the result.
+func (r *Runner) ListenCheckExternalApprovalChan(ctx context.Context, wg *sync.WaitGroup) {
 	defer wg.Done()
 	for {
 		select {
 		case msg := <-r.CheckExternalApprovalChan:
-			err := r.checkExternalApproval(ctx, msg.ExternalApproval)
+			err := r.checkExternalApproval(ctx, msg.ExternalApproval, msg.ChangeRequest)
 			msg.ErrChan <- err
 		case <-ctx.Done():
 			return
@@ -118,55 +124,47 @@ func (r *Runner) listenCheckExternalApprovalChan(ctx context.Context, wg *sync.W
 	}
 }
 
-func (r *Runner) checkExternalApproval(ctx context.Context, approval *store.ExternalApprovalMessage) error {
+func (r *Runner) checkExternalApproval(ctx context.Context, approval *store.ExternalApprovalMessage, changeRequest *externalapproval.ChangeRequest) error {
 	payload := &api.ExternalApprovalPayloadRelay{}
 	if err := json.Unmarshal([]byte(approval.Payload), payload); err != nil {
 		return errors.Wrapf(err, "failed to unmarshal external approval payload")
 	}
-	node, err := getExternalApprovalByID(ctx, r.store, payload.ExternalApprovalNodeID)
-	if err != nil {
-		return errors.Wrapf(err, "failed to get external approval node %s", payload.ExternalApprovalNodeID)
-	}
-	id := payload.ID
-	resp, err := r.Client.GetApproval(node.Endpoint, id)
-	if err != nil {
-		return errors.Wrapf(err, "failed to get external approval status, id: %v, endpoint: %s, id: %s", node.Id, node.Endpoint, id)
-	}
-	if resp.Status == relayplugin.StatusApproved {
-		if err := r.approveExternalApprovalNode(ctx, approval.IssueUID); err != nil {
-			return err
+	if changeRequest == nil {
+		node, err := utils.GetExternalApprovalNode(ctx, r.store, payload.ExternalApprovalNodeID)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get external approval node %s", payload.ExternalApprovalNodeID)
 		}
-		if _, err := r.store.UpdateExternalApprovalV2(ctx, &store.UpdateExternalApprovalMessage{
-			ID:        approval.ID,
-			RowStatus: api.Archived,
-		}); err != nil {
+		if node == nil {
+			return errors.Errorf("external approval node %s not found", payload.ExternalApprovalNodeID)
+		}
+		provider, err := r.NewProvider(node)
+		if err != nil {
 			return err
 		}
-	} else if resp.Status == relayplugin.StatusRejected {
-		if err := r.rejectExternalApprovalNode(ctx, approval.IssueUID); err != nil {
+		changeRequest, err = provider.Get(ctx, payload.ID)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get external approval status, id: %v, endpoint: %s, id: %s", node.Id, node.Endpoint, payload.ID)
+		}
+	}
+	switch changeRequest.Status {
+	case externalapproval.StatusApproved:
+		if err := r.approveExternalApprovalNode(ctx, approval.IssueUID); err != nil {
 			return err
 		}
-		if _, err := r.store.UpdateExternalApprovalV2(ctx, &store.UpdateExternalApprovalMessage{
-			ID:        approval.ID,
-			RowStatus: api.Archived,
-		}); err != nil {
+	case externalapproval.StatusRejected:
+		if err := r.rejectExternalApprovalNode(ctx, approval.IssueUID); err != nil {
 			return err
 		}
+	default:
+		return nil
 	}
-	return nil
-}
-
-func getExternalApprovalByID(ctx context.Context, s *store.Store, externalApprovalID string) (*storepb.ExternalApprovalSetting_Node, error) {
-	setting, err := s.GetWorkspaceExternalApprovalSetting(ctx)
-	if err != nil {
-		return nil, errors.Wrapf(err, "failed to get workspace external approval setting")
-	}
-	for _, node := range setting.Nodes {
-		if node.Id == externalApprovalID {
-			return node, nil
-		}
+	if _, err := r.store.UpdateExternalApprovalV2(ctx, &store.UpdateExternalApprovalMessage{
+		ID:        approval.ID,
+		RowStatus: api.Archived,
+	}); err != nil {
+		return err
 	}
-	return nil, nil
+	return nil
 }
 
 func (r *Runner) cancelExternalApproval(ctx context.Context, issueUID int) {
@@ -194,7 +192,7 @@ func (r *Runner) cancelExternalApproval(ctx context.Context, issueUID int) {
 			continue
 		}
 		// don't wait for http requests, just fire and forget
-		node, err := getExternalApprovalByID(ctx, r.store, payload.ExternalApprovalNodeID)
+		node, err := utils.GetExternalApprovalNode(ctx, r.store, payload.ExternalApprovalNodeID)
 		if err != nil {
 			slog.Error("failed to get external approval node", log.BBError(err))
 			continue
@@ -203,9 +201,14 @@ func (r *Runner) cancelExternalApproval(ctx context.Context, issueUID int) {
 			slog.Error("external approval node not found", slog.String("id", payload.ExternalApprovalNodeID))
 			continue
 		}
+		provider, err := r.NewProvider(node)
+		if err != nil {
+			slog.Error("failed to create external approval provider", log.BBError(err))
+			continue
+		}
 		go func() {
-			if err := r.Client.UpdateApproval(node.Endpoint, payload.ID, &relayplugin.UpdatePayload{}); err != nil {
-				slog.Error("failed to update external approval status", slog.String("endpoint", node.Endpoint), slog.String("id", payload.ID), log.BBError(err))
+			if err := provider.Cancel(ctx, payload.ID); err != nil {
+				slog.Error("failed to cancel external approval", slog.String("endpoint", node.Endpoint), slog.String("id", payload.ID), log.BBError(err))
 			}
 		}()
 	}
@@ -261,7 +264,7 @@ func (r *Runner) approveExternalApprovalNode(ctx context.Context, issueUID int)
 		return errors.Wrapf(err, "failed to check if the approval is approved")
 	}
 
-	newApprovers, activityCreates, err := utils.HandleIncomingApprovalSteps(ctx, r.store, r.Client, issue, payload.Approval)
+	newApprovers, activityCreates, err := utils.HandleIncomingApprovalSteps(ctx, r.store, r.NewProvider, issue, payload.Approval)
 	if err != nil {
 		return errors.Wrapf(err, "failed to handle incoming approval steps")
 	}
@@ -127,6 +127,28 @@ func (r *Runner) generateRollbackSQL(ctx context.Context, task *store.TaskMessag
 		r.generateMySQLRollbackSQL(ctx, task, payload, instance, project)
 	case storepb.Engine_ORACLE:
 		r.generateOracleRollbackSQL(ctx, task, payload, instance, project)
+	case storepb.Engine_POSTGRES:
+		r.generatePostgresRollbackSQL(ctx, task, payload)
+	}
+}
+
+// generatePostgresRollbackSQL handles the tasks enabling rollback after execution.
+// The PostgreSQL rollback SQL is generated from the pre-image captured by the task executor before executing the statement,
+// so it cannot be generated afterwards.
+func (r *Runner) generatePostgresRollbackSQL(ctx context.Context, task *store.TaskMessage, payload *api.TaskDatabaseDataUpdatePayload) {
+	if payload.RollbackSheetID != 0 {
+		return
+	}
+	rollbackSQLStatus := api.RollbackSQLStatusFailed
+	rollbackError := "Failed to generate rollback SQL statement. The pre-image of PostgreSQL data changes is only captured if rollback is enabled before the task runs."
+	patch := &api.TaskPatch{
+		ID:                task.ID,
+		UpdaterID:         api.SystemBotID,
+		RollbackSQLStatus: &rollbackSQLStatus,
+		RollbackError:     &rollbackError,
+	}
+	if _, err := r.store.UpdateTaskV2(ctx, patch); err != nil {
+		slog.Error("Failed to patch task with the PostgreSQL rollback error", slog.Int("taskID", task.ID))
 	}
 }
 
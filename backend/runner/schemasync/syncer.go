@@ -17,11 +17,13 @@ import (
 
 	"github.com/bytebase/bytebase/backend/common"
 	"github.com/bytebase/bytebase/backend/common/log"
+	"github.com/bytebase/bytebase/backend/component/activity"
 	"github.com/bytebase/bytebase/backend/component/config"
 	"github.com/bytebase/bytebase/backend/component/dbfactory"
 	"github.com/bytebase/bytebase/backend/component/state"
 	enterprise "github.com/bytebase/bytebase/backend/enterprise/api"
 	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/plugin/webhook"
 	"github.com/bytebase/bytebase/backend/store"
 	"github.com/bytebase/bytebase/backend/store/model"
 	"github.com/bytebase/bytebase/backend/utils"
@@ -35,23 +37,25 @@ const (
 )
 
 // NewSyncer creates a schema syncer.
-func NewSyncer(store *store.Store, dbFactory *dbfactory.DBFactory, stateCfg *state.State, profile config.Profile, licenseService enterprise.LicenseService) *Syncer {
+func NewSyncer(store *store.Store, dbFactory *dbfactory.DBFactory, stateCfg *state.State, profile config.Profile, licenseService enterprise.LicenseService, activityManager *activity.Manager) *Syncer {
 	return &Syncer{
-		store:          store,
-		dbFactory:      dbFactory,
-		stateCfg:       stateCfg,
-		profile:        profile,
-		licenseService: licenseService,
+		store:           store,
+		dbFactory:       dbFactory,
+		stateCfg:        stateCfg,
+		profile:         profile,
+		licenseService:  licenseService,
+		activityManager: activityManager,
 	}
 }
 
 // Syncer is the schema syncer.
 type Syncer struct {
-	store          *store.Store
-	dbFactory      *dbfactory.DBFactory
-	stateCfg       *state.State
-	profile        config.Profile
-	licenseService enterprise.LicenseService
+	store           *store.Store
+	dbFactory       *dbfactory.DBFactory
+	stateCfg        *state.State
+	profile         config.Profile
+	licenseService  enterprise.LicenseService
+	activityManager *activity.Manager
 }
 
 // Run will run the schema syncer once.
@@ -463,7 +467,7 @@ func (s *Syncer) SyncDatabaseSchema(ctx context.Context, database *store.Databas
 						slog.String("type", string(api.AnomalyDatabaseSchemaDrift)),
 						log.BBError(err))
 				} else {
-					if _, err = s.store.UpsertActiveAnomalyV2(ctx, api.SystemBotID, &store.AnomalyMessage{
+					if _, created, err := s.store.UpsertActiveAnomalyV2(ctx, api.SystemBotID, &store.AnomalyMessage{
 						InstanceID:  instance.ResourceID,
 						DatabaseUID: &database.UID,
 						Type:        api.AnomalyDatabaseSchemaDrift,
@@ -474,6 +478,8 @@ func (s *Syncer) SyncDatabaseSchema(ctx context.Context, database *store.Databas
 							slog.String("database", database.DatabaseName),
 							slog.String("type", string(api.AnomalyDatabaseSchemaDrift)),
 							log.BBError(err))
+					} else if created {
+						s.postAnomalyEvent(ctx, api.EventAnomalyCreate, instance, database, api.AnomalyDatabaseSchemaDrift, "")
 					}
 				}
 			} else {
@@ -481,7 +487,9 @@ func (s *Syncer) SyncDatabaseSchema(ctx context.Context, database *store.Databas
 					DatabaseUID: &database.UID,
 					Type:        api.AnomalyDatabaseSchemaDrift,
 				})
-				if err != nil && common.ErrorCode(err) != common.NotFound {
+				if err == nil {
+					s.postAnomalyEvent(ctx, api.EventAnomalyResolve, instance, database, api.AnomalyDatabaseSchemaDrift, "")
+				} else if common.ErrorCode(err) != common.NotFound {
 					slog.Error("Failed to close anomaly",
 						slog.String("instance", instance.ResourceID),
 						slog.String("database", database.DatabaseName),
@@ -494,6 +502,45 @@ func (s *Syncer) SyncDatabaseSchema(ctx context.Context, database *store.Databas
 	return nil
 }
 
+// postAnomalyEvent posts the event of the anomaly created or resolved to the webhooks, the database is nil for the instance anomalies.
+func (s *Syncer) postAnomalyEvent(ctx context.Context, eventType api.ActivityType, instance *store.InstanceMessage, database *store.DatabaseMessage, anomalyType api.AnomalyType, detail string) {
+	name := instance.Title
+	if database != nil {
+		name = database.DatabaseName
+	}
+	level, verb := webhook.WebhookWarn, "detected"
+	if eventType == api.EventAnomalyResolve {
+		level, verb = webhook.WebhookSuccess, "resolved"
+	}
+	s.activityManager.PostEvent(ctx, &activity.Event{
+		Type:        eventType,
+		Level:       level,
+		Title:       fmt.Sprintf("%s %s - %s", getAnomalyTitle(anomalyType), verb, name),
+		Description: detail,
+		CreatorUID:  api.SystemBotID,
+		Instance:    instance,
+		Database:    database,
+		Anomaly:     &webhook.Anomaly{Type: string(anomalyType)},
+	})
+}
+
+func getAnomalyTitle(anomalyType api.AnomalyType) string {
+	switch anomalyType {
+	case api.AnomalyInstanceConnection:
+		return "Instance connection failure"
+	case api.AnomalyDatabaseConnection:
+		return "Database connection failure"
+	case api.AnomalyDatabaseSchemaDrift:
+		return "Schema drift"
+	case api.AnomalyDatabaseBackupPolicyViolation:
+		return "Backup policy violation"
+	case api.AnomalyDatabaseBackupMissing:
+		return "Backup missing"
+	default:
+		return string(anomalyType)
+	}
+}
+
 func (s *Syncer) upsertInstanceConnectionAnomaly(ctx context.Context, instance *store.InstanceMessage, connErr error) {
 	if connErr != nil {
 		anomalyPayload := api.AnomalyInstanceConnectionPayload{
@@ -507,7 +554,7 @@ func (s *Syncer) upsertInstanceConnectionAnomaly(ctx context.Context, instance *
 				log.BBError(err))
 			return
 		}
-		if _, err = s.store.UpsertActiveAnomalyV2(ctx, api.SystemBotID, &store.AnomalyMessage{
+		if _, created, err := s.store.UpsertActiveAnomalyV2(ctx, api.SystemBotID, &store.AnomalyMessage{
 			InstanceID: instance.ResourceID,
 			Type:       api.AnomalyInstanceConnection,
 			Payload:    string(payload),
@@ -516,6 +563,8 @@ func (s *Syncer) upsertInstanceConnectionAnomaly(ctx context.Context, instance *
 				slog.String("instance", instance.ResourceID),
 				slog.String("type", string(api.AnomalyInstanceConnection)),
 				log.BBError(err))
+		} else if created {
+			s.postAnomalyEvent(ctx, api.EventAnomalyCreate, instance, nil, api.AnomalyInstanceConnection, anomalyPayload.Detail)
 		}
 		return
 	}
@@ -524,7 +573,9 @@ func (s *Syncer) upsertInstanceConnectionAnomaly(ctx context.Context, instance *
 		InstanceID: &instance.ResourceID,
 		Type:       api.AnomalyInstanceConnection,
 	})
-	if err != nil && common.ErrorCode(err) != common.NotFound {
+	if err == nil {
+		s.postAnomalyEvent(ctx, api.EventAnomalyResolve, instance, nil, api.AnomalyInstanceConnection, "")
+	} else if common.ErrorCode(err) != common.NotFound {
 		slog.Error("Failed to close anomaly",
 			slog.String("instance", instance.ResourceID),
 			slog.String("type", string(api.AnomalyInstanceConnection)),
@@ -545,7 +596,7 @@ func (s *Syncer) upsertDatabaseConnectionAnomaly(ctx context.Context, instance *
 				slog.String("type", string(api.AnomalyDatabaseConnection)),
 				log.BBError(err))
 		} else {
-			if _, err = s.store.UpsertActiveAnomalyV2(ctx, api.SystemBotID, &store.AnomalyMessage{
+			if _, created, err := s.store.UpsertActiveAnomalyV2(ctx, api.SystemBotID, &store.AnomalyMessage{
 				InstanceID:  instance.ResourceID,
 				DatabaseUID: &database.UID,
 				Type:        api.AnomalyDatabaseConnection,
@@ -556,6 +607,8 @@ func (s *Syncer) upsertDatabaseConnectionAnomaly(ctx context.Context, instance *
 					slog.String("database", database.DatabaseName),
 					slog.String("type", string(api.AnomalyDatabaseConnection)),
 					log.BBError(err))
+			} else if created {
+				s.postAnomalyEvent(ctx, api.EventAnomalyCreate, instance, database, api.AnomalyDatabaseConnection, anomalyPayload.Detail)
 			}
 		}
 		return
@@ -565,7 +618,9 @@ func (s *Syncer) upsertDatabaseConnectionAnomaly(ctx context.Context, instance *
 		DatabaseUID: &database.UID,
 		Type:        api.AnomalyDatabaseConnection,
 	})
-	if err != nil && common.ErrorCode(err) != common.NotFound {
+	if err == nil {
+		s.postAnomalyEvent(ctx, api.EventAnomalyResolve, instance, database, api.AnomalyDatabaseConnection, "")
+	} else if common.ErrorCode(err) != common.NotFound {
 		slog.Error("Failed to close anomaly",
 			slog.String("instance", instance.ResourceID),
 			slog.String("database", database.DatabaseName),
@@ -628,7 +683,7 @@ func (s *Syncer) checkBackupAnomaly(ctx context.Context, environment *store.Envi
 					slog.String("type", string(api.AnomalyDatabaseBackupPolicyViolation)),
 					log.BBError(err))
 			} else {
-				if _, err = s.store.UpsertActiveAnomalyV2(ctx, api.SystemBotID, &store.AnomalyMessage{
+				if _, created, err := s.store.UpsertActiveAnomalyV2(ctx, api.SystemBotID, &store.AnomalyMessage{
 					InstanceID:  instance.ResourceID,
 					DatabaseUID: &database.UID,
 					Type:        api.AnomalyDatabaseBackupPolicyViolation,
@@ -639,6 +694,8 @@ func (s *Syncer) checkBackupAnomaly(ctx context.Context, environment *store.Envi
 						slog.String("database", database.DatabaseName),
 						slog.String("type", string(api.AnomalyDatabaseBackupPolicyViolation)),
 						log.BBError(err))
+				} else if created {
+					s.postAnomalyEvent(ctx, api.EventAnomalyCreate, instance, database, api.AnomalyDatabaseBackupPolicyViolation, "")
 				}
 			}
 		} else {
@@ -646,7 +703,9 @@ func (s *Syncer) checkBackupAnomaly(ctx context.Context, environment *store.Envi
 				DatabaseUID: &database.UID,
 				Type:        api.AnomalyDatabaseBackupPolicyViolation,
 			})
-			if err != nil && common.ErrorCode(err) != common.NotFound {
+			if err == nil {
+				s.postAnomalyEvent(ctx, api.EventAnomalyResolve, instance, database, api.AnomalyDatabaseBackupPolicyViolation, "")
+			} else if common.ErrorCode(err) != common.NotFound {
 				slog.Error("Failed to close anomaly",
 					slog.String("instance", instance.ResourceID),
 					slog.String("database", database.DatabaseName),
@@ -710,7 +769,7 @@ func (s *Syncer) checkBackupAnomaly(ctx context.Context, environment *store.Envi
 					slog.String("type", string(api.AnomalyDatabaseBackupMissing)),
 					log.BBError(err))
 			} else {
-				if _, err = s.store.UpsertActiveAnomalyV2(ctx, api.SystemBotID, &store.AnomalyMessage{
+				if _, created, err := s.store.UpsertActiveAnomalyV2(ctx, api.SystemBotID, &store.AnomalyMessage{
 					InstanceID:  instance.ResourceID,
 					DatabaseUID: &database.UID,
 					Type:        api.AnomalyDatabaseBackupMissing,
@@ -721,6 +780,8 @@ func (s *Syncer) checkBackupAnomaly(ctx context.Context, environment *store.Envi
 						slog.String("database", database.DatabaseName),
 						slog.String("type", string(api.AnomalyDatabaseBackupMissing)),
 						log.BBError(err))
+				} else if created {
+					s.postAnomalyEvent(ctx, api.EventAnomalyCreate, instance, database, api.AnomalyDatabaseBackupMissing, "")
 				}
 			}
 		} else {
@@ -728,7 +789,9 @@ func (s *Syncer) checkBackupAnomaly(ctx context.Context, environment *store.Envi
 				DatabaseUID: &database.UID,
 				Type:        api.AnomalyDatabaseBackupMissing,
 			})
-			if err != nil && common.ErrorCode(err) != common.NotFound {
+			if err == nil {
+				s.postAnomalyEvent(ctx, api.EventAnomalyResolve, instance, database, api.AnomalyDatabaseBackupMissing, "")
+			} else if common.ErrorCode(err) != common.NotFound {
 				slog.Error("Failed to close anomaly",
 					slog.String("instance", instance.ResourceID),
 					slog.String("database", database.DatabaseName),
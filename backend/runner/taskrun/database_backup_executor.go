@@ -13,12 +13,14 @@ import (
 	"golang.org/x/sys/unix"
 
 	"github.com/bytebase/bytebase/backend/common/log"
+	"github.com/bytebase/bytebase/backend/component/activity"
 	"github.com/bytebase/bytebase/backend/component/config"
 	"github.com/bytebase/bytebase/backend/component/dbfactory"
 	"github.com/bytebase/bytebase/backend/component/state"
 	api "github.com/bytebase/bytebase/backend/legacyapi"
 	"github.com/bytebase/bytebase/backend/plugin/db"
 	bbs3 "github.com/bytebase/bytebase/backend/plugin/storage/s3"
+	"github.com/bytebase/bytebase/backend/plugin/webhook"
 	"github.com/bytebase/bytebase/backend/runner/backuprun"
 	"github.com/bytebase/bytebase/backend/store"
 	v1pb "github.com/bytebase/bytebase/proto/generated-go/v1"
@@ -30,23 +32,25 @@ const (
 )
 
 // NewDatabaseBackupExecutor creates a new database backup task executor.
-func NewDatabaseBackupExecutor(store *store.Store, dbFactory *dbfactory.DBFactory, s3Client *bbs3.Client, stateCfg *state.State, profile config.Profile) Executor {
+func NewDatabaseBackupExecutor(store *store.Store, dbFactory *dbfactory.DBFactory, s3Client *bbs3.Client, activityManager *activity.Manager, stateCfg *state.State, profile config.Profile) Executor {
 	return &DatabaseBackupExecutor{
-		store:     store,
-		dbFactory: dbFactory,
-		s3Client:  s3Client,
-		stateCfg:  stateCfg,
-		profile:   profile,
+		store:           store,
+		dbFactory:       dbFactory,
+		s3Client:        s3Client,
+		activityManager: activityManager,
+		stateCfg:        stateCfg,
+		profile:         profile,
 	}
 }
 
 // DatabaseBackupExecutor is the task executor for database backup.
 type DatabaseBackupExecutor struct {
-	store     *store.Store
-	dbFactory *dbfactory.DBFactory
-	s3Client  *bbs3.Client
-	stateCfg  *state.State
-	profile   config.Profile
+	store           *store.Store
+	dbFactory       *dbfactory.DBFactory
+	s3Client        *bbs3.Client
+	activityManager *activity.Manager
+	stateCfg        *state.State
+	profile         config.Profile
 }
 
 // RunOnce will run database backup once.
@@ -129,6 +133,19 @@ func (exec *DatabaseBackupExecutor) RunOnce(ctx context.Context, _ context.Conte
 	}
 
 	if backupErr != nil {
+		exec.activityManager.PostEvent(ctx, &activity.Event{
+			Type:        api.EventDatabaseBackupFailed,
+			Level:       webhook.WebhookError,
+			Title:       fmt.Sprintf("Backup failed - %s", database.DatabaseName),
+			Description: backupErr.Error(),
+			CreatorUID:  task.CreatorID,
+			Instance:    instance,
+			Database:    database,
+			Backup: &webhook.Backup{
+				Name:  backup.Name,
+				Error: backupErr.Error(),
+			},
+		})
 		return true, nil, backupErr
 	}
 
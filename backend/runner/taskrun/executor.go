@@ -23,6 +23,7 @@ import (
 	api "github.com/bytebase/bytebase/backend/legacyapi"
 	"github.com/bytebase/bytebase/backend/plugin/db"
 	"github.com/bytebase/bytebase/backend/plugin/db/mysql"
+	"github.com/bytebase/bytebase/backend/plugin/db/pg"
 	"github.com/bytebase/bytebase/backend/plugin/parser/sql/transform"
 	vcsplugin "github.com/bytebase/bytebase/backend/plugin/vcs"
 	"github.com/bytebase/bytebase/backend/store"
@@ -221,11 +222,38 @@ func executeMigration(ctx context.Context, driverCtx context.Context, stores *st
 		opts.EndTransactionFunc = getSetOracleTransactionIDFunc(ctx, task, stores)
 	}
 
+	// PostgreSQL has neither binlog nor undo log to generate the rollback SQL from,
+	// so we capture and lock the pre-image of the affected rows in the migration transaction before executing the statement.
+	var postgresRollbackEnabled bool
+	var postgresRollbackStatement string
+	var postgresRollbackErr error
+	if task.Type == api.TaskDatabaseDataUpdate && instance.Engine == storepb.Engine_POSTGRES {
+		payload := &api.TaskDatabaseDataUpdatePayload{}
+		if err := json.Unmarshal([]byte(task.Payload), payload); err != nil {
+			return "", "", errors.Wrap(err, "invalid database data update payload")
+		}
+		if payload.RollbackEnabled {
+			postgresRollbackEnabled = true
+			opts.BeginTransactionFunc = func(tx *sql.Tx) error {
+				// The migration continues if failing to generate the rollback SQL.
+				postgresRollbackStatement, postgresRollbackErr = generatePostgresRollbackSQL(ctx, stores, tx, statement)
+				return nil
+			}
+		}
+	}
+
 	migrationID, schema, err := utils.ExecuteMigrationDefault(ctx, driverCtx, stores, stateCfg, taskRunUID, driver, mi, statement, sheetID, opts)
 	if err != nil {
 		return "", "", err
 	}
 
+	if postgresRollbackEnabled {
+		if err := setPostgresRollbackSQL(ctx, stores, task, database, postgresRollbackStatement, postgresRollbackErr); err != nil {
+			// The migration is done, so we don't fail the task for the rollback SQL.
+			slog.Error("failed to set the PostgreSQL rollback SQL", slog.Int("task", task.ID), log.BBError(err))
+		}
+	}
+
 	// If the migration is a data migration, enable the rollback SQL generation and the type of the driver is Oracle, we need to get the rollback SQL before the transaction is committed.
 	if task.Type == api.TaskDatabaseDataUpdate && instance.Engine == storepb.Engine_ORACLE {
 		updatedTask, err := stores.GetTaskV2ByID(ctx, task.ID)
@@ -386,6 +414,60 @@ func setMigrationIDAndEndBinlogCoordinate(ctx context.Context, conn *sql.Conn, t
 	return updatedTask, nil
 }
 
+// generatePostgresRollbackSQL generates the rollback SQL from the pre-image of the rows affected by the statement.
+func generatePostgresRollbackSQL(ctx context.Context, stores *store.Store, tx *sql.Tx, statement string) (string, error) {
+	if len(statement) > common.MaxSheetSizeForRollback {
+		return "", errors.Errorf("rollback SQL isn't supported for large sheet")
+	}
+	setting, err := stores.GetDataRollbackSetting(ctx)
+	if err != nil {
+		return "", err
+	}
+	return pg.GenerateRollbackSQL(ctx, tx, statement, setting.PreImageRowLimit)
+}
+
+func setPostgresRollbackSQL(ctx context.Context, stores *store.Store, task *store.TaskMessage, database *store.DatabaseMessage, rollbackStatement string, rollbackErr error) error {
+	rollbackSQLStatus := api.RollbackSQLStatusDone
+	var rollbackError string
+	if rollbackErr != nil {
+		rollbackSQLStatus = api.RollbackSQLStatusFailed
+		rollbackStatement = ""
+		if pg.IsErrExceedRowLimit(rollbackErr) {
+			rollbackError = fmt.Sprintf("Failed to generate rollback SQL statement. %s, please adjust the pre-image row limit in the workspace setting.", rollbackErr.Error())
+		} else {
+			rollbackError = rollbackErr.Error()
+		}
+	}
+
+	project, err := stores.GetProjectV2(ctx, &store.FindProjectMessage{ResourceID: &database.ProjectID})
+	if err != nil {
+		return errors.Wrapf(err, "failed to get project %q", database.ProjectID)
+	}
+	sheet, err := stores.CreateSheet(ctx, &store.SheetMessage{
+		CreatorID:  api.SystemBotID,
+		ProjectUID: project.UID,
+		Name:       fmt.Sprintf("Sheet for rolling back task %d", task.ID),
+		Statement:  rollbackStatement,
+		Visibility: store.ProjectSheet,
+		Source:     store.SheetFromBytebaseArtifact,
+		Type:       store.SheetForSQL,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to create rollback sheet")
+	}
+	patch := &api.TaskPatch{
+		ID:                task.ID,
+		UpdaterID:         api.SystemBotID,
+		RollbackSQLStatus: &rollbackSQLStatus,
+		RollbackSheetID:   &sheet.UID,
+		RollbackError:     &rollbackError,
+	}
+	if _, err := stores.UpdateTaskV2(ctx, patch); err != nil {
+		return errors.Wrapf(err, "failed to patch task %d with the rollback SQL", task.ID)
+	}
+	return nil
+}
+
 func postMigration(ctx context.Context, stores *store.Store, activityManager *activity.Manager, license enterprise.LicenseService, task *store.TaskMessage, mi *db.MigrationInfo, migrationID string, schema string, sheetID *int) (bool, *api.TaskRunResultPayload, error) {
 	instance, err := stores.GetInstanceV2(ctx, &store.FindInstanceMessage{UID: &task.InstanceID})
 	if err != nil {
@@ -26,6 +26,7 @@ import (
 	"github.com/bytebase/bytebase/backend/store"
 	"github.com/bytebase/bytebase/backend/store/model"
 	"github.com/bytebase/bytebase/backend/utils"
+	storepb "github.com/bytebase/bytebase/proto/generated-go/store"
 	v1pb "github.com/bytebase/bytebase/proto/generated-go/v1"
 )
 
@@ -93,25 +94,57 @@ func (e *SchemaUpdateGhostCutoverExecutor) RunOnce(ctx context.Context, _ contex
 	// To avoid leaking the rendered statement, the error message should use the original statement and not the rendered statement.
 	renderedStatement := utils.RenderStatement(statement, materials)
 
-	tableName, err := ghost.GetTableNameFromStatement(renderedStatement)
-	if err != nil {
-		return true, nil, common.Wrapf(err, common.Internal, "failed to parse table name from statement, statement: %v", statement)
-	}
-
-	postponeFilename := ghost.GetPostponeFlagFilename(syncTaskID, database.UID, database.DatabaseName, tableName)
-
 	value, ok := e.stateCfg.GhostTaskState.Load(syncTaskID)
 	if !ok {
-		return true, nil, errors.Errorf("failed to get gh-ost state from sync task")
-	}
-	sharedGhost, ok := value.(sharedGhostState)
-	if !ok {
+		if instance.Engine != storepb.Engine_POSTGRES {
+			return true, nil, errors.Errorf("failed to get gh-ost state from sync task")
+		}
+		// The state of PostgreSQL is persisted in the database, so the migration survives the restart.
+		sharedState, err := resumePostgresOnlineMigration(ctx, e.dbFactory, instance, database, renderedStatement, payload.Flags)
+		if err != nil {
+			return true, nil, err
+		}
+		value = *sharedState
+	}
+	var waitFunc func(context.Context) bool
+	var cutoverFunc func() error
+	switch sharedState := value.(type) {
+	case sharedGhostState:
+		tableName, err := ghost.GetTableNameFromStatement(renderedStatement)
+		if err != nil {
+			return true, nil, common.Wrapf(err, common.Internal, "failed to parse table name from statement, statement: %v", statement)
+		}
+		postponeFilename := ghost.GetPostponeFlagFilename(syncTaskID, database.UID, database.DatabaseName, tableName)
+		waitFunc = func(ctx context.Context) bool {
+			return waitForCutover(ctx, sharedState.migrationContext)
+		}
+		cutoverFunc = func() error {
+			if err := os.Remove(postponeFilename); err != nil {
+				return errors.Wrap(err, "failed to remove postpone flag file")
+			}
+			if migrationErr := <-sharedState.errCh; migrationErr != nil {
+				return errors.Wrapf(migrationErr, "failed to run gh-ost migration")
+			}
+			return nil
+		}
+	case sharedPostgresOnlineState:
+		waitFunc = func(ctx context.Context) bool {
+			return waitForPostgresCutover(ctx, sharedState.migration)
+		}
+		cutoverFunc = func() error {
+			sharedState.migration.Cutover()
+			if migrationErr := <-sharedState.errCh; migrationErr != nil {
+				return errors.Wrapf(migrationErr, "failed to run online schema change")
+			}
+			return nil
+		}
+	default:
 		return true, nil, errors.Errorf("failed to convert shared gh-ost state")
 	}
 
 	// not using the rendered statement here because we want to avoid leaking the rendered statement
 	version := model.Version{Version: payload.SchemaVersion}
-	terminated, result, err := cutover(ctx, e.store, e.dbFactory, e.activityManager, e.stateCfg, e.license, e.profile, task, taskRunUID, statement, payload.SheetID, version, postponeFilename, sharedGhost.migrationContext, sharedGhost.errCh)
+	terminated, result, err := cutover(ctx, e.store, e.dbFactory, e.activityManager, e.stateCfg, e.license, e.profile, task, taskRunUID, statement, payload.SheetID, version, waitFunc, cutoverFunc)
 	if err := e.schemaSyncer.SyncDatabaseSchema(ctx, database, true /* force */); err != nil {
 		slog.Error("failed to sync database schema",
 			slog.String("instanceName", instance.ResourceID),
@@ -123,7 +156,7 @@ func (e *SchemaUpdateGhostCutoverExecutor) RunOnce(ctx context.Context, _ contex
 	return terminated, result, err
 }
 
-func cutover(ctx context.Context, stores *store.Store, dbFactory *dbfactory.DBFactory, activityManager *activity.Manager, stateCfg *state.State, license enterprise.LicenseService, profile config.Profile, task *store.TaskMessage, taskRunUID int, statement string, sheetID int, schemaVersion model.Version, postponeFilename string, migrationContext *base.MigrationContext, errCh <-chan error) (terminated bool, result *api.TaskRunResultPayload, err error) {
+func cutover(ctx context.Context, stores *store.Store, dbFactory *dbfactory.DBFactory, activityManager *activity.Manager, stateCfg *state.State, license enterprise.LicenseService, profile config.Profile, task *store.TaskMessage, taskRunUID int, statement string, sheetID int, schemaVersion model.Version, waitFunc func(context.Context) bool, cutoverFunc func() error) (terminated bool, result *api.TaskRunResultPayload, err error) {
 	statement = strings.TrimSpace(statement)
 	instance, err := stores.GetInstanceV2(ctx, &store.FindInstanceMessage{UID: &task.InstanceID})
 	if err != nil {
@@ -135,7 +168,7 @@ func cutover(ctx context.Context, stores *store.Store, dbFactory *dbfactory.DBFa
 	}
 	// wait for heartbeat lag.
 	// try to make the time gap between the migration history insertion and the actual cutover as close as possible.
-	cancelled := waitForCutover(ctx, migrationContext)
+	cancelled := waitFunc(ctx)
 	if cancelled {
 		return true, nil, errors.Errorf("cutover poller cancelled")
 	}
@@ -146,13 +179,7 @@ func cutover(ctx context.Context, stores *store.Store, dbFactory *dbfactory.DBFa
 	}
 
 	execFunc := func(_ context.Context, _ string) error {
-		if err := os.Remove(postponeFilename); err != nil {
-			return errors.Wrap(err, "failed to remove postpone flag file")
-		}
-		if migrationErr := <-errCh; migrationErr != nil {
-			return errors.Wrapf(migrationErr, "failed to run gh-ost migration")
-		}
-		return nil
+		return cutoverFunc()
 	}
 	driver, err := dbFactory.GetAdminDatabaseDriver(ctx, instance, database)
 	if err != nil {
@@ -0,0 +1,157 @@
+package taskrun
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/backend/common/log"
+	"github.com/bytebase/bytebase/backend/component/dbfactory"
+	"github.com/bytebase/bytebase/backend/component/pgosc"
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/store"
+	"github.com/bytebase/bytebase/backend/utils"
+)
+
+// sharedPostgresOnlineState is the online schema change state shared from the sync task to the cutover task for PostgreSQL.
+type sharedPostgresOnlineState struct {
+	migration *pgosc.Migration
+	errCh     <-chan error
+}
+
+// runPostgresOnlineMigration runs the online schema change with the shadow table for PostgreSQL.
+// Like gh-ost, the sync task is done once the shadow table catches up, and the migration keeps
+// replaying the row changes in the background until the cutover task requests the cutover.
+func (exec *SchemaUpdateGhostSyncExecutor) runPostgresOnlineMigration(ctx context.Context, task *store.TaskMessage, instance *store.InstanceMessage, statement string, flags map[string]string) (terminated bool, result *api.TaskRunResultPayload, err error) {
+	statement = strings.TrimSpace(statement)
+
+	database, err := exec.store.GetDatabaseV2(ctx, &store.FindDatabaseMessage{UID: task.DatabaseID})
+	if err != nil {
+		return true, nil, err
+	}
+	if database == nil {
+		return true, nil, errors.Errorf("database not found")
+	}
+
+	materials := utils.GetSecretMapFromDatabaseMessage(database)
+	// To avoid leaking the rendered statement, the error message should use the original statement and not the rendered statement.
+	renderedStatement := utils.RenderStatement(statement, materials)
+
+	driver, err := exec.dbFactory.GetAdminDatabaseDriver(ctx, instance, database)
+	if err != nil {
+		return true, nil, err
+	}
+	migration, err := pgosc.NewMigration(ctx, driver.GetDB(), renderedStatement, flags)
+	if err != nil {
+		driver.Close(ctx)
+		return true, nil, errors.Wrap(err, "failed to init online schema change")
+	}
+
+	// The migration outlives the sync task until the cutover, so it doesn't use the task context.
+	migrationCtx, migrationCancel := context.WithCancel(context.Background())
+	// set buffer size to 1 to unblock the sender because there is no listener if the task is canceled.
+	migrationError := make(chan error, 1)
+	go func() {
+		defer driver.Close(context.Background())
+		defer migrationCancel()
+		if err := migration.Run(migrationCtx); err != nil {
+			slog.Error("failed to run online schema change", log.BBError(err))
+			migrationError <- err
+			return
+		}
+		// we send to migrationError channel anyway because:
+		// 1. before synced, the sync task will receive it.
+		// 2. after synced, the cutover task will receive it.
+		migrationError <- nil
+	}()
+
+	ticker := time.NewTicker(1 * time.Second)
+	defer ticker.Stop()
+	createdTs := time.Now().Unix()
+	for {
+		select {
+		case <-ticker.C:
+			exec.stateCfg.TaskProgress.Store(task.ID, api.Progress{
+				TotalUnit:     migration.GetRowsEstimate(),
+				CompletedUnit: migration.GetRowsCopied(),
+				CreatedTs:     createdTs,
+				UpdatedTs:     time.Now().Unix(),
+			})
+			if migration.IsSynced() {
+				exec.stateCfg.GhostTaskState.Store(task.ID, sharedPostgresOnlineState{migration: migration, errCh: migrationError})
+				return true, &api.TaskRunResultPayload{Detail: "sync done"}, nil
+			}
+		case err := <-migrationError:
+			if err == nil {
+				err = errors.New("online schema change exited before sync done")
+			}
+			return true, nil, err
+		case <-ctx.Done():
+			migrationCancel()
+			return true, nil, errors.New("task canceled")
+		}
+	}
+}
+
+// resumePostgresOnlineMigration resumes the online schema change persisted in the database for the cutover task,
+// which is the case if Bytebase restarts or fails over after the sync task is done.
+func resumePostgresOnlineMigration(ctx context.Context, dbFactory *dbfactory.DBFactory, instance *store.InstanceMessage, database *store.DatabaseMessage, renderedStatement string, flags map[string]string) (*sharedPostgresOnlineState, error) {
+	driver, err := dbFactory.GetAdminDatabaseDriver(ctx, instance, database)
+	if err != nil {
+		return nil, err
+	}
+	migration, err := pgosc.NewMigration(ctx, driver.GetDB(), strings.TrimSpace(renderedStatement), flags)
+	if err != nil {
+		driver.Close(ctx)
+		return nil, errors.Wrap(err, "failed to init online schema change")
+	}
+	resumable, err := migration.IsResumable(ctx)
+	if err != nil {
+		driver.Close(ctx)
+		return nil, err
+	}
+	if !resumable {
+		driver.Close(ctx)
+		return nil, errors.Errorf("online schema change state not found, please rerun the sync task")
+	}
+
+	migrationError := make(chan error, 1)
+	go func() {
+		defer driver.Close(context.Background())
+		if err := migration.Run(context.Background()); err != nil {
+			slog.Error("failed to resume online schema change", log.BBError(err))
+			migrationError <- err
+			return
+		}
+		migrationError <- nil
+	}()
+	return &sharedPostgresOnlineState{migration: migration, errCh: migrationError}, nil
+}
+
+// waitForPostgresCutover waits until the lag of the row changes is within max-lag-millis.
+// It returns true if canceled.
+func waitForPostgresCutover(ctx context.Context, migration *pgosc.Migration) bool {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			lag, err := migration.GetLag(ctx)
+			if err != nil {
+				slog.Warn("failed to get the lag of online schema change", log.BBError(err))
+				continue
+			}
+			if lag <= migration.GetMaxLag() {
+				return false
+			}
+		case <-migration.Done():
+			// The migration exited, let the cutover receive the error.
+			return false
+		case <-ctx.Done():
+			return true
+		}
+	}
+}
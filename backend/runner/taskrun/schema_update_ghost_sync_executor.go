@@ -14,28 +14,32 @@ import (
 
 	"github.com/bytebase/bytebase/backend/common"
 	"github.com/bytebase/bytebase/backend/common/log"
+	"github.com/bytebase/bytebase/backend/component/dbfactory"
 	"github.com/bytebase/bytebase/backend/component/ghost"
 	"github.com/bytebase/bytebase/backend/component/state"
 	api "github.com/bytebase/bytebase/backend/legacyapi"
 	"github.com/bytebase/bytebase/backend/store"
 	"github.com/bytebase/bytebase/backend/utils"
+	storepb "github.com/bytebase/bytebase/proto/generated-go/store"
 	v1pb "github.com/bytebase/bytebase/proto/generated-go/v1"
 )
 
 // NewSchemaUpdateGhostSyncExecutor creates a schema update (gh-ost) sync task executor.
-func NewSchemaUpdateGhostSyncExecutor(store *store.Store, stateCfg *state.State, secret string) Executor {
+func NewSchemaUpdateGhostSyncExecutor(store *store.Store, dbFactory *dbfactory.DBFactory, stateCfg *state.State, secret string) Executor {
 	return &SchemaUpdateGhostSyncExecutor{
-		store:    store,
-		stateCfg: stateCfg,
-		secret:   secret,
+		store:     store,
+		dbFactory: dbFactory,
+		stateCfg:  stateCfg,
+		secret:    secret,
 	}
 }
 
 // SchemaUpdateGhostSyncExecutor is the schema update (gh-ost) sync task executor.
 type SchemaUpdateGhostSyncExecutor struct {
-	store    *store.Store
-	stateCfg *state.State
-	secret   string
+	store     *store.Store
+	dbFactory *dbfactory.DBFactory
+	stateCfg  *state.State
+	secret    string
 }
 
 // RunOnce will run SchemaUpdateGhostSync task once.
@@ -56,6 +60,17 @@ func (exec *SchemaUpdateGhostSyncExecutor) RunOnce(ctx context.Context, _ contex
 		return true, nil, err
 	}
 
+	instance, err := exec.store.GetInstanceV2(ctx, &store.FindInstanceMessage{UID: &task.InstanceID})
+	if err != nil {
+		return true, nil, err
+	}
+	if instance == nil {
+		return true, nil, errors.Errorf("instance %d not found", task.InstanceID)
+	}
+	if instance.Engine == storepb.Engine_POSTGRES {
+		return exec.runPostgresOnlineMigration(ctx, task, instance, statement, payload.Flags)
+	}
+
 	return exec.runGhostMigration(ctx, task, statement, payload.Flags)
 }
 
@@ -0,0 +1,239 @@
+// Package webhookdelivery is a runner that posts the project and workspace webhook deliveries in the outbox with retries.
+package webhookdelivery
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/backend/common/log"
+	"github.com/bytebase/bytebase/backend/plugin/webhook"
+	"github.com/bytebase/bytebase/backend/store"
+)
+
+const (
+	webhookDeliveryInterval = 1 * time.Second
+	// webhookDeliveryBatchSize is the max number of the due deliveries posted in one round.
+	webhookDeliveryBatchSize = 100
+	// maxAttempts is the number of the attempts before the delivery is moved to the dead letter.
+	// The attempts span about 3 hours with the backoff.
+	maxAttempts = 10
+	// retryBaseDelay is the delay after the first failed attempt, and it doubles for every following attempt.
+	retryBaseDelay = 30 * time.Second
+	// retryMaxDelay is the max delay between the attempts.
+	retryMaxDelay = 1 * time.Hour
+	// retentionPeriod is how long the delivered and the dead letter deliveries are kept for the redeliveries.
+	retentionPeriod = 30 * 24 * time.Hour
+	// pruneInterval is the interval of pruning the deliveries after the retention period.
+	pruneInterval = 1 * time.Hour
+)
+
+// NewRunner creates a new webhook delivery runner.
+func NewRunner(store *store.Store) *Runner {
+	return &Runner{
+		store: store,
+	}
+}
+
+// Runner is the webhook delivery runner.
+type Runner struct {
+	store *store.Store
+	// lastPruned is the time of the last pruning, it's only accessed by the Run goroutine.
+	lastPruned time.Time
+}
+
+// Run will run the webhook delivery runner.
+func (r *Runner) Run(ctx context.Context, wg *sync.WaitGroup) {
+	ticker := time.NewTicker(webhookDeliveryInterval)
+	defer ticker.Stop()
+	defer wg.Done()
+	slog.Debug(fmt.Sprintf("Webhook delivery runner started and will run every %s", webhookDeliveryInterval.String()))
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Debug("Webhook delivery runner received context cancellation")
+			return
+		case <-ticker.C:
+			r.deliverAll(ctx)
+			if time.Since(r.lastPruned) >= pruneInterval {
+				r.prune(ctx)
+				r.lastPruned = time.Now()
+			}
+		}
+	}
+}
+
+func (r *Runner) deliverAll(ctx context.Context) {
+	defer func() {
+		if r := recover(); r != nil {
+			err, ok := r.(error)
+			if !ok {
+				err = errors.Errorf("%v", r)
+			}
+			slog.Error("Webhook delivery runner PANIC RECOVER", log.BBError(err), log.BBStack("panic-stack"))
+		}
+	}()
+
+	status := store.WebhookDeliveryPending
+	now := time.Now().Unix()
+	limit := webhookDeliveryBatchSize
+	deliveries, err := r.store.ListWebhookDeliveries(ctx, &store.FindWebhookDeliveryMessage{
+		Status:              &status,
+		NextAttemptTsBefore: &now,
+		Limit:               &limit,
+	})
+	if err != nil {
+		slog.Error("failed to list due webhook deliveries", log.BBError(err))
+		return
+	}
+
+	var wg sync.WaitGroup
+	for _, delivery := range deliveries {
+		wg.Add(1)
+		go func(delivery *store.WebhookDeliveryMessage) {
+			defer wg.Done()
+			if err := r.deliver(ctx, delivery); err != nil {
+				slog.Error("failed to deliver webhook", slog.Int64("delivery", delivery.ID), log.BBError(err))
+			}
+		}(delivery)
+	}
+	wg.Wait()
+}
+
+// prune deletes the delivered and the dead letter deliveries after the retention period.
+func (r *Runner) prune(ctx context.Context) {
+	count, err := r.store.DeleteWebhookDeliveries(ctx, time.Now().Add(-retentionPeriod).Unix())
+	if err != nil {
+		slog.Error("failed to prune webhook deliveries", log.BBError(err))
+		return
+	}
+	if count > 0 {
+		slog.Debug("Pruned webhook deliveries", slog.Int64("count", count))
+	}
+}
+
+// deliver posts the delivery, and records the result of the attempt.
+func (r *Runner) deliver(ctx context.Context, delivery *store.WebhookDeliveryMessage) error {
+	target, err := r.getTarget(ctx, delivery)
+	if err != nil {
+		return err
+	}
+	if target == nil {
+		// The workspace webhooks are not deleted with the deliveries, so we move the deliveries to the dead letter.
+		status := store.WebhookDeliveryDeadLetter
+		errMessage := fmt.Sprintf("workspace webhook %q not found", delivery.WorkspaceWebhookID)
+		if _, err := r.store.UpdateWebhookDelivery(ctx, &store.UpdateWebhookDeliveryMessage{
+			ID:     delivery.ID,
+			Status: &status,
+			Error:  &errMessage,
+		}); err != nil {
+			return errors.Wrapf(err, "failed to update webhook delivery")
+		}
+		return nil
+	}
+	webhookCtx := &webhook.Context{}
+	if err := json.Unmarshal([]byte(delivery.Payload), webhookCtx); err != nil {
+		return errors.Wrapf(err, "failed to unmarshal webhook context")
+	}
+	webhookCtx.URL = target.url
+	webhookCtx.SigningSecret = target.signingSecret
+	webhookCtx.DeliveryID = strconv.FormatInt(delivery.ID, 10)
+
+	attempts := delivery.Attempts + 1
+	update := &store.UpdateWebhookDeliveryMessage{
+		ID:       delivery.ID,
+		Attempts: &attempts,
+	}
+	responseCode, postErr := post(target.webhookType, webhookCtx)
+	errMessage := ""
+	status := store.WebhookDeliveryDelivered
+	if postErr != nil {
+		errMessage = postErr.Error()
+		status = store.WebhookDeliveryPending
+		if attempts >= maxAttempts {
+			status = store.WebhookDeliveryDeadLetter
+		}
+		nextAttemptTs := time.Now().Add(getRetryDelay(attempts)).Unix()
+		update.NextAttemptTs = &nextAttemptTs
+		// The external webhook endpoint might be invalid which is out of our code control, so we just emit a warning
+		slog.Warn("Failed to post webhook event on activity",
+			slog.String("webhook type", target.webhookType),
+			slog.String("webhook name", target.title),
+			slog.String("activity type", webhookCtx.ActivityType),
+			slog.String("title", webhookCtx.Title),
+			slog.Int("attempts", attempts),
+			log.BBError(postErr))
+	}
+	update.Status = &status
+	update.ResponseCode = &responseCode
+	update.Error = &errMessage
+	if _, err := r.store.UpdateWebhookDelivery(ctx, update); err != nil {
+		return errors.Wrapf(err, "failed to update webhook delivery")
+	}
+	return nil
+}
+
+// target is the webhook that the delivery is posted to.
+type target struct {
+	webhookType   string
+	title         string
+	url           string
+	signingSecret string
+}
+
+// getTarget returns the project webhook or the workspace webhook of the delivery, nil if the workspace webhook is removed.
+func (r *Runner) getTarget(ctx context.Context, delivery *store.WebhookDeliveryMessage) (*target, error) {
+	if delivery.WorkspaceWebhookID != "" {
+		setting, err := r.store.GetWorkspaceWebhookSetting(ctx)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get workspace webhook setting")
+		}
+		for _, hook := range setting.Webhooks {
+			if hook.ID == delivery.WorkspaceWebhookID {
+				return &target{webhookType: hook.Type, title: hook.Title, url: hook.URL, signingSecret: hook.SigningSecret}, nil
+			}
+		}
+		return nil, nil
+	}
+	hook, err := r.store.GetProjectWebhookV2(ctx, &store.FindProjectWebhookMessage{ID: &delivery.ProjectWebhookID})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get project webhook %d", delivery.ProjectWebhookID)
+	}
+	if hook == nil {
+		// The deliveries are deleted with the webhook, it should not happen.
+		return nil, errors.Errorf("project webhook %d not found", delivery.ProjectWebhookID)
+	}
+	return &target{webhookType: hook.Type, title: hook.Title, url: hook.URL, signingSecret: hook.SigningSecret}, nil
+}
+
+// post posts the webhook, and returns the HTTP status code of the response, 0 if there is no response.
+func post(webhookType string, webhookCtx *webhook.Context) (int, error) {
+	err := webhook.Post(webhookType, *webhookCtx)
+	if err == nil {
+		return http.StatusOK, nil
+	}
+	var responseErr *webhook.ResponseError
+	if errors.As(err, &responseErr) {
+		return responseErr.StatusCode, err
+	}
+	return 0, err
+}
+
+// getRetryDelay returns the delay before the next attempt after the failed attempts.
+func getRetryDelay(attempts int) time.Duration {
+	delay := retryBaseDelay
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= retryMaxDelay {
+			return retryMaxDelay
+		}
+	}
+	return delay
+}
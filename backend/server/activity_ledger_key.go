@@ -0,0 +1,47 @@
+package server
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/backend/component/config"
+)
+
+const (
+	// activityLedgerKeyFileName is the name of the activity ledger key file generated in the data directory.
+	activityLedgerKeyFileName = "activity_ledger.key"
+	// activityLedgerKeyMinLength is the minimum length of the activity ledger key.
+	activityLedgerKeyMinLength = 32
+)
+
+// getActivityLedgerKey reads the key of the activity ledger hash chain from the key file.
+// If the key file isn't specified, the key is generated in the data directory on the first run.
+func getActivityLedgerKey(profile config.Profile) ([]byte, error) {
+	path := profile.ActivityLedgerKeyFile
+	if path == "" {
+		path = filepath.Join(profile.DataDir, activityLedgerKeyFileName)
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			b := make([]byte, activityLedgerKeyMinLength)
+			if _, err := rand.Read(b); err != nil {
+				return nil, errors.Wrapf(err, "failed to generate activity ledger key")
+			}
+			if err := os.WriteFile(path, []byte(hex.EncodeToString(b)), 0600); err != nil {
+				return nil, errors.Wrapf(err, "failed to write activity ledger key file %q", path)
+			}
+		}
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read activity ledger key file %q", path)
+	}
+	key := strings.TrimSpace(string(content))
+	if len(key) < activityLedgerKeyMinLength {
+		return nil, errors.Errorf("activity ledger key in %q must have at least %d characters", path, activityLedgerKeyMinLength)
+	}
+	return []byte(key), nil
+}
@@ -0,0 +1,40 @@
+package server
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/backend/component/config"
+)
+
+func TestGetActivityLedgerKey(t *testing.T) {
+	a := require.New(t)
+	dataDir := t.TempDir()
+
+	// The key is generated on the first run and reused after.
+	key, err := getActivityLedgerKey(config.Profile{DataDir: dataDir})
+	a.NoError(err)
+	a.Len(key, 2*activityLedgerKeyMinLength)
+	info, err := os.Stat(filepath.Join(dataDir, activityLedgerKeyFileName))
+	a.NoError(err)
+	a.Equal(os.FileMode(0600), info.Mode().Perm())
+	got, err := getActivityLedgerKey(config.Profile{DataDir: dataDir})
+	a.NoError(err)
+	a.Equal(key, got)
+
+	keyFile := filepath.Join(t.TempDir(), "key")
+	a.NoError(os.WriteFile(keyFile, []byte("0123456789abcdef0123456789abcdef\n"), 0600))
+	got, err = getActivityLedgerKey(config.Profile{DataDir: dataDir, ActivityLedgerKeyFile: keyFile})
+	a.NoError(err)
+	a.Equal([]byte("0123456789abcdef0123456789abcdef"), got)
+
+	a.NoError(os.WriteFile(keyFile, []byte("short"), 0600))
+	_, err = getActivityLedgerKey(config.Profile{DataDir: dataDir, ActivityLedgerKeyFile: keyFile})
+	a.Error(err)
+
+	_, err = getActivityLedgerKey(config.Profile{DataDir: dataDir, ActivityLedgerKeyFile: filepath.Join(dataDir, "missing")})
+	a.Error(err)
+}
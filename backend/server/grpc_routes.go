@@ -18,6 +18,7 @@ import (
 	enterprise "github.com/bytebase/bytebase/backend/enterprise/api"
 	api "github.com/bytebase/bytebase/backend/legacyapi"
 	"github.com/bytebase/bytebase/backend/runner/backuprun"
+	"github.com/bytebase/bytebase/backend/runner/ldapsync"
 	"github.com/bytebase/bytebase/backend/runner/metricreport"
 	"github.com/bytebase/bytebase/backend/runner/plancheck"
 	"github.com/bytebase/bytebase/backend/runner/relay"
@@ -35,6 +36,7 @@ func configureGrpcRouters(
 	licenseService enterprise.LicenseService,
 	profile *config.Profile,
 	metricReporter *metricreport.Reporter,
+	ldapSyncer *ldapsync.Syncer,
 	stateCfg *state.State,
 	schemaSyncer *schemasync.Syncer,
 	activityManager *activity.Manager,
@@ -46,7 +48,7 @@ func configureGrpcRouters(
 	errorRecordRing *api.ErrorRecordRing,
 	tokenDuration time.Duration) (*apiv1.RolloutService, *apiv1.IssueService, error) {
 	// Register services.
-	authService, err := apiv1.NewAuthService(stores, secret, tokenDuration, licenseService, metricReporter, profile, stateCfg, postCreateUser)
+	authService, err := apiv1.NewAuthService(stores, secret, tokenDuration, licenseService, metricReporter, ldapSyncer, profile, stateCfg, activityManager, postCreateUser)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -65,7 +67,8 @@ func configureGrpcRouters(
 		secret,
 		stateCfg,
 		dbFactory,
-		schemaSyncer))
+		schemaSyncer,
+		activityManager))
 	v1pb.RegisterProjectServiceServer(grpcServer, apiv1.NewProjectService(stores, activityManager, licenseService))
 	v1pb.RegisterDatabaseServiceServer(grpcServer, apiv1.NewDatabaseService(stores, backupRunner, schemaSyncer, licenseService, profile))
 	v1pb.RegisterInstanceRoleServiceServer(grpcServer, apiv1.NewInstanceRoleService(stores, dbFactory))
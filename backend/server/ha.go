@@ -0,0 +1,440 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/bytebase/bytebase/backend/common/log"
+	"github.com/bytebase/bytebase/backend/component/state"
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/store"
+	v1pb "github.com/bytebase/bytebase/proto/generated-go/v1"
+)
+
+// In the high-availability mode, multiple replicas share the same metadata database.
+// The replicas elect the leader with the PostgreSQL advisory lock, and only the leader runs the background runners.
+// The other replicas serve the API, and forward the in-process events such as the tickles to the leader with
+// LISTEN/NOTIFY. The leader broadcasts the task run execution statuses to the other replicas in the same way.
+// The task run and rollback SQL generation cancellations are propagated through the metadata database.
+const (
+	// haLeaderLockKey is the advisory lock key for the leader election, "bytebase" in hex.
+	haLeaderLockKey = 0x6279746562617365
+	// haElectionInterval is the interval for the non-leader replicas to try to become the leader,
+	// and for the leader to check its lease.
+	haElectionInterval = 5 * time.Second
+	// haEventChannel is the channel for forwarding the events from the non-leader replicas to the leader.
+	haEventChannel = "bb_ha_event"
+	// haExecutionStatusChannel is the channel for broadcasting the task run execution statuses from the leader.
+	haExecutionStatusChannel = "bb_ha_execution_status"
+	// haExecutionStatusTTL is how long the non-leader replicas keep the execution status without receiving the update.
+	haExecutionStatusTTL = 5 * time.Second
+)
+
+type haEventType string
+
+const (
+	haEventTaskRunTickle         haEventType = "TASK_RUN_TICKLE"
+	haEventPlanCheckTickle       haEventType = "PLAN_CHECK_TICKLE"
+	haEventInstanceSyncTickle    haEventType = "INSTANCE_SYNC_TICKLE"
+	haEventTaskSkippedOrDone     haEventType = "TASK_SKIPPED_OR_DONE"
+	haEventInstanceSlowQuerySync haEventType = "INSTANCE_SLOW_QUERY_SYNC"
+	haEventInstanceSync          haEventType = "INSTANCE_SYNC"
+	haEventApprovalFinding       haEventType = "APPROVAL_FINDING"
+	haEventRollbackGenerate      haEventType = "ROLLBACK_GENERATE"
+	// haEventExternalApprovalRelayCancel cancels the external approval from relay for the issue.
+	haEventExternalApprovalRelayCancel haEventType = "EXTERNAL_APPROVAL_RELAY_CANCEL"
+)
+
+// haEvent is the event forwarded from the non-leader replicas to the leader.
+type haEvent struct {
+	Type haEventType `json:"type"`
+	// ID is the task ID, instance UID or issue UID depending on the type.
+	ID         int    `json:"id,omitempty"`
+	InstanceID string `json:"instanceId,omitempty"`
+	ProjectID  string `json:"projectId,omitempty"`
+}
+
+// haExecutionStatus is the task run execution status broadcast by the leader.
+type haExecutionStatus struct {
+	TaskRunID       int                          `json:"taskRunId"`
+	ExecutionStatus v1pb.TaskRun_ExecutionStatus `json:"executionStatus"`
+	UpdateTime      int64                        `json:"updateTime"`
+}
+
+// runHA runs the replica in the high-availability mode.
+// The cache invalidation triggers must be installed before running.
+func (s *Server) runHA(ctx context.Context, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	wg.Add(1)
+	go s.haListen(ctx, wg, "cache invalidation", func(ctx context.Context) error {
+		return s.store.ListenCacheInvalidation(ctx)
+	})
+
+	for {
+		followCtx, cancelFollow := context.WithCancel(ctx)
+		var followWG sync.WaitGroup
+		followWG.Add(1)
+		go s.haFollow(followCtx, &followWG)
+		conn := s.haWaitForLeadership(ctx)
+		cancelFollow()
+		followWG.Wait()
+		if conn == nil {
+			return
+		}
+
+		s.haLead(ctx, conn)
+		if ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+// haWaitForLeadership blocks until becoming the leader, and returns the connection holding the leader lock.
+// It returns nil if the context is done.
+func (s *Server) haWaitForLeadership(ctx context.Context) *sql.Conn {
+	ticker := time.NewTicker(haElectionInterval)
+	defer ticker.Stop()
+	for {
+		conn, err := s.store.TryAcquireAdvisoryLock(ctx, haLeaderLockKey)
+		if err != nil {
+			slog.Error("failed to acquire the leader lock", log.BBError(err))
+		}
+		if conn != nil {
+			return conn
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// haLead runs the background runners until losing the leader lock held by the connection.
+func (s *Server) haLead(ctx context.Context, conn *sql.Conn) {
+	defer conn.Close()
+	slog.Info("Elected as the leader, starting the background runners")
+
+	leadCtx, cancel := context.WithCancel(ctx)
+	var leadWG sync.WaitGroup
+	defer func() {
+		cancel()
+		leadWG.Wait()
+		slog.Info("Stopped the background runners as the leader")
+	}()
+
+	if err := s.startRunners(leadCtx, &leadWG); err != nil {
+		slog.Error("failed to start the background runners", log.BBError(err))
+		return
+	}
+	leadWG.Add(1)
+	go s.haListen(leadCtx, &leadWG, "event", func(ctx context.Context) error {
+		return s.store.Listen(ctx, []string{haEventChannel}, func(_ string, payload string) {
+			s.haHandleEvent(ctx, payload)
+		})
+	})
+	leadWG.Add(1)
+	go s.haLeaderLoop(leadCtx, &leadWG)
+
+	// The advisory lock is released once the session ends, so the leader must stop if the connection is lost.
+	ticker := time.NewTicker(haElectionInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := conn.PingContext(leadCtx); err != nil {
+				slog.Error("lost the leader lock", log.BBError(err))
+				return
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// haLeaderLoop broadcasts the task run execution statuses and propagates the cancellations from the metadata database.
+func (s *Server) haLeaderLoop(ctx context.Context, wg *sync.WaitGroup) {
+	defer wg.Done()
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.haBroadcastExecutionStatuses(ctx)
+			s.haPropagateCancellations(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (s *Server) haBroadcastExecutionStatuses(ctx context.Context) {
+	s.stateCfg.TaskRunExecutionStatuses.Range(func(key, value any) bool {
+		taskRunID, ok := key.(int)
+		if !ok {
+			return true
+		}
+		executionStatus, ok := value.(state.TaskRunExecutionStatus)
+		if !ok {
+			return true
+		}
+		payload, err := json.Marshal(&haExecutionStatus{
+			TaskRunID:       taskRunID,
+			ExecutionStatus: executionStatus.ExecutionStatus,
+			UpdateTime:      executionStatus.UpdateTime.UnixMilli(),
+		})
+		if err != nil {
+			return true
+		}
+		if err := s.store.Notify(ctx, haExecutionStatusChannel, string(payload)); err != nil {
+			slog.Warn("failed to broadcast task run execution status", log.BBError(err))
+			return false
+		}
+		return true
+	})
+}
+
+// haPropagateCancellations cancels the running task runs and rollback SQL generations canceled by the other replicas.
+// The task runs are canceled in the metadata database, and the rollback SQL generations are canceled by disabling the rollback.
+func (s *Server) haPropagateCancellations(ctx context.Context) {
+	var taskRunIDs []int
+	s.stateCfg.RunningTaskRunsCancelFunc.Range(func(key, _ any) bool {
+		if taskRunID, ok := key.(int); ok {
+			taskRunIDs = append(taskRunIDs, taskRunID)
+		}
+		return true
+	})
+	if len(taskRunIDs) > 0 {
+		taskRuns, err := s.store.ListTaskRunsV2(ctx, &store.FindTaskRunMessage{
+			UIDs:   &taskRunIDs,
+			Status: &[]api.TaskRunStatus{api.TaskRunCanceled},
+		})
+		if err != nil {
+			slog.Warn("failed to list canceled task runs", log.BBError(err))
+		}
+		for _, taskRun := range taskRuns {
+			if cancelFunc, ok := s.stateCfg.RunningTaskRunsCancelFunc.Load(taskRun.ID); ok {
+				cancelFunc.(context.CancelFunc)()
+			}
+		}
+	}
+
+	s.stateCfg.RollbackCancel.Range(func(key, value any) bool {
+		taskID, ok := key.(int)
+		if !ok {
+			return true
+		}
+		task, err := s.store.GetTaskV2ByID(ctx, taskID)
+		if err != nil {
+			slog.Warn("failed to get task", slog.Int("taskID", taskID), log.BBError(err))
+			return true
+		}
+		payload := &api.TaskDatabaseDataUpdatePayload{}
+		if err := json.Unmarshal([]byte(task.Payload), payload); err != nil {
+			return true
+		}
+		if !payload.RollbackEnabled {
+			if cancel, ok := value.(context.CancelFunc); ok {
+				cancel()
+			}
+		}
+		return true
+	})
+}
+
+// haHandleEvent applies the event forwarded from the non-leader replicas to the leader.
+func (s *Server) haHandleEvent(ctx context.Context, payload string) {
+	event := &haEvent{}
+	if err := json.Unmarshal([]byte(payload), event); err != nil {
+		slog.Warn("failed to unmarshal high-availability event", slog.String("payload", payload), log.BBError(err))
+		return
+	}
+	switch event.Type {
+	case haEventTaskRunTickle:
+		tickle(s.stateCfg.TaskRunTickleChan, 0)
+	case haEventPlanCheckTickle:
+		tickle(s.stateCfg.PlanCheckTickleChan, 0)
+	case haEventInstanceSyncTickle:
+		tickle(s.stateCfg.InstanceSyncTickleChan, 0)
+	case haEventTaskSkippedOrDone:
+		tickle(s.stateCfg.TaskSkippedOrDoneChan, event.ID)
+	case haEventInstanceSlowQuerySync:
+		select {
+		case s.stateCfg.InstanceSlowQuerySyncChan <- &state.InstanceSlowQuerySyncMessage{InstanceID: event.InstanceID, ProjectID: event.ProjectID}:
+		default:
+		}
+	case haEventInstanceSync:
+		instance, err := s.store.GetInstanceV2(ctx, &store.FindInstanceMessage{UID: &event.ID})
+		if err != nil || instance == nil {
+			slog.Warn("failed to get instance", slog.Int("instanceUID", event.ID), log.BBError(err))
+			return
+		}
+		s.stateCfg.InstanceSyncs.Store(instance.UID, instance)
+	case haEventApprovalFinding:
+		issue, err := s.store.GetIssueV2(ctx, &store.FindIssueMessage{UID: &event.ID})
+		if err != nil || issue == nil {
+			slog.Warn("failed to get issue", slog.Int("issueUID", event.ID), log.BBError(err))
+			return
+		}
+		s.stateCfg.ApprovalFinding.Store(issue.UID, issue)
+	case haEventRollbackGenerate:
+		task, err := s.store.GetTaskV2ByID(ctx, event.ID)
+		if err != nil || task == nil {
+			slog.Warn("failed to get task", slog.Int("taskID", event.ID), log.BBError(err))
+			return
+		}
+		s.stateCfg.RollbackGenerate.Store(task.ID, task)
+	case haEventExternalApprovalRelayCancel:
+		// The cancellation must not be dropped, otherwise the relay keeps polling the canceled approval.
+		select {
+		case s.stateCfg.IssueExternalApprovalRelayCancelChan <- event.ID:
+		case <-ctx.Done():
+		}
+	default:
+		slog.Warn("unknown high-availability event", slog.String("type", string(event.Type)))
+	}
+}
+
+// haFollow forwards the events to the leader and receives the task run execution statuses from the leader.
+func (s *Server) haFollow(ctx context.Context, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	// The API server waits for the external approval check result, so check it locally.
+	wg.Add(1)
+	go s.relayRunner.ListenCheckExternalApprovalChan(ctx, wg)
+
+	var mu sync.Mutex
+	receivedTs := map[int]time.Time{}
+	wg.Add(1)
+	go s.haListen(ctx, wg, "execution status", func(ctx context.Context) error {
+		return s.store.Listen(ctx, []string{haExecutionStatusChannel}, func(_ string, payload string) {
+			status := &haExecutionStatus{}
+			if err := json.Unmarshal([]byte(payload), status); err != nil {
+				return
+			}
+			s.stateCfg.TaskRunExecutionStatuses.Store(status.TaskRunID, state.TaskRunExecutionStatus{
+				ExecutionStatus: status.ExecutionStatus,
+				UpdateTime:      time.UnixMilli(status.UpdateTime),
+			})
+			mu.Lock()
+			receivedTs[status.TaskRunID] = time.Now()
+			mu.Unlock()
+		})
+	})
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				mu.Lock()
+				for taskRunID, ts := range receivedTs {
+					if time.Since(ts) > haExecutionStatusTTL {
+						s.stateCfg.TaskRunExecutionStatuses.Delete(taskRunID)
+						delete(receivedTs, taskRunID)
+					}
+				}
+				mu.Unlock()
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	s.haForwardEvents(ctx, s.haForward)
+}
+
+// haForwardEvents forwards the in-process events to the leader with the forward function until the context is done.
+func (s *Server) haForwardEvents(ctx context.Context, forward func(ctx context.Context, event *haEvent) bool) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stateCfg.TaskRunTickleChan:
+			forward(ctx, &haEvent{Type: haEventTaskRunTickle})
+		case <-s.stateCfg.PlanCheckTickleChan:
+			forward(ctx, &haEvent{Type: haEventPlanCheckTickle})
+		case <-s.stateCfg.InstanceSyncTickleChan:
+			forward(ctx, &haEvent{Type: haEventInstanceSyncTickle})
+		case taskID := <-s.stateCfg.TaskSkippedOrDoneChan:
+			forward(ctx, &haEvent{Type: haEventTaskSkippedOrDone, ID: taskID})
+		case message := <-s.stateCfg.InstanceSlowQuerySyncChan:
+			forward(ctx, &haEvent{Type: haEventInstanceSlowQuerySync, InstanceID: message.InstanceID, ProjectID: message.ProjectID})
+		case issueUID := <-s.stateCfg.IssueExternalApprovalRelayCancelChan:
+			// The cancellation must reach the leader running the relay, so retry until forwarded.
+			for !forward(ctx, &haEvent{Type: haEventExternalApprovalRelayCancel, ID: issueUID}) {
+				select {
+				case <-ticker.C:
+				case <-ctx.Done():
+					return
+				}
+			}
+		case <-ticker.C:
+			haForwardPending(ctx, &s.stateCfg.InstanceSyncs, haEventInstanceSync, forward)
+			haForwardPending(ctx, &s.stateCfg.ApprovalFinding, haEventApprovalFinding, forward)
+			haForwardPending(ctx, &s.stateCfg.RollbackGenerate, haEventRollbackGenerate, forward)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// haForwardPending forwards the pending entries keyed by ID in the map to the leader.
+func haForwardPending(ctx context.Context, m *sync.Map, eventType haEventType, forward func(ctx context.Context, event *haEvent) bool) {
+	m.Range(func(key, _ any) bool {
+		id, ok := key.(int)
+		if !ok {
+			return true
+		}
+		if forward(ctx, &haEvent{Type: eventType, ID: id}) {
+			m.Delete(key)
+		}
+		return true
+	})
+}
+
+// haForward forwards the event to the leader, and returns whether it succeeded.
+func (s *Server) haForward(ctx context.Context, event *haEvent) bool {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return false
+	}
+	if err := s.store.Notify(ctx, haEventChannel, string(payload)); err != nil {
+		slog.Warn("failed to forward high-availability event", slog.String("type", string(event.Type)), log.BBError(err))
+		return false
+	}
+	return true
+}
+
+// haListen keeps listening with the listen function until the context is done.
+func (s *Server) haListen(ctx context.Context, wg *sync.WaitGroup, name string, listen func(ctx context.Context) error) {
+	defer wg.Done()
+	for {
+		err := listen(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		slog.Warn("high-availability listener stopped, reconnecting", slog.String("listener", name), log.BBError(err))
+		select {
+		case <-time.After(time.Second):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// tickle sends the value to the channel without blocking.
+func tickle(ch chan int, value int) {
+	select {
+	case ch <- value:
+	default:
+	}
+}
@@ -0,0 +1,105 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/backend/component/state"
+	"github.com/bytebase/bytebase/backend/store"
+)
+
+func TestHAForwardEvents(t *testing.T) {
+	a := require.New(t)
+	stateCfg, err := state.New()
+	a.NoError(err)
+	s := &Server{stateCfg: stateCfg}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events := make(chan *haEvent, 10)
+	failures := 1
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		s.haForwardEvents(ctx, func(_ context.Context, event *haEvent) bool {
+			// The first cancellation fails to be forwarded, and must be retried.
+			if event.Type == haEventExternalApprovalRelayCancel && failures > 0 {
+				failures--
+				return false
+			}
+			events <- event
+			return true
+		})
+	}()
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+
+	receive := func() *haEvent {
+		select {
+		case event := <-events:
+			return event
+		case <-time.After(5 * time.Second):
+			a.FailNow("timeout waiting for the forwarded event")
+			return nil
+		}
+	}
+
+	stateCfg.TaskRunTickleChan <- 0
+	a.Equal(&haEvent{Type: haEventTaskRunTickle}, receive())
+
+	stateCfg.TaskSkippedOrDoneChan <- 101
+	a.Equal(&haEvent{Type: haEventTaskSkippedOrDone, ID: 101}, receive())
+
+	stateCfg.IssueExternalApprovalRelayCancelChan <- 102
+	a.Equal(&haEvent{Type: haEventExternalApprovalRelayCancel, ID: 102}, receive())
+	a.Equal(0, failures)
+
+	stateCfg.ApprovalFinding.Store(103, &store.IssueMessage{UID: 103})
+	a.Equal(&haEvent{Type: haEventApprovalFinding, ID: 103}, receive())
+	_, ok := stateCfg.ApprovalFinding.Load(103)
+	a.False(ok)
+}
+
+func TestHAHandleEvent(t *testing.T) {
+	a := require.New(t)
+	stateCfg, err := state.New()
+	a.NoError(err)
+	s := &Server{stateCfg: stateCfg}
+	ctx := context.Background()
+
+	handle := func(event *haEvent) {
+		payload, err := json.Marshal(event)
+		a.NoError(err)
+		s.haHandleEvent(ctx, string(payload))
+	}
+
+	handle(&haEvent{Type: haEventPlanCheckTickle})
+	a.Len(stateCfg.PlanCheckTickleChan, 1)
+
+	handle(&haEvent{Type: haEventTaskSkippedOrDone, ID: 101})
+	a.Equal(101, <-stateCfg.TaskSkippedOrDoneChan)
+
+	handle(&haEvent{Type: haEventExternalApprovalRelayCancel, ID: 102})
+	a.Equal(102, <-stateCfg.IssueExternalApprovalRelayCancelChan)
+
+	// The cancellation waits for the relay instead of being dropped.
+	handle(&haEvent{Type: haEventExternalApprovalRelayCancel, ID: 103})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		handle(&haEvent{Type: haEventExternalApprovalRelayCancel, ID: 104})
+	}()
+	a.Equal(103, <-stateCfg.IssueExternalApprovalRelayCancelChan)
+	<-done
+	a.Equal(104, <-stateCfg.IssueExternalApprovalRelayCancelChan)
+
+	handle(&haEvent{Type: haEventInstanceSlowQuerySync, InstanceID: "mysql", ProjectID: "project"})
+	a.Equal(&state.InstanceSlowQuerySyncMessage{InstanceID: "mysql", ProjectID: "project"}, <-stateCfg.InstanceSlowQuerySyncChan)
+}
@@ -20,8 +20,11 @@ import (
 	"github.com/grpc-ecosystem/go-grpc-middleware/v2/interceptors/recovery"
 
 	"github.com/bytebase/bytebase/backend/api/auth"
+	"github.com/bytebase/bytebase/backend/api/externalapproval"
 	"github.com/bytebase/bytebase/backend/api/gitops"
+	"github.com/bytebase/bytebase/backend/api/imapproval"
 	"github.com/bytebase/bytebase/backend/api/lsp"
+	"github.com/bytebase/bytebase/backend/api/scim"
 	apiv1 "github.com/bytebase/bytebase/backend/api/v1"
 	"github.com/bytebase/bytebase/backend/common/log"
 	"github.com/bytebase/bytebase/backend/common/stacktrace"
@@ -40,7 +43,11 @@ import (
 	"github.com/bytebase/bytebase/backend/resources/mysqlutil"
 	"github.com/bytebase/bytebase/backend/resources/postgres"
 	"github.com/bytebase/bytebase/backend/runner/approval"
+	"github.com/bytebase/bytebase/backend/runner/approvaltimeout"
+	"github.com/bytebase/bytebase/backend/runner/auditexport"
 	"github.com/bytebase/bytebase/backend/runner/backuprun"
+	"github.com/bytebase/bytebase/backend/runner/grantexpiry"
+	"github.com/bytebase/bytebase/backend/runner/ldapsync"
 	"github.com/bytebase/bytebase/backend/runner/mail"
 	"github.com/bytebase/bytebase/backend/runner/metricreport"
 	"github.com/bytebase/bytebase/backend/runner/plancheck"
@@ -49,6 +56,7 @@ import (
 	"github.com/bytebase/bytebase/backend/runner/schemasync"
 	"github.com/bytebase/bytebase/backend/runner/slowquerysync"
 	"github.com/bytebase/bytebase/backend/runner/taskrun"
+	"github.com/bytebase/bytebase/backend/runner/webhookdelivery"
 	"github.com/bytebase/bytebase/backend/store"
 	_ "github.com/bytebase/bytebase/docs/openapi" // initial the swagger doc
 )
@@ -56,6 +64,8 @@ import (
 const (
 	// webhookAPIPrefix is the API prefix for Bytebase webhook.
 	webhookAPIPrefix = "/hook"
+	// scimAPIPrefix is the API prefix for SCIM provisioning.
+	scimAPIPrefix = "/scim/v2"
 	// lspAPI is the API for Bytebase Language Server Protocol.
 	lspAPI                 = "/lsp"
 	maxStacksize           = 1024 * 10240
@@ -65,17 +75,23 @@ const (
 // Server is the Bytebase server.
 type Server struct {
 	// Asynchronous runners.
-	taskSchedulerV2    *taskrun.SchedulerV2
-	planCheckScheduler *plancheck.Scheduler
-	metricReporter     *metricreport.Reporter
-	schemaSyncer       *schemasync.Syncer
-	slowQuerySyncer    *slowquerysync.Syncer
-	mailSender         *mail.SlowQueryWeeklyMailSender
-	backupRunner       *backuprun.Runner
-	rollbackRunner     *rollbackrun.Runner
-	approvalRunner     *approval.Runner
-	relayRunner        *relay.Runner
-	runnerWG           sync.WaitGroup
+	taskSchedulerV2       *taskrun.SchedulerV2
+	planCheckScheduler    *plancheck.Scheduler
+	metricReporter        *metricreport.Reporter
+	schemaSyncer          *schemasync.Syncer
+	slowQuerySyncer       *slowquerysync.Syncer
+	ldapSyncer            *ldapsync.Syncer
+	mailSender            *mail.SlowQueryWeeklyMailSender
+	issueMailSender       *mail.IssueMailSender
+	backupRunner          *backuprun.Runner
+	rollbackRunner        *rollbackrun.Runner
+	approvalRunner        *approval.Runner
+	approvalTimeoutRunner *approvaltimeout.Runner
+	relayRunner           *relay.Runner
+	grantExpiryRunner     *grantexpiry.Runner
+	auditExportRunner     *auditexport.Runner
+	webhookDeliveryRunner *webhookdelivery.Runner
+	runnerWG              sync.WaitGroup
 
 	activityManager *activity.Manager
 
@@ -194,6 +210,11 @@ func NewServer(ctx context.Context, profile config.Profile) (*Server, error) {
 		return nil, errors.Wrap(err, "cannot open metadb")
 	}
 	storeInstance := store.New(storeDB)
+	activityLedgerKey, err := getActivityLedgerKey(profile)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get activity ledger key")
+	}
+	storeInstance.SetActivityLedgerKey(activityLedgerKey)
 	if profile.Readonly {
 		slog.Info("Database is opened in readonly mode. Skip migration and demo data setup.")
 	} else {
@@ -251,14 +272,20 @@ func NewServer(ctx context.Context, profile config.Profile) (*Server, error) {
 	}
 
 	s.metricReporter = metricreport.NewReporter(s.store, s.licenseService, &s.profile, false)
-	s.schemaSyncer = schemasync.NewSyncer(storeInstance, s.dbFactory, s.stateCfg, profile, s.licenseService)
+	s.schemaSyncer = schemasync.NewSyncer(storeInstance, s.dbFactory, s.stateCfg, profile, s.licenseService, s.activityManager)
+	s.ldapSyncer = ldapsync.NewSyncer(storeInstance, s.licenseService)
 	if !profile.Readonly {
 		s.slowQuerySyncer = slowquerysync.NewSyncer(storeInstance, s.dbFactory, s.stateCfg, profile)
 		s.backupRunner = backuprun.NewRunner(storeInstance, s.dbFactory, s.s3Client, s.stateCfg, &profile)
 		s.rollbackRunner = rollbackrun.NewRunner(&profile, storeInstance, s.dbFactory, s.stateCfg)
 		s.mailSender = mail.NewSender(s.store, s.stateCfg)
+		s.issueMailSender = mail.NewIssueMailSender(s.store)
 		s.relayRunner = relay.NewRunner(storeInstance, s.activityManager, s.stateCfg)
 		s.approvalRunner = approval.NewRunner(storeInstance, s.dbFactory, s.stateCfg, s.activityManager, s.relayRunner, s.licenseService)
+		s.approvalTimeoutRunner = approvaltimeout.NewRunner(storeInstance, s.activityManager)
+		s.grantExpiryRunner = grantexpiry.NewRunner(storeInstance, s.activityManager)
+		s.auditExportRunner = auditexport.NewRunner(storeInstance)
+		s.webhookDeliveryRunner = webhookdelivery.NewRunner(storeInstance)
 
 		s.taskSchedulerV2 = taskrun.NewSchedulerV2(storeInstance, s.stateCfg, s.activityManager)
 		s.taskSchedulerV2.Register(api.TaskGeneral, taskrun.NewDefaultExecutor())
@@ -267,8 +294,8 @@ func NewServer(ctx context.Context, profile config.Profile) (*Server, error) {
 		s.taskSchedulerV2.Register(api.TaskDatabaseSchemaUpdate, taskrun.NewSchemaUpdateExecutor(storeInstance, s.dbFactory, s.activityManager, s.licenseService, s.stateCfg, s.schemaSyncer, profile))
 		s.taskSchedulerV2.Register(api.TaskDatabaseSchemaUpdateSDL, taskrun.NewSchemaUpdateSDLExecutor(storeInstance, s.dbFactory, s.activityManager, s.licenseService, s.stateCfg, s.schemaSyncer, profile))
 		s.taskSchedulerV2.Register(api.TaskDatabaseDataUpdate, taskrun.NewDataUpdateExecutor(storeInstance, s.dbFactory, s.activityManager, s.licenseService, s.stateCfg, profile))
-		s.taskSchedulerV2.Register(api.TaskDatabaseBackup, taskrun.NewDatabaseBackupExecutor(storeInstance, s.dbFactory, s.s3Client, s.stateCfg, profile))
-		s.taskSchedulerV2.Register(api.TaskDatabaseSchemaUpdateGhostSync, taskrun.NewSchemaUpdateGhostSyncExecutor(storeInstance, s.stateCfg, s.secret))
+		s.taskSchedulerV2.Register(api.TaskDatabaseBackup, taskrun.NewDatabaseBackupExecutor(storeInstance, s.dbFactory, s.s3Client, s.activityManager, s.stateCfg, profile))
+		s.taskSchedulerV2.Register(api.TaskDatabaseSchemaUpdateGhostSync, taskrun.NewSchemaUpdateGhostSyncExecutor(storeInstance, s.dbFactory, s.stateCfg, s.secret))
 		s.taskSchedulerV2.Register(api.TaskDatabaseSchemaUpdateGhostCutover, taskrun.NewSchemaUpdateGhostCutoverExecutor(storeInstance, s.dbFactory, s.activityManager, s.licenseService, s.stateCfg, s.schemaSyncer, profile))
 		s.taskSchedulerV2.Register(api.TaskDatabaseRestorePITRRestore, taskrun.NewPITRRestoreExecutor(storeInstance, s.dbFactory, s.s3Client, s.schemaSyncer, s.stateCfg, profile))
 		s.taskSchedulerV2.Register(api.TaskDatabaseRestorePITRCutover, taskrun.NewPITRCutoverExecutor(storeInstance, s.dbFactory, s.schemaSyncer, s.stateCfg, s.backupRunner, s.activityManager, profile))
@@ -280,19 +307,21 @@ func NewServer(ctx context.Context, profile config.Profile) (*Server, error) {
 		s.planCheckScheduler.Register(store.PlanCheckDatabaseStatementType, statementTypeExecutor)
 		statementAdviseExecutor := plancheck.NewStatementAdviseExecutor(storeInstance, s.dbFactory, s.licenseService)
 		s.planCheckScheduler.Register(store.PlanCheckDatabaseStatementAdvise, statementAdviseExecutor)
-		ghostSyncExecutor := plancheck.NewGhostSyncExecutor(storeInstance, s.secret)
+		ghostSyncExecutor := plancheck.NewGhostSyncExecutor(storeInstance, s.dbFactory, s.secret)
 		s.planCheckScheduler.Register(store.PlanCheckDatabaseGhostSync, ghostSyncExecutor)
 		pitrMySQLExecutor := plancheck.NewPITRMySQLExecutor(storeInstance, s.dbFactory)
 		s.planCheckScheduler.Register(store.PlanCheckDatabasePITRMySQL, pitrMySQLExecutor)
 		statementReportExecutor := plancheck.NewStatementReportExecutor(storeInstance, s.dbFactory)
 		s.planCheckScheduler.Register(store.PlanCheckDatabaseStatementSummaryReport, statementReportExecutor)
+		shadowDryRunExecutor := plancheck.NewShadowDryRunExecutor(storeInstance, s.dbFactory)
+		s.planCheckScheduler.Register(store.PlanCheckDatabaseShadowDryRun, shadowDryRunExecutor)
 
 		// Metric reporter
 		s.initMetricReporter()
 	}
 
 	// Setup the gRPC and grpc-gateway.
-	authProvider := auth.New(s.store, s.secret, tokenDuration, s.licenseService, s.stateCfg, profile.Mode)
+	authProvider := auth.New(s.store, s.secret, tokenDuration, s.licenseService, s.stateCfg, profile.Mode, profile.TrustedProxies)
 	aclProvider := apiv1.NewACLInterceptor(s.store, s.secret, s.licenseService, profile.Mode)
 	debugProvider := apiv1.NewDebugInterceptor(&s.errorRecordRing, &profile, s.metricReporter)
 	onPanic := func(p any) error {
@@ -337,7 +366,7 @@ func NewServer(ctx context.Context, profile config.Profile) (*Server, error) {
 		}
 		return nil
 	}
-	rolloutService, issueService, err := configureGrpcRouters(ctx, mux, s.grpcServer, s.store, s.dbFactory, s.licenseService, &s.profile, s.metricReporter, s.stateCfg, s.schemaSyncer, s.activityManager, s.backupRunner, s.relayRunner, s.planCheckScheduler, postCreateUser, s.secret, &s.errorRecordRing, tokenDuration)
+	rolloutService, issueService, err := configureGrpcRouters(ctx, mux, s.grpcServer, s.store, s.dbFactory, s.licenseService, &s.profile, s.metricReporter, s.ldapSyncer, s.stateCfg, s.schemaSyncer, s.activityManager, s.backupRunner, s.relayRunner, s.planCheckScheduler, postCreateUser, s.secret, &s.errorRecordRing, tokenDuration)
 	if err != nil {
 		return nil, err
 	}
@@ -346,6 +375,14 @@ func NewServer(ctx context.Context, profile config.Profile) (*Server, error) {
 	webhookGroup := s.e.Group(webhookAPIPrefix)
 	gitOpsService := gitops.NewService(s.store, s.dbFactory, s.activityManager, s.stateCfg, s.licenseService, rolloutService, issueService)
 	gitOpsService.RegisterWebhookRoutes(webhookGroup)
+	externalApprovalService := externalapproval.NewService(s.store, s.relayRunner)
+	externalApprovalService.RegisterWebhookRoutes(webhookGroup)
+	imApprovalService := imapproval.NewService(ctx, s.store, issueService)
+	imApprovalService.RegisterWebhookRoutes(webhookGroup)
+
+	scimGroup := s.e.Group(scimAPIPrefix)
+	scimService := scim.NewService(s.store, s.licenseService)
+	scimService.RegisterRoutes(scimGroup)
 
 	reflection.Register(s.grpcServer)
 
@@ -362,31 +399,23 @@ func (s *Server) Run(ctx context.Context, port int) error {
 	s.cancel = cancel
 	if !s.profile.Readonly {
 		// runnerWG waits for all goroutines to complete.
-		if err := s.taskSchedulerV2.ClearRunningTaskRuns(ctx); err != nil {
-			return errors.Wrap(err, "failed to clear existing RUNNING tasks before starting the task scheduler")
+		if s.profile.HA {
+			if err := s.store.InstallCacheInvalidationTriggers(ctx); err != nil {
+				return errors.Wrapf(err, "failed to install cache invalidation triggers")
+			}
+			s.runnerWG.Add(1)
+			go s.runHA(ctx, &s.runnerWG)
+		} else {
+			// Other replicas may still run in the high-availability mode, so only uninstall the triggers when asked.
+			if s.profile.UninstallHATriggers {
+				if err := s.store.UninstallCacheInvalidationTriggers(ctx); err != nil {
+					return errors.Wrapf(err, "failed to uninstall cache invalidation triggers")
+				}
+			}
+			if err := s.startRunners(ctx, &s.runnerWG); err != nil {
+				return err
+			}
 		}
-		s.runnerWG.Add(1)
-		go s.taskSchedulerV2.Run(ctx, &s.runnerWG)
-		s.runnerWG.Add(1)
-		go s.schemaSyncer.Run(ctx, &s.runnerWG)
-		s.runnerWG.Add(1)
-		go s.slowQuerySyncer.Run(ctx, &s.runnerWG)
-		s.runnerWG.Add(1)
-		go s.mailSender.Run(ctx, &s.runnerWG)
-		s.runnerWG.Add(1)
-		go s.backupRunner.Run(ctx, &s.runnerWG)
-		s.runnerWG.Add(1)
-		go s.rollbackRunner.Run(ctx, &s.runnerWG)
-		s.runnerWG.Add(1)
-		go s.approvalRunner.Run(ctx, &s.runnerWG)
-		s.runnerWG.Add(1)
-		go s.relayRunner.Run(ctx, &s.runnerWG)
-
-		s.runnerWG.Add(1)
-		go s.metricReporter.Run(ctx, &s.runnerWG)
-
-		s.runnerWG.Add(1)
-		go s.planCheckScheduler.Run(ctx, &s.runnerWG)
 	}
 
 	listen, err := net.Listen("tcp", fmt.Sprintf(":%d", port+1))
@@ -402,6 +431,48 @@ func (s *Server) Run(ctx context.Context, port int) error {
 	return s.e.Start(fmt.Sprintf(":%d", port))
 }
 
+// startRunners starts the background runners.
+func (s *Server) startRunners(ctx context.Context, wg *sync.WaitGroup) error {
+	if err := s.taskSchedulerV2.ClearRunningTaskRuns(ctx); err != nil {
+		return errors.Wrap(err, "failed to clear existing RUNNING tasks before starting the task scheduler")
+	}
+	wg.Add(1)
+	go s.taskSchedulerV2.Run(ctx, wg)
+	wg.Add(1)
+	go s.schemaSyncer.Run(ctx, wg)
+	wg.Add(1)
+	go s.slowQuerySyncer.Run(ctx, wg)
+	wg.Add(1)
+	go s.mailSender.Run(ctx, wg)
+	wg.Add(1)
+	go s.issueMailSender.Run(ctx, wg)
+	wg.Add(1)
+	go s.backupRunner.Run(ctx, wg)
+	wg.Add(1)
+	go s.rollbackRunner.Run(ctx, wg)
+	wg.Add(1)
+	go s.approvalRunner.Run(ctx, wg)
+	wg.Add(1)
+	go s.approvalTimeoutRunner.Run(ctx, wg)
+	wg.Add(1)
+	go s.relayRunner.Run(ctx, wg)
+	wg.Add(1)
+	go s.grantExpiryRunner.Run(ctx, wg)
+	wg.Add(1)
+	go s.auditExportRunner.Run(ctx, wg)
+	wg.Add(1)
+	go s.webhookDeliveryRunner.Run(ctx, wg)
+	wg.Add(1)
+	go s.ldapSyncer.Run(ctx, wg)
+
+	wg.Add(1)
+	go s.metricReporter.Run(ctx, wg)
+
+	wg.Add(1)
+	go s.planCheckScheduler.Run(ctx, wg)
+	return nil
+}
+
 // Shutdown will shut down the server.
 func (s *Server) Shutdown(ctx context.Context) error {
 	slog.Info("Stopping Bytebase...")
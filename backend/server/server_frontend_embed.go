@@ -52,5 +52,5 @@ func embedFrontend(e *echo.Echo) {
 // defaultAPIRequestSkipper is echo skipper for api requests.
 func defaultAPIRequestSkipper(c echo.Context) bool {
 	path := c.Path()
-	return common.HasPrefixes(path, "/api", "/v1", "/hook")
+	return common.HasPrefixes(path, "/api", "/v1", "/hook", "/scim")
 }
@@ -0,0 +1,252 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgtype"
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/backend/common"
+)
+
+// AccessTokenMessage is the message for a personal access token of a user or service account.
+type AccessTokenMessage struct {
+	PrincipalUID int
+	Title        string
+	// TokenHash is the hex encoded SHA-256 hash of the token, the token itself is never stored.
+	TokenHash string
+	Scopes    []string
+	ExpireTs  int64
+
+	// Output only fields.
+	UID        int
+	CreatedTs  int64
+	LastUsedTs int64
+	LastUsedIP string
+}
+
+// FindAccessTokenMessage is the message for finding access tokens.
+type FindAccessTokenMessage struct {
+	UID          *int
+	PrincipalUID *int
+	TokenHash    *string
+}
+
+// UpdateAccessTokenMessage is the message for updating an access token.
+type UpdateAccessTokenMessage struct {
+	UID int
+
+	TokenHash  *string
+	ExpireTs   *int64
+	LastUsedTs *int64
+	LastUsedIP *string
+}
+
+// GetAccessToken gets an access token.
+func (s *Store) GetAccessToken(ctx context.Context, find *FindAccessTokenMessage) (*AccessTokenMessage, error) {
+	tokens, err := s.ListAccessTokens(ctx, find)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, nil
+	}
+	if len(tokens) > 1 {
+		return nil, &common.Error{Code: common.Conflict, Err: errors.Errorf("found %d access tokens with filter %+v, expect 1", len(tokens), find)}
+	}
+	return tokens[0], nil
+}
+
+// ListAccessTokens lists access tokens.
+func (s *Store) ListAccessTokens(ctx context.Context, find *FindAccessTokenMessage) ([]*AccessTokenMessage, error) {
+	where, args := []string{"TRUE"}, []any{}
+	if v := find.UID; v != nil {
+		where, args = append(where, fmt.Sprintf("id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.PrincipalUID; v != nil {
+		where, args = append(where, fmt.Sprintf("principal_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.TokenHash; v != nil {
+		where, args = append(where, fmt.Sprintf("token_hash = $%d", len(args)+1)), append(args, *v)
+	}
+
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, fmt.Sprintf(`
+		SELECT
+			id,
+			created_ts,
+			principal_id,
+			title,
+			token_hash,
+			scopes,
+			expire_ts,
+			last_used_ts,
+			last_used_ip
+		FROM access_token
+		WHERE %s
+		ORDER BY id ASC`, strings.Join(where, " AND ")),
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tokens []*AccessTokenMessage
+	for rows.Next() {
+		token := &AccessTokenMessage{}
+		var scopes pgtype.TextArray
+		if err := rows.Scan(
+			&token.UID,
+			&token.CreatedTs,
+			&token.PrincipalUID,
+			&token.Title,
+			&token.TokenHash,
+			&scopes,
+			&token.ExpireTs,
+			&token.LastUsedTs,
+			&token.LastUsedIP,
+		); err != nil {
+			return nil, err
+		}
+		if err := scopes.AssignTo(&token.Scopes); err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, token)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// CreateAccessToken creates an access token.
+func (s *Store) CreateAccessToken(ctx context.Context, create *AccessTokenMessage) (*AccessTokenMessage, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	token := &AccessTokenMessage{
+		PrincipalUID: create.PrincipalUID,
+		Title:        create.Title,
+		TokenHash:    create.TokenHash,
+		Scopes:       create.Scopes,
+		ExpireTs:     create.ExpireTs,
+	}
+	if err := tx.QueryRowContext(ctx, `
+		INSERT INTO access_token (
+			principal_id,
+			title,
+			token_hash,
+			scopes,
+			expire_ts
+		)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_ts
+	`,
+		create.PrincipalUID,
+		create.Title,
+		create.TokenHash,
+		create.Scopes,
+		create.ExpireTs,
+	).Scan(
+		&token.UID,
+		&token.CreatedTs,
+	); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+// UpdateAccessToken updates an access token.
+func (s *Store) UpdateAccessToken(ctx context.Context, patch *UpdateAccessTokenMessage) (*AccessTokenMessage, error) {
+	set, args := []string{}, []any{}
+	if v := patch.TokenHash; v != nil {
+		set, args = append(set, fmt.Sprintf("token_hash = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.ExpireTs; v != nil {
+		set, args = append(set, fmt.Sprintf("expire_ts = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.LastUsedTs; v != nil {
+		set, args = append(set, fmt.Sprintf("last_used_ts = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.LastUsedIP; v != nil {
+		set, args = append(set, fmt.Sprintf("last_used_ip = $%d", len(args)+1)), append(args, *v)
+	}
+	if len(set) == 0 {
+		return nil, errors.New("no update field provided")
+	}
+	args = append(args, patch.UID)
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	token := &AccessTokenMessage{}
+	var scopes pgtype.TextArray
+	if err := tx.QueryRowContext(ctx, fmt.Sprintf(`
+		UPDATE access_token
+		SET %s
+		WHERE id = $%d
+		RETURNING id, created_ts, principal_id, title, token_hash, scopes, expire_ts, last_used_ts, last_used_ip
+	`, strings.Join(set, ", "), len(args)),
+		args...,
+	).Scan(
+		&token.UID,
+		&token.CreatedTs,
+		&token.PrincipalUID,
+		&token.Title,
+		&token.TokenHash,
+		&scopes,
+		&token.ExpireTs,
+		&token.LastUsedTs,
+		&token.LastUsedIP,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &common.Error{Code: common.NotFound, Err: errors.Errorf("access token ID not found: %d", patch.UID)}
+		}
+		return nil, err
+	}
+	if err := scopes.AssignTo(&token.Scopes); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+// DeleteAccessToken deletes an access token.
+func (s *Store) DeleteAccessToken(ctx context.Context, uid int) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM access_token WHERE id = $1`, uid); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -285,6 +285,7 @@ func (s *Store) CreateActivityV2(ctx context.Context, create *ActivityMessage) (
 	if err := tx.Commit(); err != nil {
 		return nil, err
 	}
+	s.trySealActivityLedger(ctx)
 
 	return activityList[0], nil
 }
@@ -305,6 +306,7 @@ func (s *Store) BatchCreateActivityV2(ctx context.Context, creates []*ActivityMe
 	if err := tx.Commit(); err != nil {
 		return nil, err
 	}
+	s.trySealActivityLedger(ctx)
 
 	return activityList, nil
 }
@@ -373,10 +375,14 @@ func (s *Store) UpdateActivityV2(ctx context.Context, update *UpdateActivityMess
 	if activity.Payload, err = convertProtoPayloadToAPIPayload(activity.Type, protoPayload); err != nil {
 		return nil, err
 	}
+	if err := appendActivityLedgerImpl(ctx, tx, []*ActivityMessage{&activity}, []string{protoPayload}); err != nil {
+		return nil, err
+	}
 
 	if err := tx.Commit(); err != nil {
 		return nil, err
 	}
+	s.trySealActivityLedger(ctx)
 
 	return &activity, nil
 }
@@ -584,6 +590,7 @@ func createActivityImplV2(ctx context.Context, tx *Tx, creates ...*ActivityMessa
 	}
 
 	var activityList []*ActivityMessage
+	var storedPayloads []string
 	rows, err := tx.QueryContext(ctx, query.String(), values...)
 	if err != nil {
 		return nil, err
@@ -610,9 +617,13 @@ func createActivityImplV2(ctx context.Context, tx *Tx, creates ...*ActivityMessa
 			return nil, err
 		}
 		activityList = append(activityList, &activity)
+		storedPayloads = append(storedPayloads, protoPayload)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
+	if err := appendActivityLedgerImpl(ctx, tx, activityList, storedPayloads); err != nil {
+		return nil, err
+	}
 	return activityList, nil
 }
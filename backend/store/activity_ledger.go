@@ -0,0 +1,457 @@
+package store
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/backend/common/log"
+)
+
+// activityLedgerSealLockKey is the advisory lock key for sealing the activity ledger, "bbledger" in hex.
+const activityLedgerSealLockKey = 0x62626c6564676572
+
+// ActivityLedgerMessage is the record in the hash chain of the activities.
+type ActivityLedgerMessage struct {
+	ID          int64
+	CreatedTs   int64
+	ActivityUID int
+	// ContentHash is the hash of the activity when the record is appended.
+	ContentHash string
+	// Seq is the position of the record in the hash chain.
+	Seq int64
+	// PrevHash is the hash of the previous record, it's empty for the first record.
+	PrevHash string
+	Hash     string
+}
+
+// FindActivityLedgerMessage is the message for listing the sealed activity ledger records.
+type FindActivityLedgerMessage struct {
+	// SeqAfter lists the records after the position in the hash chain.
+	SeqAfter int64
+	Limit    int
+}
+
+// ActivityLedgerVerification is the result of verifying the activity ledger.
+type ActivityLedgerVerification struct {
+	RecordCount int64
+	HeadHash    string
+	// ActivityUID is the first activity failing the verification, it's 0 if the verification passes.
+	ActivityUID int
+	Reason      string
+}
+
+// activityContent is the content of the activity covered by the content hash.
+// The field order is part of the hash, so don't reorder the fields.
+type activityContent struct {
+	ID          int    `json:"id"`
+	CreatorID   int    `json:"creatorId"`
+	CreatedTs   int64  `json:"createdTs"`
+	UpdaterID   int    `json:"updaterId"`
+	UpdatedTs   int64  `json:"updatedTs"`
+	ContainerID int    `json:"containerId"`
+	Type        string `json:"type"`
+	Level       string `json:"level"`
+	Comment     string `json:"comment"`
+	Payload     string `json:"payload"`
+}
+
+// getActivityContentHash returns the hex encoded SHA-256 hash of the activity.
+// The payload is the payload stored in the activity table rather than the converted API payload.
+func getActivityContentHash(activity *ActivityMessage, storedPayload string) (string, error) {
+	b, err := json.Marshal(&activityContent{
+		ID:          activity.UID,
+		CreatorID:   activity.CreatorUID,
+		CreatedTs:   activity.CreatedTs,
+		UpdaterID:   activity.UpdaterUID,
+		UpdatedTs:   activity.UpdatedTs,
+		ContainerID: activity.ContainerUID,
+		Type:        string(activity.Type),
+		Level:       string(activity.Level),
+		Comment:     activity.Comment,
+		Payload:     storedPayload,
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to marshal activity %d", activity.UID)
+	}
+	h := sha256.Sum256(b)
+	return hex.EncodeToString(h[:]), nil
+}
+
+// getActivityLedgerHash returns the hex encoded HMAC-SHA256 of the previous hash and the content hash with the key.
+// The key is kept outside the metadata database, so that the chain can't be rebuilt after tampering with the database.
+func getActivityLedgerHash(key []byte, prevHash, contentHash string) string {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(prevHash + contentHash))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// SetActivityLedgerKey sets the key of the activity ledger hash chain.
+// The activity ledger records are not sealed until the key is set.
+func (s *Store) SetActivityLedgerKey(key []byte) {
+	s.activityLedgerKey = key
+}
+
+// appendActivityLedgerImpl appends the unsealed records of the activities to the ledger.
+// The storedPayloads are the payloads stored in the activity table.
+// The records are chained by sealActivityLedger after the transaction commits, so that the concurrent activity
+// writes aren't serialized by the ledger.
+func appendActivityLedgerImpl(ctx context.Context, tx *Tx, activities []*ActivityMessage, storedPayloads []string) error {
+	if len(activities) == 0 {
+		return nil
+	}
+	// The shared lock is held until the transaction ends, so the sealing never sees a record committed after
+	// the records with greater IDs, and the unsealed records are always after the head of the chain.
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock_shared($1)`, activityLedgerSealLockKey); err != nil {
+		return errors.Wrapf(err, "failed to lock activity ledger")
+	}
+	for i, activity := range activities {
+		contentHash, err := getActivityContentHash(activity, storedPayloads[i])
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO activity_ledger (
+				activity_id,
+				content_hash
+			) VALUES ($1, $2)
+		`, activity.UID, contentHash); err != nil {
+			return errors.Wrapf(err, "failed to append activity ledger record for activity %d", activity.UID)
+		}
+	}
+	return nil
+}
+
+// trySealActivityLedger seals the activity ledger unless it's being sealed by others,
+// which may leave the records committed concurrently unsealed until the next sealing.
+func (s *Store) trySealActivityLedger(ctx context.Context) {
+	if err := s.sealActivityLedger(ctx, false /* wait */); err != nil {
+		slog.Warn("failed to seal activity ledger", log.BBError(err))
+	}
+}
+
+// sealActivityLedger chains the unsealed records after the head of the hash chain in the order of the IDs.
+// The sealing is serialized with the sealing and the activity writes by the advisory lock,
+// and it's skipped if the lock is held by others unless wait is true.
+// The sealed records are never chained again, so the tampered records can't be signed with the key.
+func (s *Store) sealActivityLedger(ctx context.Context, wait bool) error {
+	if s.activityLedgerKey == nil {
+		return nil
+	}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if wait {
+		if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, activityLedgerSealLockKey); err != nil {
+			return errors.Wrapf(err, "failed to lock activity ledger")
+		}
+	} else {
+		var acquired bool
+		if err := tx.QueryRowContext(ctx, `SELECT pg_try_advisory_xact_lock($1)`, activityLedgerSealLockKey).Scan(&acquired); err != nil {
+			return errors.Wrapf(err, "failed to lock activity ledger")
+		}
+		if !acquired {
+			return nil
+		}
+	}
+
+	var headID, headSeq int64
+	var prevHash sql.NullString
+	if err := tx.QueryRowContext(ctx, `SELECT id, seq, hash FROM activity_ledger WHERE seq IS NOT NULL ORDER BY seq DESC LIMIT 1`).Scan(&headID, &headSeq, &prevHash); err != nil && err != sql.ErrNoRows {
+		return errors.Wrapf(err, "failed to get the head of activity ledger")
+	}
+	if headSeq > 0 && !prevHash.Valid {
+		return errors.Errorf("the head of activity ledger at #%d is not sealed", headSeq)
+	}
+	type unsealed struct {
+		id          int64
+		contentHash string
+	}
+	var records []unsealed
+	if err := func() error {
+		rows, err := tx.QueryContext(ctx, `SELECT id, content_hash FROM activity_ledger WHERE seq IS NULL AND id > $1 ORDER BY id ASC`, headID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var record unsealed
+			if err := rows.Scan(&record.id, &record.contentHash); err != nil {
+				return err
+			}
+			records = append(records, record)
+		}
+		return rows.Err()
+	}(); err != nil {
+		return errors.Wrapf(err, "failed to list unsealed activity ledger records")
+	}
+	seq, hash := headSeq, prevHash.String
+	for _, record := range records {
+		seq++
+		hash = getActivityLedgerHash(s.activityLedgerKey, hash, record.contentHash)
+		if _, err := tx.ExecContext(ctx, `UPDATE activity_ledger SET seq = $1, hash = $2 WHERE id = $3`, seq, hash, record.id); err != nil {
+			return errors.Wrapf(err, "failed to seal activity ledger record %d", record.id)
+		}
+	}
+	return tx.Commit()
+}
+
+// ListActivityLedger seals and lists the activity ledger records in the order of the chain.
+func (s *Store) ListActivityLedger(ctx context.Context, find *FindActivityLedgerMessage) ([]*ActivityLedgerMessage, error) {
+	if err := s.sealActivityLedger(ctx, true /* wait */); err != nil {
+		return nil, err
+	}
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var prevHash string
+	if err := tx.QueryRowContext(ctx, `SELECT hash FROM activity_ledger WHERE seq <= $1 AND hash IS NOT NULL ORDER BY seq DESC LIMIT 1`, find.SeqAfter).Scan(&prevHash); err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	query := `
+		SELECT
+			id,
+			created_ts,
+			activity_id,
+			content_hash,
+			seq,
+			hash
+		FROM activity_ledger
+		WHERE seq > $1 AND hash IS NOT NULL
+		ORDER BY seq ASC`
+	if find.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", find.Limit)
+	}
+	rows, err := tx.QueryContext(ctx, query, find.SeqAfter)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records []*ActivityLedgerMessage
+	for rows.Next() {
+		record := &ActivityLedgerMessage{
+			PrevHash: prevHash,
+		}
+		if err := rows.Scan(
+			&record.ID,
+			&record.CreatedTs,
+			&record.ActivityUID,
+			&record.ContentHash,
+			&record.Seq,
+			&record.Hash,
+		); err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+		prevHash = record.Hash
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// VerifyActivityLedger verifies the hash chain of the activity ledger and the activities against their latest records.
+// The activities created before the ledger is introduced aren't covered.
+func (s *Store) VerifyActivityLedger(ctx context.Context) (*ActivityLedgerVerification, error) {
+	if s.activityLedgerKey == nil {
+		return nil, errors.Errorf("activity ledger key is not set")
+	}
+	if err := s.sealActivityLedger(ctx, true /* wait */); err != nil {
+		return nil, err
+	}
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true, Isolation: sql.LevelRepeatableRead})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	result := &ActivityLedgerVerification{}
+	// latest is the map from the activity ID to the content hash of its latest record.
+	latest := make(map[int]string)
+	minActivityUID := 0
+	var headID int64
+	if err := func() error {
+		rows, err := tx.QueryContext(ctx, `SELECT id, activity_id, content_hash, seq, hash FROM activity_ledger WHERE seq IS NOT NULL ORDER BY seq ASC`)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var id, seq int64
+			var activityUID int
+			var contentHash string
+			var hash sql.NullString
+			if err := rows.Scan(&id, &activityUID, &contentHash, &seq, &hash); err != nil {
+				return err
+			}
+			if seq != result.RecordCount+1 || !hash.Valid || getActivityLedgerHash(s.activityLedgerKey, result.HeadHash, contentHash) != hash.String {
+				result.ActivityUID = activityUID
+				result.Reason = fmt.Sprintf("the hash chain is broken at record #%d", result.RecordCount+1)
+				return nil
+			}
+			result.RecordCount++
+			result.HeadHash = hash.String
+			headID = id
+		}
+		return rows.Err()
+	}(); err != nil {
+		return nil, errors.Wrapf(err, "failed to verify activity ledger")
+	}
+	if result.Reason != "" {
+		return result, nil
+	}
+	// The records before the head are always sealed, so an unsealed one is removed from the chain.
+	var unsealedActivityUID int
+	if err := tx.QueryRowContext(ctx, `SELECT activity_id FROM activity_ledger WHERE seq IS NULL AND id < $1 ORDER BY id ASC LIMIT 1`, headID).Scan(&unsealedActivityUID); err != nil && err != sql.ErrNoRows {
+		return nil, errors.Wrapf(err, "failed to verify activity ledger")
+	}
+	if unsealedActivityUID != 0 {
+		result.ActivityUID = unsealedActivityUID
+		result.Reason = "the activity record before the head of the hash chain is not sealed"
+		return result, nil
+	}
+	// The records committed after the sealing are not chained yet, but the activities are covered by them.
+	if err := func() error {
+		rows, err := tx.QueryContext(ctx, `SELECT activity_id, content_hash FROM activity_ledger ORDER BY id ASC`)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var activityUID int
+			var contentHash string
+			if err := rows.Scan(&activityUID, &contentHash); err != nil {
+				return err
+			}
+			latest[activityUID] = contentHash
+			if minActivityUID == 0 || activityUID < minActivityUID {
+				minActivityUID = activityUID
+			}
+		}
+		return rows.Err()
+	}(); err != nil {
+		return nil, errors.Wrapf(err, "failed to verify activity ledger")
+	}
+	if minActivityUID == 0 {
+		return result, nil
+	}
+
+	if err := func() error {
+		rows, err := tx.QueryContext(ctx, `
+			SELECT
+				id,
+				creator_id,
+				created_ts,
+				updater_id,
+				updated_ts,
+				container_id,
+				type,
+				level,
+				comment,
+				payload
+			FROM activity
+			WHERE id >= $1
+			ORDER BY id ASC`, minActivityUID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var activity ActivityMessage
+			var storedPayload string
+			if err := rows.Scan(
+				&activity.UID,
+				&activity.CreatorUID,
+				&activity.CreatedTs,
+				&activity.UpdaterUID,
+				&activity.UpdatedTs,
+				&activity.ContainerUID,
+				&activity.Type,
+				&activity.Level,
+				&activity.Comment,
+				&storedPayload,
+			); err != nil {
+				return err
+			}
+			contentHash, err := getActivityContentHash(&activity, storedPayload)
+			if err != nil {
+				return err
+			}
+			want, ok := latest[activity.UID]
+			if !ok {
+				result.ActivityUID = activity.UID
+				result.Reason = "the activity is not recorded in the hash chain"
+				return nil
+			}
+			if contentHash != want {
+				result.ActivityUID = activity.UID
+				result.Reason = "the activity is modified since its latest record in the hash chain"
+				return nil
+			}
+			delete(latest, activity.UID)
+		}
+		return rows.Err()
+	}(); err != nil {
+		return nil, errors.Wrapf(err, "failed to verify activities")
+	}
+	if result.Reason != "" {
+		return result, nil
+	}
+	for activityUID := range latest {
+		if result.ActivityUID == 0 || activityUID < result.ActivityUID {
+			result.ActivityUID = activityUID
+			result.Reason = "the activity recorded in the hash chain is deleted"
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetAuditLogExportCursor gets the position of the last activity ledger record exported to the destination.
+func (s *Store) GetAuditLogExportCursor(ctx context.Context, destination string) (int64, error) {
+	var ledgerSeq int64
+	if err := s.db.db.QueryRowContext(ctx, `SELECT ledger_seq FROM audit_log_export_cursor WHERE destination = $1`, destination).Scan(&ledgerSeq); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return ledgerSeq, nil
+}
+
+// UpsertAuditLogExportCursor sets the position of the last activity ledger record exported to the destination.
+func (s *Store) UpsertAuditLogExportCursor(ctx context.Context, destination string, ledgerSeq int64) error {
+	if _, err := s.db.db.ExecContext(ctx, `
+		INSERT INTO audit_log_export_cursor (
+			destination,
+			ledger_seq
+		) VALUES ($1, $2)
+		ON CONFLICT (destination) DO UPDATE SET
+			ledger_seq = EXCLUDED.ledger_seq
+	`, destination, ledgerSeq); err != nil {
+		return errors.Wrapf(err, "failed to upsert audit log export cursor of %s", destination)
+	}
+	return nil
+}
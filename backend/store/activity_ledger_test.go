@@ -0,0 +1,21 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetActivityLedgerHash(t *testing.T) {
+	a := require.New(t)
+	key := []byte("0123456789abcdef0123456789abcdef")
+	contentHash := "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+
+	hash := getActivityLedgerHash(key, "", contentHash)
+	a.Len(hash, 64)
+	a.Equal(hash, getActivityLedgerHash(key, "", contentHash))
+	// The hash can't be recomputed without the key.
+	a.NotEqual(hash, getActivityLedgerHash([]byte("fedcba9876543210fedcba9876543210"), "", contentHash))
+	// The hash is chained to the previous hash.
+	a.NotEqual(hash, getActivityLedgerHash(key, hash, contentHash))
+}
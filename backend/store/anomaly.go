@@ -54,10 +54,11 @@ type updateAnomalyMessage struct {
 }
 
 // UpsertActiveAnomalyV2 upserts an instance of anomaly.
-func (s *Store) UpsertActiveAnomalyV2(ctx context.Context, principalUID int, upsert *AnomalyMessage) (*AnomalyMessage, error) {
+// It also returns whether the anomaly is created, i.e. there was no active anomaly of the type.
+func (s *Store) UpsertActiveAnomalyV2(ctx context.Context, principalUID int, upsert *AnomalyMessage) (*AnomalyMessage, bool, error) {
 	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 	defer tx.Rollback()
 
@@ -70,10 +71,11 @@ func (s *Store) UpsertActiveAnomalyV2(ctx context.Context, principalUID int, ups
 	}
 	list, err := s.listAnomalyImplV2(ctx, tx, find)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
 	var anomaly *AnomalyMessage
+	created := len(list) == 0
 	if len(list) == 0 {
 		anomaly, err = s.createAnomalyImplV2(ctx, tx, principalUID, &AnomalyMessage{
 			InstanceID:  upsert.InstanceID,
@@ -82,7 +84,7 @@ func (s *Store) UpsertActiveAnomalyV2(ctx context.Context, principalUID int, ups
 			Payload:     upsert.Payload,
 		})
 		if err != nil {
-			return nil, err
+			return nil, false, err
 		}
 	} else if len(list) == 1 {
 		// Even if field value does not change, we still patch to update the updated_ts.
@@ -91,17 +93,17 @@ func (s *Store) UpsertActiveAnomalyV2(ctx context.Context, principalUID int, ups
 			Payload: upsert.Payload,
 		})
 		if err != nil {
-			return nil, err
+			return nil, false, err
 		}
 	} else {
-		return nil, &common.Error{Code: common.Conflict, Err: errors.Errorf("found %d active anomalies with filter %+v, expect 1", len(list), find)}
+		return nil, false, &common.Error{Code: common.Conflict, Err: errors.Errorf("found %d active anomalies with filter %+v, expect 1", len(list), find)}
 	}
 
 	if err := tx.Commit(); err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
-	return anomaly, nil
+	return anomaly, created, nil
 }
 
 // ListAnomalyV2 lists anomalies, only return the normal ones.
@@ -126,6 +128,7 @@ func (s *Store) ListAnomalyV2(ctx context.Context, list *ListAnomalyMessage) ([]
 }
 
 // ArchiveAnomalyV2 archives an anomaly.
+// It returns the NotFound error if there is no active anomaly of the type.
 func (s *Store) ArchiveAnomalyV2(ctx context.Context, archive *ArchiveAnomalyMessage) error {
 	if archive.InstanceID == nil && archive.DatabaseUID == nil {
 		return &common.Error{Code: common.Internal, Err: errors.Errorf("failed to close anomaly, should specify either instanceID or databaseID")}
@@ -147,11 +150,12 @@ func (s *Store) ArchiveAnomalyV2(ctx context.Context, archive *ArchiveAnomalyMes
 				anomaly
 			SET row_status = $1
 			FROM instance
-			WHERE anomaly.instance_id = instance.id AND instance.resource_id = $2 AND anomaly.database_id IS NULL AND anomaly.type = $3
+			WHERE anomaly.instance_id = instance.id AND instance.resource_id = $2 AND anomaly.database_id IS NULL AND anomaly.type = $3 AND anomaly.row_status = $4
 		`,
 			api.Archived,
 			*archive.InstanceID,
 			archive.Type,
+			api.Normal,
 		)
 		if err != nil {
 			return err
@@ -162,10 +166,11 @@ func (s *Store) ArchiveAnomalyV2(ctx context.Context, archive *ArchiveAnomalyMes
 		}
 	} else if archive.DatabaseUID != nil {
 		result, err := tx.ExecContext(ctx,
-			`UPDATE anomaly SET row_status = $1 WHERE database_id = $2 AND type = $3`,
+			`UPDATE anomaly SET row_status = $1 WHERE database_id = $2 AND type = $3 AND row_status = $4`,
 			api.Archived,
 			*archive.DatabaseUID,
 			archive.Type,
+			api.Normal,
 		)
 		if err != nil {
 			return err
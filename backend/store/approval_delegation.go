@@ -0,0 +1,176 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/backend/common"
+)
+
+// ApprovalDelegationMessage is the message for an approval delegation.
+// The delegate reviews the approvals on behalf of the delegator between the start time and the end time.
+type ApprovalDelegationMessage struct {
+	DelegatorUID int
+	DelegateUID  int
+	StartTs      int64
+	EndTs        int64
+	Reason       string
+
+	// Output only fields.
+	UID       int
+	CreatedTs int64
+}
+
+// FindApprovalDelegationMessage is the message for finding approval delegations.
+type FindApprovalDelegationMessage struct {
+	UID          *int
+	DelegatorUID *int
+	DelegateUID  *int
+	// ActiveTs finds the delegations that are active at the unix timestamp.
+	ActiveTs *int64
+}
+
+// GetApprovalDelegation gets an approval delegation.
+func (s *Store) GetApprovalDelegation(ctx context.Context, find *FindApprovalDelegationMessage) (*ApprovalDelegationMessage, error) {
+	delegations, err := s.ListApprovalDelegations(ctx, find)
+	if err != nil {
+		return nil, err
+	}
+	if len(delegations) == 0 {
+		return nil, nil
+	}
+	if len(delegations) > 1 {
+		return nil, &common.Error{Code: common.Conflict, Err: errors.Errorf("found %d approval delegations with filter %+v, expect 1", len(delegations), find)}
+	}
+	return delegations[0], nil
+}
+
+// ListApprovalDelegations lists approval delegations.
+func (s *Store) ListApprovalDelegations(ctx context.Context, find *FindApprovalDelegationMessage) ([]*ApprovalDelegationMessage, error) {
+	where, args := []string{"TRUE"}, []any{}
+	if v := find.UID; v != nil {
+		where, args = append(where, fmt.Sprintf("id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.DelegatorUID; v != nil {
+		where, args = append(where, fmt.Sprintf("delegator_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.DelegateUID; v != nil {
+		where, args = append(where, fmt.Sprintf("delegate_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.ActiveTs; v != nil {
+		where = append(where, fmt.Sprintf("start_ts <= $%d", len(args)+1), fmt.Sprintf("end_ts > $%d", len(args)+1))
+		args = append(args, *v)
+	}
+
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, fmt.Sprintf(`
+		SELECT
+			id,
+			created_ts,
+			delegator_id,
+			delegate_id,
+			start_ts,
+			end_ts,
+			reason
+		FROM approval_delegation
+		WHERE %s
+		ORDER BY id ASC`, strings.Join(where, " AND ")),
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var delegations []*ApprovalDelegationMessage
+	for rows.Next() {
+		delegation := &ApprovalDelegationMessage{}
+		if err := rows.Scan(
+			&delegation.UID,
+			&delegation.CreatedTs,
+			&delegation.DelegatorUID,
+			&delegation.DelegateUID,
+			&delegation.StartTs,
+			&delegation.EndTs,
+			&delegation.Reason,
+		); err != nil {
+			return nil, err
+		}
+		delegations = append(delegations, delegation)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return delegations, nil
+}
+
+// CreateApprovalDelegation creates an approval delegation.
+func (s *Store) CreateApprovalDelegation(ctx context.Context, create *ApprovalDelegationMessage) (*ApprovalDelegationMessage, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	delegation := &ApprovalDelegationMessage{
+		DelegatorUID: create.DelegatorUID,
+		DelegateUID:  create.DelegateUID,
+		StartTs:      create.StartTs,
+		EndTs:        create.EndTs,
+		Reason:       create.Reason,
+	}
+	if err := tx.QueryRowContext(ctx, `
+		INSERT INTO approval_delegation (
+			delegator_id,
+			delegate_id,
+			start_ts,
+			end_ts,
+			reason
+		)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_ts
+	`,
+		create.DelegatorUID,
+		create.DelegateUID,
+		create.StartTs,
+		create.EndTs,
+		create.Reason,
+	).Scan(
+		&delegation.UID,
+		&delegation.CreatedTs,
+	); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return delegation, nil
+}
+
+// DeleteApprovalDelegation deletes an approval delegation.
+func (s *Store) DeleteApprovalDelegation(ctx context.Context, uid int) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM approval_delegation WHERE id = $1`, uid); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,208 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	storepb "github.com/bytebase/bytebase/proto/generated-go/store"
+)
+
+// EmailNotificationStatus is the status of the email notification.
+type EmailNotificationStatus string
+
+const (
+	// EmailNotificationPending is the status of the notifications waiting for the next digest email, including the ones to retry.
+	EmailNotificationPending EmailNotificationStatus = "PENDING"
+	// EmailNotificationSent is the status of the notifications sent in a digest email.
+	EmailNotificationSent EmailNotificationStatus = "SENT"
+	// EmailNotificationFailed is the status of the notifications failed to send.
+	EmailNotificationFailed EmailNotificationStatus = "FAILED"
+)
+
+// EmailNotificationMessage is the message for an email notification.
+type EmailNotificationMessage struct {
+	RecipientID int
+	Event       storepb.NotificationSetting_Event
+	IssueUID    int
+	// Payload is the JSON encoded content of the notification.
+	Payload string
+
+	// Output only fields.
+	ID        int64
+	CreatedTs int64
+	UpdatedTs int64
+	Status    EmailNotificationStatus
+	Error     string
+	// Attempts is the number of the sending attempts.
+	Attempts int
+}
+
+// FindEmailNotificationMessage is the message for finding email notifications.
+type FindEmailNotificationMessage struct {
+	RecipientID *int
+	Status      *EmailNotificationStatus
+	Limit       *int
+}
+
+// CreateEmailNotifications creates the pending email notifications.
+func (s *Store) CreateEmailNotifications(ctx context.Context, creates []*EmailNotificationMessage) error {
+	if len(creates) == 0 {
+		return nil
+	}
+	var values []string
+	var args []any
+	for _, create := range creates {
+		values = append(values, fmt.Sprintf("($%d, $%d, $%d, $%d, $%d)", len(args)+1, len(args)+2, len(args)+3, len(args)+4, len(args)+5))
+		args = append(args, create.RecipientID, create.Event.String(), create.IssueUID, create.Payload, EmailNotificationPending)
+	}
+	query := fmt.Sprintf(`
+		INSERT INTO email_notification (
+			recipient_id,
+			event,
+			issue_id,
+			payload,
+			status
+		) VALUES %s
+	`, strings.Join(values, ", "))
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return errors.Wrapf(err, "failed to create email notifications")
+	}
+	return tx.Commit()
+}
+
+// ListEmailNotifications lists the email notifications in the ascending order of the ID.
+func (s *Store) ListEmailNotifications(ctx context.Context, find *FindEmailNotificationMessage) ([]*EmailNotificationMessage, error) {
+	where, args := []string{"TRUE"}, []any{}
+	if v := find.RecipientID; v != nil {
+		where, args = append(where, fmt.Sprintf("recipient_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.Status; v != nil {
+		where, args = append(where, fmt.Sprintf("status = $%d", len(args)+1)), append(args, *v)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT
+			id,
+			created_ts,
+			updated_ts,
+			recipient_id,
+			event,
+			issue_id,
+			payload,
+			status,
+			error,
+			attempts
+		FROM email_notification
+		WHERE %s
+		ORDER BY id ASC`, strings.Join(where, " AND "))
+	if v := find.Limit; v != nil {
+		query += fmt.Sprintf(" LIMIT %d", *v)
+	}
+
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var notifications []*EmailNotificationMessage
+	for rows.Next() {
+		notification := &EmailNotificationMessage{}
+		var event string
+		if err := rows.Scan(
+			&notification.ID,
+			&notification.CreatedTs,
+			&notification.UpdatedTs,
+			&notification.RecipientID,
+			&event,
+			&notification.IssueUID,
+			&notification.Payload,
+			&notification.Status,
+			&notification.Error,
+			&notification.Attempts,
+		); err != nil {
+			return nil, err
+		}
+		notification.Event = storepb.NotificationSetting_Event(storepb.NotificationSetting_Event_value[event])
+		notifications = append(notifications, notification)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return notifications, nil
+}
+
+// ListPendingEmailNotificationRecipientIDs lists the recipients of the pending email notifications in the order of their oldest notifications.
+func (s *Store) ListPendingEmailNotificationRecipientIDs(ctx context.Context, limit int) ([]int, error) {
+	rows, err := s.db.db.QueryContext(ctx, `
+		SELECT recipient_id
+		FROM email_notification
+		WHERE status = $1
+		GROUP BY recipient_id
+		ORDER BY MIN(id) ASC
+		LIMIT $2
+	`, EmailNotificationPending, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var recipientIDs []int
+	for rows.Next() {
+		var recipientID int
+		if err := rows.Scan(&recipientID); err != nil {
+			return nil, err
+		}
+		recipientIDs = append(recipientIDs, recipientID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return recipientIDs, nil
+}
+
+// BatchUpdateEmailNotificationStatus updates the status and the error of the email notifications after a sending attempt.
+func (s *Store) BatchUpdateEmailNotificationStatus(ctx context.Context, ids []int64, status EmailNotificationStatus, errorMessage string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	var idStrings []string
+	for _, id := range ids {
+		idStrings = append(idStrings, fmt.Sprintf("%d", id))
+	}
+	query := fmt.Sprintf(`
+		UPDATE email_notification
+		SET status = $1, error = $2, attempts = attempts + 1
+		WHERE id IN (%s)
+	`, strings.Join(idStrings, ","))
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err := tx.ExecContext(ctx, query, status, errorMessage); err != nil {
+		return errors.Wrapf(err, "failed to update email notifications")
+	}
+	return tx.Commit()
+}
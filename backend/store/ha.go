@@ -0,0 +1,365 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/stdlib"
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/backend/common/log"
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+)
+
+// cacheInvalidationChannel is the channel notified by the notify_cache_invalidation() trigger with the changed table and row key.
+const cacheInvalidationChannel = "bb_cache_invalidation"
+
+// TryAcquireAdvisoryLock tries to acquire the session level advisory lock with the key on a dedicated connection.
+// The lock is held until the returned connection is closed. It returns nil if the lock is held by others.
+func (s *Store) TryAcquireAdvisoryLock(ctx context.Context, key int64) (*sql.Conn, error) {
+	conn, err := s.db.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var acquired bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", key).Scan(&acquired); err != nil {
+		conn.Close()
+		return nil, errors.Wrapf(err, "failed to acquire advisory lock %d", key)
+	}
+	if !acquired {
+		conn.Close()
+		return nil, nil
+	}
+	return conn, nil
+}
+
+// Notify sends the notification with the payload to the channel.
+func (s *Store) Notify(ctx context.Context, channel string, payload string) error {
+	if _, err := s.db.db.ExecContext(ctx, "SELECT pg_notify($1, $2)", channel, payload); err != nil {
+		return errors.Wrapf(err, "failed to notify channel %q", channel)
+	}
+	return nil
+}
+
+// Listen listens to the channels on a dedicated connection and calls the handler for each notification.
+// It blocks until the context is done or the connection fails.
+func (s *Store) Listen(ctx context.Context, channels []string, handler func(channel string, payload string)) error {
+	conn, err := s.db.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	return conn.Raw(func(driverConn any) error {
+		stdlibConn, ok := driverConn.(*stdlib.Conn)
+		if !ok {
+			return errors.Errorf("unexpected driver connection type %T", driverConn)
+		}
+		pgxConn := stdlibConn.Conn()
+		for _, channel := range channels {
+			if _, err := pgxConn.Exec(ctx, fmt.Sprintf("LISTEN %s", pgx.Identifier{channel}.Sanitize())); err != nil {
+				return errors.Wrapf(err, "failed to listen channel %q", channel)
+			}
+		}
+		for {
+			notification, err := pgxConn.WaitForNotification(ctx)
+			if err != nil {
+				return err
+			}
+			handler(notification.Channel, notification.Payload)
+		}
+	})
+}
+
+// ListenCacheInvalidation invalidates the cache on the changes made by the other Bytebase replicas sharing the metadata database.
+// It blocks until the context is done or the connection fails.
+func (s *Store) ListenCacheInvalidation(ctx context.Context) error {
+	// The changes may be missed before listening, so invalidate all.
+	s.InvalidateCache("", nil)
+	return s.Listen(ctx, []string{cacheInvalidationChannel}, func(_ string, payload string) {
+		table, key, err := parseCacheInvalidationPayload(payload)
+		if err != nil {
+			slog.Warn("failed to parse cache invalidation payload", slog.String("payload", payload), log.BBError(err))
+			s.InvalidateCache(table, nil)
+			return
+		}
+		s.InvalidateCache(table, key)
+	})
+}
+
+// cacheInvalidationTrigger is the trigger notifying the changed rows of the cached table.
+type cacheInvalidationTrigger struct {
+	table string
+	// columns are the columns of the changed rows in the payload, which identify the cache keys.
+	columns []string
+}
+
+// cacheInvalidationTriggers are the triggers of the cached tables, the columns must be consistent with InvalidateCache.
+var cacheInvalidationTriggers = []cacheInvalidationTrigger{
+	{table: "principal", columns: []string{"id"}},
+	{table: "member", columns: []string{"principal_id"}},
+	{table: "environment", columns: []string{"id", "resource_id"}},
+	{table: "instance", columns: []string{"id", "resource_id"}},
+	{table: "data_source", columns: []string{"instance_id"}},
+	{table: "db", columns: []string{"id"}},
+	{table: "project", columns: []string{"id", "resource_id"}},
+	{table: "project_member", columns: []string{"project_id"}},
+	{table: "policy", columns: []string{"resource_type", "resource_id", "type"}},
+	{table: "issue", columns: []string{"id", "pipeline_id"}},
+	{table: "issue_subscriber", columns: []string{"issue_id"}},
+	{table: "pipeline", columns: []string{"id"}},
+	{table: "db_schema", columns: []string{"database_id"}},
+	{table: "setting", columns: []string{"name"}},
+	{table: "idp", columns: []string{"resource_id"}},
+	{table: "deployment_config", columns: []string{"project_id"}},
+	{table: "risk", columns: nil},
+	{table: "db_group", columns: []string{"id", "project_id", "resource_id"}},
+	{table: "schema_group", columns: []string{"db_group_id", "resource_id"}},
+	{table: "sheet", columns: []string{"id"}},
+	{table: "vcs", columns: []string{"id"}},
+}
+
+// cacheInvalidationLockKey is the advisory lock key for installing the triggers, "bbcache" in hex.
+const cacheInvalidationLockKey = 0x6262636163686500
+
+// InstallCacheInvalidationTriggers installs the triggers notifying the changes of the cached tables with notify_cache_invalidation(),
+// which notifies the table and the JSON array of the trigger argument columns of the changed row, e.g. "environment:["101","prod"]".
+// The triggers are only needed in the high-availability mode, where multiple replicas share the metadata database.
+func (s *Store) InstallCacheInvalidationTriggers(ctx context.Context) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Serialize the installation of the replicas starting at the same time.
+	if _, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock($1)", cacheInvalidationLockKey); err != nil {
+		return err
+	}
+	for _, trigger := range cacheInvalidationTriggers {
+		table := pgx.Identifier{trigger.table}.Sanitize()
+		var args []string
+		for _, column := range trigger.columns {
+			args = append(args, fmt.Sprintf("'%s'", column))
+		}
+		for _, statement := range []string{
+			fmt.Sprintf("DROP TRIGGER IF EXISTS notify_%s_cache_invalidation ON %s", trigger.table, table),
+			fmt.Sprintf("DROP TRIGGER IF EXISTS notify_%s_cache_truncation ON %s", trigger.table, table),
+			fmt.Sprintf("CREATE TRIGGER notify_%s_cache_invalidation AFTER INSERT OR UPDATE OR DELETE ON %s FOR EACH ROW EXECUTE FUNCTION notify_cache_invalidation(%s)", trigger.table, table, strings.Join(args, ", ")),
+			fmt.Sprintf("CREATE TRIGGER notify_%s_cache_truncation AFTER TRUNCATE ON %s FOR EACH STATEMENT EXECUTE FUNCTION notify_cache_invalidation()", trigger.table, table),
+		} {
+			if _, err := tx.ExecContext(ctx, statement); err != nil {
+				return errors.Wrapf(err, "failed to create cache invalidation trigger on %q", trigger.table)
+			}
+		}
+	}
+	return tx.Commit()
+}
+
+// UninstallCacheInvalidationTriggers removes the cache invalidation triggers, so that the single replica doesn't pay for the notifications.
+// It must only be called after all replicas stop running in the high-availability mode, otherwise they miss the cache invalidations.
+func (s *Store) UninstallCacheInvalidationTriggers(ctx context.Context) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock($1)", cacheInvalidationLockKey); err != nil {
+		return err
+	}
+	for _, trigger := range cacheInvalidationTriggers {
+		table := pgx.Identifier{trigger.table}.Sanitize()
+		for _, name := range []string{"invalidation", "truncation"} {
+			if _, err := tx.ExecContext(ctx, fmt.Sprintf("DROP TRIGGER IF EXISTS notify_%s_cache_%s ON %s", trigger.table, name, table)); err != nil {
+				return errors.Wrapf(err, "failed to drop cache invalidation trigger on %q", trigger.table)
+			}
+		}
+	}
+	return tx.Commit()
+}
+
+// parseCacheInvalidationPayload parses the payload of notify_cache_invalidation(), the key is nil for the truncation.
+func parseCacheInvalidationPayload(payload string) (string, []string, error) {
+	table, rawKey, found := strings.Cut(payload, ":")
+	if !found {
+		return table, nil, nil
+	}
+	var key []*string
+	if err := json.Unmarshal([]byte(rawKey), &key); err != nil {
+		return table, nil, errors.Wrapf(err, "invalid key")
+	}
+	index := slices.IndexFunc(cacheInvalidationTriggers, func(t cacheInvalidationTrigger) bool {
+		return t.table == table
+	})
+	if index < 0 {
+		return table, nil, errors.Errorf("unknown table %q", table)
+	}
+	if len(key) != len(cacheInvalidationTriggers[index].columns) {
+		return table, nil, errors.Errorf("expect %d key columns, got %d", len(cacheInvalidationTriggers[index].columns), len(key))
+	}
+	values := []string{}
+	for _, v := range key {
+		// The nullable columns such as issue.pipeline_id are empty.
+		if v == nil {
+			values = append(values, "")
+			continue
+		}
+		values = append(values, *v)
+	}
+	return table, values, nil
+}
+
+// InvalidateCache invalidates the cache of the row identified by the key columns of cacheInvalidationTriggers in the table.
+// All caches of the table are invalidated if the key is nil, and all caches are invalidated if the table is empty.
+func (s *Store) InvalidateCache(table string, key []string) {
+	if table == "" {
+		for _, trigger := range cacheInvalidationTriggers {
+			s.InvalidateCache(trigger.table, nil)
+		}
+		return
+	}
+	if key == nil {
+		s.invalidateTableCache(table)
+		return
+	}
+	atoi := func(i int) int {
+		v, err := strconv.Atoi(key[i])
+		if err != nil {
+			return 0
+		}
+		return v
+	}
+	switch table {
+	case "principal", "member":
+		s.userIDCache.Delete(atoi(0))
+	case "environment":
+		s.environmentIDCache.Delete(atoi(0))
+		s.environmentCache.Delete(key[1])
+	case "instance":
+		s.instanceIDCache.Delete(atoi(0))
+		s.instanceCache.Delete(getInstanceCacheKey(key[1]))
+	case "data_source":
+		// The data sources are cached in the instance.
+		if v, ok := s.instanceIDCache.LoadAndDelete(atoi(0)); ok {
+			s.instanceCache.Delete(getInstanceCacheKey(v.(*InstanceMessage).ResourceID))
+		}
+	case "db":
+		if v, ok := s.databaseIDCache.LoadAndDelete(atoi(0)); ok {
+			database := v.(*DatabaseMessage)
+			s.databaseCache.Delete(getDatabaseCacheKey(database.InstanceID, database.DatabaseName))
+		}
+	case "project":
+		s.projectIDCache.Delete(atoi(0))
+		s.projectCache.Delete(key[1])
+		s.projectIDPolicyCache.Delete(atoi(0))
+		s.projectPolicyCache.Delete(key[1])
+	case "project_member":
+		s.projectIDPolicyCache.Delete(atoi(0))
+		// The IAM policy is also cached by the project resource ID, which is unknown if the project is not cached.
+		if v, ok := s.projectIDCache.Load(atoi(0)); ok {
+			s.projectPolicyCache.Delete(v.(*ProjectMessage).ResourceID)
+		} else {
+			clearSyncMap(&s.projectPolicyCache)
+		}
+	case "policy":
+		s.policyCache.Delete(getPolicyCacheKey(api.PolicyResourceType(key[0]), atoi(1), api.PolicyType(key[2])))
+	case "issue":
+		s.issueCache.Delete(atoi(0))
+		s.issueByPipelineCache.Delete(atoi(1))
+	case "issue_subscriber":
+		if v, ok := s.issueCache.LoadAndDelete(atoi(0)); ok {
+			if pipelineUID := v.(*IssueMessage).PipelineUID; pipelineUID != nil {
+				s.issueByPipelineCache.Delete(*pipelineUID)
+			}
+		}
+	case "pipeline":
+		s.pipelineCache.Delete(atoi(0))
+	case "db_schema":
+		s.dbSchemaCache.Del(atoi(0))
+	case "setting":
+		s.settingCache.Delete(api.SettingName(key[0]))
+	case "idp":
+		s.idpCache.Delete(key[0])
+	case "deployment_config":
+		s.projectIDDeploymentConfigCache.Delete(atoi(0))
+	case "risk":
+		s.risksCache.Delete(0)
+	case "db_group":
+		s.databaseGroupIDCache.Delete(int64(atoi(0)))
+		s.databaseGroupCache.Delete(int64(atoi(0)))
+		s.databaseGroupCache.Delete(getDatabaseGroupCacheKey(atoi(1), key[2]))
+	case "schema_group":
+		s.schemaGroupCache.Delete(getSchemaGroupCacheKey(int64(atoi(0)), key[1]))
+	case "sheet":
+		s.sheetStatementCache.Del(atoi(0))
+	case "vcs":
+		s.vcsIDCache.Delete(atoi(0))
+	}
+}
+
+// invalidateTableCache invalidates all caches of the table.
+func (s *Store) invalidateTableCache(table string) {
+	switch table {
+	case "principal", "member":
+		clearSyncMap(&s.userIDCache)
+	case "environment":
+		clearSyncMap(&s.environmentCache)
+		clearSyncMap(&s.environmentIDCache)
+	case "instance", "data_source":
+		clearSyncMap(&s.instanceCache)
+		clearSyncMap(&s.instanceIDCache)
+	case "db":
+		clearSyncMap(&s.databaseCache)
+		clearSyncMap(&s.databaseIDCache)
+	case "project":
+		clearSyncMap(&s.projectCache)
+		clearSyncMap(&s.projectIDCache)
+		clearSyncMap(&s.projectPolicyCache)
+		clearSyncMap(&s.projectIDPolicyCache)
+	case "project_member":
+		clearSyncMap(&s.projectPolicyCache)
+		clearSyncMap(&s.projectIDPolicyCache)
+	case "policy":
+		clearSyncMap(&s.policyCache)
+	case "issue", "issue_subscriber":
+		clearSyncMap(&s.issueCache)
+		clearSyncMap(&s.issueByPipelineCache)
+	case "pipeline":
+		clearSyncMap(&s.pipelineCache)
+	case "db_schema":
+		s.dbSchemaCache.Clear()
+	case "setting":
+		clearSyncMap(&s.settingCache)
+	case "idp":
+		clearSyncMap(&s.idpCache)
+	case "deployment_config":
+		clearSyncMap(&s.projectIDDeploymentConfigCache)
+	case "risk":
+		clearSyncMap(&s.risksCache)
+	case "db_group":
+		clearSyncMap(&s.databaseGroupCache)
+		clearSyncMap(&s.databaseGroupIDCache)
+	case "schema_group":
+		clearSyncMap(&s.schemaGroupCache)
+	case "sheet":
+		s.sheetStatementCache.Clear()
+	case "vcs":
+		clearSyncMap(&s.vcsIDCache)
+	}
+}
+
+func clearSyncMap(m *sync.Map) {
+	m.Range(func(key, _ any) bool {
+		m.Delete(key)
+		return true
+	})
+}
@@ -0,0 +1,140 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+)
+
+func TestParseCacheInvalidationPayload(t *testing.T) {
+	tests := []struct {
+		payload string
+		table   string
+		key     []string
+		wantErr bool
+	}{
+		{
+			payload: "environment",
+			table:   "environment",
+			key:     nil,
+		},
+		{
+			payload: `environment:["101","prod"]`,
+			table:   "environment",
+			key:     []string{"101", "prod"},
+		},
+		{
+			payload: `issue:["102",null]`,
+			table:   "issue",
+			key:     []string{"102", ""},
+		},
+		{
+			payload: "risk:[]",
+			table:   "risk",
+			key:     []string{},
+		},
+		{
+			payload: `environment:["101"]`,
+			table:   "environment",
+			wantErr: true,
+		},
+		{
+			payload: `unknown:["101"]`,
+			table:   "unknown",
+			wantErr: true,
+		},
+		{
+			payload: "setting:bb.branding",
+			table:   "setting",
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		table, key, err := parseCacheInvalidationPayload(test.payload)
+		require.Equal(t, test.table, table, test.payload)
+		if test.wantErr {
+			require.Error(t, err, test.payload)
+			continue
+		}
+		require.NoError(t, err, test.payload)
+		require.Equal(t, test.key, key, test.payload)
+	}
+}
+
+func TestCacheInvalidationTriggers(t *testing.T) {
+	tables := map[string]bool{}
+	for _, trigger := range cacheInvalidationTriggers {
+		require.False(t, tables[trigger.table], trigger.table)
+		tables[trigger.table] = true
+	}
+}
+
+func TestInvalidateCache(t *testing.T) {
+	a := require.New(t)
+	s := New(nil)
+
+	s.environmentIDCache.Store(101, &EnvironmentMessage{UID: 101, ResourceID: "prod"})
+	s.environmentCache.Store("prod", &EnvironmentMessage{UID: 101, ResourceID: "prod"})
+	s.environmentIDCache.Store(102, &EnvironmentMessage{UID: 102, ResourceID: "test"})
+	s.environmentCache.Store("test", &EnvironmentMessage{UID: 102, ResourceID: "test"})
+	s.InvalidateCache("environment", []string{"101", "prod"})
+	_, ok := s.environmentIDCache.Load(101)
+	a.False(ok)
+	_, ok = s.environmentCache.Load("prod")
+	a.False(ok)
+	_, ok = s.environmentIDCache.Load(102)
+	a.True(ok)
+	_, ok = s.environmentCache.Load("test")
+	a.True(ok)
+
+	// The database is invalidated by both the UID and the name.
+	s.databaseIDCache.Store(103, &DatabaseMessage{UID: 103, InstanceID: "mysql", DatabaseName: "db"})
+	s.databaseCache.Store(getDatabaseCacheKey("mysql", "db"), &DatabaseMessage{UID: 103, InstanceID: "mysql", DatabaseName: "db"})
+	s.InvalidateCache("db", []string{"103"})
+	_, ok = s.databaseIDCache.Load(103)
+	a.False(ok)
+	_, ok = s.databaseCache.Load(getDatabaseCacheKey("mysql", "db"))
+	a.False(ok)
+
+	// The project IAM policy is invalidated by the member change.
+	s.projectIDCache.Store(104, &ProjectMessage{UID: 104, ResourceID: "project"})
+	s.projectIDPolicyCache.Store(104, &IAMPolicyMessage{})
+	s.projectPolicyCache.Store("project", &IAMPolicyMessage{})
+	s.projectPolicyCache.Store("other", &IAMPolicyMessage{})
+	s.InvalidateCache("project_member", []string{"104"})
+	_, ok = s.projectIDPolicyCache.Load(104)
+	a.False(ok)
+	_, ok = s.projectPolicyCache.Load("project")
+	a.False(ok)
+	_, ok = s.projectPolicyCache.Load("other")
+	a.True(ok)
+	_, ok = s.projectIDCache.Load(104)
+	a.True(ok)
+
+	policyKey := getPolicyCacheKey(api.PolicyResourceTypeEnvironment, 101, api.PolicyTypeSQLReview)
+	s.policyCache.Store(policyKey, &PolicyMessage{})
+	s.InvalidateCache("policy", []string{string(api.PolicyResourceTypeEnvironment), "101", string(api.PolicyTypeSQLReview)})
+	_, ok = s.policyCache.Load(policyKey)
+	a.False(ok)
+
+	// The truncation invalidates the whole table.
+	s.settingCache.Store(api.SettingBrandingLogo, &SettingMessage{})
+	s.settingCache.Store(api.SettingWorkspaceID, &SettingMessage{})
+	s.InvalidateCache("setting", nil)
+	_, ok = s.settingCache.Load(api.SettingBrandingLogo)
+	a.False(ok)
+	_, ok = s.settingCache.Load(api.SettingWorkspaceID)
+	a.False(ok)
+
+	// The empty table invalidates all.
+	s.environmentIDCache.Store(101, &EnvironmentMessage{UID: 101, ResourceID: "prod"})
+	s.userIDCache.Store(1, &UserMessage{ID: 1})
+	s.InvalidateCache("", nil)
+	_, ok = s.environmentIDCache.Load(101)
+	a.False(ok)
+	_, ok = s.userIDCache.Load(1)
+	a.False(ok)
+}
@@ -293,6 +293,69 @@ func (s *Store) UpdateIssueV2(ctx context.Context, uid int, patch *UpdateIssueMe
 	return s.GetIssueV2(ctx, &FindIssueMessage{UID: &uid})
 }
 
+// UpdateIssueApproval updates the approval of the issue under the row lock, so that the concurrent updates are not overwritten.
+// The update function is called with a copy of the latest approval, and the approval is left unchanged if it returns false.
+func (s *Store) UpdateIssueApproval(ctx context.Context, uid int, update func(approval *storepb.IssuePayloadApproval) bool, updaterID int) (*IssueMessage, error) {
+	oldIssue, err := s.GetIssueV2(ctx, &FindIssueMessage{UID: &uid})
+	if err != nil {
+		return nil, err
+	}
+	if oldIssue == nil {
+		return nil, errors.Errorf("issue %d not found", uid)
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var payload []byte
+	if err := tx.QueryRowContext(ctx, `
+		SELECT payload
+		FROM issue
+		WHERE id = $1
+		FOR UPDATE`,
+		uid,
+	).Scan(&payload); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.Errorf("issue %d not found", uid)
+		}
+		return nil, err
+	}
+	issuePayload := &storepb.IssuePayload{}
+	if err := protojsonUnmarshaler.Unmarshal(payload, issuePayload); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal issue payload")
+	}
+	approval := issuePayload.Approval
+	if approval == nil {
+		approval = &storepb.IssuePayloadApproval{}
+	}
+	if !update(approval) {
+		return oldIssue, nil
+	}
+	p, err := protojson.Marshal(approval)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal issue approval")
+	}
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE issue
+		SET updater_id = $1, payload = jsonb_set(payload, '{approval}', $2)
+		WHERE id = $3`,
+		updaterID, p, uid,
+	); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	// Invalid the cache and read the value again.
+	s.issueCache.Delete(uid)
+	s.issueByPipelineCache.Delete(oldIssue.PipelineUID)
+	return s.GetIssueV2(ctx, &FindIssueMessage{UID: &uid})
+}
+
 func setSubscribers(ctx context.Context, tx *Tx, issueUID int, subscribers []*UserMessage) error {
 	subscriberIDs := make(map[int]bool)
 	for _, subscriber := range subscribers {
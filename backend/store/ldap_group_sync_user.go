@@ -0,0 +1,71 @@
+package store
+
+import (
+	"context"
+
+	"github.com/jackc/pgtype"
+)
+
+// ListLDAPGroupSyncUserIDs lists the principal ids of the users synced from the LDAP identity provider.
+func (s *Store) ListLDAPGroupSyncUserIDs(ctx context.Context, idpID string) ([]int, error) {
+	rows, err := s.db.db.QueryContext(ctx, `
+		SELECT principal_id
+		FROM ldap_group_sync_user
+		WHERE idp_id = $1
+		ORDER BY principal_id
+	`, idpID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var userIDs []int
+	for rows.Next() {
+		var userID int
+		if err := rows.Scan(&userID); err != nil {
+			return nil, err
+		}
+		userIDs = append(userIDs, userID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return userIDs, nil
+}
+
+// AddLDAPGroupSyncUsers records the users synced from the LDAP identity provider.
+func (s *Store) AddLDAPGroupSyncUsers(ctx context.Context, idpID string, userIDs []int) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	var ids pgtype.Int4Array
+	if err := ids.Set(userIDs); err != nil {
+		return err
+	}
+	if _, err := s.db.db.ExecContext(ctx, `
+		INSERT INTO ldap_group_sync_user (idp_id, principal_id)
+		SELECT $1, unnest($2::INTEGER[])
+		ON CONFLICT DO NOTHING
+	`, idpID, ids); err != nil {
+		return err
+	}
+	return nil
+}
+
+// DeleteLDAPGroupSyncUsers deletes the records of the users synced from the LDAP identity provider.
+func (s *Store) DeleteLDAPGroupSyncUsers(ctx context.Context, idpID string, userIDs []int) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	var ids pgtype.Int4Array
+	if err := ids.Set(userIDs); err != nil {
+		return err
+	}
+	if _, err := s.db.db.ExecContext(ctx, `
+		DELETE FROM ldap_group_sync_user
+		WHERE idp_id = $1 AND principal_id = ANY($2)
+	`, idpID, ids); err != nil {
+		return err
+	}
+	return nil
+}
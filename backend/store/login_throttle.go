@@ -0,0 +1,173 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// LoginThrottleType is the type of the login throttle.
+type LoginThrottleType string
+
+const (
+	// LoginThrottleAccount is the login throttle of an account keyed by the email.
+	LoginThrottleAccount LoginThrottleType = "ACCOUNT"
+	// LoginThrottleIP is the login throttle of a client IP.
+	LoginThrottleIP LoginThrottleType = "IP"
+)
+
+// LoginThrottleMessage is the message for the failed login counter of an account or a client IP.
+type LoginThrottleMessage struct {
+	Type LoginThrottleType
+	// Subject is the lower-case email for accounts and the IP address for client IPs.
+	Subject       string
+	FailureCount  int
+	LastFailureTs int64
+	// LockedUntilTs is the time before which the login attempts are rejected.
+	LockedUntilTs int64
+
+	// Output only fields.
+	UID       int
+	UpdatedTs int64
+}
+
+// FindLoginThrottleMessage is the message for finding login throttles.
+type FindLoginThrottleMessage struct {
+	Type    *LoginThrottleType
+	Subject *string
+	// LockedOnly only returns the login throttles that are locked now.
+	LockedOnly bool
+}
+
+// GetLoginThrottle gets a login throttle.
+func (s *Store) GetLoginThrottle(ctx context.Context, throttleType LoginThrottleType, subject string) (*LoginThrottleMessage, error) {
+	throttles, err := s.ListLoginThrottles(ctx, &FindLoginThrottleMessage{Type: &throttleType, Subject: &subject})
+	if err != nil {
+		return nil, err
+	}
+	if len(throttles) == 0 {
+		return nil, nil
+	}
+	return throttles[0], nil
+}
+
+// ListLoginThrottles lists login throttles.
+func (s *Store) ListLoginThrottles(ctx context.Context, find *FindLoginThrottleMessage) ([]*LoginThrottleMessage, error) {
+	where, args := []string{"TRUE"}, []any{}
+	if v := find.Type; v != nil {
+		where, args = append(where, fmt.Sprintf("type = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.Subject; v != nil {
+		where, args = append(where, fmt.Sprintf("subject = $%d", len(args)+1)), append(args, *v)
+	}
+	if find.LockedOnly {
+		where, args = append(where, fmt.Sprintf("locked_until_ts > $%d", len(args)+1)), append(args, time.Now().Unix())
+	}
+
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, fmt.Sprintf(`
+		SELECT
+			id,
+			updated_ts,
+			type,
+			subject,
+			failure_count,
+			last_failure_ts,
+			locked_until_ts
+		FROM login_throttle
+		WHERE %s
+		ORDER BY id ASC`, strings.Join(where, " AND ")),
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var throttles []*LoginThrottleMessage
+	for rows.Next() {
+		throttle := &LoginThrottleMessage{}
+		if err := rows.Scan(
+			&throttle.UID,
+			&throttle.UpdatedTs,
+			&throttle.Type,
+			&throttle.Subject,
+			&throttle.FailureCount,
+			&throttle.LastFailureTs,
+			&throttle.LockedUntilTs,
+		); err != nil {
+			return nil, err
+		}
+		throttles = append(throttles, throttle)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return throttles, nil
+}
+
+// RecordLoginFailure increases the failure count of the login throttle atomically.
+// The failure count restarts from one if the last failure is before resetBeforeTs and the throttle is not locked.
+func (s *Store) RecordLoginFailure(ctx context.Context, throttleType LoginThrottleType, subject string, failureTs, resetBeforeTs int64) (*LoginThrottleMessage, error) {
+	query := `
+		INSERT INTO login_throttle (
+			type,
+			subject,
+			failure_count,
+			last_failure_ts
+		)
+		VALUES ($1, $2, 1, $3)
+		ON CONFLICT (type, subject) DO UPDATE SET
+			failure_count = CASE
+				WHEN login_throttle.last_failure_ts < $4 AND login_throttle.locked_until_ts <= $3 THEN 1
+				ELSE login_throttle.failure_count + 1
+			END,
+			last_failure_ts = EXCLUDED.last_failure_ts
+		RETURNING id, updated_ts, failure_count, last_failure_ts, locked_until_ts
+	`
+	throttle := &LoginThrottleMessage{
+		Type:    throttleType,
+		Subject: subject,
+	}
+	if err := s.db.db.QueryRowContext(ctx, query, throttleType, subject, failureTs, resetBeforeTs).Scan(
+		&throttle.UID,
+		&throttle.UpdatedTs,
+		&throttle.FailureCount,
+		&throttle.LastFailureTs,
+		&throttle.LockedUntilTs,
+	); err != nil {
+		return nil, err
+	}
+	return throttle, nil
+}
+
+// LockLoginThrottle rejects the login attempts of the login throttle until lockedUntilTs.
+func (s *Store) LockLoginThrottle(ctx context.Context, throttleType LoginThrottleType, subject string, lockedUntilTs int64) error {
+	if _, err := s.db.db.ExecContext(ctx, `
+		UPDATE login_throttle
+		SET locked_until_ts = GREATEST(locked_until_ts, $3)
+		WHERE type = $1 AND subject = $2
+	`, throttleType, subject, lockedUntilTs); err != nil {
+		return err
+	}
+	return nil
+}
+
+// DeleteLoginThrottle deletes the login throttle, which resets the failure count and unlocks it.
+func (s *Store) DeleteLoginThrottle(ctx context.Context, throttleType LoginThrottleType, subject string) error {
+	if _, err := s.db.db.ExecContext(ctx, `DELETE FROM login_throttle WHERE type = $1 AND subject = $2`, throttleType, subject); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+)
+
+// PasswordHistoryMessage is the message for a password of the user.
+type PasswordHistoryMessage struct {
+	PrincipalUID int
+	PasswordHash string
+
+	// Output only fields.
+	UID       int
+	CreatedTs int64
+}
+
+// ListPasswordHistories lists the latest passwords of the user in the reverse chronological order.
+func (s *Store) ListPasswordHistories(ctx context.Context, principalUID int, limit int) ([]*PasswordHistoryMessage, error) {
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT
+			id,
+			created_ts,
+			principal_id,
+			password_hash
+		FROM password_history
+		WHERE principal_id = $1
+		ORDER BY id DESC
+		LIMIT $2`,
+		principalUID,
+		limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var histories []*PasswordHistoryMessage
+	for rows.Next() {
+		history := &PasswordHistoryMessage{}
+		if err := rows.Scan(
+			&history.UID,
+			&history.CreatedTs,
+			&history.PrincipalUID,
+			&history.PasswordHash,
+		); err != nil {
+			return nil, err
+		}
+		histories = append(histories, history)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return histories, nil
+}
+
+// CreatePasswordHistory records the password of the user, and prunes the old ones.
+func (s *Store) CreatePasswordHistory(ctx context.Context, create *PasswordHistoryMessage) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO password_history (
+			principal_id,
+			password_hash
+		)
+		VALUES ($1, $2)
+	`, create.PrincipalUID, create.PasswordHash); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `
+		DELETE FROM password_history
+		WHERE principal_id = $1 AND id NOT IN (
+			SELECT id FROM password_history WHERE principal_id = $1 ORDER BY id DESC LIMIT $2
+		)
+	`, create.PrincipalUID, api.MaxPasswordHistoryCount); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -31,6 +31,8 @@ const (
 	PlanCheckDatabaseGhostSync PlanCheckRunType = "bb.plan-check.database.ghost.sync"
 	// PlanCheckDatabasePITRMySQL is the plan check type for MySQL PITR.
 	PlanCheckDatabasePITRMySQL PlanCheckRunType = "bb.plan-check.database.pitr.mysql"
+	// PlanCheckDatabaseShadowDryRun is the plan check type for dry-running the statement in a shadow database.
+	PlanCheckDatabaseShadowDryRun PlanCheckRunType = "bb.plan-check.database.shadow.dry-run"
 )
 
 // PlanCheckRunStatus is the status of a plan check run.
@@ -37,6 +37,8 @@ type UpdateUserMessage struct {
 	Delete       *bool
 	MFAConfig    *storepb.MFAConfig
 	Phone        *string
+
+	NotificationSetting *storepb.NotificationSetting
 }
 
 // UserMessage is the message for an user.
@@ -51,7 +53,8 @@ type UserMessage struct {
 	MemberDeleted bool
 	MFAConfig     *storepb.MFAConfig
 	// Phone conforms E.164 format.
-	Phone string
+	Phone               string
+	NotificationSetting *storepb.NotificationSetting
 }
 
 // GetUser gets an user.
@@ -174,6 +177,7 @@ func (*Store) listUserImpl(ctx context.Context, tx *Tx, find *FindUserMessage) (
 		principal.password_hash,
 		principal.mfa_config,
 		principal.phone,
+		principal.notification_setting,
 		member.role,
 		member.row_status AS row_status
 	FROM principal
@@ -193,7 +197,7 @@ func (*Store) listUserImpl(ctx context.Context, tx *Tx, find *FindUserMessage) (
 	for rows.Next() {
 		var userMessage UserMessage
 		var role, rowStatus sql.NullString
-		var mfaConfigBytes []byte
+		var mfaConfigBytes, notificationSettingBytes []byte
 		if err := rows.Scan(
 			&userMessage.ID,
 			&userMessage.Email,
@@ -202,6 +206,7 @@ func (*Store) listUserImpl(ctx context.Context, tx *Tx, find *FindUserMessage) (
 			&userMessage.PasswordHash,
 			&mfaConfigBytes,
 			&userMessage.Phone,
+			&notificationSettingBytes,
 			&role,
 			&rowStatus,
 		); err != nil {
@@ -225,6 +230,11 @@ func (*Store) listUserImpl(ctx context.Context, tx *Tx, find *FindUserMessage) (
 			return nil, err
 		}
 		userMessage.MFAConfig = &mfaConfig
+		notificationSetting := storepb.NotificationSetting{}
+		if err := decoder.Unmarshal(notificationSettingBytes, &notificationSetting); err != nil {
+			return nil, err
+		}
+		userMessage.NotificationSetting = &notificationSetting
 		userMessages = append(userMessages, &userMessage)
 	}
 	if err := rows.Err(); err != nil {
@@ -345,6 +355,13 @@ func (s *Store) UpdateUser(ctx context.Context, userID int, patch *UpdateUserMes
 		}
 		principalSet, principalArgs = append(principalSet, fmt.Sprintf("mfa_config = $%d", len(principalArgs)+1)), append(principalArgs, mfaConfigBytes)
 	}
+	if v := patch.NotificationSetting; v != nil {
+		notificationSettingBytes, err := protojson.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		principalSet, principalArgs = append(principalSet, fmt.Sprintf("notification_setting = $%d", len(principalArgs)+1)), append(principalArgs, notificationSettingBytes)
+	}
 	principalArgs = append(principalArgs, userID)
 
 	memberSet, memberArgs := []string{"updater_id = $1"}, []any{fmt.Sprintf("%d", updaterID)}
@@ -367,12 +384,12 @@ func (s *Store) UpdateUser(ctx context.Context, userID int, patch *UpdateUserMes
 	defer tx.Rollback()
 
 	user := &UserMessage{}
-	var mfaConfigBytes []byte
+	var mfaConfigBytes, notificationSettingBytes []byte
 	if err := tx.QueryRowContext(ctx, fmt.Sprintf(`
 		UPDATE principal
 		SET `+strings.Join(principalSet, ", ")+`
 		WHERE id = $%d
-		RETURNING id, email, name, type, password_hash, mfa_config, phone
+		RETURNING id, email, name, type, password_hash, mfa_config, phone, notification_setting
 	`, len(principalArgs)),
 		principalArgs...,
 	).Scan(
@@ -383,6 +400,7 @@ func (s *Store) UpdateUser(ctx context.Context, userID int, patch *UpdateUserMes
 		&user.PasswordHash,
 		&mfaConfigBytes,
 		&user.Phone,
+		&notificationSettingBytes,
 	); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -395,6 +413,11 @@ func (s *Store) UpdateUser(ctx context.Context, userID int, patch *UpdateUserMes
 		return nil, err
 	}
 	user.MFAConfig = &mfaConfig
+	notificationSetting := storepb.NotificationSetting{}
+	if err := decoder.Unmarshal(notificationSettingBytes, &notificationSetting); err != nil {
+		return nil, err
+	}
+	user.NotificationSetting = &notificationSetting
 
 	var rowStatus string
 	if err := tx.QueryRowContext(ctx, fmt.Sprintf(`
@@ -195,6 +195,36 @@ func (s *Store) SetProjectIAMPolicy(ctx context.Context, set *IAMPolicyMessage,
 	return s.GetProjectPolicy(ctx, &GetProjectPolicyMessage{UID: &projectUID})
 }
 
+// DeleteProjectIAMPolicyBindings deletes the members of the bindings from the IAM policy of a project in a single transaction.
+// Unlike SetProjectIAMPolicy, it doesn't overwrite the bindings changed concurrently.
+func (s *Store) DeleteProjectIAMPolicyBindings(ctx context.Context, deletes *IAMPolicyMessage, projectUID int) error {
+	project, err := s.GetProjectV2(ctx, &FindProjectMessage{UID: &projectUID})
+	if err != nil {
+		return err
+	}
+	if project == nil {
+		return errors.Errorf("cannot find project %d", projectUID)
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := s.deleteProjectIAMPolicyImpl(ctx, tx, projectUID, deletes); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	s.projectPolicyCache.Delete(project.ResourceID)
+	s.projectIDPolicyCache.Delete(project.UID)
+	return nil
+}
+
 type roleConditionMapKey struct {
 	role      api.Role
 	condition string
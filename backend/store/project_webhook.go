@@ -23,6 +23,8 @@ type ProjectWebhookMessage struct {
 	URL string
 	// ActivityList is the list of activities that the webhook is interested in.
 	ActivityList []string
+	// SigningSecret is the secret for signing the request body of the custom webhooks.
+	SigningSecret string
 	// Output only fields.
 	//
 	// ID is the unique identifier of the project webhook.
@@ -38,6 +40,8 @@ type UpdateProjectWebhookMessage struct {
 	URL *string
 	// ActivityList is the list of activities that the webhook is interested in.
 	ActivityList []string
+	// SigningSecret is the secret for signing the request body of the custom webhooks.
+	SigningSecret *string
 }
 
 // FindProjectWebhookMessage is the message for finding project webhooks,
@@ -59,10 +63,11 @@ func (s *Store) CreateProjectWebhookV2(ctx context.Context, principalUID int, pr
 			type,
 			name,
 			url,
-			activity_list
+			activity_list,
+			signing_secret
 		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
-		RETURNING id, project_id, type, name, url, activity_list
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, project_id, type, name, url, activity_list, signing_secret
 	`
 	var projectWebhook ProjectWebhookMessage
 	var txtArray pgtype.TextArray
@@ -80,6 +85,7 @@ func (s *Store) CreateProjectWebhookV2(ctx context.Context, principalUID int, pr
 		create.Title,
 		create.URL,
 		create.ActivityList,
+		create.SigningSecret,
 	).Scan(
 		&projectWebhook.ID,
 		&projectWebhook.ProjectID,
@@ -87,6 +93,7 @@ func (s *Store) CreateProjectWebhookV2(ctx context.Context, principalUID int, pr
 		&projectWebhook.Title,
 		&projectWebhook.URL,
 		&txtArray,
+		&projectWebhook.SigningSecret,
 	); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, common.FormatDBErrorEmptyRowWithQuery(query)
@@ -165,6 +172,9 @@ func (s *Store) UpdateProjectWebhookV2(ctx context.Context, principalUID int, pr
 	if v := update.ActivityList; v != nil {
 		set, args = append(set, fmt.Sprintf("activity_list = $%d", len(args)+1)), append(args, v)
 	}
+	if v := update.SigningSecret; v != nil {
+		set, args = append(set, fmt.Sprintf("signing_secret = $%d", len(args)+1)), append(args, *v)
+	}
 
 	args = append(args, projectWebhookID)
 
@@ -175,7 +185,7 @@ func (s *Store) UpdateProjectWebhookV2(ctx context.Context, principalUID int, pr
 	UPDATE project_webhook
 	SET `+strings.Join(set, ", ")+`
 	WHERE id = $%d
-	RETURNING id, project_id, type, name, url, activity_list
+	RETURNING id, project_id, type, name, url, activity_list, signing_secret
 `, len(args)),
 		args...,
 	).Scan(
@@ -185,6 +195,7 @@ func (s *Store) UpdateProjectWebhookV2(ctx context.Context, principalUID int, pr
 		&projectWebhook.Title,
 		&projectWebhook.URL,
 		&txtArray,
+		&projectWebhook.SigningSecret,
 	); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, &common.Error{Code: common.NotFound, Err: errors.Errorf("project hook ID not found: %d", projectWebhookID)}
@@ -238,10 +249,12 @@ func (*Store) findProjectWebhookImplV2(ctx context.Context, tx *Tx, find *FindPr
 	rows, err := tx.QueryContext(ctx, `
 		SELECT
 			id,
+			project_id,
 			type,
 			name,
 			url,
-			activity_list
+			activity_list,
+			signing_secret
 		FROM project_webhook
 		WHERE `+strings.Join(where, " AND "),
 		args...,
@@ -258,10 +271,12 @@ func (*Store) findProjectWebhookImplV2(ctx context.Context, tx *Tx, find *FindPr
 
 		if err := rows.Scan(
 			&projectWebhook.ID,
+			&projectWebhook.ProjectID,
 			&projectWebhook.Type,
 			&projectWebhook.Title,
 			&projectWebhook.URL,
 			&txtArray,
+			&projectWebhook.SigningSecret,
 		); err != nil {
 			return nil, err
 		}
@@ -31,14 +31,16 @@ type RepositoryMessage struct {
 	SchemaPathTemplate string
 	SheetPathTemplate  string
 	EnableSQLReviewCI  bool
-	ExternalID         string
-	ExternalWebhookID  string
-	WebhookURLHost     string
-	WebhookEndpointID  string
-	WebhookSecretToken string
-	AccessToken        string
-	ExpiresTs          int64
-	RefreshToken       string
+	// EnableNativeSQLReview reviews the pull requests from the webhook events and comments on them, instead of the CI.
+	EnableNativeSQLReview bool
+	ExternalID            string
+	ExternalWebhookID     string
+	WebhookURLHost        string
+	WebhookEndpointID     string
+	WebhookSecretToken    string
+	AccessToken           string
+	ExpiresTs             int64
+	RefreshToken          string
 }
 
 // FindRepositoryMessage is the message for finding repositories.
@@ -56,15 +58,16 @@ type PatchRepositoryMessage struct {
 	WebURL *string
 
 	// Domain specific fields
-	BranchFilter       *string
-	BaseDirectory      *string
-	FilePathTemplate   *string
-	SchemaPathTemplate *string
-	SheetPathTemplate  *string
-	EnableSQLReviewCI  *bool
-	AccessToken        *string
-	ExpiresTs          *int64
-	RefreshToken       *string
+	BranchFilter          *string
+	BaseDirectory         *string
+	FilePathTemplate      *string
+	SchemaPathTemplate    *string
+	SheetPathTemplate     *string
+	EnableSQLReviewCI     *bool
+	EnableNativeSQLReview *bool
+	AccessToken           *string
+	ExpiresTs             *int64
+	RefreshToken          *string
 }
 
 // CreateRepositoryV2 creates the repository.
@@ -223,7 +226,7 @@ func (s *Store) createRepositoryImplV2(ctx context.Context, tx *Tx, create *Repo
 			refresh_token
 		)
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21)
-		RETURNING id, vcs_id, name, full_path, web_url, branch_filter, base_directory, file_path_template, schema_path_template, sheet_path_template, enable_sql_review_ci, external_id, external_webhook_id, webhook_url_host, webhook_endpoint_id, webhook_secret_token, access_token, expires_ts, refresh_token
+		RETURNING id, vcs_id, name, full_path, web_url, branch_filter, base_directory, file_path_template, schema_path_template, sheet_path_template, enable_sql_review_ci, enable_native_sql_review, external_id, external_webhook_id, webhook_url_host, webhook_endpoint_id, webhook_secret_token, access_token, expires_ts, refresh_token
 	`
 	if err := tx.QueryRowContext(ctx, query,
 		creatorID,
@@ -259,6 +262,7 @@ func (s *Store) createRepositoryImplV2(ctx context.Context, tx *Tx, create *Repo
 		&repository.SchemaPathTemplate,
 		&repository.SheetPathTemplate,
 		&repository.EnableSQLReviewCI,
+		&repository.EnableNativeSQLReview,
 		&repository.ExternalID,
 		&repository.ExternalWebhookID,
 		&repository.WebhookURLHost,
@@ -309,6 +313,7 @@ func (*Store) listRepositoryImplV2(ctx context.Context, tx *Tx, find *FindReposi
 			schema_path_template,
 			sheet_path_template,
 			enable_sql_review_ci,
+			enable_native_sql_review,
 			external_id,
 			external_webhook_id,
 			webhook_url_host,
@@ -344,6 +349,8 @@ func (*Store) listRepositoryImplV2(ctx context.Context, tx *Tx, find *FindReposi
 			&repository.SchemaPathTemplate,
 			&repository.SheetPathTemplate,
 			&repository.EnableSQLReviewCI,
+			&repository.EnableNativeSQLReview,
+			&repository.EnableNativeSQLReview,
 			&repository.ExternalID,
 			&repository.ExternalWebhookID,
 			&repository.WebhookURLHost,
@@ -397,6 +404,9 @@ func (*Store) patchRepositoryImplV2(ctx context.Context, tx *Tx, patch *PatchRep
 	if v := patch.EnableSQLReviewCI; v != nil {
 		set, args = append(set, fmt.Sprintf("enable_sql_review_ci = $%d", len(args)+1)), append(args, *v)
 	}
+	if v := patch.EnableNativeSQLReview; v != nil {
+		set, args = append(set, fmt.Sprintf("enable_native_sql_review = $%d", len(args)+1)), append(args, *v)
+	}
 
 	where := []string{}
 	if v := patch.UID; v != nil {
@@ -429,6 +439,7 @@ func (*Store) patchRepositoryImplV2(ctx context.Context, tx *Tx, patch *PatchRep
 			schema_path_template,
 			sheet_path_template,
 			enable_sql_review_ci,
+			enable_native_sql_review,
 			external_id,
 			external_webhook_id,
 			webhook_url_host,
@@ -452,6 +463,7 @@ func (*Store) patchRepositoryImplV2(ctx context.Context, tx *Tx, patch *PatchRep
 		&repository.SchemaPathTemplate,
 		&repository.SheetPathTemplate,
 		&repository.EnableSQLReviewCI,
+		&repository.EnableNativeSQLReview,
 		&repository.ExternalID,
 		&repository.ExternalWebhookID,
 		&repository.WebhookURLHost,
@@ -0,0 +1,208 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgtype"
+	"github.com/pkg/errors"
+)
+
+// SCIMGroupMessage is the message for the group provisioned by the SCIM client.
+type SCIMGroupMessage struct {
+	// ResourceID is the SCIM id of the group.
+	ResourceID string
+	// ExternalID is the id of the group in the SCIM client.
+	ExternalID  string
+	DisplayName string
+	// MemberIDs are the principal ids of the group members.
+	MemberIDs []int
+
+	// Output only fields.
+	UID       int
+	CreatedTs int64
+	UpdatedTs int64
+}
+
+// FindSCIMGroupMessage is the message for finding SCIM groups.
+type FindSCIMGroupMessage struct {
+	ResourceID  *string
+	DisplayName *string
+	MemberID    *int
+}
+
+// UpdateSCIMGroupMessage is the message for updating a SCIM group.
+type UpdateSCIMGroupMessage struct {
+	ExternalID  *string
+	DisplayName *string
+	MemberIDs   *[]int
+}
+
+// GetSCIMGroup gets a SCIM group.
+func (s *Store) GetSCIMGroup(ctx context.Context, find *FindSCIMGroupMessage) (*SCIMGroupMessage, error) {
+	groups, err := s.ListSCIMGroups(ctx, find)
+	if err != nil {
+		return nil, err
+	}
+	if len(groups) == 0 {
+		return nil, nil
+	}
+	if len(groups) > 1 {
+		return nil, errors.Errorf("found %d SCIM groups with filter %+v, expect 1", len(groups), find)
+	}
+	return groups[0], nil
+}
+
+// ListSCIMGroups lists SCIM groups.
+func (s *Store) ListSCIMGroups(ctx context.Context, find *FindSCIMGroupMessage) ([]*SCIMGroupMessage, error) {
+	where, args := []string{"TRUE"}, []any{}
+	if v := find.ResourceID; v != nil {
+		where, args = append(where, fmt.Sprintf("resource_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.DisplayName; v != nil {
+		where, args = append(where, fmt.Sprintf("display_name = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.MemberID; v != nil {
+		where, args = append(where, fmt.Sprintf("$%d = ANY(member_ids)", len(args)+1)), append(args, *v)
+	}
+
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, fmt.Sprintf(`
+		SELECT
+			id,
+			created_ts,
+			updated_ts,
+			resource_id,
+			external_id,
+			display_name,
+			member_ids
+		FROM scim_group
+		WHERE %s
+		ORDER BY id ASC`, strings.Join(where, " AND ")),
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var groups []*SCIMGroupMessage
+	for rows.Next() {
+		group := &SCIMGroupMessage{}
+		var memberIDs pgtype.Int4Array
+		if err := rows.Scan(
+			&group.UID,
+			&group.CreatedTs,
+			&group.UpdatedTs,
+			&group.ResourceID,
+			&group.ExternalID,
+			&group.DisplayName,
+			&memberIDs,
+		); err != nil {
+			return nil, err
+		}
+		if err := memberIDs.AssignTo(&group.MemberIDs); err != nil {
+			return nil, err
+		}
+		groups = append(groups, group)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+// CreateSCIMGroup creates a SCIM group.
+func (s *Store) CreateSCIMGroup(ctx context.Context, create *SCIMGroupMessage) (*SCIMGroupMessage, error) {
+	var memberIDs pgtype.Int4Array
+	if err := memberIDs.Set(nonNilMemberIDs(create.MemberIDs)); err != nil {
+		return nil, err
+	}
+	query := `
+		INSERT INTO scim_group (
+			resource_id,
+			external_id,
+			display_name,
+			member_ids
+		)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_ts, updated_ts
+	`
+	group := &SCIMGroupMessage{
+		ResourceID:  create.ResourceID,
+		ExternalID:  create.ExternalID,
+		DisplayName: create.DisplayName,
+		MemberIDs:   create.MemberIDs,
+	}
+	if err := s.db.db.QueryRowContext(ctx, query,
+		create.ResourceID,
+		create.ExternalID,
+		create.DisplayName,
+		&memberIDs,
+	).Scan(
+		&group.UID,
+		&group.CreatedTs,
+		&group.UpdatedTs,
+	); err != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
+// UpdateSCIMGroup updates a SCIM group.
+func (s *Store) UpdateSCIMGroup(ctx context.Context, resourceID string, patch *UpdateSCIMGroupMessage) (*SCIMGroupMessage, error) {
+	set, args := []string{}, []any{}
+	if v := patch.ExternalID; v != nil {
+		set, args = append(set, fmt.Sprintf("external_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.DisplayName; v != nil {
+		set, args = append(set, fmt.Sprintf("display_name = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.MemberIDs; v != nil {
+		var memberIDs pgtype.Int4Array
+		if err := memberIDs.Set(nonNilMemberIDs(*v)); err != nil {
+			return nil, err
+		}
+		set, args = append(set, fmt.Sprintf("member_ids = $%d", len(args)+1)), append(args, &memberIDs)
+	}
+	if len(set) == 0 {
+		return s.GetSCIMGroup(ctx, &FindSCIMGroupMessage{ResourceID: &resourceID})
+	}
+	args = append(args, resourceID)
+
+	query := fmt.Sprintf(`
+		UPDATE scim_group
+		SET %s
+		WHERE resource_id = $%d
+	`, strings.Join(set, ", "), len(args))
+	if _, err := s.db.db.ExecContext(ctx, query, args...); err != nil {
+		return nil, err
+	}
+	return s.GetSCIMGroup(ctx, &FindSCIMGroupMessage{ResourceID: &resourceID})
+}
+
+// DeleteSCIMGroup deletes a SCIM group.
+func (s *Store) DeleteSCIMGroup(ctx context.Context, resourceID string) error {
+	if _, err := s.db.db.ExecContext(ctx, `DELETE FROM scim_group WHERE resource_id = $1`, resourceID); err != nil {
+		return err
+	}
+	return nil
+}
+
+func nonNilMemberIDs(memberIDs []int) []int {
+	if memberIDs == nil {
+		return []int{}
+	}
+	return memberIDs
+}
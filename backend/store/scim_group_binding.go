@@ -0,0 +1,71 @@
+package store
+
+import (
+	"context"
+
+	"github.com/jackc/pgtype"
+)
+
+// ListSCIMGroupBindingMemberIDs lists the principal ids of the members granted the project role by the SCIM group mappings.
+func (s *Store) ListSCIMGroupBindingMemberIDs(ctx context.Context, project, role string) ([]int, error) {
+	rows, err := s.db.db.QueryContext(ctx, `
+		SELECT principal_id
+		FROM scim_group_binding
+		WHERE project = $1 AND role = $2
+		ORDER BY principal_id
+	`, project, role)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var memberIDs []int
+	for rows.Next() {
+		var memberID int
+		if err := rows.Scan(&memberID); err != nil {
+			return nil, err
+		}
+		memberIDs = append(memberIDs, memberID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return memberIDs, nil
+}
+
+// AddSCIMGroupBindingMembers records the members granted the project role by the SCIM group mappings.
+func (s *Store) AddSCIMGroupBindingMembers(ctx context.Context, project, role string, memberIDs []int) error {
+	if len(memberIDs) == 0 {
+		return nil
+	}
+	var ids pgtype.Int4Array
+	if err := ids.Set(memberIDs); err != nil {
+		return err
+	}
+	if _, err := s.db.db.ExecContext(ctx, `
+		INSERT INTO scim_group_binding (project, role, principal_id)
+		SELECT $1, $2, unnest($3::INTEGER[])
+		ON CONFLICT DO NOTHING
+	`, project, role, ids); err != nil {
+		return err
+	}
+	return nil
+}
+
+// DeleteSCIMGroupBindingMembers deletes the records of the members granted the project role by the SCIM group mappings.
+func (s *Store) DeleteSCIMGroupBindingMembers(ctx context.Context, project, role string, memberIDs []int) error {
+	if len(memberIDs) == 0 {
+		return nil
+	}
+	var ids pgtype.Int4Array
+	if err := ids.Set(memberIDs); err != nil {
+		return err
+	}
+	if _, err := s.db.db.ExecContext(ctx, `
+		DELETE FROM scim_group_binding
+		WHERE project = $1 AND role = $2 AND principal_id = ANY($3)
+	`, project, role, ids); err != nil {
+		return err
+	}
+	return nil
+}
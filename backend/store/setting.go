@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"strings"
 	"sync"
 
@@ -113,6 +114,151 @@ func (s *Store) GetAppIMApprovalSetting(ctx context.Context) (*api.SettingAppIMV
 	return &value, nil
 }
 
+// GetShadowDatabaseSetting gets the shadow database setting.
+func (s *Store) GetShadowDatabaseSetting(ctx context.Context) (*api.SettingShadowDatabaseValue, error) {
+	settingName := api.SettingShadowDatabase
+	setting, err := s.GetSettingV2(ctx, &FindSettingMessage{Name: &settingName})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get setting %s", settingName)
+	}
+	value := &api.SettingShadowDatabaseValue{}
+	if setting == nil || setting.Value == "" {
+		return value, nil
+	}
+	if err := json.Unmarshal([]byte(setting.Value), value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// GetDataRollbackSetting gets the data rollback setting.
+func (s *Store) GetDataRollbackSetting(ctx context.Context) (*api.SettingDataRollbackValue, error) {
+	settingName := api.SettingDataRollback
+	setting, err := s.GetSettingV2(ctx, &FindSettingMessage{Name: &settingName})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get setting %s", settingName)
+	}
+	value := &api.SettingDataRollbackValue{}
+	if setting != nil && setting.Value != "" {
+		if err := json.Unmarshal([]byte(setting.Value), value); err != nil {
+			return nil, err
+		}
+	}
+	if value.PreImageRowLimit <= 0 {
+		value.PreImageRowLimit = common.DefaultRollbackPreImageRowLimit
+	}
+	return value, nil
+}
+
+// GetSCIMSetting gets the SCIM setting.
+func (s *Store) GetSCIMSetting(ctx context.Context) (*api.SettingSCIMValue, error) {
+	settingName := api.SettingSCIM
+	setting, err := s.GetSettingV2(ctx, &FindSettingMessage{Name: &settingName})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get setting %s", settingName)
+	}
+	value := &api.SettingSCIMValue{}
+	if setting != nil && setting.Value != "" {
+		if err := json.Unmarshal([]byte(setting.Value), value); err != nil {
+			return nil, err
+		}
+	}
+	return value, nil
+}
+
+// GetLDAPGroupSyncSetting gets the LDAP group sync setting.
+func (s *Store) GetLDAPGroupSyncSetting(ctx context.Context) (*api.SettingLDAPGroupSyncValue, error) {
+	settingName := api.SettingLDAPGroupSync
+	setting, err := s.GetSettingV2(ctx, &FindSettingMessage{Name: &settingName})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get setting %s", settingName)
+	}
+	value := &api.SettingLDAPGroupSyncValue{}
+	if setting != nil && setting.Value != "" {
+		if err := json.Unmarshal([]byte(setting.Value), value); err != nil {
+			return nil, err
+		}
+	}
+	return value, nil
+}
+
+// GetIdentityProviderClaimMappingSetting gets the identity provider claim mapping setting.
+func (s *Store) GetIdentityProviderClaimMappingSetting(ctx context.Context) (*api.SettingIdentityProviderClaimMappingValue, error) {
+	settingName := api.SettingIdentityProviderClaimMapping
+	setting, err := s.GetSettingV2(ctx, &FindSettingMessage{Name: &settingName})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get setting %s", settingName)
+	}
+	value := &api.SettingIdentityProviderClaimMappingValue{}
+	if setting != nil && setting.Value != "" {
+		if err := json.Unmarshal([]byte(setting.Value), value); err != nil {
+			return nil, err
+		}
+	}
+	return value, nil
+}
+
+// GetPasswordPolicySetting gets the password policy setting with the defaults of the login throttling.
+func (s *Store) GetPasswordPolicySetting(ctx context.Context) (*api.SettingPasswordPolicyValue, error) {
+	settingName := api.SettingPasswordPolicy
+	setting, err := s.GetSettingV2(ctx, &FindSettingMessage{Name: &settingName})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get setting %s", settingName)
+	}
+	value := &api.SettingPasswordPolicyValue{}
+	if setting != nil && setting.Value != "" {
+		if err := json.Unmarshal([]byte(setting.Value), value); err != nil {
+			return nil, err
+		}
+	}
+	if value.MaxAccountFailedAttempts <= 0 {
+		value.MaxAccountFailedAttempts = api.DefaultMaxAccountFailedAttempts
+	}
+	if value.MaxIPFailedAttempts <= 0 {
+		value.MaxIPFailedAttempts = api.DefaultMaxIPFailedAttempts
+	}
+	if value.LockoutMinutes <= 0 {
+		value.LockoutMinutes = api.DefaultLockoutMinutes
+	}
+	return value, nil
+}
+
+// GetWebAuthnSetting gets the WebAuthn setting. The RP ID and the origins default to the workspace external URL.
+func (s *Store) GetWebAuthnSetting(ctx context.Context) (*api.SettingWebAuthnValue, error) {
+	settingName := api.SettingWebAuthn
+	setting, err := s.GetSettingV2(ctx, &FindSettingMessage{Name: &settingName})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get setting %s", settingName)
+	}
+	value := &api.SettingWebAuthnValue{}
+	if setting != nil && setting.Value != "" {
+		if err := json.Unmarshal([]byte(setting.Value), value); err != nil {
+			return nil, err
+		}
+	}
+	if value.RPID != "" && len(value.Origins) > 0 {
+		return value, nil
+	}
+	generalSetting, err := s.GetWorkspaceGeneralSetting(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if generalSetting.ExternalUrl == "" {
+		return value, nil
+	}
+	externalURL, err := url.Parse(generalSetting.ExternalUrl)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid external URL %q", generalSetting.ExternalUrl)
+	}
+	if value.RPID == "" {
+		value.RPID = externalURL.Hostname()
+	}
+	if len(value.Origins) == 0 {
+		value.Origins = []string{fmt.Sprintf("%s://%s", externalURL.Scheme, externalURL.Host)}
+	}
+	return value, nil
+}
+
 // GetWorkspaceExternalApprovalSetting gets the workspace external approval setting.
 func (s *Store) GetWorkspaceExternalApprovalSetting(ctx context.Context) (*storepb.ExternalApprovalSetting, error) {
 	settingName := api.SettingWorkspaceExternalApproval
@@ -400,3 +546,35 @@ func listSettingV2Impl(ctx context.Context, tx *Tx, find *FindSettingMessage) ([
 
 	return settingMessages, nil
 }
+
+// GetAuditLogExportSetting gets the audit log export setting.
+func (s *Store) GetAuditLogExportSetting(ctx context.Context) (*api.SettingAuditLogExportValue, error) {
+	settingName := api.SettingAuditLogExport
+	setting, err := s.GetSettingV2(ctx, &FindSettingMessage{Name: &settingName})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get setting %s", settingName)
+	}
+	value := &api.SettingAuditLogExportValue{}
+	if setting != nil && setting.Value != "" {
+		if err := json.Unmarshal([]byte(setting.Value), value); err != nil {
+			return nil, err
+		}
+	}
+	return value, nil
+}
+
+// GetWorkspaceWebhookSetting gets the workspace webhook setting.
+func (s *Store) GetWorkspaceWebhookSetting(ctx context.Context) (*api.SettingWorkspaceWebhookValue, error) {
+	settingName := api.SettingWorkspaceWebhook
+	setting, err := s.GetSettingV2(ctx, &FindSettingMessage{Name: &settingName})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get setting %s", settingName)
+	}
+	value := &api.SettingWorkspaceWebhookValue{}
+	if setting != nil && setting.Value != "" {
+		if err := json.Unmarshal([]byte(setting.Value), value); err != nil {
+			return nil, err
+		}
+	}
+	return value, nil
+}
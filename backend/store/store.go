@@ -49,6 +49,9 @@ type Store struct {
 	// sheetStatementCache caches the statement of a sheet.
 	sheetStatementCache *ristretto.Cache // map[sheetUID]sheetStatementString
 	vcsIDCache          sync.Map         // map[int]*ExternalVersionControlMessage
+
+	// activityLedgerKey is the key of the activity ledger hash chain.
+	activityLedgerKey []byte
 }
 
 // New creates a new instance of Store.
@@ -0,0 +1,49 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// CreateWebAuthnChallenge stores the issued WebAuthn challenge of the subject until expiresTs.
+// The expired challenges are removed at the same time.
+func (s *Store) CreateWebAuthnChallenge(ctx context.Context, challenge, subject string, expiresTs int64) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM webauthn_challenge WHERE expires_ts <= $1`, time.Now().Unix()); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO webauthn_challenge (
+			challenge,
+			subject,
+			expires_ts
+		)
+		VALUES ($1, $2, $3)
+	`, challenge, subject, expiresTs); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ConsumeWebAuthnChallenge deletes the unexpired WebAuthn challenge of the subject atomically,
+// and returns false if the challenge is not found, e.g. it has been consumed by a concurrent request.
+func (s *Store) ConsumeWebAuthnChallenge(ctx context.Context, challenge, subject string) (bool, error) {
+	var id int64
+	if err := s.db.db.QueryRowContext(ctx, `
+		DELETE FROM webauthn_challenge
+		WHERE challenge = $1 AND subject = $2 AND expires_ts > $3
+		RETURNING id
+	`, challenge, subject, time.Now().Unix()).Scan(&id); err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
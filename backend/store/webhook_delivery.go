@@ -0,0 +1,303 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/backend/common"
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+)
+
+// WebhookDeliveryStatus is the status of the webhook delivery.
+type WebhookDeliveryStatus string
+
+const (
+	// WebhookDeliveryPending is the status of the deliveries waiting for the next attempt.
+	WebhookDeliveryPending WebhookDeliveryStatus = "PENDING"
+	// WebhookDeliveryDelivered is the status of the deliveries accepted by the receiver.
+	WebhookDeliveryDelivered WebhookDeliveryStatus = "DELIVERED"
+	// WebhookDeliveryDeadLetter is the status of the deliveries that run out of the attempts.
+	WebhookDeliveryDeadLetter WebhookDeliveryStatus = "DEAD_LETTER"
+)
+
+// WebhookDeliveryMessage is the message for a webhook delivery.
+// The delivery targets either a project webhook or a workspace webhook.
+type WebhookDeliveryMessage struct {
+	// ProjectWebhookID is 0 for the workspace webhook deliveries.
+	ProjectWebhookID int
+	// WorkspaceWebhookID is the ID of the workspace webhook in the setting, it's empty for the project webhook deliveries.
+	WorkspaceWebhookID string
+	ActivityType       api.ActivityType
+	// Payload is the JSON encoded webhook context.
+	Payload string
+
+	// Output only fields.
+	ID            int64
+	CreatedTs     int64
+	UpdatedTs     int64
+	Status        WebhookDeliveryStatus
+	Attempts      int
+	NextAttemptTs int64
+	ResponseCode  int
+	Error         string
+}
+
+// FindWebhookDeliveryMessage is the message for finding webhook deliveries.
+type FindWebhookDeliveryMessage struct {
+	ID                 *int64
+	ProjectWebhookID   *int
+	WorkspaceWebhookID *string
+	Status             *WebhookDeliveryStatus
+	// NextAttemptTsBefore finds the deliveries due before the timestamp.
+	NextAttemptTsBefore *int64
+	Limit               *int
+	Offset              *int
+}
+
+// UpdateWebhookDeliveryMessage is the message for updating a webhook delivery.
+type UpdateWebhookDeliveryMessage struct {
+	ID            int64
+	Status        *WebhookDeliveryStatus
+	Attempts      *int
+	NextAttemptTs *int64
+	ResponseCode  *int
+	Error         *string
+}
+
+// CreateWebhookDeliveries creates the pending webhook deliveries.
+func (s *Store) CreateWebhookDeliveries(ctx context.Context, creates []*WebhookDeliveryMessage) error {
+	if len(creates) == 0 {
+		return nil
+	}
+	var values []string
+	var args []any
+	for _, create := range creates {
+		var projectWebhookID *int
+		if create.ProjectWebhookID != 0 {
+			projectWebhookID = &create.ProjectWebhookID
+		}
+		values = append(values, fmt.Sprintf("($%d, $%d, $%d, $%d, $%d)", len(args)+1, len(args)+2, len(args)+3, len(args)+4, len(args)+5))
+		args = append(args, projectWebhookID, create.WorkspaceWebhookID, create.ActivityType, create.Payload, WebhookDeliveryPending)
+	}
+	query := fmt.Sprintf(`
+		INSERT INTO webhook_delivery (
+			project_webhook_id,
+			workspace_webhook_id,
+			activity_type,
+			payload,
+			status
+		) VALUES %s
+	`, strings.Join(values, ", "))
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return errors.Wrapf(err, "failed to create webhook deliveries")
+	}
+	return tx.Commit()
+}
+
+// GetWebhookDelivery gets the webhook delivery.
+func (s *Store) GetWebhookDelivery(ctx context.Context, find *FindWebhookDeliveryMessage) (*WebhookDeliveryMessage, error) {
+	deliveries, err := s.ListWebhookDeliveries(ctx, find)
+	if err != nil {
+		return nil, err
+	}
+	if len(deliveries) == 0 {
+		return nil, nil
+	}
+	if len(deliveries) > 1 {
+		return nil, &common.Error{Code: common.Conflict, Err: errors.Errorf("found %d webhook deliveries with filter %+v, expect 1", len(deliveries), find)}
+	}
+	return deliveries[0], nil
+}
+
+// ListWebhookDeliveries lists the webhook deliveries in the descending order of the ID.
+// The due deliveries, i.e. with NextAttemptTsBefore, are listed in the ascending order of the next attempt time instead.
+func (s *Store) ListWebhookDeliveries(ctx context.Context, find *FindWebhookDeliveryMessage) ([]*WebhookDeliveryMessage, error) {
+	where, args := []string{"TRUE"}, []any{}
+	if v := find.ID; v != nil {
+		where, args = append(where, fmt.Sprintf("id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.ProjectWebhookID; v != nil {
+		where, args = append(where, fmt.Sprintf("project_webhook_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.WorkspaceWebhookID; v != nil {
+		where, args = append(where, fmt.Sprintf("workspace_webhook_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.Status; v != nil {
+		where, args = append(where, fmt.Sprintf("status = $%d", len(args)+1)), append(args, *v)
+	}
+	orderBy := "id DESC"
+	if v := find.NextAttemptTsBefore; v != nil {
+		where, args = append(where, fmt.Sprintf("next_attempt_ts <= $%d", len(args)+1)), append(args, *v)
+		orderBy = "next_attempt_ts ASC, id ASC"
+	}
+
+	query := fmt.Sprintf(`
+		SELECT
+			id,
+			created_ts,
+			updated_ts,
+			project_webhook_id,
+			workspace_webhook_id,
+			activity_type,
+			payload,
+			status,
+			attempts,
+			next_attempt_ts,
+			response_code,
+			error
+		FROM webhook_delivery
+		WHERE %s
+		ORDER BY %s`, strings.Join(where, " AND "), orderBy)
+	if v := find.Limit; v != nil {
+		query += fmt.Sprintf(" LIMIT %d", *v)
+	}
+	if v := find.Offset; v != nil {
+		query += fmt.Sprintf(" OFFSET %d", *v)
+	}
+
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []*WebhookDeliveryMessage
+	for rows.Next() {
+		delivery := &WebhookDeliveryMessage{}
+		var projectWebhookID sql.NullInt32
+		if err := rows.Scan(
+			&delivery.ID,
+			&delivery.CreatedTs,
+			&delivery.UpdatedTs,
+			&projectWebhookID,
+			&delivery.WorkspaceWebhookID,
+			&delivery.ActivityType,
+			&delivery.Payload,
+			&delivery.Status,
+			&delivery.Attempts,
+			&delivery.NextAttemptTs,
+			&delivery.ResponseCode,
+			&delivery.Error,
+		); err != nil {
+			return nil, err
+		}
+		delivery.ProjectWebhookID = int(projectWebhookID.Int32)
+		deliveries = append(deliveries, delivery)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// UpdateWebhookDelivery updates the webhook delivery.
+func (s *Store) UpdateWebhookDelivery(ctx context.Context, update *UpdateWebhookDeliveryMessage) (*WebhookDeliveryMessage, error) {
+	set, args := []string{}, []any{}
+	if v := update.Status; v != nil {
+		set, args = append(set, fmt.Sprintf("status = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := update.Attempts; v != nil {
+		set, args = append(set, fmt.Sprintf("attempts = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := update.NextAttemptTs; v != nil {
+		set, args = append(set, fmt.Sprintf("next_attempt_ts = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := update.ResponseCode; v != nil {
+		set, args = append(set, fmt.Sprintf("response_code = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := update.Error; v != nil {
+		set, args = append(set, fmt.Sprintf("error = $%d", len(args)+1)), append(args, *v)
+	}
+	if len(set) == 0 {
+		return nil, errors.New("no update field provided")
+	}
+	args = append(args, update.ID)
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	delivery := &WebhookDeliveryMessage{}
+	var projectWebhookID sql.NullInt32
+	if err := tx.QueryRowContext(ctx, fmt.Sprintf(`
+		UPDATE webhook_delivery
+		SET %s
+		WHERE id = $%d
+		RETURNING
+			id,
+			created_ts,
+			updated_ts,
+			project_webhook_id,
+			workspace_webhook_id,
+			activity_type,
+			payload,
+			status,
+			attempts,
+			next_attempt_ts,
+			response_code,
+			error
+	`, strings.Join(set, ", "), len(args)), args...).Scan(
+		&delivery.ID,
+		&delivery.CreatedTs,
+		&delivery.UpdatedTs,
+		&projectWebhookID,
+		&delivery.WorkspaceWebhookID,
+		&delivery.ActivityType,
+		&delivery.Payload,
+		&delivery.Status,
+		&delivery.Attempts,
+		&delivery.NextAttemptTs,
+		&delivery.ResponseCode,
+		&delivery.Error,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &common.Error{Code: common.NotFound, Err: errors.Errorf("webhook delivery %d not found", update.ID)}
+		}
+		return nil, err
+	}
+	delivery.ProjectWebhookID = int(projectWebhookID.Int32)
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+// DeleteWebhookDeliveries deletes the delivered and the dead letter deliveries last updated before the timestamp,
+// and returns the number of the deleted deliveries.
+func (s *Store) DeleteWebhookDeliveries(ctx context.Context, updatedBeforeTs int64) (int64, error) {
+	result, err := s.db.db.ExecContext(ctx, `
+		DELETE FROM webhook_delivery
+		WHERE status <> $1 AND updated_ts < $2
+	`, WebhookDeliveryPending, updatedBeforeTs)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to delete webhook deliveries")
+	}
+	count, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
@@ -0,0 +1,192 @@
+package fake
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/backend/common/signature"
+	"github.com/bytebase/bytebase/backend/plugin/app/externalapproval"
+)
+
+// ExternalApproval is a fake ticketing system implementing the APIs of the generic HTTP external approval provider.
+type ExternalApproval struct {
+	port   int
+	echo   *echo.Echo
+	client *http.Client
+	secret string
+
+	mu             sync.Mutex
+	nextID         int
+	changeRequests map[string]*externalapproval.ChangeRequest
+	// payloads is the map from the change request ID to the payload creating it.
+	payloads map[string]*externalapproval.CreatePayload
+	canceled map[string]bool
+}
+
+// NewExternalApproval creates a new fake ticketing system, the requests must be signed with the secret.
+func NewExternalApproval(port int, secret string) *ExternalApproval {
+	e := newEchoServer()
+	ea := &ExternalApproval{
+		port:           port,
+		echo:           e,
+		client:         &http.Client{},
+		secret:         secret,
+		nextID:         1,
+		changeRequests: make(map[string]*externalapproval.ChangeRequest),
+		payloads:       make(map[string]*externalapproval.CreatePayload),
+		canceled:       make(map[string]bool),
+	}
+	e.Use(ea.verifySignature)
+	e.POST("/change-requests", ea.createChangeRequest)
+	e.GET("/change-requests/:id", ea.getChangeRequest)
+	// The path of the custom method is "/change-requests/{id}:cancel".
+	e.POST("/change-requests/:id", ea.cancelChangeRequest)
+	return ea
+}
+
+// Run starts the fake ticketing system server.
+func (ea *ExternalApproval) Run() error {
+	return ea.echo.Start(fmt.Sprintf(":%d", ea.port))
+}
+
+// Close shuts down the fake ticketing system server.
+func (ea *ExternalApproval) Close() error {
+	return ea.echo.Close()
+}
+
+// ListenerAddr returns the fake ticketing system server listener address.
+func (ea *ExternalApproval) ListenerAddr() net.Addr {
+	return ea.echo.ListenerAddr()
+}
+
+// ChangeRequests returns the payloads of the created change requests by the change request IDs.
+func (ea *ExternalApproval) ChangeRequests() map[string]*externalapproval.CreatePayload {
+	ea.mu.Lock()
+	defer ea.mu.Unlock()
+	payloads := make(map[string]*externalapproval.CreatePayload)
+	for id, payload := range ea.payloads {
+		payloads[id] = payload
+	}
+	return payloads
+}
+
+// IsCanceled returns true if the change request is canceled.
+func (ea *ExternalApproval) IsCanceled(id string) bool {
+	ea.mu.Lock()
+	defer ea.mu.Unlock()
+	return ea.canceled[id]
+}
+
+// SetStatus sets the status of the change request, and Bytebase gets the status by polling.
+func (ea *ExternalApproval) SetStatus(id string, status externalapproval.Status) error {
+	ea.mu.Lock()
+	defer ea.mu.Unlock()
+	changeRequest, ok := ea.changeRequests[id]
+	if !ok {
+		return errors.Errorf("change request %q not found", id)
+	}
+	changeRequest.Status = status
+	return nil
+}
+
+// SendCallback sets the status of the change request, and posts the signed change request to the callback URL.
+func (ea *ExternalApproval) SendCallback(callbackURL, id string, status externalapproval.Status) error {
+	if err := ea.SetStatus(id, status); err != nil {
+		return err
+	}
+	ea.mu.Lock()
+	body, err := json.Marshal(ea.changeRequests[id])
+	ea.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, callbackURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(signature.TimestampHeader, timestamp)
+	req.Header.Set(signature.Header, signature.Sign(ea.secret, timestamp, body))
+	resp, err := ea.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("failed to send callback with status %v", resp.Status)
+	}
+	return nil
+}
+
+func (ea *ExternalApproval) verifySignature(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		body, err := io.ReadAll(c.Request().Body)
+		if err != nil {
+			return c.String(http.StatusBadRequest, fmt.Sprintf("failed to read request body, error %v", err))
+		}
+		c.Request().Body = io.NopCloser(bytes.NewReader(body))
+		header := c.Request().Header
+		if err := signature.Verify(ea.secret, header.Get(signature.TimestampHeader), header.Get(signature.Header), body, time.Now()); err != nil {
+			return c.String(http.StatusUnauthorized, err.Error())
+		}
+		return next(c)
+	}
+}
+
+func (ea *ExternalApproval) createChangeRequest(c echo.Context) error {
+	payload := &externalapproval.CreatePayload{}
+	if err := json.NewDecoder(c.Request().Body).Decode(payload); err != nil {
+		return c.String(http.StatusBadRequest, fmt.Sprintf("failed to unmarshal request body, error %v", err))
+	}
+
+	ea.mu.Lock()
+	defer ea.mu.Unlock()
+	id := fmt.Sprintf("CHG%07d", ea.nextID)
+	ea.nextID++
+	changeRequest := &externalapproval.ChangeRequest{
+		ID:     id,
+		Status: externalapproval.StatusPending,
+		URL:    fmt.Sprintf("http://localhost:%d/change-requests/%s", ea.port, id),
+	}
+	ea.changeRequests[id] = changeRequest
+	ea.payloads[id] = payload
+	return c.JSON(http.StatusOK, changeRequest)
+}
+
+func (ea *ExternalApproval) getChangeRequest(c echo.Context) error {
+	ea.mu.Lock()
+	defer ea.mu.Unlock()
+	changeRequest, ok := ea.changeRequests[c.Param("id")]
+	if !ok {
+		return c.String(http.StatusNotFound, fmt.Sprintf("change request %q not found", c.Param("id")))
+	}
+	return c.JSON(http.StatusOK, changeRequest)
+}
+
+func (ea *ExternalApproval) cancelChangeRequest(c echo.Context) error {
+	id, ok := strings.CutSuffix(c.Param("id"), ":cancel")
+	if !ok {
+		return c.String(http.StatusNotFound, fmt.Sprintf("method not found for %q", c.Param("id")))
+	}
+
+	ea.mu.Lock()
+	defer ea.mu.Unlock()
+	changeRequest, ok := ea.changeRequests[id]
+	if !ok {
+		return c.String(http.StatusNotFound, fmt.Sprintf("change request %q not found", id))
+	}
+	ea.canceled[id] = true
+	return c.JSON(http.StatusOK, changeRequest)
+}
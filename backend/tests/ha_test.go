@@ -0,0 +1,120 @@
+package tests
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+
+	"github.com/bytebase/bytebase/backend/tests/fake"
+	v1pb "github.com/bytebase/bytebase/proto/generated-go/v1"
+)
+
+// haLeaderLockKey is the advisory lock key for the leader election in the server package.
+const haLeaderLockKey = 0x6279746562617365
+
+func TestHA(t *testing.T) {
+	t.Parallel()
+	a := require.New(t)
+	ctx := context.Background()
+	ctl := &controller{}
+	ctx, err := ctl.StartServerWithExternalPg(ctx, &config{
+		dataDir:            t.TempDir(),
+		vcsProviderCreator: fake.NewGitLab,
+		ha:                 true,
+	})
+	a.NoError(err)
+	defer ctl.Close(ctx)
+
+	metaDB, err := sql.Open("pgx", ctl.profile.PgURL)
+	a.NoError(err)
+	defer metaDB.Close()
+
+	// getLeaderPID returns the backend PID holding the leader lock, or 0 if there is no leader.
+	getLeaderPID := func() int {
+		var pid int
+		if err := metaDB.QueryRowContext(ctx, `
+			SELECT pid FROM pg_locks
+			WHERE locktype = 'advisory' AND classid = $1 AND objid = $2 AND objsubid = 1 AND granted`,
+			uint32(haLeaderLockKey>>32), uint32(haLeaderLockKey&0xFFFFFFFF),
+		).Scan(&pid); err != nil {
+			return 0
+		}
+		return pid
+	}
+
+	t.Run("election", func(t *testing.T) {
+		a := require.New(t)
+		var leaderPID int
+		a.Eventually(func() bool {
+			leaderPID = getLeaderPID()
+			return leaderPID != 0
+		}, 30*time.Second, 100*time.Millisecond)
+
+		// The other replicas cannot take the leadership.
+		var acquired bool
+		err := metaDB.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", int64(haLeaderLockKey)).Scan(&acquired)
+		a.NoError(err)
+		a.False(acquired)
+
+		// The leadership is re-elected after losing the leader lock.
+		_, err = metaDB.ExecContext(ctx, "SELECT pg_terminate_backend($1)", leaderPID)
+		a.NoError(err)
+		a.Eventually(func() bool {
+			pid := getLeaderPID()
+			return pid != 0 && pid != leaderPID
+		}, 60*time.Second, 500*time.Millisecond)
+	})
+
+	t.Run("cache invalidation", func(t *testing.T) {
+		a := require.New(t)
+		var count int
+		err := metaDB.QueryRowContext(ctx, `
+			SELECT COUNT(*) FROM pg_trigger
+			WHERE tgname IN ('notify_project_cache_invalidation', 'notify_project_cache_truncation')`,
+		).Scan(&count)
+		a.NoError(err)
+		a.Equal(2, count)
+
+		conn, err := pgx.Connect(ctx, ctl.profile.PgURL)
+		a.NoError(err)
+		defer conn.Close(ctx)
+		_, err = conn.Exec(ctx, "LISTEN bb_cache_invalidation")
+		a.NoError(err)
+
+		var projectUID string
+		err = metaDB.QueryRowContext(ctx, "SELECT id::TEXT FROM project WHERE resource_id = $1", "test-project").Scan(&projectUID)
+		a.NoError(err)
+		_, err = ctl.projectServiceClient.UpdateProject(ctx, &v1pb.UpdateProjectRequest{
+			Project: &v1pb.Project{
+				Name:  "projects/test-project",
+				Title: "renamed",
+			},
+			UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"title"}},
+		})
+		a.NoError(err)
+
+		// The notification identifies the changed row.
+		waitCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+		for {
+			notification, err := conn.WaitForNotification(waitCtx)
+			a.NoError(err)
+			table, rawKey, found := strings.Cut(notification.Payload, ":")
+			if table != "project" {
+				continue
+			}
+			a.True(found)
+			var key []string
+			a.NoError(json.Unmarshal([]byte(rawKey), &key))
+			a.Equal([]string{projectUID, "test-project"}, key)
+			break
+		}
+	})
+}
@@ -0,0 +1,141 @@
+package tests
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/tests/fake"
+	v1pb "github.com/bytebase/bytebase/proto/generated-go/v1"
+)
+
+func TestPasswordPolicy(t *testing.T) {
+	t.Parallel()
+	a := require.New(t)
+	ctx := context.Background()
+	ctl := &controller{}
+	dataDir := t.TempDir()
+	ctx, err := ctl.StartServerWithExternalPg(ctx, &config{
+		dataDir:            dataDir,
+		vcsProviderCreator: fake.NewGitLab,
+	})
+	a.NoError(err)
+	defer ctl.Close(ctx)
+
+	policy, err := json.Marshal(&api.SettingPasswordPolicyValue{
+		RotationDays:             1,
+		MaxAccountFailedAttempts: 3,
+		MaxIPFailedAttempts:      100,
+		LockoutMinutes:           1,
+	})
+	a.NoError(err)
+	_, err = ctl.settingServiceClient.SetSetting(ctx, &v1pb.SetSettingRequest{
+		Setting: &v1pb.Setting{
+			Name: fmt.Sprintf("settings/%s", api.SettingPasswordPolicy),
+			Value: &v1pb.Value{
+				Value: &v1pb.Value_StringValue{StringValue: string(policy)},
+			},
+		},
+	})
+	a.NoError(err)
+
+	metaDB, err := sql.Open("pgx", ctl.profile.PgURL)
+	a.NoError(err)
+	defer metaDB.Close()
+
+	t.Run("rotation", func(t *testing.T) {
+		a := require.New(t)
+		user, err := ctl.authServiceClient.CreateUser(ctx, &v1pb.CreateUserRequest{
+			User: &v1pb.User{
+				Email:    "rotation@example.com",
+				Password: "Bytebase1!",
+				Title:    "rotation",
+				UserType: v1pb.UserType_USER,
+			},
+		})
+		a.NoError(err)
+		resp, err := ctl.authServiceClient.Login(context.Background(), &v1pb.LoginRequest{
+			Email:    "rotation@example.com",
+			Password: "Bytebase1!",
+		})
+		a.NoError(err)
+		a.False(resp.RequireResetPassword)
+
+		// Expire the password.
+		_, err = metaDB.ExecContext(ctx, `
+			UPDATE password_history SET created_ts = created_ts - 2 * 86400
+			WHERE principal_id = (SELECT id FROM principal WHERE email = $1)`, "rotation@example.com")
+		a.NoError(err)
+		resp, err = ctl.authServiceClient.Login(context.Background(), &v1pb.LoginRequest{
+			Email:    "rotation@example.com",
+			Password: "Bytebase1!",
+		})
+		a.NoError(err)
+		a.True(resp.RequireResetPassword)
+		resetCtx := metadata.NewOutgoingContext(context.Background(), metadata.Pairs(
+			"Authorization",
+			fmt.Sprintf("Bearer %s", resp.Token),
+		))
+
+		// The token cannot be used for anything other than updating the password.
+		_, err = ctl.projectServiceClient.ListProjects(resetCtx, &v1pb.ListProjectsRequest{})
+		a.Equal(codes.PermissionDenied, status.Code(err))
+		_, err = ctl.authServiceClient.UpdateUser(resetCtx, &v1pb.UpdateUserRequest{
+			User:       &v1pb.User{Name: user.Name, Title: "renamed"},
+			UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"title"}},
+		})
+		a.Equal(codes.PermissionDenied, status.Code(err))
+		_, err = ctl.authServiceClient.UpdateUser(resetCtx, &v1pb.UpdateUserRequest{
+			User:       &v1pb.User{Name: user.Name, Password: "Bytebase2!"},
+			UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"password"}},
+		})
+		a.NoError(err)
+
+		resp, err = ctl.authServiceClient.Login(context.Background(), &v1pb.LoginRequest{
+			Email:    "rotation@example.com",
+			Password: "Bytebase2!",
+		})
+		a.NoError(err)
+		a.False(resp.RequireResetPassword)
+		_, err = ctl.projectServiceClient.ListProjects(metadata.NewOutgoingContext(context.Background(), metadata.Pairs(
+			"Authorization",
+			fmt.Sprintf("Bearer %s", resp.Token),
+		)), &v1pb.ListProjectsRequest{})
+		a.NoError(err)
+	})
+
+	t.Run("lockout", func(t *testing.T) {
+		a := require.New(t)
+		_, err := ctl.authServiceClient.CreateUser(ctx, &v1pb.CreateUserRequest{
+			User: &v1pb.User{
+				Email:    "lockout@example.com",
+				Password: "Bytebase1!",
+				Title:    "lockout",
+				UserType: v1pb.UserType_USER,
+			},
+		})
+		a.NoError(err)
+		for i := 0; i < 3; i++ {
+			_, err = ctl.authServiceClient.Login(context.Background(), &v1pb.LoginRequest{
+				Email:    "lockout@example.com",
+				Password: "wrong",
+			})
+			a.Equal(codes.Unauthenticated, status.Code(err))
+		}
+		// The account is locked even with the correct password.
+		_, err = ctl.authServiceClient.Login(context.Background(), &v1pb.LoginRequest{
+			Email:    "lockout@example.com",
+			Password: "Bytebase1!",
+		})
+		a.Equal(codes.ResourceExhausted, status.Code(err))
+	})
+}
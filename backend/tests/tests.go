@@ -191,6 +191,8 @@ type config struct {
 	vcsProviderCreator fake.VCSProviderCreator
 	readOnly           bool
 	skipOnboardingData bool
+	// ha runs the server in the high-availability mode, only supported with the external Postgres.
+	ha bool
 }
 
 var (
@@ -252,6 +254,7 @@ func (ctl *controller) StartServerWithExternalPg(ctx context.Context, config *co
 	pgURL := fmt.Sprintf("postgresql://%s@:%d/%s?host=%s", postgres.TestPgUser, externalPgPort, databaseName, common.GetPostgresSocketDir())
 	serverPort := getTestPort()
 	profile := getTestProfileWithExternalPg(config.dataDir, resourceDir, serverPort, postgres.TestPgUser, pgURL, config.skipOnboardingData)
+	profile.HA = config.ha
 	server, err := server.NewServer(ctx, profile)
 	if err != nil {
 		return nil, err
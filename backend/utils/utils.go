@@ -9,6 +9,7 @@ import (
 	"log/slog"
 	"reflect"
 	"regexp"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -24,7 +25,7 @@ import (
 	"github.com/bytebase/bytebase/backend/component/activity"
 	"github.com/bytebase/bytebase/backend/component/state"
 	api "github.com/bytebase/bytebase/backend/legacyapi"
-	"github.com/bytebase/bytebase/backend/plugin/app/relay"
+	"github.com/bytebase/bytebase/backend/plugin/app/externalapproval"
 	"github.com/bytebase/bytebase/backend/plugin/db"
 	"github.com/bytebase/bytebase/backend/plugin/vcs"
 	"github.com/bytebase/bytebase/backend/store"
@@ -414,6 +415,28 @@ func FindNextPendingStep(template *storepb.ApprovalTemplate, approvers []*storep
 	return template.Flow.Steps[len(approvers)]
 }
 
+// GetEscalationStep returns the step that the step escalates to after the timeout, or nil if the step doesn't escalate.
+// Any user with the escalation role in the project can review the escalation step.
+func GetEscalationStep(step *storepb.ApprovalStep) *storepb.ApprovalStep {
+	if step.EscalationRole == "" {
+		return nil
+	}
+	return &storepb.ApprovalStep{
+		Type: storepb.ApprovalStep_ANY,
+		Nodes: []*storepb.ApprovalNode{
+			{
+				Type:    storepb.ApprovalNode_ANY_IN_GROUP,
+				Payload: &storepb.ApprovalNode_Role{Role: step.EscalationRole},
+			},
+		},
+	}
+}
+
+// IsPendingStepEscalated returns true if the next pending step has timed out and is escalated.
+func IsPendingStepEscalated(approval *storepb.IssuePayloadApproval) bool {
+	return approval.PendingStepEscalated && int(approval.PendingStepIndex) == len(approval.Approvers)
+}
+
 // FindRejectedStep finds the rejected step in the approval flow.
 func FindRejectedStep(template *storepb.ApprovalTemplate, approvers []*storepb.IssuePayloadApproval_Approver) *storepb.ApprovalStep {
 	for i, approver := range approvers {
@@ -452,20 +475,23 @@ func CheckIssueApproved(issue *store.IssueMessage) (bool, error) {
 // HandleIncomingApprovalSteps handles incoming approval steps.
 // - Blocks approval steps if no user can approve the step.
 // - creates external approvals for external approval nodes.
-func HandleIncomingApprovalSteps(ctx context.Context, s *store.Store, relayClient *relay.Client, issue *store.IssueMessage, approval *storepb.IssuePayloadApproval) ([]*storepb.IssuePayloadApproval_Approver, []*store.ActivityMessage, error) {
+func HandleIncomingApprovalSteps(ctx context.Context, s *store.Store, newProvider externalapproval.Factory, issue *store.IssueMessage, approval *storepb.IssuePayloadApproval) ([]*storepb.IssuePayloadApproval_Approver, []*store.ActivityMessage, error) {
 	if len(approval.ApprovalTemplates) == 0 {
 		return nil, nil, nil
 	}
 
 	getActivityCreate := func(status storepb.ActivityIssueCommentCreatePayload_ApprovalEvent_Status, comment string) (*store.ActivityMessage, error) {
-		activityPayload, err := protojson.Marshal(&storepb.ActivityIssueCommentCreatePayload{
-			Event: &storepb.ActivityIssueCommentCreatePayload_ApprovalEvent_{
+		payload := &storepb.ActivityIssueCommentCreatePayload{
+			IssueName: issue.Title,
+		}
+		if status != storepb.ActivityIssueCommentCreatePayload_ApprovalEvent_STATUS_UNSPECIFIED {
+			payload.Event = &storepb.ActivityIssueCommentCreatePayload_ApprovalEvent_{
 				ApprovalEvent: &storepb.ActivityIssueCommentCreatePayload_ApprovalEvent{
 					Status: status,
 				},
-			},
-			IssueName: issue.Title,
-		})
+			}
+		}
+		activityPayload, err := protojson.Marshal(payload)
 		if err != nil {
 			return nil, err
 		}
@@ -494,7 +520,8 @@ func HandleIncomingApprovalSteps(ctx context.Context, s *store.Store, relayClien
 	}
 	node := step.Nodes[0]
 	if v, ok := node.GetPayload().(*storepb.ApprovalNode_ExternalNodeId); ok {
-		if err := handleApprovalNodeExternalNode(ctx, s, relayClient, issue, v.ExternalNodeId); err != nil {
+		changeRequest, err := handleApprovalNodeExternalNode(ctx, s, newProvider, issue, v.ExternalNodeId)
+		if err != nil {
 			approvers = append(approvers, &storepb.IssuePayloadApproval_Approver{
 				Status:      storepb.IssuePayloadApproval_Approver_REJECTED,
 				PrincipalId: api.SystemBotID,
@@ -504,32 +531,49 @@ func HandleIncomingApprovalSteps(ctx context.Context, s *store.Store, relayClien
 				return nil, nil, err
 			}
 			activities = append(activities, activity)
+		} else {
+			// Record the change request ID, e.g. the ticket ID, on the issue for the change management.
+			comment := fmt.Sprintf("Created external approval %s.", changeRequest.ID)
+			if changeRequest.URL != "" {
+				comment = fmt.Sprintf("Created external approval %s: %s", changeRequest.ID, changeRequest.URL)
+			}
+			activity, err := getActivityCreate(storepb.ActivityIssueCommentCreatePayload_ApprovalEvent_STATUS_UNSPECIFIED, comment)
+			if err != nil {
+				return nil, nil, err
+			}
+			activities = append(activities, activity)
 		}
 	}
 	return approvers, activities, nil
 }
 
-func handleApprovalNodeExternalNode(ctx context.Context, s *store.Store, relayClient *relay.Client, issue *store.IssueMessage, externalNodeID string) error {
-	getExternalApprovalByID := func(ctx context.Context, s *store.Store, externalApprovalID string) (*storepb.ExternalApprovalSetting_Node, error) {
-		setting, err := s.GetWorkspaceExternalApprovalSetting(ctx)
-		if err != nil {
-			return nil, errors.Wrapf(err, "failed to get workspace external approval setting")
-		}
-		for _, node := range setting.Nodes {
-			if node.Id == externalApprovalID {
-				return node, nil
-			}
+// GetExternalApprovalNode gets the external approval node by ID from the workspace external approval setting.
+func GetExternalApprovalNode(ctx context.Context, s *store.Store, externalNodeID string) (*storepb.ExternalApprovalSetting_Node, error) {
+	setting, err := s.GetWorkspaceExternalApprovalSetting(ctx)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get workspace external approval setting")
+	}
+	for _, node := range setting.Nodes {
+		if node.Id == externalNodeID {
+			return node, nil
 		}
-		return nil, nil
 	}
-	node, err := getExternalApprovalByID(ctx, s, externalNodeID)
+	return nil, nil
+}
+
+func handleApprovalNodeExternalNode(ctx context.Context, s *store.Store, newProvider externalapproval.Factory, issue *store.IssueMessage, externalNodeID string) (*externalapproval.ChangeRequest, error) {
+	node, err := GetExternalApprovalNode(ctx, s, externalNodeID)
 	if err != nil {
-		return errors.Wrapf(err, "failed to get external approval node %s", externalNodeID)
+		return nil, errors.Wrapf(err, "failed to get external approval node %s", externalNodeID)
 	}
 	if node == nil {
-		return errors.Errorf("external approval node %s not found", externalNodeID)
+		return nil, errors.Errorf("external approval node %s not found", externalNodeID)
+	}
+	provider, err := newProvider(node)
+	if err != nil {
+		return nil, err
 	}
-	id, err := relayClient.Create(node.Endpoint, &relay.CreatePayload{
+	changeRequest, err := provider.Create(ctx, &externalapproval.CreatePayload{
 		IssueID:     fmt.Sprintf("%d", issue.UID),
 		Title:       issue.Title,
 		Description: issue.Description,
@@ -539,14 +583,18 @@ func handleApprovalNodeExternalNode(ctx context.Context, s *store.Store, relayCl
 		Assignee:    issue.Assignee.Email,
 	})
 	if err != nil {
-		return errors.Wrapf(err, "failed to create external approval")
+		return nil, errors.Wrapf(err, "failed to create external approval")
+	}
+	if changeRequest.ID == "" {
+		return nil, errors.Errorf("external approval node %s returns an empty change request id", externalNodeID)
 	}
 	payload, err := json.Marshal(&api.ExternalApprovalPayloadRelay{
 		ExternalApprovalNodeID: node.Id,
-		ID:                     id,
+		ID:                     changeRequest.ID,
+		URL:                    changeRequest.URL,
 	})
 	if err != nil {
-		return errors.Wrapf(err, "failed to marshal external approval payload")
+		return nil, errors.Wrapf(err, "failed to marshal external approval payload")
 	}
 	if _, err := s.CreateExternalApprovalV2(ctx, &store.ExternalApprovalMessage{
 		IssueUID:     issue.UID,
@@ -555,9 +603,9 @@ func handleApprovalNodeExternalNode(ctx context.Context, s *store.Store, relayCl
 		Payload:      string(payload),
 		RequesterUID: api.SystemBotID,
 	}); err != nil {
-		return errors.Wrapf(err, "failed to create external approval")
+		return nil, errors.Wrapf(err, "failed to create external approval")
 	}
-	return nil
+	return changeRequest, nil
 }
 
 // UpdateProjectPolicyFromGrantIssue updates the project policy from grant issue.
@@ -615,6 +663,144 @@ func UpdateProjectPolicyFromGrantIssue(ctx context.Context, stores *store.Store,
 	return nil
 }
 
+// GetGrantIssueUID returns the UID of the grant request issue that the binding is granted by, or 0 if there is none.
+func GetGrantIssueUID(binding *store.PolicyBinding) int {
+	if binding.Condition == nil || !strings.HasPrefix(binding.Condition.Description, "#") {
+		return 0
+	}
+	issueUID, err := strconv.Atoi(strings.TrimPrefix(binding.Condition.Description, "#"))
+	if err != nil {
+		return 0
+	}
+	return issueUID
+}
+
+// UpdateProjectRoleMembers grants the role without condition in the project to the users in grants,
+// and revokes it from the users in revokes. It's used for the roles synced from the identity providers.
+// It returns the ids of the users newly added to the role, excluding the ones who already have it.
+func UpdateProjectRoleMembers(ctx context.Context, stores *store.Store, projectID, roleName string, grants, revokes []int) ([]int, error) {
+	project, err := stores.GetProjectV2(ctx, &store.FindProjectMessage{ResourceID: &projectID})
+	if err != nil {
+		return nil, err
+	}
+	if project == nil || project.Deleted {
+		slog.Warn("project of the synced role not found", slog.String("project", projectID))
+		return nil, nil
+	}
+	roleID, err := common.GetRoleID(roleName)
+	if err != nil {
+		return nil, err
+	}
+	role := api.Role(roleID)
+	policy, err := stores.GetProjectPolicy(ctx, &store.GetProjectPolicyMessage{UID: &project.UID})
+	if err != nil {
+		return nil, err
+	}
+
+	newPolicy := &store.IAMPolicyMessage{}
+	var binding *store.PolicyBinding
+	for _, b := range policy.Bindings {
+		if b.Role == role && (b.Condition == nil || b.Condition.Expression == "") && binding == nil {
+			binding = b
+			continue
+		}
+		newPolicy.Bindings = append(newPolicy.Bindings, b)
+	}
+
+	changed := false
+	var members []*store.UserMessage
+	var added []int
+	if binding != nil {
+		for _, m := range binding.Members {
+			if slices.Contains(revokes, m.ID) {
+				changed = true
+				continue
+			}
+			members = append(members, m)
+		}
+	}
+	for _, id := range grants {
+		if slices.ContainsFunc(members, func(m *store.UserMessage) bool { return m.ID == id }) {
+			continue
+		}
+		u, err := stores.GetUserByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if u == nil {
+			continue
+		}
+		members = append(members, u)
+		added = append(added, u.ID)
+		changed = true
+	}
+	if !changed {
+		return nil, nil
+	}
+	if len(members) == 0 && role == api.Owner {
+		slog.Warn("skip removing the last owner of the project", slog.String("project", projectID))
+		return nil, nil
+	}
+	if len(members) > 0 {
+		newBinding := &store.PolicyBinding{
+			Role:    role,
+			Members: members,
+		}
+		if binding != nil {
+			newBinding.Condition = binding.Condition
+		}
+		newPolicy.Bindings = append(newPolicy.Bindings, newBinding)
+	}
+	if _, err := stores.SetProjectIAMPolicy(ctx, newPolicy, api.SystemBotID, project.UID); err != nil {
+		return nil, err
+	}
+	return added, nil
+}
+
+// UpdateUserWorkspaceRole updates the workspace role of the user synced from the identity providers.
+// The last workspace owner is never demoted.
+func UpdateUserWorkspaceRole(ctx context.Context, stores *store.Store, user *store.UserMessage, role api.Role) error {
+	if user.Role == role {
+		return nil
+	}
+	if user.Role == api.Owner {
+		owner, endUser := api.Owner, api.EndUser
+		owners, err := stores.ListUsers(ctx, &store.FindUserMessage{Role: &owner, Type: &endUser})
+		if err != nil {
+			return err
+		}
+		if len(owners) <= 1 {
+			slog.Warn("skip demoting the last workspace owner", slog.String("user", user.Email))
+			return nil
+		}
+	}
+	if _, err := stores.UpdateUser(ctx, user.ID, &store.UpdateUserMessage{Role: &role}, api.SystemBotID); err != nil {
+		return err
+	}
+	user.Role = role
+	return nil
+}
+
+// MaxWorkspaceRole returns the higher workspace role of the two roles.
+func MaxWorkspaceRole(a, b api.Role) api.Role {
+	level := func(role api.Role) int {
+		switch role {
+		case api.Owner:
+			return 3
+		case api.DBA:
+			return 2
+		case api.Developer:
+			return 1
+		default:
+			return 0
+		}
+	}
+	if level(b) > level(a) {
+		return b
+	}
+	return a
+}
+
 // RenderStatement renders the given template statement with the given key-value map.
 func RenderStatement(templateStatement string, secrets map[string]string) string {
 	// Happy path for empty template statement.
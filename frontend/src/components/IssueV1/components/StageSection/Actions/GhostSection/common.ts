@@ -164,6 +164,10 @@ export const allowChangeTaskGhostFlags = (issue: ComposedIssue, task: Task) => {
 };
 
 export const allowGhostForDatabase = (database: ComposedDatabase) => {
+  // PostgreSQL uses the shadow table based online schema change instead of gh-ost.
+  if (database.instanceEntity.engine === Engine.POSTGRES) {
+    return true;
+  }
   return (
     database.instanceEntity.engine === Engine.MYSQL &&
     semverCompare(
@@ -58,6 +58,9 @@ export const useRollbackContext = () => {
       case Engine.ORACLE:
         // We don't have a check for oracle similar to the MySQL version check.
         break;
+      case Engine.POSTGRES:
+        // PostgreSQL rollback SQL is generated from the pre-image captured before execution.
+        break;
       default:
         return "NONE";
     }
@@ -70,6 +70,7 @@ const MFA_MODULE = "auth.mfa";
 const ACTIVATE_MODULE = "auth.activate";
 const PASSWORD_RESET_MODULE = "auth.password.reset";
 const PASSWORD_FORGOT_MODULE = "auth.password.forgot";
+const PASSWORD_EXPIRED_MODULE = "auth.password.expired";
 const SQL_EDITOR_HOME_MODULE = "sql-editor.home";
 
 const routes: Array<RouteRecordRaw> = [
@@ -115,6 +116,13 @@ const routes: Array<RouteRecordRaw> = [
         component: () => import("../views/auth/PasswordForgot.vue"),
         props: true,
       },
+      {
+        path: "password-expired",
+        name: PASSWORD_EXPIRED_MODULE,
+        meta: { title: () => `${t("auth.password-expired.title")}` },
+        component: () => import("../views/auth/PasswordExpired.vue"),
+        props: true,
+      },
     ],
   },
   {
@@ -1010,6 +1018,16 @@ router.beforeEach((to, from, next) => {
     return;
   }
 
+  // The password reset token is only valid to update the expired password.
+  if (to.name === PASSWORD_EXPIRED_MODULE) {
+    if (authStore.passwordResetToken) {
+      next();
+    } else {
+      next({ name: SIGNIN_MODULE, replace: true });
+    }
+    return;
+  }
+
   if (
     to.name === SIGNIN_MODULE ||
     to.name === SIGNUP_MODULE ||
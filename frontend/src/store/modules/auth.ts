@@ -15,11 +15,15 @@ import { convertUserToPrincipal, useUserStore } from ".";
 
 interface AuthState {
   currentUser: User;
+  // The token issued for the user whose password is expired, which can
+  // only be used to update the password.
+  passwordResetToken: string;
 }
 
 export const useAuthStore = defineStore("auth_v1", {
   state: (): AuthState => ({
     currentUser: unknownUser(),
+    passwordResetToken: "",
   }),
   actions: {
     isLoggedIn: () => {
@@ -27,11 +31,15 @@ export const useAuthStore = defineStore("auth_v1", {
     },
     async login(request: Partial<LoginRequest>) {
       const {
-        data: { mfaTempToken },
+        data: { mfaTempToken, token, requireResetPassword },
       } = await axios.post<LoginResponse>("/v1/auth/login", request);
       if (mfaTempToken) {
         return mfaTempToken;
       }
+      if (requireResetPassword) {
+        this.passwordResetToken = token;
+        return;
+      }
 
       const userId = getIntCookie("user");
       if (userId) {
@@ -56,6 +64,23 @@ export const useAuthStore = defineStore("auth_v1", {
         web: true,
       });
     },
+    async resetExpiredPassword(password: string) {
+      const token = this.passwordResetToken;
+      // The user ID is the subject of the JWT.
+      const payload = JSON.parse(
+        atob(token.split(".")[1].replace(/-/g, "+").replace(/_/g, "/"))
+      );
+      const name = `users/${payload.sub}`;
+      await axios.patch(
+        `/v1/${name}`,
+        { name, password },
+        {
+          params: { update_mask: "password" },
+          headers: { Authorization: `Bearer ${token}` },
+        }
+      );
+      this.passwordResetToken = "";
+    },
     async logout() {
       const unknown = unknownUser();
       try {
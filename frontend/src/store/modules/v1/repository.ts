@@ -175,5 +175,11 @@ const getUpdateMaskForRepository = (
   ) {
     updateMask.push("enable_sql_review_ci");
   }
+  if (
+    !isUndefined(update.enableNativeSqlReview) &&
+    !isEqual(origin.enableNativeSqlReview, update.enableNativeSqlReview)
+  ) {
+    updateMask.push("enable_native_sql_review");
+  }
   return updateMask;
 };
@@ -59,6 +59,8 @@ export function activityName(action: LogEntity_Action): string {
       return t("activity.type.project-member-create");
     case LogEntity_Action.ACTION_PROJECT_MEMBER_DELETE:
       return t("activity.type.project-member-delete");
+    case LogEntity_Action.ACTION_PROJECT_MEMBER_EXPIRING:
+      return t("activity.type.project-member-expiring");
     case LogEntity_Action.ACTION_PROJECT_DATABASE_RECOVERY_PITR_DONE:
       return t("activity.type.database-recovery-pitr-done");
   }
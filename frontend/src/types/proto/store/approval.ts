@@ -1,6 +1,7 @@
 /* eslint-disable */
 import Long from "long";
 import _m0 from "protobufjs/minimal";
+import { Duration } from "../google/protobuf/duration";
 
 export const protobufPackage = "bytebase.store";
 
@@ -17,6 +18,17 @@ export interface IssuePayloadApproval {
    */
   approvalFindingDone: boolean;
   approvalFindingError: string;
+  /**
+   * The index of the pending step that the following pending step fields belong to.
+   * It's the number of the approvers as one step is reviewed by one approver.
+   */
+  pendingStepIndex: number;
+  /** The unix timestamp when the pending step started to wait for review. */
+  pendingStepStartTs: Long;
+  /** The unix timestamp when the reviewers of the pending step were reminded last time. */
+  pendingStepRemindedTs: Long;
+  /** The pending step has timed out and is escalated to the escalation role of the step. */
+  pendingStepEscalated: boolean;
 }
 
 export interface IssuePayloadApproval_Approver {
@@ -24,6 +36,8 @@ export interface IssuePayloadApproval_Approver {
   status: IssuePayloadApproval_Approver_Status;
   /** The principal id of the approver. */
   principalId: number;
+  /** The principal id of the delegator if the approver reviews on behalf of the delegator. */
+  delegatorId: number;
 }
 
 export enum IssuePayloadApproval_Approver_Status {
@@ -85,6 +99,16 @@ export interface ApprovalFlow {
 export interface ApprovalStep {
   type: ApprovalStep_Type;
   nodes: ApprovalNode[];
+  /** The step times out if it's not reviewed within the timeout. The step never times out if it's not set. */
+  timeout: Duration | undefined;
+  /**
+   * The role that the step escalates to after the timeout, and the users with the role can review the step as well.
+   * The issue is rejected automatically after the timeout if it's not set.
+   * Format: roles/{role}
+   */
+  escalationRole: string;
+  /** The reviewers are reminded at the interval until the step is reviewed. No reminders are sent if it's not set. */
+  reminderInterval: Duration | undefined;
 }
 
 /**
@@ -239,7 +263,16 @@ export function approvalNode_GroupValueToJSON(object: ApprovalNode_GroupValue):
 }
 
 function createBaseIssuePayloadApproval(): IssuePayloadApproval {
-  return { approvalTemplates: [], approvers: [], approvalFindingDone: false, approvalFindingError: "" };
+  return {
+    approvalTemplates: [],
+    approvers: [],
+    approvalFindingDone: false,
+    approvalFindingError: "",
+    pendingStepIndex: 0,
+    pendingStepStartTs: Long.ZERO,
+    pendingStepRemindedTs: Long.ZERO,
+    pendingStepEscalated: false,
+  };
 }
 
 export const IssuePayloadApproval = {
@@ -256,6 +289,18 @@ export const IssuePayloadApproval = {
     if (message.approvalFindingError !== "") {
       writer.uint32(34).string(message.approvalFindingError);
     }
+    if (message.pendingStepIndex !== 0) {
+      writer.uint32(40).int32(message.pendingStepIndex);
+    }
+    if (!message.pendingStepStartTs.isZero()) {
+      writer.uint32(48).int64(message.pendingStepStartTs);
+    }
+    if (!message.pendingStepRemindedTs.isZero()) {
+      writer.uint32(56).int64(message.pendingStepRemindedTs);
+    }
+    if (message.pendingStepEscalated === true) {
+      writer.uint32(64).bool(message.pendingStepEscalated);
+    }
     return writer;
   },
 
@@ -294,6 +339,34 @@ export const IssuePayloadApproval = {
 
           message.approvalFindingError = reader.string();
           continue;
+        case 5:
+          if (tag !== 40) {
+            break;
+          }
+
+          message.pendingStepIndex = reader.int32();
+          continue;
+        case 6:
+          if (tag !== 48) {
+            break;
+          }
+
+          message.pendingStepStartTs = reader.int64() as Long;
+          continue;
+        case 7:
+          if (tag !== 56) {
+            break;
+          }
+
+          message.pendingStepRemindedTs = reader.int64() as Long;
+          continue;
+        case 8:
+          if (tag !== 64) {
+            break;
+          }
+
+          message.pendingStepEscalated = reader.bool();
+          continue;
       }
       if ((tag & 7) === 4 || tag === 0) {
         break;
@@ -313,6 +386,14 @@ export const IssuePayloadApproval = {
         : [],
       approvalFindingDone: isSet(object.approvalFindingDone) ? globalThis.Boolean(object.approvalFindingDone) : false,
       approvalFindingError: isSet(object.approvalFindingError) ? globalThis.String(object.approvalFindingError) : "",
+      pendingStepIndex: isSet(object.pendingStepIndex) ? globalThis.Number(object.pendingStepIndex) : 0,
+      pendingStepStartTs: isSet(object.pendingStepStartTs) ? Long.fromValue(object.pendingStepStartTs) : Long.ZERO,
+      pendingStepRemindedTs: isSet(object.pendingStepRemindedTs)
+        ? Long.fromValue(object.pendingStepRemindedTs)
+        : Long.ZERO,
+      pendingStepEscalated: isSet(object.pendingStepEscalated)
+        ? globalThis.Boolean(object.pendingStepEscalated)
+        : false,
     };
   },
 
@@ -330,6 +411,18 @@ export const IssuePayloadApproval = {
     if (message.approvalFindingError !== "") {
       obj.approvalFindingError = message.approvalFindingError;
     }
+    if (message.pendingStepIndex !== 0) {
+      obj.pendingStepIndex = Math.round(message.pendingStepIndex);
+    }
+    if (!message.pendingStepStartTs.isZero()) {
+      obj.pendingStepStartTs = (message.pendingStepStartTs || Long.ZERO).toString();
+    }
+    if (!message.pendingStepRemindedTs.isZero()) {
+      obj.pendingStepRemindedTs = (message.pendingStepRemindedTs || Long.ZERO).toString();
+    }
+    if (message.pendingStepEscalated === true) {
+      obj.pendingStepEscalated = message.pendingStepEscalated;
+    }
     return obj;
   },
 
@@ -342,12 +435,20 @@ export const IssuePayloadApproval = {
     message.approvers = object.approvers?.map((e) => IssuePayloadApproval_Approver.fromPartial(e)) || [];
     message.approvalFindingDone = object.approvalFindingDone ?? false;
     message.approvalFindingError = object.approvalFindingError ?? "";
+    message.pendingStepIndex = object.pendingStepIndex ?? 0;
+    message.pendingStepStartTs = (object.pendingStepStartTs !== undefined && object.pendingStepStartTs !== null)
+      ? Long.fromValue(object.pendingStepStartTs)
+      : Long.ZERO;
+    message.pendingStepRemindedTs = (object.pendingStepRemindedTs !== undefined && object.pendingStepRemindedTs !== null)
+      ? Long.fromValue(object.pendingStepRemindedTs)
+      : Long.ZERO;
+    message.pendingStepEscalated = object.pendingStepEscalated ?? false;
     return message;
   },
 };
 
 function createBaseIssuePayloadApproval_Approver(): IssuePayloadApproval_Approver {
-  return { status: 0, principalId: 0 };
+  return { status: 0, principalId: 0, delegatorId: 0 };
 }
 
 export const IssuePayloadApproval_Approver = {
@@ -358,6 +459,9 @@ export const IssuePayloadApproval_Approver = {
     if (message.principalId !== 0) {
       writer.uint32(16).int32(message.principalId);
     }
+    if (message.delegatorId !== 0) {
+      writer.uint32(24).int32(message.delegatorId);
+    }
     return writer;
   },
 
@@ -382,6 +486,13 @@ export const IssuePayloadApproval_Approver = {
 
           message.principalId = reader.int32();
           continue;
+        case 3:
+          if (tag !== 24) {
+            break;
+          }
+
+          message.delegatorId = reader.int32();
+          continue;
       }
       if ((tag & 7) === 4 || tag === 0) {
         break;
@@ -395,6 +506,7 @@ export const IssuePayloadApproval_Approver = {
     return {
       status: isSet(object.status) ? issuePayloadApproval_Approver_StatusFromJSON(object.status) : 0,
       principalId: isSet(object.principalId) ? globalThis.Number(object.principalId) : 0,
+      delegatorId: isSet(object.delegatorId) ? globalThis.Number(object.delegatorId) : 0,
     };
   },
 
@@ -406,6 +518,9 @@ export const IssuePayloadApproval_Approver = {
     if (message.principalId !== 0) {
       obj.principalId = Math.round(message.principalId);
     }
+    if (message.delegatorId !== 0) {
+      obj.delegatorId = Math.round(message.delegatorId);
+    }
     return obj;
   },
 
@@ -416,6 +531,7 @@ export const IssuePayloadApproval_Approver = {
     const message = createBaseIssuePayloadApproval_Approver();
     message.status = object.status ?? 0;
     message.principalId = object.principalId ?? 0;
+    message.delegatorId = object.delegatorId ?? 0;
     return message;
   },
 };
@@ -586,7 +702,7 @@ export const ApprovalFlow = {
 };
 
 function createBaseApprovalStep(): ApprovalStep {
-  return { type: 0, nodes: [] };
+  return { type: 0, nodes: [], timeout: undefined, escalationRole: "", reminderInterval: undefined };
 }
 
 export const ApprovalStep = {
@@ -597,6 +713,15 @@ export const ApprovalStep = {
     for (const v of message.nodes) {
       ApprovalNode.encode(v!, writer.uint32(18).fork()).ldelim();
     }
+    if (message.timeout !== undefined) {
+      Duration.encode(message.timeout, writer.uint32(26).fork()).ldelim();
+    }
+    if (message.escalationRole !== "") {
+      writer.uint32(34).string(message.escalationRole);
+    }
+    if (message.reminderInterval !== undefined) {
+      Duration.encode(message.reminderInterval, writer.uint32(42).fork()).ldelim();
+    }
     return writer;
   },
 
@@ -621,6 +746,27 @@ export const ApprovalStep = {
 
           message.nodes.push(ApprovalNode.decode(reader, reader.uint32()));
           continue;
+        case 3:
+          if (tag !== 26) {
+            break;
+          }
+
+          message.timeout = Duration.decode(reader, reader.uint32());
+          continue;
+        case 4:
+          if (tag !== 34) {
+            break;
+          }
+
+          message.escalationRole = reader.string();
+          continue;
+        case 5:
+          if (tag !== 42) {
+            break;
+          }
+
+          message.reminderInterval = Duration.decode(reader, reader.uint32());
+          continue;
       }
       if ((tag & 7) === 4 || tag === 0) {
         break;
@@ -634,6 +780,9 @@ export const ApprovalStep = {
     return {
       type: isSet(object.type) ? approvalStep_TypeFromJSON(object.type) : 0,
       nodes: globalThis.Array.isArray(object?.nodes) ? object.nodes.map((e: any) => ApprovalNode.fromJSON(e)) : [],
+      timeout: isSet(object.timeout) ? Duration.fromJSON(object.timeout) : undefined,
+      escalationRole: isSet(object.escalationRole) ? globalThis.String(object.escalationRole) : "",
+      reminderInterval: isSet(object.reminderInterval) ? Duration.fromJSON(object.reminderInterval) : undefined,
     };
   },
 
@@ -645,6 +794,15 @@ export const ApprovalStep = {
     if (message.nodes?.length) {
       obj.nodes = message.nodes.map((e) => ApprovalNode.toJSON(e));
     }
+    if (message.timeout !== undefined) {
+      obj.timeout = Duration.toJSON(message.timeout);
+    }
+    if (message.escalationRole !== "") {
+      obj.escalationRole = message.escalationRole;
+    }
+    if (message.reminderInterval !== undefined) {
+      obj.reminderInterval = Duration.toJSON(message.reminderInterval);
+    }
     return obj;
   },
 
@@ -655,6 +813,13 @@ export const ApprovalStep = {
     const message = createBaseApprovalStep();
     message.type = object.type ?? 0;
     message.nodes = object.nodes?.map((e) => ApprovalNode.fromPartial(e)) || [];
+    message.timeout = (object.timeout !== undefined && object.timeout !== null)
+      ? Duration.fromPartial(object.timeout)
+      : undefined;
+    message.escalationRole = object.escalationRole ?? "";
+    message.reminderInterval = (object.reminderInterval !== undefined && object.reminderInterval !== null)
+      ? Duration.fromPartial(object.reminderInterval)
+      : undefined;
     return message;
   },
 };
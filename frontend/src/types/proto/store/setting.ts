@@ -124,6 +124,62 @@ export interface ExternalApprovalSetting_Node {
   title: string;
   /** The external endpoint for the relay service, e.g. "http://hello:1234". */
   endpoint: string;
+  /** The type of the external approval provider. */
+  type: ExternalApprovalSetting_Node_Type;
+  /**
+   * The secret to sign the requests sent to the provider and verify the
+   * callbacks from the provider with HMAC-SHA256.
+   */
+  signingSecret: string;
+}
+
+export enum ExternalApprovalSetting_Node_Type {
+  /**
+   * The unspecified type is treated as RELAY for the nodes created before
+   * the types are introduced.
+   */
+  TYPE_UNSPECIFIED = 0,
+  /** The relay service which is polled for the approval status. */
+  RELAY = 1,
+  /**
+   * The generic HTTP provider for the ticketing systems, e.g. ServiceNow
+   * and Jira. A change request is created in the ticketing system, and the
+   * status is received via the signed callback or polling.
+   */
+  HTTP = 2,
+  UNRECOGNIZED = -1,
+}
+
+export function externalApprovalSetting_Node_TypeFromJSON(object: any): ExternalApprovalSetting_Node_Type {
+  switch (object) {
+    case 0:
+    case "TYPE_UNSPECIFIED":
+      return ExternalApprovalSetting_Node_Type.TYPE_UNSPECIFIED;
+    case 1:
+    case "RELAY":
+      return ExternalApprovalSetting_Node_Type.RELAY;
+    case 2:
+    case "HTTP":
+      return ExternalApprovalSetting_Node_Type.HTTP;
+    case -1:
+    case "UNRECOGNIZED":
+    default:
+      return ExternalApprovalSetting_Node_Type.UNRECOGNIZED;
+  }
+}
+
+export function externalApprovalSetting_Node_TypeToJSON(object: ExternalApprovalSetting_Node_Type): string {
+  switch (object) {
+    case ExternalApprovalSetting_Node_Type.TYPE_UNSPECIFIED:
+      return "TYPE_UNSPECIFIED";
+    case ExternalApprovalSetting_Node_Type.RELAY:
+      return "RELAY";
+    case ExternalApprovalSetting_Node_Type.HTTP:
+      return "HTTP";
+    case ExternalApprovalSetting_Node_Type.UNRECOGNIZED:
+    default:
+      return "UNRECOGNIZED";
+  }
 }
 
 export interface SMTPMailDeliverySetting {
@@ -936,7 +992,7 @@ export const ExternalApprovalSetting = {
 };
 
 function createBaseExternalApprovalSetting_Node(): ExternalApprovalSetting_Node {
-  return { id: "", title: "", endpoint: "" };
+  return { id: "", title: "", endpoint: "", type: 0, signingSecret: "" };
 }
 
 export const ExternalApprovalSetting_Node = {
@@ -950,6 +1006,12 @@ export const ExternalApprovalSetting_Node = {
     if (message.endpoint !== "") {
       writer.uint32(26).string(message.endpoint);
     }
+    if (message.type !== 0) {
+      writer.uint32(32).int32(message.type);
+    }
+    if (message.signingSecret !== "") {
+      writer.uint32(42).string(message.signingSecret);
+    }
     return writer;
   },
 
@@ -981,6 +1043,20 @@ export const ExternalApprovalSetting_Node = {
 
           message.endpoint = reader.string();
           continue;
+        case 4:
+          if (tag !== 32) {
+            break;
+          }
+
+          message.type = reader.int32() as any;
+          continue;
+        case 5:
+          if (tag !== 42) {
+            break;
+          }
+
+          message.signingSecret = reader.string();
+          continue;
       }
       if ((tag & 7) === 4 || tag === 0) {
         break;
@@ -995,6 +1071,8 @@ export const ExternalApprovalSetting_Node = {
       id: isSet(object.id) ? globalThis.String(object.id) : "",
       title: isSet(object.title) ? globalThis.String(object.title) : "",
       endpoint: isSet(object.endpoint) ? globalThis.String(object.endpoint) : "",
+      type: isSet(object.type) ? externalApprovalSetting_Node_TypeFromJSON(object.type) : 0,
+      signingSecret: isSet(object.signingSecret) ? globalThis.String(object.signingSecret) : "",
     };
   },
 
@@ -1009,6 +1087,12 @@ export const ExternalApprovalSetting_Node = {
     if (message.endpoint !== "") {
       obj.endpoint = message.endpoint;
     }
+    if (message.type !== 0) {
+      obj.type = externalApprovalSetting_Node_TypeToJSON(message.type);
+    }
+    if (message.signingSecret !== "") {
+      obj.signingSecret = message.signingSecret;
+    }
     return obj;
   },
 
@@ -1020,6 +1104,8 @@ export const ExternalApprovalSetting_Node = {
     message.id = object.id ?? "";
     message.title = object.title ?? "";
     message.endpoint = object.endpoint ?? "";
+    message.type = object.type ?? 0;
+    message.signingSecret = object.signingSecret ?? "";
     return message;
   },
 };
@@ -14,10 +14,32 @@ export interface MFAConfig {
   recoveryCodes: string[];
   /** The temp_recovery_codes are the temporary codes that will replace the recovery_codes in two phase commits. */
   tempRecoveryCodes: string[];
+  /** The webauthn_credentials are the registered WebAuthn credentials used as the second factor or for the passwordless login. */
+  webauthnCredentials: WebAuthnCredential[];
+}
+
+/** WebAuthnCredential is a WebAuthn credential registered by the user, e.g. a security key or a passkey. */
+export interface WebAuthnCredential {
+  /** The id is the credential ID generated by the authenticator. */
+  id: Uint8Array;
+  /** The public_key is the COSE encoded public key of the credential. */
+  publicKey: Uint8Array;
+  /** The sign_count is the signature counter of the authenticator used to detect cloned authenticators. */
+  signCount: number;
+  /** The name is the user-defined name of the credential. */
+  name: string;
+  /** The backup_eligible flag means the credential can be synced across devices, i.e. it is not bound to the hardware. */
+  backupEligible: boolean;
+  /** The created_ts is the unix timestamp when the credential is registered. */
+  createdTs: Long;
+  /** The attested flag means the attestation statement is signed by a certificate chained to the hardware key attestation roots. */
+  attested: boolean;
+  /** The aaguid is the authenticator model ID in the attested credential data. */
+  aaguid: Uint8Array;
 }
 
 function createBaseMFAConfig(): MFAConfig {
-  return { otpSecret: "", tempOtpSecret: "", recoveryCodes: [], tempRecoveryCodes: [] };
+  return { otpSecret: "", tempOtpSecret: "", recoveryCodes: [], tempRecoveryCodes: [], webauthnCredentials: [] };
 }
 
 export const MFAConfig = {
@@ -34,6 +56,9 @@ export const MFAConfig = {
     for (const v of message.tempRecoveryCodes) {
       writer.uint32(34).string(v!);
     }
+    for (const v of message.webauthnCredentials) {
+      WebAuthnCredential.encode(v!, writer.uint32(42).fork()).ldelim();
+    }
     return writer;
   },
 
@@ -72,6 +97,13 @@ export const MFAConfig = {
 
           message.tempRecoveryCodes.push(reader.string());
           continue;
+        case 5:
+          if (tag !== 42) {
+            break;
+          }
+
+          message.webauthnCredentials.push(WebAuthnCredential.decode(reader, reader.uint32()));
+          continue;
       }
       if ((tag & 7) === 4 || tag === 0) {
         break;
@@ -91,6 +123,9 @@ export const MFAConfig = {
       tempRecoveryCodes: globalThis.Array.isArray(object?.tempRecoveryCodes)
         ? object.tempRecoveryCodes.map((e: any) => globalThis.String(e))
         : [],
+      webauthnCredentials: globalThis.Array.isArray(object?.webauthnCredentials)
+        ? object.webauthnCredentials.map((e: any) => WebAuthnCredential.fromJSON(e))
+        : [],
     };
   },
 
@@ -108,6 +143,9 @@ export const MFAConfig = {
     if (message.tempRecoveryCodes?.length) {
       obj.tempRecoveryCodes = message.tempRecoveryCodes;
     }
+    if (message.webauthnCredentials?.length) {
+      obj.webauthnCredentials = message.webauthnCredentials.map((e) => WebAuthnCredential.toJSON(e));
+    }
     return obj;
   },
 
@@ -120,10 +158,211 @@ export const MFAConfig = {
     message.tempOtpSecret = object.tempOtpSecret ?? "";
     message.recoveryCodes = object.recoveryCodes?.map((e) => e) || [];
     message.tempRecoveryCodes = object.tempRecoveryCodes?.map((e) => e) || [];
+    message.webauthnCredentials = object.webauthnCredentials?.map((e) => WebAuthnCredential.fromPartial(e)) || [];
     return message;
   },
 };
 
+function createBaseWebAuthnCredential(): WebAuthnCredential {
+  return {
+    id: new Uint8Array(0),
+    publicKey: new Uint8Array(0),
+    signCount: 0,
+    name: "",
+    backupEligible: false,
+    createdTs: Long.ZERO,
+    attested: false,
+    aaguid: new Uint8Array(0),
+  };
+}
+
+export const WebAuthnCredential = {
+  encode(message: WebAuthnCredential, writer: _m0.Writer = _m0.Writer.create()): _m0.Writer {
+    if (message.id.length !== 0) {
+      writer.uint32(10).bytes(message.id);
+    }
+    if (message.publicKey.length !== 0) {
+      writer.uint32(18).bytes(message.publicKey);
+    }
+    if (message.signCount !== 0) {
+      writer.uint32(24).uint32(message.signCount);
+    }
+    if (message.name !== "") {
+      writer.uint32(34).string(message.name);
+    }
+    if (message.backupEligible === true) {
+      writer.uint32(40).bool(message.backupEligible);
+    }
+    if (!message.createdTs.isZero()) {
+      writer.uint32(48).int64(message.createdTs);
+    }
+    if (message.attested === true) {
+      writer.uint32(56).bool(message.attested);
+    }
+    if (message.aaguid.length !== 0) {
+      writer.uint32(66).bytes(message.aaguid);
+    }
+    return writer;
+  },
+
+  decode(input: _m0.Reader | Uint8Array, length?: number): WebAuthnCredential {
+    const reader = input instanceof _m0.Reader ? input : _m0.Reader.create(input);
+    let end = length === undefined ? reader.len : reader.pos + length;
+    const message = createBaseWebAuthnCredential();
+    while (reader.pos < end) {
+      const tag = reader.uint32();
+      switch (tag >>> 3) {
+        case 1:
+          if (tag !== 10) {
+            break;
+          }
+
+          message.id = reader.bytes();
+          continue;
+        case 2:
+          if (tag !== 18) {
+            break;
+          }
+
+          message.publicKey = reader.bytes();
+          continue;
+        case 3:
+          if (tag !== 24) {
+            break;
+          }
+
+          message.signCount = reader.uint32();
+          continue;
+        case 4:
+          if (tag !== 34) {
+            break;
+          }
+
+          message.name = reader.string();
+          continue;
+        case 5:
+          if (tag !== 40) {
+            break;
+          }
+
+          message.backupEligible = reader.bool();
+          continue;
+        case 6:
+          if (tag !== 48) {
+            break;
+          }
+
+          message.createdTs = reader.int64() as Long;
+          continue;
+        case 7:
+          if (tag !== 56) {
+            break;
+          }
+
+          message.attested = reader.bool();
+          continue;
+        case 8:
+          if (tag !== 66) {
+            break;
+          }
+
+          message.aaguid = reader.bytes();
+          continue;
+      }
+      if ((tag & 7) === 4 || tag === 0) {
+        break;
+      }
+      reader.skipType(tag & 7);
+    }
+    return message;
+  },
+
+  fromJSON(object: any): WebAuthnCredential {
+    return {
+      id: isSet(object.id) ? bytesFromBase64(object.id) : new Uint8Array(0),
+      publicKey: isSet(object.publicKey) ? bytesFromBase64(object.publicKey) : new Uint8Array(0),
+      signCount: isSet(object.signCount) ? globalThis.Number(object.signCount) : 0,
+      name: isSet(object.name) ? globalThis.String(object.name) : "",
+      backupEligible: isSet(object.backupEligible) ? globalThis.Boolean(object.backupEligible) : false,
+      createdTs: isSet(object.createdTs) ? Long.fromValue(object.createdTs) : Long.ZERO,
+      attested: isSet(object.attested) ? globalThis.Boolean(object.attested) : false,
+      aaguid: isSet(object.aaguid) ? bytesFromBase64(object.aaguid) : new Uint8Array(0),
+    };
+  },
+
+  toJSON(message: WebAuthnCredential): unknown {
+    const obj: any = {};
+    if (message.id.length !== 0) {
+      obj.id = base64FromBytes(message.id);
+    }
+    if (message.publicKey.length !== 0) {
+      obj.publicKey = base64FromBytes(message.publicKey);
+    }
+    if (message.signCount !== 0) {
+      obj.signCount = Math.round(message.signCount);
+    }
+    if (message.name !== "") {
+      obj.name = message.name;
+    }
+    if (message.backupEligible === true) {
+      obj.backupEligible = message.backupEligible;
+    }
+    if (!message.createdTs.isZero()) {
+      obj.createdTs = (message.createdTs || Long.ZERO).toString();
+    }
+    if (message.attested === true) {
+      obj.attested = message.attested;
+    }
+    if (message.aaguid.length !== 0) {
+      obj.aaguid = base64FromBytes(message.aaguid);
+    }
+    return obj;
+  },
+
+  create(base?: DeepPartial<WebAuthnCredential>): WebAuthnCredential {
+    return WebAuthnCredential.fromPartial(base ?? {});
+  },
+  fromPartial(object: DeepPartial<WebAuthnCredential>): WebAuthnCredential {
+    const message = createBaseWebAuthnCredential();
+    message.id = object.id ?? new Uint8Array(0);
+    message.publicKey = object.publicKey ?? new Uint8Array(0);
+    message.signCount = object.signCount ?? 0;
+    message.name = object.name ?? "";
+    message.backupEligible = object.backupEligible ?? false;
+    message.createdTs = (object.createdTs !== undefined && object.createdTs !== null)
+      ? Long.fromValue(object.createdTs)
+      : Long.ZERO;
+    message.attested = object.attested ?? false;
+    message.aaguid = object.aaguid ?? new Uint8Array(0);
+    return message;
+  },
+};
+
+function bytesFromBase64(b64: string): Uint8Array {
+  if (globalThis.Buffer) {
+    return Uint8Array.from(globalThis.Buffer.from(b64, "base64"));
+  } else {
+    const bin = globalThis.atob(b64);
+    const arr = new Uint8Array(bin.length);
+    for (let i = 0; i < bin.length; ++i) {
+      arr[i] = bin.charCodeAt(i);
+    }
+    return arr;
+  }
+}
+
+function base64FromBytes(arr: Uint8Array): string {
+  if (globalThis.Buffer) {
+    return globalThis.Buffer.from(arr).toString("base64");
+  } else {
+    const bin: string[] = [];
+    arr.forEach((byte) => {
+      bin.push(globalThis.String.fromCharCode(byte));
+    });
+    return globalThis.btoa(bin.join(""));
+  }
+}
+
 type Builtin = Date | Function | Uint8Array | string | number | boolean | undefined;
 
 export type DeepPartial<T> = T extends Builtin ? T
@@ -3,6 +3,7 @@ import Long from "long";
 import _m0 from "protobufjs/minimal";
 import { Empty } from "../google/protobuf/empty";
 import { FieldMask } from "../google/protobuf/field_mask";
+import { Timestamp } from "../google/protobuf/timestamp";
 import { State, stateFromJSON, stateToJSON } from "./common";
 
 export const protobufPackage = "bytebase.v1";
@@ -165,6 +166,16 @@ export interface UpdateUserRequest {
   regenerateTempMfaSecret: boolean;
   /** The regenerate_recovery_codes flag means to regenerate recovery codes for user. */
   regenerateRecoveryCodes: boolean;
+  /**
+   * The regenerate_webauthn_challenge flag means to generate the WebAuthn credential creation options for user.
+   * This is used for WebAuthn setup. The creation options will be returned in the response.
+   */
+  regenerateWebauthnChallenge: boolean;
+  /**
+   * The webauthn_registration is the JSON serialized PublicKeyCredential from navigator.credentials.create().
+   * The verified credential will be added to the user.
+   */
+  webauthnRegistration?: string | undefined;
 }
 
 export interface DeleteUserRequest {
@@ -206,7 +217,15 @@ export interface LoginRequest {
     | string
     | undefined;
   /** The mfa_temp_token is used to verify the user's identity by MFA. */
-  mfaTempToken?: string | undefined;
+  mfaTempToken?:
+    | string
+    | undefined;
+  /**
+   * The webauthn_assertion is the JSON serialized PublicKeyCredential from navigator.credentials.get().
+   * It's used to verify the user's identity by MFA with the mfa_temp_token, or to login without password.
+   * An empty assertion without email and mfa_temp_token requests the options for the passwordless login.
+   */
+  webauthnAssertion?: string | undefined;
 }
 
 export interface IdentityProviderContext {
@@ -224,6 +243,12 @@ export interface OIDCIdentityProviderContext {
 export interface LoginResponse {
   token: string;
   mfaTempToken?: string | undefined;
+  /** The require_reset_password flag means the password has expired by the workspace password policy, and the token can only be used to update the password. */
+  requireResetPassword: boolean;
+  /** The webauthn_request_options is the JSON serialized PublicKeyCredentialRequestOptions for navigator.credentials.get(). */
+  webauthnRequestOptions?: string | undefined;
+  /** The require_hardware_key_enrollment flag means the user is required to use a hardware security key by the workspace WebAuthn setting but has none, and the token can only be used to register a security key. */
+  requireHardwareKeyEnrollment: boolean;
 }
 
 export interface LogoutRequest {
@@ -254,6 +279,234 @@ export interface User {
    * Could be empty.
    */
   phone: string;
+  /**
+   * The locked flag means if the user is temporarily locked after too many failed login attempts.
+   * It can only be set to false for unlocking the user.
+   */
+  locked: boolean;
+  /**
+   * The webauthn_creation_options is the temporary JSON serialized PublicKeyCredentialCreationOptions
+   * for navigator.credentials.create() using in WebAuthn setup.
+   */
+  webauthnCreationOptions: string;
+  /** The webauthn_credentials are the registered WebAuthn credentials of the user. */
+  webauthnCredentials: WebAuthnCredential[];
+  /** The notification_setting is the notification preference of the user. */
+  notificationSetting: NotificationSetting | undefined;
+}
+
+export interface WebAuthnCredential {
+  /** The id is the base64url encoded credential ID. */
+  id: string;
+  /** The name is the display name of the credential, e.g. "YubiKey". */
+  name: string;
+  /** The hardware_bound flag means the credential is a security key vouched for by its vendor with the attestation. */
+  hardwareBound: boolean;
+}
+
+export interface AccessToken {
+  /**
+   * The name of the access token.
+   * Format: users/{user}/accessTokens/{access_token}
+   */
+  name: string;
+  /** The title is the display name of the access token, e.g. "CI bot". */
+  title: string;
+  /** The token is only returned when the access token is created or rotated. */
+  token: string;
+  /**
+   * The scopes limit the methods that the access token can call.
+   * Supported scopes: "all", "read", "sql.query" and "issue.create".
+   * A scope can be restricted to a project by the ":projects/{project}" suffix, e.g. "issue.create:projects/hr".
+   * The access token has no permissions if no scope is specified.
+   */
+  scopes: string[];
+  /** The expiration time of the access token. */
+  expireTime:
+    | Date
+    | undefined;
+  /** The last time that the access token is used. */
+  lastUsedTime:
+    | Date
+    | undefined;
+  /** The client IP address of the last usage. */
+  lastUsedIp: string;
+  createTime: Date | undefined;
+}
+
+export interface ListAccessTokensRequest {
+  /**
+   * The parent resource of the access tokens.
+   * Format: users/{user}
+   */
+  parent: string;
+}
+
+export interface ListAccessTokensResponse {
+  /** The access tokens of the user. The token values are not returned. */
+  accessTokens: AccessToken[];
+}
+
+export interface CreateAccessTokenRequest {
+  /**
+   * The parent resource of the access token.
+   * Format: users/{user}
+   */
+  parent: string;
+  /** The access token to create. */
+  accessToken: AccessToken | undefined;
+}
+
+export interface DeleteAccessTokenRequest {
+  /**
+   * The name of the access token to revoke.
+   * Format: users/{user}/accessTokens/{access_token}
+   */
+  name: string;
+}
+
+export interface RotateAccessTokenRequest {
+  /**
+   * The name of the access token to rotate.
+   * Format: users/{user}/accessTokens/{access_token}
+   */
+  name: string;
+  /** The expiration time of the new token. */
+  expireTime: Date | undefined;
+}
+
+export interface ApprovalDelegation {
+  /**
+   * The name of the approval delegation.
+   * Format: users/{user}/approvalDelegations/{approval_delegation}
+   */
+  name: string;
+  /**
+   * The delegate who reviews the approvals on behalf of the user during the delegation window.
+   * Format: users/hello@world.com
+   */
+  delegate: string;
+  /** The start time of the delegation window. */
+  startTime: Date | undefined;
+  /** The end time of the delegation window. */
+  endTime: Date | undefined;
+  /** The reason of the delegation, e.g. "Out of office". */
+  reason: string;
+  createTime: Date | undefined;
+}
+
+export interface ListApprovalDelegationsRequest {
+  /**
+   * The parent resource of the approval delegations.
+   * Format: users/{user}
+   */
+  parent: string;
+}
+
+export interface ListApprovalDelegationsResponse {
+  /** The approval delegations of the user. */
+  approvalDelegations: ApprovalDelegation[];
+}
+
+export interface CreateApprovalDelegationRequest {
+  /**
+   * The parent resource of the approval delegation.
+   * Format: users/{user}
+   */
+  parent: string;
+  /** The approval delegation to create. */
+  approvalDelegation: ApprovalDelegation | undefined;
+}
+
+export interface DeleteApprovalDelegationRequest {
+  /**
+   * The name of the approval delegation to delete.
+   * Format: users/{user}/approvalDelegations/{approval_delegation}
+   */
+  name: string;
+}
+
+export interface NotificationSetting {
+  /** The email_disabled flag means the user doesn't receive any email notification. */
+  emailDisabled: boolean;
+  /** The email_muted_events are the events that the user doesn't receive email notifications for. */
+  emailMutedEvents: NotificationSetting_Event[];
+}
+
+export enum NotificationSetting_Event {
+  EVENT_UNSPECIFIED = 0,
+  /** The issue is created and the user is the assignee or a subscriber. */
+  ISSUE_CREATED = 1,
+  /** The issue is waiting for the approval of the user. */
+  APPROVAL_REQUESTED = 2,
+  /** The issue created by the user is approved. */
+  ISSUE_APPROVED = 3,
+  /** The issue created by the user is rejected. */
+  ISSUE_REJECTED = 4,
+  /** A task of the issue created or assigned to the user fails. */
+  TASK_FAILED = 5,
+  /** The rollout of the issue created or subscribed by the user is done. */
+  ROLLOUT_DONE = 6,
+  /** The user is mentioned in a comment of the issue. */
+  COMMENT_MENTIONED = 7,
+  UNRECOGNIZED = -1,
+}
+
+export function notificationSetting_EventFromJSON(object: any): NotificationSetting_Event {
+  switch (object) {
+    case 0:
+    case "EVENT_UNSPECIFIED":
+      return NotificationSetting_Event.EVENT_UNSPECIFIED;
+    case 1:
+    case "ISSUE_CREATED":
+      return NotificationSetting_Event.ISSUE_CREATED;
+    case 2:
+    case "APPROVAL_REQUESTED":
+      return NotificationSetting_Event.APPROVAL_REQUESTED;
+    case 3:
+    case "ISSUE_APPROVED":
+      return NotificationSetting_Event.ISSUE_APPROVED;
+    case 4:
+    case "ISSUE_REJECTED":
+      return NotificationSetting_Event.ISSUE_REJECTED;
+    case 5:
+    case "TASK_FAILED":
+      return NotificationSetting_Event.TASK_FAILED;
+    case 6:
+    case "ROLLOUT_DONE":
+      return NotificationSetting_Event.ROLLOUT_DONE;
+    case 7:
+    case "COMMENT_MENTIONED":
+      return NotificationSetting_Event.COMMENT_MENTIONED;
+    case -1:
+    case "UNRECOGNIZED":
+    default:
+      return NotificationSetting_Event.UNRECOGNIZED;
+  }
+}
+
+export function notificationSetting_EventToJSON(object: NotificationSetting_Event): string {
+  switch (object) {
+    case NotificationSetting_Event.EVENT_UNSPECIFIED:
+      return "EVENT_UNSPECIFIED";
+    case NotificationSetting_Event.ISSUE_CREATED:
+      return "ISSUE_CREATED";
+    case NotificationSetting_Event.APPROVAL_REQUESTED:
+      return "APPROVAL_REQUESTED";
+    case NotificationSetting_Event.ISSUE_APPROVED:
+      return "ISSUE_APPROVED";
+    case NotificationSetting_Event.ISSUE_REJECTED:
+      return "ISSUE_REJECTED";
+    case NotificationSetting_Event.TASK_FAILED:
+      return "TASK_FAILED";
+    case NotificationSetting_Event.ROLLOUT_DONE:
+      return "ROLLOUT_DONE";
+    case NotificationSetting_Event.COMMENT_MENTIONED:
+      return "COMMENT_MENTIONED";
+    case NotificationSetting_Event.UNRECOGNIZED:
+    default:
+      return "UNRECOGNIZED";
+  }
 }
 
 function createBaseGetUserRequest(): GetUserRequest {
@@ -540,6 +793,8 @@ function createBaseUpdateUserRequest(): UpdateUserRequest {
     otpCode: undefined,
     regenerateTempMfaSecret: false,
     regenerateRecoveryCodes: false,
+    regenerateWebauthnChallenge: false,
+    webauthnRegistration: undefined,
   };
 }
 
@@ -560,6 +815,12 @@ export const UpdateUserRequest = {
     if (message.regenerateRecoveryCodes === true) {
       writer.uint32(40).bool(message.regenerateRecoveryCodes);
     }
+    if (message.regenerateWebauthnChallenge === true) {
+      writer.uint32(48).bool(message.regenerateWebauthnChallenge);
+    }
+    if (message.webauthnRegistration !== undefined) {
+      writer.uint32(58).string(message.webauthnRegistration);
+    }
     return writer;
   },
 
@@ -605,6 +866,20 @@ export const UpdateUserRequest = {
 
           message.regenerateRecoveryCodes = reader.bool();
           continue;
+        case 6:
+          if (tag !== 48) {
+            break;
+          }
+
+          message.regenerateWebauthnChallenge = reader.bool();
+          continue;
+        case 7:
+          if (tag !== 58) {
+            break;
+          }
+
+          message.webauthnRegistration = reader.string();
+          continue;
       }
       if ((tag & 7) === 4 || tag === 0) {
         break;
@@ -625,6 +900,12 @@ export const UpdateUserRequest = {
       regenerateRecoveryCodes: isSet(object.regenerateRecoveryCodes)
         ? globalThis.Boolean(object.regenerateRecoveryCodes)
         : false,
+      regenerateWebauthnChallenge: isSet(object.regenerateWebauthnChallenge)
+        ? globalThis.Boolean(object.regenerateWebauthnChallenge)
+        : false,
+      webauthnRegistration: isSet(object.webauthnRegistration)
+        ? globalThis.String(object.webauthnRegistration)
+        : undefined,
     };
   },
 
@@ -645,6 +926,12 @@ export const UpdateUserRequest = {
     if (message.regenerateRecoveryCodes === true) {
       obj.regenerateRecoveryCodes = message.regenerateRecoveryCodes;
     }
+    if (message.regenerateWebauthnChallenge === true) {
+      obj.regenerateWebauthnChallenge = message.regenerateWebauthnChallenge;
+    }
+    if (message.webauthnRegistration !== undefined) {
+      obj.webauthnRegistration = message.webauthnRegistration;
+    }
     return obj;
   },
 
@@ -658,6 +945,8 @@ export const UpdateUserRequest = {
     message.otpCode = object.otpCode ?? undefined;
     message.regenerateTempMfaSecret = object.regenerateTempMfaSecret ?? false;
     message.regenerateRecoveryCodes = object.regenerateRecoveryCodes ?? false;
+    message.regenerateWebauthnChallenge = object.regenerateWebauthnChallenge ?? false;
+    message.webauthnRegistration = object.webauthnRegistration ?? undefined;
     return message;
   },
 };
@@ -786,6 +1075,7 @@ function createBaseLoginRequest(): LoginRequest {
     otpCode: undefined,
     recoveryCode: undefined,
     mfaTempToken: undefined,
+    webauthnAssertion: undefined,
   };
 }
 
@@ -815,6 +1105,9 @@ export const LoginRequest = {
     if (message.mfaTempToken !== undefined) {
       writer.uint32(66).string(message.mfaTempToken);
     }
+    if (message.webauthnAssertion !== undefined) {
+      writer.uint32(74).string(message.webauthnAssertion);
+    }
     return writer;
   },
 
@@ -881,6 +1174,13 @@ export const LoginRequest = {
 
           message.mfaTempToken = reader.string();
           continue;
+        case 9:
+          if (tag !== 74) {
+            break;
+          }
+
+          message.webauthnAssertion = reader.string();
+          continue;
       }
       if ((tag & 7) === 4 || tag === 0) {
         break;
@@ -900,6 +1200,7 @@ export const LoginRequest = {
       otpCode: isSet(object.otpCode) ? globalThis.String(object.otpCode) : undefined,
       recoveryCode: isSet(object.recoveryCode) ? globalThis.String(object.recoveryCode) : undefined,
       mfaTempToken: isSet(object.mfaTempToken) ? globalThis.String(object.mfaTempToken) : undefined,
+      webauthnAssertion: isSet(object.webauthnAssertion) ? globalThis.String(object.webauthnAssertion) : undefined,
     };
   },
 
@@ -929,6 +1230,9 @@ export const LoginRequest = {
     if (message.mfaTempToken !== undefined) {
       obj.mfaTempToken = message.mfaTempToken;
     }
+    if (message.webauthnAssertion !== undefined) {
+      obj.webauthnAssertion = message.webauthnAssertion;
+    }
     return obj;
   },
 
@@ -947,6 +1251,7 @@ export const LoginRequest = {
     message.otpCode = object.otpCode ?? undefined;
     message.recoveryCode = object.recoveryCode ?? undefined;
     message.mfaTempToken = object.mfaTempToken ?? undefined;
+    message.webauthnAssertion = object.webauthnAssertion ?? undefined;
     return message;
   },
 };
@@ -1132,7 +1437,13 @@ export const OIDCIdentityProviderContext = {
 };
 
 function createBaseLoginResponse(): LoginResponse {
-  return { token: "", mfaTempToken: undefined };
+  return {
+    token: "",
+    mfaTempToken: undefined,
+    requireResetPassword: false,
+    webauthnRequestOptions: undefined,
+    requireHardwareKeyEnrollment: false,
+  };
 }
 
 export const LoginResponse = {
@@ -1143,6 +1454,15 @@ export const LoginResponse = {
     if (message.mfaTempToken !== undefined) {
       writer.uint32(18).string(message.mfaTempToken);
     }
+    if (message.requireResetPassword === true) {
+      writer.uint32(24).bool(message.requireResetPassword);
+    }
+    if (message.webauthnRequestOptions !== undefined) {
+      writer.uint32(34).string(message.webauthnRequestOptions);
+    }
+    if (message.requireHardwareKeyEnrollment === true) {
+      writer.uint32(40).bool(message.requireHardwareKeyEnrollment);
+    }
     return writer;
   },
 
@@ -1167,6 +1487,27 @@ export const LoginResponse = {
 
           message.mfaTempToken = reader.string();
           continue;
+        case 3:
+          if (tag !== 24) {
+            break;
+          }
+
+          message.requireResetPassword = reader.bool();
+          continue;
+        case 4:
+          if (tag !== 34) {
+            break;
+          }
+
+          message.webauthnRequestOptions = reader.string();
+          continue;
+        case 5:
+          if (tag !== 40) {
+            break;
+          }
+
+          message.requireHardwareKeyEnrollment = reader.bool();
+          continue;
       }
       if ((tag & 7) === 4 || tag === 0) {
         break;
@@ -1180,6 +1521,15 @@ export const LoginResponse = {
     return {
       token: isSet(object.token) ? globalThis.String(object.token) : "",
       mfaTempToken: isSet(object.mfaTempToken) ? globalThis.String(object.mfaTempToken) : undefined,
+      requireResetPassword: isSet(object.requireResetPassword)
+        ? globalThis.Boolean(object.requireResetPassword)
+        : false,
+      webauthnRequestOptions: isSet(object.webauthnRequestOptions)
+        ? globalThis.String(object.webauthnRequestOptions)
+        : undefined,
+      requireHardwareKeyEnrollment: isSet(object.requireHardwareKeyEnrollment)
+        ? globalThis.Boolean(object.requireHardwareKeyEnrollment)
+        : false,
     };
   },
 
@@ -1191,6 +1541,15 @@ export const LoginResponse = {
     if (message.mfaTempToken !== undefined) {
       obj.mfaTempToken = message.mfaTempToken;
     }
+    if (message.requireResetPassword === true) {
+      obj.requireResetPassword = message.requireResetPassword;
+    }
+    if (message.webauthnRequestOptions !== undefined) {
+      obj.webauthnRequestOptions = message.webauthnRequestOptions;
+    }
+    if (message.requireHardwareKeyEnrollment === true) {
+      obj.requireHardwareKeyEnrollment = message.requireHardwareKeyEnrollment;
+    }
     return obj;
   },
 
@@ -1201,6 +1560,9 @@ export const LoginResponse = {
     const message = createBaseLoginResponse();
     message.token = object.token ?? "";
     message.mfaTempToken = object.mfaTempToken ?? undefined;
+    message.requireResetPassword = object.requireResetPassword ?? false;
+    message.webauthnRequestOptions = object.webauthnRequestOptions ?? undefined;
+    message.requireHardwareKeyEnrollment = object.requireHardwareKeyEnrollment ?? false;
     return message;
   },
 };
@@ -1262,6 +1624,10 @@ function createBaseUser(): User {
     mfaSecret: "",
     recoveryCodes: [],
     phone: "",
+    locked: false,
+    webauthnCreationOptions: "",
+    webauthnCredentials: [],
+    notificationSetting: undefined,
   };
 }
 
@@ -1303,6 +1669,18 @@ export const User = {
     if (message.phone !== "") {
       writer.uint32(98).string(message.phone);
     }
+    if (message.locked === true) {
+      writer.uint32(104).bool(message.locked);
+    }
+    if (message.webauthnCreationOptions !== "") {
+      writer.uint32(114).string(message.webauthnCreationOptions);
+    }
+    for (const v of message.webauthnCredentials) {
+      WebAuthnCredential.encode(v!, writer.uint32(122).fork()).ldelim();
+    }
+    if (message.notificationSetting !== undefined) {
+      NotificationSetting.encode(message.notificationSetting, writer.uint32(130).fork()).ldelim();
+    }
     return writer;
   },
 
@@ -1397,6 +1775,34 @@ export const User = {
 
           message.phone = reader.string();
           continue;
+        case 13:
+          if (tag !== 104) {
+            break;
+          }
+
+          message.locked = reader.bool();
+          continue;
+        case 14:
+          if (tag !== 114) {
+            break;
+          }
+
+          message.webauthnCreationOptions = reader.string();
+          continue;
+        case 15:
+          if (tag !== 122) {
+            break;
+          }
+
+          message.webauthnCredentials.push(WebAuthnCredential.decode(reader, reader.uint32()));
+          continue;
+        case 16:
+          if (tag !== 130) {
+            break;
+          }
+
+          message.notificationSetting = NotificationSetting.decode(reader, reader.uint32());
+          continue;
       }
       if ((tag & 7) === 4 || tag === 0) {
         break;
@@ -1422,6 +1828,16 @@ export const User = {
         ? object.recoveryCodes.map((e: any) => globalThis.String(e))
         : [],
       phone: isSet(object.phone) ? globalThis.String(object.phone) : "",
+      locked: isSet(object.locked) ? globalThis.Boolean(object.locked) : false,
+      webauthnCreationOptions: isSet(object.webauthnCreationOptions)
+        ? globalThis.String(object.webauthnCreationOptions)
+        : "",
+      webauthnCredentials: globalThis.Array.isArray(object?.webauthnCredentials)
+        ? object.webauthnCredentials.map((e: any) => WebAuthnCredential.fromJSON(e))
+        : [],
+      notificationSetting: isSet(object.notificationSetting)
+        ? NotificationSetting.fromJSON(object.notificationSetting)
+        : undefined,
     };
   },
 
@@ -1463,6 +1879,18 @@ export const User = {
     if (message.phone !== "") {
       obj.phone = message.phone;
     }
+    if (message.locked === true) {
+      obj.locked = message.locked;
+    }
+    if (message.webauthnCreationOptions !== "") {
+      obj.webauthnCreationOptions = message.webauthnCreationOptions;
+    }
+    if (message.webauthnCredentials?.length) {
+      obj.webauthnCredentials = message.webauthnCredentials.map((e) => WebAuthnCredential.toJSON(e));
+    }
+    if (message.notificationSetting !== undefined) {
+      obj.notificationSetting = NotificationSetting.toJSON(message.notificationSetting);
+    }
     return obj;
   },
 
@@ -1483,112 +1911,1440 @@ export const User = {
     message.mfaSecret = object.mfaSecret ?? "";
     message.recoveryCodes = object.recoveryCodes?.map((e) => e) || [];
     message.phone = object.phone ?? "";
+    message.locked = object.locked ?? false;
+    message.webauthnCreationOptions = object.webauthnCreationOptions ?? "";
+    message.webauthnCredentials = object.webauthnCredentials?.map((e) => WebAuthnCredential.fromPartial(e)) || [];
+    message.notificationSetting = (object.notificationSetting !== undefined && object.notificationSetting !== null)
+      ? NotificationSetting.fromPartial(object.notificationSetting)
+      : undefined;
     return message;
   },
 };
 
-export type AuthServiceDefinition = typeof AuthServiceDefinition;
-export const AuthServiceDefinition = {
-  name: "AuthService",
-  fullName: "bytebase.v1.AuthService",
-  methods: {
-    getUser: {
-      name: "GetUser",
-      requestType: GetUserRequest,
-      requestStream: false,
-      responseType: User,
-      responseStream: false,
-      options: {
-        _unknownFields: {
-          8410: [new Uint8Array([4, 110, 97, 109, 101])],
-          578365826: [
-            new Uint8Array([
-              20,
-              18,
-              18,
-              47,
-              118,
-              49,
-              47,
-              123,
-              110,
-              97,
-              109,
-              101,
-              61,
-              117,
-              115,
-              101,
-              114,
-              115,
-              47,
-              42,
-              125,
-            ]),
-          ],
-        },
-      },
-    },
-    listUsers: {
-      name: "ListUsers",
-      requestType: ListUsersRequest,
-      requestStream: false,
-      responseType: ListUsersResponse,
-      responseStream: false,
-      options: {
-        _unknownFields: {
-          8410: [new Uint8Array([6, 112, 97, 114, 101, 110, 116])],
-          578365826: [new Uint8Array([11, 18, 9, 47, 118, 49, 47, 117, 115, 101, 114, 115])],
-        },
-      },
-    },
-    createUser: {
-      name: "CreateUser",
-      requestType: CreateUserRequest,
-      requestStream: false,
-      responseType: User,
-      responseStream: false,
-      options: {
-        _unknownFields: {
-          8410: [new Uint8Array([4, 117, 115, 101, 114])],
-          578365826: [new Uint8Array([17, 58, 4, 117, 115, 101, 114, 34, 9, 47, 118, 49, 47, 117, 115, 101, 114, 115])],
-        },
-      },
-    },
-    updateUser: {
-      name: "UpdateUser",
-      requestType: UpdateUserRequest,
-      requestStream: false,
-      responseType: User,
-      responseStream: false,
-      options: {
-        _unknownFields: {
-          8410: [new Uint8Array([16, 117, 115, 101, 114, 44, 117, 112, 100, 97, 116, 101, 95, 109, 97, 115, 107])],
-          578365826: [
-            new Uint8Array([
+function createBaseWebAuthnCredential(): WebAuthnCredential {
+  return { id: "", name: "", hardwareBound: false };
+}
+
+export const WebAuthnCredential = {
+  encode(message: WebAuthnCredential, writer: _m0.Writer = _m0.Writer.create()): _m0.Writer {
+    if (message.id !== "") {
+      writer.uint32(10).string(message.id);
+    }
+    if (message.name !== "") {
+      writer.uint32(18).string(message.name);
+    }
+    if (message.hardwareBound === true) {
+      writer.uint32(24).bool(message.hardwareBound);
+    }
+    return writer;
+  },
+
+  decode(input: _m0.Reader | Uint8Array, length?: number): WebAuthnCredential {
+    const reader = input instanceof _m0.Reader ? input : _m0.Reader.create(input);
+    let end = length === undefined ? reader.len : reader.pos + length;
+    const message = createBaseWebAuthnCredential();
+    while (reader.pos < end) {
+      const tag = reader.uint32();
+      switch (tag >>> 3) {
+        case 1:
+          if (tag !== 10) {
+            break;
+          }
+
+          message.id = reader.string();
+          continue;
+        case 2:
+          if (tag !== 18) {
+            break;
+          }
+
+          message.name = reader.string();
+          continue;
+        case 3:
+          if (tag !== 24) {
+            break;
+          }
+
+          message.hardwareBound = reader.bool();
+          continue;
+      }
+      if ((tag & 7) === 4 || tag === 0) {
+        break;
+      }
+      reader.skipType(tag & 7);
+    }
+    return message;
+  },
+
+  fromJSON(object: any): WebAuthnCredential {
+    return {
+      id: isSet(object.id) ? globalThis.String(object.id) : "",
+      name: isSet(object.name) ? globalThis.String(object.name) : "",
+      hardwareBound: isSet(object.hardwareBound) ? globalThis.Boolean(object.hardwareBound) : false,
+    };
+  },
+
+  toJSON(message: WebAuthnCredential): unknown {
+    const obj: any = {};
+    if (message.id !== "") {
+      obj.id = message.id;
+    }
+    if (message.name !== "") {
+      obj.name = message.name;
+    }
+    if (message.hardwareBound === true) {
+      obj.hardwareBound = message.hardwareBound;
+    }
+    return obj;
+  },
+
+  create(base?: DeepPartial<WebAuthnCredential>): WebAuthnCredential {
+    return WebAuthnCredential.fromPartial(base ?? {});
+  },
+  fromPartial(object: DeepPartial<WebAuthnCredential>): WebAuthnCredential {
+    const message = createBaseWebAuthnCredential();
+    message.id = object.id ?? "";
+    message.name = object.name ?? "";
+    message.hardwareBound = object.hardwareBound ?? false;
+    return message;
+  },
+};
+
+function createBaseAccessToken(): AccessToken {
+  return { name: "", title: "", token: "", scopes: [], expireTime: undefined, lastUsedTime: undefined, lastUsedIp: "", createTime: undefined };
+}
+
+export const AccessToken = {
+  encode(message: AccessToken, writer: _m0.Writer = _m0.Writer.create()): _m0.Writer {
+    if (message.name !== "") {
+      writer.uint32(10).string(message.name);
+    }
+    if (message.title !== "") {
+      writer.uint32(18).string(message.title);
+    }
+    if (message.token !== "") {
+      writer.uint32(26).string(message.token);
+    }
+    for (const v of message.scopes) {
+      writer.uint32(34).string(v!);
+    }
+    if (message.expireTime !== undefined) {
+      Timestamp.encode(toTimestamp(message.expireTime), writer.uint32(42).fork()).ldelim();
+    }
+    if (message.lastUsedTime !== undefined) {
+      Timestamp.encode(toTimestamp(message.lastUsedTime), writer.uint32(50).fork()).ldelim();
+    }
+    if (message.lastUsedIp !== "") {
+      writer.uint32(58).string(message.lastUsedIp);
+    }
+    if (message.createTime !== undefined) {
+      Timestamp.encode(toTimestamp(message.createTime), writer.uint32(66).fork()).ldelim();
+    }
+    return writer;
+  },
+
+  decode(input: _m0.Reader | Uint8Array, length?: number): AccessToken {
+    const reader = input instanceof _m0.Reader ? input : _m0.Reader.create(input);
+    let end = length === undefined ? reader.len : reader.pos + length;
+    const message = createBaseAccessToken();
+    while (reader.pos < end) {
+      const tag = reader.uint32();
+      switch (tag >>> 3) {
+        case 1:
+          if (tag !== 10) {
+            break;
+          }
+
+          message.name = reader.string();
+          continue;
+        case 2:
+          if (tag !== 18) {
+            break;
+          }
+
+          message.title = reader.string();
+          continue;
+        case 3:
+          if (tag !== 26) {
+            break;
+          }
+
+          message.token = reader.string();
+          continue;
+        case 4:
+          if (tag !== 34) {
+            break;
+          }
+
+          message.scopes.push(reader.string());
+          continue;
+        case 5:
+          if (tag !== 42) {
+            break;
+          }
+
+          message.expireTime = fromTimestamp(Timestamp.decode(reader, reader.uint32()));
+          continue;
+        case 6:
+          if (tag !== 50) {
+            break;
+          }
+
+          message.lastUsedTime = fromTimestamp(Timestamp.decode(reader, reader.uint32()));
+          continue;
+        case 7:
+          if (tag !== 58) {
+            break;
+          }
+
+          message.lastUsedIp = reader.string();
+          continue;
+        case 8:
+          if (tag !== 66) {
+            break;
+          }
+
+          message.createTime = fromTimestamp(Timestamp.decode(reader, reader.uint32()));
+          continue;
+      }
+      if ((tag & 7) === 4 || tag === 0) {
+        break;
+      }
+      reader.skipType(tag & 7);
+    }
+    return message;
+  },
+
+  fromJSON(object: any): AccessToken {
+    return {
+      name: isSet(object.name) ? globalThis.String(object.name) : "",
+      title: isSet(object.title) ? globalThis.String(object.title) : "",
+      token: isSet(object.token) ? globalThis.String(object.token) : "",
+      scopes: globalThis.Array.isArray(object?.scopes) ? object.scopes.map((e: any) => globalThis.String(e)) : [],
+      expireTime: isSet(object.expireTime) ? fromJsonTimestamp(object.expireTime) : undefined,
+      lastUsedTime: isSet(object.lastUsedTime) ? fromJsonTimestamp(object.lastUsedTime) : undefined,
+      lastUsedIp: isSet(object.lastUsedIp) ? globalThis.String(object.lastUsedIp) : "",
+      createTime: isSet(object.createTime) ? fromJsonTimestamp(object.createTime) : undefined,
+    };
+  },
+
+  toJSON(message: AccessToken): unknown {
+    const obj: any = {};
+    if (message.name !== "") {
+      obj.name = message.name;
+    }
+    if (message.title !== "") {
+      obj.title = message.title;
+    }
+    if (message.token !== "") {
+      obj.token = message.token;
+    }
+    if (message.scopes?.length) {
+      obj.scopes = message.scopes;
+    }
+    if (message.expireTime !== undefined) {
+      obj.expireTime = message.expireTime.toISOString();
+    }
+    if (message.lastUsedTime !== undefined) {
+      obj.lastUsedTime = message.lastUsedTime.toISOString();
+    }
+    if (message.lastUsedIp !== "") {
+      obj.lastUsedIp = message.lastUsedIp;
+    }
+    if (message.createTime !== undefined) {
+      obj.createTime = message.createTime.toISOString();
+    }
+    return obj;
+  },
+
+  create(base?: DeepPartial<AccessToken>): AccessToken {
+    return AccessToken.fromPartial(base ?? {});
+  },
+  fromPartial(object: DeepPartial<AccessToken>): AccessToken {
+    const message = createBaseAccessToken();
+    message.name = object.name ?? "";
+    message.title = object.title ?? "";
+    message.token = object.token ?? "";
+    message.scopes = object.scopes?.map((e) => e) || [];
+    message.expireTime = object.expireTime ?? undefined;
+    message.lastUsedTime = object.lastUsedTime ?? undefined;
+    message.lastUsedIp = object.lastUsedIp ?? "";
+    message.createTime = object.createTime ?? undefined;
+    return message;
+  },
+};
+
+function createBaseListAccessTokensRequest(): ListAccessTokensRequest {
+  return { parent: "" };
+}
+
+export const ListAccessTokensRequest = {
+  encode(message: ListAccessTokensRequest, writer: _m0.Writer = _m0.Writer.create()): _m0.Writer {
+    if (message.parent !== "") {
+      writer.uint32(10).string(message.parent);
+    }
+    return writer;
+  },
+
+  decode(input: _m0.Reader | Uint8Array, length?: number): ListAccessTokensRequest {
+    const reader = input instanceof _m0.Reader ? input : _m0.Reader.create(input);
+    let end = length === undefined ? reader.len : reader.pos + length;
+    const message = createBaseListAccessTokensRequest();
+    while (reader.pos < end) {
+      const tag = reader.uint32();
+      switch (tag >>> 3) {
+        case 1:
+          if (tag !== 10) {
+            break;
+          }
+
+          message.parent = reader.string();
+          continue;
+      }
+      if ((tag & 7) === 4 || tag === 0) {
+        break;
+      }
+      reader.skipType(tag & 7);
+    }
+    return message;
+  },
+
+  fromJSON(object: any): ListAccessTokensRequest {
+    return {
+      parent: isSet(object.parent) ? globalThis.String(object.parent) : "",
+    };
+  },
+
+  toJSON(message: ListAccessTokensRequest): unknown {
+    const obj: any = {};
+    if (message.parent !== "") {
+      obj.parent = message.parent;
+    }
+    return obj;
+  },
+
+  create(base?: DeepPartial<ListAccessTokensRequest>): ListAccessTokensRequest {
+    return ListAccessTokensRequest.fromPartial(base ?? {});
+  },
+  fromPartial(object: DeepPartial<ListAccessTokensRequest>): ListAccessTokensRequest {
+    const message = createBaseListAccessTokensRequest();
+    message.parent = object.parent ?? "";
+    return message;
+  },
+};
+
+function createBaseListAccessTokensResponse(): ListAccessTokensResponse {
+  return { accessTokens: [] };
+}
+
+export const ListAccessTokensResponse = {
+  encode(message: ListAccessTokensResponse, writer: _m0.Writer = _m0.Writer.create()): _m0.Writer {
+    for (const v of message.accessTokens) {
+      AccessToken.encode(v!, writer.uint32(10).fork()).ldelim();
+    }
+    return writer;
+  },
+
+  decode(input: _m0.Reader | Uint8Array, length?: number): ListAccessTokensResponse {
+    const reader = input instanceof _m0.Reader ? input : _m0.Reader.create(input);
+    let end = length === undefined ? reader.len : reader.pos + length;
+    const message = createBaseListAccessTokensResponse();
+    while (reader.pos < end) {
+      const tag = reader.uint32();
+      switch (tag >>> 3) {
+        case 1:
+          if (tag !== 10) {
+            break;
+          }
+
+          message.accessTokens.push(AccessToken.decode(reader, reader.uint32()));
+          continue;
+      }
+      if ((tag & 7) === 4 || tag === 0) {
+        break;
+      }
+      reader.skipType(tag & 7);
+    }
+    return message;
+  },
+
+  fromJSON(object: any): ListAccessTokensResponse {
+    return {
+      accessTokens: globalThis.Array.isArray(object?.accessTokens)
+        ? object.accessTokens.map((e: any) => AccessToken.fromJSON(e))
+        : [],
+    };
+  },
+
+  toJSON(message: ListAccessTokensResponse): unknown {
+    const obj: any = {};
+    if (message.accessTokens?.length) {
+      obj.accessTokens = message.accessTokens.map((e) => AccessToken.toJSON(e));
+    }
+    return obj;
+  },
+
+  create(base?: DeepPartial<ListAccessTokensResponse>): ListAccessTokensResponse {
+    return ListAccessTokensResponse.fromPartial(base ?? {});
+  },
+  fromPartial(object: DeepPartial<ListAccessTokensResponse>): ListAccessTokensResponse {
+    const message = createBaseListAccessTokensResponse();
+    message.accessTokens = object.accessTokens?.map((e) => AccessToken.fromPartial(e)) || [];
+    return message;
+  },
+};
+
+function createBaseCreateAccessTokenRequest(): CreateAccessTokenRequest {
+  return { parent: "", accessToken: undefined };
+}
+
+export const CreateAccessTokenRequest = {
+  encode(message: CreateAccessTokenRequest, writer: _m0.Writer = _m0.Writer.create()): _m0.Writer {
+    if (message.parent !== "") {
+      writer.uint32(10).string(message.parent);
+    }
+    if (message.accessToken !== undefined) {
+      AccessToken.encode(message.accessToken, writer.uint32(18).fork()).ldelim();
+    }
+    return writer;
+  },
+
+  decode(input: _m0.Reader | Uint8Array, length?: number): CreateAccessTokenRequest {
+    const reader = input instanceof _m0.Reader ? input : _m0.Reader.create(input);
+    let end = length === undefined ? reader.len : reader.pos + length;
+    const message = createBaseCreateAccessTokenRequest();
+    while (reader.pos < end) {
+      const tag = reader.uint32();
+      switch (tag >>> 3) {
+        case 1:
+          if (tag !== 10) {
+            break;
+          }
+
+          message.parent = reader.string();
+          continue;
+        case 2:
+          if (tag !== 18) {
+            break;
+          }
+
+          message.accessToken = AccessToken.decode(reader, reader.uint32());
+          continue;
+      }
+      if ((tag & 7) === 4 || tag === 0) {
+        break;
+      }
+      reader.skipType(tag & 7);
+    }
+    return message;
+  },
+
+  fromJSON(object: any): CreateAccessTokenRequest {
+    return {
+      parent: isSet(object.parent) ? globalThis.String(object.parent) : "",
+      accessToken: isSet(object.accessToken) ? AccessToken.fromJSON(object.accessToken) : undefined,
+    };
+  },
+
+  toJSON(message: CreateAccessTokenRequest): unknown {
+    const obj: any = {};
+    if (message.parent !== "") {
+      obj.parent = message.parent;
+    }
+    if (message.accessToken !== undefined) {
+      obj.accessToken = AccessToken.toJSON(message.accessToken);
+    }
+    return obj;
+  },
+
+  create(base?: DeepPartial<CreateAccessTokenRequest>): CreateAccessTokenRequest {
+    return CreateAccessTokenRequest.fromPartial(base ?? {});
+  },
+  fromPartial(object: DeepPartial<CreateAccessTokenRequest>): CreateAccessTokenRequest {
+    const message = createBaseCreateAccessTokenRequest();
+    message.parent = object.parent ?? "";
+    message.accessToken = (object.accessToken !== undefined && object.accessToken !== null)
+      ? AccessToken.fromPartial(object.accessToken)
+      : undefined;
+    return message;
+  },
+};
+
+function createBaseDeleteAccessTokenRequest(): DeleteAccessTokenRequest {
+  return { name: "" };
+}
+
+export const DeleteAccessTokenRequest = {
+  encode(message: DeleteAccessTokenRequest, writer: _m0.Writer = _m0.Writer.create()): _m0.Writer {
+    if (message.name !== "") {
+      writer.uint32(10).string(message.name);
+    }
+    return writer;
+  },
+
+  decode(input: _m0.Reader | Uint8Array, length?: number): DeleteAccessTokenRequest {
+    const reader = input instanceof _m0.Reader ? input : _m0.Reader.create(input);
+    let end = length === undefined ? reader.len : reader.pos + length;
+    const message = createBaseDeleteAccessTokenRequest();
+    while (reader.pos < end) {
+      const tag = reader.uint32();
+      switch (tag >>> 3) {
+        case 1:
+          if (tag !== 10) {
+            break;
+          }
+
+          message.name = reader.string();
+          continue;
+      }
+      if ((tag & 7) === 4 || tag === 0) {
+        break;
+      }
+      reader.skipType(tag & 7);
+    }
+    return message;
+  },
+
+  fromJSON(object: any): DeleteAccessTokenRequest {
+    return {
+      name: isSet(object.name) ? globalThis.String(object.name) : "",
+    };
+  },
+
+  toJSON(message: DeleteAccessTokenRequest): unknown {
+    const obj: any = {};
+    if (message.name !== "") {
+      obj.name = message.name;
+    }
+    return obj;
+  },
+
+  create(base?: DeepPartial<DeleteAccessTokenRequest>): DeleteAccessTokenRequest {
+    return DeleteAccessTokenRequest.fromPartial(base ?? {});
+  },
+  fromPartial(object: DeepPartial<DeleteAccessTokenRequest>): DeleteAccessTokenRequest {
+    const message = createBaseDeleteAccessTokenRequest();
+    message.name = object.name ?? "";
+    return message;
+  },
+};
+
+function createBaseRotateAccessTokenRequest(): RotateAccessTokenRequest {
+  return { name: "", expireTime: undefined };
+}
+
+export const RotateAccessTokenRequest = {
+  encode(message: RotateAccessTokenRequest, writer: _m0.Writer = _m0.Writer.create()): _m0.Writer {
+    if (message.name !== "") {
+      writer.uint32(10).string(message.name);
+    }
+    if (message.expireTime !== undefined) {
+      Timestamp.encode(toTimestamp(message.expireTime), writer.uint32(18).fork()).ldelim();
+    }
+    return writer;
+  },
+
+  decode(input: _m0.Reader | Uint8Array, length?: number): RotateAccessTokenRequest {
+    const reader = input instanceof _m0.Reader ? input : _m0.Reader.create(input);
+    let end = length === undefined ? reader.len : reader.pos + length;
+    const message = createBaseRotateAccessTokenRequest();
+    while (reader.pos < end) {
+      const tag = reader.uint32();
+      switch (tag >>> 3) {
+        case 1:
+          if (tag !== 10) {
+            break;
+          }
+
+          message.name = reader.string();
+          continue;
+        case 2:
+          if (tag !== 18) {
+            break;
+          }
+
+          message.expireTime = fromTimestamp(Timestamp.decode(reader, reader.uint32()));
+          continue;
+      }
+      if ((tag & 7) === 4 || tag === 0) {
+        break;
+      }
+      reader.skipType(tag & 7);
+    }
+    return message;
+  },
+
+  fromJSON(object: any): RotateAccessTokenRequest {
+    return {
+      name: isSet(object.name) ? globalThis.String(object.name) : "",
+      expireTime: isSet(object.expireTime) ? fromJsonTimestamp(object.expireTime) : undefined,
+    };
+  },
+
+  toJSON(message: RotateAccessTokenRequest): unknown {
+    const obj: any = {};
+    if (message.name !== "") {
+      obj.name = message.name;
+    }
+    if (message.expireTime !== undefined) {
+      obj.expireTime = message.expireTime.toISOString();
+    }
+    return obj;
+  },
+
+  create(base?: DeepPartial<RotateAccessTokenRequest>): RotateAccessTokenRequest {
+    return RotateAccessTokenRequest.fromPartial(base ?? {});
+  },
+  fromPartial(object: DeepPartial<RotateAccessTokenRequest>): RotateAccessTokenRequest {
+    const message = createBaseRotateAccessTokenRequest();
+    message.name = object.name ?? "";
+    message.expireTime = object.expireTime ?? undefined;
+    return message;
+  },
+};
+
+function createBaseApprovalDelegation(): ApprovalDelegation {
+  return { name: "", delegate: "", startTime: undefined, endTime: undefined, reason: "", createTime: undefined };
+}
+
+export const ApprovalDelegation = {
+  encode(message: ApprovalDelegation, writer: _m0.Writer = _m0.Writer.create()): _m0.Writer {
+    if (message.name !== "") {
+      writer.uint32(10).string(message.name);
+    }
+    if (message.delegate !== "") {
+      writer.uint32(18).string(message.delegate);
+    }
+    if (message.startTime !== undefined) {
+      Timestamp.encode(toTimestamp(message.startTime), writer.uint32(26).fork()).ldelim();
+    }
+    if (message.endTime !== undefined) {
+      Timestamp.encode(toTimestamp(message.endTime), writer.uint32(34).fork()).ldelim();
+    }
+    if (message.reason !== "") {
+      writer.uint32(42).string(message.reason);
+    }
+    if (message.createTime !== undefined) {
+      Timestamp.encode(toTimestamp(message.createTime), writer.uint32(50).fork()).ldelim();
+    }
+    return writer;
+  },
+
+  decode(input: _m0.Reader | Uint8Array, length?: number): ApprovalDelegation {
+    const reader = input instanceof _m0.Reader ? input : _m0.Reader.create(input);
+    let end = length === undefined ? reader.len : reader.pos + length;
+    const message = createBaseApprovalDelegation();
+    while (reader.pos < end) {
+      const tag = reader.uint32();
+      switch (tag >>> 3) {
+        case 1:
+          if (tag !== 10) {
+            break;
+          }
+
+          message.name = reader.string();
+          continue;
+        case 2:
+          if (tag !== 18) {
+            break;
+          }
+
+          message.delegate = reader.string();
+          continue;
+        case 3:
+          if (tag !== 26) {
+            break;
+          }
+
+          message.startTime = fromTimestamp(Timestamp.decode(reader, reader.uint32()));
+          continue;
+        case 4:
+          if (tag !== 34) {
+            break;
+          }
+
+          message.endTime = fromTimestamp(Timestamp.decode(reader, reader.uint32()));
+          continue;
+        case 5:
+          if (tag !== 42) {
+            break;
+          }
+
+          message.reason = reader.string();
+          continue;
+        case 6:
+          if (tag !== 50) {
+            break;
+          }
+
+          message.createTime = fromTimestamp(Timestamp.decode(reader, reader.uint32()));
+          continue;
+      }
+      if ((tag & 7) === 4 || tag === 0) {
+        break;
+      }
+      reader.skipType(tag & 7);
+    }
+    return message;
+  },
+
+  fromJSON(object: any): ApprovalDelegation {
+    return {
+      name: isSet(object.name) ? globalThis.String(object.name) : "",
+      delegate: isSet(object.delegate) ? globalThis.String(object.delegate) : "",
+      startTime: isSet(object.startTime) ? fromJsonTimestamp(object.startTime) : undefined,
+      endTime: isSet(object.endTime) ? fromJsonTimestamp(object.endTime) : undefined,
+      reason: isSet(object.reason) ? globalThis.String(object.reason) : "",
+      createTime: isSet(object.createTime) ? fromJsonTimestamp(object.createTime) : undefined,
+    };
+  },
+
+  toJSON(message: ApprovalDelegation): unknown {
+    const obj: any = {};
+    if (message.name !== "") {
+      obj.name = message.name;
+    }
+    if (message.delegate !== "") {
+      obj.delegate = message.delegate;
+    }
+    if (message.startTime !== undefined) {
+      obj.startTime = message.startTime.toISOString();
+    }
+    if (message.endTime !== undefined) {
+      obj.endTime = message.endTime.toISOString();
+    }
+    if (message.reason !== "") {
+      obj.reason = message.reason;
+    }
+    if (message.createTime !== undefined) {
+      obj.createTime = message.createTime.toISOString();
+    }
+    return obj;
+  },
+
+  create(base?: DeepPartial<ApprovalDelegation>): ApprovalDelegation {
+    return ApprovalDelegation.fromPartial(base ?? {});
+  },
+  fromPartial(object: DeepPartial<ApprovalDelegation>): ApprovalDelegation {
+    const message = createBaseApprovalDelegation();
+    message.name = object.name ?? "";
+    message.delegate = object.delegate ?? "";
+    message.startTime = object.startTime ?? undefined;
+    message.endTime = object.endTime ?? undefined;
+    message.reason = object.reason ?? "";
+    message.createTime = object.createTime ?? undefined;
+    return message;
+  },
+};
+
+function createBaseListApprovalDelegationsRequest(): ListApprovalDelegationsRequest {
+  return { parent: "" };
+}
+
+export const ListApprovalDelegationsRequest = {
+  encode(message: ListApprovalDelegationsRequest, writer: _m0.Writer = _m0.Writer.create()): _m0.Writer {
+    if (message.parent !== "") {
+      writer.uint32(10).string(message.parent);
+    }
+    return writer;
+  },
+
+  decode(input: _m0.Reader | Uint8Array, length?: number): ListApprovalDelegationsRequest {
+    const reader = input instanceof _m0.Reader ? input : _m0.Reader.create(input);
+    let end = length === undefined ? reader.len : reader.pos + length;
+    const message = createBaseListApprovalDelegationsRequest();
+    while (reader.pos < end) {
+      const tag = reader.uint32();
+      switch (tag >>> 3) {
+        case 1:
+          if (tag !== 10) {
+            break;
+          }
+
+          message.parent = reader.string();
+          continue;
+      }
+      if ((tag & 7) === 4 || tag === 0) {
+        break;
+      }
+      reader.skipType(tag & 7);
+    }
+    return message;
+  },
+
+  fromJSON(object: any): ListApprovalDelegationsRequest {
+    return {
+      parent: isSet(object.parent) ? globalThis.String(object.parent) : "",
+    };
+  },
+
+  toJSON(message: ListApprovalDelegationsRequest): unknown {
+    const obj: any = {};
+    if (message.parent !== "") {
+      obj.parent = message.parent;
+    }
+    return obj;
+  },
+
+  create(base?: DeepPartial<ListApprovalDelegationsRequest>): ListApprovalDelegationsRequest {
+    return ListApprovalDelegationsRequest.fromPartial(base ?? {});
+  },
+  fromPartial(object: DeepPartial<ListApprovalDelegationsRequest>): ListApprovalDelegationsRequest {
+    const message = createBaseListApprovalDelegationsRequest();
+    message.parent = object.parent ?? "";
+    return message;
+  },
+};
+
+function createBaseListApprovalDelegationsResponse(): ListApprovalDelegationsResponse {
+  return { approvalDelegations: [] };
+}
+
+export const ListApprovalDelegationsResponse = {
+  encode(message: ListApprovalDelegationsResponse, writer: _m0.Writer = _m0.Writer.create()): _m0.Writer {
+    for (const v of message.approvalDelegations) {
+      ApprovalDelegation.encode(v!, writer.uint32(10).fork()).ldelim();
+    }
+    return writer;
+  },
+
+  decode(input: _m0.Reader | Uint8Array, length?: number): ListApprovalDelegationsResponse {
+    const reader = input instanceof _m0.Reader ? input : _m0.Reader.create(input);
+    let end = length === undefined ? reader.len : reader.pos + length;
+    const message = createBaseListApprovalDelegationsResponse();
+    while (reader.pos < end) {
+      const tag = reader.uint32();
+      switch (tag >>> 3) {
+        case 1:
+          if (tag !== 10) {
+            break;
+          }
+
+          message.approvalDelegations.push(ApprovalDelegation.decode(reader, reader.uint32()));
+          continue;
+      }
+      if ((tag & 7) === 4 || tag === 0) {
+        break;
+      }
+      reader.skipType(tag & 7);
+    }
+    return message;
+  },
+
+  fromJSON(object: any): ListApprovalDelegationsResponse {
+    return {
+      approvalDelegations: globalThis.Array.isArray(object?.approvalDelegations)
+        ? object.approvalDelegations.map((e: any) => ApprovalDelegation.fromJSON(e))
+        : [],
+    };
+  },
+
+  toJSON(message: ListApprovalDelegationsResponse): unknown {
+    const obj: any = {};
+    if (message.approvalDelegations?.length) {
+      obj.approvalDelegations = message.approvalDelegations.map((e) => ApprovalDelegation.toJSON(e));
+    }
+    return obj;
+  },
+
+  create(base?: DeepPartial<ListApprovalDelegationsResponse>): ListApprovalDelegationsResponse {
+    return ListApprovalDelegationsResponse.fromPartial(base ?? {});
+  },
+  fromPartial(object: DeepPartial<ListApprovalDelegationsResponse>): ListApprovalDelegationsResponse {
+    const message = createBaseListApprovalDelegationsResponse();
+    message.approvalDelegations = object.approvalDelegations?.map((e) => ApprovalDelegation.fromPartial(e)) || [];
+    return message;
+  },
+};
+
+function createBaseCreateApprovalDelegationRequest(): CreateApprovalDelegationRequest {
+  return { parent: "", approvalDelegation: undefined };
+}
+
+export const CreateApprovalDelegationRequest = {
+  encode(message: CreateApprovalDelegationRequest, writer: _m0.Writer = _m0.Writer.create()): _m0.Writer {
+    if (message.parent !== "") {
+      writer.uint32(10).string(message.parent);
+    }
+    if (message.approvalDelegation !== undefined) {
+      ApprovalDelegation.encode(message.approvalDelegation, writer.uint32(18).fork()).ldelim();
+    }
+    return writer;
+  },
+
+  decode(input: _m0.Reader | Uint8Array, length?: number): CreateApprovalDelegationRequest {
+    const reader = input instanceof _m0.Reader ? input : _m0.Reader.create(input);
+    let end = length === undefined ? reader.len : reader.pos + length;
+    const message = createBaseCreateApprovalDelegationRequest();
+    while (reader.pos < end) {
+      const tag = reader.uint32();
+      switch (tag >>> 3) {
+        case 1:
+          if (tag !== 10) {
+            break;
+          }
+
+          message.parent = reader.string();
+          continue;
+        case 2:
+          if (tag !== 18) {
+            break;
+          }
+
+          message.approvalDelegation = ApprovalDelegation.decode(reader, reader.uint32());
+          continue;
+      }
+      if ((tag & 7) === 4 || tag === 0) {
+        break;
+      }
+      reader.skipType(tag & 7);
+    }
+    return message;
+  },
+
+  fromJSON(object: any): CreateApprovalDelegationRequest {
+    return {
+      parent: isSet(object.parent) ? globalThis.String(object.parent) : "",
+      approvalDelegation: isSet(object.approvalDelegation) ? ApprovalDelegation.fromJSON(object.approvalDelegation) : undefined,
+    };
+  },
+
+  toJSON(message: CreateApprovalDelegationRequest): unknown {
+    const obj: any = {};
+    if (message.parent !== "") {
+      obj.parent = message.parent;
+    }
+    if (message.approvalDelegation !== undefined) {
+      obj.approvalDelegation = ApprovalDelegation.toJSON(message.approvalDelegation);
+    }
+    return obj;
+  },
+
+  create(base?: DeepPartial<CreateApprovalDelegationRequest>): CreateApprovalDelegationRequest {
+    return CreateApprovalDelegationRequest.fromPartial(base ?? {});
+  },
+  fromPartial(object: DeepPartial<CreateApprovalDelegationRequest>): CreateApprovalDelegationRequest {
+    const message = createBaseCreateApprovalDelegationRequest();
+    message.parent = object.parent ?? "";
+    message.approvalDelegation = (object.approvalDelegation !== undefined && object.approvalDelegation !== null)
+      ? ApprovalDelegation.fromPartial(object.approvalDelegation)
+      : undefined;
+    return message;
+  },
+};
+
+function createBaseDeleteApprovalDelegationRequest(): DeleteApprovalDelegationRequest {
+  return { name: "" };
+}
+
+export const DeleteApprovalDelegationRequest = {
+  encode(message: DeleteApprovalDelegationRequest, writer: _m0.Writer = _m0.Writer.create()): _m0.Writer {
+    if (message.name !== "") {
+      writer.uint32(10).string(message.name);
+    }
+    return writer;
+  },
+
+  decode(input: _m0.Reader | Uint8Array, length?: number): DeleteApprovalDelegationRequest {
+    const reader = input instanceof _m0.Reader ? input : _m0.Reader.create(input);
+    let end = length === undefined ? reader.len : reader.pos + length;
+    const message = createBaseDeleteApprovalDelegationRequest();
+    while (reader.pos < end) {
+      const tag = reader.uint32();
+      switch (tag >>> 3) {
+        case 1:
+          if (tag !== 10) {
+            break;
+          }
+
+          message.name = reader.string();
+          continue;
+      }
+      if ((tag & 7) === 4 || tag === 0) {
+        break;
+      }
+      reader.skipType(tag & 7);
+    }
+    return message;
+  },
+
+  fromJSON(object: any): DeleteApprovalDelegationRequest {
+    return {
+      name: isSet(object.name) ? globalThis.String(object.name) : "",
+    };
+  },
+
+  toJSON(message: DeleteApprovalDelegationRequest): unknown {
+    const obj: any = {};
+    if (message.name !== "") {
+      obj.name = message.name;
+    }
+    return obj;
+  },
+
+  create(base?: DeepPartial<DeleteApprovalDelegationRequest>): DeleteApprovalDelegationRequest {
+    return DeleteApprovalDelegationRequest.fromPartial(base ?? {});
+  },
+  fromPartial(object: DeepPartial<DeleteApprovalDelegationRequest>): DeleteApprovalDelegationRequest {
+    const message = createBaseDeleteApprovalDelegationRequest();
+    message.name = object.name ?? "";
+    return message;
+  },
+};
+
+function createBaseNotificationSetting(): NotificationSetting {
+  return { emailDisabled: false, emailMutedEvents: [] };
+}
+
+export const NotificationSetting = {
+  encode(message: NotificationSetting, writer: _m0.Writer = _m0.Writer.create()): _m0.Writer {
+    if (message.emailDisabled === true) {
+      writer.uint32(8).bool(message.emailDisabled);
+    }
+    writer.uint32(18).fork();
+    for (const v of message.emailMutedEvents) {
+      writer.int32(v);
+    }
+    writer.ldelim();
+    return writer;
+  },
+
+  decode(input: _m0.Reader | Uint8Array, length?: number): NotificationSetting {
+    const reader = input instanceof _m0.Reader ? input : _m0.Reader.create(input);
+    let end = length === undefined ? reader.len : reader.pos + length;
+    const message = createBaseNotificationSetting();
+    while (reader.pos < end) {
+      const tag = reader.uint32();
+      switch (tag >>> 3) {
+        case 1:
+          if (tag !== 8) {
+            break;
+          }
+
+          message.emailDisabled = reader.bool();
+          continue;
+        case 2:
+          if (tag === 16) {
+            message.emailMutedEvents.push(reader.int32() as any);
+
+            continue;
+          }
+
+          if (tag === 18) {
+            const end2 = reader.uint32() + reader.pos;
+            while (reader.pos < end2) {
+              message.emailMutedEvents.push(reader.int32() as any);
+            }
+
+            continue;
+          }
+
+          break;
+      }
+      if ((tag & 7) === 4 || tag === 0) {
+        break;
+      }
+      reader.skipType(tag & 7);
+    }
+    return message;
+  },
+
+  fromJSON(object: any): NotificationSetting {
+    return {
+      emailDisabled: isSet(object.emailDisabled) ? globalThis.Boolean(object.emailDisabled) : false,
+      emailMutedEvents: globalThis.Array.isArray(object?.emailMutedEvents)
+        ? object.emailMutedEvents.map((e: any) => notificationSetting_EventFromJSON(e))
+        : [],
+    };
+  },
+
+  toJSON(message: NotificationSetting): unknown {
+    const obj: any = {};
+    if (message.emailDisabled === true) {
+      obj.emailDisabled = message.emailDisabled;
+    }
+    if (message.emailMutedEvents?.length) {
+      obj.emailMutedEvents = message.emailMutedEvents.map((e) => notificationSetting_EventToJSON(e));
+    }
+    return obj;
+  },
+
+  create(base?: DeepPartial<NotificationSetting>): NotificationSetting {
+    return NotificationSetting.fromPartial(base ?? {});
+  },
+  fromPartial(object: DeepPartial<NotificationSetting>): NotificationSetting {
+    const message = createBaseNotificationSetting();
+    message.emailDisabled = object.emailDisabled ?? false;
+    message.emailMutedEvents = object.emailMutedEvents?.map((e) => e) || [];
+    return message;
+  },
+};
+
+export type AuthServiceDefinition = typeof AuthServiceDefinition;
+export const AuthServiceDefinition = {
+  name: "AuthService",
+  fullName: "bytebase.v1.AuthService",
+  methods: {
+    getUser: {
+      name: "GetUser",
+      requestType: GetUserRequest,
+      requestStream: false,
+      responseType: User,
+      responseStream: false,
+      options: {
+        _unknownFields: {
+          8410: [new Uint8Array([4, 110, 97, 109, 101])],
+          578365826: [
+            new Uint8Array([
+              20,
+              18,
+              18,
+              47,
+              118,
+              49,
+              47,
+              123,
+              110,
+              97,
+              109,
+              101,
+              61,
+              117,
+              115,
+              101,
+              114,
+              115,
+              47,
+              42,
+              125,
+            ]),
+          ],
+        },
+      },
+    },
+    listUsers: {
+      name: "ListUsers",
+      requestType: ListUsersRequest,
+      requestStream: false,
+      responseType: ListUsersResponse,
+      responseStream: false,
+      options: {
+        _unknownFields: {
+          8410: [new Uint8Array([6, 112, 97, 114, 101, 110, 116])],
+          578365826: [new Uint8Array([11, 18, 9, 47, 118, 49, 47, 117, 115, 101, 114, 115])],
+        },
+      },
+    },
+    createUser: {
+      name: "CreateUser",
+      requestType: CreateUserRequest,
+      requestStream: false,
+      responseType: User,
+      responseStream: false,
+      options: {
+        _unknownFields: {
+          8410: [new Uint8Array([4, 117, 115, 101, 114])],
+          578365826: [new Uint8Array([17, 58, 4, 117, 115, 101, 114, 34, 9, 47, 118, 49, 47, 117, 115, 101, 114, 115])],
+        },
+      },
+    },
+    updateUser: {
+      name: "UpdateUser",
+      requestType: UpdateUserRequest,
+      requestStream: false,
+      responseType: User,
+      responseStream: false,
+      options: {
+        _unknownFields: {
+          8410: [new Uint8Array([16, 117, 115, 101, 114, 44, 117, 112, 100, 97, 116, 101, 95, 109, 97, 115, 107])],
+          578365826: [
+            new Uint8Array([
               31,
               58,
-              4,
-              117,
+              4,
+              117,
+              115,
+              101,
+              114,
+              50,
+              23,
+              47,
+              118,
+              49,
+              47,
+              123,
+              117,
+              115,
+              101,
+              114,
+              46,
+              110,
+              97,
+              109,
+              101,
+              61,
+              117,
+              115,
+              101,
+              114,
+              115,
+              47,
+              42,
+              125,
+            ]),
+          ],
+        },
+      },
+    },
+    deleteUser: {
+      name: "DeleteUser",
+      requestType: DeleteUserRequest,
+      requestStream: false,
+      responseType: Empty,
+      responseStream: false,
+      options: {
+        _unknownFields: {
+          8410: [new Uint8Array([4, 110, 97, 109, 101])],
+          578365826: [
+            new Uint8Array([
+              20,
+              42,
+              18,
+              47,
+              118,
+              49,
+              47,
+              123,
+              110,
+              97,
+              109,
+              101,
+              61,
+              117,
+              115,
+              101,
+              114,
+              115,
+              47,
+              42,
+              125,
+            ]),
+          ],
+        },
+      },
+    },
+    undeleteUser: {
+      name: "UndeleteUser",
+      requestType: UndeleteUserRequest,
+      requestStream: false,
+      responseType: User,
+      responseStream: false,
+      options: {
+        _unknownFields: {
+          578365826: [
+            new Uint8Array([
+              32,
+              58,
+              1,
+              42,
+              34,
+              27,
+              47,
+              118,
+              49,
+              47,
+              123,
+              110,
+              97,
+              109,
+              101,
+              61,
+              117,
+              115,
+              101,
+              114,
+              115,
+              47,
+              42,
+              125,
+              58,
+              117,
+              110,
+              100,
+              101,
+              108,
+              101,
+              116,
+              101,
+            ]),
+          ],
+        },
+      },
+    },
+    login: {
+      name: "Login",
+      requestType: LoginRequest,
+      requestStream: false,
+      responseType: LoginResponse,
+      responseStream: false,
+      options: {
+        _unknownFields: {
+          578365826: [
+            new Uint8Array([19, 58, 1, 42, 34, 14, 47, 118, 49, 47, 97, 117, 116, 104, 47, 108, 111, 103, 105, 110]),
+          ],
+        },
+      },
+    },
+    logout: {
+      name: "Logout",
+      requestType: LogoutRequest,
+      requestStream: false,
+      responseType: Empty,
+      responseStream: false,
+      options: {
+        _unknownFields: {
+          578365826: [
+            new Uint8Array([
+              20,
+              58,
+              1,
+              42,
+              34,
+              15,
+              47,
+              118,
+              49,
+              47,
+              97,
+              117,
+              116,
+              104,
+              47,
+              108,
+              111,
+              103,
+              111,
+              117,
+              116,
+            ]),
+          ],
+        },
+      },
+    },
+    listAccessTokens: {
+      name: "ListAccessTokens",
+      requestType: ListAccessTokensRequest,
+      requestStream: false,
+      responseType: ListAccessTokensResponse,
+      responseStream: false,
+      options: {
+        _unknownFields: {
+          8410: [new Uint8Array([6, 112, 97, 114, 101, 110, 116])],
+          578365826: [
+            new Uint8Array([
+              35,
+              18,
+              33,
+              47,
+              118,
+              49,
+              47,
+              123,
+              112,
+              97,
+              114,
+              101,
+              110,
+              116,
+              61,
+              117,
+              115,
+              101,
+              114,
+              115,
+              47,
+              42,
+              125,
+              47,
+              97,
+              99,
+              99,
+              101,
+              115,
+              115,
+              84,
+              111,
+              107,
+              101,
+              110,
+              115,
+            ]),
+          ],
+        },
+      },
+    },
+    createAccessToken: {
+      name: "CreateAccessToken",
+      requestType: CreateAccessTokenRequest,
+      requestStream: false,
+      responseType: AccessToken,
+      responseStream: false,
+      options: {
+        _unknownFields: {
+          8410: [
+            new Uint8Array([
+              19,
+              112,
+              97,
+              114,
+              101,
+              110,
+              116,
+              44,
+              97,
+              99,
+              99,
+              101,
+              115,
+              115,
+              95,
+              116,
+              111,
+              107,
+              101,
+              110,
+            ]),
+          ],
+          578365826: [
+            new Uint8Array([
+              49,
+              58,
+              12,
+              97,
+              99,
+              99,
+              101,
+              115,
               115,
+              95,
+              116,
+              111,
+              107,
               101,
-              114,
-              50,
-              23,
+              110,
+              34,
+              33,
               47,
               118,
               49,
               47,
               123,
-              117,
-              115,
-              101,
-              114,
-              46,
-              110,
+              112,
               97,
-              109,
+              114,
               101,
+              110,
+              116,
               61,
               117,
               115,
@@ -1598,14 +3354,27 @@ export const AuthServiceDefinition = {
               47,
               42,
               125,
+              47,
+              97,
+              99,
+              99,
+              101,
+              115,
+              115,
+              84,
+              111,
+              107,
+              101,
+              110,
+              115,
             ]),
           ],
         },
       },
     },
-    deleteUser: {
-      name: "DeleteUser",
-      requestType: DeleteUserRequest,
+    deleteAccessToken: {
+      name: "DeleteAccessToken",
+      requestType: DeleteAccessTokenRequest,
       requestStream: false,
       responseType: Empty,
       responseStream: false,
@@ -1614,9 +3383,9 @@ export const AuthServiceDefinition = {
           8410: [new Uint8Array([4, 110, 97, 109, 101])],
           578365826: [
             new Uint8Array([
-              20,
+              35,
               42,
-              18,
+              33,
               47,
               118,
               49,
@@ -1634,28 +3403,43 @@ export const AuthServiceDefinition = {
               115,
               47,
               42,
+              47,
+              97,
+              99,
+              99,
+              101,
+              115,
+              115,
+              84,
+              111,
+              107,
+              101,
+              110,
+              115,
+              47,
+              42,
               125,
             ]),
           ],
         },
       },
     },
-    undeleteUser: {
-      name: "UndeleteUser",
-      requestType: UndeleteUserRequest,
+    rotateAccessToken: {
+      name: "RotateAccessToken",
+      requestType: RotateAccessTokenRequest,
       requestStream: false,
-      responseType: User,
+      responseType: AccessToken,
       responseStream: false,
       options: {
         _unknownFields: {
           578365826: [
             new Uint8Array([
-              32,
+              45,
               58,
               1,
               42,
               34,
-              27,
+              40,
               47,
               118,
               49,
@@ -1673,66 +3457,257 @@ export const AuthServiceDefinition = {
               115,
               47,
               42,
+              47,
+              97,
+              99,
+              99,
+              101,
+              115,
+              115,
+              84,
+              111,
+              107,
+              101,
+              110,
+              115,
+              47,
+              42,
               125,
               58,
-              117,
+              114,
+              111,
+              116,
+              97,
+              116,
+              101,
+            ]),
+          ],
+        },
+      },
+    },
+    listApprovalDelegations: {
+      name: "ListApprovalDelegations",
+      requestType: ListApprovalDelegationsRequest,
+      requestStream: false,
+      responseType: ListApprovalDelegationsResponse,
+      responseStream: false,
+      options: {
+        _unknownFields: {
+          8410: [new Uint8Array([6, 112, 97, 114, 101, 110, 116])],
+          578365826: [
+            new Uint8Array([
+              42,
+              18,
+              40,
+              47,
+              118,
+              49,
+              47,
+              123,
+              112,
+              97,
+              114,
+              101,
               110,
-              100,
+              116,
+              61,
+              117,
+              115,
+              101,
+              114,
+              115,
+              47,
+              42,
+              125,
+              47,
+              97,
+              112,
+              112,
+              114,
+              111,
+              118,
+              97,
+              108,
+              68,
               101,
               108,
               101,
+              103,
+              97,
               116,
-              101,
+              105,
+              111,
+              110,
+              115,
             ]),
           ],
         },
       },
     },
-    login: {
-      name: "Login",
-      requestType: LoginRequest,
+    createApprovalDelegation: {
+      name: "CreateApprovalDelegation",
+      requestType: CreateApprovalDelegationRequest,
       requestStream: false,
-      responseType: LoginResponse,
+      responseType: ApprovalDelegation,
       responseStream: false,
       options: {
         _unknownFields: {
+          8410: [
+            new Uint8Array([
+              26,
+              112,
+              97,
+              114,
+              101,
+              110,
+              116,
+              44,
+              97,
+              112,
+              112,
+              114,
+              111,
+              118,
+              97,
+              108,
+              95,
+              100,
+              101,
+              108,
+              101,
+              103,
+              97,
+              116,
+              105,
+              111,
+              110,
+            ]),
+          ],
           578365826: [
-            new Uint8Array([19, 58, 1, 42, 34, 14, 47, 118, 49, 47, 97, 117, 116, 104, 47, 108, 111, 103, 105, 110]),
+            new Uint8Array([
+              63,
+              58,
+              19,
+              97,
+              112,
+              112,
+              114,
+              111,
+              118,
+              97,
+              108,
+              95,
+              100,
+              101,
+              108,
+              101,
+              103,
+              97,
+              116,
+              105,
+              111,
+              110,
+              34,
+              40,
+              47,
+              118,
+              49,
+              47,
+              123,
+              112,
+              97,
+              114,
+              101,
+              110,
+              116,
+              61,
+              117,
+              115,
+              101,
+              114,
+              115,
+              47,
+              42,
+              125,
+              47,
+              97,
+              112,
+              112,
+              114,
+              111,
+              118,
+              97,
+              108,
+              68,
+              101,
+              108,
+              101,
+              103,
+              97,
+              116,
+              105,
+              111,
+              110,
+              115,
+            ]),
           ],
         },
       },
     },
-    logout: {
-      name: "Logout",
-      requestType: LogoutRequest,
+    deleteApprovalDelegation: {
+      name: "DeleteApprovalDelegation",
+      requestType: DeleteApprovalDelegationRequest,
       requestStream: false,
       responseType: Empty,
       responseStream: false,
       options: {
         _unknownFields: {
+          8410: [new Uint8Array([4, 110, 97, 109, 101])],
           578365826: [
             new Uint8Array([
-              20,
-              58,
-              1,
               42,
-              34,
-              15,
+              42,
+              40,
               47,
               118,
               49,
               47,
+              123,
+              110,
               97,
+              109,
+              101,
+              61,
               117,
-              116,
-              104,
+              115,
+              101,
+              114,
+              115,
               47,
-              108,
+              42,
+              47,
+              97,
+              112,
+              112,
+              114,
               111,
+              118,
+              97,
+              108,
+              68,
+              101,
+              108,
+              101,
               103,
-              111,
-              117,
+              97,
               116,
+              105,
+              111,
+              110,
+              115,
+              47,
+              42,
+              125,
             ]),
           ],
         },
@@ -1749,6 +3724,32 @@ export type DeepPartial<T> = T extends Builtin ? T
   : T extends {} ? { [K in keyof T]?: DeepPartial<T[K]> }
   : Partial<T>;
 
+function toTimestamp(date: Date): Timestamp {
+  const seconds = numberToLong(date.getTime() / 1_000);
+  const nanos = (date.getTime() % 1_000) * 1_000_000;
+  return { seconds, nanos };
+}
+
+function fromTimestamp(t: Timestamp): Date {
+  let millis = (t.seconds.toNumber() || 0) * 1_000;
+  millis += (t.nanos || 0) / 1_000_000;
+  return new globalThis.Date(millis);
+}
+
+function fromJsonTimestamp(o: any): Date {
+  if (o instanceof globalThis.Date) {
+    return o;
+  } else if (typeof o === "string") {
+    return new globalThis.Date(o);
+  } else {
+    return fromTimestamp(Timestamp.fromJSON(o));
+  }
+}
+
+function numberToLong(number: number) {
+  return Long.fromNumber(number);
+}
+
 if (_m0.util.Long !== Long) {
   _m0.util.Long = Long as any;
   _m0.configure();
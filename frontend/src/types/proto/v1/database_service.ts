@@ -1429,6 +1429,44 @@ export interface GetChangeHistoryRequest {
   sdlFormat: boolean;
 }
 
+export interface ListDatabaseGrantsRequest {
+  /**
+   * The parent resource of the grants.
+   * Format: instances/{instance}/databases/{database}
+   */
+  parent: string;
+}
+
+export interface ListDatabaseGrantsResponse {
+  /** The active grants of the database. */
+  grants: DatabaseGrant[];
+}
+
+/** DatabaseGrant is the query or export role granted to a member in the project of the database. */
+export interface DatabaseGrant {
+  /**
+   * The member of the grant.
+   * Format: user:{email}
+   */
+  member: string;
+  /**
+   * The role of the grant.
+   * Format: roles/{role}
+   */
+  role: string;
+  /** The CEL expression of the grant condition. */
+  expression: string;
+  /** The expiration time of the grant. The grant never expires if it's not set. */
+  expireTime:
+    | Date
+    | undefined;
+  /**
+   * The grant request issue of the grant, if any.
+   * Format: projects/{project}/issues/{issue}
+   */
+  issue: string;
+}
+
 function createBaseGetDatabaseRequest(): GetDatabaseRequest {
   return { name: "" };
 }
@@ -7963,6 +8001,241 @@ export const GetChangeHistoryRequest = {
 };
 
 export type DatabaseServiceDefinition = typeof DatabaseServiceDefinition;
+function createBaseListDatabaseGrantsRequest(): ListDatabaseGrantsRequest {
+  return { parent: "" };
+}
+
+export const ListDatabaseGrantsRequest = {
+  encode(message: ListDatabaseGrantsRequest, writer: _m0.Writer = _m0.Writer.create()): _m0.Writer {
+    if (message.parent !== "") {
+      writer.uint32(10).string(message.parent);
+    }
+    return writer;
+  },
+
+  decode(input: _m0.Reader | Uint8Array, length?: number): ListDatabaseGrantsRequest {
+    const reader = input instanceof _m0.Reader ? input : _m0.Reader.create(input);
+    let end = length === undefined ? reader.len : reader.pos + length;
+    const message = createBaseListDatabaseGrantsRequest();
+    while (reader.pos < end) {
+      const tag = reader.uint32();
+      switch (tag >>> 3) {
+        case 1:
+          if (tag !== 10) {
+            break;
+          }
+
+          message.parent = reader.string();
+          continue;
+      }
+      if ((tag & 7) === 4 || tag === 0) {
+        break;
+      }
+      reader.skipType(tag & 7);
+    }
+    return message;
+  },
+
+  fromJSON(object: any): ListDatabaseGrantsRequest {
+    return { parent: isSet(object.parent) ? globalThis.String(object.parent) : "" };
+  },
+
+  toJSON(message: ListDatabaseGrantsRequest): unknown {
+    const obj: any = {};
+    if (message.parent !== "") {
+      obj.parent = message.parent;
+    }
+    return obj;
+  },
+
+  create(base?: DeepPartial<ListDatabaseGrantsRequest>): ListDatabaseGrantsRequest {
+    return ListDatabaseGrantsRequest.fromPartial(base ?? {});
+  },
+  fromPartial(object: DeepPartial<ListDatabaseGrantsRequest>): ListDatabaseGrantsRequest {
+    const message = createBaseListDatabaseGrantsRequest();
+    message.parent = object.parent ?? "";
+    return message;
+  },
+};
+
+function createBaseListDatabaseGrantsResponse(): ListDatabaseGrantsResponse {
+  return { grants: [] };
+}
+
+export const ListDatabaseGrantsResponse = {
+  encode(message: ListDatabaseGrantsResponse, writer: _m0.Writer = _m0.Writer.create()): _m0.Writer {
+    for (const v of message.grants) {
+      DatabaseGrant.encode(v!, writer.uint32(10).fork()).ldelim();
+    }
+    return writer;
+  },
+
+  decode(input: _m0.Reader | Uint8Array, length?: number): ListDatabaseGrantsResponse {
+    const reader = input instanceof _m0.Reader ? input : _m0.Reader.create(input);
+    let end = length === undefined ? reader.len : reader.pos + length;
+    const message = createBaseListDatabaseGrantsResponse();
+    while (reader.pos < end) {
+      const tag = reader.uint32();
+      switch (tag >>> 3) {
+        case 1:
+          if (tag !== 10) {
+            break;
+          }
+
+          message.grants.push(DatabaseGrant.decode(reader, reader.uint32()));
+          continue;
+      }
+      if ((tag & 7) === 4 || tag === 0) {
+        break;
+      }
+      reader.skipType(tag & 7);
+    }
+    return message;
+  },
+
+  fromJSON(object: any): ListDatabaseGrantsResponse {
+    return {
+      grants: globalThis.Array.isArray(object?.grants) ? object.grants.map((e: any) => DatabaseGrant.fromJSON(e)) : [],
+    };
+  },
+
+  toJSON(message: ListDatabaseGrantsResponse): unknown {
+    const obj: any = {};
+    if (message.grants?.length) {
+      obj.grants = message.grants.map((e) => DatabaseGrant.toJSON(e));
+    }
+    return obj;
+  },
+
+  create(base?: DeepPartial<ListDatabaseGrantsResponse>): ListDatabaseGrantsResponse {
+    return ListDatabaseGrantsResponse.fromPartial(base ?? {});
+  },
+  fromPartial(object: DeepPartial<ListDatabaseGrantsResponse>): ListDatabaseGrantsResponse {
+    const message = createBaseListDatabaseGrantsResponse();
+    message.grants = object.grants?.map((e) => DatabaseGrant.fromPartial(e)) || [];
+    return message;
+  },
+};
+
+function createBaseDatabaseGrant(): DatabaseGrant {
+  return { member: "", role: "", expression: "", expireTime: undefined, issue: "" };
+}
+
+export const DatabaseGrant = {
+  encode(message: DatabaseGrant, writer: _m0.Writer = _m0.Writer.create()): _m0.Writer {
+    if (message.member !== "") {
+      writer.uint32(10).string(message.member);
+    }
+    if (message.role !== "") {
+      writer.uint32(18).string(message.role);
+    }
+    if (message.expression !== "") {
+      writer.uint32(26).string(message.expression);
+    }
+    if (message.expireTime !== undefined) {
+      Timestamp.encode(toTimestamp(message.expireTime), writer.uint32(34).fork()).ldelim();
+    }
+    if (message.issue !== "") {
+      writer.uint32(42).string(message.issue);
+    }
+    return writer;
+  },
+
+  decode(input: _m0.Reader | Uint8Array, length?: number): DatabaseGrant {
+    const reader = input instanceof _m0.Reader ? input : _m0.Reader.create(input);
+    let end = length === undefined ? reader.len : reader.pos + length;
+    const message = createBaseDatabaseGrant();
+    while (reader.pos < end) {
+      const tag = reader.uint32();
+      switch (tag >>> 3) {
+        case 1:
+          if (tag !== 10) {
+            break;
+          }
+
+          message.member = reader.string();
+          continue;
+        case 2:
+          if (tag !== 18) {
+            break;
+          }
+
+          message.role = reader.string();
+          continue;
+        case 3:
+          if (tag !== 26) {
+            break;
+          }
+
+          message.expression = reader.string();
+          continue;
+        case 4:
+          if (tag !== 34) {
+            break;
+          }
+
+          message.expireTime = fromTimestamp(Timestamp.decode(reader, reader.uint32()));
+          continue;
+        case 5:
+          if (tag !== 42) {
+            break;
+          }
+
+          message.issue = reader.string();
+          continue;
+      }
+      if ((tag & 7) === 4 || tag === 0) {
+        break;
+      }
+      reader.skipType(tag & 7);
+    }
+    return message;
+  },
+
+  fromJSON(object: any): DatabaseGrant {
+    return {
+      member: isSet(object.member) ? globalThis.String(object.member) : "",
+      role: isSet(object.role) ? globalThis.String(object.role) : "",
+      expression: isSet(object.expression) ? globalThis.String(object.expression) : "",
+      expireTime: isSet(object.expireTime) ? fromJsonTimestamp(object.expireTime) : undefined,
+      issue: isSet(object.issue) ? globalThis.String(object.issue) : "",
+    };
+  },
+
+  toJSON(message: DatabaseGrant): unknown {
+    const obj: any = {};
+    if (message.member !== "") {
+      obj.member = message.member;
+    }
+    if (message.role !== "") {
+      obj.role = message.role;
+    }
+    if (message.expression !== "") {
+      obj.expression = message.expression;
+    }
+    if (message.expireTime !== undefined) {
+      obj.expireTime = message.expireTime.toISOString();
+    }
+    if (message.issue !== "") {
+      obj.issue = message.issue;
+    }
+    return obj;
+  },
+
+  create(base?: DeepPartial<DatabaseGrant>): DatabaseGrant {
+    return DatabaseGrant.fromPartial(base ?? {});
+  },
+  fromPartial(object: DeepPartial<DatabaseGrant>): DatabaseGrant {
+    const message = createBaseDatabaseGrant();
+    message.member = object.member ?? "";
+    message.role = object.role ?? "";
+    message.expression = object.expression ?? "";
+    message.expireTime = object.expireTime ?? undefined;
+    message.issue = object.issue ?? "";
+    return message;
+  },
+};
+
 export const DatabaseServiceDefinition = {
   name: "DatabaseService",
   fullName: "bytebase.v1.DatabaseService",
@@ -9468,6 +9741,68 @@ export const DatabaseServiceDefinition = {
         },
       },
     },
+    listDatabaseGrants: {
+      name: "ListDatabaseGrants",
+      requestType: ListDatabaseGrantsRequest,
+      requestStream: false,
+      responseType: ListDatabaseGrantsResponse,
+      responseStream: false,
+      options: {
+        _unknownFields: {
+          8410: [new Uint8Array([6, 112, 97, 114, 101, 110, 116])],
+          578365826: [
+            new Uint8Array([
+              45,
+              18,
+              43,
+              47,
+              118,
+              49,
+              47,
+              123,
+              112,
+              97,
+              114,
+              101,
+              110,
+              116,
+              61,
+              105,
+              110,
+              115,
+              116,
+              97,
+              110,
+              99,
+              101,
+              115,
+              47,
+              42,
+              47,
+              100,
+              97,
+              116,
+              97,
+              98,
+              97,
+              115,
+              101,
+              115,
+              47,
+              42,
+              125,
+              47,
+              103,
+              114,
+              97,
+              110,
+              116,
+              115,
+            ]),
+          ],
+        },
+      },
+    },
   },
 } as const;
 
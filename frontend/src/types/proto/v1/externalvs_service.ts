@@ -130,6 +130,10 @@ export enum ExternalVersionControl_Type {
   BITBUCKET = 3,
   /** AZURE_DEVOPS - Azure DevOps. Using for Azure DevOps GitOps workflow. */
   AZURE_DEVOPS = 4,
+  /** GITEA - Gitea type. Using for Gitea and Forgejo. */
+  GITEA = 5,
+  /** BITBUCKET_DATA_CENTER - Bitbucket Data Center type. Using for self-hosted Bitbucket Data Center and Bitbucket Server. */
+  BITBUCKET_DATA_CENTER = 6,
   UNRECOGNIZED = -1,
 }
 
@@ -150,6 +154,12 @@ export function externalVersionControl_TypeFromJSON(object: any): ExternalVersio
     case 4:
     case "AZURE_DEVOPS":
       return ExternalVersionControl_Type.AZURE_DEVOPS;
+    case 5:
+    case "GITEA":
+      return ExternalVersionControl_Type.GITEA;
+    case 6:
+    case "BITBUCKET_DATA_CENTER":
+      return ExternalVersionControl_Type.BITBUCKET_DATA_CENTER;
     case -1:
     case "UNRECOGNIZED":
     default:
@@ -169,6 +179,10 @@ export function externalVersionControl_TypeToJSON(object: ExternalVersionControl
       return "BITBUCKET";
     case ExternalVersionControl_Type.AZURE_DEVOPS:
       return "AZURE_DEVOPS";
+    case ExternalVersionControl_Type.GITEA:
+      return "GITEA";
+    case ExternalVersionControl_Type.BITBUCKET_DATA_CENTER:
+      return "BITBUCKET_DATA_CENTER";
     case ExternalVersionControl_Type.UNRECOGNIZED:
     default:
       return "UNRECOGNIZED";
@@ -220,6 +234,8 @@ export interface ProjectGitOpsInfo {
   accessToken: string;
   expiresTime: Date | undefined;
   refreshToken: string;
+  /** Set to true to review the PR/MRs from the webhook events and comment on them, instead of the SQL review CI. */
+  enableNativeSqlReview: boolean;
 }
 
 export interface ExchangeTokenRequest {
@@ -1206,6 +1222,7 @@ function createBaseProjectGitOpsInfo(): ProjectGitOpsInfo {
     accessToken: "",
     expiresTime: undefined,
     refreshToken: "",
+    enableNativeSqlReview: false,
   };
 }
 
@@ -1259,6 +1276,9 @@ export const ProjectGitOpsInfo = {
     if (message.refreshToken !== "") {
       writer.uint32(130).string(message.refreshToken);
     }
+    if (message.enableNativeSqlReview === true) {
+      writer.uint32(136).bool(message.enableNativeSqlReview);
+    }
     return writer;
   },
 
@@ -1381,6 +1401,13 @@ export const ProjectGitOpsInfo = {
 
           message.refreshToken = reader.string();
           continue;
+        case 17:
+          if (tag !== 136) {
+            break;
+          }
+
+          message.enableNativeSqlReview = reader.bool();
+          continue;
       }
       if ((tag & 7) === 4 || tag === 0) {
         break;
@@ -1408,6 +1435,9 @@ export const ProjectGitOpsInfo = {
       accessToken: isSet(object.accessToken) ? globalThis.String(object.accessToken) : "",
       expiresTime: isSet(object.expiresTime) ? fromJsonTimestamp(object.expiresTime) : undefined,
       refreshToken: isSet(object.refreshToken) ? globalThis.String(object.refreshToken) : "",
+      enableNativeSqlReview: isSet(object.enableNativeSqlReview)
+        ? globalThis.Boolean(object.enableNativeSqlReview)
+        : false,
     };
   },
 
@@ -1461,6 +1491,9 @@ export const ProjectGitOpsInfo = {
     if (message.refreshToken !== "") {
       obj.refreshToken = message.refreshToken;
     }
+    if (message.enableNativeSqlReview === true) {
+      obj.enableNativeSqlReview = message.enableNativeSqlReview;
+    }
     return obj;
   },
 
@@ -1485,6 +1518,7 @@ export const ProjectGitOpsInfo = {
     message.accessToken = object.accessToken ?? "";
     message.expiresTime = object.expiresTime ?? undefined;
     message.refreshToken = object.refreshToken ?? "";
+    message.enableNativeSqlReview = object.enableNativeSqlReview ?? false;
     return message;
   },
 };
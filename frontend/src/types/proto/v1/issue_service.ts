@@ -326,6 +326,11 @@ export interface Issue_Approver {
   status: Issue_Approver_Status;
   /** Format: users/hello@world.com */
   principal: string;
+  /**
+   * The delegator if the approver reviews on behalf of the delegator.
+   * Format: users/hello@world.com
+   */
+  delegator: string;
 }
 
 export enum Issue_Approver_Status {
@@ -406,6 +411,16 @@ export interface ApprovalFlow {
 export interface ApprovalStep {
   type: ApprovalStep_Type;
   nodes: ApprovalNode[];
+  /** The step times out if it's not reviewed within the timeout. The step never times out if it's not set. */
+  timeout: Duration | undefined;
+  /**
+   * The role that the step escalates to after the timeout, and the users with the role can review the step as well.
+   * The issue is rejected automatically after the timeout if it's not set.
+   * Format: roles/{role}
+   */
+  escalationRole: string;
+  /** The reviewers are reminded at the interval until the step is reviewed. No reminders are sent if it's not set. */
+  reminderInterval: Duration | undefined;
 }
 
 /**
@@ -1910,7 +1925,7 @@ export const Issue = {
 };
 
 function createBaseIssue_Approver(): Issue_Approver {
-  return { status: 0, principal: "" };
+  return { status: 0, principal: "", delegator: "" };
 }
 
 export const Issue_Approver = {
@@ -1921,6 +1936,9 @@ export const Issue_Approver = {
     if (message.principal !== "") {
       writer.uint32(18).string(message.principal);
     }
+    if (message.delegator !== "") {
+      writer.uint32(26).string(message.delegator);
+    }
     return writer;
   },
 
@@ -1945,6 +1963,13 @@ export const Issue_Approver = {
 
           message.principal = reader.string();
           continue;
+        case 3:
+          if (tag !== 26) {
+            break;
+          }
+
+          message.delegator = reader.string();
+          continue;
       }
       if ((tag & 7) === 4 || tag === 0) {
         break;
@@ -1958,6 +1983,7 @@ export const Issue_Approver = {
     return {
       status: isSet(object.status) ? issue_Approver_StatusFromJSON(object.status) : 0,
       principal: isSet(object.principal) ? globalThis.String(object.principal) : "",
+      delegator: isSet(object.delegator) ? globalThis.String(object.delegator) : "",
     };
   },
 
@@ -1969,6 +1995,9 @@ export const Issue_Approver = {
     if (message.principal !== "") {
       obj.principal = message.principal;
     }
+    if (message.delegator !== "") {
+      obj.delegator = message.delegator;
+    }
     return obj;
   },
 
@@ -1979,6 +2008,7 @@ export const Issue_Approver = {
     const message = createBaseIssue_Approver();
     message.status = object.status ?? 0;
     message.principal = object.principal ?? "";
+    message.delegator = object.delegator ?? "";
     return message;
   },
 };
@@ -2257,7 +2287,7 @@ export const ApprovalFlow = {
 };
 
 function createBaseApprovalStep(): ApprovalStep {
-  return { type: 0, nodes: [] };
+  return { type: 0, nodes: [], timeout: undefined, escalationRole: "", reminderInterval: undefined };
 }
 
 export const ApprovalStep = {
@@ -2268,6 +2298,15 @@ export const ApprovalStep = {
     for (const v of message.nodes) {
       ApprovalNode.encode(v!, writer.uint32(18).fork()).ldelim();
     }
+    if (message.timeout !== undefined) {
+      Duration.encode(message.timeout, writer.uint32(26).fork()).ldelim();
+    }
+    if (message.escalationRole !== "") {
+      writer.uint32(34).string(message.escalationRole);
+    }
+    if (message.reminderInterval !== undefined) {
+      Duration.encode(message.reminderInterval, writer.uint32(42).fork()).ldelim();
+    }
     return writer;
   },
 
@@ -2292,6 +2331,27 @@ export const ApprovalStep = {
 
           message.nodes.push(ApprovalNode.decode(reader, reader.uint32()));
           continue;
+        case 3:
+          if (tag !== 26) {
+            break;
+          }
+
+          message.timeout = Duration.decode(reader, reader.uint32());
+          continue;
+        case 4:
+          if (tag !== 34) {
+            break;
+          }
+
+          message.escalationRole = reader.string();
+          continue;
+        case 5:
+          if (tag !== 42) {
+            break;
+          }
+
+          message.reminderInterval = Duration.decode(reader, reader.uint32());
+          continue;
       }
       if ((tag & 7) === 4 || tag === 0) {
         break;
@@ -2305,6 +2365,9 @@ export const ApprovalStep = {
     return {
       type: isSet(object.type) ? approvalStep_TypeFromJSON(object.type) : 0,
       nodes: globalThis.Array.isArray(object?.nodes) ? object.nodes.map((e: any) => ApprovalNode.fromJSON(e)) : [],
+      timeout: isSet(object.timeout) ? Duration.fromJSON(object.timeout) : undefined,
+      escalationRole: isSet(object.escalationRole) ? globalThis.String(object.escalationRole) : "",
+      reminderInterval: isSet(object.reminderInterval) ? Duration.fromJSON(object.reminderInterval) : undefined,
     };
   },
 
@@ -2316,6 +2379,15 @@ export const ApprovalStep = {
     if (message.nodes?.length) {
       obj.nodes = message.nodes.map((e) => ApprovalNode.toJSON(e));
     }
+    if (message.timeout !== undefined) {
+      obj.timeout = Duration.toJSON(message.timeout);
+    }
+    if (message.escalationRole !== "") {
+      obj.escalationRole = message.escalationRole;
+    }
+    if (message.reminderInterval !== undefined) {
+      obj.reminderInterval = Duration.toJSON(message.reminderInterval);
+    }
     return obj;
   },
 
@@ -2326,6 +2398,13 @@ export const ApprovalStep = {
     const message = createBaseApprovalStep();
     message.type = object.type ?? 0;
     message.nodes = object.nodes?.map((e) => ApprovalNode.fromPartial(e)) || [];
+    message.timeout = (object.timeout !== undefined && object.timeout !== null)
+      ? Duration.fromPartial(object.timeout)
+      : undefined;
+    message.escalationRole = object.escalationRole ?? "";
+    message.reminderInterval = (object.reminderInterval !== undefined && object.reminderInterval !== null)
+      ? Duration.fromPartial(object.reminderInterval)
+      : undefined;
     return message;
   },
 };
@@ -155,6 +155,18 @@ export enum LogEntity_Action {
   ACTION_MEMBER_ACTIVATE = 3,
   /** ACTION_MEMBER_DEACTIVE - ACTION_MEMBER_DEACTIVE is the type for deactiving members. */
   ACTION_MEMBER_DEACTIVE = 4,
+  /** ACTION_MEMBER_LOGIN_FAILED - ACTION_MEMBER_LOGIN_FAILED is the type for failed login attempts of members. */
+  ACTION_MEMBER_LOGIN_FAILED = 5,
+  /** ACTION_MEMBER_LOCK - ACTION_MEMBER_LOCK is the type for locking members after too many failed login attempts. */
+  ACTION_MEMBER_LOCK = 6,
+  /** ACTION_MEMBER_UNLOCK - ACTION_MEMBER_UNLOCK is the type for unlocking members. */
+  ACTION_MEMBER_UNLOCK = 7,
+  /** ACTION_MEMBER_ACCESS_TOKEN_CREATE - ACTION_MEMBER_ACCESS_TOKEN_CREATE is the type for creating personal access tokens of members. */
+  ACTION_MEMBER_ACCESS_TOKEN_CREATE = 8,
+  /** ACTION_MEMBER_ACCESS_TOKEN_REVOKE - ACTION_MEMBER_ACCESS_TOKEN_REVOKE is the type for revoking personal access tokens of members. */
+  ACTION_MEMBER_ACCESS_TOKEN_REVOKE = 9,
+  /** ACTION_MEMBER_ACCESS_TOKEN_ROTATE - ACTION_MEMBER_ACCESS_TOKEN_ROTATE is the type for rotating personal access tokens of members. */
+  ACTION_MEMBER_ACCESS_TOKEN_ROTATE = 10,
   /**
    * ACTION_ISSUE_CREATE - Issue related activity types.
    * Enum value 21 - 40
@@ -197,6 +209,8 @@ export enum LogEntity_Action {
   ACTION_PROJECT_DATABASE_RECOVERY_PITR_DONE = 44,
   /** ACTION_PROJECT_DATABASE_TRANSFER - ACTION_PROJECT_DATABASE_TRANSFER represents transfering the database from one project to another. */
   ACTION_PROJECT_DATABASE_TRANSFER = 45,
+  /** ACTION_PROJECT_MEMBER_EXPIRING - ACTION_PROJECT_MEMBER_EXPIRING represents notifying the project member that the granted role is about to expire. */
+  ACTION_PROJECT_MEMBER_EXPIRING = 46,
   /**
    * ACTION_DATABASE_SQL_EDITOR_QUERY - Database related activity types.
    * Enum value 61 - 80
@@ -226,6 +240,24 @@ export function logEntity_ActionFromJSON(object: any): LogEntity_Action {
     case 4:
     case "ACTION_MEMBER_DEACTIVE":
       return LogEntity_Action.ACTION_MEMBER_DEACTIVE;
+    case 5:
+    case "ACTION_MEMBER_LOGIN_FAILED":
+      return LogEntity_Action.ACTION_MEMBER_LOGIN_FAILED;
+    case 6:
+    case "ACTION_MEMBER_LOCK":
+      return LogEntity_Action.ACTION_MEMBER_LOCK;
+    case 7:
+    case "ACTION_MEMBER_UNLOCK":
+      return LogEntity_Action.ACTION_MEMBER_UNLOCK;
+    case 8:
+    case "ACTION_MEMBER_ACCESS_TOKEN_CREATE":
+      return LogEntity_Action.ACTION_MEMBER_ACCESS_TOKEN_CREATE;
+    case 9:
+    case "ACTION_MEMBER_ACCESS_TOKEN_REVOKE":
+      return LogEntity_Action.ACTION_MEMBER_ACCESS_TOKEN_REVOKE;
+    case 10:
+    case "ACTION_MEMBER_ACCESS_TOKEN_ROTATE":
+      return LogEntity_Action.ACTION_MEMBER_ACCESS_TOKEN_ROTATE;
     case 21:
     case "ACTION_ISSUE_CREATE":
       return LogEntity_Action.ACTION_ISSUE_CREATE;
@@ -274,6 +306,9 @@ export function logEntity_ActionFromJSON(object: any): LogEntity_Action {
     case 45:
     case "ACTION_PROJECT_DATABASE_TRANSFER":
       return LogEntity_Action.ACTION_PROJECT_DATABASE_TRANSFER;
+    case 46:
+    case "ACTION_PROJECT_MEMBER_EXPIRING":
+      return LogEntity_Action.ACTION_PROJECT_MEMBER_EXPIRING;
     case 61:
     case "ACTION_DATABASE_SQL_EDITOR_QUERY":
       return LogEntity_Action.ACTION_DATABASE_SQL_EDITOR_QUERY;
@@ -299,6 +334,18 @@ export function logEntity_ActionToJSON(object: LogEntity_Action): string {
       return "ACTION_MEMBER_ACTIVATE";
     case LogEntity_Action.ACTION_MEMBER_DEACTIVE:
       return "ACTION_MEMBER_DEACTIVE";
+    case LogEntity_Action.ACTION_MEMBER_LOGIN_FAILED:
+      return "ACTION_MEMBER_LOGIN_FAILED";
+    case LogEntity_Action.ACTION_MEMBER_LOCK:
+      return "ACTION_MEMBER_LOCK";
+    case LogEntity_Action.ACTION_MEMBER_UNLOCK:
+      return "ACTION_MEMBER_UNLOCK";
+    case LogEntity_Action.ACTION_MEMBER_ACCESS_TOKEN_CREATE:
+      return "ACTION_MEMBER_ACCESS_TOKEN_CREATE";
+    case LogEntity_Action.ACTION_MEMBER_ACCESS_TOKEN_REVOKE:
+      return "ACTION_MEMBER_ACCESS_TOKEN_REVOKE";
+    case LogEntity_Action.ACTION_MEMBER_ACCESS_TOKEN_ROTATE:
+      return "ACTION_MEMBER_ACCESS_TOKEN_ROTATE";
     case LogEntity_Action.ACTION_ISSUE_CREATE:
       return "ACTION_ISSUE_CREATE";
     case LogEntity_Action.ACTION_ISSUE_COMMENT_CREATE:
@@ -331,6 +378,8 @@ export function logEntity_ActionToJSON(object: LogEntity_Action): string {
       return "ACTION_PROJECT_DATABASE_RECOVERY_PITR_DONE";
     case LogEntity_Action.ACTION_PROJECT_DATABASE_TRANSFER:
       return "ACTION_PROJECT_DATABASE_TRANSFER";
+    case LogEntity_Action.ACTION_PROJECT_MEMBER_EXPIRING:
+      return "ACTION_PROJECT_MEMBER_EXPIRING";
     case LogEntity_Action.ACTION_DATABASE_SQL_EDITOR_QUERY:
       return "ACTION_DATABASE_SQL_EDITOR_QUERY";
     case LogEntity_Action.ACTION_DATABASE_SQL_EXPORT:
@@ -389,6 +438,28 @@ export function logEntity_LevelToJSON(object: LogEntity_Level): string {
   }
 }
 
+export interface VerifyLogsRequest {
+}
+
+export interface VerifyLogsResponse {
+  /** verified is true if none of the logs is modified or deleted since it's written. */
+  verified: boolean;
+  /** The number of the verified log records, a log has a new record every time it's updated. */
+  recordCount: Long;
+  /**
+   * The hash of the latest record in the chain.
+   * Compare it with the hash of the latest exported record to detect the truncation of the chain.
+   */
+  headHash: string;
+  /**
+   * The name of the first log failing the verification.
+   * Format: logs/{uid}
+   */
+  log: string;
+  /** The reason why the verification fails. */
+  reason: string;
+}
+
 function createBaseListLogsRequest(): ListLogsRequest {
   return { filter: "", orderBy: "", pageSize: 0, pageToken: "" };
 }
@@ -961,6 +1032,170 @@ export const LogEntity = {
   },
 };
 
+function createBaseVerifyLogsRequest(): VerifyLogsRequest {
+  return {};
+}
+
+export const VerifyLogsRequest = {
+  encode(_: VerifyLogsRequest, writer: _m0.Writer = _m0.Writer.create()): _m0.Writer {
+    return writer;
+  },
+
+  decode(input: _m0.Reader | Uint8Array, length?: number): VerifyLogsRequest {
+    const reader = input instanceof _m0.Reader ? input : _m0.Reader.create(input);
+    let end = length === undefined ? reader.len : reader.pos + length;
+    const message = createBaseVerifyLogsRequest();
+    while (reader.pos < end) {
+      const tag = reader.uint32();
+      switch (tag >>> 3) {
+      }
+      if ((tag & 7) === 4 || tag === 0) {
+        break;
+      }
+      reader.skipType(tag & 7);
+    }
+    return message;
+  },
+
+  fromJSON(_: any): VerifyLogsRequest {
+    return {};
+  },
+
+  toJSON(_: VerifyLogsRequest): unknown {
+    const obj: any = {};
+    return obj;
+  },
+
+  create(base?: DeepPartial<VerifyLogsRequest>): VerifyLogsRequest {
+    return VerifyLogsRequest.fromPartial(base ?? {});
+  },
+  fromPartial(_: DeepPartial<VerifyLogsRequest>): VerifyLogsRequest {
+    const message = createBaseVerifyLogsRequest();
+    return message;
+  },
+};
+
+function createBaseVerifyLogsResponse(): VerifyLogsResponse {
+  return { verified: false, recordCount: Long.ZERO, headHash: "", log: "", reason: "" };
+}
+
+export const VerifyLogsResponse = {
+  encode(message: VerifyLogsResponse, writer: _m0.Writer = _m0.Writer.create()): _m0.Writer {
+    if (message.verified === true) {
+      writer.uint32(8).bool(message.verified);
+    }
+    if (!message.recordCount.isZero()) {
+      writer.uint32(16).int64(message.recordCount);
+    }
+    if (message.headHash !== "") {
+      writer.uint32(26).string(message.headHash);
+    }
+    if (message.log !== "") {
+      writer.uint32(34).string(message.log);
+    }
+    if (message.reason !== "") {
+      writer.uint32(42).string(message.reason);
+    }
+    return writer;
+  },
+
+  decode(input: _m0.Reader | Uint8Array, length?: number): VerifyLogsResponse {
+    const reader = input instanceof _m0.Reader ? input : _m0.Reader.create(input);
+    let end = length === undefined ? reader.len : reader.pos + length;
+    const message = createBaseVerifyLogsResponse();
+    while (reader.pos < end) {
+      const tag = reader.uint32();
+      switch (tag >>> 3) {
+        case 1:
+          if (tag !== 8) {
+            break;
+          }
+
+          message.verified = reader.bool();
+          continue;
+        case 2:
+          if (tag !== 16) {
+            break;
+          }
+
+          message.recordCount = reader.int64() as Long;
+          continue;
+        case 3:
+          if (tag !== 26) {
+            break;
+          }
+
+          message.headHash = reader.string();
+          continue;
+        case 4:
+          if (tag !== 34) {
+            break;
+          }
+
+          message.log = reader.string();
+          continue;
+        case 5:
+          if (tag !== 42) {
+            break;
+          }
+
+          message.reason = reader.string();
+          continue;
+      }
+      if ((tag & 7) === 4 || tag === 0) {
+        break;
+      }
+      reader.skipType(tag & 7);
+    }
+    return message;
+  },
+
+  fromJSON(object: any): VerifyLogsResponse {
+    return {
+      verified: isSet(object.verified) ? globalThis.Boolean(object.verified) : false,
+      recordCount: isSet(object.recordCount) ? Long.fromValue(object.recordCount) : Long.ZERO,
+      headHash: isSet(object.headHash) ? globalThis.String(object.headHash) : "",
+      log: isSet(object.log) ? globalThis.String(object.log) : "",
+      reason: isSet(object.reason) ? globalThis.String(object.reason) : "",
+    };
+  },
+
+  toJSON(message: VerifyLogsResponse): unknown {
+    const obj: any = {};
+    if (message.verified === true) {
+      obj.verified = message.verified;
+    }
+    if (!message.recordCount.isZero()) {
+      obj.recordCount = (message.recordCount || Long.ZERO).toString();
+    }
+    if (message.headHash !== "") {
+      obj.headHash = message.headHash;
+    }
+    if (message.log !== "") {
+      obj.log = message.log;
+    }
+    if (message.reason !== "") {
+      obj.reason = message.reason;
+    }
+    return obj;
+  },
+
+  create(base?: DeepPartial<VerifyLogsResponse>): VerifyLogsResponse {
+    return VerifyLogsResponse.fromPartial(base ?? {});
+  },
+  fromPartial(object: DeepPartial<VerifyLogsResponse>): VerifyLogsResponse {
+    const message = createBaseVerifyLogsResponse();
+    message.verified = object.verified ?? false;
+    message.recordCount = (object.recordCount !== undefined && object.recordCount !== null)
+      ? Long.fromValue(object.recordCount)
+      : Long.ZERO;
+    message.headHash = object.headHash ?? "";
+    message.log = object.log ?? "";
+    message.reason = object.reason ?? "";
+    return message;
+  },
+};
+
 export type LoggingServiceDefinition = typeof LoggingServiceDefinition;
 export const LoggingServiceDefinition = {
   name: "LoggingService",
@@ -1031,6 +1266,42 @@ export const LoggingServiceDefinition = {
         },
       },
     },
+    verifyLogs: {
+      name: "VerifyLogs",
+      requestType: VerifyLogsRequest,
+      requestStream: false,
+      responseType: VerifyLogsResponse,
+      responseStream: false,
+      options: {
+        _unknownFields: {
+          578365826: [
+            new Uint8Array([
+              20,
+              58,
+              1,
+              42,
+              34,
+              15,
+              47,
+              118,
+              49,
+              47,
+              108,
+              111,
+              103,
+              115,
+              58,
+              118,
+              101,
+              114,
+              105,
+              102,
+              121,
+            ]),
+          ],
+        },
+      },
+    },
   },
 } as const;
 
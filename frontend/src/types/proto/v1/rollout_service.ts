@@ -404,6 +404,7 @@ export enum PlanCheckRun_Type {
   DATABASE_CONNECT = 6,
   DATABASE_GHOST_SYNC = 7,
   DATABASE_PITR_MYSQL = 8,
+  DATABASE_SHADOW_DRY_RUN = 9,
   UNRECOGNIZED = -1,
 }
 
@@ -436,6 +437,9 @@ export function planCheckRun_TypeFromJSON(object: any): PlanCheckRun_Type {
     case 8:
     case "DATABASE_PITR_MYSQL":
       return PlanCheckRun_Type.DATABASE_PITR_MYSQL;
+    case 9:
+    case "DATABASE_SHADOW_DRY_RUN":
+      return PlanCheckRun_Type.DATABASE_SHADOW_DRY_RUN;
     case -1:
     case "UNRECOGNIZED":
     default:
@@ -463,6 +467,8 @@ export function planCheckRun_TypeToJSON(object: PlanCheckRun_Type): string {
       return "DATABASE_GHOST_SYNC";
     case PlanCheckRun_Type.DATABASE_PITR_MYSQL:
       return "DATABASE_PITR_MYSQL";
+    case PlanCheckRun_Type.DATABASE_SHADOW_DRY_RUN:
+      return "DATABASE_SHADOW_DRY_RUN";
     case PlanCheckRun_Type.UNRECOGNIZED:
     default:
       return "UNRECOGNIZED";
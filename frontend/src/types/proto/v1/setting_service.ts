@@ -226,6 +226,8 @@ export interface AppIMSetting {
   appId: string;
   appSecret: string;
   externalApproval: AppIMSetting_ExternalApproval | undefined;
+  slack: AppIMSetting_Slack | undefined;
+  teams: AppIMSetting_Teams | undefined;
 }
 
 export enum AppIMSetting_IMType {
@@ -266,6 +268,25 @@ export interface AppIMSetting_ExternalApproval {
   approvalDefinitionId: string;
 }
 
+export interface AppIMSetting_Slack {
+  /** Enabled controls whether the approval requests are posted to the Slack webhooks with Approve/Reject buttons. */
+  enabled: boolean;
+  /** The signing secret of the Slack app, used to verify the interaction requests from Slack. */
+  signingSecret: string;
+  /**
+   * The bot token of the Slack app, used to look up the emails of the Slack users.
+   * The bot requires the users:read and users:read.email scopes.
+   */
+  botToken: string;
+}
+
+export interface AppIMSetting_Teams {
+  /** Enabled controls whether the approval requests are posted to the Teams webhooks with Approve/Reject buttons. */
+  enabled: boolean;
+  /** The token to sign the actions in the Teams message cards, the actions with invalid signatures are rejected. */
+  token: string;
+}
+
 export interface AgentPluginSetting {
   /** The URL for the agent API. */
   url: string;
@@ -376,6 +397,55 @@ export interface ExternalApprovalSetting_Node {
   title: string;
   /** The external endpoint for the relay service, e.g. "http://hello:1234". */
   endpoint: string;
+  /** The type of the external approval provider. */
+  type: ExternalApprovalSetting_Node_Type;
+  /** The secret to sign the requests sent to the provider and verify the callbacks from the provider with HMAC-SHA256. */
+  signingSecret: string;
+}
+
+export enum ExternalApprovalSetting_Node_Type {
+  /** The unspecified type is treated as RELAY for the nodes created before the types are introduced. */
+  TYPE_UNSPECIFIED = 0,
+  /** The relay service which is polled for the approval status. */
+  RELAY = 1,
+  /**
+   * The generic HTTP provider for the ticketing systems, e.g. ServiceNow and Jira.
+   * A change request is created in the ticketing system, and the status is received via the signed callback or polling.
+   */
+  HTTP = 2,
+  UNRECOGNIZED = -1,
+}
+
+export function externalApprovalSetting_Node_TypeFromJSON(object: any): ExternalApprovalSetting_Node_Type {
+  switch (object) {
+    case 0:
+    case "TYPE_UNSPECIFIED":
+      return ExternalApprovalSetting_Node_Type.TYPE_UNSPECIFIED;
+    case 1:
+    case "RELAY":
+      return ExternalApprovalSetting_Node_Type.RELAY;
+    case 2:
+    case "HTTP":
+      return ExternalApprovalSetting_Node_Type.HTTP;
+    case -1:
+    case "UNRECOGNIZED":
+    default:
+      return ExternalApprovalSetting_Node_Type.UNRECOGNIZED;
+  }
+}
+
+export function externalApprovalSetting_Node_TypeToJSON(object: ExternalApprovalSetting_Node_Type): string {
+  switch (object) {
+    case ExternalApprovalSetting_Node_Type.TYPE_UNSPECIFIED:
+      return "TYPE_UNSPECIFIED";
+    case ExternalApprovalSetting_Node_Type.RELAY:
+      return "RELAY";
+    case ExternalApprovalSetting_Node_Type.HTTP:
+      return "HTTP";
+    case ExternalApprovalSetting_Node_Type.UNRECOGNIZED:
+    default:
+      return "UNRECOGNIZED";
+  }
 }
 
 export interface SchemaTemplateSetting {
@@ -1452,7 +1522,7 @@ export const SMTPMailDeliverySettingValue = {
 };
 
 function createBaseAppIMSetting(): AppIMSetting {
-  return { imType: 0, appId: "", appSecret: "", externalApproval: undefined };
+  return { imType: 0, appId: "", appSecret: "", externalApproval: undefined, slack: undefined, teams: undefined };
 }
 
 export const AppIMSetting = {
@@ -1469,6 +1539,12 @@ export const AppIMSetting = {
     if (message.externalApproval !== undefined) {
       AppIMSetting_ExternalApproval.encode(message.externalApproval, writer.uint32(34).fork()).ldelim();
     }
+    if (message.slack !== undefined) {
+      AppIMSetting_Slack.encode(message.slack, writer.uint32(42).fork()).ldelim();
+    }
+    if (message.teams !== undefined) {
+      AppIMSetting_Teams.encode(message.teams, writer.uint32(50).fork()).ldelim();
+    }
     return writer;
   },
 
@@ -1507,6 +1583,20 @@ export const AppIMSetting = {
 
           message.externalApproval = AppIMSetting_ExternalApproval.decode(reader, reader.uint32());
           continue;
+        case 5:
+          if (tag !== 42) {
+            break;
+          }
+
+          message.slack = AppIMSetting_Slack.decode(reader, reader.uint32());
+          continue;
+        case 6:
+          if (tag !== 50) {
+            break;
+          }
+
+          message.teams = AppIMSetting_Teams.decode(reader, reader.uint32());
+          continue;
       }
       if ((tag & 7) === 4 || tag === 0) {
         break;
@@ -1524,6 +1614,8 @@ export const AppIMSetting = {
       externalApproval: isSet(object.externalApproval)
         ? AppIMSetting_ExternalApproval.fromJSON(object.externalApproval)
         : undefined,
+      slack: isSet(object.slack) ? AppIMSetting_Slack.fromJSON(object.slack) : undefined,
+      teams: isSet(object.teams) ? AppIMSetting_Teams.fromJSON(object.teams) : undefined,
     };
   },
 
@@ -1541,6 +1633,12 @@ export const AppIMSetting = {
     if (message.externalApproval !== undefined) {
       obj.externalApproval = AppIMSetting_ExternalApproval.toJSON(message.externalApproval);
     }
+    if (message.slack !== undefined) {
+      obj.slack = AppIMSetting_Slack.toJSON(message.slack);
+    }
+    if (message.teams !== undefined) {
+      obj.teams = AppIMSetting_Teams.toJSON(message.teams);
+    }
     return obj;
   },
 
@@ -1555,6 +1653,12 @@ export const AppIMSetting = {
     message.externalApproval = (object.externalApproval !== undefined && object.externalApproval !== null)
       ? AppIMSetting_ExternalApproval.fromPartial(object.externalApproval)
       : undefined;
+    message.slack = (object.slack !== undefined && object.slack !== null)
+      ? AppIMSetting_Slack.fromPartial(object.slack)
+      : undefined;
+    message.teams = (object.teams !== undefined && object.teams !== null)
+      ? AppIMSetting_Teams.fromPartial(object.teams)
+      : undefined;
     return message;
   },
 };
@@ -1633,6 +1737,169 @@ export const AppIMSetting_ExternalApproval = {
   },
 };
 
+function createBaseAppIMSetting_Slack(): AppIMSetting_Slack {
+  return { enabled: false, signingSecret: "", botToken: "" };
+}
+
+export const AppIMSetting_Slack = {
+  encode(message: AppIMSetting_Slack, writer: _m0.Writer = _m0.Writer.create()): _m0.Writer {
+    if (message.enabled === true) {
+      writer.uint32(8).bool(message.enabled);
+    }
+    if (message.signingSecret !== "") {
+      writer.uint32(18).string(message.signingSecret);
+    }
+    if (message.botToken !== "") {
+      writer.uint32(26).string(message.botToken);
+    }
+    return writer;
+  },
+
+  decode(input: _m0.Reader | Uint8Array, length?: number): AppIMSetting_Slack {
+    const reader = input instanceof _m0.Reader ? input : _m0.Reader.create(input);
+    let end = length === undefined ? reader.len : reader.pos + length;
+    const message = createBaseAppIMSetting_Slack();
+    while (reader.pos < end) {
+      const tag = reader.uint32();
+      switch (tag >>> 3) {
+        case 1:
+          if (tag !== 8) {
+            break;
+          }
+
+          message.enabled = reader.bool();
+          continue;
+        case 2:
+          if (tag !== 18) {
+            break;
+          }
+
+          message.signingSecret = reader.string();
+          continue;
+        case 3:
+          if (tag !== 26) {
+            break;
+          }
+
+          message.botToken = reader.string();
+          continue;
+      }
+      if ((tag & 7) === 4 || tag === 0) {
+        break;
+      }
+      reader.skipType(tag & 7);
+    }
+    return message;
+  },
+
+  fromJSON(object: any): AppIMSetting_Slack {
+    return {
+      enabled: isSet(object.enabled) ? globalThis.Boolean(object.enabled) : false,
+      signingSecret: isSet(object.signingSecret) ? globalThis.String(object.signingSecret) : "",
+      botToken: isSet(object.botToken) ? globalThis.String(object.botToken) : "",
+    };
+  },
+
+  toJSON(message: AppIMSetting_Slack): unknown {
+    const obj: any = {};
+    if (message.enabled === true) {
+      obj.enabled = message.enabled;
+    }
+    if (message.signingSecret !== "") {
+      obj.signingSecret = message.signingSecret;
+    }
+    if (message.botToken !== "") {
+      obj.botToken = message.botToken;
+    }
+    return obj;
+  },
+
+  create(base?: DeepPartial<AppIMSetting_Slack>): AppIMSetting_Slack {
+    return AppIMSetting_Slack.fromPartial(base ?? {});
+  },
+  fromPartial(object: DeepPartial<AppIMSetting_Slack>): AppIMSetting_Slack {
+    const message = createBaseAppIMSetting_Slack();
+    message.enabled = object.enabled ?? false;
+    message.signingSecret = object.signingSecret ?? "";
+    message.botToken = object.botToken ?? "";
+    return message;
+  },
+};
+
+function createBaseAppIMSetting_Teams(): AppIMSetting_Teams {
+  return { enabled: false, token: "" };
+}
+
+export const AppIMSetting_Teams = {
+  encode(message: AppIMSetting_Teams, writer: _m0.Writer = _m0.Writer.create()): _m0.Writer {
+    if (message.enabled === true) {
+      writer.uint32(8).bool(message.enabled);
+    }
+    if (message.token !== "") {
+      writer.uint32(18).string(message.token);
+    }
+    return writer;
+  },
+
+  decode(input: _m0.Reader | Uint8Array, length?: number): AppIMSetting_Teams {
+    const reader = input instanceof _m0.Reader ? input : _m0.Reader.create(input);
+    let end = length === undefined ? reader.len : reader.pos + length;
+    const message = createBaseAppIMSetting_Teams();
+    while (reader.pos < end) {
+      const tag = reader.uint32();
+      switch (tag >>> 3) {
+        case 1:
+          if (tag !== 8) {
+            break;
+          }
+
+          message.enabled = reader.bool();
+          continue;
+        case 2:
+          if (tag !== 18) {
+            break;
+          }
+
+          message.token = reader.string();
+          continue;
+      }
+      if ((tag & 7) === 4 || tag === 0) {
+        break;
+      }
+      reader.skipType(tag & 7);
+    }
+    return message;
+  },
+
+  fromJSON(object: any): AppIMSetting_Teams {
+    return {
+      enabled: isSet(object.enabled) ? globalThis.Boolean(object.enabled) : false,
+      token: isSet(object.token) ? globalThis.String(object.token) : "",
+    };
+  },
+
+  toJSON(message: AppIMSetting_Teams): unknown {
+    const obj: any = {};
+    if (message.enabled === true) {
+      obj.enabled = message.enabled;
+    }
+    if (message.token !== "") {
+      obj.token = message.token;
+    }
+    return obj;
+  },
+
+  create(base?: DeepPartial<AppIMSetting_Teams>): AppIMSetting_Teams {
+    return AppIMSetting_Teams.fromPartial(base ?? {});
+  },
+  fromPartial(object: DeepPartial<AppIMSetting_Teams>): AppIMSetting_Teams {
+    const message = createBaseAppIMSetting_Teams();
+    message.enabled = object.enabled ?? false;
+    message.token = object.token ?? "";
+    return message;
+  },
+};
+
 function createBaseAgentPluginSetting(): AgentPluginSetting {
   return { url: "", token: "" };
 }
@@ -2160,7 +2427,7 @@ export const ExternalApprovalSetting = {
 };
 
 function createBaseExternalApprovalSetting_Node(): ExternalApprovalSetting_Node {
-  return { id: "", title: "", endpoint: "" };
+  return { id: "", title: "", endpoint: "", type: 0, signingSecret: "" };
 }
 
 export const ExternalApprovalSetting_Node = {
@@ -2174,6 +2441,12 @@ export const ExternalApprovalSetting_Node = {
     if (message.endpoint !== "") {
       writer.uint32(26).string(message.endpoint);
     }
+    if (message.type !== 0) {
+      writer.uint32(32).int32(message.type);
+    }
+    if (message.signingSecret !== "") {
+      writer.uint32(42).string(message.signingSecret);
+    }
     return writer;
   },
 
@@ -2205,6 +2478,20 @@ export const ExternalApprovalSetting_Node = {
 
           message.endpoint = reader.string();
           continue;
+        case 4:
+          if (tag !== 32) {
+            break;
+          }
+
+          message.type = reader.int32() as any;
+          continue;
+        case 5:
+          if (tag !== 42) {
+            break;
+          }
+
+          message.signingSecret = reader.string();
+          continue;
       }
       if ((tag & 7) === 4 || tag === 0) {
         break;
@@ -2219,6 +2506,8 @@ export const ExternalApprovalSetting_Node = {
       id: isSet(object.id) ? globalThis.String(object.id) : "",
       title: isSet(object.title) ? globalThis.String(object.title) : "",
       endpoint: isSet(object.endpoint) ? globalThis.String(object.endpoint) : "",
+      type: isSet(object.type) ? externalApprovalSetting_Node_TypeFromJSON(object.type) : 0,
+      signingSecret: isSet(object.signingSecret) ? globalThis.String(object.signingSecret) : "",
     };
   },
 
@@ -2233,6 +2522,12 @@ export const ExternalApprovalSetting_Node = {
     if (message.endpoint !== "") {
       obj.endpoint = message.endpoint;
     }
+    if (message.type !== 0) {
+      obj.type = externalApprovalSetting_Node_TypeToJSON(message.type);
+    }
+    if (message.signingSecret !== "") {
+      obj.signingSecret = message.signingSecret;
+    }
     return obj;
   },
 
@@ -2244,6 +2539,8 @@ export const ExternalApprovalSetting_Node = {
     message.id = object.id ?? "";
     message.title = object.title ?? "";
     message.endpoint = object.endpoint ?? "";
+    message.type = object.type ?? 0;
+    message.signingSecret = object.signingSecret ?? "";
     return message;
   },
 };
@@ -41,6 +41,7 @@ const (
 	DatabaseService_AdviseIndex_FullMethodName            = "/bytebase.v1.DatabaseService/AdviseIndex"
 	DatabaseService_ListChangeHistories_FullMethodName    = "/bytebase.v1.DatabaseService/ListChangeHistories"
 	DatabaseService_GetChangeHistory_FullMethodName       = "/bytebase.v1.DatabaseService/GetChangeHistory"
+	DatabaseService_ListDatabaseGrants_FullMethodName     = "/bytebase.v1.DatabaseService/ListDatabaseGrants"
 )
 
 // DatabaseServiceClient is the client API for DatabaseService service.
@@ -69,6 +70,8 @@ type DatabaseServiceClient interface {
 	AdviseIndex(ctx context.Context, in *AdviseIndexRequest, opts ...grpc.CallOption) (*AdviseIndexResponse, error)
 	ListChangeHistories(ctx context.Context, in *ListChangeHistoriesRequest, opts ...grpc.CallOption) (*ListChangeHistoriesResponse, error)
 	GetChangeHistory(ctx context.Context, in *GetChangeHistoryRequest, opts ...grpc.CallOption) (*ChangeHistory, error)
+	// ListDatabaseGrants lists the active query and export grants of the database.
+	ListDatabaseGrants(ctx context.Context, in *ListDatabaseGrantsRequest, opts ...grpc.CallOption) (*ListDatabaseGrantsResponse, error)
 }
 
 type databaseServiceClient struct {
@@ -268,6 +271,15 @@ func (c *databaseServiceClient) GetChangeHistory(ctx context.Context, in *GetCha
 	return out, nil
 }
 
+func (c *databaseServiceClient) ListDatabaseGrants(ctx context.Context, in *ListDatabaseGrantsRequest, opts ...grpc.CallOption) (*ListDatabaseGrantsResponse, error) {
+	out := new(ListDatabaseGrantsResponse)
+	err := c.cc.Invoke(ctx, DatabaseService_ListDatabaseGrants_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // DatabaseServiceServer is the server API for DatabaseService service.
 // All implementations must embed UnimplementedDatabaseServiceServer
 // for forward compatibility
@@ -294,6 +306,8 @@ type DatabaseServiceServer interface {
 	AdviseIndex(context.Context, *AdviseIndexRequest) (*AdviseIndexResponse, error)
 	ListChangeHistories(context.Context, *ListChangeHistoriesRequest) (*ListChangeHistoriesResponse, error)
 	GetChangeHistory(context.Context, *GetChangeHistoryRequest) (*ChangeHistory, error)
+	// ListDatabaseGrants lists the active query and export grants of the database.
+	ListDatabaseGrants(context.Context, *ListDatabaseGrantsRequest) (*ListDatabaseGrantsResponse, error)
 	mustEmbedUnimplementedDatabaseServiceServer()
 }
 
@@ -364,6 +378,9 @@ func (UnimplementedDatabaseServiceServer) ListChangeHistories(context.Context, *
 func (UnimplementedDatabaseServiceServer) GetChangeHistory(context.Context, *GetChangeHistoryRequest) (*ChangeHistory, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetChangeHistory not implemented")
 }
+func (UnimplementedDatabaseServiceServer) ListDatabaseGrants(context.Context, *ListDatabaseGrantsRequest) (*ListDatabaseGrantsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDatabaseGrants not implemented")
+}
 func (UnimplementedDatabaseServiceServer) mustEmbedUnimplementedDatabaseServiceServer() {}
 
 // UnsafeDatabaseServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -755,6 +772,24 @@ func _DatabaseService_GetChangeHistory_Handler(srv interface{}, ctx context.Cont
 	return interceptor(ctx, in, info, handler)
 }
 
+func _DatabaseService_ListDatabaseGrants_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDatabaseGrantsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DatabaseServiceServer).ListDatabaseGrants(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DatabaseService_ListDatabaseGrants_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DatabaseServiceServer).ListDatabaseGrants(ctx, req.(*ListDatabaseGrantsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // DatabaseService_ServiceDesc is the grpc.ServiceDesc for DatabaseService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -846,6 +881,10 @@ var DatabaseService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetChangeHistory",
 			Handler:    _DatabaseService_GetChangeHistory_Handler,
 		},
+		{
+			MethodName: "ListDatabaseGrants",
+			Handler:    _DatabaseService_ListDatabaseGrants_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "v1/database_service.proto",
@@ -22,6 +22,7 @@ const (
 	LoggingService_ListLogs_FullMethodName   = "/bytebase.v1.LoggingService/ListLogs"
 	LoggingService_GetLog_FullMethodName     = "/bytebase.v1.LoggingService/GetLog"
 	LoggingService_ExportLogs_FullMethodName = "/bytebase.v1.LoggingService/ExportLogs"
+	LoggingService_VerifyLogs_FullMethodName = "/bytebase.v1.LoggingService/VerifyLogs"
 )
 
 // LoggingServiceClient is the client API for LoggingService service.
@@ -31,6 +32,8 @@ type LoggingServiceClient interface {
 	ListLogs(ctx context.Context, in *ListLogsRequest, opts ...grpc.CallOption) (*ListLogsResponse, error)
 	GetLog(ctx context.Context, in *GetLogRequest, opts ...grpc.CallOption) (*LogEntity, error)
 	ExportLogs(ctx context.Context, in *ExportLogsRequest, opts ...grpc.CallOption) (*ExportLogsResponse, error)
+	// VerifyLogs verifies the hash chain of the logs, and reports the first log that is tampered with.
+	VerifyLogs(ctx context.Context, in *VerifyLogsRequest, opts ...grpc.CallOption) (*VerifyLogsResponse, error)
 }
 
 type loggingServiceClient struct {
@@ -68,6 +71,15 @@ func (c *loggingServiceClient) ExportLogs(ctx context.Context, in *ExportLogsReq
 	return out, nil
 }
 
+func (c *loggingServiceClient) VerifyLogs(ctx context.Context, in *VerifyLogsRequest, opts ...grpc.CallOption) (*VerifyLogsResponse, error) {
+	out := new(VerifyLogsResponse)
+	err := c.cc.Invoke(ctx, LoggingService_VerifyLogs_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // LoggingServiceServer is the server API for LoggingService service.
 // All implementations must embed UnimplementedLoggingServiceServer
 // for forward compatibility
@@ -75,6 +87,8 @@ type LoggingServiceServer interface {
 	ListLogs(context.Context, *ListLogsRequest) (*ListLogsResponse, error)
 	GetLog(context.Context, *GetLogRequest) (*LogEntity, error)
 	ExportLogs(context.Context, *ExportLogsRequest) (*ExportLogsResponse, error)
+	// VerifyLogs verifies the hash chain of the logs, and reports the first log that is tampered with.
+	VerifyLogs(context.Context, *VerifyLogsRequest) (*VerifyLogsResponse, error)
 	mustEmbedUnimplementedLoggingServiceServer()
 }
 
@@ -91,6 +105,9 @@ func (UnimplementedLoggingServiceServer) GetLog(context.Context, *GetLogRequest)
 func (UnimplementedLoggingServiceServer) ExportLogs(context.Context, *ExportLogsRequest) (*ExportLogsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ExportLogs not implemented")
 }
+func (UnimplementedLoggingServiceServer) VerifyLogs(context.Context, *VerifyLogsRequest) (*VerifyLogsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VerifyLogs not implemented")
+}
 func (UnimplementedLoggingServiceServer) mustEmbedUnimplementedLoggingServiceServer() {}
 
 // UnsafeLoggingServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -158,6 +175,24 @@ func _LoggingService_ExportLogs_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _LoggingService_VerifyLogs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyLogsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoggingServiceServer).VerifyLogs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LoggingService_VerifyLogs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoggingServiceServer).VerifyLogs(ctx, req.(*VerifyLogsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // LoggingService_ServiceDesc is the grpc.ServiceDesc for LoggingService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -177,6 +212,10 @@ var LoggingService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ExportLogs",
 			Handler:    _LoggingService_ExportLogs_Handler,
 		},
+		{
+			MethodName: "VerifyLogs",
+			Handler:    _LoggingService_VerifyLogs_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "v1/logging_service.proto",